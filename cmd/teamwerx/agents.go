@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+)
+
+var (
+	agentsTargets []string
+	agentsCheck   bool
+	agentsRoot    string
+)
+
+var agentsCmd = &cobra.Command{
+	Use:   "agents",
+	Short: "Keep AI agent instruction files in sync with the charter",
+}
+
+var agentsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write CLAUDE.md, .cursorrules and AGENTS.md from the charter",
+	Long: `Generate tool-specific agent instruction files from the charter's
+"## AI Agent Instructions" section, its purpose, tech stack and conventions,
+and the workspace's file layout:
+
+  claude  CLAUDE.md
+  cursor  .cursorrules
+  agents  AGENTS.md
+
+Files are written to the workspace root. Generated files carry a marker
+comment; a hand-written file of the same name is never overwritten. --check
+writes nothing and exits non-zero when a file is missing or out of date, so
+CI can keep the charter the single source of agent guidance.`,
+	Args: cobra.NoArgs,
+	RunE: runAgentsExport,
+}
+
+func init() {
+	rootCmd.AddCommand(agentsCmd)
+	agentsCmd.AddCommand(agentsExportCmd)
+	agentsExportCmd.Flags().StringSliceVar(&agentsTargets, "target", nil, "Targets to export: "+strings.Join(core.AgentTargetNames(), ", ")+" (default: all)")
+	agentsExportCmd.Flags().BoolVar(&agentsCheck, "check", false, "Report missing or stale files without writing them")
+	agentsExportCmd.Flags().StringVar(&agentsRoot, "root", "", "Directory to write the files to (default: the workspace root)")
+	agentsExportCmd.Flags().StringVar(&charterBaseDir, "charter-dir", ".teamwerx", "Base directory for charter")
+}
+
+func runAgentsExport(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{CharterDir: charterBaseDir})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+	root := agentsRoot
+	if root == "" {
+		if root = core.FindWorkspaceRoot("."); root == "" {
+			root = "."
+		}
+	}
+	results, err := app.ExportAgentInstructions(core.AgentExportOptions{Root: root, Targets: agentsTargets, Check: agentsCheck})
+	if err != nil {
+		return fmt.Errorf("failed to export agent instructions: %w", err)
+	}
+
+	outdated := 0
+	for _, r := range results {
+		porcelain("agents", r.Target, r.File, r.Status)
+		switch r.Status {
+		case core.AgentFileStale, core.AgentFileMissing:
+			outdated++
+			style.Warn.Printf("%-10s", r.Status)
+		case core.AgentFileWritten:
+			style.Success.Printf("%-10s", r.Status)
+		default:
+			fmt.Printf("%-10s", r.Status)
+		}
+		fmt.Printf(" %s (%s)\n", r.File, r.Target)
+	}
+	if outdated > 0 {
+		return fmt.Errorf("%d agent instruction file(s) out of date; run 'teamwerx agents export'", outdated)
+	}
+	return nil
+}
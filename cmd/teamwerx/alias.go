@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+var (
+	removeAlias bool
+
+	specReqAliasCmd = &cobra.Command{
+		Use:   "alias <domain> <old-id> [new-id]",
+		Short: "Keep a renamed requirement's old ID working as an alias",
+		Long: `Record <old-id> in the spec's front matter as an alias of the requirement
+<new-id>. Changes, references, task links and command arguments that still
+use the old ID are resolved to the new one, with a deprecation warning.
+--remove drops the alias.`,
+		Args: cobra.RangeArgs(2, 3),
+		RunE: runSpecReqAlias,
+	}
+)
+
+func init() {
+	specReqCmd.AddCommand(specReqAliasCmd)
+	specReqAliasCmd.Flags().BoolVar(&removeAlias, "remove", false, "Remove the alias instead of adding it")
+}
+
+func runSpecReqAlias(cmd *cobra.Command, args []string) error {
+	if removeAlias == (len(args) == 3) {
+		return fmt.Errorf("give either a new requirement ID or --remove")
+	}
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	domain, oldID, newID := strings.TrimSpace(args[0]), strings.TrimSpace(args[1]), ""
+	if len(args) == 3 {
+		newID = strings.TrimSpace(args[2])
+	}
+	spec, err := app.SpecManager.ReadSpec(domain)
+	if err != nil {
+		return fmt.Errorf("failed to read spec: %w", err)
+	}
+	content, err := core.SetRequirementAlias(spec, oldID, newID)
+	if err != nil {
+		return err
+	}
+	if spec, err = core.NewSpecParserWithFormat(app.Options.SpecFormat).Parse([]byte(content)); err != nil {
+		return err
+	}
+	spec.Domain = domain
+	if err := app.SpecManager.WriteSpec(spec); err != nil {
+		return fmt.Errorf("failed to write spec: %w", err)
+	}
+
+	porcelainSpecMeta(spec)
+	if newID == "" {
+		printLine(style.Success, "Removed alias %s from spec %s.", oldID, domain)
+		return nil
+	}
+	printLine(style.Success, "%s/%s is now an alias of %s/%s.", domain, oldID, domain, spec.Meta.Aliases[strings.ToLower(oldID)])
+	return nil
+}
+
+// requirementArg returns the current ID of the requirement a command-line
+// argument names in domain, warning when it is a deprecated alias. The
+// argument is returned as is when the domain has no spec, for the command
+// itself to report.
+func requirementArg(app *core.App, domain, id string) string {
+	spec, err := app.SpecManager.ReadSpec(strings.TrimSpace(domain))
+	if err != nil {
+		return strings.TrimSpace(id)
+	}
+	return resolveRequirementArg(spec, id)
+}
+
+// resolveRequirementArg is requirementArg for a spec already read.
+func resolveRequirementArg(spec *model.Spec, id string) string {
+	current, deprecated := core.ResolveRequirementAlias(spec, id)
+	if deprecated {
+		warnDeprecated(core.DeprecatedRequirementID{Domain: spec.Domain, Alias: strings.TrimSpace(id), ID: current})
+	}
+	return current
+}
+
+// warnDeprecatedChangeIDs warns about every operation of ch that still
+// names a renamed requirement by its old ID.
+func warnDeprecatedChangeIDs(app *core.App, ch *model.Change) error {
+	uses, err := core.DeprecatedChangeIDs(app.SpecManager, ch)
+	if err != nil {
+		return err
+	}
+	for _, d := range uses {
+		warnDeprecated(d)
+	}
+	return nil
+}
+
+func warnDeprecated(d core.DeprecatedRequirementID) {
+	porcelain("deprecated", d.Domain, d.Alias, d.ID, d.Where)
+	if d.Where != "" {
+		printLine(style.Warn, "Warning: %s (%s)", d, d.Where)
+		return
+	}
+	printLine(style.Warn, "Warning: %s", d)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+)
+
+var analyticsOutput string
+
+var (
+	analyticsCmd = &cobra.Command{
+		Use:   "analytics",
+		Short: "Local, opt-in usage analytics",
+		Long: `Usage analytics are disabled by default. Enable them in .teamwerx/config.yaml:
+
+  analytics:
+    enabled: true
+
+Each command then appends its name, duration and error category (never arguments
+or error messages) to .teamwerx/analytics.jsonl. Nothing is sent over the network.`,
+	}
+
+	analyticsReportCmd = &cobra.Command{
+		Use:   "report",
+		Short: "Summarize command frequency and error categories",
+		Args:  cobra.NoArgs,
+		RunE:  runAnalyticsReport,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(analyticsCmd)
+	analyticsCmd.AddCommand(analyticsReportCmd)
+	analyticsCmd.PersistentFlags().StringVar(&charterBaseDir, "charter-dir", ".teamwerx", "Base directory for charter and config")
+	analyticsReportCmd.Flags().StringVarP(&analyticsOutput, "output", "o", "text", "Output format: text or json")
+}
+
+// recordCommand appends an analytics event for a finished command. Failures to
+// record are ignored so analytics never break the command being measured.
+func recordCommand(command string, started time.Time, err error) {
+	if readOnlyMode() {
+		return
+	}
+	charterDir := core.AppOptions{CharterDir: charterBaseDir}.WithDefaults().CharterDir
+	_ = core.RecordAnalytics(charterDir, core.AnalyticsEvent{
+		Time:          started.UTC(),
+		Command:       command,
+		ErrorCategory: core.ClassifyError(err),
+		DurationMS:    time.Since(started).Milliseconds(),
+	})
+}
+
+func runAnalyticsReport(cmd *cobra.Command, args []string) error {
+	charterDir := core.AppOptions{CharterDir: charterBaseDir}.WithDefaults().CharterDir
+	cfg, err := core.LoadConfig(core.ConfigPath(charterDir))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	events, err := core.LoadAnalytics(cfg.Analytics.Path(charterDir))
+	if err != nil {
+		return fmt.Errorf("failed to load analytics: %w", err)
+	}
+	report := core.SummarizeAnalytics(events)
+
+	switch analyticsOutput {
+	case "json":
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	case "text", "":
+	default:
+		return fmt.Errorf("unsupported output %q (expected text or json)", analyticsOutput)
+	}
+
+	if !cfg.Analytics.Enabled {
+		printLine(style.Warn, "Analytics are disabled; set analytics.enabled: true in %s to start recording.", core.ConfigPath(charterDir))
+	}
+	if report.Events == 0 {
+		printLine(style.Warn, "No analytics recorded.")
+		return nil
+	}
+
+	for _, c := range report.Commands {
+		porcelain("command", c.Command, strconv.Itoa(c.Count), formatCategoryCounts(c.Errors))
+	}
+	hdr := style.Header
+	hdr.Printf("%d command(s) from %s to %s\n", report.Events, report.From.Format("2006-01-02"), report.To.Format("2006-01-02"))
+	for _, c := range report.Commands {
+		fmt.Printf("  %-28s %5d", c.Command, c.Count)
+		if len(c.Errors) > 0 {
+			fmt.Printf("  errors: %s", formatCategoryCounts(c.Errors))
+		}
+		fmt.Println()
+	}
+	if len(report.Errors) > 0 {
+		fmt.Printf("Errors by category: %s\n", formatCategoryCounts(report.Errors))
+	}
+	return nil
+}
+
+// formatCategoryCounts renders {"conflict": 2, "usage": 1} as "conflict=2, usage=1".
+func formatCategoryCounts(counts map[string]int) string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%d", k, counts[k]))
+	}
+	return strings.Join(parts, ", ")
+}
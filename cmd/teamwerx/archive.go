@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+)
+
+var (
+	compactKeep     int
+	compactKeepDays int
+	compactDryRun   bool
+	compactList     bool
+)
+
+var changeCompactArchiveCmd = &cobra.Command{
+	Use:   "compact-archive",
+	Short: "Compress old archived changes into a single tarball with a manifest",
+	Long: `Move archived changes outside the retention policy into
+<archive>/.compacted: their files into changes.tar.gz and their change records
+into manifest.json. A change is kept as is while it is one of the --keep most
+recently archived, or was archived less than --keep-days days ago (by the
+change file's modification time). Set the defaults in .teamwerx/config.yaml:
+
+  archive:
+    keep_recent: 50
+    keep_days: 90
+
+Compacted changes still count for stats, milestones and new change IDs, and
+can be cloned. --list prints the manifest.`,
+	Args: cobra.NoArgs,
+	RunE: runChangeCompactArchive,
+}
+
+func init() {
+	changeCmd.AddCommand(changeCompactArchiveCmd)
+	f := changeCompactArchiveCmd.Flags()
+	f.IntVar(&compactKeep, "keep", -1, "Keep this many most recently archived changes (default archive.keep_recent)")
+	f.IntVar(&compactKeepDays, "keep-days", -1, "Keep changes archived within this many days (default archive.keep_days)")
+	f.BoolVar(&compactDryRun, "dry-run", false, "List what would be compacted without writing")
+	f.BoolVar(&compactList, "list", false, "List the compacted changes instead")
+}
+
+func runChangeCompactArchive(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+		CharterDir: charterBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	if compactList {
+		compacted, err := app.CompactedChanges()
+		if err != nil {
+			return fmt.Errorf("failed to read the compacted archive: %w", err)
+		}
+		if len(compacted) == 0 {
+			printLine(style.Muted, "No compacted changes.")
+			return nil
+		}
+		for _, c := range compacted {
+			printCompactedChange(c)
+		}
+		return nil
+	}
+
+	cfg, err := core.LoadConfig(core.ConfigPath(app.Options.CharterDir))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	policy := cfg.Archive
+	if cmd.Flags().Changed("keep") {
+		policy.KeepRecent = compactKeep
+	}
+	if cmd.Flags().Changed("keep-days") {
+		policy.KeepDays = compactKeepDays
+	}
+	compacted, err := app.CompactArchive(policy, core.CompactOptions{DryRun: compactDryRun})
+	if err != nil {
+		return err
+	}
+	if len(compacted) == 0 {
+		printLine(style.Success, "No archived changes outside the retention policy.")
+		return nil
+	}
+	for _, c := range compacted {
+		printCompactedChange(c)
+	}
+	if compactDryRun {
+		printLine(style.Warn, "Dry run: %d archived change(s) not compacted.", len(compacted))
+		return nil
+	}
+	style.Success.Printf("Compacted %d archived change(s) into %s\n", len(compacted), app.Options.Layout.CompactedBundlePath(app.Options.ChangesDir))
+	return nil
+}
+
+// printCompactedChange prints one change of the compacted archive.
+func printCompactedChange(c core.CompactedChange) {
+	ch := c.Change
+	porcelain("compacted", ch.ID, ch.Status, porcelainTime(c.ArchivedAt), porcelainTime(c.CompactedAt), ch.Title)
+	fmt.Printf("  %-12s %-10s %s  %s\n", ch.ID, ch.Status, displayTime(c.ArchivedAt, time.RFC3339), ch.Title)
+}
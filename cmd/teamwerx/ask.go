@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+)
+
+var askCmd = &cobra.Command{
+	Use:   "ask <tasks|requirements|changes> [key=value ...]",
+	Short: "Answer a structured question about the workspace as JSON",
+	Long: `Answer a question about the workspace in one read-only call and print the
+matching records as JSON. Every filter must match; a value may list
+alternatives separated by commas.
+
+  tasks         goal, status (a status, open or closed), assignee,
+                requirement (domain/id), domain, label, field (key=value)
+  requirements  domain, tag (a tag of the domain), priority (or none),
+                tasks (none, any, open or done)
+  changes       status (a status, or pending), goal, domain, author,
+                milestone, awaiting (approval, or a domain), older, newer
+                (an age like 7d or 36h, or a date)
+
+Examples:
+
+  teamwerx ask tasks goal=001-auth status=open
+  teamwerx ask requirements tag=security tasks=none
+  teamwerx ask changes awaiting=approval older=7d`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runAsk,
+}
+
+func init() {
+	rootCmd.AddCommand(askCmd)
+	askCmd.Flags().StringVar(&specsBaseDir, "specs-dir", ".teamwerx/specs", "Base directory containing spec domains")
+	askCmd.Flags().StringVar(&goalsBaseDir, "goals-dir", ".teamwerx/goals", "Base directory containing goals")
+	askCmd.Flags().StringVar(&changesBaseDir, "changes-dir", ".teamwerx/changes", "Base directory containing changes")
+}
+
+func runAsk(cmd *cobra.Command, args []string) error {
+	q, err := core.ParseQuery(args)
+	if err != nil {
+		return err
+	}
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+		CharterDir: charterBaseDir,
+		ReadOnly:   true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+	answer, err := app.Ask(q, time.Now())
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(answer, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, string(data))
+	return nil
+}
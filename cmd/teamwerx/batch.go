@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+	fileutil "github.com/teamwerx/teamwerx/internal/utils/file"
+)
+
+var (
+	batchFile   string
+	batchResume bool
+	batchRate   float64
+	batchDryRun bool
+)
+
+var batchCmd = &cobra.Command{
+	Use:   "batch",
+	Short: "Apply many plan and discussion mutations from a JSON lines file",
+	Long: `Apply one operation per line of --file in a single run. Every operation is
+validated against the workspace first; if any fails, nothing is written.
+Each goal's plan and discussion log is then written once, however many
+operations touch it.
+
+  {"op":"task.add","goal":"001-auth","title":"Write tests"}
+  {"op":"task.add","goal":"001-auth","title":"Cover expiry","parent":"T01"}
+  {"op":"task.complete","goal":"001-auth","task":"T02","force":false}
+  {"op":"task.waive","goal":"001-auth","task":"T03","reason":"Out of scope"}
+  {"op":"task.block","goal":"001-auth","task":"T04","reason":"Waiting on infra","author":"agent"}
+  {"op":"task.unblock","goal":"001-auth","task":"T04"}
+  {"op":"discuss.add","goal":"001-auth","type":"decision","content":"Use JWT","author":"agent"}
+
+Progress is checkpointed to <file>.done after each file is written. If a run
+is interrupted, --resume skips the operations already applied.`,
+	Args: cobra.NoArgs,
+	RunE: runBatch,
+}
+
+func init() {
+	rootCmd.AddCommand(batchCmd)
+	batchCmd.Flags().StringVar(&batchFile, "file", "", "JSON lines file of operations ('-' for stdin)")
+	batchCmd.Flags().BoolVar(&batchResume, "resume", false, "Skip operations recorded in <file>.done by an interrupted run")
+	batchCmd.Flags().Float64Var(&batchRate, "rate", 0, "Maximum operations written per second (0 for no limit)")
+	batchCmd.Flags().BoolVar(&batchDryRun, "dry-run", false, "Validate the operations without writing")
+	batchCmd.Flags().StringVar(&goalsBaseDir, "goals-dir", ".teamwerx/goals", "Base directory containing goals")
+	_ = batchCmd.MarkFlagRequired("file")
+}
+
+// readBatchCheckpoint returns the line numbers recorded in a checkpoint file.
+func readBatchCheckpoint(path string) (map[int]bool, error) {
+	done := map[int]bool{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range strings.Fields(string(data)) {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, fmt.Errorf("malformed checkpoint %s: %q", path, f)
+		}
+		done[n] = true
+	}
+	return done, nil
+}
+
+func writeBatchCheckpoint(path string, done map[int]bool) error {
+	lines := make([]int, 0, len(done))
+	for n := range done {
+		lines = append(lines, n)
+	}
+	sort.Ints(lines)
+	var b strings.Builder
+	for _, n := range lines {
+		fmt.Fprintf(&b, "%d\n", n)
+	}
+	return fileutil.SafeWriteAtomic(path, []byte(b.String()), 0o644)
+}
+
+func runBatch(cmd *cobra.Command, args []string) error {
+	if batchRate < 0 {
+		return fmt.Errorf("--rate must not be negative")
+	}
+	in := os.Stdin
+	if batchFile != "-" {
+		f, err := os.Open(batchFile)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", batchFile, err)
+		}
+		defer f.Close()
+		in = f
+	} else if batchResume {
+		return fmt.Errorf("--resume needs a --file to checkpoint against")
+	}
+	ops, err := core.ParseBatch(in)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", batchFile, err)
+	}
+
+	app, err := newApp(core.AppOptions{GoalsDir: goalsBaseDir})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	checkpoint := batchFile + ".done"
+	done := map[int]bool{}
+	if batchResume {
+		if done, err = readBatchCheckpoint(checkpoint); err != nil {
+			return err
+		}
+		if len(done) > 0 {
+			printLine(style.Info, "Resuming: skipping %d operation(s) recorded in %s", len(done), checkpoint)
+		}
+	}
+	opts := core.BatchOptions{Skip: done, Rate: batchRate, DryRun: batchDryRun}
+	if batchFile != "-" && !batchDryRun {
+		opts.Applied = func(ops []core.BatchOp) error {
+			for _, op := range ops {
+				done[op.Line] = true
+			}
+			return writeBatchCheckpoint(checkpoint, done)
+		}
+	}
+
+	results, err := app.RunBatch(ops, opts)
+	for _, r := range results {
+		porcelain("batch", strconv.Itoa(r.Op.Line), r.Op.Op, r.Op.Goal, r.Target)
+	}
+	if err != nil {
+		if len(results) > 0 && batchFile != "-" {
+			return fmt.Errorf("%w (%d operation(s) applied; rerun with --resume to continue)", err, len(results))
+		}
+		return err
+	}
+	if batchDryRun {
+		printLine(style.Success, "%d operation(s) valid; nothing written (--dry-run).", len(results))
+		return nil
+	}
+	if batchFile != "-" {
+		if err := os.Remove(checkpoint); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove checkpoint %s: %w", checkpoint, err)
+		}
+	}
+	printLine(style.Success, "Applied %d operation(s).", len(results))
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+var (
+	benchSize    = core.DefaultSyntheticWorkspace
+	benchMinTime time.Duration
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Measure parse, merge and list performance on a synthetic workspace",
+	Long: `Generate an in-memory workspace (1000 requirements and 500 changes by
+default) and time the core spec and change operations on it. Combine with
+--cpuprofile or --memprofile to capture a pprof profile of the run.
+
+The same operations are covered by 'go test -bench . ./internal/core'.`,
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE:   runBench,
+}
+
+func init() {
+	rootCmd.AddCommand(benchCmd)
+	benchCmd.Flags().IntVar(&benchSize.Domains, "domains", benchSize.Domains, "Number of spec domains to generate")
+	benchCmd.Flags().IntVar(&benchSize.Requirements, "requirements", benchSize.Requirements, "Number of requirements to generate across all domains")
+	benchCmd.Flags().IntVar(&benchSize.Changes, "changes", benchSize.Changes, "Number of pending changes to generate")
+	benchCmd.Flags().DurationVar(&benchMinTime, "benchtime", time.Second, "Minimum time to run each operation")
+}
+
+func runBench(cmd *cobra.Command, args []string) error {
+	if benchSize.Domains < 1 || benchSize.Requirements < benchSize.Domains || benchSize.Changes < 0 {
+		return fmt.Errorf("need at least one domain, one requirement per domain and a non-negative number of changes")
+	}
+	app, err := newApp(core.AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+	started := time.Now()
+	if err := core.GenerateSyntheticWorkspace(app, benchSize); err != nil {
+		return fmt.Errorf("failed to generate workspace: %w", err)
+	}
+	printLine(style.Info, "Generated %d domain(s), %d requirement(s) and %d change(s) in %s",
+		benchSize.Domains, benchSize.Requirements, benchSize.Changes, time.Since(started).Round(time.Millisecond))
+
+	cases, err := core.CoreBenchCases(app, benchSize)
+	if err != nil {
+		return fmt.Errorf("failed to prepare benchmarks: %w", err)
+	}
+	fmt.Printf("%-14s %8s %14s %12s %10s\n", "OPERATION", "RUNS", "TIME/OP", "BYTES/OP", "ALLOCS/OP")
+	for _, c := range cases {
+		r, err := core.Measure(c.Name, benchMinTime, c.Run)
+		if err != nil {
+			return err
+		}
+		porcelain("bench", r.Name, strconv.Itoa(r.Iterations), strconv.FormatInt(r.PerOp.Nanoseconds(), 10),
+			strconv.FormatUint(r.BytesPerOp, 10), strconv.FormatUint(r.AllocsPerOp, 10))
+		fmt.Printf("%-14s %8d %14s %12d %10d\n", r.Name, r.Iterations, r.PerOp, r.BytesPerOp, r.AllocsPerOp)
+	}
+	return nil
+}
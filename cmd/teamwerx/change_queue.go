@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+var changeQueueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Changes that 'change apply --all' left for conflict resolution",
+}
+
+var changeQueueListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List changes waiting for conflict resolution",
+	Args:  cobra.NoArgs,
+	RunE:  runChangeQueueList,
+}
+
+var changeQueueResolveCmd = &cobra.Command{
+	Use:   "resolve",
+	Short: "Resolve and apply queued changes one at a time",
+	Long:  "Run 'change resolve' for the queued change given by --id, or for every queued change oldest first. Changes that apply leave the queue; cancelled ones stay queued.",
+	Args:  cobra.NoArgs,
+	RunE:  runChangeQueueResolve,
+}
+
+func init() {
+	changeCmd.AddCommand(changeQueueCmd)
+	changeQueueCmd.AddCommand(changeQueueListCmd)
+	changeQueueCmd.AddCommand(changeQueueResolveCmd)
+	changeQueueResolveCmd.Flags().StringVar(&changeID, "id", "", "Resolve only this queued change")
+}
+
+func porcelainQueued(q core.QueuedChange) {
+	porcelain("queued", q.ChangeID, strings.Join(q.Domains, ","), strconv.Itoa(q.Attempts), porcelainTime(q.QueuedAt), q.Reason)
+}
+
+// dequeueChange removes a change that has been applied from the
+// needs-resolution queue.
+func dequeueChange(app *core.App, id string) error {
+	queue, err := app.LoadApplyQueue()
+	if err != nil {
+		return fmt.Errorf("failed to load apply queue: %w", err)
+	}
+	if !queue.Remove(id) {
+		return nil
+	}
+	if err := app.SaveApplyQueue(queue); err != nil {
+		return fmt.Errorf("failed to save apply queue: %w", err)
+	}
+	return nil
+}
+
+// applyAllChanges implements 'change apply --all'.
+func applyAllChanges(app *core.App, filter core.ChangeFilter) error {
+	result, err := app.ApplyPendingMatching(filter, func(ch *model.Change) error { return checkChangeApprovals(app, ch) })
+	for _, ch := range result.Applied {
+		porcelainChange(ch)
+		style.Success.Printf("Applied change %s: %s\n", ch.ID, ch.Title)
+	}
+	for _, q := range result.Queued {
+		porcelainQueued(q)
+		printLine(style.Warn, "Queued change %s for resolution: diverged in %s", q.ChangeID, strings.Join(q.Domains, ", "))
+	}
+	skipped := make([]string, 0, len(result.Skipped))
+	for id := range result.Skipped {
+		skipped = append(skipped, id)
+	}
+	sort.Strings(skipped)
+	for _, id := range skipped {
+		porcelain("skipped", id, result.Skipped[id])
+		printLine(style.Warn, "Skipped change %s: %s", id, result.Skipped[id])
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%d applied, %d queued, %d skipped.\n", len(result.Applied), len(result.Queued), len(result.Skipped))
+	if len(result.Queued) > 0 {
+		fmt.Println("Run 'teamwerx change queue resolve' to work through the queue.")
+	}
+	return nil
+}
+
+func runChangeQueueList(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		ChangesDir: changesBaseDir,
+		CharterDir: charterBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+	queue, err := app.LoadApplyQueue()
+	if err != nil {
+		return fmt.Errorf("failed to load apply queue: %w", err)
+	}
+	if len(queue.Changes) == 0 {
+		printLine(style.Success, "No changes need resolution.")
+		return nil
+	}
+
+	fmt.Printf("%-16s %-8s %-20s %s\n", "CHANGE", "ATTEMPTS", "QUEUED", "DIVERGED DOMAINS")
+	for _, q := range queue.Changes {
+		porcelainQueued(q)
+		fmt.Printf("%-16s %-8d %-20s %s\n", q.ChangeID, q.Attempts, displayTime(q.QueuedAt, "2006-01-02 15:04"), strings.Join(q.Domains, ", "))
+	}
+	return nil
+}
+
+func runChangeQueueResolve(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		ChangesDir: changesBaseDir,
+		CharterDir: charterBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+	queue, err := app.LoadApplyQueue()
+	if err != nil {
+		return fmt.Errorf("failed to load apply queue: %w", err)
+	}
+
+	var ids []string
+	if id := strings.TrimSpace(changeID); id != "" {
+		if queue.Find(id) == nil {
+			return fmt.Errorf("change %s is not queued for resolution", id)
+		}
+		ids = append(ids, id)
+	} else {
+		for _, q := range queue.Changes {
+			ids = append(ids, q.ChangeID)
+		}
+	}
+	if len(ids) == 0 {
+		printLine(style.Success, "No changes need resolution.")
+		return nil
+	}
+
+	resolved := 0
+	for _, id := range ids {
+		style.Header.Printf("\nResolving %s\n", id)
+		ch, err := app.ChangeManager.ReadChange(id)
+		if err != nil {
+			return fmt.Errorf("failed to read change: %w", err)
+		}
+		applied, err := resolveChange(app, ch)
+		if err != nil {
+			return err
+		}
+		if applied {
+			resolved++
+		}
+	}
+	fmt.Printf("\n%d of %d queued change(s) resolved.\n", resolved, len(ids))
+	return nil
+}
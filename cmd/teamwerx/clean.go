@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+)
+
+var (
+	cleanDryRun        bool
+	cleanRetentionDays int
+	cleanTrashDays     int
+)
+
+var cleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove workspace garbage",
+	Long:  "Remove temp files left by interrupted writes, empty goal/spec directories, archived changes without a change.json, split/merged archives older than the retention window, and trashed items older than the trash retention window.",
+	Args:  cobra.NoArgs,
+	RunE:  runClean,
+}
+
+func init() {
+	rootCmd.AddCommand(cleanCmd)
+	cleanCmd.Flags().BoolVar(&cleanDryRun, "dry-run", false, "List what would be removed without deleting anything")
+	cleanCmd.Flags().IntVar(&cleanRetentionDays, "retention-days", 30, "Keep split/merged archived changes newer than this many days (0 uses the default)")
+	cleanCmd.Flags().IntVar(&cleanTrashDays, "trash-retention-days", 30, "Keep trashed items newer than this many days (0 uses the default)")
+	cleanCmd.Flags().StringVar(&specsBaseDir, "specs-dir", ".teamwerx/specs", "Base directory containing spec domains")
+	cleanCmd.Flags().StringVar(&goalsBaseDir, "goals-dir", ".teamwerx/goals", "Base directory containing goals")
+	cleanCmd.Flags().StringVar(&changesBaseDir, "changes-dir", ".teamwerx/changes", "Base directory containing changes")
+	cleanCmd.Flags().StringVar(&charterBaseDir, "charter-dir", ".teamwerx", "Base directory for charter")
+}
+
+func runClean(cmd *cobra.Command, args []string) error {
+	if cleanRetentionDays < 0 {
+		return fmt.Errorf("--retention-days must not be negative")
+	}
+	if cleanTrashDays < 0 {
+		return fmt.Errorf("--trash-retention-days must not be negative")
+	}
+
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+		CharterDir: charterBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	items, err := core.FindGarbage(app.Options, core.CleanOptions{
+		Retention:      time.Duration(cleanRetentionDays) * 24 * time.Hour,
+		TrashRetention: time.Duration(cleanTrashDays) * 24 * time.Hour,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan workspace: %w", err)
+	}
+
+	if len(items) == 0 {
+		printLine(style.Success, "Workspace is clean.")
+		return nil
+	}
+
+	if cleanDryRun {
+		for _, item := range items {
+			porcelain("garbage", "would-remove", item.Kind, item.Path, item.Reason)
+			fmt.Printf("Would remove %-18s %s (%s)\n", item.Kind, item.Path, item.Reason)
+		}
+		printLine(style.Warn, "Dry run: %d item(s) not removed.", len(items))
+		return nil
+	}
+	if err := core.RemoveGarbage(app.Options, items); err != nil {
+		return fmt.Errorf("failed to clean workspace: %w", err)
+	}
+	paths := make([]string, len(items))
+	for i, item := range items {
+		paths[i] = item.Path
+	}
+	if err := core.ForgetManifestPaths(app.Options, paths); err != nil {
+		return fmt.Errorf("failed to update manifest: %w", err)
+	}
+	for _, item := range items {
+		porcelain("garbage", "removed", item.Kind, item.Path, item.Reason)
+		fmt.Printf("Removed %-18s %s (%s)\n", item.Kind, item.Path, item.Reason)
+	}
+	printLine(style.Success, "Removed %d item(s).", len(items))
+	return nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+)
+
+var (
+	decisionGoals []string
+	decisionsOut  string
+)
+
+var decisionsCmd = &cobra.Command{
+	Use:   "decisions",
+	Short: "Print a decision log of every 'decision' discussion entry",
+	Long: `Collect the discussion entries of type 'decision' across goals, oldest
+first, into a markdown decision log. Record decisions with:
+
+  teamwerx discuss add --goal 001-auth --type decision "Use JWT for sessions"`,
+	Args: cobra.NoArgs,
+	RunE: runDecisions,
+}
+
+var discussTypesCmd = &cobra.Command{
+	Use:   "types",
+	Short: "List the discussion entry types this workspace accepts",
+	Long:  "List the built-in entry types and any added under 'discussion.types' in .teamwerx/config.yaml.",
+	Args:  cobra.NoArgs,
+	RunE:  runDiscussTypes,
+}
+
+func init() {
+	rootCmd.AddCommand(decisionsCmd)
+	decisionsCmd.Flags().StringArrayVar(&decisionGoals, "goal", nil, "Only include this goal (repeatable)")
+	decisionsCmd.Flags().StringVar(&decisionsOut, "out", "", "Write the decision log to this file instead of stdout")
+	decisionsCmd.Flags().StringVar(&goalsBaseDir, "goals-dir", ".teamwerx/goals", "Base directory containing goals")
+	decisionsCmd.Flags().StringVar(&charterBaseDir, "charter-dir", ".teamwerx", "Base directory for charter and config")
+
+	discussCmd.AddCommand(discussTypesCmd)
+}
+
+var entryColors = map[string]color.Attribute{
+	"black":   color.FgBlack,
+	"red":     color.FgRed,
+	"green":   color.FgGreen,
+	"yellow":  color.FgYellow,
+	"blue":    color.FgBlue,
+	"magenta": color.FgMagenta,
+	"cyan":    color.FgCyan,
+	"white":   color.FgWhite,
+}
+
+// entryTypeLabel renders an entry type with its icon and color, e.g. "✔ decision".
+func entryTypeLabel(types *core.DiscussionTypeRegistry, name string) string {
+	t, _ := types.Lookup(name)
+	label := t.Name
+	if t.Icon != "" {
+		label = t.Icon + " " + label
+	}
+	if c, ok := entryColors[t.Color]; ok && !style.NoColor {
+		return color.New(c).Sprint(label)
+	}
+	return label
+}
+
+func runDiscussTypes(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{GoalsDir: goalsBaseDir})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+	for _, t := range app.DiscussionTypes.Types() {
+		porcelain("entry-type", t.Name, t.Icon, t.Color, t.Description)
+		fmt.Printf("%-28s %s\n", entryTypeLabel(app.DiscussionTypes, t.Name), t.Description)
+	}
+	return nil
+}
+
+func runDecisions(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{GoalsDir: goalsBaseDir, CharterDir: charterBaseDir})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+	decisions, err := app.Decisions(decisionGoals...)
+	if err != nil {
+		return fmt.Errorf("failed to collect decisions: %w", err)
+	}
+	for _, d := range decisions {
+		porcelain("decision", d.GoalID, d.Entry.ID, porcelainTime(d.Entry.Timestamp), d.Entry.Author, d.Entry.Content)
+	}
+	log := core.FormatDecisionLog(decisions)
+	if decisionsOut == "" {
+		fmt.Print(log)
+		return nil
+	}
+	if err := ioutil.WriteFile(decisionsOut, []byte(log), 0o644); err != nil {
+		return fmt.Errorf("failed to write decision log: %w", err)
+	}
+	porcelainFile("written", decisionsOut)
+	printLine(style.Success, "Wrote %d decision(s) to %s", len(decisions), decisionsOut)
+	return nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+	"github.com/teamwerx/teamwerx/internal/storage"
+	gitutil "github.com/teamwerx/teamwerx/internal/utils/git"
+)
+
+var diffstatOutput string
+
+var diffstatCmd = &cobra.Command{
+	Use:   "diffstat [from] [to]",
+	Short: "Summarize workspace changes between two git revisions as markdown",
+	Long: `Compare the workspace at two git revisions: requirements added, modified and
+removed per domain, tasks added and completed, and changes applied. from
+defaults to HEAD and to to the working tree. The markdown output is meant to
+be posted as a pull request comment by CI:
+
+  teamwerx diffstat origin/main HEAD > diffstat.md`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runDiffstat,
+}
+
+func init() {
+	rootCmd.AddCommand(diffstatCmd)
+	diffstatCmd.Flags().StringVarP(&diffstatOutput, "output", "o", "markdown", "Output format: markdown or json")
+	diffstatCmd.Flags().StringVar(&specsBaseDir, "specs-dir", ".teamwerx/specs", "Base directory containing spec domains")
+	diffstatCmd.Flags().StringVar(&goalsBaseDir, "goals-dir", ".teamwerx/goals", "Base directory containing goals")
+	diffstatCmd.Flags().StringVar(&changesBaseDir, "changes-dir", ".teamwerx/changes", "Base directory containing changes")
+}
+
+func runDiffstat(cmd *cobra.Command, args []string) error {
+	if diffstatOutput != "markdown" && diffstatOutput != "json" {
+		return fmt.Errorf("unknown --output %q (expected markdown or json)", diffstatOutput)
+	}
+	from, to := "HEAD", ""
+	if len(args) > 0 {
+		from = args[0]
+	}
+	if len(args) > 1 {
+		to = args[1]
+	}
+
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+		CharterDir: charterBaseDir,
+		ReadOnly:   true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+	before, err := workspaceAt(app, from)
+	if err != nil {
+		return err
+	}
+	after, toLabel := app, "working tree"
+	if to != "" {
+		if after, err = workspaceAt(app, to); err != nil {
+			return err
+		}
+		toLabel = to
+	}
+
+	stat, err := core.ComputeDiffstat(before, after)
+	if err != nil {
+		return fmt.Errorf("failed to compare workspaces: %w", err)
+	}
+	stat.From, stat.To = from, toLabel
+	if diffstatOutput == "json" {
+		data, err := json.MarshalIndent(stat, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, string(data))
+		return nil
+	}
+	fmt.Fprint(stdout, core.FormatDiffstatMarkdown(stat))
+	return nil
+}
+
+// workspaceAt returns a read-only App over app's workspace as of the git
+// revision ref: its specs, goals, changes and config, read into memory.
+func workspaceAt(app *core.App, ref string) (*core.App, error) {
+	ctx := context.Background()
+	o := app.Options
+	store := storage.NewInMem()
+	for _, dir := range []string{o.SpecsDir, o.GoalsDir, o.ChangesDir} {
+		// Directories missing at ref read as empty.
+		if err := store.MkdirAll(relativeToCwd(dir), 0o755); err != nil {
+			return nil, err
+		}
+		files, err := gitutil.ListFiles(ctx, ".", ref, relativeToCwd(dir))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s at %s: %w", dir, ref, err)
+		}
+		for _, file := range files {
+			content, err := gitutil.Show(ctx, ".", ref, file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s at %s: %w", file, ref, err)
+			}
+			if err := store.WriteFile(filepath.FromSlash(file), []byte(content), 0o644); err != nil {
+				return nil, err
+			}
+		}
+	}
+	config := relativeToCwd(core.ConfigPath(o.CharterDir))
+	if content, err := gitutil.Show(ctx, ".", ref, config); err == nil {
+		if err := store.WriteFile(config, []byte(content), 0o644); err != nil {
+			return nil, err
+		}
+	}
+	return core.NewApp(core.AppOptions{
+		Storage:    store,
+		SpecsDir:   relativeToCwd(o.SpecsDir),
+		GoalsDir:   relativeToCwd(o.GoalsDir),
+		ChangesDir: relativeToCwd(o.ChangesDir),
+		CharterDir: relativeToCwd(o.CharterDir),
+		ReadOnly:   true,
+	})
+}
+
+// relativeToCwd returns path relative to the working directory, as git
+// lists files.
+func relativeToCwd(path string) string {
+	if !filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(cwd, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
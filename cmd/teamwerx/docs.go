@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+)
+
+var (
+	docsOutDir    string
+	docsTitle     string
+	docsOmitNotes bool
+)
+
+var docsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Publish the workspace as documentation",
+}
+
+var docsBuildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "Generate a static documentation site from the workspace",
+	Long: `Generate a static site from the workspace: the charter, specs by domain, a
+page per requirement with its scenarios, linked tasks and changes, goal
+summaries and the change history, with navigation and search on every page.
+The site needs no server; open index.html or publish the directory as is.
+
+  teamwerx docs build --out site/`,
+	Args: cobra.NoArgs,
+	RunE: runDocsBuild,
+}
+
+func init() {
+	rootCmd.AddCommand(docsCmd)
+	docsCmd.AddCommand(docsBuildCmd)
+	docsBuildCmd.Flags().StringVar(&docsOutDir, "out", "site", "Directory to write the site to")
+	docsBuildCmd.Flags().StringVar(&docsTitle, "title", "", "Site title (default: the charter's title)")
+	docsBuildCmd.Flags().BoolVar(&docsOmitNotes, "omit-notes", false, "Leave requirement notes (rationale and <details>) out of the site")
+	docsBuildCmd.Flags().StringVar(&specsBaseDir, "specs-dir", ".teamwerx/specs", "Base directory containing spec domains")
+	docsBuildCmd.Flags().StringVar(&goalsBaseDir, "goals-dir", ".teamwerx/goals", "Base directory containing goals")
+	docsBuildCmd.Flags().StringVar(&changesBaseDir, "changes-dir", ".teamwerx/changes", "Base directory containing changes")
+	docsBuildCmd.Flags().StringVar(&charterBaseDir, "charter-dir", ".teamwerx", "Base directory for charter")
+}
+
+func runDocsBuild(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+		CharterDir: charterBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+	site, err := app.BuildDocsSite(core.DocsSiteOptions{Title: docsTitle, OmitNotes: docsOmitNotes})
+	if err != nil {
+		return fmt.Errorf("failed to build site: %w", err)
+	}
+
+	for _, p := range site.Paths() {
+		out := filepath.Join(docsOutDir, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(out), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(out), err)
+		}
+		if err := os.WriteFile(out, site.Pages[p], 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", out, err)
+		}
+		porcelainFile("written", out)
+	}
+	printLine(style.Success, "Wrote %d file(s) to %s; open %s", len(site.Pages), docsOutDir, filepath.Join(docsOutDir, "index.html"))
+	return nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+	promptutil "github.com/teamwerx/teamwerx/internal/utils/prompt"
+	"github.com/teamwerx/teamwerx/internal/version"
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Print resolved workspace configuration as JSON",
+	Long:  "Print the resolved base directories, file layout, workspace root, config file, interactivity mode, read-only mode and version as JSON without modifying anything.",
+	Args:  cobra.NoArgs,
+	RunE:  runEnv,
+}
+
+// porcelainEnv writes one "env" record per setting, in the order of envInfo's
+// JSON fields; layout keys are prefixed with "layout.".
+func porcelainEnv(info envInfo) {
+	porcelain("env", "version", info.Version)
+	porcelain("env", "working_dir", info.WorkingDir)
+	porcelain("env", "workspace_root", info.WorkspaceRoot)
+	porcelain("env", "config_file", info.ConfigFile)
+	porcelain("env", "config_exists", strconv.FormatBool(info.ConfigExists))
+	porcelain("env", "specs_dir", info.SpecsDir)
+	porcelain("env", "goals_dir", info.GoalsDir)
+	porcelain("env", "changes_dir", info.ChangesDir)
+	porcelain("env", "charter_dir", info.CharterDir)
+	porcelain("env", "interactive", strconv.FormatBool(info.Interactive))
+	porcelain("env", "read_only", strconv.FormatBool(info.ReadOnly))
+	l := info.Layout
+	porcelain("env", "layout.spec_file", l.SpecFile)
+	porcelain("env", "layout.review_file", l.ReviewFile)
+	porcelain("env", "layout.plan_file", l.PlanFile)
+	porcelain("env", "layout.discussion_file", l.DiscussionFile)
+	porcelain("env", "layout.attachments_dir", l.AttachmentsDir)
+	porcelain("env", "layout.change_file", l.ChangeFile)
+	porcelain("env", "layout.archive_dir", l.ArchiveDir)
+	porcelain("env", "layout.charter_file", l.CharterFile)
+}
+
+// envInfo is the JSON document printed by `teamwerx env`.
+type envInfo struct {
+	Version       string      `json:"version"`
+	WorkingDir    string      `json:"working_dir"`
+	WorkspaceRoot string      `json:"workspace_root"`
+	ConfigFile    string      `json:"config_file"`
+	ConfigExists  bool        `json:"config_exists"`
+	SpecsDir      string      `json:"specs_dir"`
+	GoalsDir      string      `json:"goals_dir"`
+	ChangesDir    string      `json:"changes_dir"`
+	CharterDir    string      `json:"charter_dir"`
+	Interactive   bool        `json:"interactive"`
+	ReadOnly      bool        `json:"read_only"`
+	Layout        core.Layout `json:"layout"`
+}
+
+func init() {
+	rootCmd.AddCommand(envCmd)
+	envCmd.Flags().StringVar(&specsBaseDir, "specs-dir", ".teamwerx/specs", "Base directory containing spec domains")
+	envCmd.Flags().StringVar(&goalsBaseDir, "goals-dir", ".teamwerx/goals", "Base directory containing goals")
+	envCmd.Flags().StringVar(&changesBaseDir, "changes-dir", ".teamwerx/changes", "Base directory containing changes")
+	envCmd.Flags().StringVar(&charterBaseDir, "charter-dir", ".teamwerx", "Base directory for charter")
+}
+
+func runEnv(cmd *cobra.Command, args []string) error {
+	o, err := core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+		CharterDir: charterBaseDir,
+		ReadOnly:   readOnlyMode(),
+	}.Resolve()
+	if err != nil {
+		return err
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to resolve working directory: %w", err)
+	}
+
+	info := envInfo{
+		Version:       version.Version,
+		WorkingDir:    wd,
+		WorkspaceRoot: core.FindWorkspaceRoot(wd),
+		SpecsDir:      absPath(o.SpecsDir),
+		GoalsDir:      absPath(o.GoalsDir),
+		ChangesDir:    absPath(o.ChangesDir),
+		CharterDir:    absPath(o.CharterDir),
+		Interactive:   promptutil.IsInteractive(),
+		ReadOnly:      o.ReadOnly,
+		Layout:        o.Layout,
+	}
+	if info.WorkspaceRoot != "" {
+		info.ConfigFile = filepath.Join(info.WorkspaceRoot, core.WorkspaceDirName, core.ConfigFileName)
+		if _, err := os.Stat(info.ConfigFile); err == nil {
+			info.ConfigExists = true
+		}
+	}
+
+	if porcelainMode {
+		porcelainEnv(info)
+		return nil
+	}
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode env: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+)
+
+// errorFormat selects how main reports a failed command: "text" or "json".
+var errorFormat string
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&errorFormat, "error-format", "text", "How to report errors: text, or json (an object on stderr with any field-level validation errors)")
+}
+
+// errorReport is the --error-format json document.
+type errorReport struct {
+	Error      string                         `json:"error"`
+	Validation []*custom_errors.ErrValidation `json:"validation,omitempty"`
+}
+
+// jsonErrors reports whether --error-format asks for JSON.
+func jsonErrors() bool {
+	return strings.EqualFold(errorFormat, "json")
+}
+
+// reportError writes err to w: as JSON with --error-format json, otherwise
+// as "Error: ..." with several validation problems listed one per line,
+// grouped by the first segment of their field path.
+func reportError(w io.Writer, err error) {
+	fields := custom_errors.Validation(err)
+	if jsonErrors() {
+		data, _ := json.Marshal(errorReport{Error: err.Error(), Validation: fields})
+		fmt.Fprintln(w, string(data))
+		return
+	}
+	if len(fields) < 2 {
+		fmt.Fprintln(w, "Error:", err)
+		return
+	}
+
+	fmt.Fprintf(w, "Error: %s (%d problem(s))\n", validationHeadline(err, fields), len(fields))
+	var groups []string
+	byGroup := map[string][]*custom_errors.ErrValidation{}
+	for _, f := range fields {
+		group, _ := splitFieldPath(f.Field)
+		if _, ok := byGroup[group]; !ok {
+			groups = append(groups, group)
+		}
+		byGroup[group] = append(byGroup[group], f)
+	}
+	red := style.Error
+	for _, group := range groups {
+		indent := "  "
+		if group != "" {
+			fmt.Fprintf(w, "  %s\n", group)
+			indent = "    "
+		}
+		for _, f := range byGroup[group] {
+			if _, rest := splitFieldPath(f.Field); rest != "" {
+				red.Fprintf(w, "%s- %s: ", indent, rest)
+			} else {
+				red.Fprintf(w, "%s- ", indent)
+			}
+			fmt.Fprintln(w, f.Reason)
+		}
+	}
+}
+
+// validationHeadline is err's message without the trailing list of
+// validation problems, e.g. "invalid config '.teamwerx/config.yaml'".
+func validationHeadline(err error, fields []*custom_errors.ErrValidation) string {
+	msg := err.Error()
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		parts[i] = f.Error()
+	}
+	for _, tail := range []string{custom_errors.ValidationErrors(fields).Error(), strings.Join(parts, "; ")} {
+		if head := strings.TrimSuffix(msg, tail); head != msg {
+			if head = strings.TrimRight(head, ": "); head != "" {
+				return head
+			}
+		}
+	}
+	return "invalid input"
+}
+
+// splitFieldPath splits "spec_deltas[0].domain" into "spec_deltas[0]" and
+// "domain". A path with one segment is a field of its own, not a group.
+func splitFieldPath(field string) (string, string) {
+	if i := strings.IndexByte(field, '.'); i > 0 {
+		return field[:i], field[i+1:]
+	}
+	return "", field
+}
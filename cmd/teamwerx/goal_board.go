@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/teamwerx/teamwerx/internal/core"
+)
+
+var (
+	goalListBoard bool
+	goalListJSON  bool
+	goalListAll   bool
+)
+
+// boardWidth is the width of one board column in the terminal table.
+const boardWidth = 30
+
+func init() {
+	goalListCmd.Flags().BoolVar(&goalListBoard, "board", false, "Show tasks across goals as a board with pending, in-progress, review and completed columns")
+	goalListCmd.Flags().BoolVar(&goalListJSON, "json", false, "With --board, print the board as JSON")
+	goalListCmd.Flags().BoolVar(&goalListAll, "all", false, "With --board, include completed goals")
+}
+
+func runGoalBoard(app *core.App) error {
+	board, err := app.BuildBoard(core.BoardOptions{All: goalListAll})
+	if err != nil {
+		return fmt.Errorf("failed to build board: %w", err)
+	}
+	for _, col := range board.Columns {
+		for _, c := range col.Cards {
+			porcelainTask(c.Goal, c.Task())
+		}
+	}
+	if goalListJSON {
+		data, err := json.MarshalIndent(board, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, string(data))
+		return nil
+	}
+	if len(board.Goals) == 0 {
+		fmt.Println("No goals with open plans found.")
+		return nil
+	}
+
+	bold := style.Header
+	yellow := style.Warn
+	var header, rule []string
+	rows := 0
+	for _, col := range board.Columns {
+		header = append(header, bold.Sprint(boardCell(fmt.Sprintf("%s (%d)", strings.ToUpper(col.Status), col.Count))))
+		rule = append(rule, boardCell(strings.Repeat("-", boardWidth-2)))
+		if len(col.Cards) > rows {
+			rows = len(col.Cards)
+		}
+	}
+	printBoardRow(header)
+	printBoardRow(rule)
+	for i := 0; i < rows; i++ {
+		// Each card takes two lines: its ID and assignee, then its title.
+		var head, title []string
+		for _, col := range board.Columns {
+			if i >= len(col.Cards) {
+				head, title = append(head, boardCell("")), append(title, boardCell(""))
+				continue
+			}
+			c := col.Cards[i]
+			h := c.Goal + "/" + c.ID
+			if c.Assignee != "" {
+				h += " " + c.Assignee
+			}
+			if c.Status == core.TaskStatusBlocked || c.Status == core.TaskStatusWaived {
+				h = yellow.Sprint(boardCell(h + " [" + c.Status + "]"))
+			} else {
+				h = boardCell(h)
+			}
+			head, title = append(head, h), append(title, boardCell("  "+c.Title))
+		}
+		printBoardRow(head)
+		printBoardRow(title)
+	}
+
+	fmt.Println()
+	for _, col := range board.Columns {
+		if col.Count == 0 {
+			continue
+		}
+		var parts []string
+		for _, name := range col.AssigneeNames() {
+			label := name
+			if label == "" {
+				label = "unassigned"
+			}
+			parts = append(parts, fmt.Sprintf("%s %d", label, col.Assignees[name]))
+		}
+		fmt.Printf("%-12s %s\n", col.Status+":", strings.Join(parts, ", "))
+	}
+	return nil
+}
+
+// printBoardRow prints one line of board cells without trailing padding.
+func printBoardRow(cells []string) {
+	fmt.Println(strings.TrimRight(strings.Join(cells, ""), " "))
+}
+
+// boardCell pads or truncates s to one board column.
+func boardCell(s string) string {
+	r := []rune(s)
+	if len(r) > boardWidth-2 {
+		r = append(r[:boardWidth-3], '…')
+	}
+	return string(r) + strings.Repeat(" ", boardWidth-len(r))
+}
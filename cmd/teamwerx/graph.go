@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+)
+
+var (
+	graphFormat       string
+	graphOut          string
+	graphGoal         string
+	graphRequirements bool
+	graphTasks        bool
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Draw how goals, changes and spec domains relate as a Mermaid or Graphviz diagram",
+	Long: `Render goals, the pending changes they propose, the spec domains those
+changes modify and (with --requirements) each domain's requirements. --tasks
+adds goal plans, subtasks and the requirements tasks link to; dashed edges
+are change dependencies.
+
+  teamwerx graph > workspace.mmd
+  teamwerx graph --goal 001-auth --requirements --tasks --out docs/auth.md
+  teamwerx graph --format dot | dot -Tsvg > workspace.svg
+
+Writing Mermaid to a .md file wraps it in a mermaid code fence, which GitHub
+and most doc sites render.`,
+	Args: cobra.NoArgs,
+	RunE: runGraph,
+}
+
+func init() {
+	rootCmd.AddCommand(graphCmd)
+	graphCmd.Flags().StringVar(&graphFormat, "format", "mermaid", "Output format: mermaid, dot or json")
+	graphCmd.Flags().StringVar(&graphOut, "out", "", "File to write (default: stdout)")
+	graphCmd.Flags().StringVar(&graphGoal, "goal", "", "Only draw this goal, its changes and the domains they modify")
+	graphCmd.Flags().BoolVar(&graphRequirements, "requirements", false, "Draw each domain's requirements")
+	graphCmd.Flags().BoolVar(&graphTasks, "tasks", false, "Draw goal plans, subtasks and task requirement links")
+	graphCmd.Flags().StringVar(&specsBaseDir, "specs-dir", ".teamwerx/specs", "Base directory containing spec domains")
+	graphCmd.Flags().StringVar(&goalsBaseDir, "goals-dir", ".teamwerx/goals", "Base directory containing goals")
+	graphCmd.Flags().StringVar(&changesBaseDir, "changes-dir", ".teamwerx/changes", "Base directory containing changes")
+}
+
+func runGraph(cmd *cobra.Command, args []string) error {
+	format := strings.ToLower(strings.TrimSpace(graphFormat))
+	if format != "mermaid" && format != "dot" && format != "json" {
+		return fmt.Errorf("unknown format %q (want mermaid, dot or json)", graphFormat)
+	}
+	app, err := newApp(core.AppOptions{SpecsDir: specsBaseDir, GoalsDir: goalsBaseDir, ChangesDir: changesBaseDir})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+	g, err := app.BuildGraph(core.GraphOptions{Goal: graphGoal, Requirements: graphRequirements, Tasks: graphTasks})
+	if err != nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	var data []byte
+	switch format {
+	case "dot":
+		data = []byte(core.RenderDOT(g))
+	case "json":
+		if data, err = json.MarshalIndent(g, "", "  "); err != nil {
+			return err
+		}
+		data = append(data, '\n')
+	default:
+		out := core.RenderMermaid(g)
+		if strings.EqualFold(filepath.Ext(graphOut), ".md") {
+			out = "```mermaid\n" + out + "```\n"
+		}
+		data = []byte(out)
+	}
+
+	if graphOut == "" {
+		_, err := stdout.Write(data)
+		return err
+	}
+	if err := ioutil.WriteFile(graphOut, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write graph: %w", err)
+	}
+	porcelainFile("written", graphOut)
+	style.Success.Printf("Wrote %d node(s) and %d edge(s) to %s\n", len(g.Nodes), len(g.Edges), graphOut)
+	return nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+)
+
+var (
+	grepTypes      []string
+	grepIgnoreCase bool
+	grepJSON       bool
+)
+
+var grepCmd = &cobra.Command{
+	Use:   "grep <pattern>",
+	Short: "Search specs, plans, discussions and changes with a regular expression",
+	Long: `Search artifact files line by line with a Go regular expression and print
+each match with its file, line and column and the part of the artifact it is
+in: the requirement of a spec, the task of a plan, the entry (with its type
+and author) of a discussion, or the domain and requirement of a change.
+
+--type limits the search to spec, plan, discuss or change (repeatable).
+--json prints the matches with their locations for editors and other tools.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGrep,
+}
+
+func init() {
+	rootCmd.AddCommand(grepCmd)
+	grepCmd.Flags().StringSliceVar(&grepTypes, "type", nil, "Only search this artifact type: spec, plan, discuss or change (repeatable)")
+	grepCmd.Flags().BoolVarP(&grepIgnoreCase, "ignore-case", "i", false, "Match without regard to case")
+	grepCmd.Flags().BoolVar(&grepJSON, "json", false, "Print matches as JSON")
+	grepCmd.Flags().StringVar(&specsBaseDir, "specs-dir", ".teamwerx/specs", "Base directory containing spec domains")
+	grepCmd.Flags().StringVar(&goalsBaseDir, "goals-dir", ".teamwerx/goals", "Base directory containing goals")
+	grepCmd.Flags().StringVar(&changesBaseDir, "changes-dir", ".teamwerx/changes", "Base directory containing changes")
+}
+
+func runGrep(cmd *cobra.Command, args []string) error {
+	expr := args[0]
+	if expr == "" {
+		return fmt.Errorf("pattern is required")
+	}
+	if grepIgnoreCase {
+		expr = "(?i)" + expr
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
+
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+	matches, err := app.Grep(core.GrepOptions{Pattern: re, Types: grepTypes})
+	if err != nil {
+		return err
+	}
+
+	for _, m := range matches {
+		porcelain("match", m.Type, m.Path, strconv.Itoa(m.Line), strconv.Itoa(m.Column), grepContext(m), m.Text)
+	}
+	if grepJSON {
+		if matches == nil {
+			matches = []core.GrepMatch{}
+		}
+		data, err := json.MarshalIndent(matches, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, string(data))
+		return nil
+	}
+	if len(matches) == 0 {
+		printLine(style.Warn, "No matches.")
+		return nil
+	}
+
+	loc := style.ID
+	ctx := style.Muted
+	hit := style.Error
+	for _, m := range matches {
+		loc.Printf("%s:%d:%d", m.Path, m.Line, m.Column)
+		if c := grepContext(m); c != "" {
+			ctx.Printf(" [%s]", c)
+		}
+		text := strings.TrimSpace(m.Text)
+		fmt.Printf(" %s\n", strings.Replace(text, m.Match, hit.Sprint(m.Match), 1))
+	}
+	fmt.Printf("\n%d match(es).\n", len(matches))
+	return nil
+}
+
+// grepContext describes where in its artifact a match is, e.g.
+// "auth/login", "001-auth T03: Build login" or "001-auth D02 decision by @ana".
+func grepContext(m core.GrepMatch) string {
+	switch m.Type {
+	case core.GrepTypeSpec:
+		if m.RequirementID == "" {
+			return m.Domain
+		}
+		return m.Domain + "/" + m.RequirementID
+	case core.GrepTypePlan:
+		if m.TaskID == "" {
+			return m.Goal
+		}
+		return fmt.Sprintf("%s %s: %s", m.Goal, m.TaskID, m.TaskTitle)
+	case core.GrepTypeDiscuss:
+		if m.EntryID == "" {
+			return m.Goal
+		}
+		s := fmt.Sprintf("%s %s %s", m.Goal, m.EntryID, m.EntryType)
+		if m.Author != "" {
+			s += " by " + m.Author
+		}
+		return s
+	case core.GrepTypeChange:
+		s := m.ChangeID
+		if m.Domain != "" {
+			s += " " + m.Domain
+			if m.RequirementID != "" {
+				s += "/" + m.RequirementID
+			}
+		}
+		return s
+	}
+	return ""
+}
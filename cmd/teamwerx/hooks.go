@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+	gitutil "github.com/teamwerx/teamwerx/internal/utils/git"
+)
+
+var (
+	hookNames     []string
+	hookManager   string
+	hookForce     bool
+	hookUninstall bool
+)
+
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Install git hooks that check workspace artifacts",
+}
+
+var hooksInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install pre-commit and pre-push hooks that block malformed artifacts",
+	Long: `Install git hooks that run 'teamwerx hooks run <hook>':
+
+  pre-commit  checks the staged spec, change, plan and discussion files
+  pre-push    checks every artifact in the workspace
+
+A check fails on files that do not parse, specs missing a required section
+or holding conflict markers (as 'spec validate' reports), and pending changes
+whose deltas do not validate (as 'change check' reports). Approvals and stale
+fingerprints are not checked.
+
+--manager picks where the hooks go: git writes scripts to the git hooks
+directory, husky adds a line to .husky/<hook>, and lefthook adds a teamwerx
+command to lefthook.yml. The default detects husky and lefthook and falls
+back to git. An existing git hook is kept unless --force, which saves it as
+<hook>.pre-teamwerx and runs it before the check. --uninstall removes the
+hooks again and restores saved ones.`,
+	Args: cobra.NoArgs,
+	RunE: runHooksInstall,
+}
+
+var hooksRunCmd = &cobra.Command{
+	Use:       "run <pre-commit|pre-push>",
+	Short:     "Run a hook's checks (called by the installed hooks)",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: core.GitHookNames,
+	RunE:      runHooksRun,
+}
+
+func init() {
+	rootCmd.AddCommand(hooksCmd)
+	hooksCmd.AddCommand(hooksInstallCmd)
+	hooksCmd.AddCommand(hooksRunCmd)
+	hooksInstallCmd.Flags().StringSliceVar(&hookNames, "hook", nil, "Hook to install: pre-commit or pre-push (repeatable; default both)")
+	hooksInstallCmd.Flags().StringVar(&hookManager, "manager", "auto", "Where to install: auto, git, husky or lefthook")
+	hooksInstallCmd.Flags().BoolVar(&hookForce, "force", false, "Replace an existing git hook, keeping it as <hook>.pre-teamwerx")
+	hooksInstallCmd.Flags().BoolVar(&hookUninstall, "uninstall", false, "Remove the hooks instead")
+	hooksRunCmd.Flags().StringVar(&specsBaseDir, "specs-dir", ".teamwerx/specs", "Base directory containing spec domains")
+	hooksRunCmd.Flags().StringVar(&goalsBaseDir, "goals-dir", ".teamwerx/goals", "Base directory containing goals")
+	hooksRunCmd.Flags().StringVar(&changesBaseDir, "changes-dir", ".teamwerx/changes", "Base directory containing changes")
+	hooksRunCmd.Flags().StringVar(&charterBaseDir, "charter-dir", ".teamwerx", "Base directory for charter and config")
+}
+
+func runHooksInstall(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	root, err := gitutil.RepoRoot(ctx, ".")
+	if err != nil {
+		return fmt.Errorf("not in a git repository: %w", err)
+	}
+	hooksDir, err := gitutil.HooksDir(ctx, root)
+	if err != nil {
+		return fmt.Errorf("failed to locate git hooks: %w", err)
+	}
+	manager := strings.ToLower(strings.TrimSpace(hookManager))
+	if manager == "" || manager == "auto" {
+		manager = core.DetectHookManager(root)
+	}
+
+	opts := core.HookInstallOptions{RepoRoot: root, HooksDir: hooksDir, Manager: manager, Hooks: hookNames, Force: hookForce}
+	install := core.InstallGitHooks
+	if hookUninstall {
+		install = core.UninstallGitHooks
+	}
+	changes, err := install(opts)
+	for _, c := range changes {
+		porcelain("hook", c.Hook, manager, c.Action, c.Path)
+		fmt.Printf("%-10s %-10s %s\n", c.Hook, c.Action, c.Path)
+	}
+	if err != nil {
+		return err
+	}
+	if hookUninstall {
+		style.Success.Printf("Removed teamwerx %s hooks.\n", manager)
+		return nil
+	}
+	style.Success.Printf("Installed teamwerx %s hooks.\n", manager)
+	return nil
+}
+
+func runHooksRun(cmd *cobra.Command, args []string) error {
+	hook := args[0]
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+		CharterDir: charterBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	opts := core.ArtifactCheckOptions{SpecSections: requiredSpecSections(app)}
+	switch hook {
+	case "pre-commit":
+		staged, err := gitutil.StagedFiles(context.Background(), ".")
+		if err != nil {
+			return fmt.Errorf("failed to list staged files: %w", err)
+		}
+		set := map[string]bool{}
+		for _, f := range staged {
+			set[absPath(f)] = true
+		}
+		opts.Include = func(path string) bool { return set[absPath(path)] }
+	case "pre-push":
+	default:
+		return fmt.Errorf("unsupported hook %q (want %s)", hook, strings.Join(core.GitHookNames, " or "))
+	}
+
+	problems, err := app.CheckArtifacts(opts)
+	if err != nil {
+		return err
+	}
+	for _, p := range problems {
+		porcelain("problem", p.Kind, p.ID, p.Path, p.Message)
+		style.Error.Printf("FAIL %s %s", p.Kind, p.ID)
+		fmt.Printf(": %s\n", p.Message)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d problem(s) in workspace artifacts; fix them or bypass the hook with --no-verify", len(problems))
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+)
+
+var (
+	initTemplate      string
+	initListTemplates bool
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Set up a workspace, optionally from a template",
+	Long: `Set up a teamwerx workspace in the current directory. Without --template this
+writes the default charter, like 'teamwerx charter init'.
+
+--template pre-populates the charter, spec domains and goals with skeleton plans:
+  web-service  api, auth and operations specs; a service skeleton goal
+  library      api and compatibility specs; a first release goal
+  ml-project   data, training and evaluation specs; a baseline model spike
+
+--template also takes a template directory or a git URL, which is cloned. A
+template holds template.yaml (name, description and goals, each with an id,
+title, goal template and optional tasks), plus an optional charter.md and
+specs/<domain>.md files. Existing charters, specs and goals are never
+overwritten.`,
+	Args: cobra.NoArgs,
+	RunE: runInit,
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+	initCmd.Flags().StringVar(&initTemplate, "template", "", "Built-in template name, template directory or git URL")
+	initCmd.Flags().BoolVar(&initListTemplates, "list-templates", false, "List the built-in templates")
+	initCmd.Flags().StringVar(&specsBaseDir, "specs-dir", ".teamwerx/specs", "Base directory containing spec domains")
+	initCmd.Flags().StringVar(&goalsBaseDir, "goals-dir", ".teamwerx/goals", "Base directory containing goals")
+	initCmd.Flags().StringVar(&changesBaseDir, "changes-dir", ".teamwerx/changes", "Base directory containing changes")
+	initCmd.Flags().StringVar(&charterBaseDir, "charter-dir", ".teamwerx", "Base directory for charter")
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	if initListTemplates {
+		for _, name := range core.WorkspaceTemplates {
+			tmpl, err := core.ResolveWorkspaceTemplate(ctx, name)
+			if err != nil {
+				return err
+			}
+			porcelain("template", tmpl.Name, tmpl.Description)
+			fmt.Printf("%-12s %s\n", tmpl.Name, tmpl.Description)
+		}
+		return nil
+	}
+	if initTemplate == "" {
+		return runCharterInit(cmd, nil)
+	}
+
+	tmpl, err := core.ResolveWorkspaceTemplate(ctx, initTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to load template: %w", err)
+	}
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+		CharterDir: charterBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+	res, err := app.ApplyWorkspaceTemplate(tmpl)
+	if err != nil {
+		return err
+	}
+
+	ok := style.Header
+	ok.Printf("Initialized workspace from the %s template\n", tmpl.Name)
+	if res.Charter != nil {
+		porcelainCharter(res.Charter)
+		fmt.Println("  charter: .teamwerx/charter.md")
+	}
+	for _, s := range res.Specs {
+		porcelainSpec(s)
+		fmt.Printf("  spec:    %s (%d requirement(s))\n", s.Domain, len(s.Requirements))
+	}
+	for _, plan := range res.Goals {
+		porcelainGoal(plan)
+		for _, t := range plan.Tasks {
+			porcelainTask(plan.GoalID, t)
+		}
+		fmt.Printf("  goal:    %s (%d task(s))\n", plan.GoalID, len(plan.Tasks))
+	}
+	if len(res.Goals) > 0 {
+		createdContext[core.LocalGoal] = res.Goals[0].GoalID
+	}
+
+	warn := style.Warn
+	if res.SkippedCharter {
+		warn.Println("Kept the existing charter.")
+	}
+	for _, d := range res.SkippedSpecs {
+		warn.Printf("Kept the existing spec %s.\n", d)
+	}
+	for _, g := range res.SkippedGoals {
+		warn.Printf("Kept the existing goal %s.\n", g)
+	}
+	fmt.Println("\nEdit the charter and specs to fit your project, then run 'teamwerx goal list'.")
+	return nil
+}
@@ -1,13 +1,23 @@
 package main
 
 import (
-	"fmt"
+	"errors"
 	"os"
+	"os/exec"
 )
 
 func main() {
 	if err := Execute(); err != nil {
-		fmt.Println("Error:", err)
+		// Propagate plugin exit codes unchanged; the plugin already reported its error.
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+		if porcelainMode || jsonErrors() {
+			reportError(os.Stderr, err)
+		} else {
+			reportError(os.Stdout, err)
+		}
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+var (
+	changeMilestoneCmd = &cobra.Command{
+		Use:   "milestone",
+		Short: "Group changes into milestones released together",
+		Long: `Assign changes to a named milestone, such as a release, then apply them
+together with 'change apply --milestone <name>' and track how many are done.`,
+	}
+
+	changeMilestoneAssignCmd = &cobra.Command{
+		Use:   "assign <change-id> <milestone>",
+		Short: "Assign a pending change to a milestone",
+		Args:  cobra.ExactArgs(2),
+		RunE:  runChangeMilestoneAssign,
+	}
+
+	changeMilestoneUnassignCmd = &cobra.Command{
+		Use:   "unassign <change-id>",
+		Short: "Remove a pending change from its milestone",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runChangeMilestoneAssign,
+	}
+
+	changeMilestoneListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List milestones and how many of their changes are applied",
+		Args:  cobra.NoArgs,
+		RunE:  runChangeMilestoneList,
+	}
+
+	changeMilestoneShowCmd = &cobra.Command{
+		Use:   "show <milestone>",
+		Short: "Show a milestone's completion and its changes",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runChangeMilestoneShow,
+	}
+)
+
+func init() {
+	changeCmd.AddCommand(changeMilestoneCmd)
+	changeMilestoneCmd.AddCommand(changeMilestoneAssignCmd)
+	changeMilestoneCmd.AddCommand(changeMilestoneUnassignCmd)
+	changeMilestoneCmd.AddCommand(changeMilestoneListCmd)
+	changeMilestoneCmd.AddCommand(changeMilestoneShowCmd)
+}
+
+func porcelainMilestone(m core.MilestoneStatus) {
+	porcelain("milestone", m.Name, strconv.Itoa(m.Applied), strconv.Itoa(m.Total), strings.Join(m.Pending, ","))
+}
+
+// milestoneLine renders a milestone's completion, e.g. "v1.2  3/4 applied (75%)".
+func milestoneLine(m core.MilestoneStatus) string {
+	line := fmt.Sprintf("%-16s %d/%d applied (%d%%)", m.Name, m.Applied, m.Total, m.Percent())
+	if m.Complete() {
+		return line + " " + style.Success.Sprint("complete")
+	}
+	return line
+}
+
+func milestoneApp() (*core.App, error) {
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+		CharterDir: charterBaseDir,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to init app: %w", err)
+	}
+	return app, nil
+}
+
+// runChangeMilestoneAssign serves both assign and unassign, which passes no
+// milestone.
+func runChangeMilestoneAssign(cmd *cobra.Command, args []string) error {
+	app, err := milestoneApp()
+	if err != nil {
+		return err
+	}
+	milestone := ""
+	if len(args) == 2 {
+		if milestone = strings.TrimSpace(args[1]); milestone == "" {
+			return fmt.Errorf("milestone name is required")
+		}
+	}
+	ch, err := app.AssignMilestone(strings.TrimSpace(args[0]), milestone)
+	if err != nil {
+		return fmt.Errorf("failed to update change: %w", err)
+	}
+	porcelainChange(ch)
+	if milestone == "" {
+		style.Success.Printf("Removed change %s from its milestone\n", ch.ID)
+		return nil
+	}
+	style.Success.Printf("Assigned change %s to milestone %s\n", ch.ID, milestone)
+	return nil
+}
+
+func runChangeMilestoneList(cmd *cobra.Command, args []string) error {
+	app, err := milestoneApp()
+	if err != nil {
+		return err
+	}
+	milestones, err := app.Milestones()
+	if err != nil {
+		return fmt.Errorf("failed to list milestones: %w", err)
+	}
+	if len(milestones) == 0 {
+		printLine(style.Warn, "No milestones found. Assign one with 'teamwerx change milestone assign <change-id> <milestone>'.")
+		return nil
+	}
+	for _, m := range milestones {
+		porcelainMilestone(m)
+		fmt.Println(milestoneLine(m))
+	}
+	return nil
+}
+
+func runChangeMilestoneShow(cmd *cobra.Command, args []string) error {
+	app, err := milestoneApp()
+	if err != nil {
+		return err
+	}
+	name := strings.TrimSpace(args[0])
+	m, err := app.Milestone(name)
+	if err != nil {
+		return err
+	}
+	changes, err := app.ChangeManager.ListChangesFiltered(core.ChangeFilter{Milestone: name})
+	if err != nil {
+		return fmt.Errorf("failed to list changes: %w", err)
+	}
+
+	porcelainMilestone(*m)
+	hdr := style.Header
+	hdr.Println(milestoneLine(*m))
+	for _, ch := range changes {
+		porcelainChange(ch)
+		fmt.Printf("- %s [%s] %s\n", ch.ID, style.Status.Sprint(ch.Status), ch.Title)
+	}
+	if applied := m.Total - len(m.Pending) - countApplied(changes); applied > 0 {
+		fmt.Printf("- and %d archived change(s)\n", applied)
+	}
+	if !m.Complete() {
+		fmt.Printf("Run 'teamwerx change apply --milestone %s' to apply the %d pending change(s).\n", m.Name, len(m.Pending))
+	}
+	return nil
+}
+
+// countApplied counts the changes in changes that are no longer pending.
+func countApplied(changes []*model.Change) int {
+	n := 0
+	for _, ch := range changes {
+		if !core.IsPendingChange(ch) {
+			n++
+		}
+	}
+	return n
+}
+
+// applyMilestone applies every pending change of a milestone as
+// 'change apply --all' does, then reports the milestone's completion.
+func applyMilestone(app *core.App, name string) error {
+	if _, err := app.Milestone(name); err != nil {
+		return err
+	}
+	if err := applyAllChanges(app, core.ChangeFilter{Milestone: name}); err != nil {
+		return err
+	}
+	m, err := app.Milestone(name)
+	if err != nil {
+		return err
+	}
+	porcelainMilestone(*m)
+	fmt.Printf("Milestone %s\n", milestoneLine(*m))
+	return nil
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	promptutil "github.com/teamwerx/teamwerx/internal/utils/prompt"
+)
+
+var (
+	onboardDomain string
+	onboardGoal   string
+)
+
+var onboardCmd = &cobra.Command{
+	Use:   "onboard",
+	Short: "Guided tour that sets up a charter, spec, goal and change",
+	Long: `Walk through the teamwerx workflow step by step. Each step explains what it does,
+shows the equivalent command and then runs it against this workspace: creating a charter,
+a spec domain, a goal with tasks, and a sample change proposal that is validated against
+the spec. In non-interactive mode every step runs with its defaults.`,
+	Args: cobra.NoArgs,
+	RunE: runOnboard,
+}
+
+func init() {
+	rootCmd.AddCommand(onboardCmd)
+	onboardCmd.Flags().StringVar(&onboardDomain, "domain", "onboarding", "Spec domain to create")
+	onboardCmd.Flags().StringVar(&onboardGoal, "goal", "001-getting-started", "Goal ID to plan tasks under")
+	onboardCmd.Flags().StringVar(&specsBaseDir, "specs-dir", ".teamwerx/specs", "Base directory containing spec domains")
+	onboardCmd.Flags().StringVar(&goalsBaseDir, "goals-dir", ".teamwerx/goals", "Base directory containing goals")
+	onboardCmd.Flags().StringVar(&changesBaseDir, "changes-dir", ".teamwerx/changes", "Base directory containing changes")
+	onboardCmd.Flags().StringVar(&charterBaseDir, "charter-dir", ".teamwerx", "Base directory for charter")
+}
+
+// onboardStep is one stop of the guided tour.
+type onboardStep struct {
+	title   string
+	explain string
+	command string // equivalent CLI invocation, shown before running
+	run     func() error
+}
+
+func runOnboard(cmd *cobra.Command, args []string) error {
+	domain, err := promptutil.Input("Spec domain to create", onboardDomain)
+	if err != nil {
+		return err
+	}
+	goal, err := promptutil.Input("Goal ID for your first plan", onboardGoal)
+	if err != nil {
+		return err
+	}
+	domain, goal = strings.TrimSpace(domain), strings.TrimSpace(goal)
+	if domain == "" || goal == "" {
+		return fmt.Errorf("domain and goal are required")
+	}
+	sampleID := "CH-" + domain + "-welcome"
+	tasks := []string{"Read the charter", "Review the " + domain + " spec", "Apply the sample change"}
+
+	steps := []onboardStep{
+		{
+			title:   "Create the project charter",
+			explain: "The charter is the steering document for humans and agents: purpose, standards and the sections every spec must have.",
+			command: "teamwerx charter init",
+			run: func() error {
+				return runCharterInit(cmd, nil)
+			},
+		},
+		{
+			title:   "Create a spec domain",
+			explain: "Specs describe what the system must do, one markdown file per domain. Requirements are '### Requirement:' headings.",
+			command: "teamwerx spec create " + domain,
+			run: func() error {
+				app, err := newOnboardApp()
+				if err != nil {
+					return err
+				}
+				if _, err := app.SpecManager.ReadSpec(domain); err == nil {
+					printLine(style.Warn, "Spec %s already exists; keeping it.", domain)
+					return nil
+				} else if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+					return fmt.Errorf("failed to check spec: %w", err)
+				}
+				return runSpecCreate(cmd, []string{domain})
+			},
+		},
+		{
+			title:   "Plan a goal",
+			explain: "A goal groups a plan of tasks and a discussion log. Adding the first task creates the goal.",
+			command: fmt.Sprintf("teamwerx plan add --goal %s %q", goal, tasks[0]),
+			run: func() error {
+				app, err := newOnboardApp()
+				if err != nil {
+					return err
+				}
+				if plan, err := app.PlanManager.Load(goal); err == nil && len(plan.Tasks) > 0 {
+					printLine(style.Warn, "Goal %s already has %d task(s); keeping them.", goal, len(plan.Tasks))
+					return nil
+				}
+				goalID, parentTaskID = goal, ""
+				for _, t := range tasks {
+					if err := runPlanAdd(cmd, []string{t}); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			title:   "Propose a change",
+			explain: "Specs are edited through change proposals: spec deltas that ADD, MODIFY or REMOVE requirements, reviewed before they are applied.",
+			command: fmt.Sprintf("(writes %s/%s/change.json)", changesBaseDir, sampleID),
+			run: func() error {
+				app, err := newOnboardApp()
+				if err != nil {
+					return err
+				}
+				if _, err := app.ChangeManager.ReadChange(sampleID); err == nil {
+					printLine(style.Warn, "Change %s already exists; keeping it.", sampleID)
+					return nil
+				}
+				ch := &model.Change{
+					ID:     sampleID,
+					Title:  "Add a welcome requirement to " + domain,
+					Status: "draft",
+					GoalID: goal,
+					SpecDeltas: []model.SpecDelta{{
+						Domain: domain,
+						Operations: []model.DeltaOperation{{
+							Type: "ADDED",
+							Requirement: model.Requirement{
+								ID:      "welcome-message",
+								Title:   "Welcome Message",
+								Content: app.Options.SpecFormat.RequirementHeading("Welcome Message") + "\n\nThe system SHALL greet new users on first run.\n",
+							},
+						}},
+					}},
+				}
+				if err := app.ChangeManager.Save(ch); err != nil {
+					return fmt.Errorf("failed to save change: %w", err)
+				}
+				porcelainChange(ch)
+				style.Success.Printf("Created change %s: %s\n", ch.ID, ch.Title)
+				return nil
+			},
+		},
+		{
+			title:   "Validate the change",
+			explain: "Validation checks every delta against the current spec before review. Next you would submit, approve and apply it.",
+			command: "teamwerx change validate --id " + sampleID,
+			run: func() error {
+				changeID = sampleID
+				return runChangeValidate(cmd, nil)
+			},
+		},
+	}
+
+	heading := style.Header
+	for i, s := range steps {
+		heading.Printf("\nStep %d/%d: %s\n", i+1, len(steps), s.title)
+		fmt.Println(s.explain)
+		fmt.Printf("  $ %s\n", s.command)
+		proceed, err := promptutil.Confirm("Run this step?", true)
+		if err != nil {
+			return fmt.Errorf("prompt failed: %w", err)
+		}
+		if !proceed {
+			porcelain("step", strconv.Itoa(i+1), "skipped", s.title)
+			printLine(style.Warn, "Skipped.")
+			continue
+		}
+		if err := s.run(); err != nil {
+			return fmt.Errorf("step %d (%s) failed: %w", i+1, s.title, err)
+		}
+		porcelain("step", strconv.Itoa(i+1), "ran", s.title)
+	}
+
+	style.Success.Println("\nOnboarding complete.")
+	fmt.Println("Next steps:")
+	fmt.Printf("  teamwerx plan list --goal %s\n", goal)
+	fmt.Printf("  teamwerx change submit --id %s\n", sampleID)
+	fmt.Printf("  teamwerx change apply --id %s\n", sampleID)
+	return nil
+}
+
+func newOnboardApp() (*core.App, error) {
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+		CharterDir: charterBaseDir,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to init app: %w", err)
+	}
+	return app, nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+	"github.com/teamwerx/teamwerx/internal/utils/opener"
+)
+
+var openPathOnly bool
+
+var openCmd = &cobra.Command{
+	Use:   "open <spec|plan|change|charter> [id]",
+	Short: "Open a spec, plan, change or the charter in $EDITOR",
+	Long: `Resolve the file backing an artifact using the workspace layout and open it
+in $VISUAL or $EDITOR (or the default application when neither is set).
+
+  teamwerx open spec auth
+  teamwerx open plan 001-demo
+  teamwerx open change CH-001
+  teamwerx open charter
+
+With --path the file's path is printed instead, e.g. "$(teamwerx open spec auth --path)".`,
+	Args:      cobra.RangeArgs(1, 2),
+	ValidArgs: core.ArtifactKinds,
+	RunE:      runOpen,
+}
+
+func init() {
+	rootCmd.AddCommand(openCmd)
+	openCmd.Flags().BoolVar(&openPathOnly, "path", false, "Print the artifact's path instead of opening it")
+	openCmd.Flags().StringVar(&specsBaseDir, "specs-dir", ".teamwerx/specs", "Base directory containing spec domains")
+	openCmd.Flags().StringVar(&goalsBaseDir, "goals-dir", ".teamwerx/goals", "Base directory containing goals")
+	openCmd.Flags().StringVar(&changesBaseDir, "changes-dir", ".teamwerx/changes", "Base directory containing changes")
+	openCmd.Flags().StringVar(&charterBaseDir, "charter-dir", ".teamwerx", "Base directory for charter")
+}
+
+func runOpen(cmd *cobra.Command, args []string) error {
+	o, err := core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+		CharterDir: charterBaseDir,
+	}.Resolve()
+	if err != nil {
+		return err
+	}
+
+	kind, id := strings.ToLower(args[0]), ""
+	if len(args) == 2 {
+		id = args[1]
+	}
+	path, err := core.ArtifactPath(o, kind, id)
+	if err != nil {
+		return err
+	}
+
+	porcelain("artifact", kind, id, path)
+	// Porcelain mode never launches an editor: scripts get the path record.
+	if openPathOnly || porcelainMode {
+		fmt.Println(path)
+		return nil
+	}
+	if err := opener.Edit(path); err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	return nil
+}
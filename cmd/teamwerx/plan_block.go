@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+var blockReason string
+
+var planBlockCmd = &cobra.Command{
+	Use:   "block",
+	Short: "Mark a plan task as blocked, with the reason",
+	Long: `Mark an open task as blocked. The reason, who blocked it and when are kept
+on the task, and blocked tasks are listed first by 'goal status' and
+'report' until 'plan unblock' restores the status the task had before.`,
+	Args: cobra.NoArgs,
+	RunE: runPlanBlock,
+}
+
+var planUnblockCmd = &cobra.Command{
+	Use:   "unblock",
+	Short: "Unblock a blocked plan task",
+	Args:  cobra.NoArgs,
+	RunE:  runPlanUnblock,
+}
+
+func init() {
+	planCmd.AddCommand(planBlockCmd)
+	planCmd.AddCommand(planUnblockCmd)
+	for _, c := range []*cobra.Command{planBlockCmd, planUnblockCmd} {
+		c.Flags().StringVar(&goalID, "goal", "", "Goal ID")
+		c.Flags().StringVar(&taskID, "task", "", "Task ID (e.g., T03)")
+		c.Flags().StringVar(&authorName, "by", defaultAuthor(), "Who changed the task")
+		_ = c.MarkFlagRequired("goal")
+		_ = c.MarkFlagRequired("task")
+	}
+	planBlockCmd.Flags().StringVar(&blockReason, "reason", "", "What the task is waiting on")
+	_ = planBlockCmd.MarkFlagRequired("reason")
+}
+
+func runPlanBlock(cmd *cobra.Command, args []string) error {
+	return updateTaskBlock(func(pm core.PlanManager, plan *model.Plan) error {
+		return pm.BlockTask(plan, taskID, blockReason, authorName)
+	}, "Blocked")
+}
+
+func runPlanUnblock(cmd *cobra.Command, args []string) error {
+	return updateTaskBlock(func(pm core.PlanManager, plan *model.Plan) error {
+		return pm.UnblockTask(plan, taskID, authorName)
+	}, "Unblocked")
+}
+
+// updateTaskBlock loads the goal's plan, blocks or unblocks the task with
+// update, and saves the plan.
+func updateTaskBlock(update func(core.PlanManager, *model.Plan) error, verb string) error {
+	app, err := newApp(core.AppOptions{GoalsDir: goalsBaseDir})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+	plan, err := app.PlanManager.Load(goalID)
+	if err != nil {
+		return fmt.Errorf("failed to load plan: %w", err)
+	}
+	if err := update(app.PlanManager, plan); err != nil {
+		if _, ok := err.(*custom_errors.ErrNotFound); ok {
+			return fmt.Errorf("task %s not found in goal %s", taskID, goalID)
+		}
+		return err
+	}
+	if err := app.PlanManager.Save(plan); err != nil {
+		return fmt.Errorf("failed to save plan: %w", err)
+	}
+
+	t := findPlanTask(plan, taskID)
+	porcelainTask(goalID, *t)
+	porcelainBlock(goalID, *t)
+	style.Success.Printf("%s task %s of goal %s (now %s)\n", verb, t.ID, goalID, t.Status)
+	return nil
+}
+
+// porcelainBlock reports the open block of a blocked task.
+func porcelainBlock(goal string, t model.Task) {
+	if b := core.OpenBlock(t); b != nil {
+		porcelain("block", goal, t.ID, b.Reason, porcelainTime(b.BlockedAt), b.BlockedBy)
+	}
+}
+
+// printBlockedTasks lists the blocked tasks of plan with what they wait on.
+func printBlockedTasks(plan *model.Plan, indent string) {
+	for _, t := range plan.Tasks {
+		if t.Status != core.TaskStatusBlocked {
+			continue
+		}
+		porcelainBlock(plan.GoalID, t)
+		line := fmt.Sprintf("%s%s %s", indent, t.ID, t.Title)
+		if b := core.OpenBlock(t); b != nil {
+			line += fmt.Sprintf(": %s (since %s", b.Reason, displayTime(b.BlockedAt, time.RFC3339))
+			if b.BlockedBy != "" {
+				line += ", by " + b.BlockedBy
+			}
+			line += ")"
+		}
+		printLine(style.Warn, strings.TrimRight(line, " "))
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+)
+
+var planConvertTo string
+
+var planConvertCmd = &cobra.Command{
+	Use:   "convert",
+	Short: "Store every goal's plan as JSON, YAML or a markdown checklist",
+	Long: `Rewrite each goal's plan in another format and point layout.plan_file
+in config.yaml at the new files. The conversion is lossless in every
+direction, so it can be undone by converting back.
+
+  json  plan.json, the default
+  yaml  plan.yaml
+  md    plan.md, a checklist people can edit by hand: ticking a box
+        completes the task, and fields the checklist cannot show are kept
+        in an HTML comment at the end of the item`,
+	Args: cobra.NoArgs,
+	RunE: runPlanConvert,
+}
+
+func init() {
+	planCmd.AddCommand(planConvertCmd)
+	planConvertCmd.Flags().StringVar(&planConvertTo, "to", "", "Target format: "+strings.Join(core.PlanFormats, ", "))
+	_ = planConvertCmd.MarkFlagRequired("to")
+	planConvertCmd.Flags().StringVar(&charterBaseDir, "charter-dir", ".teamwerx", "Base directory for charter and config")
+}
+
+func runPlanConvert(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{GoalsDir: goalsBaseDir, CharterDir: charterBaseDir})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+	planFile, converted, err := app.ConvertPlans(strings.ToLower(strings.TrimSpace(planConvertTo)))
+	for _, c := range converted {
+		porcelain("converted", c.Goal, c.From, c.To)
+		fmt.Printf("%s: %s -> %s\n", c.Goal, c.From, c.To)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to convert plans: %w", err)
+	}
+	style.Success.Printf("Converted %d plan(s); layout.plan_file is now %q.\n", len(converted), planFile)
+	return nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+)
+
+var (
+	taskLabels       []string
+	taskRemoveLabels []string
+	taskFields       []string
+)
+
+var planEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Set a plan task's labels and custom fields",
+	Long: `Add or remove a task's labels and set its custom fields. Labels and fields
+are the team's own: teamwerx stores them with the task, keeps them in every
+plan format and export, and filters on them in 'plan list' and 'ask tasks'.
+An empty value clears a field.
+
+  teamwerx plan edit --goal 001-auth --task T03 --label backend --field sprint=12
+  teamwerx plan edit --goal 001-auth --task T03 --remove-label backend --field sprint=`,
+	Args: cobra.NoArgs,
+	RunE: runPlanEdit,
+}
+
+func init() {
+	planCmd.AddCommand(planEditCmd)
+	planEditCmd.Flags().StringVar(&goalID, "goal", "", "Goal ID")
+	planEditCmd.Flags().StringVar(&taskID, "task", "", "Task ID (e.g., T03)")
+	_ = planEditCmd.MarkFlagRequired("goal")
+	_ = planEditCmd.MarkFlagRequired("task")
+	planEditCmd.Flags().StringArrayVar(&taskLabels, "label", nil, "Label to add (repeatable)")
+	planEditCmd.Flags().StringArrayVar(&taskRemoveLabels, "remove-label", nil, "Label to remove (repeatable)")
+	planEditCmd.Flags().StringArrayVar(&taskFields, "field", nil, "Custom field as key=value; key= clears it (repeatable)")
+
+	planListCmd.Flags().StringArrayVar(&taskLabels, "label", nil, "Only tasks with this label (repeatable; all must match)")
+	planListCmd.Flags().StringArrayVar(&taskFields, "field", nil, "Only tasks with this field value, as key=value (repeatable; all must match)")
+}
+
+func runPlanEdit(cmd *cobra.Command, args []string) error {
+	if len(taskLabels)+len(taskRemoveLabels)+len(taskFields) == 0 {
+		return fmt.Errorf("nothing to edit: give --label, --remove-label or --field")
+	}
+	fields, err := core.ParseTaskFields(taskFields)
+	if err != nil {
+		return err
+	}
+	app, err := newApp(core.AppOptions{GoalsDir: goalsBaseDir})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+	plan, err := app.PlanManager.Load(goalID)
+	if err != nil {
+		return fmt.Errorf("failed to load plan: %w", err)
+	}
+	task, err := core.EditTask(plan, taskID, core.TaskEdit{AddLabels: taskLabels, RemoveLabels: taskRemoveLabels, SetFields: fields})
+	if err != nil {
+		if _, ok := err.(*custom_errors.ErrNotFound); ok {
+			return fmt.Errorf("task %s not found in goal %s", taskID, goalID)
+		}
+		return err
+	}
+	if err := app.PlanManager.Save(plan); err != nil {
+		return fmt.Errorf("failed to save plan: %w", err)
+	}
+
+	porcelainTask(goalID, *task)
+	style.Success.Printf("Updated task %s of goal %s\n", task.ID, goalID)
+	if len(task.Labels) > 0 {
+		fmt.Printf("  Labels: %s\n", strings.Join(task.Labels, ", "))
+	}
+	if len(task.Fields) > 0 {
+		fmt.Printf("  Fields: %s\n", core.FormatTaskFields(task.Fields, ", "))
+	}
+	return nil
+}
+
+// taskListFilter is the --label and --field filter of 'plan list'.
+func taskListFilter() (core.TaskFilter, error) {
+	fields, err := core.ParseTaskFields(taskFields)
+	if err != nil {
+		return core.TaskFilter{}, err
+	}
+	return core.TaskFilter{Labels: taskLabels, Fields: fields}, nil
+}
+
+// taskAttributes renders a task's labels and fields for listings, e.g.
+// " #backend sprint=12", or "" when it has none.
+func taskAttributes(labels []string, fields map[string]string) string {
+	var parts []string
+	for _, l := range labels {
+		parts = append(parts, "#"+l)
+	}
+	if len(fields) > 0 {
+		parts = append(parts, core.FormatTaskFields(fields, " "))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " " + style.Muted.Sprint(strings.Join(parts, " "))
+}
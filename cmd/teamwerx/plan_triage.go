@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+	"github.com/teamwerx/teamwerx/internal/model"
+	promptutil "github.com/teamwerx/teamwerx/internal/utils/prompt"
+)
+
+var triageReport bool
+
+var planTriageCmd = &cobra.Command{
+	Use:   "triage",
+	Short: "Review a goal's open tasks one at a time",
+	Long: `Walk through the goal's open tasks in plan order and choose an action for
+each:
+
+  complete  mark the task completed
+  skip      leave it as it is
+  edit      change its title
+  assign    set its assignee
+  defer     move it (with its subtasks) to the end of the plan
+  quit      stop; earlier actions are kept
+
+Each task is shown with notes on what may need attention: blocked,
+unassigned, not linked to a requirement, or all subtasks done. The plan is
+saved after every action.
+
+With --report, or when not running on a terminal, only the tasks and their
+notes are printed and nothing is changed.`,
+	Args: cobra.NoArgs,
+	RunE: runPlanTriage,
+}
+
+func init() {
+	planCmd.AddCommand(planTriageCmd)
+	planTriageCmd.Flags().StringVar(&goalID, "goal", "", "Goal ID to triage")
+	_ = planTriageCmd.MarkFlagRequired("goal")
+	planTriageCmd.Flags().BoolVar(&triageReport, "report", false, "Print the open tasks and their notes without prompting")
+}
+
+func runPlanTriage(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{GoalsDir: goalsBaseDir})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+	plan, err := app.PlanManager.Load(goalID)
+	if err != nil {
+		return fmt.Errorf("failed to load plan: %w", err)
+	}
+
+	items := core.TriagePlan(plan)
+	if triageReport || porcelainMode || !promptutil.IsInteractive() {
+		for _, it := range items {
+			porcelain("triage", goalID, it.Task.ID, taskStatus(it.Task), it.Task.Assignee, strings.Join(it.Notes, ","), it.Task.Title)
+		}
+		if len(items) == 0 {
+			printLine(style.Success, "No open tasks in goal %s.", goalID)
+			return nil
+		}
+		style.Header.Printf("%d open task(s) in goal %s:\n", len(items), goalID)
+		for _, it := range items {
+			printTriageItem(it)
+		}
+		return nil
+	}
+	if len(items) == 0 {
+		printLine(style.Success, "No open tasks in goal %s.", goalID)
+		return nil
+	}
+
+	// The items are a snapshot, so a deferred task is not offered again in
+	// this session.
+	counts := map[string]int{}
+	choices := append(append([]string{}, core.TriageActions...), "quit")
+	for i, it := range items {
+		style.Header.Printf("\nTask %d/%d\n", i+1, len(items))
+		printTriageItem(it)
+		_, action, err := promptutil.Select("Action", choices, 1)
+		if err != nil {
+			return fmt.Errorf("prompt failed: %w", err)
+		}
+		if action == "quit" {
+			break
+		}
+		if err := applyTriageAction(app, plan, it.Task, action); err != nil {
+			printLine(style.Error, "  %v", err)
+			continue
+		}
+		counts[action]++
+	}
+
+	var parts []string
+	for _, a := range core.TriageActions {
+		if n := counts[a]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, a))
+		}
+	}
+	if len(parts) == 0 {
+		fmt.Println("\nNo changes.")
+		return nil
+	}
+	style.Success.Printf("\nTriage done: %s.\n", strings.Join(parts, ", "))
+	return nil
+}
+
+// applyTriageAction performs action on task, prompting for any input it
+// needs, and saves the plan when it changed.
+func applyTriageAction(app *core.App, plan *model.Plan, task model.Task, action string) error {
+	switch action {
+	case core.TriageSkip:
+		return nil
+	case core.TriageComplete:
+		if err := app.PlanManager.CompleteTask(plan, task.ID, false); err != nil {
+			return err
+		}
+		if t := findPlanTask(plan, task.ID); t != nil {
+			t.CompletedBy = defaultAuthor()
+		}
+	case core.TriageEdit:
+		title, err := promptutil.Input("Title", task.Title)
+		if err != nil {
+			return fmt.Errorf("prompt failed: %w", err)
+		}
+		if title = strings.TrimSpace(title); title == "" || title == task.Title {
+			return nil
+		}
+		findPlanTask(plan, task.ID).Title = title
+	case core.TriageAssign:
+		assignee, err := promptutil.Input("Assignee", task.Assignee)
+		if err != nil {
+			return fmt.Errorf("prompt failed: %w", err)
+		}
+		findPlanTask(plan, task.ID).Assignee = strings.TrimSpace(assignee)
+	case core.TriageDefer:
+		if err := core.DeferTask(plan, task.ID); err != nil {
+			return err
+		}
+	}
+	if err := app.PlanManager.Save(plan); err != nil {
+		return fmt.Errorf("failed to save plan: %w", err)
+	}
+	if t := findPlanTask(plan, task.ID); t != nil {
+		porcelainTask(goalID, *t)
+	}
+	return nil
+}
+
+func printTriageItem(it core.TriageItem) {
+	t := it.Task
+	fmt.Printf("  %s [%s] %s", t.ID, style.Status.Sprint(taskStatus(t)), t.Title)
+	if t.Assignee != "" {
+		fmt.Printf(" (%s)", t.Assignee)
+	}
+	fmt.Println()
+	if len(it.Notes) > 0 {
+		style.Warn.Printf("    %s\n", strings.Join(it.Notes, "; "))
+	}
+}
+
+// taskStatus returns the task's status, "pending" when unset.
+func taskStatus(t model.Task) string {
+	if strings.TrimSpace(t.Status) == "" {
+		return core.TaskStatusPending
+	}
+	return t.Status
+}
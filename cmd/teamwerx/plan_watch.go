@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+var watchInterval time.Duration
+
+// watchRecentEntries is how many new discussion entries stay on screen
+// below the task table when redrawing.
+const watchRecentEntries = 10
+
+var planWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Follow a goal's tasks and new discussion entries as they change",
+	Long: `Show the goal's task table, then check plan.json and discuss.md for
+changes every --interval and re-render them, e.g. while an agent works
+through the plan in another terminal. Discussion entries added while
+watching are listed under the table. On a terminal the screen is redrawn in
+place; otherwise each update is appended. Press Ctrl+C to stop.`,
+	Args: cobra.NoArgs,
+	RunE: runPlanWatch,
+}
+
+func init() {
+	planCmd.AddCommand(planWatchCmd)
+	planWatchCmd.Flags().StringVar(&goalID, "goal", "", "Goal ID to watch")
+	_ = planWatchCmd.MarkFlagRequired("goal")
+	planWatchCmd.Flags().DurationVar(&watchInterval, "interval", time.Second, "How often to check for changes")
+	planWatchCmd.Flags().BoolVar(&showTree, "tree", false, "Render subtasks as a tree")
+}
+
+func runPlanWatch(cmd *cobra.Command, args []string) error {
+	if watchInterval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+	app, err := newApp(core.AppOptions{GoalsDir: goalsBaseDir})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	watcher := app.WatchGoal(goalID)
+	update, err := watcher.Poll()
+	if err != nil {
+		return fmt.Errorf("failed to load goal: %w", err)
+	}
+	plan := update.Plan
+	redraw := !porcelainMode && (isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd()))
+	var recent []model.DiscussionEntry
+
+	render := func(u *core.GoalUpdate) {
+		if !redraw {
+			// Append only what changed.
+			if u.Plan != nil {
+				printPlan(goalID, u.Plan, showTree)
+			}
+			if len(u.Entries) > 0 {
+				style.Header.Println("New discussion entries:")
+				for _, e := range u.Entries {
+					printEntrySummary(app, goalID, e)
+				}
+			}
+			fmt.Println()
+			return
+		}
+		fmt.Print("\033[H\033[2J")
+		printPlan(goalID, plan, showTree)
+		if len(recent) > 0 {
+			fmt.Println()
+			style.Header.Println("New discussion entries:")
+			for _, e := range recent {
+				printEntrySummary(app, goalID, e)
+			}
+		}
+		fmt.Println()
+		style.Muted.Printf("Watching %s every %s. Press Ctrl+C to stop.\n", goalID, watchInterval)
+	}
+	render(update)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	defer signal.Stop(stop)
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	lastErr := ""
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+		update, err := watcher.Poll()
+		if err != nil {
+			// Report each new problem once and keep watching: the file may
+			// be mid-edit, or the goal may come back.
+			if err.Error() != lastErr {
+				lastErr = err.Error()
+				printLine(style.Warn, "Warning: %v", err)
+			}
+			continue
+		}
+		lastErr = ""
+		if update == nil {
+			continue
+		}
+		if update.Plan != nil {
+			plan = update.Plan
+		}
+		recent = append(recent, update.Entries...)
+		if len(recent) > watchRecentEntries {
+			recent = recent[len(recent)-watchRecentEntries:]
+		}
+		render(update)
+	}
+}
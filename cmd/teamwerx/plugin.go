@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+	"github.com/teamwerx/teamwerx/internal/plugin"
+)
+
+var (
+	pluginCmd = &cobra.Command{
+		Use:   "plugin",
+		Short: "Work with external plugins",
+		Long: "Plugins are executables named teamwerx-<name> on PATH. Running 'teamwerx <name>' " +
+			"dispatches to them with workspace context exported via TEAMWERX_* environment variables.",
+	}
+
+	pluginListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List plugins discovered on PATH",
+		RunE:  runPluginList,
+	}
+)
+
+func init() {
+	// Attach plugin hierarchy: root -> plugin -> list
+	rootCmd.AddCommand(pluginCmd)
+	pluginCmd.AddCommand(pluginListCmd)
+}
+
+func runPluginList(cmd *cobra.Command, args []string) error {
+	plugins := plugin.Discover()
+	if len(plugins) == 0 {
+		printLine(style.Warn, "No plugins found on PATH (looking for %s* executables).", plugin.Prefix)
+		return nil
+	}
+
+	ok := style.Header
+	ok.Printf("Found %d plugin(s):\n", len(plugins))
+	for _, p := range plugins {
+		porcelain("plugin", p.Name, p.Path)
+		fmt.Printf("- %s ", p.Name)
+		style.Muted.Printf("(%s)\n", p.Path)
+	}
+	return nil
+}
+
+// dispatchPlugin runs an external plugin when the first argument is not a
+// built-in command but a matching teamwerx-<name> executable exists on PATH.
+// It reports whether the arguments were handled by a plugin.
+func dispatchPlugin(args []string) (bool, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return false, nil
+	}
+	if _, _, err := rootCmd.Find(args); err == nil {
+		return false, nil
+	}
+	p, err := plugin.Find(args[0])
+	if err != nil {
+		// Let cobra report the unknown command as usual.
+		return false, nil
+	}
+	return true, p.Run(pluginContext(), args[1:])
+}
+
+// pluginContext describes the default workspace layout using absolute paths so
+// plugins can change directories freely.
+func pluginContext() plugin.Context {
+	o := core.DefaultAppOptions()
+	return plugin.Context{
+		Workspace:  absPath(o.CharterDir),
+		SpecsDir:   absPath(o.SpecsDir),
+		GoalsDir:   absPath(o.GoalsDir),
+		ChangesDir: absPath(o.ChangesDir),
+		CharterDir: absPath(o.CharterDir),
+	}
+}
+
+func absPath(p string) string {
+	if abs, err := filepath.Abs(p); err == nil {
+		return abs
+	}
+	return p
+}
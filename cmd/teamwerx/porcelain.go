@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// porcelainMode is set by the global --porcelain flag. In porcelain mode the
+// human-readable output of every command is discarded and commands instead
+// write the tab-separated records documented in docs/porcelain.md.
+var porcelainMode bool
+
+// stdout receives porcelain records and raw data (exports, completion
+// scripts, JSON). It is the real standard output even while human output is
+// being discarded.
+var stdout io.Writer = os.Stdout
+
+var realStdout *os.File
+
+// beginPorcelain discards human output: fmt.Print* to os.Stdout and all
+// colored output. Errors still go to standard error.
+func beginPorcelain() error {
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	realStdout = os.Stdout
+	stdout = realStdout
+	os.Stdout = devNull
+	color.NoColor = true
+	color.Output = io.Discard
+	return nil
+}
+
+// endPorcelain restores standard output after the command has run.
+func endPorcelain() {
+	if realStdout == nil {
+		return
+	}
+	_ = os.Stdout.Close()
+	os.Stdout = realStdout
+	realStdout = nil
+}
+
+// porcelain writes one record: the record type followed by its fields,
+// separated by tabs. It does nothing outside porcelain mode.
+func porcelain(record string, fields ...string) {
+	if !porcelainMode {
+		return
+	}
+	var b strings.Builder
+	b.WriteString(record)
+	for _, f := range fields {
+		b.WriteByte('\t')
+		b.WriteString(porcelainEscape(f))
+	}
+	b.WriteByte('\n')
+	_, _ = io.WriteString(stdout, b.String())
+}
+
+var porcelainEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// porcelainEscape keeps every record on one line: backslash, tab, newline and
+// carriage return are written as \\, \t, \n and \r.
+func porcelainEscape(s string) string {
+	return porcelainEscaper.Replace(s)
+}
+
+// porcelainTime formats t as RFC 3339 in UTC, or "" for the zero time.
+func porcelainTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func porcelainSpec(spec *model.Spec) {
+	porcelain("spec", spec.Domain, spec.Fingerprint, strconv.Itoa(len(spec.Requirements)))
+}
+
+func porcelainSpecMeta(spec *model.Spec) {
+	porcelain("meta", spec.Domain, spec.Meta.Owner, spec.Meta.Status, strings.Join(spec.Meta.Tags, ","), spec.Meta.Version)
+}
+
+func porcelainRequirement(domain string, r model.Requirement) {
+	porcelain("requirement", domain, r.ID, strconv.Itoa(len(r.Scenarios)), r.Title, r.Source, r.Priority)
+}
+
+func porcelainCriterion(domain, reqID string, c model.AcceptanceCriterion) {
+	state := "open"
+	if c.Done {
+		state = "done"
+	}
+	porcelain("criterion", domain, reqID, strconv.Itoa(c.Index), state, c.Text)
+}
+
+// porcelainValidation reports a spec's required-section check: "ok", or
+// "fail" with the missing sections.
+func porcelainValidation(domain string, missing []string, markers bool) {
+	result := "ok"
+	if len(missing) > 0 || markers {
+		result = "fail"
+	}
+	porcelain("validation", domain, result, strings.Join(missing, ","), strconv.FormatBool(markers))
+}
+
+func porcelainTask(goal string, t model.Task) {
+	status := t.Status
+	if strings.TrimSpace(status) == "" {
+		status = "pending"
+	}
+	porcelain("task", goal, t.ID, status, t.ParentID, t.Assignee, strings.Join(t.Requirements, ","), t.Title, t.WaiverReason)
+	if len(t.Labels) > 0 || len(t.Fields) > 0 {
+		fields := []string{goal, t.ID, strings.Join(t.Labels, ",")}
+		keys := make([]string, 0, len(t.Fields))
+		for k := range t.Fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fields = append(fields, k+"="+t.Fields[k])
+		}
+		porcelain("task-attributes", fields...)
+	}
+}
+
+func porcelainChange(ch *model.Change) {
+	porcelain("change", ch.ID, ch.Status, ch.GoalID, porcelainTime(ch.CreatedAt), strconv.Itoa(len(ch.SpecDeltas)), ch.Title, ch.Milestone)
+}
+
+func porcelainEntry(goal string, e model.DiscussionEntry) {
+	porcelain("entry", goal, e.ID, e.Type, porcelainTime(e.Timestamp), e.TaskID, e.Author, e.Content, strings.Join(e.Refs, ","))
+}
+
+func porcelainAttachment(goal, entryID string, a model.Attachment) {
+	porcelain("attachment", goal, entryID, a.Path, strconv.FormatInt(a.Size, 10), a.Name)
+}
+
+func porcelainGoal(plan *model.Plan) {
+	status, completedAt := plan.Status, ""
+	if status == "" {
+		status = "active"
+	}
+	if plan.CompletedAt != nil {
+		completedAt = porcelainTime(*plan.CompletedAt)
+	}
+	p := plan.Progress
+	porcelain("goal", plan.GoalID, status,
+		strconv.Itoa(p.Total), strconv.Itoa(p.Completed), strconv.Itoa(p.Waived),
+		strconv.Itoa(p.InProgress), strconv.Itoa(p.Blocked), strconv.Itoa(p.Pending), completedAt)
+}
+
+// porcelainCharter writes the charter record followed by one record per tech
+// stack entry, convention (sorted by key) and required spec section, and the
+// markdown body.
+func porcelainCharter(c *model.Charter) {
+	porcelain("charter", c.Title, c.Version, porcelainTime(c.Created), porcelainTime(c.Updated), c.Purpose)
+	for _, tech := range c.TechStack {
+		porcelain("tech", tech)
+	}
+	keys := make([]string, 0, len(c.Conventions))
+	for k := range c.Conventions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		porcelain("convention", k, fmt.Sprint(c.Conventions[k]))
+	}
+	for _, section := range c.SpecSections {
+		porcelain("section", section)
+	}
+	if c.Content != "" {
+		porcelain("content", c.Content)
+	}
+}
+
+func porcelainComment(domain string, c model.ReviewComment) {
+	porcelain("comment", domain, c.ID, c.RequirementID, c.Status, c.Author, c.Body)
+}
+
+func porcelainChangeComment(changeID string, c model.DiscussionEntry) {
+	porcelain("change-comment", changeID, c.ID, porcelainTime(c.Timestamp), c.Author, c.Content)
+}
+
+// porcelainFile reports a file or directory a command wrote, moved or removed.
+func porcelainFile(action, path string) {
+	porcelain("file", action, path)
+}
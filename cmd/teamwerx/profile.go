@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+var (
+	cpuProfilePath string
+	memProfilePath string
+	cpuProfileFile *os.File
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&cpuProfilePath, "cpuprofile", "", "Write a pprof CPU profile of the command to this file")
+	rootCmd.PersistentFlags().StringVar(&memProfilePath, "memprofile", "", "Write a pprof heap profile to this file when the command exits")
+	_ = rootCmd.PersistentFlags().MarkHidden("cpuprofile")
+	_ = rootCmd.PersistentFlags().MarkHidden("memprofile")
+}
+
+// startProfiling begins CPU profiling when --cpuprofile is set.
+func startProfiling() error {
+	if cpuProfilePath == "" {
+		return nil
+	}
+	f, err := os.Create(cpuProfilePath)
+	if err != nil {
+		return fmt.Errorf("failed to create CPU profile: %w", err)
+	}
+	if err := pprof.StartCPUProfile(f); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to start CPU profile: %w", err)
+	}
+	cpuProfileFile = f
+	return nil
+}
+
+// stopProfiling finishes the CPU profile and writes the heap profile, if
+// either was requested. Failures are reported but do not change the exit
+// status of the command being profiled.
+func stopProfiling() {
+	if cpuProfileFile != nil {
+		pprof.StopCPUProfile()
+		cpuProfileFile.Close()
+		cpuProfileFile = nil
+	}
+	if memProfilePath == "" {
+		return
+	}
+	f, err := os.Create(memProfilePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create memory profile: %v\n", err)
+		return
+	}
+	defer f.Close()
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write memory profile: %v\n", err)
+	}
+}
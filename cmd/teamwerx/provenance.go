@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// Provenance flags of 'change create'. Each defaults to an environment
+// variable so an agent sets them once for every change it proposes.
+var (
+	provenanceTool         string
+	provenanceModel        string
+	provenanceConversation string
+	provenanceCommit       string
+	filterCreatedBy        string
+)
+
+func init() {
+	f := changeCreateCmd.Flags()
+	f.StringVar(&provenanceTool, "tool", os.Getenv("TEAMWERX_TOOL"), "Agent or tool generating the change (default $TEAMWERX_TOOL)")
+	f.StringVar(&provenanceModel, "model", os.Getenv("TEAMWERX_MODEL"), "Model behind the tool (default $TEAMWERX_MODEL)")
+	f.StringVar(&provenanceConversation, "conversation", os.Getenv("TEAMWERX_CONVERSATION"), "ID or URL of the source conversation (default $TEAMWERX_CONVERSATION)")
+	f.StringVar(&provenanceCommit, "commit", os.Getenv("TEAMWERX_COMMIT"), "Commit the change was generated from (default $TEAMWERX_COMMIT)")
+	changeListCmd.Flags().StringVar(&filterCreatedBy, "created-by", "", "Only list changes created by this author or agent")
+}
+
+// changeProvenance returns the provenance given by the flags, or nil when
+// none is set.
+func changeProvenance() *model.Provenance {
+	p := model.Provenance{
+		Tool:         strings.TrimSpace(provenanceTool),
+		Model:        strings.TrimSpace(provenanceModel),
+		Conversation: strings.TrimSpace(provenanceConversation),
+		Commit:       strings.TrimSpace(provenanceCommit),
+	}
+	if p == (model.Provenance{}) {
+		return nil
+	}
+	return &p
+}
+
+// printProvenance prints who and what created ch, indented under its header.
+func printProvenance(ch *model.Change) {
+	p := ch.Provenance
+	if p == nil {
+		p = &model.Provenance{}
+	}
+	if ch.Author != "" || p.Tool != "" || p.Model != "" || p.Conversation != "" || p.Commit != "" {
+		porcelain("provenance", ch.ID, ch.Author, p.Tool, p.Model, p.Conversation, p.Commit)
+	}
+	if ch.Author != "" {
+		fmt.Printf("  Created by: %s\n", ch.Author)
+	}
+	if p.Tool != "" {
+		tool := p.Tool
+		if p.Model != "" {
+			tool += " (" + p.Model + ")"
+		}
+		fmt.Printf("  Tool: %s\n", tool)
+	} else if p.Model != "" {
+		fmt.Printf("  Model: %s\n", p.Model)
+	}
+	if p.Conversation != "" {
+		fmt.Printf("  Conversation: %s\n", p.Conversation)
+	}
+	if p.Commit != "" {
+		fmt.Printf("  Commit: %s\n", p.Commit)
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// readOnlyFlag is --read-only: open the workspace without ever writing to it.
+var readOnlyFlag bool
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&readOnlyFlag, "read-only", false, "Refuse every change to the workspace, for review and CI jobs (see also TEAMWERX_READ_ONLY)")
+}
+
+// readOnlyMode reports whether --read-only or a true TEAMWERX_READ_ONLY asks
+// for a read-only workspace. "read_only: true" in config.yaml locks it on
+// regardless; core applies that when the App is opened.
+func readOnlyMode() bool {
+	if readOnlyFlag {
+		return true
+	}
+	on, _ := strconv.ParseBool(strings.TrimSpace(os.Getenv("TEAMWERX_READ_ONLY")))
+	return on
+}
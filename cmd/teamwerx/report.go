@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+)
+
+var (
+	reportPriority string
+	reportJSON     bool
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Roll requirement priorities up per domain with planned and done counts",
+	Long: `Count each domain's requirements by priority, the value of a
+"Priority: must|should|could" line in the requirement:
+
+  ### Requirement: Login
+  Priority: must
+  Users log in with email and password.
+
+A requirement is planned when a plan task links to it and done when every
+task linking to it is completed or waived, so the must row shows how much of
+the MVP is scoped and finished. Blocked tasks are listed below the table with
+what they wait on.`,
+	Args: cobra.NoArgs,
+	RunE: runReport,
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.Flags().StringVar(&reportPriority, "priority", "", "Only count requirements of this priority: must, should, could or none")
+	reportCmd.Flags().BoolVar(&reportJSON, "json", false, "Print the report as JSON")
+	reportCmd.Flags().StringVar(&specsBaseDir, "specs-dir", ".teamwerx/specs", "Base directory containing spec domains")
+	reportCmd.Flags().StringVar(&goalsBaseDir, "goals-dir", ".teamwerx/goals", "Base directory containing goals")
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{SpecsDir: specsBaseDir, GoalsDir: goalsBaseDir})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+	report, err := app.BuildPriorityReport(strings.ToLower(strings.TrimSpace(reportPriority)))
+	if err != nil {
+		return fmt.Errorf("failed to build report: %w", err)
+	}
+	for _, d := range report.Domains {
+		for _, p := range d.Priorities {
+			porcelain("priority", d.Domain, priorityLabel(p.Priority), strconv.Itoa(p.Total), strconv.Itoa(p.Planned), strconv.Itoa(p.Done))
+		}
+	}
+	if reportJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, string(data))
+		return nil
+	}
+	if len(report.Domains) == 0 {
+		printLine(style.Warn, "No requirements found.")
+		printReportBlocked(report.Blocked)
+		return nil
+	}
+
+	bold := style.Header
+	bold.Printf("%-24s %-8s %6s %8s %6s\n", "DOMAIN", "PRIORITY", "TOTAL", "PLANNED", "DONE")
+	for _, d := range report.Domains {
+		for i, p := range d.Priorities {
+			domain := d.Domain
+			if i > 0 {
+				domain = ""
+			}
+			fmt.Printf("%-24s %-8s %6d %8d %6d\n", domain, priorityLabel(p.Priority), p.Total, p.Planned, p.Done)
+		}
+	}
+	printReportBlocked(report.Blocked)
+	fmt.Println()
+	for _, p := range report.Total {
+		line := fmt.Sprintf("%-8s %d/%d done, %d planned", priorityLabel(p.Priority)+":", p.Done, p.Total, p.Planned)
+		if p.Priority == core.PriorityMust && p.Done < p.Total {
+			printLine(style.Warn, line)
+			continue
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// printReportBlocked lists the blocked tasks with what they wait on and the
+// requirements they hold up.
+func printReportBlocked(blocked []core.BlockedTask) {
+	if len(blocked) == 0 {
+		return
+	}
+	fmt.Println()
+	printLine(style.Warn, "Blocked tasks (%d):", len(blocked))
+	for _, b := range blocked {
+		porcelainBlock(b.GoalID, b.Task)
+		line := fmt.Sprintf("  %s %s %s", b.GoalID, b.Task.ID, b.Task.Title)
+		if b.Block != nil {
+			line += ": " + b.Block.Reason
+		}
+		if len(b.Task.Requirements) > 0 {
+			line += " [" + strings.Join(b.Task.Requirements, ", ") + "]"
+		}
+		printLine(style.Warn, line)
+	}
+}
+
+// priorityLabel names a priority for display, "none" when unset.
+func priorityLabel(p string) string {
+	if p == "" {
+		return "none"
+	}
+	return p
+}
+
+// formatPriorityCounts renders counts per priority as e.g. "2 must, 1 none",
+// highest priority first.
+func formatPriorityCounts(counts map[string]int) string {
+	var unknown []string
+	for p := range counts {
+		if p != "" && !containsPriority(p) {
+			unknown = append(unknown, p)
+		}
+	}
+	sort.Strings(unknown)
+	var rows []string
+	for _, p := range append(append(append([]string{}, core.Priorities...), unknown...), "") {
+		if counts[p] > 0 {
+			rows = append(rows, fmt.Sprintf("%d %s", counts[p], priorityLabel(p)))
+		}
+	}
+	return strings.Join(rows, ", ")
+}
+
+func containsPriority(p string) bool {
+	for _, known := range core.Priorities {
+		if p == known {
+			return true
+		}
+	}
+	return false
+}
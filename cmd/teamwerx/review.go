@@ -0,0 +1,383 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+var (
+	commentAuthor   string
+	showAllComments bool
+
+	specCommentCmd = &cobra.Command{
+		Use:   "comment <domain> <requirement-id> <text>",
+		Short: "Add a review comment to a spec requirement",
+		Long:  "Record a review comment in the domain's review sidecar without editing the spec. Use the resolve, close and reopen subcommands to track it.",
+		Args:  cobra.MinimumNArgs(3),
+		RunE:  runSpecComment,
+	}
+
+	specCommentsCmd = &cobra.Command{
+		Use:   "comments <domain>",
+		Short: "List review comments for a spec",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runSpecComments,
+	}
+
+	changeCheckCmd = &cobra.Command{
+		Use:   "check",
+		Short: "Report everything that blocks or needs attention before applying a change",
+		Long:  "Show delta validation issues, stale base fingerprints, missing owner approvals and open review comments on the requirements a change touches.",
+		RunE:  runChangeCheck,
+	}
+
+	changeCommentCmd = &cobra.Command{
+		Use:   "comment <text>",
+		Short: "Add a review comment to a change",
+		Long:  "Append a comment to the change's review thread (comments.md next to the change), so the conversation about a proposal lives with it. 'change show' prints the thread.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  runChangeComment,
+	}
+
+	changeShowCmd = &cobra.Command{
+		Use:   "show",
+		Short: "Show a change with its spec deltas and review comments",
+		RunE:  runChangeShow,
+	}
+)
+
+func init() {
+	specCmd.AddCommand(specCommentCmd)
+	specCmd.AddCommand(specCommentsCmd)
+	for status, verb := range map[string]string{
+		model.ReviewResolved: "resolve",
+		model.ReviewClosed:   "close",
+		model.ReviewOpen:     "reopen",
+	} {
+		specCommentCmd.AddCommand(newCommentStatusCmd(verb, status))
+	}
+	specCommentCmd.Flags().StringVar(&commentAuthor, "author", defaultAuthor(), "Comment author")
+	specCommentsCmd.Flags().BoolVar(&showAllComments, "all", false, "Include resolved and closed comments")
+
+	changeCmd.AddCommand(changeCheckCmd)
+	changeCheckCmd.Flags().StringVar(&changeID, "id", "", "Change ID to check")
+	_ = changeCheckCmd.MarkFlagRequired("id")
+
+	changeCmd.AddCommand(changeCommentCmd)
+	changeCommentCmd.Flags().StringVar(&changeID, "id", "", "Change ID to comment on")
+	changeCommentCmd.Flags().StringVar(&commentAuthor, "author", defaultAuthor(), "Comment author")
+	_ = changeCommentCmd.MarkFlagRequired("id")
+
+	changeCmd.AddCommand(changeShowCmd)
+	changeShowCmd.Flags().StringVar(&changeID, "id", "", "Change ID to show")
+	_ = changeShowCmd.MarkFlagRequired("id")
+}
+
+// defaultAuthor is the default for --author style flags: $TEAMWERX_AUTHOR,
+// which agents set to identify themselves, else the login name.
+func defaultAuthor() string {
+	if a := strings.TrimSpace(os.Getenv("TEAMWERX_AUTHOR")); a != "" {
+		return a
+	}
+	return os.Getenv("USER")
+}
+
+func newCommentStatusCmd(verb, status string) *cobra.Command {
+	return &cobra.Command{
+		Use:   verb + " <domain> <comment-id>",
+		Short: fmt.Sprintf("Mark a review comment as %s", status),
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := newApp(core.AppOptions{
+				SpecsDir:   specsBaseDir,
+				GoalsDir:   goalsBaseDir,
+				ChangesDir: changesBaseDir,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to init app: %w", err)
+			}
+			c, err := app.ReviewManager.SetStatus(strings.TrimSpace(args[0]), strings.TrimSpace(args[1]), status)
+			if err != nil {
+				return fmt.Errorf("failed to update comment: %w", err)
+			}
+			porcelainComment(strings.TrimSpace(args[0]), *c)
+			style.Success.Printf("Comment %s on %s is now %s\n", c.ID, c.RequirementID, c.Status)
+			return nil
+		},
+	}
+}
+
+func runSpecComment(cmd *cobra.Command, args []string) error {
+	domain := strings.TrimSpace(args[0])
+	body := strings.TrimSpace(strings.Join(args[2:], " "))
+
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	spec, err := app.SpecManager.ReadSpec(domain)
+	if err != nil {
+		return fmt.Errorf("failed to read spec: %w", err)
+	}
+	reqID := resolveRequirementArg(spec, args[1])
+	found := false
+	for _, r := range spec.Requirements {
+		if r.ID == reqID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return custom_errors.NewErrNotFound("requirement", reqID)
+	}
+
+	comment := &model.ReviewComment{RequirementID: reqID, Author: strings.TrimSpace(commentAuthor), Body: body}
+	if err := app.ReviewManager.AddComment(domain, comment); err != nil {
+		return fmt.Errorf("failed to add comment: %w", err)
+	}
+	porcelainComment(domain, *comment)
+	style.Success.Printf("Added review comment %s on %s/%s\n", comment.ID, domain, reqID)
+	return nil
+}
+
+func runSpecComments(cmd *cobra.Command, args []string) error {
+	domain := strings.TrimSpace(args[0])
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	comments, err := app.ReviewManager.Load(domain)
+	if err != nil {
+		return fmt.Errorf("failed to load review comments: %w", err)
+	}
+	if !showAllComments {
+		comments = core.OpenReviewComments(comments)
+	}
+	if len(comments) == 0 {
+		printLine(style.Warn, "No review comments for %s.", domain)
+		return nil
+	}
+	for _, c := range comments {
+		porcelainComment(domain, c)
+		printReviewComment(c)
+	}
+	return nil
+}
+
+// printReviewComment prints one comment on a single line, e.g.
+// "- R01 [open] password-reset (alice): needs NFRs".
+func printReviewComment(c model.ReviewComment) {
+	fmt.Printf("- %s [%s] %s", c.ID, c.Status, c.RequirementID)
+	if c.Author != "" {
+		fmt.Printf(" (%s)", c.Author)
+	}
+	fmt.Printf(": %s\n", c.Body)
+}
+
+func runChangeCheck(cmd *cobra.Command, args []string) error {
+	if strings.TrimSpace(changeID) == "" {
+		return fmt.Errorf("change id is required")
+	}
+
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+		CharterDir: charterBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	ch, err := app.ChangeManager.ReadChange(changeID)
+	if err != nil {
+		return fmt.Errorf("failed to read change: %w", err)
+	}
+
+	porcelainChange(ch)
+	hdr := style.Header
+	hdr.Printf("Checking change %s: %s\n", ch.ID, ch.Title)
+	blocking := 0
+	if err := warnDeprecatedChangeIDs(app, ch); err != nil {
+		return err
+	}
+
+	if err := core.ValidateChangeDeltas(app.SpecManager, ch); err != nil {
+		if _, ok := err.(*core.DeltaValidationError); !ok {
+			return err
+		}
+		blocking++
+		printLine(style.Error, "Invalid delta operations:")
+		printDeltaIssues(err)
+	}
+
+	stale, err := core.FindDivergedDeltas(app.SpecManager, ch)
+	if err != nil {
+		return err
+	}
+	if len(stale) > 0 {
+		blocking++
+		printLine(style.Error, "Stale deltas (spec changed since authoring):")
+		for _, s := range stale {
+			porcelain("stale", ch.ID, s.Domain, s.BaseFingerprint, s.CurrentFingerprint, strconv.Itoa(s.Requirements))
+			fmt.Printf("  %s: base %s, current %s\n", s.Domain, s.BaseFingerprint, s.CurrentFingerprint)
+		}
+	}
+
+	drifted, err := core.DriftedBases(app.SpecManager, ch)
+	if err != nil {
+		return err
+	}
+	if len(drifted) > 0 {
+		printLine(style.Warn, "Requirements changed since the change was written:")
+		for _, d := range drifted {
+			porcelain("base-drift", ch.ID, d.Domain, d.RequirementID, d.Base, d.Current)
+			fmt.Printf("  %s/%s\n", d.Domain, d.RequirementID)
+			printLine(style.Muted, "    base:")
+			printIndented(style.Muted, d.Base, "      ")
+			if d.Current == "" {
+				printLine(style.Muted, "    now: removed")
+				continue
+			}
+			printLine(style.Muted, "    now:")
+			printIndented(style.Muted, d.Current, "      ")
+		}
+	}
+
+	if missing := core.MissingApprovals(ch); len(missing) > 0 {
+		blocking++
+		for _, domain := range missing {
+			porcelain("awaiting", ch.ID, domain)
+		}
+		printLine(style.Error, "Missing owner approval for: %s", strings.Join(missing, ", "))
+	}
+
+	comments, err := core.ChangeReviewComments(app.ReviewManager, ch)
+	if err != nil {
+		return err
+	}
+	if len(comments) > 0 {
+		printLine(style.Warn, "Open review comments on touched requirements:")
+		domains := make([]string, 0, len(comments))
+		for d := range comments {
+			domains = append(domains, d)
+		}
+		sort.Strings(domains)
+		for _, d := range domains {
+			fmt.Printf("  %s:\n", d)
+			for _, c := range comments[d] {
+				porcelainComment(d, c)
+				fmt.Print("  ")
+				printReviewComment(c)
+			}
+		}
+	}
+
+	porcelain("check", ch.ID, strconv.Itoa(blocking))
+	if blocking > 0 {
+		return fmt.Errorf("change %s has %d blocking issue(s)", ch.ID, blocking)
+	}
+	printLine(style.Success, "No blocking issues.")
+	return nil
+}
+
+func runChangeComment(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	id := strings.TrimSpace(changeID)
+	comment := &model.DiscussionEntry{Author: strings.TrimSpace(commentAuthor), Content: strings.TrimSpace(strings.Join(args, " "))}
+	if err := app.AddChangeComment(id, comment); err != nil {
+		return fmt.Errorf("failed to add comment: %w", err)
+	}
+	porcelainChangeComment(id, *comment)
+	style.Success.Printf("Added comment %s on change %s\n", comment.ID, id)
+	return nil
+}
+
+func runChangeShow(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	ch, err := app.ChangeManager.ReadChange(strings.TrimSpace(changeID))
+	if err != nil {
+		return fmt.Errorf("failed to read change: %w", err)
+	}
+	comments, err := app.ChangeComments(ch.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load comments: %w", err)
+	}
+
+	porcelainChange(ch)
+	hdr := style.Header
+	hdr.Printf("Change %s: %s\n", ch.ID, ch.Title)
+	fmt.Printf("  Status: %s\n", ch.Status)
+	if ch.GoalID != "" {
+		fmt.Printf("  Goal: %s\n", ch.GoalID)
+	}
+	if !ch.CreatedAt.IsZero() {
+		fmt.Printf("  Created: %s\n", displayTime(ch.CreatedAt, "2006-01-02 15:04"))
+	}
+	printProvenance(ch)
+	for _, d := range ch.SpecDeltas {
+		fmt.Printf("  %s:\n", d.Domain)
+		for _, op := range d.Operations {
+			fmt.Printf("    %s %s\n", op.Type, op.Requirement.ID)
+			if op.Base != "" {
+				porcelain("base", ch.ID, d.Domain, op.Type, op.Requirement.ID, op.Base)
+				printLine(style.Muted, "      base:")
+				printIndented(style.Muted, op.Base, "        ")
+			}
+		}
+	}
+
+	fmt.Println()
+	if len(comments) == 0 {
+		style.Muted.Println("No comments.")
+		return nil
+	}
+	hdr.Printf("Comments (%d):\n", len(comments))
+	for _, c := range comments {
+		porcelainChangeComment(ch.ID, c)
+		style.ID.Printf("- %s ", c.ID)
+		if c.Author != "" {
+			fmt.Printf("@%s ", c.Author)
+		}
+		style.Muted.Printf("%s\n", displayTime(c.Timestamp, time.RFC3339))
+		for _, line := range strings.Split(strings.TrimSpace(c.Content), "\n") {
+			fmt.Printf("    %s\n", line)
+		}
+	}
+	return nil
+}
@@ -1,8 +1,14 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,6 +17,8 @@ import (
 	"github.com/teamwerx/teamwerx/internal/core"
 	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
 	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/plugin"
+	"github.com/teamwerx/teamwerx/internal/utils/opener"
 	promptutil "github.com/teamwerx/teamwerx/internal/utils/prompt"
 )
 
@@ -35,6 +43,69 @@ var (
 		RunE:  runSpecList,
 	}
 
+	specFingerprintCmd = &cobra.Command{
+		Use:   "fingerprint [domain]",
+		Short: "Print current spec fingerprints",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runSpecFingerprint,
+	}
+
+	specCreateCmd = &cobra.Command{
+		Use:   "create <domain>",
+		Short: "Scaffold a new spec with the charter's required sections",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runSpecCreate,
+	}
+
+	specValidateCmd = &cobra.Command{
+		Use:   "validate [domain]",
+		Short: "Check specs for the charter's required sections",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runSpecValidate,
+	}
+
+	specExportCmd = &cobra.Command{
+		Use:   "export [domain]",
+		Short: "Render specs as HTML or PDF with an index page",
+		Long:  "Render one or all specs to --out with requirement anchors, cross-domain links rewritten to the exported pages, and an index.html listing every domain. PDF output requires wkhtmltopdf on PATH and keeps the HTML pages alongside the PDFs.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runSpecExport,
+	}
+
+	specReqCmd = &cobra.Command{
+		Use:   "req",
+		Short: "Work with individual requirements in a spec",
+	}
+
+	specReqMoveCmd = &cobra.Command{
+		Use:   "move <domain> <req-id>",
+		Short: "Move a requirement before or after another requirement",
+		Long:  "Reorder a spec by moving one requirement block next to another. Block text is preserved exactly; only the order changes. The spec's fingerprint changes, so pending changes against it become stale.",
+		Args:  cobra.ExactArgs(2),
+		RunE:  runSpecReqMove,
+	}
+
+	specCheckCmd = &cobra.Command{
+		Use:   "check <domain> <req-id>",
+		Short: "Check or uncheck a requirement's acceptance criterion",
+		Long:  "Set the state of a \"- [ ]\" checklist item under a requirement, counted from 1 as listed by 'spec show'. Without --done or --undone the item is toggled. The edit is merged like a MODIFIED change against the spec's current fingerprint.",
+		Args:  cobra.ExactArgs(2),
+		RunE:  runSpecCheck,
+	}
+
+	specMetaCmd = &cobra.Command{
+		Use:   "meta",
+		Short: "Work with a spec's front matter metadata",
+	}
+
+	specMetaSetCmd = &cobra.Command{
+		Use:   "set <domain> <key=value>...",
+		Short: "Set front matter keys (owner, status, tags, version) on a spec",
+		Long:  "Set keys in the YAML front matter at the top of spec.md, creating it if needed. tags takes a comma-separated list; an empty value (e.g. status=) removes the key. The rest of the file is left untouched, and metadata is not part of the spec fingerprint.",
+		Args:  cobra.MinimumNArgs(2),
+		RunE:  runSpecMetaSet,
+	}
+
 	planCmd = &cobra.Command{
 		Use:   "plan",
 		Short: "Work with plans",
@@ -48,6 +119,14 @@ var (
 		RunE:  runPlanAdd,
 	}
 
+	planGenerateCmd = &cobra.Command{
+		Use:   "generate",
+		Short: "Propose plan tasks from a spec's requirements and scenarios",
+		Long:  "Propose an \"Implement <requirement>\" task per requirement and a \"Test <requirement>: <scenario>\" task per scenario (\"Test <requirement>\" when it has none), each linked to its requirement. Pick which to keep interactively; non-interactive runs keep every new proposal. Proposals matching an existing task title are skipped.",
+		Args:  cobra.NoArgs,
+		RunE:  runPlanGenerate,
+	}
+
 	planListCmd = &cobra.Command{
 		Use:   "list",
 		Short: "List tasks for a goal's plan",
@@ -60,6 +139,20 @@ var (
 		RunE:  runPlanComplete,
 	}
 
+	planCommentCmd = &cobra.Command{
+		Use:   "comment",
+		Short: "Add a comment to a plan task",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  runPlanComment,
+	}
+
+	planExportCmd = &cobra.Command{
+		Use:   "export",
+		Short: "Export a goal's plan for import into Jira or Linear",
+		Long:  "Map each task's title, status, assignee, parent and linked requirements onto Jira's CSV importer (jira-csv) or Linear issue objects (linear-json).",
+		RunE:  runPlanExport,
+	}
+
 	changeCmd = &cobra.Command{
 		Use:   "change",
 		Short: "Work with changes",
@@ -74,7 +167,8 @@ var (
 
 	changeApplyCmd = &cobra.Command{
 		Use:   "apply",
-		Short: "Apply a change by ID",
+		Short: "Apply a change by ID, or every pending change with --all",
+		Long:  "Apply a change by ID. With --all, apply every pending change in ID order; changes whose base fingerprints diverged are moved to the needs-resolution queue (see 'change queue') and the batch continues. With --domain or --op, apply only part of the change; it is left partially-applied with a record of the remaining operations, which a later apply finishes. With --strategy markers, a spec that diverged is merged anyway: each requirement the change would alter is written with its current and incoming text between git-style conflict markers for you to resolve in an editor, and the conflicts are recorded in the change.",
 		RunE:  runChangeApply,
 	}
 
@@ -87,10 +181,92 @@ var (
 	changeResolveCmd = &cobra.Command{
 		Use:   "resolve",
 		Short: "Interactively resolve change conflicts",
-		Long:  "Resolve ErrDiverged conflicts when applying a change by allowing refresh/skip/cancel per domain, then re-apply.",
+		Long:  "Detect every ErrDiverged conflict up front, choose refresh or skip per domain in one session, then apply the change once.",
 		RunE:  runChangeResolve,
 	}
 
+	changeStalenessCmd = &cobra.Command{
+		Use:   "staleness",
+		Short: "Report pending changes whose base fingerprints no longer match current specs",
+		RunE:  runChangeStaleness,
+	}
+
+	changeSplitCmd = &cobra.Command{
+		Use:   "split",
+		Short: "Split a change into one change per domain",
+		RunE:  runChangeSplit,
+	}
+
+	changeMergeCmd = &cobra.Command{
+		Use:   "merge <change-id> <change-id>...",
+		Short: "Merge several changes into a new change",
+		Args:  cobra.MinimumNArgs(2),
+		RunE:  runChangeMerge,
+	}
+
+	changeCloneCmd = &cobra.Command{
+		Use:   "clone <change-id>",
+		Short: "Start a new draft change from an existing one",
+		Long: `Copy a pending or archived change's spec deltas into a new draft change.
+Base fingerprints are refreshed to the current specs, approvals are dropped,
+and the new change records which change it was cloned from. Without --as the
+next free CH-NNN ID is used.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runChangeClone,
+	}
+
+	changeSubmitCmd = &cobra.Command{
+		Use:   "submit",
+		Short: "Submit a change for owner approval",
+		Long:  "Record the required approvers for every domain the change touches, based on the OWNERS file in the workspace directory.",
+		RunE:  runChangeSubmit,
+	}
+
+	changeValidateCmd = &cobra.Command{
+		Use:   "validate",
+		Short: "Check a change's delta operations against the current specs",
+		RunE:  runChangeValidate,
+	}
+
+	changeApproveCmd = &cobra.Command{
+		Use:   "approve",
+		Short: "Record owner sign-off for a submitted change",
+		RunE:  runChangeApprove,
+	}
+
+	changeCreateCmd = &cobra.Command{
+		Use:   "create",
+		Short: "Create a draft change from a template",
+		Long: `Create a draft change whose spec delta is pre-populated for a common operation:
+  add-requirement        ADDED requirement titled --title
+  tighten-requirement    MODIFIED --requirement, seeded with its current text
+  deprecate-requirement  MODIFIED --requirement with a deprecation notice
+  split-requirement      REMOVED --requirement plus one ADDED requirement per --into title`,
+		RunE: runChangeCreate,
+	}
+
+	changeDiffCmd = &cobra.Command{
+		Use:   "diff",
+		Short: "Show what a change would do to the spec text",
+		Long:  "For each operation in a change, show a word-level diff of MODIFIED requirements and scenarios against the current spec, the new block for ADDED operations and the block being deleted for REMOVED operations.",
+		RunE:  runChangeDiff,
+	}
+
+	changeImpactCmd = &cobra.Command{
+		Use:   "impact",
+		Short: "Show which requirements, tasks and domains a change affects",
+		Long: "List the requirements a change edits, every requirement in any domain that references them through " +
+			"[[domain/requirement]] links (transitively), and the plan tasks linked to any of those requirements.",
+		RunE: runChangeImpact,
+	}
+
+	changeReloadCmd = &cobra.Command{
+		Use:   "reload",
+		Short: "Show the current on-disk state of a change",
+		Long:  "Re-read change.json and print its current fingerprint, status and approvals. Use it after a save fails with ErrDiverged to see what another process changed before retrying.",
+		RunE:  runChangeReload,
+	}
+
 	discussCmd = &cobra.Command{
 		Use:   "discuss",
 		Short: "Work with discussions",
@@ -110,6 +286,36 @@ var (
 		RunE:  runDiscussAdd,
 	}
 
+	discussShowCmd = &cobra.Command{
+		Use:   "show <entry-id>",
+		Short: "Show a discussion entry and its attachments",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runDiscussShow,
+	}
+
+	discussSummarizeCmd = &cobra.Command{
+		Use:   "summarize",
+		Short: "Add a summary entry condensing the discussion since the last summary",
+		Long: "Condense every entry recorded since the last summary into a new entry of type 'summary'. " +
+			"The summary is template-based unless --plugin names a teamwerx-<name> plugin, which receives " +
+			"the transcript on stdin (invoked as 'teamwerx-<name> summarize --goal <id>') and prints the summary.",
+		RunE: runDiscussSummarize,
+	}
+
+	discussImportCmd = &cobra.Command{
+		Use:   "import",
+		Short: "Import a chat transcript as discussion entries",
+		Long:  "Convert an exported conversation (Slack channel export, JSON Lines, or markdown with '## <author> (<timestamp>)' headings) into timestamped discussion entries with authors.",
+		RunE:  runDiscussImport,
+	}
+
+	discussContextCmd = &cobra.Command{
+		Use:   "context",
+		Short: "Print the condensed discussion history used for agent prompts",
+		Long:  "Print every summary followed by the raw entries recorded since the last summary.",
+		RunE:  runDiscussContext,
+	}
+
 	charterCmd = &cobra.Command{
 		Use:   "charter",
 		Short: "Work with project charter",
@@ -125,7 +331,66 @@ var (
 	charterShowCmd = &cobra.Command{
 		Use:   "show",
 		Short: "Display project charter",
-		RunE:  runCharterShow,
+		Long: `Display the project charter. A charter can extend an organization charter,
+named by URL or by path relative to it in its frontmatter:
+
+  extends: ../org/charter.md
+
+--effective shows the charter with the ones it extends merged in: fields the
+project sets override the organization's, conventions and goal templates key
+by key, and "## " sections of the body by heading.`,
+		RunE: runCharterShow,
+	}
+
+	goalCmd = &cobra.Command{
+		Use:   "goal",
+		Short: "Work with goals",
+		Long:  "Commands for creating, tracking and completing goals, each with its own plan and discussion.",
+	}
+
+	goalCreateCmd = &cobra.Command{
+		Use:   "create",
+		Short: "Create a goal from a template",
+		Long: `Create a goal with a pre-seeded plan and an opening discussion entry of guiding questions:
+  feature  research, spec delta, implement, tests, docs
+  bugfix   reproduce, failing test, fix, spec update
+  spike    question and timebox, explore, findings
+
+The charter can override a template's tasks and questions, or add new templates,
+under goal_templates in its frontmatter.`,
+		RunE: runGoalCreate,
+	}
+
+	goalListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List goals with plan progress",
+		Long: `List goals with plan progress.
+
+--board shows the tasks of every active goal in pending, in-progress, review
+and completed columns instead (blocked tasks wait in pending, waived ones
+count as completed), with each column's count per assignee. --json prints
+the board as JSON; --all keeps completed goals on it.`,
+		RunE: runGoalList,
+	}
+
+	goalStatusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "Show a goal's progress and open tasks",
+		RunE:  runGoalStatus,
+	}
+
+	goalCompleteCmd = &cobra.Command{
+		Use:   "complete",
+		Short: "Mark a goal completed",
+		Long: `Mark a goal completed. Every task in its plan must be completed or waived;
+waive the rest with --waive <task> (repeatable) and a --reason, which is recorded on each waived task.
+
+Completing a goal asks for a retrospective: what worked, what didn't and
+the follow-ups, each given with --worked, --didnt-work and --follow-up
+(repeatable) or at the prompts. It is added to the goal's discussion as a
+reflection entry. --spawn-follow-ups creates a goal from --template for
+each follow-up; interactively you are asked instead.`,
+		RunE: runGoalComplete,
 	}
 
 	completionCmd = &cobra.Command{
@@ -136,37 +401,154 @@ var (
 	}
 
 	// Flags
-	specsBaseDir   string
-	goalsBaseDir   string
-	goalID         string
-	changesBaseDir string
-	charterBaseDir string
-	changeID       string
-	taskID         string
+	specsBaseDir     string
+	goalsBaseDir     string
+	goalID           string
+	changesBaseDir   string
+	charterBaseDir   string
+	changeID         string
+	taskID           string
+	attachPaths      []string
+	entryRefs        []string
+	discussListRef   string
+	openAttach       bool
+	parentTaskID     string
+	forceComplete    bool
+	showTree         bool
+	splitByDomain    bool
+	mergeIntoID      string
+	cloneAsID        string
+	changeTitle      string
+	approverName     string
+	authorName       string
+	approveDomain    string
+	exportFormat     string
+	exportOutDir     string
+	sortKey          string
+	sortOrder        string
+	summaryPlugin    string
+	summarizeDryRun  bool
+	importFile       string
+	importFormat     string
+	taskAssignee     string
+	taskReqs         []string
+	planExportFmt    string
+	planExportOut    string
+	specShowJSON     bool
+	changeTemplate   string
+	changeDomain     string
+	changeReqID      string
+	splitInto        []string
+	goalTemplate     string
+	goalTitle        string
+	waiveTasks       []string
+	waiveReason      string
+	retroWorked      []string
+	retroDidntWork   []string
+	retroFollowUps   []string
+	spawnFollowUps   bool
+	moveBefore       string
+	moveAfter        string
+	filterStatus     string
+	filterGoal       string
+	filterDomain     string
+	filterSince      string
+	milestoneName    string
+	exportOmitNotes  bool
+	applyAll         bool
+	checkItem        int
+	checkDone        bool
+	checkUndone      bool
+	fromSpec         string
+	fromRequirement  string
+	generateDryRun   bool
+	specListLong     bool
+	specListPriority string
+	applyDomains     []string
+	applyOps         []string
+	applyStrategy    string
+	entryType        string
+	changeDesc       string
+	charterPurpose   string
+	charterEffective bool
 )
 
-// Execute runs the root command (to be called by main in future integration).
+// Execute runs the root command, dispatching to an external plugin when the
+// first argument names one instead of a built-in command.
 func Execute() error {
-	return rootCmd.Execute()
+	started := time.Now()
+	if handled, err := dispatchPlugin(os.Args[1:]); handled {
+		recordCommand("plugin "+os.Args[1], started, err)
+		return err
+	}
+	cmd, err := rootCmd.ExecuteC()
+	if err == nil && cmd != nil {
+		rememberContext(cmd)
+	}
+	stopProfiling()
+	endPorcelain()
+	if cmd != nil && cmd != rootCmd {
+		recordCommand(strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" "), started, err)
+	}
+	return err
 }
 
 func init() {
+	rootCmd.PersistentFlags().BoolVar(&porcelainMode, "porcelain", false, "Print stable tab-separated records instead of human-readable output (see docs/porcelain.md)")
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := startProfiling(); err != nil {
+			return err
+		}
+		if _, err := timeMode(); err != nil {
+			return err
+		}
+		if porcelainMode {
+			if err := beginPorcelain(); err != nil {
+				return err
+			}
+		}
+		applyLocalDefaults(cmd)
+		return applyTheme()
+	}
+
 	// Attach hierarchy: root -> spec -> list
 	rootCmd.AddCommand(specCmd)
 	specCmd.AddCommand(specListCmd)
 	specShowCmd := &cobra.Command{
-		Use:   "show",
-		Short: "Show details for a spec domain",
-		Args:  cobra.ExactArgs(1),
-		RunE:  runSpecShow,
+		Use:   "show <domain> [req-id]",
+		Short: "Show details for a spec domain or one of its requirements",
+		Long: `Show a spec domain's requirements, or with a requirement ID only that
+requirement. A single requirement is read without parsing the rest of the
+spec, so this stays fast on large specs.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: runSpecShow,
 	}
 	specCmd.AddCommand(specShowCmd)
+	specShowCmd.Flags().BoolVar(&specShowJSON, "json", false, "Print requirement byte ranges, line numbers, hashes, tags and references as JSON")
+	specCmd.AddCommand(specFingerprintCmd)
+	specCmd.AddCommand(specCreateCmd)
+	specCmd.AddCommand(specValidateCmd)
+	specCmd.AddCommand(specExportCmd)
+	specCmd.AddCommand(specReqCmd)
+	specCmd.AddCommand(specCheckCmd)
+	specCmd.AddCommand(specMetaCmd)
+	specMetaCmd.AddCommand(specMetaSetCmd)
+	specCheckCmd.Flags().IntVar(&checkItem, "item", 0, "Criterion number (1-based)")
+	specCheckCmd.Flags().BoolVar(&checkDone, "done", false, "Mark the criterion done")
+	specCheckCmd.Flags().BoolVar(&checkUndone, "undone", false, "Mark the criterion not done")
+	_ = specCheckCmd.MarkFlagRequired("item")
+	specReqCmd.AddCommand(specReqMoveCmd)
+	specReqMoveCmd.Flags().StringVar(&moveBefore, "before", "", "Requirement ID to move in front of")
+	specReqMoveCmd.Flags().StringVar(&moveAfter, "after", "", "Requirement ID to move behind")
 
 	// Attach plan hierarchy: root -> plan -> add
 	rootCmd.AddCommand(planCmd)
 	planCmd.AddCommand(planAddCmd)
+	planCmd.AddCommand(planGenerateCmd)
 	planCmd.AddCommand(planListCmd)
 	planCmd.AddCommand(planCompleteCmd)
+	planCmd.AddCommand(planCommentCmd)
+	planCmd.AddCommand(planExportCmd)
 	planShowCmd := &cobra.Command{
 		Use:   "show",
 		Short: "Show a goal's plan details",
@@ -175,59 +557,197 @@ func init() {
 	planCmd.AddCommand(planShowCmd)
 	planShowCmd.Flags().StringVar(&goalID, "goal", "", "Goal ID to show plan for")
 	_ = planShowCmd.MarkFlagRequired("goal")
+	planShowCmd.Flags().BoolVar(&showTree, "tree", false, "Render subtasks as a tree")
+	planShowCmd.Flags().StringVar(&taskID, "task", "", "Show a single task with its comments")
 
 	// Attach change hierarchy: root -> change -> [list|apply|archive]
 	rootCmd.AddCommand(changeCmd)
 	changeCmd.AddCommand(changeListCmd)
 	changeCmd.AddCommand(changeApplyCmd)
 	changeCmd.AddCommand(changeArchiveCmd)
-	changeCmd.AddCommand(changeResolveCmd) // TODO: interactive conflict resolution scaffolding
+	changeCmd.AddCommand(changeResolveCmd)
+	changeCmd.AddCommand(changeStalenessCmd)
+	changeCmd.AddCommand(changeSplitCmd)
+	changeCmd.AddCommand(changeMergeCmd)
+	changeCmd.AddCommand(changeCloneCmd)
+	changeCmd.AddCommand(changeSubmitCmd)
+	changeCmd.AddCommand(changeApproveCmd)
+	changeCmd.AddCommand(changeValidateCmd)
+	changeCmd.AddCommand(changeReloadCmd)
+	changeCmd.AddCommand(changeDiffCmd)
+	changeCmd.AddCommand(changeImpactCmd)
+	changeCmd.AddCommand(changeCreateCmd)
 
 	// Attach discuss hierarchy: root -> discuss -> [list|add]
 	rootCmd.AddCommand(discussCmd)
 	discussCmd.AddCommand(discussListCmd)
 	discussCmd.AddCommand(discussAddCmd)
+	discussCmd.AddCommand(discussShowCmd)
+	discussCmd.AddCommand(discussSummarizeCmd)
+	discussCmd.AddCommand(discussContextCmd)
+	discussCmd.AddCommand(discussImportCmd)
 
 	// Attach charter hierarchy: root -> charter -> [init|show]
 	rootCmd.AddCommand(charterCmd)
 	charterCmd.AddCommand(charterInitCmd)
 	charterCmd.AddCommand(charterShowCmd)
 
+	// Attach goal hierarchy: root -> goal -> create
+	rootCmd.AddCommand(goalCmd)
+	goalCmd.AddCommand(goalCreateCmd)
+	goalCmd.PersistentFlags().StringVar(&goalsBaseDir, "goals-dir", ".teamwerx/goals", "Base directory containing goals")
+	goalCreateCmd.Flags().StringVar(&goalTitle, "title", "", "Goal title")
+	_ = goalCreateCmd.MarkFlagRequired("title")
+	goalCreateCmd.Flags().StringVar(&goalTemplate, "template", core.GoalTemplateFeature, "Template: "+strings.Join(core.GoalTemplates, ", ")+" or one defined in the charter")
+	goalCreateCmd.Flags().StringVar(&goalID, "id", "", "Goal ID (default: next NNN-<title>)")
+	goalCmd.AddCommand(goalListCmd)
+	goalCmd.AddCommand(goalStatusCmd)
+	goalStatusCmd.Flags().StringVar(&goalID, "goal", "", "Goal ID")
+	_ = goalStatusCmd.MarkFlagRequired("goal")
+	goalCmd.AddCommand(goalCompleteCmd)
+	goalCompleteCmd.Flags().StringVar(&goalID, "goal", "", "Goal ID to complete")
+	_ = goalCompleteCmd.MarkFlagRequired("goal")
+	goalCompleteCmd.Flags().StringArrayVar(&waiveTasks, "waive", nil, "Task ID to waive instead of completing (repeatable)")
+	goalCompleteCmd.Flags().StringVar(&waiveReason, "reason", "", "Why the waived tasks are being skipped (required with --waive)")
+	goalCompleteCmd.Flags().StringArrayVar(&retroWorked, "worked", nil, "Retrospective: something that worked (repeatable)")
+	goalCompleteCmd.Flags().StringArrayVar(&retroDidntWork, "didnt-work", nil, "Retrospective: something that didn't work (repeatable)")
+	goalCompleteCmd.Flags().StringArrayVar(&retroFollowUps, "follow-up", nil, "Retrospective: a follow-up to do (repeatable)")
+	goalCompleteCmd.Flags().BoolVar(&spawnFollowUps, "spawn-follow-ups", false, "Create a goal for each follow-up")
+	goalCompleteCmd.Flags().StringVar(&authorName, "author", defaultAuthor(), "Author of the retrospective entry")
+	goalCompleteCmd.Flags().StringVar(&goalTemplate, "template", core.GoalTemplateFeature, "Template for spawned follow-up goals")
+
 	// Completion command
 	rootCmd.AddCommand(completionCmd)
 
 	// Flags for discuss
 	discussCmd.PersistentFlags().StringVar(&goalsBaseDir, "goals-dir", ".teamwerx/goals", "Base directory containing goals")
 	discussListCmd.Flags().StringVar(&goalID, "goal", "", "Goal ID")
-	_ = discussListCmd.MarkFlagRequired("goal")
+	discussListCmd.Flags().StringVar(&discussListRef, "ref", "", "Only entries that ref this task, change or requirement (searches every goal without --goal)")
 	discussAddCmd.Flags().StringVar(&goalID, "goal", "", "Goal ID")
 	_ = discussAddCmd.MarkFlagRequired("goal")
+	discussAddCmd.Flags().StringArrayVar(&attachPaths, "attach", nil, "File to attach to the entry (repeatable)")
+	discussAddCmd.Flags().StringSliceVar(&entryRefs, "ref", nil, "Task (T03), change (CH-001) or requirement (auth/login) the entry is about (repeatable)")
+	discussAddCmd.Flags().StringVar(&authorName, "author", defaultAuthor(), "Entry author")
+	discussAddCmd.Flags().StringVar(&entryType, "type", "", "Entry type, e.g. discussion, reflection or decision (see 'discuss types'; prompts when omitted)")
+	discussShowCmd.Flags().StringVar(&goalID, "goal", "", "Goal ID")
+	_ = discussShowCmd.MarkFlagRequired("goal")
+	discussShowCmd.Flags().BoolVar(&openAttach, "open", false, "Open attachments with the system default application")
+	discussSummarizeCmd.Flags().StringVar(&goalID, "goal", "", "Goal ID")
+	_ = discussSummarizeCmd.MarkFlagRequired("goal")
+	discussSummarizeCmd.Flags().StringVar(&summaryPlugin, "plugin", "", "Summarizer plugin name (teamwerx-<name> on PATH)")
+	discussSummarizeCmd.Flags().BoolVar(&summarizeDryRun, "dry-run", false, "Print the summary without adding it")
+	discussContextCmd.Flags().StringVar(&goalID, "goal", "", "Goal ID")
+	discussImportCmd.Flags().StringVar(&goalID, "goal", "", "Goal ID")
+	discussImportCmd.Flags().StringVar(&importFile, "file", "", "Transcript file to import")
+	discussImportCmd.Flags().StringVar(&importFormat, "format", core.TranscriptJSONL, "Transcript format: "+strings.Join(core.TranscriptFormats, ", "))
+	_ = discussImportCmd.MarkFlagRequired("goal")
+	_ = discussImportCmd.MarkFlagRequired("file")
+	_ = discussContextCmd.MarkFlagRequired("goal")
 
 	// Flags
 	specCmd.PersistentFlags().StringVar(&specsBaseDir, "specs-dir", ".teamwerx/specs", "Base directory containing spec domains")
 	specCmd.PersistentFlags().StringVar(&goalsBaseDir, "goals-dir", ".teamwerx/goals", "Base directory containing goals")
 	specCmd.PersistentFlags().StringVar(&changesBaseDir, "changes-dir", ".teamwerx/changes", "Base directory containing changes")
+	specCmd.PersistentFlags().StringVar(&charterBaseDir, "charter-dir", ".teamwerx", "Base directory for charter")
+	specExportCmd.Flags().StringVar(&exportFormat, "format", "html", "Output format: html or pdf")
+	specExportCmd.Flags().StringVar(&exportOutDir, "out", "docs", "Directory to write exported files to")
+	specExportCmd.Flags().BoolVar(&exportOmitNotes, "omit-notes", false, "Leave requirement rationale and <details> notes out of exported pages")
+	addSortFlags(specListCmd, core.SpecSortKeys)
+	specListCmd.Flags().BoolVar(&specListLong, "long", false, "Also show each domain's owner, status, tags and version")
+	specListCmd.Flags().StringVar(&specListPriority, "priority", "", "Only list requirements of this priority: must, should, could or none")
+	addSortFlags(changeListCmd, core.ChangeSortKeys)
+	changeListCmd.Flags().StringVar(&filterStatus, "status", "", "Only list changes with this status")
+	changeListCmd.Flags().StringVar(&filterGoal, "goal", "", "Only list changes for this goal")
+	changeListCmd.Flags().StringVar(&filterDomain, "domain", "", "Only list changes touching this spec domain")
+	changeListCmd.Flags().StringVar(&filterSince, "since", "", "Only list changes created since a date (YYYY-MM-DD) or age (e.g. 7d, 36h)")
+	changeListCmd.Flags().StringVar(&milestoneName, "milestone", "", "Only list changes assigned to this milestone")
+	addSortFlags(discussListCmd, core.DiscussionSortKeys)
+	addSortFlags(planListCmd, core.TaskSortKeys)
 	planCmd.PersistentFlags().StringVar(&goalsBaseDir, "goals-dir", ".teamwerx/goals", "Base directory containing goals")
 	planAddCmd.Flags().StringVar(&goalID, "goal", "", "Goal ID to add the task to")
 	_ = planAddCmd.MarkFlagRequired("goal")
+	planAddCmd.Flags().StringVar(&parentTaskID, "parent", "", "Parent task ID to add a subtask under (e.g., T02)")
+	planAddCmd.Flags().StringVar(&taskAssignee, "assignee", "", "Person responsible for the task (e.g., @alice)")
+	planAddCmd.Flags().StringArrayVar(&taskReqs, "requirement", nil, "Linked requirement as <domain>/<requirement-id> (repeatable)")
+	planGenerateCmd.Flags().StringVar(&goalID, "goal", "", "Goal ID whose plan to add tasks to")
+	_ = planGenerateCmd.MarkFlagRequired("goal")
+	planGenerateCmd.Flags().StringVar(&fromSpec, "from-spec", "", "Spec domain to generate tasks from")
+	_ = planGenerateCmd.MarkFlagRequired("from-spec")
+	planGenerateCmd.Flags().StringVar(&fromRequirement, "requirement", "", "Only generate tasks for this requirement ID")
+	planGenerateCmd.Flags().BoolVar(&generateDryRun, "dry-run", false, "Print the proposals without changing the plan")
+	planGenerateCmd.Flags().StringVar(&specsBaseDir, "specs-dir", ".teamwerx/specs", "Base directory containing spec domains")
+	planExportCmd.Flags().StringVar(&goalID, "goal", "", "Goal ID whose plan to export")
+	_ = planExportCmd.MarkFlagRequired("goal")
+	planExportCmd.Flags().StringVar(&planExportFmt, "format", core.PlanExportJiraCSV, "Export format: "+strings.Join(core.PlanExportFormats, ", "))
+	planExportCmd.Flags().StringVar(&planExportOut, "out", "", "File to write (default: stdout)")
 
 	planListCmd.Flags().StringVar(&goalID, "goal", "", "Goal ID to list tasks for")
 	_ = planListCmd.MarkFlagRequired("goal")
+	planListCmd.Flags().BoolVar(&showTree, "tree", false, "Render subtasks as a tree")
 
 	planCompleteCmd.Flags().StringVar(&goalID, "goal", "", "Goal ID")
 	planCompleteCmd.Flags().StringVar(&taskID, "task", "", "Task ID to complete (e.g., T01)")
+	planCompleteCmd.Flags().BoolVar(&forceComplete, "force", false, "Complete a parent task even if subtasks are still open")
+	planCompleteCmd.Flags().StringVar(&authorName, "by", defaultAuthor(), "Who completed the task")
 	_ = planCompleteCmd.MarkFlagRequired("goal")
 	_ = planCompleteCmd.MarkFlagRequired("task")
 
+	planCommentCmd.Flags().StringVar(&goalID, "goal", "", "Goal ID")
+	planCommentCmd.Flags().StringVar(&taskID, "task", "", "Task ID to comment on (e.g., T02)")
+	_ = planCommentCmd.MarkFlagRequired("goal")
+	_ = planCommentCmd.MarkFlagRequired("task")
+
 	changeCmd.PersistentFlags().StringVar(&changesBaseDir, "changes-dir", ".teamwerx/changes", "Base directory containing changes")
+	changeCmd.PersistentFlags().StringVar(&specsBaseDir, "specs-dir", ".teamwerx/specs", "Base directory containing spec domains")
 	changeApplyCmd.Flags().StringVar(&changeID, "id", "", "Change ID to apply")
-	_ = changeApplyCmd.MarkFlagRequired("id")
+	changeApplyCmd.Flags().BoolVar(&applyAll, "all", false, "Apply every pending change, queueing diverged ones for resolution")
+	changeApplyCmd.Flags().StringVar(&milestoneName, "milestone", "", "Apply every pending change of this milestone, as --all does")
+	changeApplyCmd.Flags().StringArrayVar(&applyDomains, "domain", nil, "Only apply the deltas for this spec domain (repeatable)")
+	changeApplyCmd.Flags().StringArrayVar(&applyOps, "op", nil, "Only apply operations on this requirement, as <req-id> or <domain>/<req-id> (repeatable)")
+	changeApplyCmd.Flags().StringVar(&applyStrategy, "strategy", core.MergeStrategyReject, "What to do when a spec diverged: reject, or markers to write both versions of each conflicted requirement between conflict markers")
 	changeArchiveCmd.Flags().StringVar(&changeID, "id", "", "Change ID to archive")
 	_ = changeArchiveCmd.MarkFlagRequired("id")
 	changeResolveCmd.Flags().StringVar(&changeID, "id", "", "Change ID to resolve conflicts for")
 	_ = changeResolveCmd.MarkFlagRequired("id")
+	changeSplitCmd.Flags().StringVar(&changeID, "id", "", "Change ID to split")
+	_ = changeSplitCmd.MarkFlagRequired("id")
+	changeSplitCmd.Flags().BoolVar(&splitByDomain, "by-domain", false, "Create one change per spec domain")
+	_ = changeSplitCmd.MarkFlagRequired("by-domain")
+	changeMergeCmd.Flags().StringVar(&mergeIntoID, "into", "", "ID of the new merged change")
+	_ = changeMergeCmd.MarkFlagRequired("into")
+	changeMergeCmd.Flags().StringVar(&changeTitle, "title", "", "Title for the merged change (default lists the source IDs)")
+	changeCloneCmd.Flags().StringVar(&cloneAsID, "as", "", "ID of the new change (default: next CH-NNN)")
+	changeCmd.PersistentFlags().StringVar(&charterBaseDir, "charter-dir", ".teamwerx", "Base directory for charter and OWNERS file")
+	changeValidateCmd.Flags().StringVar(&changeID, "id", "", "Change ID to validate")
+	_ = changeValidateCmd.MarkFlagRequired("id")
+	changeSubmitCmd.Flags().StringVar(&changeID, "id", "", "Change ID to submit")
+	_ = changeSubmitCmd.MarkFlagRequired("id")
+	changeApproveCmd.Flags().StringVar(&changeID, "id", "", "Change ID to approve")
+	_ = changeApproveCmd.MarkFlagRequired("id")
+	changeApproveCmd.Flags().StringVar(&approverName, "as", "", "Owner granting approval (e.g., @alice)")
+	_ = changeApproveCmd.MarkFlagRequired("as")
+	changeApproveCmd.Flags().StringVar(&approveDomain, "domain", "", "Approve only this domain (default: every domain the owner owns)")
+	changeReloadCmd.Flags().StringVar(&changeID, "id", "", "Change ID to reload")
+	changeDiffCmd.Flags().StringVar(&changeID, "id", "", "Change ID to diff")
+	_ = changeDiffCmd.MarkFlagRequired("id")
+	changeImpactCmd.Flags().StringVar(&changeID, "id", "", "Change ID to analyze")
+	_ = changeImpactCmd.MarkFlagRequired("id")
+	changeCreateCmd.Flags().StringVar(&changeTemplate, "template", "", "Template: "+strings.Join(core.ChangeTemplates, ", "))
+	_ = changeCreateCmd.MarkFlagRequired("template")
+	changeCreateCmd.Flags().StringVar(&changeDomain, "domain", "", "Spec domain the change targets")
+	_ = changeCreateCmd.MarkFlagRequired("domain")
+	changeCreateCmd.Flags().StringVar(&changeTitle, "title", "", "Requirement title (add-requirement) or change title override")
+	changeCreateCmd.Flags().StringVar(&changeReqID, "requirement", "", "Existing requirement ID (tighten, deprecate, split)")
+	changeCreateCmd.Flags().StringArrayVar(&splitInto, "into", nil, "Title of a requirement to split into (repeatable, split-requirement)")
+	changeCreateCmd.Flags().StringVar(&changeID, "id", "", "Change ID (default: next CH-NNN)")
+	changeCreateCmd.Flags().StringVar(&goalID, "goal", "", "Goal the change belongs to")
+	changeCreateCmd.Flags().StringVar(&authorName, "author", defaultAuthor(), "Change author")
+	changeCreateCmd.Flags().StringVar(&changeDesc, "description", "", "Why the change is needed (default: write it in $EDITOR when interactive)")
+	_ = changeReloadCmd.MarkFlagRequired("id")
 
 	charterCmd.PersistentFlags().StringVar(&charterBaseDir, "charter-dir", ".teamwerx", "Base directory for charter")
+	charterInitCmd.Flags().StringVar(&charterPurpose, "purpose", "", "Project purpose (default: write it in $EDITOR when interactive)")
+	charterShowCmd.Flags().BoolVar(&charterEffective, "effective", false, "Merge in the charters this one extends")
 }
 
 func runSpecList(cmd *cobra.Command, args []string) error {
@@ -235,15 +755,15 @@ func runSpecList(cmd *cobra.Command, args []string) error {
 	if idx, choice, err := promptutil.Select("Select action", []string{"List specs", "Cancel"}, 0); err != nil {
 		return fmt.Errorf("prompt failed: %w", err)
 	} else if idx != 0 || choice != "List specs" {
-		color.Yellow("Cancelled.")
+		printLine(style.Warn, "Cancelled.")
 		return nil
 	}
 
 	// Proceed to list specs
-	hdr := color.New(color.FgCyan, color.Bold)
+	hdr := style.Header
 	hdr.Printf("Scanning specs directory: %s\n", specsBaseDir)
 
-	app, err := core.NewApp(core.AppOptions{
+	app, err := newApp(core.AppOptions{
 		SpecsDir:   specsBaseDir,
 		GoalsDir:   goalsBaseDir,
 		ChangesDir: changesBaseDir,
@@ -255,24 +775,57 @@ func runSpecList(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to list specs: %w", err)
 	}
+	if err := core.SortSpecs(specs, sortKey, sortOrder); err != nil {
+		return err
+	}
+	priority := strings.ToLower(strings.TrimSpace(specListPriority))
+	if priority != "" {
+		if err := core.ValidatePriority(priority); err != nil {
+			return err
+		}
+		var filtered []*model.Spec
+		for _, spec := range specs {
+			var reqs []model.Requirement
+			for _, r := range spec.Requirements {
+				if core.MatchesPriority(r, priority) {
+					reqs = append(reqs, r)
+				}
+			}
+			if len(reqs) > 0 {
+				spec.Requirements = reqs
+				filtered = append(filtered, spec)
+			}
+		}
+		specs = filtered
+	}
 
 	if len(specs) == 0 {
-		color.Yellow("No specs found.")
+		printLine(style.Warn, "No specs found.")
 		return nil
 	}
 
-	ok := color.New(color.FgGreen, color.Bold)
+	ok := style.Header
 	ok.Printf("Found %d spec(s):\n", len(specs))
 
 	for _, spec := range specs {
-		title := color.New(color.FgWhite, color.Bold)
+		porcelainSpec(spec)
+		title := style.ID
 		title.Printf("- Domain: %s\n", spec.Domain)
 
-		subtle := color.New(color.Faint)
+		subtle := style.Muted
+		if specListLong {
+			porcelainSpecMeta(spec)
+			printSpecMeta("  ", spec.Meta)
+		}
 		subtle.Printf("  Requirements (%d):\n", len(spec.Requirements))
 
 		for _, req := range spec.Requirements {
+			porcelainRequirement(spec.Domain, req)
 			fmt.Printf("    - %s ", req.Title)
+			if req.Priority != "" {
+				subtle.Printf("(id=%s, %s)\n", req.ID, req.Priority)
+				continue
+			}
 			subtle.Printf("(id=%s)\n", req.ID)
 		}
 	}
@@ -286,7 +839,7 @@ func runPlanAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("task title cannot be empty")
 	}
 
-	app, err := core.NewApp(core.AppOptions{
+	app, err := newApp(core.AppOptions{
 		SpecsDir:   specsBaseDir,
 		GoalsDir:   goalsBaseDir,
 		ChangesDir: changesBaseDir,
@@ -304,14 +857,118 @@ func runPlanAdd(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	if _, err := app.PlanManager.AddTask(plan, title); err != nil {
+	var task *model.Task
+	if strings.TrimSpace(parentTaskID) != "" {
+		task, err = app.PlanManager.AddSubtask(plan, strings.TrimSpace(parentTaskID), title)
+	} else {
+		task, err = app.PlanManager.AddTask(plan, title)
+	}
+	if err != nil {
 		return err
 	}
+	task.Assignee = strings.TrimSpace(taskAssignee)
+	for _, r := range taskReqs {
+		if r = strings.TrimSpace(r); r != "" {
+			task.Requirements = append(task.Requirements, r)
+		}
+	}
 	if err := app.PlanManager.Save(plan); err != nil {
 		return err
 	}
+	porcelainTask(goalID, *task)
+
+	if task.ParentID != "" {
+		style.Success.Printf("Added subtask %s under %s to goal %s: %s\n", task.ID, task.ParentID, goalID, title)
+		return nil
+	}
+	style.Success.Printf("Added task to goal %s: %s\n", goalID, title)
+	return nil
+}
+
+func runPlanGenerate(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	spec, err := app.SpecManager.ReadSpec(strings.TrimSpace(fromSpec))
+	if err != nil {
+		return fmt.Errorf("failed to read spec: %w", err)
+	}
+	plan, err := app.PlanManager.Load(goalID)
+	if err != nil {
+		if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+			return fmt.Errorf("failed to load plan: %w", err)
+		}
+		plan = &model.Plan{GoalID: goalID, Tasks: []model.Task{}}
+	}
+
+	fromReq := strings.TrimSpace(fromRequirement)
+	if fromReq != "" {
+		fromReq = resolveRequirementArg(spec, fromReq)
+	}
+	proposals, err := core.ProposeTasks(spec, fromReq, plan)
+	if err != nil {
+		return fmt.Errorf("failed to propose tasks: %w", err)
+	}
+	var fresh []core.TaskProposal
+	for _, p := range proposals {
+		if !p.Existing {
+			fresh = append(fresh, p)
+		}
+	}
+	if skipped := len(proposals) - len(fresh); skipped > 0 {
+		fmt.Printf("Skipping %d proposal(s) already in the plan.\n", skipped)
+	}
+	if len(fresh) == 0 {
+		printLine(style.Warn, "No new tasks to propose for %s.", spec.Domain)
+		return nil
+	}
+
+	if generateDryRun {
+		for _, p := range fresh {
+			porcelain("proposal", goalID, p.Requirement, p.Title)
+			fmt.Printf("- %s  [%s]\n", p.Title, p.Requirement)
+		}
+		printLine(style.Warn, "Dry run: %d task(s) not added.", len(fresh))
+		return nil
+	}
+
+	items := make([]string, len(fresh))
+	defaults := make([]bool, len(fresh))
+	for i, p := range fresh {
+		items[i] = fmt.Sprintf("%s  [%s]", p.Title, p.Requirement)
+		defaults[i] = true
+	}
+	picked, err := promptutil.MultiSelect("Select tasks to add", items, defaults)
+	if err != nil {
+		return fmt.Errorf("prompt failed: %w", err)
+	}
+	keep := make([]core.TaskProposal, 0, len(picked))
+	for _, i := range picked {
+		keep = append(keep, fresh[i])
+	}
+	if len(keep) == 0 {
+		printLine(style.Warn, "No tasks selected.")
+		return nil
+	}
 
-	color.New(color.FgGreen).Printf("Added task to goal %s: %s\n", goalID, title)
+	added, err := core.AddProposedTasks(app.PlanManager, plan, keep)
+	if err != nil {
+		return err
+	}
+	if err := app.PlanManager.Save(plan); err != nil {
+		return err
+	}
+	for _, t := range added {
+		porcelainTask(goalID, t)
+		fmt.Printf("  %s  %s\n", t.ID, t.Title)
+	}
+	style.Success.Printf("Added %d task(s) to goal %s from spec %s\n", len(added), goalID, spec.Domain)
 	return nil
 }
 
@@ -320,7 +977,7 @@ func runPlanList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("goal id is required")
 	}
 
-	app, err := core.NewApp(core.AppOptions{
+	app, err := newApp(core.AppOptions{
 		SpecsDir:   specsBaseDir,
 		GoalsDir:   goalsBaseDir,
 		ChangesDir: changesBaseDir,
@@ -331,19 +988,76 @@ func runPlanList(cmd *cobra.Command, args []string) error {
 
 	plan, err := app.PlanManager.Load(goalID)
 	if err != nil {
-		color.Yellow("No plan found for goal %s.", goalID)
+		printLine(style.Warn, "No plan found for goal %s.", goalID)
 		return nil
 	}
 
-	ok := color.New(color.FgGreen, color.Bold)
+	if err := core.SortTasks(plan.Tasks, sortKey, sortOrder); err != nil {
+		return err
+	}
+	filter, err := taskListFilter()
+	if err != nil {
+		return err
+	}
+	tasks := plan.Tasks[:0]
+	for _, t := range plan.Tasks {
+		if filter.Matches(t) {
+			tasks = append(tasks, t)
+		}
+	}
+	plan.Tasks = tasks
+
+	for _, t := range plan.Tasks {
+		porcelainTask(goalID, t)
+	}
+	ok := style.Header
 	ok.Printf("Tasks for goal %s (%d):\n", goalID, len(plan.Tasks))
+	if showTree {
+		printTaskTree(plan.Tasks)
+		return nil
+	}
 	for _, t := range plan.Tasks {
 		status := t.Status
 		if strings.TrimSpace(status) == "" {
 			status = "pending"
 		}
-		fmt.Printf("- %s [%s] %s\n", t.ID, status, t.Title)
+		fmt.Printf("- %s [%s] %s%s\n", t.ID, style.Status.Sprint(status), t.Title, taskAttributes(t.Labels, t.Fields))
+	}
+	return nil
+}
+
+func runPlanExport(cmd *cobra.Command, args []string) error {
+	if strings.TrimSpace(goalID) == "" {
+		return fmt.Errorf("goal id is required")
+	}
+
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	plan, err := app.PlanManager.Load(goalID)
+	if err != nil {
+		return fmt.Errorf("failed to load plan: %w", err)
+	}
+	data, err := core.ExportPlan(plan, planExportFmt)
+	if err != nil {
+		return err
+	}
+
+	if planExportOut == "" {
+		_, err := stdout.Write(data)
+		return err
+	}
+	if err := ioutil.WriteFile(planExportOut, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
 	}
+	porcelainFile("written", planExportOut)
+	style.Success.Printf("Exported %d task(s) from goal %s to %s\n", len(plan.Tasks), goalID, planExportOut)
 	return nil
 }
 
@@ -355,7 +1069,7 @@ func runPlanComplete(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("task id is required")
 	}
 
-	app, err := core.NewApp(core.AppOptions{
+	app, err := newApp(core.AppOptions{
 		SpecsDir:   specsBaseDir,
 		GoalsDir:   goalsBaseDir,
 		ChangesDir: changesBaseDir,
@@ -369,28 +1083,80 @@ func runPlanComplete(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load plan: %w", err)
 	}
 
-	found := false
-	for i := range plan.Tasks {
-		if strings.EqualFold(plan.Tasks[i].ID, taskID) {
-			plan.Tasks[i].Status = "completed"
-			found = true
-			break
+	if err := app.PlanManager.CompleteTask(plan, taskID, forceComplete); err != nil {
+		if _, ok := err.(*custom_errors.ErrNotFound); ok {
+			return fmt.Errorf("task %s not found in goal %s", taskID, goalID)
 		}
+		return err
 	}
-	if !found {
-		return fmt.Errorf("task %s not found in goal %s", taskID, goalID)
+	if t := findPlanTask(plan, taskID); t != nil {
+		t.CompletedBy = strings.TrimSpace(authorName)
 	}
 
 	if err := app.PlanManager.Save(plan); err != nil {
 		return fmt.Errorf("failed to save plan: %w", err)
 	}
+	if t := findPlanTask(plan, taskID); t != nil {
+		porcelainTask(goalID, *t)
+	}
+
+	style.Success.Printf("Marked task %s as completed for goal %s\n", taskID, goalID)
+	return nil
+}
+
+func runPlanComment(cmd *cobra.Command, args []string) error {
+	if strings.TrimSpace(goalID) == "" {
+		return fmt.Errorf("goal id is required")
+	}
+	message := strings.TrimSpace(strings.Join(args, " "))
+	if message == "" {
+		return fmt.Errorf("comment cannot be empty")
+	}
+
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	plan, err := app.PlanManager.Load(goalID)
+	if err != nil {
+		return fmt.Errorf("failed to load plan: %w", err)
+	}
+	task := findPlanTask(plan, taskID)
+	if task == nil {
+		return fmt.Errorf("task %s not found in goal %s", taskID, goalID)
+	}
+
+	entry := model.DiscussionEntry{
+		Type:    core.DiscussionTypeComment,
+		Content: message,
+		TaskID:  task.ID,
+	}
+	if err := app.DiscussionManager.AddEntry(goalID, &entry); err != nil {
+		return fmt.Errorf("failed to add comment: %w", err)
+	}
+	porcelainEntry(goalID, entry)
+
+	style.Success.Printf("Added comment %s to task %s in goal %s\n", entry.ID, task.ID, goalID)
+	return nil
+}
 
-	color.New(color.FgGreen).Printf("Marked task %s as completed for goal %s\n", taskID, goalID)
+// findPlanTask returns the task with the given ID (case-insensitive), or nil.
+func findPlanTask(plan *model.Plan, id string) *model.Task {
+	for i := range plan.Tasks {
+		if strings.EqualFold(plan.Tasks[i].ID, strings.TrimSpace(id)) {
+			return &plan.Tasks[i]
+		}
+	}
 	return nil
 }
 
 func runChangeList(cmd *cobra.Command, args []string) error {
-	app, err := core.NewApp(core.AppOptions{
+	app, err := newApp(core.AppOptions{
 		SpecsDir:   specsBaseDir,
 		GoalsDir:   goalsBaseDir,
 		ChangesDir: changesBaseDir,
@@ -398,60 +1164,1598 @@ func runChangeList(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to init app: %w", err)
 	}
-	changes, err := app.ChangeManager.ListChanges()
+	filter := core.ChangeFilter{
+		Status:    filterStatus,
+		GoalID:    filterGoal,
+		Domain:    filterDomain,
+		Sort:      sortKey,
+		Order:     sortOrder,
+		Milestone: strings.TrimSpace(milestoneName),
+		CreatedBy: strings.TrimSpace(filterCreatedBy),
+	}
+	if filterSince != "" {
+		if filter.Since, err = core.ParseSince(filterSince, time.Now()); err != nil {
+			return err
+		}
+	}
+	changes, err := app.ChangeManager.ListChangesFiltered(filter)
 	if err != nil {
 		return fmt.Errorf("failed to list changes: %w", err)
 	}
 
 	if len(changes) == 0 {
-		color.Yellow("No changes found.")
+		printLine(style.Warn, "No changes found.")
 		return nil
 	}
 
-	ok := color.New(color.FgGreen, color.Bold)
+	ok := style.Header
 	ok.Printf("Found %d change(s):\n", len(changes))
 	for _, ch := range changes {
-		title := color.New(color.FgWhite, color.Bold)
+		porcelainChange(ch)
+		title := style.ID
 		title.Printf("- ID: %s\n", ch.ID)
 		fmt.Printf("  Title: %s\n", ch.Title)
 		fmt.Printf("  Status: %s\n", ch.Status)
+		if ch.GoalID != "" {
+			fmt.Printf("  Goal: %s\n", ch.GoalID)
+		}
+		if !ch.CreatedAt.IsZero() {
+			fmt.Printf("  Created: %s\n", displayTime(ch.CreatedAt, "2006-01-02 15:04"))
+		}
+		if ch.ClonedFrom != "" {
+			fmt.Printf("  Cloned from: %s\n", ch.ClonedFrom)
+		}
+		if ch.Milestone != "" {
+			fmt.Printf("  Milestone: %s\n", ch.Milestone)
+		}
+		if ch.Author != "" {
+			fmt.Printf("  Created by: %s\n", ch.Author)
+		}
 		fmt.Printf("  Spec deltas: %d\n", len(ch.SpecDeltas))
 	}
 	return nil
 }
 
 func runChangeApply(cmd *cobra.Command, args []string) error {
-	if strings.TrimSpace(changeID) == "" {
-		return fmt.Errorf("change id is required")
+	milestone := strings.TrimSpace(milestoneName)
+	modes := 0
+	for _, set := range []bool{applyAll, strings.TrimSpace(changeID) != "", milestone != ""} {
+		if set {
+			modes++
+		}
+	}
+	if modes != 1 {
+		return fmt.Errorf("exactly one of --id, --all or --milestone is required")
 	}
 
-	app, err := core.NewApp(core.AppOptions{
+	app, err := newApp(core.AppOptions{
 		SpecsDir:   specsBaseDir,
 		GoalsDir:   goalsBaseDir,
 		ChangesDir: changesBaseDir,
+		CharterDir: charterBaseDir,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to init app: %w", err)
 	}
+	if !core.ValidMergeStrategy(applyStrategy) {
+		return fmt.Errorf("unknown --strategy %q (expected %s)", applyStrategy, strings.Join(core.MergeStrategies, " or "))
+	}
+	target := core.ApplyTarget{Domains: applyDomains, Ops: applyOps, Strategy: strings.ToLower(applyStrategy)}
+	if applyAll || milestone != "" {
+		batch := "--all"
+		if milestone != "" {
+			batch = "--milestone"
+		}
+		if len(target.Domains) > 0 || len(target.Ops) > 0 {
+			return fmt.Errorf("--domain and --op cannot be combined with %s", batch)
+		}
+		if target.Strategy == core.MergeStrategyMarkers {
+			return fmt.Errorf("--strategy markers cannot be combined with %s", batch)
+		}
+		if milestone != "" {
+			return applyMilestone(app, milestone)
+		}
+		return applyAllChanges(app, core.ChangeFilter{})
+	}
 
 	ch, err := app.ChangeManager.ReadChange(changeID)
 	if err != nil {
 		return fmt.Errorf("failed to read change: %w", err)
 	}
-	if err := app.ChangeManager.ApplyChange(ch); err != nil {
+	if err := checkChangeApprovals(app, ch); err != nil {
+		return err
+	}
+	if err := warnDeprecatedChangeIDs(app, ch); err != nil {
+		return err
+	}
+	conflicted := len(ch.Conflicts)
+	if err := app.ChangeManager.ApplyChangeTargets(ch, target); err != nil {
 		return fmt.Errorf("failed to apply change: %w", err)
 	}
+	porcelainChange(ch)
+	printMergeConflicts(ch, ch.Conflicts[conflicted:])
+
+	remaining := core.RemainingOperations(ch)
+	if len(remaining) > 0 {
+		style.Success.Printf("Partially applied change %s: %s\n", ch.ID, ch.Title)
+		fmt.Println("Remaining operations:")
+		for _, d := range remaining {
+			for _, op := range d.Operations {
+				porcelain("remaining", ch.ID, d.Domain, op.Type, op.Requirement.ID)
+				fmt.Printf("  %s %s/%s\n", op.Type, d.Domain, op.Requirement.ID)
+			}
+		}
+		fmt.Printf("Run 'teamwerx change apply --id %s' to apply the rest.\n", ch.ID)
+		return nil
+	}
+	if err := dequeueChange(app, ch.ID); err != nil {
+		return err
+	}
+
+	style.Success.Printf("Applied change %s: %s\n", ch.ID, ch.Title)
+	return nil
+}
+
+func runChangeArchive(cmd *cobra.Command, args []string) error {
+	if strings.TrimSpace(changeID) == "" {
+		return fmt.Errorf("change id is required")
+	}
+
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	ch, err := app.ChangeManager.ReadChange(changeID)
+	if err != nil {
+		return fmt.Errorf("failed to read change: %w", err)
+	}
+	if err := app.ChangeManager.ArchiveChange(ch); err != nil {
+		return fmt.Errorf("failed to archive change: %w", err)
+	}
+	porcelainChange(ch)
+
+	style.Success.Printf("Archived change %s: %s\n", ch.ID, ch.Title)
+	return nil
+}
+
+// change resolve: collect every ErrDiverged conflict in a single pre-flight pass,
+// show them as a table, let the user pick which domains to refresh (the rest are
+// skipped), then apply the change once. Saves any updates to the change file on success.
+func runChangeResolve(cmd *cobra.Command, args []string) error {
+	if strings.TrimSpace(changeID) == "" {
+		return fmt.Errorf("change id is required")
+	}
+
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+		CharterDir: charterBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	ch, err := app.ChangeManager.ReadChange(changeID)
+	if err != nil {
+		return fmt.Errorf("failed to read change: %w", err)
+	}
+	_, err = resolveChange(app, ch)
+	return err
+}
+
+// resolveStrategies are the answers TEAMWERX_RESOLVE_STRATEGY can script:
+// refresh every conflicting domain, skip them all, or abort.
+var resolveStrategies = []string{"refresh", "skip", "abort"}
+
+// resolveChange runs the interactive conflict resolution for ch and applies it.
+// It reports whether the change was applied; a change that was applied leaves
+// the needs-resolution queue.
+func resolveChange(app *core.App, ch *model.Change) (bool, error) {
+	if err := checkChangeApprovals(app, ch); err != nil {
+		return false, err
+	}
+
+	diverged, err := core.FindDivergedDeltas(app.SpecManager, ch)
+	if err != nil {
+		return false, fmt.Errorf("failed to check for conflicts: %w", err)
+	}
+
+	if len(diverged) > 0 {
+		warn := style.Warn
+		warn.Printf("Conflicts detected in %d domain(s):\n", len(diverged))
+		fmt.Printf("  %-20s %-16s %-16s %s\n", "DOMAIN", "BASE", "CURRENT", "REQUIREMENTS")
+		for _, d := range diverged {
+			porcelain("conflict", ch.ID, d.Domain, d.BaseFingerprint, d.CurrentFingerprint, strconv.Itoa(d.Requirements))
+		}
+		items := make([]string, len(diverged))
+		defaults := make([]bool, len(diverged))
+		for i, d := range diverged {
+			fmt.Printf("  %-20s %-16s %-16s %d\n", d.Domain, d.BaseFingerprint, d.CurrentFingerprint, d.Requirements)
+			items[i] = d.Domain
+			defaults[i] = true
+		}
+
+		strategy, scripted, perr := promptutil.Scripted(promptutil.EnvResolveStrategy, resolveStrategies)
+		if perr != nil {
+			return false, perr
+		}
+		var refresh []int
+		switch {
+		case strategy == "abort":
+			return false, fmt.Errorf("conflicts in %d domain(s); aborted by %s", len(diverged), promptutil.EnvResolveStrategy)
+		case strategy == "refresh":
+			for i := range diverged {
+				refresh = append(refresh, i)
+			}
+		case !scripted:
+			if refresh, perr = promptutil.MultiSelect("Select domains to refresh (unselected domains are skipped)", items, defaults); perr != nil {
+				return false, fmt.Errorf("prompt failed: %w", perr)
+			}
+		}
+		refreshed := map[string]string{}
+		for _, idx := range refresh {
+			refreshed[diverged[idx].Domain] = diverged[idx].CurrentFingerprint
+		}
+		skipped := map[string]bool{}
+		for _, d := range diverged {
+			if _, ok := refreshed[d.Domain]; !ok {
+				skipped[d.Domain] = true
+			}
+		}
+
+		proceed, perr := promptutil.Confirm(fmt.Sprintf("Apply with %d refreshed and %d skipped domain(s)?", len(refreshed), len(skipped)), true)
+		if perr != nil {
+			return false, fmt.Errorf("prompt failed: %w", perr)
+		}
+		if !proceed {
+			printLine(style.Warn, "Cancelled by user. No changes were applied.")
+			return false, nil
+		}
+
+		pruned := ch.SpecDeltas[:0]
+		for _, d := range ch.SpecDeltas {
+			if skipped[d.Domain] {
+				continue
+			}
+			if fp, ok := refreshed[d.Domain]; ok {
+				d.BaseFingerprint = fp
+				// The author accepted the current spec: it is the new base.
+				if spec, err := app.SpecManager.ReadSpec(d.Domain); err == nil {
+					for i := range d.Operations {
+						d.Operations[i].Base = ""
+					}
+					core.SnapshotDeltaBases(spec, &d)
+				}
+			}
+			pruned = append(pruned, d)
+		}
+		ch.SpecDeltas = pruned
+		if len(ch.SpecDeltas) == 0 {
+			printLine(style.Warn, "All deltas skipped; nothing to apply.")
+			_ = app.ChangeManager.Save(ch)
+			return false, nil
+		}
+	}
+
+	if err := app.ChangeManager.ApplyChange(ch); err != nil {
+		return false, fmt.Errorf("failed to apply change: %w", err)
+	}
+	style.Success.Printf("Applied change %s: %s\n", ch.ID, ch.Title)
+	// Persist updated change (e.g., refreshed BaseFingerprints or pruned deltas)
+	_ = app.ChangeManager.Save(ch)
+	porcelainChange(ch)
+	return true, dequeueChange(app, ch.ID)
+}
+
+func runChangeSplit(cmd *cobra.Command, args []string) error {
+	if strings.TrimSpace(changeID) == "" {
+		return fmt.Errorf("change id is required")
+	}
+	if !splitByDomain {
+		return fmt.Errorf("a split strategy is required (use --by-domain)")
+	}
+
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	ch, err := app.ChangeManager.ReadChange(changeID)
+	if err != nil {
+		return fmt.Errorf("failed to read change: %w", err)
+	}
+	parts, err := app.ChangeManager.SplitChange(ch)
+	if err != nil {
+		return fmt.Errorf("failed to split change: %w", err)
+	}
+
+	style.Success.Printf("Split change %s into %d change(s):\n", ch.ID, len(parts))
+	for _, p := range parts {
+		porcelainChange(p)
+		fmt.Printf("- %s: %s\n", p.ID, p.Title)
+	}
+	return nil
+}
+
+func runChangeMerge(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	merged, err := app.ChangeManager.MergeChanges(args, strings.TrimSpace(mergeIntoID), changeTitle)
+	if err != nil {
+		return fmt.Errorf("failed to merge changes: %w", err)
+	}
+	porcelainChange(merged)
+
+	style.Success.Printf("Merged %s into %s: %s\n", strings.Join(args, ", "), merged.ID, merged.Title)
+	fmt.Printf("  Spec deltas: %d\n", len(merged.SpecDeltas))
+	return nil
+}
+
+func runChangeClone(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	id := strings.TrimSpace(cloneAsID)
+	if id == "" {
+		changes, err := app.ChangeManager.ListChanges()
+		if err != nil {
+			return fmt.Errorf("failed to list changes: %w", err)
+		}
+		id = core.NextChangeID(changes)
+	}
+	clone, err := app.ChangeManager.CloneChange(strings.TrimSpace(args[0]), id)
+	if err != nil {
+		return fmt.Errorf("failed to clone change: %w", err)
+	}
+
+	createdContext[core.LocalChange] = clone.ID
+	porcelainChange(clone)
+	style.Success.Printf("Cloned %s into %s: %s\n", clone.ClonedFrom, clone.ID, clone.Title)
+	fmt.Printf("  Status: %s\n", clone.Status)
+	fmt.Printf("  Spec deltas: %d\n", len(clone.SpecDeltas))
+	return nil
+}
+
+func runChangeSubmit(cmd *cobra.Command, args []string) error {
+	if strings.TrimSpace(changeID) == "" {
+		return fmt.Errorf("change id is required")
+	}
+
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+		CharterDir: charterBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	ch, err := app.ChangeManager.ReadChange(changeID)
+	if err != nil {
+		return fmt.Errorf("failed to read change: %w", err)
+	}
+	if err := core.ValidateChangeDeltas(app.SpecManager, ch); err != nil {
+		printDeltaIssues(err)
+		return err
+	}
+	owners, err := app.Owners()
+	if err != nil {
+		return fmt.Errorf("failed to load owners: %w", err)
+	}
+	if err := saveChangeMerging(app, ch, func(c *model.Change) error {
+		return core.SubmitChange(owners, c)
+	}); err != nil {
+		return err
+	}
+
+	porcelainChange(ch)
+	for _, domain := range sortedDomains(ch.RequiredApprovers) {
+		porcelain("approver", ch.ID, domain, strings.Join(ch.RequiredApprovers[domain], ","))
+	}
+	style.Success.Printf("Submitted change %s: %s\n", ch.ID, ch.Title)
+	if len(ch.RequiredApprovers) == 0 {
+		fmt.Println("  No owned domains; no approvals required.")
+		return nil
+	}
+	for _, domain := range sortedDomains(ch.RequiredApprovers) {
+		fmt.Printf("  %s: requires one of %s\n", domain, strings.Join(ch.RequiredApprovers[domain], ", "))
+	}
+	return nil
+}
+
+func runChangeValidate(cmd *cobra.Command, args []string) error {
+	if strings.TrimSpace(changeID) == "" {
+		return fmt.Errorf("change id is required")
+	}
+
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	ch, err := app.ChangeManager.ReadChange(changeID)
+	if err != nil {
+		return fmt.Errorf("failed to read change: %w", err)
+	}
+	if err := warnDeprecatedChangeIDs(app, ch); err != nil {
+		return err
+	}
+	if err := core.ValidateChangeDeltas(app.SpecManager, ch); err != nil {
+		printDeltaIssues(err)
+		return err
+	}
+
+	porcelainChange(ch)
+	style.Success.Printf("Change %s is valid (%d delta(s))\n", ch.ID, len(ch.SpecDeltas))
+	return nil
+}
+
+// saveChangeMerging applies edit to ch and saves it. If change.json was modified
+// by someone else since it was read (ErrDiverged), the change is reloaded and
+// edit is replayed once on top of the newer contents before saving again.
+func saveChangeMerging(app *core.App, ch *model.Change, edit func(*model.Change) error) error {
+	if err := edit(ch); err != nil {
+		return err
+	}
+	err := app.ChangeManager.Save(ch)
+	if _, ok := err.(*custom_errors.ErrDiverged); ok {
+		printLine(style.Warn, "Change %s was modified concurrently; reloading and reapplying.", ch.ID)
+		if err := app.ChangeManager.Reload(ch); err != nil {
+			return fmt.Errorf("failed to reload change: %w", err)
+		}
+		if err := edit(ch); err != nil {
+			return err
+		}
+		err = app.ChangeManager.Save(ch)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save change: %w", err)
+	}
+	return nil
+}
+
+func runChangeCreate(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	// Without --id the template is built under a provisional ID and
+	// CreateChange allocates the real one when the change is saved.
+	id := strings.TrimSpace(changeID)
+	allocate := id == ""
+	if allocate {
+		id = "CH-new"
+	} else if _, err := app.ChangeManager.ReadChange(id); err == nil {
+		return fmt.Errorf("change %s already exists", id)
+	}
+
+	ch, err := core.NewChangeFromTemplate(app.SpecManager, changeTemplate, core.ChangeTemplateOptions{
+		ID:            id,
+		GoalID:        strings.TrimSpace(goalID),
+		Domain:        changeDomain,
+		Title:         changeTitle,
+		RequirementID: requirementArg(app, changeDomain, changeReqID),
+		SplitInto:     splitInto,
+	})
+	if err != nil {
+		return err
+	}
+	if err := core.ValidateChangeDeltas(app.SpecManager, ch); err != nil {
+		printDeltaIssues(err)
+		return err
+	}
+	description := strings.TrimSpace(changeDesc)
+	if description == "" {
+		if description, err = promptutil.Editor(fmt.Sprintf("Description of %s (why is it needed?)", ch.Title), ""); err != nil {
+			return fmt.Errorf("failed to prompt for description: %w", err)
+		}
+	}
+	if allocate {
+		if ch, err = app.ChangeManager.CreateChange(ch.Title, ch.GoalID, ch.SpecDeltas); err != nil {
+			return fmt.Errorf("failed to create change: %w", err)
+		}
+	}
+	ch.Description = description
+	ch.Author = strings.TrimSpace(authorName)
+	ch.Provenance = changeProvenance()
+	if err := app.ChangeManager.Save(ch); err != nil {
+		return fmt.Errorf("failed to save change: %w", err)
+	}
+
+	createdContext[core.LocalChange] = ch.ID
+	porcelainChange(ch)
+	style.Success.Printf("Created change %s: %s\n", ch.ID, ch.Title)
+	for _, d := range ch.SpecDeltas {
+		for _, op := range d.Operations {
+			fmt.Printf("  %s %s/%s\n", op.Type, d.Domain, op.Requirement.ID)
+		}
+	}
+	fmt.Printf("Edit %s, then run: teamwerx change submit --id %s\n", app.Options.Layout.ChangePath(app.Options.ChangesDir, ch.ID), ch.ID)
+	return nil
+}
+
+func runGoalCreate(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{
+		GoalsDir: goalsBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	var charter *model.Charter
+	if app.CharterManager.Exists() {
+		if charter, err = app.CharterManager.ReadEffective(); err != nil {
+			return fmt.Errorf("failed to read charter: %w", err)
+		}
+	}
+	tmpl, err := core.ResolveGoalTemplate(goalTemplate, charter)
+	if err != nil {
+		return err
+	}
+
+	id := strings.TrimSpace(goalID)
+	if id == "" {
+		existing, err := core.ListGoalIDs(app.Options)
+		if err != nil {
+			return fmt.Errorf("failed to list goals: %w", err)
+		}
+		id = core.NextGoalID(existing, goalTitle)
+	}
+
+	plan, err := core.CreateGoal(app.PlanManager, app.DiscussionManager, id, goalTitle, tmpl)
+	if err != nil {
+		return err
+	}
+
+	createdContext[core.LocalGoal] = id
+	porcelainGoal(plan)
+	style.Success.Printf("Created goal %s from the %s template\n", id, tmpl.Name)
+	for _, t := range plan.Tasks {
+		porcelainTask(id, t)
+		fmt.Printf("  %s %s\n", t.ID, t.Title)
+	}
+	fmt.Printf("Answer the guiding questions with: teamwerx discuss add --goal %s\n", id)
+	return nil
+}
+
+func runGoalList(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{
+		GoalsDir: goalsBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+	if goalListBoard {
+		return runGoalBoard(app)
+	}
+	if goalListJSON || goalListAll {
+		return fmt.Errorf("--json and --all require --board")
+	}
+
+	ids, err := core.ListGoalIDs(app.Options)
+	if err != nil {
+		return fmt.Errorf("failed to list goals: %w", err)
+	}
+	if len(ids) == 0 {
+		fmt.Println("No goals found.")
+		return nil
+	}
+	for _, id := range ids {
+		plan, err := app.PlanManager.Load(id)
+		if err != nil {
+			if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+				return fmt.Errorf("failed to load plan for %s: %w", id, err)
+			}
+			porcelainGoal(&model.Plan{GoalID: id})
+			fmt.Printf("%-30s (no plan)\n", id)
+			continue
+		}
+		porcelainGoal(plan)
+		line := fmt.Sprintf("%-30s %s", id, core.FormatPlanProgress(plan.Progress))
+		if plan.Status == core.PlanStatusCompleted {
+			style.Success.Printf("%s  [completed]\n", line)
+			continue
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func runGoalStatus(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{
+		GoalsDir: goalsBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	plan, err := app.PlanManager.Load(goalID)
+	if err != nil {
+		return fmt.Errorf("failed to load plan: %w", err)
+	}
+
+	porcelainGoal(plan)
+	for _, t := range core.OpenTasks(plan) {
+		porcelainTask(plan.GoalID, t)
+	}
+	style.Header.Printf("Goal %s\n", plan.GoalID)
+	status := "active"
+	if plan.Status == core.PlanStatusCompleted {
+		status = "completed"
+		if plan.CompletedAt != nil {
+			status += " " + displayTime(*plan.CompletedAt, time.RFC3339)
+		}
+	}
+	fmt.Printf("  Status:      %s\n", status)
+	p := plan.Progress
+	fmt.Printf("  Progress:    %s\n", core.FormatPlanProgress(p))
+	fmt.Printf("  In progress: %d, pending: %d\n", p.InProgress, p.Pending)
+
+	if p.Blocked > 0 {
+		printLine(style.Warn, "Blocked tasks:")
+		printBlockedTasks(plan, "  - ")
+	}
+	var open []model.Task
+	for _, t := range core.OpenTasks(plan) {
+		if t.Status != core.TaskStatusBlocked {
+			open = append(open, t)
+		}
+	}
+	if len(open) == 0 {
+		return nil
+	}
+	fmt.Println("Open tasks:")
+	for _, t := range open {
+		s := strings.TrimSpace(t.Status)
+		if s == "" {
+			s = core.TaskStatusPending
+		}
+		fmt.Printf("  - %s [%s] %s\n", t.ID, s, t.Title)
+	}
+	return nil
+}
+
+func runGoalComplete(cmd *cobra.Command, args []string) error {
+	if len(waiveTasks) > 0 && strings.TrimSpace(waiveReason) == "" {
+		return fmt.Errorf("--reason is required when waiving tasks")
+	}
+
+	app, err := newApp(core.AppOptions{
+		GoalsDir: goalsBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	plan, err := app.PlanManager.Load(goalID)
+	if err != nil {
+		return fmt.Errorf("failed to load plan: %w", err)
+	}
+	for _, id := range waiveTasks {
+		if err := app.PlanManager.WaiveTask(plan, id, waiveReason); err != nil {
+			return err
+		}
+	}
+	if err := core.CompleteGoal(plan); err != nil {
+		return err
+	}
+	if err := app.PlanManager.Save(plan); err != nil {
+		return fmt.Errorf("failed to save plan: %w", err)
+	}
+
+	porcelainGoal(plan)
+	style.Success.Printf("Completed goal %s: %s\n", plan.GoalID, core.FormatPlanProgress(plan.Progress))
+	return recordRetrospective(cmd, app, plan.GoalID)
+}
+
+// recordRetrospective adds the retrospective from the flags, or the prompts
+// when none were given, to a completed goal and spawns its follow-up goals.
+func recordRetrospective(cmd *cobra.Command, app *core.App, goalID string) error {
+	retro := core.Retrospective{Worked: retroWorked, DidntWork: retroDidntWork, FollowUps: retroFollowUps}
+	if retro.Empty() {
+		for _, q := range []struct {
+			label string
+			items *[]string
+		}{
+			{"What worked? (separate items with ;)", &retro.Worked},
+			{"What didn't work?", &retro.DidntWork},
+			{"Follow-ups?", &retro.FollowUps},
+		} {
+			answer, err := promptutil.Input(q.label, "")
+			if err != nil {
+				return err
+			}
+			for _, item := range strings.Split(answer, ";") {
+				if item = strings.TrimSpace(item); item != "" {
+					*q.items = append(*q.items, item)
+				}
+			}
+		}
+	}
+	if retro.Empty() {
+		return nil
+	}
+	entry, err := app.RecordRetrospective(goalID, authorName, retro)
+	if err != nil {
+		return fmt.Errorf("failed to record retrospective: %w", err)
+	}
+	porcelainEntry(goalID, *entry)
+	fmt.Printf("  Recorded retrospective %s\n", entry.ID)
+
+	if len(retro.FollowUps) == 0 {
+		return nil
+	}
+	spawn := spawnFollowUps
+	if !cmd.Flags().Changed("spawn-follow-ups") {
+		if spawn, err = promptutil.Confirm("Create a goal for each follow-up?", false); err != nil {
+			return err
+		}
+	}
+	if !spawn {
+		return nil
+	}
+	var charter *model.Charter
+	if app.CharterManager.Exists() {
+		if charter, err = app.CharterManager.ReadEffective(); err != nil {
+			return fmt.Errorf("failed to read charter: %w", err)
+		}
+	}
+	tmpl, err := core.ResolveGoalTemplate(goalTemplate, charter)
+	if err != nil {
+		return err
+	}
+	plans, err := app.SpawnFollowUpGoals(goalID, retro, tmpl)
+	for _, p := range plans {
+		porcelainGoal(p)
+		printLine(style.Success, "  Created follow-up goal %s", p.GoalID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create follow-up goals: %w", err)
+	}
+	return nil
+}
+
+func runChangeReload(cmd *cobra.Command, args []string) error {
+	if strings.TrimSpace(changeID) == "" {
+		return fmt.Errorf("change id is required")
+	}
+
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	ch, err := app.ChangeManager.ReadChange(changeID)
+	if err != nil {
+		return fmt.Errorf("failed to read change: %w", err)
+	}
+
+	porcelainChange(ch)
+	for _, a := range ch.Approvals {
+		porcelain("approval", ch.ID, a.Domain, a.Approver, porcelainTime(a.ApprovedAt))
+	}
+	style.Success.Printf("Reloaded change %s: %s\n", ch.ID, ch.Title)
+	fmt.Printf("  Fingerprint: %s\n", ch.LoadedFingerprint)
+	fmt.Printf("  Status:      %s\n", ch.Status)
+	fmt.Printf("  Deltas:      %d\n", len(ch.SpecDeltas))
+	for _, a := range ch.Approvals {
+		fmt.Printf("  Approved %s by %s at %s\n", a.Domain, a.Approver, displayTime(a.ApprovedAt, time.RFC3339))
+	}
+	open, err := core.UnresolvedConflicts(app.SpecManager, ch)
+	if err != nil {
+		return fmt.Errorf("failed to check conflicts: %w", err)
+	}
+	printMergeConflicts(ch, open)
+	return nil
+}
+
+// printMergeConflicts lists requirements written with conflict markers.
+func printMergeConflicts(ch *model.Change, conflicts []model.MergeConflict) {
+	if len(conflicts) == 0 {
+		return
+	}
+	printLine(style.Warn, "%d requirement(s) need resolving; edit the specs and remove the conflict markers:", len(conflicts))
+	for _, c := range conflicts {
+		porcelain("marker", ch.ID, c.Domain, c.RequirementID, porcelainTime(c.RecordedAt))
+		fmt.Printf("  %s/%s\n", c.Domain, c.RequirementID)
+	}
+}
+
+func runChangeImpact(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	ch, err := app.ChangeManager.ReadChange(changeID)
+	if err != nil {
+		return fmt.Errorf("failed to read change: %w", err)
+	}
+	impact, err := core.AnalyzeChangeImpact(app, ch)
+	if err != nil {
+		return fmt.Errorf("failed to analyze change: %w", err)
+	}
+
+	hdr := style.Header
+	subtle := style.Muted
+	hdr.Printf("Impact of change %s\n", ch.ID)
+	for _, r := range impact.Requirements {
+		porcelain("edited", r.Domain, r.RequirementID, strings.Join(r.Operations, ","), strings.Join(r.Scenarios, ","))
+	}
+	for _, r := range impact.References {
+		porcelain("reference", r.Domain, r.RequirementID, r.References, strconv.Itoa(r.Depth))
+	}
+	for _, t := range impact.Tasks {
+		porcelain("linked", t.GoalID, t.TaskID, t.Status, t.Requirement, t.Title)
+	}
+
+	fmt.Printf("\nEdited requirements (%d):\n", len(impact.Requirements))
+	for _, r := range impact.Requirements {
+		fmt.Printf("  - %s/%s [%s]\n", r.Domain, r.RequirementID, strings.Join(r.Operations, ", "))
+		if len(r.Scenarios) > 0 {
+			subtle.Printf("    scenarios: %s\n", strings.Join(r.Scenarios, ", "))
+		}
+	}
+
+	fmt.Printf("\nReferencing requirements (%d):\n", len(impact.References))
+	for _, r := range impact.References {
+		fmt.Printf("  - %s/%s → %s", r.Domain, r.RequirementID, r.References)
+		if r.Depth > 1 {
+			subtle.Printf(" (indirect, depth %d)", r.Depth)
+		}
+		fmt.Println()
+	}
+
+	fmt.Printf("\nLinked tasks (%d):\n", len(impact.Tasks))
+	for _, t := range impact.Tasks {
+		fmt.Printf("  - %s %s [%s] %s", t.GoalID, t.TaskID, t.Status, t.Title)
+		subtle.Printf(" (%s)\n", t.Requirement)
+	}
+
+	if len(impact.Domains) > 0 {
+		printLine(style.Warn, "\nCross-referenced domains not edited by this change: %s", strings.Join(impact.Domains, ", "))
+	}
+	return nil
+}
+
+func runChangeDiff(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	ch, err := app.ChangeManager.ReadChange(changeID)
+	if err != nil {
+		return fmt.Errorf("failed to read change: %w", err)
+	}
+	diffs, err := core.DiffChange(app.SpecManager, ch)
+	if err != nil {
+		return fmt.Errorf("failed to diff change: %w", err)
+	}
+	if len(diffs) == 0 {
+		fmt.Printf("Change %s has no operations.\n", ch.ID)
+		return nil
+	}
+
+	for i, d := range diffs {
+		if i > 0 {
+			fmt.Println()
+		}
+		target := d.Domain + "/" + d.RequirementID
+		if d.ScenarioID != "" {
+			target += "#" + d.ScenarioID
+		}
+		porcelain("operation", d.Domain, d.Type, d.RequirementID, d.ScenarioID, d.Note)
+		for _, seg := range d.Segments {
+			porcelain("segment", [...]string{"equal", "insert", "delete"}[seg.Op], seg.Text)
+		}
+		style.Header.Printf("%s %s\n", d.Type, target)
+		if d.Note != "" {
+			printLine(style.Warn, "  %s", d.Note)
+		}
+		printDiffSegments(d.Segments)
+	}
+	return nil
+}
+
+// printDiffSegments renders a diff. Whole-block additions and removals are
+// printed line by line with +/- prefixes; word diffs are printed inline, in
+// color when the terminal supports it and with git-style [-…-]/{+…+} markers
+// otherwise.
+func printDiffSegments(segs []core.DiffSegment) {
+	red, green := style.Error, style.Success
+	if len(segs) == 1 && segs[0].Op != core.DiffEqual {
+		c, prefix := green, "+ "
+		if segs[0].Op == core.DiffDelete {
+			c, prefix = red, "- "
+		}
+		for _, line := range strings.Split(strings.TrimRight(segs[0].Text, "\n"), "\n") {
+			c.Println(prefix + line)
+		}
+		return
+	}
+
+	var out strings.Builder
+	for _, seg := range segs {
+		switch {
+		case seg.Op == core.DiffEqual:
+			out.WriteString(seg.Text)
+		case (color.NoColor || style.NoColor) && seg.Op == core.DiffDelete:
+			out.WriteString("[-" + seg.Text + "-]")
+		case color.NoColor || style.NoColor:
+			out.WriteString("{+" + seg.Text + "+}")
+		case seg.Op == core.DiffDelete:
+			out.WriteString(style.Error.Sprint(seg.Text))
+		default:
+			out.WriteString(green.Sprint(seg.Text))
+		}
+	}
+	fmt.Println(strings.TrimRight(out.String(), "\n"))
+}
+
+// newApp wraps core.NewApp so secrets found by warn- or redact-mode scanning,
+// and organization charters read from the cache, are reported to the user.
+func newApp(opts core.AppOptions) (*core.App, error) {
+	opts.OnSecretFindings = printSecretFindings
+	opts.OnCachedCharter = printCachedCharter
+	opts.ReadOnly = opts.ReadOnly || readOnlyMode()
+	return core.NewApp(opts)
+}
+
+func printSecretFindings(source string, findings []core.SecretFinding) {
+	for _, f := range findings {
+		porcelain("secret", source, f.Rule, strconv.Itoa(f.Line), f.Preview)
+		printLine(style.Warn, "Warning: possible secret in %s at line %d: %s (%s)", source, f.Line, f.Rule, f.Preview)
+	}
+}
+
+func printCachedCharter(url string, err error) {
+	printLine(style.Warn, "Warning: could not fetch %s (%v); using the cached copy", url, err)
+}
+
+// printDeltaIssues lists field-level delta issues, one per line, when err carries them.
+func printDeltaIssues(err error) {
+	verr, ok := err.(*core.DeltaValidationError)
+	if !ok {
+		return
+	}
+	for _, issue := range verr.Issues {
+		porcelain("issue", verr.ChangeID, issue.Field, issue.Message)
+		style.Error.Printf("  %s: ", issue.Field)
+		fmt.Println(issue.Message)
+	}
+}
+
+func runChangeApprove(cmd *cobra.Command, args []string) error {
+	if strings.TrimSpace(changeID) == "" {
+		return fmt.Errorf("change id is required")
+	}
+
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+		CharterDir: charterBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	ch, err := app.ChangeManager.ReadChange(changeID)
+	if err != nil {
+		return fmt.Errorf("failed to read change: %w", err)
+	}
+	var domains []string
+	if err := saveChangeMerging(app, ch, func(c *model.Change) error {
+		var aerr error
+		domains, aerr = core.ApproveChange(c, approverName, strings.TrimSpace(approveDomain))
+		return aerr
+	}); err != nil {
+		return err
+	}
+
+	porcelainChange(ch)
+	for _, a := range ch.Approvals {
+		porcelain("approval", ch.ID, a.Domain, a.Approver, porcelainTime(a.ApprovedAt))
+	}
+	style.Success.Printf("Approved %s for domain(s): %s\n", ch.ID, strings.Join(domains, ", "))
+	if missing := core.MissingApprovals(ch); len(missing) > 0 {
+		for _, domain := range missing {
+			porcelain("awaiting", ch.ID, domain)
+		}
+		printLine(style.Warn, "Still awaiting approval for: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// checkChangeApprovals enforces owner sign-off using the workspace OWNERS file.
+func checkChangeApprovals(app *core.App, ch *model.Change) error {
+	owners, err := app.Owners()
+	if err != nil {
+		return fmt.Errorf("failed to load owners: %w", err)
+	}
+	return core.CheckApprovals(owners, ch)
+}
+
+func sortedDomains(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func runDiscussList(cmd *cobra.Command, args []string) error {
+	ref := strings.TrimSpace(discussListRef)
+	if strings.TrimSpace(goalID) == "" && ref == "" {
+		return fmt.Errorf("goal id is required")
+	}
+
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+	if ref != "" {
+		return listLinkedEntries(app, strings.TrimSpace(goalID), ref)
+	}
+
+	entries, err := app.DiscussionManager.Load(goalID)
+	if err != nil {
+		return fmt.Errorf("failed to load discussion entries: %w", err)
+	}
+	if err := core.SortDiscussionEntries(entries, sortKey, sortOrder); err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		printLine(style.Warn, "No discussion entries found for goal %s.", goalID)
+		return nil
+	}
+
+	hdr := style.Header
+	hdr.Printf("Found %d discussion entrie(s) for goal %s:\n", len(entries), goalID)
+
+	for _, e := range entries {
+		printEntrySummary(app, goalID, e)
+	}
+
+	return nil
+}
+
+// listLinkedEntries lists the entries that ref a task, change or
+// requirement: in goal's log, or in every goal when goal is empty.
+func listLinkedEntries(app *core.App, goal, ref string) error {
+	linked, err := app.EntriesReferencing(goal, ref)
+	if err != nil {
+		return fmt.Errorf("failed to load discussion entries: %w", err)
+	}
+	if len(linked) == 0 {
+		printLine(style.Warn, "No discussion entries ref %s.", ref)
+		return nil
+	}
+	hdr := style.Header
+	hdr.Printf("Found %d discussion entrie(s) referencing %s:\n", len(linked), ref)
+	for _, l := range linked {
+		if goal == "" {
+			fmt.Printf("%s ", l.Goal)
+		}
+		printEntrySummary(app, l.Goal, l.Entry)
+	}
+	return nil
+}
+
+// printEntrySummary prints one discussion entry as a single line with the
+// first line of its content, followed by its attachments.
+func printEntrySummary(app *core.App, goalID string, e model.DiscussionEntry) {
+	porcelainEntry(goalID, e)
+	for _, a := range e.Attachments {
+		porcelainAttachment(goalID, e.ID, a)
+	}
+	title := style.ID
+	title.Printf("- %s ", e.ID)
+	fmt.Printf("[%s] ", entryTypeLabel(app.DiscussionTypes, strings.TrimSpace(e.Type)))
+	if e.TaskID != "" {
+		fmt.Printf("(task %s) ", e.TaskID)
+	}
+	if e.Author != "" {
+		fmt.Printf("@%s ", e.Author)
+	}
+	if !e.Timestamp.IsZero() {
+		fmt.Printf("%s ", displayTime(e.Timestamp, time.RFC3339))
+	}
+	// Print first line of content as a preview
+	firstLine := strings.SplitN(strings.TrimSpace(e.Content), "\n", 2)[0]
+	if firstLine != "" {
+		fmt.Printf("- %s", firstLine)
+	}
+	fmt.Println()
+	if len(e.Refs) > 0 {
+		style.Muted.Printf("    refs: %s\n", strings.Join(e.Refs, ", "))
+	}
+	for _, a := range e.Attachments {
+		style.Muted.Printf("    attachment: %s (%d bytes)\n", a.Path, a.Size)
+	}
+}
+
+func runDiscussAdd(cmd *cobra.Command, args []string) error {
+	if strings.TrimSpace(goalID) == "" {
+		return fmt.Errorf("goal id is required")
+	}
+
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	refs, err := app.ResolveEntryRefs(goalID, entryRefs)
+	if err != nil {
+		return fmt.Errorf("invalid --ref: %w", err)
+	}
+
+	// Determine message content
+	message := strings.TrimSpace(strings.Join(args, " "))
+	if message == "" {
+		if v, perr := promptutil.Editor("Discussion message for goal "+goalID, ""); perr == nil {
+			message = strings.TrimSpace(v)
+		} else {
+			return fmt.Errorf("failed to prompt for message: %w", perr)
+		}
+	}
+	if message == "" {
+		return fmt.Errorf("discussion message cannot be empty")
+	}
+
+	// Determine entry type: --type, or a prompt over the registered types
+	kind := strings.TrimSpace(entryType)
+	if kind == "" {
+		kind = core.DiscussionTypeDiscussion
+		var types []string
+		for _, t := range app.DiscussionTypes.Types() {
+			if t.Name != core.DiscussionTypeSummary && t.Name != core.DiscussionTypeComment {
+				types = append(types, t.Name)
+			}
+		}
+		idx, choice, err := promptutil.SelectScripted(promptutil.EnvDefaultType, "Entry type", types, 0)
+		if err != nil {
+			return err
+		}
+		if idx >= 0 && choice != "" {
+			kind = choice
+		}
+	} else if err := app.DiscussionTypes.Validate(kind); err != nil {
+		return err
+	}
+
+	entry := model.DiscussionEntry{
+		Type:    kind,
+		Content: message,
+		Author:  strings.TrimSpace(authorName),
+		Refs:    refs,
+	}
+	for _, p := range attachPaths {
+		att, aerr := app.DiscussionManager.StoreAttachment(goalID, p)
+		if aerr != nil {
+			return fmt.Errorf("failed to attach file: %w", aerr)
+		}
+		entry.Attachments = append(entry.Attachments, *att)
+	}
+	if err := app.DiscussionManager.AddEntry(goalID, &entry); err != nil {
+		return fmt.Errorf("failed to add discussion entry: %w", err)
+	}
+	porcelainEntry(goalID, entry)
+	for _, a := range entry.Attachments {
+		porcelainAttachment(goalID, entry.ID, a)
+	}
+
+	style.Success.Printf("Added discussion entry %s to goal %s\n", entry.ID, goalID)
+	for _, a := range entry.Attachments {
+		fmt.Printf("  attached: %s\n", a.Path)
+	}
+	if len(entry.Refs) > 0 {
+		fmt.Printf("  refs: %s\n", strings.Join(entry.Refs, ", "))
+	}
+	return nil
+}
+
+func runDiscussSummarize(cmd *cobra.Command, args []string) error {
+	if strings.TrimSpace(goalID) == "" {
+		return fmt.Errorf("goal id is required")
+	}
+
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	entries, err := app.DiscussionManager.Load(goalID)
+	if err != nil {
+		return fmt.Errorf("failed to load discussion entries: %w", err)
+	}
+	pending := core.EntriesSinceSummary(entries)
+	if len(pending) == 0 {
+		printLine(style.Warn, "Nothing to summarize for goal %s since the last summary.", goalID)
+		return nil
+	}
+
+	summary := core.SummarizeDiscussion(pending)
+	if name := strings.TrimSpace(summaryPlugin); name != "" {
+		p, err := plugin.Find(name)
+		if err != nil {
+			return err
+		}
+		out, err := p.Output(pluginContext(), []string{"summarize", "--goal", goalID}, []byte(core.FormatDiscussionTranscript(pending)))
+		if err != nil {
+			return fmt.Errorf("summarizer plugin %s failed: %w", name, err)
+		}
+		summary = strings.TrimSpace(string(out))
+		if summary == "" {
+			return fmt.Errorf("summarizer plugin %s returned an empty summary", name)
+		}
+	}
+
+	if summarizeDryRun {
+		porcelain("summary", goalID, summary)
+		fmt.Println(summary)
+		return nil
+	}
+
+	entry := model.DiscussionEntry{Type: core.DiscussionTypeSummary, Content: summary}
+	if err := app.DiscussionManager.AddEntry(goalID, &entry); err != nil {
+		return fmt.Errorf("failed to add summary entry: %w", err)
+	}
+	porcelainEntry(goalID, entry)
+	style.Success.Printf("Added summary %s covering %d entrie(s) to goal %s\n", entry.ID, len(pending), goalID)
+	return nil
+}
+
+func runDiscussImport(cmd *cobra.Command, args []string) error {
+	if strings.TrimSpace(goalID) == "" {
+		return fmt.Errorf("goal id is required")
+	}
+
+	data, err := os.ReadFile(importFile)
+	if err != nil {
+		return fmt.Errorf("failed to read transcript: %w", err)
+	}
+	entries, err := core.ParseTranscript(strings.ToLower(strings.TrimSpace(importFormat)), data)
+	if err != nil {
+		return fmt.Errorf("failed to parse transcript: %w", err)
+	}
+	if len(entries) == 0 {
+		printLine(style.Warn, "No messages found in %s.", importFile)
+		return nil
+	}
+
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	for i := range entries {
+		if err := app.DiscussionManager.AddEntry(goalID, &entries[i]); err != nil {
+			return fmt.Errorf("failed to add entry %d of %d: %w", i+1, len(entries), err)
+		}
+		porcelainEntry(goalID, entries[i])
+	}
+	style.Success.Printf("Imported %d entrie(s) into goal %s (%s..%s)\n", len(entries), goalID, entries[0].ID, entries[len(entries)-1].ID)
+	return nil
+}
+
+func runDiscussContext(cmd *cobra.Command, args []string) error {
+	if strings.TrimSpace(goalID) == "" {
+		return fmt.Errorf("goal id is required")
+	}
+
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	entries, err := app.DiscussionManager.Load(goalID)
+	if err != nil {
+		return fmt.Errorf("failed to load discussion entries: %w", err)
+	}
+	context := core.DiscussionContext(entries)
+	for _, e := range context {
+		porcelainEntry(goalID, e)
+	}
+	fmt.Print(core.FormatDiscussionTranscript(context))
+	return nil
+}
+
+func runDiscussShow(cmd *cobra.Command, args []string) error {
+	if strings.TrimSpace(goalID) == "" {
+		return fmt.Errorf("goal id is required")
+	}
+	entryID := strings.TrimSpace(args[0])
+
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	entries, err := app.DiscussionManager.Load(goalID)
+	if err != nil {
+		return fmt.Errorf("failed to load discussion entries: %w", err)
+	}
+
+	var entry *model.DiscussionEntry
+	for i := range entries {
+		if strings.EqualFold(entries[i].ID, entryID) {
+			entry = &entries[i]
+			break
+		}
+	}
+	if entry == nil {
+		return fmt.Errorf("discussion entry %s not found in goal %s", entryID, goalID)
+	}
+
+	porcelainEntry(goalID, *entry)
+	for _, a := range entry.Attachments {
+		porcelainAttachment(goalID, entry.ID, a)
+	}
+	hdr := style.Header
+	hdr.Printf("Entry %s [%s]\n", entry.ID, strings.TrimSpace(entry.Type))
+	if entry.Author != "" {
+		fmt.Printf("Author: %s\n", entry.Author)
+	}
+	if !entry.Timestamp.IsZero() {
+		fmt.Printf("Timestamp: %s\n", displayTime(entry.Timestamp, time.RFC3339))
+	}
+	if len(entry.Refs) > 0 {
+		fmt.Printf("Refs: %s\n", strings.Join(entry.Refs, ", "))
+	}
+	fmt.Printf("\n%s\n", strings.TrimSpace(entry.Content))
+
+	if len(entry.Attachments) > 0 {
+		fmt.Printf("\nAttachments (%d):\n", len(entry.Attachments))
+		for _, a := range entry.Attachments {
+			path := app.DiscussionManager.AttachmentPath(goalID, a)
+			fmt.Printf("- %s (%d bytes) %s\n", a.Name, a.Size, path)
+			if openAttach {
+				if oerr := opener.Open(path); oerr != nil {
+					printLine(style.Warn, "  could not open %s: %v", path, oerr)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func runSpecShow(cmd *cobra.Command, args []string) error {
+	domain := strings.TrimSpace(args[0])
+	if domain == "" {
+		return fmt.Errorf("domain is required")
+	}
+
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+	if len(args) == 2 && !specShowJSON {
+		return showRequirement(app, domain, strings.TrimSpace(args[1]))
+	}
+
+	spec, err := app.SpecManager.ReadSpec(domain)
+	if err != nil {
+		return fmt.Errorf("failed to read spec: %w", err)
+	}
+
+	if specShowJSON {
+		info := core.DescribeSpec(spec)
+		if len(args) == 2 {
+			id := resolveRequirementArg(spec, args[1])
+			var kept []core.RequirementInfo
+			for _, r := range info.Requirements {
+				if r.ID == id {
+					kept = append(kept, r)
+				}
+			}
+			if len(kept) == 0 {
+				return fmt.Errorf("requirement %s not found in %s", args[1], domain)
+			}
+			info.Requirements = kept
+		}
+		data, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode spec: %w", err)
+		}
+		fmt.Fprintln(stdout, string(data))
+		return nil
+	}
+
+	hdr := style.Header
+	hdr.Printf("Spec: %s\n", spec.Domain)
+	printSpecMeta("", spec.Meta)
+	fmt.Printf("Requirements: %d\n", len(spec.Requirements))
+
+	max := len(spec.Requirements)
+	if max > 10 {
+		max = 10
+	}
+	comments, err := app.ReviewManager.Load(domain)
+	if err != nil {
+		return fmt.Errorf("failed to load review comments: %w", err)
+	}
+	open := core.OpenReviewComments(comments)
+	porcelainSpec(spec)
+	porcelainSpecMeta(spec)
+	for _, r := range spec.Requirements {
+		porcelainRequirement(spec.Domain, r)
+		for _, c := range r.AcceptanceCriteria {
+			porcelainCriterion(spec.Domain, r.ID, c)
+		}
+	}
+	for _, c := range open {
+		porcelainComment(spec.Domain, c)
+	}
+	for i := 0; i < max; i++ {
+		r := spec.Requirements[i]
+		if r.Source != "" {
+			fmt.Printf("- %s (%s, from %s)\n", r.Title, r.ID, r.Source)
+		} else {
+			fmt.Printf("- %s (%s)\n", r.Title, r.ID)
+		}
+		for _, c := range r.AcceptanceCriteria {
+			mark := " "
+			if c.Done {
+				mark = "x"
+			}
+			fmt.Printf("    %d. [%s] %s\n", c.Index, mark, c.Text)
+		}
+		for _, c := range core.OpenReviewComments(open, r.ID) {
+			fmt.Print("  ")
+			printReviewComment(c)
+		}
+	}
+	if len(open) > 0 {
+		printLine(style.Warn, "Open review comments: %d", len(open))
+	}
 
-	color.New(color.FgGreen).Printf("Applied change %s: %s\n", ch.ID, ch.Title)
 	return nil
 }
 
-func runChangeArchive(cmd *cobra.Command, args []string) error {
-	if strings.TrimSpace(changeID) == "" {
-		return fmt.Errorf("change id is required")
+// showRequirement prints one requirement of domain, its acceptance
+// criteria, scenarios and open review comments.
+func showRequirement(app *core.App, domain, id string) error {
+	r, err := app.SpecManager.ReadRequirement(domain, id)
+	if err != nil {
+		return fmt.Errorf("failed to read requirement: %w", err)
+	}
+	if !strings.EqualFold(r.ID, id) {
+		warnDeprecated(core.DeprecatedRequirementID{Domain: domain, Alias: id, ID: r.ID})
+	}
+	comments, err := app.ReviewManager.Load(domain)
+	if err != nil {
+		return fmt.Errorf("failed to load review comments: %w", err)
+	}
+	open := core.OpenReviewComments(comments, r.ID)
+
+	porcelainRequirement(domain, *r)
+	for _, c := range r.AcceptanceCriteria {
+		porcelainCriterion(domain, r.ID, c)
+	}
+	for _, c := range open {
+		porcelainComment(domain, c)
+	}
+
+	style.Header.Printf("Requirement: %s (%s/%s)\n", r.Title, domain, r.ID)
+	if r.Source != "" {
+		fmt.Printf("Source:   %s\n", r.Source)
+	}
+	if r.Priority != "" {
+		fmt.Printf("Priority: %s\n", r.Priority)
+	}
+	if len(r.Code) > 0 {
+		fmt.Printf("Code:     %s\n", strings.Join(r.Code, ", "))
 	}
+	for _, c := range r.AcceptanceCriteria {
+		mark := " "
+		if c.Done {
+			mark = "x"
+		}
+		fmt.Printf("  %d. [%s] %s\n", c.Index, mark, c.Text)
+	}
+	if len(r.Scenarios) > 0 {
+		fmt.Printf("Scenarios: %d\n", len(r.Scenarios))
+		for _, sc := range r.Scenarios {
+			fmt.Printf("  - %s (%s)\n", sc.Title, sc.ID)
+		}
+	}
+	for _, c := range open {
+		printReviewComment(c)
+	}
+	if len(open) > 0 {
+		printLine(style.Warn, "Open review comments: %d", len(open))
+	}
+	return nil
+}
 
-	app, err := core.NewApp(core.AppOptions{
+func runSpecCheck(cmd *cobra.Command, args []string) error {
+	if checkDone && checkUndone {
+		return fmt.Errorf("--done and --undone are mutually exclusive")
+	}
+	var done *bool
+	if checkDone || checkUndone {
+		done = &checkDone
+	}
+
+	app, err := newApp(core.AppOptions{
 		SpecsDir:   specsBaseDir,
 		GoalsDir:   goalsBaseDir,
 		ChangesDir: changesBaseDir,
@@ -460,27 +2764,96 @@ func runChangeArchive(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to init app: %w", err)
 	}
 
-	ch, err := app.ChangeManager.ReadChange(changeID)
+	domain := strings.TrimSpace(args[0])
+	reqID := requirementArg(app, domain, args[1])
+	c, err := core.SetAcceptanceCriterion(app.SpecManager, app.SpecMerger, domain, reqID, checkItem, done)
 	if err != nil {
-		return fmt.Errorf("failed to read change: %w", err)
+		return fmt.Errorf("failed to update acceptance criterion: %w", err)
 	}
-	if err := app.ChangeManager.ArchiveChange(ch); err != nil {
-		return fmt.Errorf("failed to archive change: %w", err)
+	porcelainCriterion(domain, reqID, *c)
+
+	state := "not done"
+	if c.Done {
+		state = "done"
+	}
+	style.Success.Printf("%s/%s criterion %d is %s: %s\n", domain, reqID, c.Index, state, c.Text)
+	return nil
+}
+
+// printSpecMeta prints the front matter fields that are set, one per line.
+func printSpecMeta(indent string, meta model.SpecMeta) {
+	for _, f := range []struct{ name, value string }{
+		{"Owner", meta.Owner},
+		{"Status", meta.Status},
+		{"Tags", strings.Join(meta.Tags, ", ")},
+		{"Version", meta.Version},
+	} {
+		if f.value != "" {
+			fmt.Printf("%s%s: %s\n", indent, f.name, f.value)
+		}
+	}
+	if len(meta.Aliases) > 0 {
+		olds := make([]string, 0, len(meta.Aliases))
+		for old := range meta.Aliases {
+			olds = append(olds, old)
+		}
+		sort.Strings(olds)
+		for i, old := range olds {
+			olds[i] = old + " -> " + meta.Aliases[old]
+		}
+		fmt.Printf("%sAliases: %s\n", indent, strings.Join(olds, ", "))
+	}
+}
+
+func runSpecMetaSet(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	domain := strings.TrimSpace(args[0])
+	spec, err := app.SpecManager.ReadSpec(domain)
+	if err != nil {
+		return fmt.Errorf("failed to read spec: %w", err)
+	}
+	for _, pair := range args[1:] {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("expected key=value, got %q", pair)
+		}
+		content, err := core.SetSpecMeta(spec, key, value)
+		if err != nil {
+			return err
+		}
+		if spec, err = core.NewSpecParserWithFormat(app.Options.SpecFormat).Parse([]byte(content)); err != nil {
+			return err
+		}
+		spec.Domain = domain
+	}
+	if err := app.SpecManager.WriteSpec(spec); err != nil {
+		return fmt.Errorf("failed to write spec: %w", err)
 	}
 
-	color.New(color.FgGreen).Printf("Archived change %s: %s\n", ch.ID, ch.Title)
+	porcelainSpecMeta(spec)
+	printLine(style.Success, "Updated metadata for spec %s.", domain)
+	printSpecMeta("  ", spec.Meta)
 	return nil
 }
 
-// change resolve: interactively handle ErrDiverged conflicts by allowing the user
-// to refresh base fingerprints from current specs or skip conflicting domains, then
-// re-apply the change. Saves any updates to the change file on success.
-func runChangeResolve(cmd *cobra.Command, args []string) error {
-	if strings.TrimSpace(changeID) == "" {
-		return fmt.Errorf("change id is required")
+func runSpecReqMove(cmd *cobra.Command, args []string) error {
+	if (moveBefore == "") == (moveAfter == "") {
+		return fmt.Errorf("exactly one of --before or --after is required")
+	}
+	anchor, after := moveBefore, false
+	if moveAfter != "" {
+		anchor, after = moveAfter, true
 	}
 
-	app, err := core.NewApp(core.AppOptions{
+	app, err := newApp(core.AppOptions{
 		SpecsDir:   specsBaseDir,
 		GoalsDir:   goalsBaseDir,
 		ChangesDir: changesBaseDir,
@@ -489,129 +2862,167 @@ func runChangeResolve(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to init app: %w", err)
 	}
 
-	ch, err := app.ChangeManager.ReadChange(changeID)
+	domain := strings.TrimSpace(args[0])
+	reqID := requirementArg(app, domain, args[1])
+	anchor = requirementArg(app, domain, anchor)
+	spec, err := core.MoveRequirement(app.SpecManager, domain, reqID, anchor, after)
 	if err != nil {
-		return fmt.Errorf("failed to read change: %w", err)
+		return err
 	}
 
-	for {
-		// Attempt to apply the full change
-		if err := app.ChangeManager.ApplyChange(ch); err == nil {
-			color.New(color.FgGreen).Printf("Applied change %s: %s\n", ch.ID, ch.Title)
-			// Persist updated change (e.g., refreshed BaseFingerprints or pruned deltas)
-			_ = app.ChangeManager.Save(ch)
-			return nil
-		} else {
-			// Check for divergence conflicts
-			if de, ok := err.(*custom_errors.ErrDiverged); ok && de != nil {
-				color.Yellow("Conflict detected for domain '%s' (base=%s current=%s)", de.Domain, de.BaseFingerprint, de.CurrentFingerprint)
-
-				// Offer resolution choices
-				opts := []string{
-					"Refresh base fingerprint from current spec and retry",
-					"Skip this domain and continue",
-					"Cancel",
-				}
-				idx, _, perr := promptutil.Select("Choose a resolution", opts, 0)
-				if perr != nil {
-					return fmt.Errorf("prompt failed: %w", perr)
-				}
+	where := "before"
+	if after {
+		where = "after"
+	}
+	porcelainSpec(spec)
+	style.Success.Printf("Moved %s/%s %s %s\n", domain, reqID, where, anchor)
+	fmt.Printf("New fingerprint: %s\n", spec.Fingerprint)
+	return nil
+}
 
-				switch idx {
-				case 0: // Refresh base fingerprint and retry
-					// Read current spec to obtain its fingerprint
-					spec, rerr := app.SpecManager.ReadSpec(de.Domain)
-					if rerr != nil {
-						return fmt.Errorf("failed to read current spec for '%s': %w", de.Domain, rerr)
-					}
-					// Update BaseFingerprint for the conflicting delta
-					for i := range ch.SpecDeltas {
-						if ch.SpecDeltas[i].Domain == de.Domain {
-							ch.SpecDeltas[i].BaseFingerprint = spec.Fingerprint
-						}
-					}
-					// Loop and retry apply
-					continue
-
-				case 1: // Skip this domain and continue
-					pruned := ch.SpecDeltas[:0]
-					for i := range ch.SpecDeltas {
-						if ch.SpecDeltas[i].Domain != de.Domain {
-							pruned = append(pruned, ch.SpecDeltas[i])
-						}
-					}
-					ch.SpecDeltas = pruned
-					if len(ch.SpecDeltas) == 0 {
-						color.Yellow("All deltas skipped; nothing to apply.")
-						_ = app.ChangeManager.Save(ch)
-						return nil
-					}
-					// Loop and retry apply with remaining deltas
-					continue
+func runSpecFingerprint(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
 
-				default: // Cancel
-					color.Yellow("Cancelled by user. No changes were applied.")
-					return nil
-				}
-			}
+	var specs []*model.Spec
+	if len(args) == 1 {
+		spec, err := app.SpecManager.ReadSpec(strings.TrimSpace(args[0]))
+		if err != nil {
+			return fmt.Errorf("failed to read spec: %w", err)
+		}
+		specs = append(specs, spec)
+	} else {
+		specs, err = app.SpecManager.ListSpecs()
+		if err != nil {
+			return fmt.Errorf("failed to list specs: %w", err)
+		}
+	}
 
-			// Non-divergence error: surface to caller
-			return fmt.Errorf("failed to apply change: %w", err)
+	if len(specs) == 0 {
+		printLine(style.Warn, "No specs found.")
+		return nil
+	}
+	for _, spec := range specs {
+		porcelainSpec(spec)
+		fp := spec.Fingerprint
+		if fp == "" {
+			fp = "(empty)"
 		}
+		fmt.Printf("%s\t%s\n", spec.Domain, fp)
 	}
+	return nil
 }
 
-func runDiscussList(cmd *cobra.Command, args []string) error {
-	if strings.TrimSpace(goalID) == "" {
-		return fmt.Errorf("goal id is required")
+func runSpecCreate(cmd *cobra.Command, args []string) error {
+	domain := strings.TrimSpace(args[0])
+	if domain == "" {
+		return fmt.Errorf("domain is required")
 	}
 
-	app, err := core.NewApp(core.AppOptions{
+	app, err := newApp(core.AppOptions{
 		SpecsDir:   specsBaseDir,
 		GoalsDir:   goalsBaseDir,
 		ChangesDir: changesBaseDir,
+		CharterDir: charterBaseDir,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to init app: %w", err)
 	}
 
-	entries, err := app.DiscussionManager.Load(goalID)
-	if err != nil {
-		return fmt.Errorf("failed to load discussion entries: %w", err)
+	if _, err := app.SpecManager.ReadSpec(domain); err == nil {
+		return fmt.Errorf("spec %s already exists", domain)
+	} else if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+		return fmt.Errorf("failed to check spec: %w", err)
 	}
 
-	if len(entries) == 0 {
-		color.Yellow("No discussion entries found for goal %s.", goalID)
-		return nil
+	spec := &model.Spec{
+		Domain:  domain,
+		Content: core.ScaffoldSpec(domain, requiredSpecSections(app)),
+	}
+	if err := app.SpecManager.WriteSpec(spec); err != nil {
+		return fmt.Errorf("failed to write spec: %w", err)
 	}
 
-	hdr := color.New(color.FgGreen, color.Bold)
-	hdr.Printf("Found %d discussion entrie(s) for goal %s:\n", len(entries), goalID)
+	porcelainSpec(spec)
+	style.Success.Printf("Created spec %s\n", domain)
+	return nil
+}
 
-	for _, e := range entries {
-		title := color.New(color.FgWhite, color.Bold)
-		title.Printf("- %s ", e.ID)
-		fmt.Printf("[%s] ", strings.TrimSpace(e.Type))
-		if !e.Timestamp.IsZero() {
-			fmt.Printf("%s ", e.Timestamp.Format(time.RFC3339))
+func runSpecValidate(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+		CharterDir: charterBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	var specs []*model.Spec
+	if len(args) == 1 {
+		spec, err := app.SpecManager.ReadSpec(strings.TrimSpace(args[0]))
+		if err != nil {
+			return fmt.Errorf("failed to read spec: %w", err)
 		}
-		// Print first line of content as a preview
-		firstLine := strings.SplitN(strings.TrimSpace(e.Content), "\n", 2)[0]
-		if firstLine != "" {
-			fmt.Printf("- %s", firstLine)
+		specs = append(specs, spec)
+	} else {
+		specs, err = app.SpecManager.ListSpecs()
+		if err != nil {
+			return fmt.Errorf("failed to list specs: %w", err)
+		}
+	}
+
+	required := requiredSpecSections(app)
+	invalid := 0
+	for _, spec := range specs {
+		missing := core.MissingSpecSections(spec, required)
+		markers := core.HasConflictMarkers(spec.Content)
+		porcelainValidation(spec.Domain, missing, markers)
+		for _, w := range spec.Warnings {
+			porcelain("parse-warning", spec.Domain, w)
+			style.Warn.Printf("warn %s: %s\n", spec.Domain, w)
+		}
+		if len(missing) == 0 && !markers {
+			style.Success.Printf("ok   %s\n", spec.Domain)
+			continue
+		}
+		invalid++
+		if len(missing) > 0 {
+			style.Error.Printf("FAIL %s: missing section(s): %s\n", spec.Domain, strings.Join(missing, ", "))
+		}
+		if markers {
+			style.Error.Printf("FAIL %s: unresolved conflict markers\n", spec.Domain)
 		}
-		fmt.Println()
 	}
 
+	if invalid > 0 {
+		return fmt.Errorf("%d spec(s) missing required sections or with unresolved conflict markers", invalid)
+	}
 	return nil
 }
 
-func runDiscussAdd(cmd *cobra.Command, args []string) error {
-	if strings.TrimSpace(goalID) == "" {
-		return fmt.Errorf("goal id is required")
+func runSpecExport(cmd *cobra.Command, args []string) error {
+	format := strings.ToLower(strings.TrimSpace(exportFormat))
+	if format != "html" && format != "pdf" {
+		return fmt.Errorf("unsupported format %q (expected html or pdf)", exportFormat)
+	}
+	var wkhtmltopdf string
+	if format == "pdf" {
+		path, err := exec.LookPath("wkhtmltopdf")
+		if err != nil {
+			return fmt.Errorf("pdf export requires wkhtmltopdf on PATH: %w", err)
+		}
+		wkhtmltopdf = path
 	}
 
-	app, err := core.NewApp(core.AppOptions{
+	app, err := newApp(core.AppOptions{
 		SpecsDir:   specsBaseDir,
 		GoalsDir:   goalsBaseDir,
 		ChangesDir: changesBaseDir,
@@ -620,45 +3031,84 @@ func runDiscussAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to init app: %w", err)
 	}
 
-	// Determine message content
-	message := strings.TrimSpace(strings.Join(args, " "))
-	if message == "" {
-		if v, perr := promptutil.Input("Discussion message", ""); perr == nil {
-			message = strings.TrimSpace(v)
-		} else {
-			return fmt.Errorf("failed to prompt for message: %w", perr)
+	var specs []*model.Spec
+	if len(args) == 1 {
+		spec, err := app.SpecManager.ReadSpec(strings.TrimSpace(args[0]))
+		if err != nil {
+			return fmt.Errorf("failed to read spec: %w", err)
+		}
+		specs = append(specs, spec)
+	} else {
+		specs, err = app.SpecManager.ListSpecs()
+		if err != nil {
+			return fmt.Errorf("failed to list specs: %w", err)
 		}
 	}
-	if message == "" {
-		return fmt.Errorf("discussion message cannot be empty")
+	if len(specs) == 0 {
+		printLine(style.Warn, "No specs found.")
+		return nil
 	}
 
-	// Determine entry type (discussion|reflection)
-	entryType := "discussion"
-	types := []string{"discussion", "reflection"}
-	if idx, choice, _ := promptutil.Select("Entry type", types, 0); idx >= 0 && choice != "" {
-		entryType = choice
+	if err := os.MkdirAll(exportOutDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	entry := model.DiscussionEntry{
-		Type:    entryType,
-		Content: message,
-	}
-	if err := app.DiscussionManager.AddEntry(goalID, &entry); err != nil {
-		return fmt.Errorf("failed to add discussion entry: %w", err)
+	exporter := core.NewSpecExporter()
+	exporter.OmitNotes = exportOmitNotes
+	exporter.Format = app.Options.SpecFormat
+	pages := map[string][]byte{"index.html": exporter.RenderIndex(specs)}
+	for _, spec := range specs {
+		page, err := exporter.RenderSpec(spec)
+		if err != nil {
+			return err
+		}
+		pages[core.SpecPageName(spec.Domain)] = page
 	}
 
-	color.New(color.FgGreen).Printf("Added discussion entry %s to goal %s\n", entry.ID, goalID)
+	names := make([]string, 0, len(pages))
+	for name := range pages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		out := filepath.Join(exportOutDir, name)
+		if err := ioutil.WriteFile(out, pages[name], 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", out, err)
+		}
+		if format == "pdf" {
+			pdf := strings.TrimSuffix(out, ".html") + ".pdf"
+			if output, err := exec.Command(wkhtmltopdf, "--quiet", "--enable-local-file-access", out, pdf).CombinedOutput(); err != nil {
+				return fmt.Errorf("failed to render %s: %v: %s", pdf, err, strings.TrimSpace(string(output)))
+			}
+			out = pdf
+		}
+		porcelainFile("written", out)
+		fmt.Printf("Wrote %s\n", out)
+	}
 	return nil
 }
 
-func runSpecShow(cmd *cobra.Command, args []string) error {
-	domain := strings.TrimSpace(args[0])
-	if domain == "" {
-		return fmt.Errorf("domain is required")
+// addSortFlags registers the shared --sort/--order flags on a listing command.
+//
+// The flag defaults to "" (each Sort* function's default key) because every
+// list command shares sortKey: a per-command default would be overwritten by
+// whichever command registered last.
+func addSortFlags(cmd *cobra.Command, keys []string) {
+	cmd.Flags().StringVar(&sortKey, "sort", "", "Sort key: "+strings.Join(keys, ", ")+" (default "+keys[0]+")")
+	cmd.Flags().StringVar(&sortOrder, "order", core.SortAsc, "Sort order: asc or desc")
+}
+
+// requiredSpecSections returns the charter's spec_sections, falling back to
+// core.DefaultSpecSections when there is no charter or it defines none.
+func requiredSpecSections(app *core.App) []string {
+	if charter, err := app.CharterManager.ReadEffective(); err == nil && len(charter.SpecSections) > 0 {
+		return charter.SpecSections
 	}
+	return core.DefaultSpecSections
+}
 
-	app, err := core.NewApp(core.AppOptions{
+func runChangeStaleness(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{
 		SpecsDir:   specsBaseDir,
 		GoalsDir:   goalsBaseDir,
 		ChangesDir: changesBaseDir,
@@ -666,25 +3116,31 @@ func runSpecShow(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to init app: %w", err)
 	}
-
-	spec, err := app.SpecManager.ReadSpec(domain)
+	changes, err := app.ChangeManager.ListChanges()
 	if err != nil {
-		return fmt.Errorf("failed to read spec: %w", err)
+		return fmt.Errorf("failed to list changes: %w", err)
 	}
 
-	hdr := color.New(color.FgGreen, color.Bold)
-	hdr.Printf("Spec: %s\n", spec.Domain)
-	fmt.Printf("Requirements: %d\n", len(spec.Requirements))
-
-	max := len(spec.Requirements)
-	if max > 10 {
-		max = 10
+	report, err := core.CheckStaleness(app.SpecManager, changes)
+	if err != nil {
+		return fmt.Errorf("failed to check staleness: %w", err)
 	}
-	for i := 0; i < max; i++ {
-		r := spec.Requirements[i]
-		fmt.Printf("- %s (%s)\n", r.Title, r.ID)
+	if len(report) == 0 {
+		printLine(style.Success, "All pending changes are up to date with current specs.")
+		return nil
 	}
 
+	warn := style.Warn
+	warn.Printf("Found %d stale change(s):\n", len(report))
+	for _, r := range report {
+		title := style.ID
+		title.Printf("- %s: %s\n", r.ChangeID, r.Title)
+		for _, d := range r.Stale {
+			porcelain("stale", r.ChangeID, d.Domain, d.BaseFingerprint, d.CurrentFingerprint, strconv.Itoa(d.Requirements))
+			fmt.Printf("  %s: base=%s current=%s (%d requirement(s) affected)\n",
+				d.Domain, d.BaseFingerprint, d.CurrentFingerprint, d.Requirements)
+		}
+	}
 	return nil
 }
 
@@ -693,7 +3149,7 @@ func runPlanShow(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("goal id is required")
 	}
 
-	app, err := core.NewApp(core.AppOptions{
+	app, err := newApp(core.AppOptions{
 		SpecsDir:   specsBaseDir,
 		GoalsDir:   goalsBaseDir,
 		ChangesDir: changesBaseDir,
@@ -707,43 +3163,158 @@ func runPlanShow(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load plan: %w", err)
 	}
 
-	hdr := color.New(color.FgGreen, color.Bold)
+	if strings.TrimSpace(taskID) != "" {
+		return showPlanTask(app, plan)
+	}
+
+	printPlan(goalID, plan, showTree)
+	return nil
+}
+
+// printPlan prints a goal's plan: its progress and task table, or task tree.
+func printPlan(goalID string, plan *model.Plan, tree bool) {
+	porcelainGoal(plan)
+	for _, t := range plan.Tasks {
+		porcelainTask(goalID, t)
+	}
+	hdr := style.Header
 	hdr.Printf("Plan for goal %s\n", goalID)
 	if !plan.UpdatedAt.IsZero() {
-		fmt.Printf("Updated: %s\n", plan.UpdatedAt.Format(time.RFC3339))
+		fmt.Printf("Updated: %s\n", displayTime(plan.UpdatedAt, time.RFC3339))
+	}
+	fmt.Printf("Progress: %s\n", core.FormatPlanProgress(plan.Progress))
+	if plan.Status == core.PlanStatusCompleted && plan.CompletedAt != nil {
+		fmt.Printf("Goal completed: %s\n", displayTime(*plan.CompletedAt, time.RFC3339))
 	}
 	fmt.Printf("Tasks (%d):\n", len(plan.Tasks))
+	if tree {
+		printTaskTree(plan.Tasks)
+		return
+	}
 	for _, t := range plan.Tasks {
 		status := strings.TrimSpace(t.Status)
 		if status == "" {
 			status = "pending"
 		}
-		fmt.Printf("- %s [%s] %s\n", t.ID, status, t.Title)
+		fmt.Printf("- %s [%s] %s\n", t.ID, style.Status.Sprint(status), t.Title)
+		if t.WaiverReason != "" {
+			fmt.Printf("    waived: %s\n", t.WaiverReason)
+		}
+	}
+}
+
+// showPlanTask prints a single task followed by its comment thread.
+func showPlanTask(app *core.App, plan *model.Plan) error {
+	task := findPlanTask(plan, taskID)
+	if task == nil {
+		return fmt.Errorf("task %s not found in goal %s", taskID, goalID)
+	}
+
+	status := strings.TrimSpace(task.Status)
+	if status == "" {
+		status = "pending"
+	}
+	porcelainTask(goalID, *task)
+	hdr := style.Header
+	hdr.Printf("Task %s [%s] %s\n", task.ID, status, task.Title)
+	if task.ParentID != "" {
+		fmt.Printf("Parent: %s\n", task.ParentID)
+	}
+
+	entries, err := app.DiscussionManager.Load(goalID)
+	if err != nil {
+		return fmt.Errorf("failed to load comments: %w", err)
+	}
+	comments := core.TaskComments(entries, task.ID)
+	if len(comments) == 0 {
+		printLine(style.Warn, "No comments for task %s.", task.ID)
+	} else {
+		fmt.Printf("Comments (%d):\n", len(comments))
+		for _, c := range comments {
+			porcelainEntry(goalID, c)
+			fmt.Printf("- %s ", c.ID)
+			if !c.Timestamp.IsZero() {
+				fmt.Printf("%s ", displayTime(c.Timestamp, time.RFC3339))
+			}
+			fmt.Printf("- %s\n", strings.TrimSpace(c.Content))
+		}
 	}
 
+	linked, err := app.EntriesReferencing(goalID, task.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find linked entries: %w", err)
+	}
+	if len(linked) > 0 {
+		fmt.Printf("Linked discussion (%d):\n", len(linked))
+		for _, l := range linked {
+			printEntrySummary(app, l.Goal, l.Entry)
+		}
+	}
 	return nil
 }
 
+// printTaskTree renders tasks hierarchically using ParentID, indenting subtasks
+// under their parent. Tasks whose parent is missing are shown at the top level.
+func printTaskTree(tasks []model.Task) {
+	ids := map[string]bool{}
+	children := map[string][]model.Task{}
+	for _, t := range tasks {
+		ids[t.ID] = true
+	}
+	var roots []model.Task
+	for _, t := range tasks {
+		if t.ParentID != "" && ids[t.ParentID] && t.ParentID != t.ID {
+			children[t.ParentID] = append(children[t.ParentID], t)
+		} else {
+			roots = append(roots, t)
+		}
+	}
+
+	printed := map[string]bool{}
+	var walk func(t model.Task, depth int)
+	walk = func(t model.Task, depth int) {
+		if printed[t.ID] {
+			return
+		}
+		printed[t.ID] = true
+		status := strings.TrimSpace(t.Status)
+		if status == "" {
+			status = "pending"
+		}
+		fmt.Printf("%s- %s [%s] %s%s\n", strings.Repeat("  ", depth), t.ID, style.Status.Sprint(status), t.Title, taskAttributes(t.Labels, t.Fields))
+		for _, c := range children[t.ID] {
+			walk(c, depth+1)
+		}
+	}
+	for _, t := range roots {
+		walk(t, 0)
+	}
+	// Tasks caught in a ParentID cycle are unreachable from any root; list them flat.
+	for _, t := range tasks {
+		walk(t, 0)
+	}
+}
+
 func runCompletion(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
 		return fmt.Errorf("one shell must be specified: bash|zsh|fish|powershell")
 	}
 	switch args[0] {
 	case "bash":
-		return rootCmd.GenBashCompletion(os.Stdout)
+		return rootCmd.GenBashCompletion(stdout)
 	case "zsh":
-		return rootCmd.GenZshCompletion(os.Stdout)
+		return rootCmd.GenZshCompletion(stdout)
 	case "fish":
-		return rootCmd.GenFishCompletion(os.Stdout, true)
+		return rootCmd.GenFishCompletion(stdout, true)
 	case "powershell":
-		return rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+		return rootCmd.GenPowerShellCompletionWithDesc(stdout)
 	default:
 		return fmt.Errorf("unsupported shell: %s", args[0])
 	}
 }
 
 func runCharterInit(cmd *cobra.Command, args []string) error {
-	app, err := core.NewApp(core.AppOptions{
+	app, err := newApp(core.AppOptions{
 		SpecsDir:   specsBaseDir,
 		GoalsDir:   goalsBaseDir,
 		ChangesDir: changesBaseDir,
@@ -755,15 +3326,27 @@ func runCharterInit(cmd *cobra.Command, args []string) error {
 
 	// Check if charter already exists
 	if app.CharterManager.Exists() {
-		color.Yellow("Charter already exists. Edit .teamwerx/charter.md directly or use 'teamwerx charter show' to view it.")
+		printLine(style.Warn, "Charter already exists. Edit .teamwerx/charter.md directly or use 'teamwerx charter show' to view it.")
 		return nil
 	}
 
+	purpose := strings.TrimSpace(charterPurpose)
+	if purpose == "" {
+		if purpose, err = promptutil.Editor("Project purpose: what is this project for, and for whom?", ""); err != nil {
+			return fmt.Errorf("failed to prompt for purpose: %w", err)
+		}
+	}
+	purposeSection := purpose
+	if purpose == "" {
+		purpose = "Define the purpose and goals of this project"
+		purposeSection = "[Describe the purpose and vision of this project]"
+	}
+
 	// Create default charter
 	charter := &model.Charter{
 		Title:   "Project Charter",
 		Version: "1.0.0",
-		Purpose: "Define the purpose and goals of this project",
+		Purpose: purpose,
 		TechStack: []string{
 			"Add your technology stack here",
 		},
@@ -771,11 +3354,12 @@ func runCharterInit(cmd *cobra.Command, args []string) error {
 			"commit_prefix": "[PROJECT]",
 			"branch_naming": "feature/*, bugfix/*",
 		},
+		SpecSections: core.DefaultSpecSections,
 		Content: `# Project Charter
 
 ## Purpose
 
-[Describe the purpose and vision of this project]
+` + purposeSection + `
 
 ## Governance
 
@@ -794,8 +3378,9 @@ func runCharterInit(cmd *cobra.Command, args []string) error {
 	if err := app.CharterManager.Write(charter); err != nil {
 		return fmt.Errorf("failed to write charter: %w", err)
 	}
+	porcelainCharter(charter)
 
-	ok := color.New(color.FgGreen, color.Bold)
+	ok := style.Header
 	ok.Println("Charter initialized at .teamwerx/charter.md")
 	fmt.Println("\nEdit the file to customize your project's steering document.")
 	fmt.Println("This charter will guide AI agents and team members throughout the project.")
@@ -804,7 +3389,7 @@ func runCharterInit(cmd *cobra.Command, args []string) error {
 }
 
 func runCharterShow(cmd *cobra.Command, args []string) error {
-	app, err := core.NewApp(core.AppOptions{
+	app, err := newApp(core.AppOptions{
 		SpecsDir:   specsBaseDir,
 		GoalsDir:   goalsBaseDir,
 		ChangesDir: changesBaseDir,
@@ -814,24 +3399,41 @@ func runCharterShow(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to init app: %w", err)
 	}
 
-	charter, err := app.CharterManager.Read()
-	if err != nil {
-		color.Yellow("No charter found. Run 'teamwerx charter init' to create one.")
+	if !app.CharterManager.Exists() {
+		printLine(style.Warn, "No charter found. Run 'teamwerx charter init' to create one.")
 		return nil
 	}
+	read := app.CharterManager.Read
+	if charterEffective {
+		read = app.CharterManager.ReadEffective
+	}
+	charter, err := read()
+	if err != nil {
+		return fmt.Errorf("failed to read charter: %w", err)
+	}
+
+	porcelainCharter(charter)
 
 	// Display charter
-	hdr := color.New(color.FgCyan, color.Bold)
+	hdr := style.Header
 	hdr.Printf("Charter: %s\n", charter.Title)
 
+	if charter.Extends != "" {
+		note := ""
+		if charterEffective {
+			note = " (merged)"
+		}
+		fmt.Printf("Extends: %s%s\n", charter.Extends, note)
+	}
+
 	if charter.Version != "" {
 		fmt.Printf("Version: %s\n", charter.Version)
 	}
 	if !charter.Created.IsZero() {
-		fmt.Printf("Created: %s\n", charter.Created.Format("2006-01-02"))
+		fmt.Printf("Created: %s\n", displayTime(charter.Created, "2006-01-02"))
 	}
 	if !charter.Updated.IsZero() {
-		fmt.Printf("Updated: %s\n", charter.Updated.Format("2006-01-02"))
+		fmt.Printf("Updated: %s\n", displayTime(charter.Updated, "2006-01-02"))
 	}
 
 	if charter.Purpose != "" {
@@ -852,6 +3454,10 @@ func runCharterShow(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if len(charter.SpecSections) > 0 {
+		fmt.Printf("\nRequired spec sections: %s\n", strings.Join(charter.SpecSections, ", "))
+	}
+
 	if charter.Content != "" {
 		fmt.Println("\n" + charter.Content)
 	}
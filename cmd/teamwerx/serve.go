@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+	"github.com/teamwerx/teamwerx/internal/web"
+)
+
+var (
+	serveAddr      string
+	serveExclusive bool
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the workspace over HTTP",
+}
+
+var serveWebCmd = &cobra.Command{
+	Use:   "web",
+	Short: "Serve a read-only web dashboard of specs, goals and changes",
+	Long: `Serve a read-only dashboard of specs and requirements, goal progress, changes
+and the apply queue, for stakeholders who do not use the CLI. The page is
+embedded in the binary and reads the JSON API under /api/, which is re-read
+on every request. Only GET and HEAD are accepted.
+
+The server binds to 127.0.0.1 by default; use --addr :8080 to share it on
+the network.
+
+--exclusive freezes the workspace while serving: the server takes the
+workspace writer lease, and other teamwerx processes then fail fast, naming
+the server, instead of changing what the dashboard shows. The server itself
+does not write, so nothing changes until it stops. The lease is refreshed
+every few seconds, released on Ctrl+C and expires on its own if the server
+dies; if the server cannot refresh it, it stops with an error.`,
+	Args: cobra.NoArgs,
+	RunE: runServeWeb,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.AddCommand(serveWebCmd)
+	serveWebCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:8080", "Address to listen on")
+	serveWebCmd.Flags().BoolVar(&serveExclusive, "exclusive", false, "Freeze the workspace: hold the writer lease so no other process changes it while serving")
+	serveWebCmd.Flags().StringVar(&specsBaseDir, "specs-dir", ".teamwerx/specs", "Base directory containing spec domains")
+	serveWebCmd.Flags().StringVar(&goalsBaseDir, "goals-dir", ".teamwerx/goals", "Base directory containing goals")
+	serveWebCmd.Flags().StringVar(&changesBaseDir, "changes-dir", ".teamwerx/changes", "Base directory containing changes")
+	serveWebCmd.Flags().StringVar(&charterBaseDir, "charter-dir", ".teamwerx", "Base directory for charter")
+}
+
+func runServeWeb(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+		CharterDir: charterBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	var leaseLost <-chan error
+	if serveExclusive {
+		release, lost, err := app.AcquireWriterLease("serve web", 0)
+		if err != nil {
+			return err
+		}
+		defer release()
+		leaseLost = lost
+	}
+
+	ln, err := net.Listen("tcp", serveAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", serveAddr, err)
+	}
+	url := "http://" + ln.Addr().String() + "/"
+	porcelain("serving", url)
+	style.Success.Printf("Serving dashboard at %s (Ctrl+C to stop)\n", url)
+
+	// Stop cleanly on Ctrl+C so the deferred lease release runs.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	srv := &http.Server{Handler: web.Handler(app)}
+	stopped := make(chan error, 1)
+	go func() {
+		// A nil leaseLost (no --exclusive) never fires.
+		var err error
+		select {
+		case <-ctx.Done():
+		case err = <-leaseLost:
+		}
+		stopped <- err
+		_ = srv.Shutdown(context.Background())
+	}()
+	if err := srv.Serve(ln); err != http.ErrServerClosed {
+		return err
+	}
+	return <-stopped
+}
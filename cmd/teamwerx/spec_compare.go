@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+	"github.com/teamwerx/teamwerx/internal/model"
+	gitutil "github.com/teamwerx/teamwerx/internal/utils/git"
+)
+
+var (
+	compareLeftRef        string
+	compareRightRef       string
+	compareRightWorkspace string
+)
+
+var specDiffDomainsCmd = &cobra.Command{
+	Use:   "diff-domains <domain> [other-domain]",
+	Short: "Compare the requirements of two domains, workspaces or revisions",
+	Long: `List requirements present in only one of two specs, and word diffs for
+requirement IDs both share. The sides can be two domains in this workspace,
+or one domain in two git revisions or workspaces:
+
+  teamwerx spec diff-domains auth login          # two overlapping domains
+  teamwerx spec diff-domains auth --left-ref main # auth on main vs the working tree
+  teamwerx spec diff-domains auth --right-workspace ../other-repo`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runSpecDiffDomains,
+}
+
+func init() {
+	specCmd.AddCommand(specDiffDomainsCmd)
+	specDiffDomainsCmd.Flags().StringVar(&compareLeftRef, "left-ref", "", "Read the first spec as of this git revision")
+	specDiffDomainsCmd.Flags().StringVar(&compareRightRef, "right-ref", "", "Read the second spec as of this git revision")
+	specDiffDomainsCmd.Flags().StringVar(&compareRightWorkspace, "right-workspace", "", "Read the second spec from the workspace rooted at this directory")
+}
+
+// loadComparedSpec reads domain from app's specs, at ref when one is given,
+// and returns it with a label naming where it came from.
+func loadComparedSpec(app *core.App, domain, ref, workspace string) (*model.Spec, string, error) {
+	label := domain
+	if workspace != "" {
+		label = workspace + ":" + label
+	}
+	if ref == "" {
+		spec, err := app.SpecManager.ReadSpec(domain)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read %s: %w", label, err)
+		}
+		return spec, label, nil
+	}
+	label += "@" + ref
+	file := app.Options.Layout.SpecPath(app.Options.SpecsDir, domain)
+	repo := "."
+	if workspace != "" {
+		repo = workspace
+		if rel, err := filepath.Rel(workspace, file); err == nil {
+			file = rel
+		}
+	}
+	content, err := gitutil.Show(context.Background(), repo, ref, file)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read %s: %w", label, err)
+	}
+	spec, err := core.NewSpecParserWithFormat(app.Options.SpecFormat).Parse([]byte(content))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse %s: %w", label, err)
+	}
+	spec.Domain = domain
+	return spec, label, nil
+}
+
+func runSpecDiffDomains(cmd *cobra.Command, args []string) error {
+	leftDomain, rightDomain := args[0], args[0]
+	if len(args) == 2 {
+		rightDomain = args[1]
+	}
+	if leftDomain == rightDomain && compareLeftRef == compareRightRef && compareRightWorkspace == "" {
+		return fmt.Errorf("both sides are %s; name a second domain or pass --left-ref, --right-ref or --right-workspace", leftDomain)
+	}
+
+	app, err := newApp(core.AppOptions{SpecsDir: specsBaseDir, CharterDir: charterBaseDir})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+	rightApp := app
+	if compareRightWorkspace != "" {
+		dir := filepath.Join(compareRightWorkspace, core.WorkspaceDirName)
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return fmt.Errorf("%s is not a teamwerx workspace (no %s directory)", compareRightWorkspace, core.WorkspaceDirName)
+		}
+		rightApp, err = newApp(core.AppOptions{SpecsDir: filepath.Join(dir, "specs"), CharterDir: dir})
+		if err != nil {
+			return fmt.Errorf("failed to init app for %s: %w", compareRightWorkspace, err)
+		}
+	}
+
+	left, leftLabel, err := loadComparedSpec(app, leftDomain, compareLeftRef, "")
+	if err != nil {
+		return err
+	}
+	right, rightLabel, err := loadComparedSpec(rightApp, rightDomain, compareRightRef, compareRightWorkspace)
+	if err != nil {
+		return err
+	}
+
+	comparisons := core.CompareSpecs(left, right)
+	style.Header.Printf("Comparing %s with %s\n", leftLabel, rightLabel)
+	counts := map[string]int{}
+	for _, c := range comparisons {
+		counts[c.Status]++
+		porcelain("compare", c.Status, c.ID, c.LeftTitle, c.RightTitle)
+		switch c.Status {
+		case core.CompareLeftOnly:
+			printLine(style.Error, "only in %s: %s (%s)", leftLabel, c.ID, c.LeftTitle)
+		case core.CompareRightOnly:
+			printLine(style.Success, "only in %s: %s (%s)", rightLabel, c.ID, c.RightTitle)
+		case core.CompareDiffers:
+			for _, seg := range c.Segments {
+				porcelain("segment", [...]string{"equal", "insert", "delete"}[seg.Op], seg.Text)
+			}
+			fmt.Println()
+			style.Header.Printf("differs: %s\n", c.ID)
+			printDiffSegments(c.Segments)
+			fmt.Println()
+		}
+	}
+	fmt.Printf("%d only in %s, %d only in %s, %d differ, %d identical\n",
+		counts[core.CompareLeftOnly], leftLabel, counts[core.CompareRightOnly], rightLabel,
+		counts[core.CompareDiffers], counts[core.CompareSame])
+	return nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+var lintStyle bool
+
+var specReqLintCmd = &cobra.Command{
+	Use:   "lint [domain] [req-id]",
+	Short: "Lint requirements for structure and, with --style, writing style",
+	Long: `Check every requirement (of one domain, or one requirement) for a statement
+and a unique ID. --style adds writing rules: the statement uses "shall" or
+"must", avoids the passive voice (a heuristic), keeps sentences under
+lint.max_sentence_words (30), has no TODO/FIXME/TBD/XXX markers, and only
+puts defined glossary terms in **bold**. Findings come with a suggested fix
+where one can be derived.
+
+Turn rules off, or set the limits and glossary, in .teamwerx/config.yaml:
+
+  lint:
+    rules:
+      passive-voice: false
+    max_sentence_words: 25
+    glossary: [session, access token]`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runSpecReqLint,
+}
+
+func init() {
+	specReqCmd.AddCommand(specReqLintCmd)
+	specReqLintCmd.Flags().BoolVar(&lintStyle, "style", false, "Also check the writing style rules")
+}
+
+func runSpecReqLint(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+		CharterDir: charterBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+	cfg, err := core.LoadConfig(core.ConfigPath(app.Options.CharterDir))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	var specs []*model.Spec
+	if len(args) > 0 {
+		spec, err := app.SpecManager.ReadSpec(strings.TrimSpace(args[0]))
+		if err != nil {
+			return fmt.Errorf("failed to read spec: %w", err)
+		}
+		specs = append(specs, spec)
+	} else if specs, err = app.SpecManager.ListSpecs(); err != nil {
+		return fmt.Errorf("failed to list specs: %w", err)
+	}
+	opts := core.LintOptions{Config: cfg.Lint, Style: lintStyle}
+	if len(args) == 2 {
+		opts.RequirementID = resolveRequirementArg(specs[0], args[1])
+	}
+
+	total := 0
+	for _, spec := range specs {
+		findings, err := core.LintRequirements(spec, opts)
+		if err != nil {
+			return err
+		}
+		for _, f := range findings {
+			porcelain("lint", f.Domain, f.RequirementID, f.Rule, f.Message, f.Suggestion)
+			style.Error.Printf("%s/%s ", f.Domain, f.RequirementID)
+			fmt.Printf("[%s] %s\n", f.Rule, f.Message)
+			if f.Suggestion != "" {
+				printLine(style.Muted, "  suggestion: %s", f.Suggestion)
+			}
+		}
+		if len(findings) == 0 {
+			style.Success.Printf("ok   %s\n", spec.Domain)
+		}
+		total += len(findings)
+	}
+	if total > 0 {
+		return fmt.Errorf("%d lint finding(s)", total)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+var (
+	specMergeFrom   string
+	specMergeApply  bool
+	specMergeRemove bool
+)
+
+var specMergeCmd = &cobra.Command{
+	Use:   "merge --domain <domain> --from <ref>",
+	Short: "Propose (or apply) the requirement changes a spec has in another git revision",
+	Long: `Read the domain's spec as of a git revision, e.g. a feature branch, and turn
+its requirement differences with the current spec into a change: requirements
+new on the branch are ADDED and requirements whose text differs are MODIFIED.
+Requirements missing from the branch are only REMOVED with --remove, since
+without a merge base they may just as well be new here.
+
+The change is written as a draft to review and apply; --apply applies it
+right away.
+
+  teamwerx spec merge --domain auth --from feature/2fa
+  teamwerx spec merge --domain auth --from origin/main --apply`,
+	Args: cobra.NoArgs,
+	RunE: runSpecMerge,
+}
+
+func init() {
+	specCmd.AddCommand(specMergeCmd)
+	f := specMergeCmd.Flags()
+	f.StringVar(&changeDomain, "domain", "", "Spec domain to merge")
+	_ = specMergeCmd.MarkFlagRequired("domain")
+	f.StringVar(&specMergeFrom, "from", "", "Git revision to read the spec from, e.g. a branch")
+	_ = specMergeCmd.MarkFlagRequired("from")
+	f.BoolVar(&specMergeApply, "apply", false, "Apply the change instead of leaving it as a draft")
+	f.BoolVar(&specMergeRemove, "remove", false, "Remove requirements missing from the revision")
+	f.StringVar(&changeTitle, "title", "", "Change title (default: Merge <domain> from <ref>)")
+	f.StringVar(&goalID, "goal", "", "Goal the change belongs to")
+	f.StringVar(&authorName, "author", defaultAuthor(), "Change author")
+}
+
+func runSpecMerge(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+		CharterDir: charterBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+	domain := strings.TrimSpace(changeDomain)
+	current, err := app.SpecManager.ReadSpec(domain)
+	if err != nil {
+		return fmt.Errorf("failed to read spec: %w", err)
+	}
+	incoming, label, err := loadComparedSpec(app, domain, specMergeFrom, "")
+	if err != nil {
+		return err
+	}
+
+	delta := core.BranchDelta(current, incoming, specMergeRemove)
+	if len(delta.Operations) == 0 {
+		printLine(style.Success, "%s is up to date with %s.", domain, label)
+		return nil
+	}
+	title := strings.TrimSpace(changeTitle)
+	if title == "" {
+		title = fmt.Sprintf("Merge %s from %s", domain, specMergeFrom)
+	}
+	ch, err := app.ChangeManager.CreateChange(title, goalID, []model.SpecDelta{delta})
+	if err != nil {
+		return fmt.Errorf("failed to create change: %w", err)
+	}
+	ch.Description = fmt.Sprintf("Requirement changes of %s merged from git revision %s.", domain, specMergeFrom)
+	ch.Author = strings.TrimSpace(authorName)
+	if err := app.ChangeManager.Save(ch); err != nil {
+		return fmt.Errorf("failed to save change: %w", err)
+	}
+	style.Success.Printf("Created change %s: %s\n", ch.ID, ch.Title)
+	for _, op := range delta.Operations {
+		fmt.Printf("  %s %s/%s\n", op.Type, domain, op.Requirement.ID)
+	}
+
+	if !specMergeApply {
+		porcelainChange(ch)
+		fmt.Printf("Review it, then run: teamwerx change apply --id %s\n", ch.ID)
+		return nil
+	}
+	if err := app.ChangeManager.ApplyChange(ch); err != nil {
+		return fmt.Errorf("failed to apply change: %w", err)
+	}
+	_ = app.ChangeManager.Save(ch)
+	porcelainChange(ch)
+	style.Success.Printf("Applied change %s: %s\n", ch.ID, ch.Title)
+	return nil
+}
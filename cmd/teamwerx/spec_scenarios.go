@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+)
+
+var (
+	skeletonFormat  string
+	skeletonOutDir  string
+	skeletonPackage string
+	skeletonForce   bool
+)
+
+var specReqExtractScenariosCmd = &cobra.Command{
+	Use:   "extract-scenarios <domain> [req-id]",
+	Short: "Generate test skeletons from a requirement's scenarios",
+	Long: `Write a test skeleton for the requirement's "#### Scenario:" blocks, or for
+every requirement of the domain that has scenarios, into --out:
+
+  go       <domain>_<req>_test.go with one skipped test function per scenario
+  gherkin  <domain>_<req>.feature with one scenario per scenario
+
+Each test or scenario is preceded by a comment naming the
+<domain>/<requirement>#<scenario> it came from, so failures trace back to the
+spec. Existing files are kept unless --force is given, since skeletons are
+meant to be filled in.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runSpecReqExtractScenarios,
+}
+
+func init() {
+	specReqCmd.AddCommand(specReqExtractScenariosCmd)
+	specReqExtractScenariosCmd.Flags().StringVar(&skeletonFormat, "format", core.SkeletonGo, "Skeleton format: go or gherkin")
+	specReqExtractScenariosCmd.Flags().StringVar(&skeletonOutDir, "out", ".", "Directory to write the skeletons to")
+	specReqExtractScenariosCmd.Flags().StringVar(&skeletonPackage, "package", "", `Go package name (default "<domain>_test")`)
+	specReqExtractScenariosCmd.Flags().BoolVar(&skeletonForce, "force", false, "Overwrite existing files")
+}
+
+func runSpecReqExtractScenarios(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	spec, err := app.SpecManager.ReadSpec(strings.TrimSpace(args[0]))
+	if err != nil {
+		return fmt.Errorf("failed to read spec: %w", err)
+	}
+	reqID := ""
+	if len(args) == 2 {
+		reqID = resolveRequirementArg(spec, args[1])
+	}
+	skeletons, err := core.RenderScenarioSkeletons(spec, reqID, core.SkeletonOptions{Format: skeletonFormat, Package: skeletonPackage})
+	if err != nil {
+		return err
+	}
+	if len(skeletons) == 0 {
+		printLine(style.Warn, "No scenarios found in %s.", spec.Domain)
+		return nil
+	}
+
+	if err := os.MkdirAll(skeletonOutDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	written := 0
+	for _, sk := range skeletons {
+		out := filepath.Join(skeletonOutDir, sk.Path)
+		if _, err := os.Stat(out); err == nil && !skeletonForce {
+			porcelainFile("skipped", out)
+			printLine(style.Warn, "Kept existing %s (use --force to overwrite)", out)
+			continue
+		}
+		if err := os.WriteFile(out, sk.Content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", out, err)
+		}
+		written++
+		porcelainFile("written", out)
+		fmt.Printf("Wrote %s (%d scenario(s) of %s/%s)\n", out, sk.Scenarios, spec.Domain, sk.RequirementID)
+	}
+	style.Success.Printf("Generated %d skeleton file(s).\n", written)
+	return nil
+}
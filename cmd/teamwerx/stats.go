@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+)
+
+var (
+	statsSince  string
+	statsOutput string
+
+	statsCmd = &cobra.Command{
+		Use:   "stats",
+		Short: "Workspace activity statistics",
+	}
+
+	statsContributorsCmd = &cobra.Command{
+		Use:   "contributors",
+		Short: "Break activity down by author, people and agents",
+		Long: `Count, per author, the tasks completed, discussion entries written, changes
+authored and change approvals given, with totals for people and for agents.
+List the agent authors in .teamwerx/config.yaml:
+
+  contributors:
+    agents: [claude, ci-bot]
+
+Authors come from --by, --author and --as on the commands that record them,
+which default to $TEAMWERX_AUTHOR and then $USER.`,
+		Args: cobra.NoArgs,
+		RunE: runStatsContributors,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+	statsCmd.AddCommand(statsContributorsCmd)
+	statsContributorsCmd.Flags().StringVar(&statsSince, "since", "", "Only count activity since a date, RFC 3339 time or age (e.g. 30d)")
+	statsContributorsCmd.Flags().StringVarP(&statsOutput, "output", "o", "text", "Output format: text or json")
+}
+
+func runStatsContributors(cmd *cobra.Command, args []string) error {
+	if statsOutput != "text" && statsOutput != "json" && statsOutput != "" {
+		return fmt.Errorf("unsupported output %q (expected text or json)", statsOutput)
+	}
+	var since time.Time
+	if strings.TrimSpace(statsSince) != "" {
+		t, err := core.ParseSince(statsSince, time.Now())
+		if err != nil {
+			return err
+		}
+		since = t
+	}
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+	cfg, err := core.LoadConfig(core.ConfigPath(app.Options.CharterDir))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	stats, err := app.ContributorStats(since, cfg.Contributors)
+	if err != nil {
+		return err
+	}
+
+	if statsOutput == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+
+	for _, c := range stats.Contributors {
+		porcelainContributor(c)
+	}
+	if len(stats.Contributors) == 0 {
+		printLine(style.Warn, "No activity recorded.")
+		return nil
+	}
+	hdr := style.Header
+	if since.IsZero() {
+		hdr.Println("Contributor activity")
+	} else {
+		hdr.Printf("Contributor activity since %s\n", displayTime(since, "2006-01-02 15:04"))
+	}
+	row := func(name string, c core.ContributorActivity) {
+		fmt.Printf("  %-24s %6d %9d %8d %9d\n", name, c.TasksCompleted, c.DiscussionEntries, c.ChangesAuthored, c.ChangesApproved)
+	}
+	fmt.Printf("  %-24s %6s %9s %8s %9s\n", "AUTHOR", "TASKS", "ENTRIES", "CHANGES", "APPROVED")
+	for _, c := range stats.Contributors {
+		name := c.Author
+		if c.Agent {
+			name += " (agent)"
+		}
+		row(name, c)
+	}
+	fmt.Println()
+	row("humans", stats.Humans)
+	row("agents", stats.Agents)
+	if total := stats.Humans.Total() + stats.Agents.Total(); total > 0 {
+		fmt.Printf("Agent share: %d%% of %d contribution(s)\n", stats.Agents.Total()*100/total, total)
+	}
+	return nil
+}
+
+func porcelainContributor(c core.ContributorActivity) {
+	kind := "human"
+	if c.Agent {
+		kind = "agent"
+	}
+	porcelain("contributor", c.Author, kind, strconv.Itoa(c.TasksCompleted), strconv.Itoa(c.DiscussionEntries), strconv.Itoa(c.ChangesAuthored), strconv.Itoa(c.ChangesApproved))
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/teamwerx/teamwerx/internal/core"
+)
+
+// themeFlag is --theme: a built-in theme used instead of the config's.
+var themeFlag string
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&themeFlag, "theme", "", "Output theme: default, high-contrast or monochrome (see also TEAMWERX_THEME and theme in config.yaml)")
+}
+
+// outputStyles is the color of each theme role. Commands print through the
+// package-level style rather than picking colors themselves.
+type outputStyles struct {
+	Header  *color.Color
+	ID      *color.Color
+	Status  *color.Color
+	Success *color.Color
+	Warn    *color.Color
+	Error   *color.Color
+	Info    *color.Color
+	Muted   *color.Color
+	// NoColor is set when no role has a color, so output that tells things
+	// apart by color alone (inline diffs, entry type colors) must not rely
+	// on it.
+	NoColor bool
+}
+
+// style is the theme commands print with. applyTheme sets it before each
+// command runs; until then it is the default theme.
+var style = newOutputStyles(core.ThemeConfig{}.Resolve())
+
+var themeAttributes = map[string]color.Attribute{
+	"bold":      color.Bold,
+	"faint":     color.Faint,
+	"italic":    color.Italic,
+	"underline": color.Underline,
+	"reverse":   color.ReverseVideo,
+}
+
+// newOutputStyles builds the styles for roles, as resolved by
+// core.ThemeConfig. The styles are validated with the config.
+func newOutputStyles(roles map[string]string) *outputStyles {
+	s := &outputStyles{NoColor: true}
+	build := func(role string) *color.Color {
+		name, attrs, _ := core.ParseThemeStyle(roles[role])
+		c := color.New()
+		if name != "" {
+			s.NoColor = false
+			fg := entryColors[strings.TrimPrefix(name, "hi-")]
+			if strings.HasPrefix(name, "hi-") {
+				fg += color.FgHiBlack - color.FgBlack
+			}
+			c.Add(fg)
+		}
+		for _, a := range attrs {
+			c.Add(themeAttributes[a])
+		}
+		return c
+	}
+	s.Header = build(core.ThemeRoleHeader)
+	s.ID = build(core.ThemeRoleID)
+	s.Status = build(core.ThemeRoleStatus)
+	s.Success = build(core.ThemeRoleSuccess)
+	s.Warn = build(core.ThemeRoleWarn)
+	s.Error = build(core.ThemeRoleError)
+	s.Info = build(core.ThemeRoleInfo)
+	s.Muted = build(core.ThemeRoleMuted)
+	return s
+}
+
+// applyTheme sets style from the theme chosen by --theme, then
+// TEAMWERX_THEME, then the theme section of config.yaml. A theme named by the
+// flag or environment replaces the config's entirely, per-role styles
+// included, so e.g. --theme monochrome always prints without color. An
+// unreadable config is left for the command to report.
+func applyTheme() error {
+	name := strings.TrimSpace(themeFlag)
+	if name == "" {
+		name = strings.TrimSpace(os.Getenv("TEAMWERX_THEME"))
+	}
+	if name != "" {
+		theme := core.ThemeConfig{Name: strings.ToLower(name)}
+		if err := theme.Validate(); err != nil {
+			return fmt.Errorf("unknown theme %q (want one of %s)", name, strings.Join(core.BuiltinThemes(), ", "))
+		}
+		style = newOutputStyles(theme.Resolve())
+		return nil
+	}
+	charterDir := core.AppOptions{CharterDir: charterBaseDir}.WithDefaults().CharterDir
+	if cfg, err := core.LoadConfig(core.ConfigPath(charterDir)); err == nil {
+		style = newOutputStyles(cfg.Theme.Resolve())
+	}
+	return nil
+}
+
+// printLine prints a line in c, adding the trailing newline as color.Green
+// and friends do. Without arguments format is printed as is.
+func printLine(c *color.Color, format string, a ...interface{}) {
+	if !strings.HasSuffix(format, "\n") {
+		format += "\n"
+	}
+	if len(a) == 0 {
+		c.Print(format)
+		return
+	}
+	c.Printf(format, a...)
+}
+
+// printIndented prints each line of text in c, prefixed with indent.
+func printIndented(c *color.Color, text, indent string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		if strings.TrimSpace(line) == "" {
+			fmt.Println()
+			continue
+		}
+		c.Print(indent + line + "\n")
+	}
+}
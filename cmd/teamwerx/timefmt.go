@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/teamwerx/teamwerx/internal/utils"
+)
+
+// Timestamp display modes, chosen by --utc, --relative or TEAMWERX_TIME.
+const (
+	timeLocal    = "local"
+	timeUTC      = "utc"
+	timeRelative = "relative"
+)
+
+var (
+	timeUTCFlag      bool
+	timeRelativeFlag bool
+)
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&timeUTCFlag, "utc", false, "Print timestamps in UTC (default: local time; see also TEAMWERX_TIME)")
+	rootCmd.PersistentFlags().BoolVar(&timeRelativeFlag, "relative", false, `Print timestamps relative to now, e.g. "2h ago"`)
+}
+
+// timeMode is the display mode for timestamps: the flags win, then
+// TEAMWERX_TIME (local, utc or relative), then local time.
+func timeMode() (string, error) {
+	switch {
+	case timeUTCFlag && timeRelativeFlag:
+		return "", fmt.Errorf("--utc and --relative cannot be combined")
+	case timeUTCFlag:
+		return timeUTC, nil
+	case timeRelativeFlag:
+		return timeRelative, nil
+	}
+	switch mode := strings.ToLower(strings.TrimSpace(os.Getenv("TEAMWERX_TIME"))); mode {
+	case "", timeLocal:
+		return timeLocal, nil
+	case timeUTC, timeRelative:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown TEAMWERX_TIME %q (want local, utc or relative)", mode)
+	}
+}
+
+// displayTime formats t for human-readable output in the chosen mode. layout
+// is used for absolute times; layouts without a zone get one appended so
+// local and UTC times cannot be mistaken for each other. JSON and porcelain
+// output keep the raw value instead.
+func displayTime(t time.Time, layout string) string {
+	mode, err := timeMode()
+	if err != nil {
+		// validated in rootCmd's PersistentPreRunE; fall back to local time
+		mode = timeLocal
+	}
+	switch mode {
+	case timeRelative:
+		return utils.RelativeTime(t, time.Now())
+	case timeUTC:
+		t = t.UTC()
+	default:
+		t = t.Local()
+	}
+	s := t.Format(layout)
+	if layout != time.RFC3339 && strings.Contains(layout, "15") {
+		s += " " + t.Format("MST")
+	}
+	return s
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+	gitutil "github.com/teamwerx/teamwerx/internal/utils/git"
+)
+
+var (
+	traceSince string
+	traceRoot  string
+	traceNoGit bool
+)
+
+var traceCmd = &cobra.Command{
+	Use:   "trace",
+	Short: "Trace requirements to the code that implements them",
+}
+
+var traceCodeCmd = &cobra.Command{
+	Use:   "code",
+	Short: "Check requirement Code: paths and flag code changed without a spec change",
+	Long: `Requirements name the files that implement them with "Code:" lines:
+
+  ### Requirement: Login
+  Users log in with email and password.
+  Code: internal/auth/**, cmd/login.go
+
+Globs are relative to the workspace root and support "**". 'trace code'
+reports globs that match no file, then compares the files changed since
+--since (HEAD by default, i.e. uncommitted work) with those globs and flags
+requirements whose code changed while neither their spec nor a pending change
+touching them did. It exits non-zero when anything is reported.`,
+	Args: cobra.NoArgs,
+	RunE: runTraceCode,
+}
+
+func init() {
+	rootCmd.AddCommand(traceCmd)
+	traceCmd.AddCommand(traceCodeCmd)
+	traceCodeCmd.Flags().StringVar(&traceSince, "since", "HEAD", "Git revision to diff the working tree against (e.g. origin/main)")
+	traceCodeCmd.Flags().StringVar(&traceRoot, "root", "", "Directory Code: globs are relative to (default: the workspace root)")
+	traceCodeCmd.Flags().BoolVar(&traceNoGit, "no-git", false, "Only check that Code: globs match files")
+	traceCodeCmd.Flags().StringVar(&specsBaseDir, "specs-dir", ".teamwerx/specs", "Base directory containing spec domains")
+	traceCodeCmd.Flags().StringVar(&changesBaseDir, "changes-dir", ".teamwerx/changes", "Base directory containing changes")
+}
+
+func runTraceCode(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{SpecsDir: specsBaseDir, ChangesDir: changesBaseDir})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+	root := traceRoot
+	if root == "" {
+		if root = core.FindWorkspaceRoot("."); root == "" {
+			root = "."
+		}
+	}
+
+	opts := core.CodeTraceOptions{Root: root}
+	if !traceNoGit {
+		ctx := context.Background()
+		if ok, _ := gitutil.IsRepo(ctx, root); ok {
+			files, err := gitutil.ChangedFiles(ctx, root, traceSince)
+			if err != nil {
+				return fmt.Errorf("failed to list changed files: %w", err)
+			}
+			opts.ChangedFiles = files
+		} else {
+			printLine(style.Warn, "%s is not a git repository; only checking that Code: paths exist.", root)
+		}
+	}
+
+	report, err := app.TraceCode(opts)
+	if err != nil {
+		return fmt.Errorf("failed to trace code: %w", err)
+	}
+	if report.Requirements == 0 {
+		printLine(style.Warn, "No requirements have Code: annotations.")
+		return nil
+	}
+	if len(report.Findings) == 0 {
+		printLine(style.Success, "%d traced requirement(s): every Code: path exists and no code changed without a spec change.", report.Requirements)
+		fmt.Printf("Traced by priority: %s\n", formatPriorityCounts(report.ByPriority))
+		return nil
+	}
+	for _, f := range report.Findings {
+		porcelain("trace", f.Kind, f.Domain, f.RequirementID, f.Pattern, strings.Join(f.Files, ","), f.Priority)
+		tag := ""
+		if f.Priority != "" {
+			tag = " [" + f.Priority + "]"
+		}
+		switch f.Kind {
+		case core.TraceMissing:
+			style.Error.Printf("%-12s", f.Kind)
+			fmt.Printf(" %s/%s%s: %s matches no file\n", f.Domain, f.RequirementID, tag, f.Pattern)
+		default:
+			style.Warn.Printf("%-12s", f.Kind)
+			fmt.Printf(" %s/%s%s: %s changed without a spec change\n", f.Domain, f.RequirementID, tag, strings.Join(f.Files, ", "))
+		}
+	}
+	fmt.Printf("Traced by priority: %s\n", formatPriorityCounts(report.ByPriority))
+	return fmt.Errorf("%d traceability issue(s) in %d traced requirement(s)", len(report.Findings), report.Requirements)
+}
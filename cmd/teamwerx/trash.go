@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+)
+
+var trashOlderThanDays int
+
+var specDeleteCmd = &cobra.Command{
+	Use:   "delete <domain>",
+	Short: "Move a spec domain to the trash",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSpecDelete,
+}
+
+var discussDeleteCmd = &cobra.Command{
+	Use:   "delete <entry-id>",
+	Short: "Move a discussion entry to the trash",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDiscussDelete,
+}
+
+var planRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Move a task and its subtasks to the trash",
+	Args:  cobra.NoArgs,
+	RunE:  runPlanRemove,
+}
+
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "List, restore or purge deleted specs, discussion entries and tasks",
+	Long: `Deleted specs, discussion entries and tasks are kept in .teamwerx/.trash
+until restored or purged. 'teamwerx clean' purges items older than its
+--trash-retention-days window.`,
+}
+
+var trashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List trashed items, oldest first",
+	Args:  cobra.NoArgs,
+	RunE:  runTrashList,
+}
+
+var trashRestoreCmd = &cobra.Command{
+	Use:   "restore <id>",
+	Short: "Put a trashed item back where it came from",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTrashRestore,
+}
+
+var trashPurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Permanently remove trashed items",
+	Args:  cobra.NoArgs,
+	RunE:  runTrashPurge,
+}
+
+func init() {
+	specCmd.AddCommand(specDeleteCmd)
+
+	discussCmd.AddCommand(discussDeleteCmd)
+	discussDeleteCmd.Flags().StringVar(&goalID, "goal", "", "Goal ID")
+	_ = discussDeleteCmd.MarkFlagRequired("goal")
+	discussDeleteCmd.Flags().StringVar(&charterBaseDir, "charter-dir", ".teamwerx", "Base directory for charter and trash")
+
+	planCmd.AddCommand(planRemoveCmd)
+	planRemoveCmd.Flags().StringVar(&goalID, "goal", "", "Goal ID")
+	planRemoveCmd.Flags().StringVar(&taskID, "task", "", "Task ID to remove (e.g., T03)")
+	_ = planRemoveCmd.MarkFlagRequired("goal")
+	_ = planRemoveCmd.MarkFlagRequired("task")
+	planRemoveCmd.Flags().StringVar(&charterBaseDir, "charter-dir", ".teamwerx", "Base directory for charter and trash")
+
+	rootCmd.AddCommand(trashCmd)
+	trashCmd.AddCommand(trashListCmd)
+	trashCmd.AddCommand(trashRestoreCmd)
+	trashCmd.AddCommand(trashPurgeCmd)
+	trashPurgeCmd.Flags().IntVar(&trashOlderThanDays, "older-than-days", 30, "Only purge items deleted more than this many days ago (0 purges everything)")
+	trashCmd.PersistentFlags().StringVar(&specsBaseDir, "specs-dir", ".teamwerx/specs", "Base directory containing spec domains")
+	trashCmd.PersistentFlags().StringVar(&goalsBaseDir, "goals-dir", ".teamwerx/goals", "Base directory containing goals")
+	trashCmd.PersistentFlags().StringVar(&charterBaseDir, "charter-dir", ".teamwerx", "Base directory for charter and trash")
+}
+
+func porcelainTrash(item core.TrashItem) {
+	porcelain("trashed", item.ID, item.Kind, item.Name, porcelainTime(item.DeletedAt))
+}
+
+func newTrashApp() (*core.App, error) {
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		CharterDir: charterBaseDir,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to init app: %w", err)
+	}
+	return app, nil
+}
+
+func printTrashed(item *core.TrashItem) {
+	porcelainTrash(*item)
+	printLine(style.Success, "Moved %s %s to the trash.", item.Kind, item.Name)
+	fmt.Printf("Restore with: teamwerx trash restore %s\n", item.ID)
+}
+
+func runSpecDelete(cmd *cobra.Command, args []string) error {
+	app, err := newTrashApp()
+	if err != nil {
+		return err
+	}
+	item, err := app.TrashSpec(strings.TrimSpace(args[0]))
+	if err != nil {
+		return fmt.Errorf("failed to delete spec: %w", err)
+	}
+	printTrashed(item)
+	return nil
+}
+
+func runDiscussDelete(cmd *cobra.Command, args []string) error {
+	app, err := newTrashApp()
+	if err != nil {
+		return err
+	}
+	item, err := app.TrashDiscussionEntry(goalID, strings.TrimSpace(args[0]))
+	if err != nil {
+		return fmt.Errorf("failed to delete discussion entry: %w", err)
+	}
+	printTrashed(item)
+	return nil
+}
+
+func runPlanRemove(cmd *cobra.Command, args []string) error {
+	app, err := newTrashApp()
+	if err != nil {
+		return err
+	}
+	item, err := app.TrashTask(goalID, strings.TrimSpace(taskID))
+	if err != nil {
+		return fmt.Errorf("failed to remove task: %w", err)
+	}
+	printTrashed(item)
+	if n := len(item.Tasks) - 1; n > 0 {
+		fmt.Printf("Removed %d subtask(s) with it.\n", n)
+	}
+	return nil
+}
+
+func runTrashList(cmd *cobra.Command, args []string) error {
+	app, err := newTrashApp()
+	if err != nil {
+		return err
+	}
+	items, err := app.ListTrash()
+	if err != nil {
+		return fmt.Errorf("failed to list trash: %w", err)
+	}
+	if len(items) == 0 {
+		printLine(style.Success, "Trash is empty.")
+		return nil
+	}
+	fmt.Printf("%-44s %-6s %-20s %s\n", "ID", "KIND", "DELETED", "NAME")
+	for _, item := range items {
+		porcelainTrash(item)
+		fmt.Printf("%-44s %-6s %-20s %s\n", item.ID, item.Kind, displayTime(item.DeletedAt, "2006-01-02 15:04"), item.Name)
+	}
+	return nil
+}
+
+func runTrashRestore(cmd *cobra.Command, args []string) error {
+	app, err := newTrashApp()
+	if err != nil {
+		return err
+	}
+	item, err := app.RestoreTrash(strings.TrimSpace(args[0]))
+	if err != nil {
+		return fmt.Errorf("failed to restore: %w", err)
+	}
+	porcelainTrash(*item)
+	printLine(style.Success, "Restored %s %s.", item.Kind, item.Name)
+	return nil
+}
+
+func runTrashPurge(cmd *cobra.Command, args []string) error {
+	if trashOlderThanDays < 0 {
+		return fmt.Errorf("--older-than-days must not be negative")
+	}
+	app, err := newTrashApp()
+	if err != nil {
+		return err
+	}
+	cutoff := time.Now().Add(-time.Duration(trashOlderThanDays) * 24 * time.Hour)
+	purged, err := app.PurgeTrash(cutoff)
+	for _, item := range purged {
+		porcelainTrash(item)
+		fmt.Printf("Purged %s %s (%s)\n", item.Kind, item.Name, item.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to purge trash: %w", err)
+	}
+	printLine(style.Success, "Purged %d item(s).", len(purged))
+	return nil
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/teamwerx/teamwerx/internal/core"
+)
+
+var useClear bool
+
+var useCmd = &cobra.Command{
+	Use:   "use [goal|domain|change] [id]",
+	Short: "Show or set the goal, spec domain and change commands default to",
+	Long: `Commands remember the goal, spec domain and change you last used in
+.teamwerx/.local/state.json, which is per-user and never committed. When a
+required --goal, --domain or change --id flag is omitted, the remembered value
+is used instead, so after 'teamwerx use goal 001-demo' you can run
+'teamwerx plan add "task"'.
+
+With no arguments, prints the current context.`,
+	Args: cobra.RangeArgs(0, 2),
+	RunE: runUse,
+}
+
+func init() {
+	rootCmd.AddCommand(useCmd)
+	useCmd.Flags().BoolVar(&useClear, "clear", false, "Forget the remembered value")
+	useCmd.Flags().StringVar(&specsBaseDir, "specs-dir", ".teamwerx/specs", "Base directory containing spec domains")
+	useCmd.Flags().StringVar(&goalsBaseDir, "goals-dir", ".teamwerx/goals", "Base directory containing goals")
+	useCmd.Flags().StringVar(&changesBaseDir, "changes-dir", ".teamwerx/changes", "Base directory containing changes")
+	useCmd.Flags().StringVar(&charterBaseDir, "charter-dir", ".teamwerx", "Base directory for charter and local state")
+}
+
+// contextFlags maps the flags filled from local state onto their state keys.
+// "id" only names a change under `teamwerx change`.
+var contextFlags = []struct{ flag, key string }{
+	{"goal", core.LocalGoal},
+	{"domain", core.LocalDomain},
+	{"id", core.LocalChange},
+}
+
+// createdContext collects IDs created by the running command (a new goal or
+// change) so they become the default for the next one.
+var createdContext = map[string]string{}
+
+func newStateApp() (*core.App, error) {
+	return newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+		CharterDir: charterBaseDir,
+	})
+}
+
+func isChangeCommand(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c == changeCmd {
+			return true
+		}
+	}
+	return false
+}
+
+// contextFlag returns cmd's flag for a context key, if it is a single-valued
+// string flag.
+func contextFlag(cmd *cobra.Command, flag string) *pflag.Flag {
+	if flag == "id" && !isChangeCommand(cmd) {
+		return nil
+	}
+	f := cmd.Flags().Lookup(flag)
+	if f == nil || f.Value.Type() != "string" {
+		return nil
+	}
+	return f
+}
+
+// applyLocalDefaults fills omitted required context flags from local state.
+// It runs before cobra checks required flags, so a remembered value satisfies
+// them and a missing one still fails as before.
+func applyLocalDefaults(cmd *cobra.Command) {
+	var st *core.LocalState
+	for _, cf := range contextFlags {
+		f := contextFlag(cmd, cf.flag)
+		if f == nil || f.Changed {
+			continue
+		}
+		if _, required := f.Annotations[cobra.BashCompOneRequiredFlag]; !required {
+			continue
+		}
+		if st == nil {
+			app, err := newStateApp()
+			if err != nil {
+				return
+			}
+			st = app.LoadLocalState()
+		}
+		value := st.Get(cf.key)
+		if value == "" || cmd.Flags().Set(cf.flag, value) != nil {
+			continue
+		}
+		if !porcelainMode {
+			fmt.Fprintf(os.Stderr, "Using %s %s (see 'teamwerx use')\n", cf.key, value)
+		}
+	}
+}
+
+// rememberContext records the context a successful command ran with.
+// Failures are ignored: local state only ever supplies defaults.
+func rememberContext(cmd *cobra.Command) {
+	values := map[string]string{}
+	for _, cf := range contextFlags {
+		if f := contextFlag(cmd, cf.flag); f != nil && f.Changed {
+			values[cf.key] = f.Value.String()
+		}
+	}
+	for key, value := range createdContext {
+		values[key] = value
+	}
+	if len(values) == 0 {
+		return
+	}
+	if app, err := newStateApp(); err == nil {
+		_ = app.RememberContext(values)
+	}
+}
+
+func printLocalState(st *core.LocalState) {
+	for _, key := range core.LocalStateKeys {
+		value := st.Get(key)
+		porcelain("context", key, value)
+		if value == "" {
+			value = style.Muted.Sprint("(none)")
+		}
+		fmt.Printf("%-7s %s\n", key, value)
+	}
+}
+
+func runUse(cmd *cobra.Command, args []string) error {
+	app, err := newStateApp()
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+	switch {
+	case len(args) == 0:
+		if useClear {
+			return fmt.Errorf("--clear needs a key: goal, domain or change")
+		}
+		printLocalState(app.LoadLocalState())
+		return nil
+	case len(args) == 1 && !useClear:
+		return fmt.Errorf("missing %s ID (or pass --clear to forget it)", args[0])
+	case len(args) == 2 && useClear:
+		return fmt.Errorf("--clear takes no ID")
+	}
+	key := strings.ToLower(args[0])
+	value := ""
+	if len(args) == 2 {
+		value = args[1]
+	}
+	st, err := app.UseContext(key, value)
+	if err != nil {
+		return fmt.Errorf("failed to set context: %w", err)
+	}
+	if value == "" {
+		printLine(style.Success, "Forgot the default %s.", key)
+	} else {
+		printLine(style.Success, "Using %s %s by default.", key, st.Get(key))
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/core"
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+)
+
+var verifyAccept bool
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Detect artifacts changed outside teamwerx since the last operation",
+	Long: `Compare specs, plans, discussions, changes and the charter against the
+integrity manifest (.teamwerx/manifest.json) that teamwerx updates on every
+write. Reports each file that was edited, deleted, left unparseable (e.g. a
+truncated JSON file) or created outside teamwerx, and exits non-zero if any
+drifted.
+
+Hand edits are normal for specs; run with --accept to record the current state
+as the new baseline once reviewed.`,
+	Args: cobra.NoArgs,
+	RunE: runVerify,
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+	verifyCmd.Flags().BoolVar(&verifyAccept, "accept", false, "Record the current files as the new baseline")
+	verifyCmd.Flags().StringVar(&specsBaseDir, "specs-dir", ".teamwerx/specs", "Base directory containing spec domains")
+	verifyCmd.Flags().StringVar(&goalsBaseDir, "goals-dir", ".teamwerx/goals", "Base directory containing goals")
+	verifyCmd.Flags().StringVar(&changesBaseDir, "changes-dir", ".teamwerx/changes", "Base directory containing changes")
+	verifyCmd.Flags().StringVar(&charterBaseDir, "charter-dir", ".teamwerx", "Base directory for charter")
+}
+
+func runVerify(cmd *cobra.Command, args []string) error {
+	app, err := newApp(core.AppOptions{
+		SpecsDir:   specsBaseDir,
+		GoalsDir:   goalsBaseDir,
+		ChangesDir: changesBaseDir,
+		CharterDir: charterBaseDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to init app: %w", err)
+	}
+
+	if verifyAccept {
+		n, err := core.AcceptWorkspace(app.Options)
+		if err != nil {
+			return fmt.Errorf("failed to update manifest: %w", err)
+		}
+		printLine(style.Success, "Recorded %d file(s) in the manifest.", n)
+		return nil
+	}
+
+	drifts, err := core.VerifyWorkspace(app.Options)
+	if err != nil {
+		if _, ok := err.(*custom_errors.ErrNotFound); ok {
+			return fmt.Errorf("no integrity manifest yet; run 'teamwerx verify --accept' to record the current state")
+		}
+		return fmt.Errorf("failed to verify workspace: %w", err)
+	}
+	if len(drifts) == 0 {
+		printLine(style.Success, "All artifacts match the manifest.")
+		return nil
+	}
+
+	for _, d := range drifts {
+		porcelain("drift", d.Path, d.Kind, d.Detail)
+		c := style.Warn
+		if d.Kind == core.DriftCorrupt || d.Kind == core.DriftMissing {
+			c = style.Error
+		}
+		c.Printf("%-10s", d.Kind)
+		fmt.Printf(" %s", d.Path)
+		if d.Detail != "" {
+			fmt.Printf(" (%s)", d.Detail)
+		}
+		fmt.Println()
+	}
+	return fmt.Errorf("%d file(s) drifted since the last teamwerx operation", len(drifts))
+}
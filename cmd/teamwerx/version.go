@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/teamwerx/teamwerx/internal/version"
+)
+
+var versionOutput string
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print version and build metadata",
+	Args:  cobra.NoArgs,
+	RunE:  runVersion,
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+	versionCmd.Flags().StringVarP(&versionOutput, "output", "o", "text", "Output format: text or json")
+}
+
+func runVersion(cmd *cobra.Command, args []string) error {
+	info := version.Get()
+	switch versionOutput {
+	case "text", "":
+		porcelain("version", info.Version, info.Commit, info.Date, info.GoVersion, info.Platform)
+		fmt.Printf("Version:    %s\n", info.Version)
+		fmt.Printf("Commit:     %s\n", info.Commit)
+		fmt.Printf("Built:      %s\n", info.Date)
+		fmt.Printf("Go version: %s\n", info.GoVersion)
+		fmt.Printf("Platform:   %s\n", info.Platform)
+		return nil
+	case "json":
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(info)
+	default:
+		return fmt.Errorf("unsupported output %q (expected text or json)", versionOutput)
+	}
+}
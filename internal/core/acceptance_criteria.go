@@ -0,0 +1,116 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// checklistItem matches a task list item such as "- [ ] text" or "1. [x] text".
+// The second group is the mark between the brackets.
+var checklistItem = regexp.MustCompile(`^( {0,3}(?:[-*+]|\d+[.)]) +\[)([ xX])\] +(.*?)\s*$`)
+
+// findAcceptanceCriteria returns the checklist items in a requirement block.
+// block is the block's text and offset its position in the spec. Items inside
+// fenced code or requirement notes are not criteria.
+func findAcceptanceCriteria(block string, offset int, notes []model.RequirementNote) []model.AcceptanceCriterion {
+	var criteria []model.AcceptanceCriterion
+	fence := ""
+	pos := 0
+	for _, line := range strings.SplitAfter(block, "\n") {
+		start := pos
+		pos += len(line)
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		if inNote(notes, offset+start) {
+			continue
+		}
+		m := checklistItem.FindStringSubmatchIndex(strings.TrimRight(line, "\r\n"))
+		if m == nil {
+			continue
+		}
+		criteria = append(criteria, model.AcceptanceCriterion{
+			Index: len(criteria) + 1,
+			Text:  line[m[6]:m[7]],
+			Done:  line[m[4]] != ' ',
+			Mark:  offset + start + m[4],
+		})
+	}
+	return criteria
+}
+
+func inNote(notes []model.RequirementNote, offset int) bool {
+	for _, n := range notes {
+		if offset >= n.Start && offset < n.End {
+			return true
+		}
+	}
+	return false
+}
+
+// SetAcceptanceCriterion checks (done true) or unchecks item index (1-based)
+// of a requirement's checklist; a nil done toggles it. The edit is merged as a
+// MODIFIED operation based on the spec's current fingerprint, so it goes
+// through the same divergence check as any change. It returns the item's new
+// state; an item already in the requested state leaves the spec untouched.
+func SetAcceptanceCriterion(specs SpecManager, merger SpecMerger, domain, reqID string, index int, done *bool) (*model.AcceptanceCriterion, error) {
+	spec, err := specs.ReadSpec(domain)
+	if err != nil {
+		return nil, err
+	}
+	var req *model.Requirement
+	for i := range spec.Requirements {
+		if spec.Requirements[i].ID == reqID {
+			req = &spec.Requirements[i]
+			break
+		}
+	}
+	if req == nil {
+		return nil, custom_errors.NewErrNotFound("requirement", reqID)
+	}
+	if index < 1 || index > len(req.AcceptanceCriteria) {
+		return nil, custom_errors.NewErrNotFound("acceptance criterion", fmt.Sprintf("%s#%d", reqID, index))
+	}
+
+	item := req.AcceptanceCriteria[index-1]
+	want := !item.Done
+	if done != nil {
+		want = *done
+	}
+	if want == item.Done {
+		return &item, nil
+	}
+	mark := " "
+	if want {
+		mark = "x"
+	}
+	block := spec.Content[req.Start:req.End]
+	rel := item.Mark - req.Start
+	block = block[:rel] + mark + block[rel+1:]
+
+	delta := &model.SpecDelta{
+		Domain:          domain,
+		BaseFingerprint: spec.Fingerprint,
+		Operations: []model.DeltaOperation{{
+			Type:        "MODIFIED",
+			Requirement: model.Requirement{ID: req.ID, Title: req.Title, Content: block},
+		}},
+	}
+	if err := merger.Merge(delta); err != nil {
+		return nil, err
+	}
+	item.Done = want
+	return &item, nil
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+const criteriaSpec = `# Auth
+
+### Requirement: Login
+
+Users log in.
+
+- [ ] Password is checked
+- [X] Lockout after 5 tries
+
+> Rationale: carried over from
+> - [ ] the old checklist
+
+` + "```" + `
+- [ ] not a criterion
+` + "```" + `
+
+### Requirement: Logout
+
+Users log out.
+`
+
+func TestSpecParser_ParsesAcceptanceCriteria(t *testing.T) {
+	spec, err := NewSpecParser().Parse([]byte(criteriaSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := spec.Requirements[0].AcceptanceCriteria
+	if len(got) != 2 {
+		t.Fatalf("criteria = %+v", got)
+	}
+	if got[0].Index != 1 || got[0].Text != "Password is checked" || got[0].Done {
+		t.Fatalf("first criterion = %+v", got[0])
+	}
+	if got[1].Index != 2 || got[1].Text != "Lockout after 5 tries" || !got[1].Done {
+		t.Fatalf("second criterion = %+v", got[1])
+	}
+	if criteriaSpec[got[1].Mark] != 'X' {
+		t.Fatalf("mark offset %d points at %q", got[1].Mark, criteriaSpec[got[1].Mark])
+	}
+	if len(spec.Requirements[1].AcceptanceCriteria) != 0 {
+		t.Fatalf("logout criteria = %+v", spec.Requirements[1].AcceptanceCriteria)
+	}
+}
+
+func TestSetAcceptanceCriterion(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "auth", Content: criteriaSpec}); err != nil {
+		t.Fatal(err)
+	}
+
+	done := true
+	c, err := SetAcceptanceCriterion(app.SpecManager, app.SpecMerger, "auth", "login", 1, &done)
+	if err != nil || !c.Done {
+		t.Fatalf("check = %+v, %v", c, err)
+	}
+	// Toggle without an explicit state.
+	if c, err = SetAcceptanceCriterion(app.SpecManager, app.SpecMerger, "auth", "login", 2, nil); err != nil || c.Done {
+		t.Fatalf("toggle = %+v, %v", c, err)
+	}
+
+	spec, err := app.SpecManager.ReadSpec("auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Replace(strings.Replace(criteriaSpec, "- [ ] Password", "- [x] Password", 1), "- [X] Lockout", "- [ ] Lockout", 1)
+	if spec.Content != want {
+		t.Fatalf("content:\n%s", spec.Content)
+	}
+
+	if _, err := SetAcceptanceCriterion(app.SpecManager, app.SpecMerger, "auth", "login", 3, nil); err == nil {
+		t.Fatal("expected out-of-range item to fail")
+	} else if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+		t.Fatalf("expected ErrNotFound, got %T", err)
+	}
+}
@@ -0,0 +1,207 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// AgentInstructionsSection is the charter section exported by `agents export`.
+const AgentInstructionsSection = "AI Agent Instructions"
+
+// agentsGeneratedMarker opens every exported file; files without it were
+// written by hand and are never overwritten.
+const agentsGeneratedMarker = "Generated by teamwerx agents export"
+
+// AgentTargets maps each `agents export` target to the file it writes,
+// relative to the workspace root.
+var AgentTargets = map[string]string{
+	"claude": "CLAUDE.md",
+	"cursor": ".cursorrules",
+	"agents": "AGENTS.md",
+}
+
+// AgentTargetNames returns the target names, sorted.
+func AgentTargetNames() []string {
+	names := make([]string, 0, len(AgentTargets))
+	for name := range AgentTargets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Agent export outcomes.
+const (
+	AgentFileWritten   = "written"
+	AgentFileUnchanged = "unchanged"
+	AgentFileStale     = "stale"   // --check: the file differs from the charter
+	AgentFileMissing   = "missing" // --check: the file does not exist
+)
+
+// AgentExport is the outcome for one target.
+type AgentExport struct {
+	Target string `json:"target"`
+	File   string `json:"file"`
+	Status string `json:"status"`
+}
+
+// AgentExportOptions configures ExportAgentInstructions.
+type AgentExportOptions struct {
+	// Root is the directory the files are written to (default ".").
+	Root string
+	// Targets are AgentTargets keys; empty means every target.
+	Targets []string
+	// Check compares the files with the charter without writing them.
+	Check bool
+}
+
+// CharterSection returns the body of the charter's "## <heading>" section,
+// trimmed, or "" when the section is missing. Headings are matched without
+// regard to case; fenced code is never mistaken for a heading.
+func CharterSection(content, heading string) string {
+	var body []string
+	in, fence := false, ""
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		} else if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+		} else if strings.HasPrefix(line, "# ") || strings.HasPrefix(line, "## ") {
+			if in {
+				break
+			}
+			in = strings.HasPrefix(line, "## ") && strings.EqualFold(strings.TrimSpace(line[3:]), heading)
+			continue
+		}
+		if in {
+			body = append(body, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(body, "\n"))
+}
+
+// isPlaceholder reports whether text is a bracketed charter init placeholder
+// such as "[Specific instructions for AI coding assistants ...]".
+func isPlaceholder(text string) bool {
+	return strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]") && !strings.Contains(text, "\n")
+}
+
+// RenderAgentInstructions builds an instruction file from the charter: its
+// purpose, the AI Agent Instructions section, tech stack and conventions,
+// followed by where the workspace keeps specs, goals and changes.
+func (a *App) RenderAgentInstructions(charter *model.Charter) (string, error) {
+	instructions := CharterSection(charter.Content, AgentInstructionsSection)
+	if instructions == "" || isPlaceholder(instructions) {
+		return "", custom_errors.NewErrValidation("charter", fmt.Sprintf("the %q section is missing or still a placeholder", "## "+AgentInstructionsSection))
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "<!-- %s from %s; edit the charter instead. -->\n\n", agentsGeneratedMarker, filepath.ToSlash(a.Options.Layout.CharterPath(a.Options.CharterDir)))
+	title := strings.TrimSpace(charter.Title)
+	if title == "" {
+		title = "Project"
+	}
+	fmt.Fprintf(&b, "# %s: agent instructions\n\n", title)
+	if purpose := strings.TrimSpace(charter.Purpose); purpose != "" {
+		fmt.Fprintf(&b, "%s\n\n", purpose)
+	}
+	fmt.Fprintf(&b, "## Instructions\n\n%s\n\n", instructions)
+
+	if len(charter.TechStack) > 0 {
+		b.WriteString("## Tech stack\n\n")
+		for _, t := range charter.TechStack {
+			fmt.Fprintf(&b, "- %s\n", t)
+		}
+		b.WriteString("\n")
+	}
+	if len(charter.Conventions) > 0 {
+		b.WriteString("## Conventions\n\n")
+		keys := make([]string, 0, len(charter.Conventions))
+		for k := range charter.Conventions {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(&b, "- %s: %v\n", k, charter.Conventions[k])
+		}
+		b.WriteString("\n")
+	}
+
+	o := a.Options
+	b.WriteString("## Workspace\n\n")
+	b.WriteString("This project plans work with teamwerx. Read the relevant files before changing code:\n\n")
+	fmt.Fprintf(&b, "- Specs: `%s` (one spec per domain)\n", filepath.ToSlash(o.Layout.SpecPath(o.SpecsDir, "<domain>")))
+	fmt.Fprintf(&b, "- Goal plans: `%s`\n", filepath.ToSlash(o.Layout.PlanPath(o.GoalsDir, "<goal>")))
+	fmt.Fprintf(&b, "- Goal discussions: `%s`\n", filepath.ToSlash(o.Layout.DiscussionPath(o.GoalsDir, "<goal>")))
+	fmt.Fprintf(&b, "- Pending changes: `%s`\n", filepath.ToSlash(o.Layout.ChangePath(o.ChangesDir, "<change>")))
+	b.WriteString("\nRecord decisions with `teamwerx discuss add` and mark finished tasks with `teamwerx plan complete` rather than editing those files by hand.\n")
+	return b.String(), nil
+}
+
+// ExportAgentInstructions writes the rendered charter instructions to each
+// target's file, or with Check reports which files are missing or stale.
+// A file that exists without the generated marker is left alone and is an
+// ErrConflict, so hand-written instructions are never clobbered.
+func (a *App) ExportAgentInstructions(opts AgentExportOptions) ([]AgentExport, error) {
+	targets := opts.Targets
+	if len(targets) == 0 {
+		targets = AgentTargetNames()
+	}
+	root := opts.Root
+	if root == "" {
+		root = "."
+	}
+	for _, t := range targets {
+		if _, ok := AgentTargets[t]; !ok {
+			return nil, custom_errors.NewErrValidation("target", fmt.Sprintf("unknown target %q (want %s)", t, strings.Join(AgentTargetNames(), ", ")))
+		}
+	}
+
+	charter, err := a.CharterManager.ReadEffective()
+	if err != nil {
+		return nil, err
+	}
+	text, err := a.RenderAgentInstructions(charter)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []AgentExport
+	for _, t := range targets {
+		path := filepath.Join(root, AgentTargets[t])
+		r := AgentExport{Target: t, File: path}
+		current, err := a.Options.Storage.ReadFile(path)
+		exists := err == nil
+		if err != nil {
+			if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+				return results, err
+			}
+		}
+		switch {
+		case exists && string(current) == text:
+			r.Status = AgentFileUnchanged
+		case exists && !bytes.Contains(current, []byte(agentsGeneratedMarker)):
+			return results, custom_errors.NewErrConflict(fmt.Sprintf("%s was not generated by teamwerx; move its instructions into the charter's %q section and delete it", path, AgentInstructionsSection))
+		case opts.Check && exists:
+			r.Status = AgentFileStale
+		case opts.Check:
+			r.Status = AgentFileMissing
+		default:
+			if err := a.Options.Storage.WriteFile(path, []byte(text), 0o644); err != nil {
+				return results, fmt.Errorf("failed to write %s: %w", path, err)
+			}
+			r.Status = AgentFileWritten
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestCharterSection(t *testing.T) {
+	content := "# Charter\n\n## Purpose\n\nShip it.\n\n## AI Agent Instructions\n\nRun tests.\n\n```\n## not a heading\n```\n\n## Later\n\nOther.\n"
+	if got := CharterSection(content, "ai agent instructions"); got != "Run tests.\n\n```\n## not a heading\n```" {
+		t.Fatalf("section = %q", got)
+	}
+	if got := CharterSection(content, "Missing"); got != "" {
+		t.Fatalf("missing section = %q", got)
+	}
+}
+
+func TestExportAgentInstructions(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	charter := &model.Charter{Title: "Demo", Purpose: "Ship it.", Content: "## AI Agent Instructions\n\n[Specific instructions for AI coding assistants working on this project]\n"}
+	if err := app.CharterManager.Write(charter); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.ExportAgentInstructions(AgentExportOptions{}); err == nil {
+		t.Fatal("expected a placeholder section to be rejected")
+	}
+
+	charter.Content = "## AI Agent Instructions\n\nRun go test before committing.\n"
+	if err := app.CharterManager.Write(charter); err != nil {
+		t.Fatal(err)
+	}
+	results, err := app.ExportAgentInstructions(AgentExportOptions{Check: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 || results[0].Status != AgentFileMissing {
+		t.Fatalf("check before export = %+v", results)
+	}
+	if _, err := app.ExportAgentInstructions(AgentExportOptions{Targets: []string{"claude"}}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := app.Options.Storage.ReadFile("CLAUDE.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "Run go test before committing.") || !strings.Contains(string(data), agentsGeneratedMarker) {
+		t.Fatalf("CLAUDE.md = %s", data)
+	}
+
+	charter.Content = "## AI Agent Instructions\n\nRun go vet too.\n"
+	if err := app.CharterManager.Write(charter); err != nil {
+		t.Fatal(err)
+	}
+	results, err = app.ExportAgentInstructions(AgentExportOptions{Targets: []string{"claude"}, Check: true})
+	if err != nil || results[0].Status != AgentFileStale {
+		t.Fatalf("check after charter edit = %+v, %v", results, err)
+	}
+
+	if err := app.Options.Storage.WriteFile("AGENTS.md", []byte("hand-written\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = app.ExportAgentInstructions(AgentExportOptions{Targets: []string{"agents"}})
+	if _, ok := err.(*custom_errors.ErrConflict); !ok {
+		t.Fatalf("expected ErrConflict for a hand-written file, got %v", err)
+	}
+}
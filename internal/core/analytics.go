@@ -0,0 +1,215 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/storage"
+	fileutil "github.com/teamwerx/teamwerx/internal/utils/file"
+)
+
+// DefaultAnalyticsFile is the analytics log inside the workspace directory.
+const DefaultAnalyticsFile = "analytics.jsonl"
+
+// Error categories recorded by analytics. Error messages themselves are never
+// recorded, only these coarse categories.
+const (
+	ErrorCategoryNone       = ""
+	ErrorCategoryNotFound   = "not-found"
+	ErrorCategoryConflict   = "conflict"
+	ErrorCategoryDiverged   = "diverged"
+	ErrorCategoryValidation = "validation"
+	ErrorCategoryUsage      = "usage"
+	ErrorCategoryOther      = "other"
+)
+
+// AnalyticsConfig is the "analytics" section of config.yaml. Analytics are off
+// unless enabled, and events only ever go to a local file.
+//
+//	analytics:
+//	  enabled: true
+//	  file: analytics.jsonl   # relative to the workspace directory
+type AnalyticsConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	File    string `yaml:"file,omitempty"`
+}
+
+// Path returns the analytics log location for a workspace directory.
+func (c AnalyticsConfig) Path(charterDir string) string {
+	file := c.File
+	if file == "" {
+		file = DefaultAnalyticsFile
+	}
+	if filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(charterDir, file)
+}
+
+// AnalyticsEvent is one command invocation, stored as a JSON line.
+type AnalyticsEvent struct {
+	Time          time.Time `json:"time"`
+	Command       string    `json:"command"` // e.g. "change apply"
+	ErrorCategory string    `json:"error_category,omitempty"`
+	DurationMS    int64     `json:"duration_ms"`
+}
+
+// CommandStats aggregates the events for one command.
+type CommandStats struct {
+	Command string         `json:"command"`
+	Count   int            `json:"count"`
+	Errors  map[string]int `json:"errors,omitempty"` // by error category
+}
+
+// AnalyticsReport summarizes an analytics log.
+type AnalyticsReport struct {
+	Events   int            `json:"events"`
+	From     time.Time      `json:"from,omitempty"`
+	To       time.Time      `json:"to,omitempty"`
+	Commands []CommandStats `json:"commands"`
+	Errors   map[string]int `json:"errors,omitempty"` // by error category, across commands
+}
+
+// ClassifyError maps an error onto an analytics error category.
+func ClassifyError(err error) string {
+	if err == nil {
+		return ErrorCategoryNone
+	}
+	switch err.(type) {
+	case *custom_errors.ErrNotFound:
+		return ErrorCategoryNotFound
+	case *custom_errors.ErrConflict:
+		return ErrorCategoryConflict
+	case *custom_errors.ErrDiverged:
+		return ErrorCategoryDiverged
+	case *DeltaValidationError, *SecretsDetectedError, *custom_errors.ErrValidation, custom_errors.ValidationErrors:
+		return ErrorCategoryValidation
+	}
+	if custom_errors.Validation(err) != nil {
+		return ErrorCategoryValidation
+	}
+	msg := err.Error()
+	for _, prefix := range []string{"unknown command", "unknown flag", "unknown shorthand flag", "required flag", "accepts ", "requires at least", "invalid argument"} {
+		if strings.HasPrefix(msg, prefix) {
+			return ErrorCategoryUsage
+		}
+	}
+	// Wrapped errors (fmt.Errorf("...: %w")) keep their category.
+	if u, ok := err.(interface{ Unwrap() error }); ok && u.Unwrap() != nil {
+		return ClassifyError(u.Unwrap())
+	}
+	return ErrorCategoryOther
+}
+
+// RecordAnalytics appends ev to the workspace analytics log when analytics are
+// enabled in <charterDir>/config.yaml. It is a no-op otherwise, and in a
+// workspace locked read-only; it is an ErrConflict while another process
+// holds the writer lease.
+func RecordAnalytics(charterDir string, ev AnalyticsEvent) error {
+	cfg, err := LoadConfig(ConfigPath(charterDir))
+	if err != nil {
+		return err
+	}
+	if !cfg.Analytics.Enabled || cfg.ReadOnly {
+		return nil
+	}
+	path := cfg.Analytics.Path(charterDir)
+
+	line, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	// Storage has no append, so the log is written directly; the writer
+	// lease still applies to it.
+	if err := (leaseGuardStorage{Storage: storage.OS(), charterDir: charterDir}).check("append to", path); err != nil {
+		return err
+	}
+	unlock, err := fileutil.Lock(path, 0)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadAnalytics reads every event from an analytics log. A missing file yields
+// no events; malformed lines are skipped.
+func LoadAnalytics(path string) ([]AnalyticsEvent, error) {
+	data, err := fileutil.ReadFile(path)
+	if err != nil {
+		if _, ok := err.(*custom_errors.ErrNotFound); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var events []AnalyticsEvent
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		var ev AnalyticsEvent
+		if json.Unmarshal(sc.Bytes(), &ev) == nil && ev.Command != "" {
+			events = append(events, ev)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read analytics '%s': %w", path, err)
+	}
+	return events, nil
+}
+
+// SummarizeAnalytics counts events per command (most used first, then by name)
+// and per error category.
+func SummarizeAnalytics(events []AnalyticsEvent) AnalyticsReport {
+	report := AnalyticsReport{Events: len(events), Commands: []CommandStats{}}
+	byCommand := map[string]*CommandStats{}
+	for _, ev := range events {
+		if report.From.IsZero() || ev.Time.Before(report.From) {
+			report.From = ev.Time
+		}
+		if ev.Time.After(report.To) {
+			report.To = ev.Time
+		}
+		st := byCommand[ev.Command]
+		if st == nil {
+			st = &CommandStats{Command: ev.Command}
+			byCommand[ev.Command] = st
+		}
+		st.Count++
+		if ev.ErrorCategory != "" {
+			if st.Errors == nil {
+				st.Errors = map[string]int{}
+			}
+			st.Errors[ev.ErrorCategory]++
+			if report.Errors == nil {
+				report.Errors = map[string]int{}
+			}
+			report.Errors[ev.ErrorCategory]++
+		}
+	}
+	for _, st := range byCommand {
+		report.Commands = append(report.Commands, *st)
+	}
+	sort.Slice(report.Commands, func(i, j int) bool {
+		if report.Commands[i].Count != report.Commands[j].Count {
+			return report.Commands[i].Count > report.Commands[j].Count
+		}
+		return report.Commands[i].Command < report.Commands[j].Command
+	})
+	return report
+}
@@ -0,0 +1,71 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+)
+
+func TestRecordAnalytics_DisabledByDefault(t *testing.T) {
+	dir := createTempDir(t)
+	if err := RecordAnalytics(dir, AnalyticsEvent{Command: "spec list", Time: time.Now()}); err != nil {
+		t.Fatalf("RecordAnalytics failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, DefaultAnalyticsFile)); !os.IsNotExist(err) {
+		t.Fatalf("expected no analytics file when disabled, stat err: %v", err)
+	}
+}
+
+func TestRecordAnalytics_EnabledAppendsAndSummarizes(t *testing.T) {
+	dir := createTempDir(t)
+	if err := os.WriteFile(filepath.Join(dir, ConfigFileName), []byte("analytics:\n  enabled: true\n"), 0o644); err != nil {
+		t.Fatalf("write config failed: %v", err)
+	}
+	base := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	events := []AnalyticsEvent{
+		{Command: "change apply", Time: base, ErrorCategory: ErrorCategoryDiverged},
+		{Command: "spec list", Time: base.Add(time.Hour)},
+		{Command: "change apply", Time: base.Add(2 * time.Hour)},
+	}
+	for _, ev := range events {
+		if err := RecordAnalytics(dir, ev); err != nil {
+			t.Fatalf("RecordAnalytics failed: %v", err)
+		}
+	}
+
+	loaded, err := LoadAnalytics(AnalyticsConfig{}.Path(dir))
+	if err != nil {
+		t.Fatalf("LoadAnalytics failed: %v", err)
+	}
+	report := SummarizeAnalytics(loaded)
+	if report.Events != 3 || !report.From.Equal(base) || !report.To.Equal(base.Add(2*time.Hour)) {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if len(report.Commands) != 2 || report.Commands[0].Command != "change apply" || report.Commands[0].Count != 2 {
+		t.Fatalf("unexpected command stats: %+v", report.Commands)
+	}
+	if report.Commands[0].Errors[ErrorCategoryDiverged] != 1 || report.Errors[ErrorCategoryDiverged] != 1 {
+		t.Fatalf("unexpected error counts: %+v", report)
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	cases := map[string]error{
+		ErrorCategoryNone:       nil,
+		ErrorCategoryNotFound:   fmt.Errorf("failed to read change: %w", custom_errors.NewErrNotFound("change", "x")),
+		ErrorCategoryConflict:   custom_errors.NewErrConflict("boom"),
+		ErrorCategoryDiverged:   custom_errors.NewErrDiverged("auth", "a", "b", ""),
+		ErrorCategoryValidation: &DeltaValidationError{ChangeID: "CH-1"},
+		ErrorCategoryUsage:      fmt.Errorf(`required flag(s) "goal" not set`),
+		ErrorCategoryOther:      fmt.Errorf("disk full"),
+	}
+	for want, err := range cases {
+		if got := ClassifyError(err); got != want {
+			t.Errorf("ClassifyError(%v) = %q, want %q", err, got, want)
+		}
+	}
+}
@@ -2,8 +2,9 @@ package core
 
 import (
 	"fmt"
+	"path/filepath"
 
-	fileutil "github.com/teamwerx/teamwerx/internal/utils/file"
+	"github.com/teamwerx/teamwerx/internal/storage"
 )
 
 // AppOptions defines the base directories for all managers.
@@ -14,15 +15,57 @@ import (
 //   - GoalsDir:   ".teamwerx/goals"
 //   - ChangesDir: ".teamwerx/changes"
 //   - CharterDir: ".teamwerx"
+//
+// Layout customizes file names and nesting inside those directories; NewApp
+// fills it from <CharterDir>/config.yaml, with any non-empty field set here
+// taking precedence.
+//
+// Storage is where the managers read and write files; nil means the real file
+// system. Use storage.NewInMem() (or NewAppWithStorage) for a workspace that
+// never touches disk.
+//
+// Secrets configures secret scanning of discussion entries and specs; when its
+// Mode is empty, the "secrets" section of config.yaml is used. OnSecretFindings
+// is told about secrets found in warn and redact mode.
+//
+// DiscussionTypes adds to or restyles the built-in discussion entry types;
+// when nil, the "discussion" section of config.yaml is used.
+// DiscussionRotateSize is the size in bytes past which a goal's discussion log
+// is rotated into numbered parts; zero uses discussion.rotate_size (or
+// DefaultDiscussionRotateSize) and a negative size never rotates.
+//
+// SpecFormat is how specs mark requirements; when zero, the "spec_format"
+// section of config.yaml is used.
+//
+// OnCachedCharter is told when a charter "extends:" URL could not be fetched
+// and the copy cached by an earlier fetch was used instead.
+//
+// ReadOnly makes every write through the managers fail with
+// errors.ErrReadOnly, for review environments and CI jobs that must not alter
+// the workspace. "read_only: true" in config.yaml locks it on.
 type AppOptions struct {
 	SpecsDir   string
 	GoalsDir   string
 	ChangesDir string
 	CharterDir string
+	Layout     Layout
+	Storage    storage.Storage
+
+	Secrets          SecretsConfig
+	OnSecretFindings func(source string, findings []SecretFinding)
+
+	DiscussionTypes      []DiscussionType
+	DiscussionRotateSize int64
+
+	SpecFormat SpecFormat
+
+	OnCachedCharter func(url string, err error)
+
+	ReadOnly bool
 }
 
-// withDefaults returns a copy of the options, filling in missing values.
-func (o AppOptions) withDefaults() AppOptions {
+// WithDefaults returns a copy of the options, filling in missing values.
+func (o AppOptions) WithDefaults() AppOptions {
 	if o.SpecsDir == "" {
 		o.SpecsDir = ".teamwerx/specs"
 	}
@@ -35,9 +78,19 @@ func (o AppOptions) withDefaults() AppOptions {
 	if o.CharterDir == "" {
 		o.CharterDir = ".teamwerx"
 	}
+	if o.Storage == nil {
+		o.Storage = storage.OS()
+	}
+	o.Layout = o.Layout.WithDefaults()
+	o.SpecFormat = o.SpecFormat.WithDefaults()
 	return o
 }
 
+// DefaultAppOptions returns AppOptions with every directory set to its default.
+func DefaultAppOptions() AppOptions {
+	return AppOptions{}.WithDefaults()
+}
+
 // App is a small dependency injection container that wires together
 // the core managers with consistent base directories.
 //
@@ -59,36 +112,77 @@ type App struct {
 	ChangeManager     ChangeManager
 	DiscussionManager DiscussionManager
 	CharterManager    CharterManager
+	ReviewManager     ReviewManager
+
+	// DiscussionTypes are the entry types DiscussionManager accepts.
+	DiscussionTypes *DiscussionTypeRegistry
 }
 
 // NewApp constructs an App with the provided options, applying defaults for any
-// missing paths, ensuring directories exist, and wiring all managers together.
+// missing paths, loading the layout from <CharterDir>/config.yaml, ensuring
+// directories exist, and wiring all managers together.
 //
 // Returns a fully initialized App or an error if required directories cannot be created.
 func NewApp(opts AppOptions) (*App, error) {
-	o := opts.withDefaults()
+	o, err := opts.Resolve()
+	if err != nil {
+		return nil, err
+	}
 
-	// Ensure base directories exist so downstream file ops don't fail unexpectedly.
-	if err := fileutil.MkdirAll(o.SpecsDir, 0o755); err != nil {
-		return nil, fmt.Errorf("ensure specs dir: %w", err)
+	// Ensure base directories exist so downstream file ops don't fail
+	// unexpectedly. A read-only workspace is taken as it is.
+	if !o.ReadOnly {
+		if err := o.Storage.MkdirAll(o.SpecsDir, 0o755); err != nil {
+			return nil, fmt.Errorf("ensure specs dir: %w", err)
+		}
+		if err := o.Storage.MkdirAll(o.GoalsDir, 0o755); err != nil {
+			return nil, fmt.Errorf("ensure goals dir: %w", err)
+		}
+		if err := o.Storage.MkdirAll(o.ChangesDir, 0o755); err != nil {
+			return nil, fmt.Errorf("ensure changes dir: %w", err)
+		}
+		if err := o.Storage.MkdirAll(o.CharterDir, 0o755); err != nil {
+			return nil, fmt.Errorf("ensure charter dir: %w", err)
+		}
 	}
-	if err := fileutil.MkdirAll(o.GoalsDir, 0o755); err != nil {
-		return nil, fmt.Errorf("ensure goals dir: %w", err)
+
+	// Record every artifact write in the integrity manifest (see VerifyWorkspace).
+	if _, ok := o.Storage.(*manifestStorage); !ok {
+		o.Storage = &manifestStorage{Storage: o.Storage, tracker: newManifestTracker(o)}
 	}
-	if err := fileutil.MkdirAll(o.ChangesDir, 0o755); err != nil {
-		return nil, fmt.Errorf("ensure changes dir: %w", err)
+	// Writes fail fast while another process holds the writer lease.
+	if _, ok := o.Storage.(leaseGuardStorage); !ok {
+		o.Storage = leaseGuardStorage{Storage: o.Storage, charterDir: o.CharterDir}
 	}
-	if err := fileutil.MkdirAll(o.CharterDir, 0o755); err != nil {
-		return nil, fmt.Errorf("ensure charter dir: %w", err)
+	if _, ok := o.Storage.(readOnlyStorage); o.ReadOnly && !ok {
+		o.Storage = readOnlyStorage{Storage: o.Storage}
+	}
+
+	secrets, err := NewSecretScanner(o.Secrets, o.OnSecretFindings)
+	if err != nil {
+		return nil, err
+	}
+	entryTypes, err := NewDiscussionTypeRegistry(DiscussionConfig{Types: o.DiscussionTypes})
+	if err != nil {
+		return nil, err
 	}
 
 	// Wire managers
-	specMgr := NewSpecManager(o.SpecsDir)
+	specMgr := newSpecManager(o.SpecsDir, o.Layout, o.Storage, secrets, o.SpecFormat)
 	specMerger := NewSpecMerger(specMgr)
-	planMgr := NewPlanManager(o.GoalsDir)
-	changeMgr := NewChangeManager(o.ChangesDir, specMgr, specMerger)
-	discMgr := NewDiscussionManager(o.GoalsDir)
-	charterMgr := NewCharterManager(o.CharterDir)
+	planMgr := newPlanManager(o.GoalsDir, o.Layout, o.Storage)
+	changeMgr := newChangeManager(o.ChangesDir, o.Layout, specMgr, specMerger, o.Storage, func() (*Owners, error) {
+		return loadOwners(o.Storage, filepath.Join(o.CharterDir, OwnersFileName))
+	})
+	rotateSize := o.DiscussionRotateSize
+	if rotateSize == 0 {
+		rotateSize = DefaultDiscussionRotateSize
+	} else if rotateSize < 0 {
+		rotateSize = 0
+	}
+	discMgr := newDiscussionManager(o.GoalsDir, o.Layout, o.Storage, secrets, entryTypes, rotateSize)
+	charterMgr := newCharterManager(o.CharterDir, o.Layout, o.Storage, o.OnCachedCharter)
+	reviewMgr := newReviewManager(o.SpecsDir, o.Layout, o.Storage)
 
 	return &App{
 		Options:           o,
@@ -98,9 +192,78 @@ func NewApp(opts AppOptions) (*App, error) {
 		ChangeManager:     changeMgr,
 		DiscussionManager: discMgr,
 		CharterManager:    charterMgr,
+		ReviewManager:     reviewMgr,
+		DiscussionTypes:   entryTypes,
 	}, nil
 }
 
+// NewAppWithStorage constructs an App with default options whose managers read
+// and write through store, e.g. storage.NewInMem() for tests or embedding.
+func NewAppWithStorage(store storage.Storage) (*App, error) {
+	return NewApp(AppOptions{Storage: store})
+}
+
+// Resolve applies directory defaults and merges the layout from the workspace
+// config file underneath any layout fields set explicitly on the options.
+func (o AppOptions) Resolve() (AppOptions, error) {
+	explicit, format := o.Layout, o.SpecFormat
+	o = o.WithDefaults()
+
+	cfg, err := loadConfig(o.Storage, ConfigPath(o.CharterDir))
+	if err != nil {
+		return o, err
+	}
+	layout := cfg.Layout
+	overrideLayout(&layout, explicit)
+	o.Layout = layout.WithDefaults()
+	if o.Secrets.Mode == "" {
+		o.Secrets = cfg.Secrets
+	}
+	if o.DiscussionTypes == nil {
+		o.DiscussionTypes = cfg.Discussion.Types
+	}
+	if o.DiscussionRotateSize == 0 {
+		o.DiscussionRotateSize = cfg.Discussion.RotateBytes()
+	}
+	if format == (SpecFormat{}) {
+		format = cfg.SpecFormat
+	}
+	o.SpecFormat = format.WithDefaults()
+	if err := o.SpecFormat.Validate(); err != nil {
+		return o, err
+	}
+	// The config can lock read-only mode on, never off.
+	o.ReadOnly = o.ReadOnly || cfg.ReadOnly
+	if err := o.Layout.Validate(); err != nil {
+		return o, err
+	}
+	return o, nil
+}
+
+// overrideLayout copies every non-empty field of src onto dst.
+func overrideLayout(dst *Layout, src Layout) {
+	for _, f := range []struct{ d, s *string }{
+		{&dst.SpecFile, &src.SpecFile},
+		{&dst.ReviewFile, &src.ReviewFile},
+		{&dst.PlanFile, &src.PlanFile},
+		{&dst.DiscussionFile, &src.DiscussionFile},
+		{&dst.AttachmentsDir, &src.AttachmentsDir},
+		{&dst.ChangeFile, &src.ChangeFile},
+		{&dst.ArchiveDir, &src.ArchiveDir},
+		{&dst.CharterFile, &src.CharterFile},
+	} {
+		if *f.s != "" {
+			*f.d = *f.s
+		}
+	}
+}
+
+// Owners loads the domain ownership rules from <CharterDir>/OWNERS.
+// A missing file yields empty rules.
+func (a *App) Owners() (*Owners, error) {
+	return loadOwners(a.Options.Storage, filepath.Join(a.Options.CharterDir, OwnersFileName))
+}
+
 // NewDefaultApp is a convenience constructor that builds an App using all default
 // directories under .teamwerx/*.
 //
@@ -0,0 +1,124 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	ce "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestNewAppWithStorage_InMemoryWorkflow(t *testing.T) {
+	// Run from an empty directory so any stray disk write would be visible.
+	wd, _ := os.Getwd()
+	dir := createTempDir(t)
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	store := storage.NewInMem()
+	app, err := NewAppWithStorage(store)
+	if err != nil {
+		t.Fatalf("NewAppWithStorage: %v", err)
+	}
+
+	if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "auth", Content: "# Auth\n\n### Requirement: Login\n\nUsers log in.\n"}); err != nil {
+		t.Fatalf("WriteSpec: %v", err)
+	}
+	spec, err := app.SpecManager.ReadSpec("auth")
+	if err != nil {
+		t.Fatalf("ReadSpec: %v", err)
+	}
+
+	ch := &model.Change{ID: "CH-001", SpecDeltas: []model.SpecDelta{{
+		Domain:          "auth",
+		BaseFingerprint: spec.Fingerprint,
+		Operations: []model.DeltaOperation{{
+			Type:        "ADDED",
+			Requirement: model.Requirement{ID: "logout", Title: "Logout", Content: "### Requirement: Logout\n\nUsers log out.\n"},
+		}},
+	}}}
+	if err := app.ChangeManager.Save(ch); err != nil {
+		t.Fatalf("Save change: %v", err)
+	}
+	if err := app.ChangeManager.ApplyChange(ch); err != nil {
+		t.Fatalf("ApplyChange: %v", err)
+	}
+	if err := app.ChangeManager.ArchiveChange(ch); err != nil {
+		t.Fatalf("ArchiveChange: %v", err)
+	}
+	if changes, err := app.ChangeManager.ListChanges(); err != nil || len(changes) != 0 {
+		t.Fatalf("expected no live changes after archive, got %d (err %v)", len(changes), err)
+	}
+	specs, err := app.SpecManager.ListSpecs()
+	if err != nil || len(specs) != 1 || len(specs[0].Requirements) != 2 {
+		t.Fatalf("expected merged spec with 2 requirements, got %+v (err %v)", specs, err)
+	}
+
+	tmpl, _ := ResolveGoalTemplate(GoalTemplateSpike, nil)
+	if _, err := CreateGoal(app.PlanManager, app.DiscussionManager, "001-auth", "Auth", tmpl); err != nil {
+		t.Fatalf("CreateGoal: %v", err)
+	}
+	if ids, err := ListGoalIDs(app.Options); err != nil || strings.Join(ids, ",") != "001-auth" {
+		t.Fatalf("ListGoalIDs = %v (err %v)", ids, err)
+	}
+
+	files := strings.Join(store.Files(), "\n")
+	for _, want := range []string{"auth/spec.md", ".archive/CH-001/change.json", "001-auth/plan.json", "001-auth/discuss.md"} {
+		if !strings.Contains(files, want) {
+			t.Errorf("in-memory storage missing %s; have:\n%s", want, files)
+		}
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Fatalf("in-memory app wrote to disk: %v", entries)
+	}
+}
+
+func TestNewApp_ReadOnlyRefusesWrites(t *testing.T) {
+	store := storage.NewInMem()
+	app, err := NewAppWithStorage(store)
+	if err != nil {
+		t.Fatalf("NewAppWithStorage: %v", err)
+	}
+	if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "auth", Content: "# Auth\n\n### Requirement: Login\n\nUsers log in.\n"}); err != nil {
+		t.Fatalf("WriteSpec: %v", err)
+	}
+	before := strings.Join(store.Files(), "\n")
+
+	ro, err := NewApp(AppOptions{Storage: store, ReadOnly: true})
+	if err != nil {
+		t.Fatalf("NewApp read-only: %v", err)
+	}
+	if _, err := ro.SpecManager.ReadSpec("auth"); err != nil {
+		t.Fatalf("ReadSpec: %v", err)
+	}
+	var refused *ce.ErrReadOnly
+	if err := ro.SpecManager.WriteSpec(&model.Spec{Domain: "billing", Content: "# Billing\n"}); !errors.As(err, &refused) {
+		t.Fatalf("expected ErrReadOnly from WriteSpec, got %v", err)
+	}
+	if err := ro.ChangeManager.Save(&model.Change{ID: "CH-001"}); !errors.As(err, &refused) {
+		t.Fatalf("expected ErrReadOnly from Save, got %v", err)
+	}
+	if after := strings.Join(store.Files(), "\n"); after != before {
+		t.Fatalf("read-only app changed storage:\n%s\nwant:\n%s", after, before)
+	}
+
+	// "read_only: true" in config.yaml locks every App read-only.
+	if err := store.WriteFile(ConfigPath(".teamwerx"), []byte("read_only: true\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	locked, err := NewAppWithStorage(store)
+	if err != nil {
+		t.Fatalf("NewAppWithStorage: %v", err)
+	}
+	if !locked.Options.ReadOnly {
+		t.Fatal("expected the config to lock read-only mode on")
+	}
+	if err := locked.PlanManager.Save(&model.Plan{GoalID: "001-auth"}); !errors.As(err, &refused) {
+		t.Fatalf("expected ErrReadOnly from plan Save, got %v", err)
+	}
+}
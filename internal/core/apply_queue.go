@@ -0,0 +1,239 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// ApplyQueueFileName is the workspace state file, under the charter
+// directory, listing changes that a batch apply left for manual resolution.
+const ApplyQueueFileName = "apply-queue.json"
+
+// QueuedChange is a change a batch apply skipped because its base
+// fingerprints no longer match the current specs.
+type QueuedChange struct {
+	ChangeID string    `json:"change_id"`
+	Domains  []string  `json:"domains"` // diverged domains at the last attempt
+	Reason   string    `json:"reason"`
+	QueuedAt time.Time `json:"queued_at"`
+	// Attempts counts the batch applies that hit the conflict.
+	Attempts int `json:"attempts"`
+}
+
+// ApplyQueue is the "needs-resolution" queue of diverged changes, oldest first.
+type ApplyQueue struct {
+	Changes []QueuedChange `json:"changes"`
+}
+
+// Find returns the queued entry for changeID, or nil.
+func (q *ApplyQueue) Find(changeID string) *QueuedChange {
+	for i := range q.Changes {
+		if q.Changes[i].ChangeID == changeID {
+			return &q.Changes[i]
+		}
+	}
+	return nil
+}
+
+// Enqueue records a diverged change, or refreshes its entry and counts
+// another attempt when it is already queued.
+func (q *ApplyQueue) Enqueue(changeID string, diverged []StaleDelta, now time.Time) *QueuedChange {
+	var domains []string
+	for _, d := range diverged {
+		domains = append(domains, d.Domain)
+	}
+	entry := q.Find(changeID)
+	if entry == nil {
+		q.Changes = append(q.Changes, QueuedChange{ChangeID: changeID, QueuedAt: now})
+		entry = &q.Changes[len(q.Changes)-1]
+	}
+	entry.Domains = domains
+	entry.Reason = fmt.Sprintf("base fingerprint diverged in %d domain(s)", len(domains))
+	entry.Attempts++
+	return entry
+}
+
+// Remove drops changeID from the queue and reports whether it was queued.
+func (q *ApplyQueue) Remove(changeID string) bool {
+	for i := range q.Changes {
+		if q.Changes[i].ChangeID == changeID {
+			q.Changes = append(q.Changes[:i], q.Changes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (a *App) applyQueuePath() string {
+	return filepath.Join(a.Options.CharterDir, ApplyQueueFileName)
+}
+
+// LoadApplyQueue reads the workspace's apply queue. A missing file yields an
+// empty queue.
+func (a *App) LoadApplyQueue() (*ApplyQueue, error) {
+	q := &ApplyQueue{Changes: []QueuedChange{}}
+	data, err := a.Options.Storage.ReadFile(a.applyQueuePath())
+	if err != nil {
+		if _, ok := err.(*custom_errors.ErrNotFound); ok {
+			return q, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, q); err != nil {
+		return nil, fmt.Errorf("failed to parse apply queue '%s': %w", a.applyQueuePath(), err)
+	}
+	return q, nil
+}
+
+// SaveApplyQueue writes the apply queue back to the workspace.
+func (a *App) SaveApplyQueue(q *ApplyQueue) error {
+	data, err := json.MarshalIndent(q, "", "  ")
+	if err != nil {
+		return err
+	}
+	return a.Options.Storage.WriteFile(a.applyQueuePath(), append(data, '\n'), 0o644)
+}
+
+// BatchApplyResult reports what ApplyPending did with each change.
+type BatchApplyResult struct {
+	Applied []*model.Change
+	// Queued are the changes moved to the needs-resolution queue.
+	Queued []QueuedChange
+	// Skipped maps a change ID to why it was not attempted (e.g. missing
+	// approvals); skipped changes are not queued.
+	Skipped map[string]string
+}
+
+// IsBatchApplicable reports whether ApplyPending considers a change: pending
+// and not superseded by a split or merge.
+func IsBatchApplicable(ch *model.Change) bool {
+	switch ch.Status {
+	case "split", "merged":
+		return false
+	}
+	return IsPendingChange(ch)
+}
+
+// ApplyPending applies every applicable change in ID order, each after the
+// changes it depends on. A change whose base fingerprints diverged is checked
+// up front, so it is never half-applied, and moved to the apply queue
+// instead; the batch then continues. Changes that apply cleanly leave the
+// queue. check, when non-nil, vets each change before it is attempted (e.g.
+// owner approvals); a failing check skips the change. A change depending on
+// one that was queued or skipped is skipped too. Any other error stops the
+// batch and is returned with the partial result.
+func (a *App) ApplyPending(check func(*model.Change) error) (*BatchApplyResult, error) {
+	return a.ApplyPendingMatching(ChangeFilter{}, check)
+}
+
+// ApplyPendingMatching is ApplyPending for the changes matching filter, e.g.
+// one milestone's. The filter's sort is ignored.
+func (a *App) ApplyPendingMatching(filter ChangeFilter, check func(*model.Change) error) (result *BatchApplyResult, err error) {
+	result = &BatchApplyResult{Skipped: map[string]string{}}
+	filter.Sort, filter.Order = "", ""
+	changes, err := a.ChangeManager.ListChangesFiltered(filter)
+	if err != nil {
+		return result, err
+	}
+	changes = dependencyOrder(changes)
+	queue, err := a.LoadApplyQueue()
+	if err != nil {
+		return result, err
+	}
+	// The queue is persisted even when the batch stops early.
+	changed := false
+	defer func() {
+		if !changed {
+			return
+		}
+		if serr := a.SaveApplyQueue(queue); err == nil {
+			err = serr
+		}
+	}()
+
+	// held records the changes not applied in this batch, by lower-case ID,
+	// so their dependents wait for them.
+	held := map[string]string{}
+	for _, ch := range changes {
+		if !IsBatchApplicable(ch) {
+			continue
+		}
+		if dep, why := heldDependency(ch, held); dep != "" {
+			result.Skipped[ch.ID] = fmt.Sprintf("depends on %s, which was %s", dep, why)
+			held[strings.ToLower(ch.ID)] = "skipped"
+			continue
+		}
+		if check != nil {
+			if err := check(ch); err != nil {
+				result.Skipped[ch.ID] = err.Error()
+				held[strings.ToLower(ch.ID)] = "skipped"
+				continue
+			}
+		}
+		diverged, err := FindDivergedDeltas(a.SpecManager, ch)
+		if err != nil {
+			return result, err
+		}
+		if len(diverged) > 0 {
+			result.Queued = append(result.Queued, *queue.Enqueue(ch.ID, diverged, time.Now()))
+			held[strings.ToLower(ch.ID)] = "queued for resolution"
+			changed = true
+			continue
+		}
+		if err := a.ChangeManager.ApplyChange(ch); err != nil {
+			return result, fmt.Errorf("failed to apply change %s: %w", ch.ID, err)
+		}
+		if queue.Remove(ch.ID) {
+			changed = true
+		}
+		result.Applied = append(result.Applied, ch)
+	}
+	return result, nil
+}
+
+// heldDependency returns the first of ch's dependencies held back in this
+// batch, and why, or "" when none is.
+func heldDependency(ch *model.Change, held map[string]string) (string, string) {
+	for _, dep := range ch.DependsOn {
+		dep = strings.TrimSpace(dep)
+		if why, ok := held[strings.ToLower(dep)]; ok {
+			return dep, why
+		}
+	}
+	return "", ""
+}
+
+// dependencyOrder returns changes, in their order, with each change moved
+// after the changes it depends on. Dependency cycles keep their order.
+func dependencyOrder(changes []*model.Change) []*model.Change {
+	byID := map[string]*model.Change{}
+	for _, ch := range changes {
+		byID[strings.ToLower(ch.ID)] = ch
+	}
+	out := make([]*model.Change, 0, len(changes))
+	state := map[*model.Change]int{} // 1 visiting, 2 done
+	var visit func(ch *model.Change)
+	visit = func(ch *model.Change) {
+		if state[ch] != 0 {
+			return
+		}
+		state[ch] = 1
+		for _, dep := range ch.DependsOn {
+			if d := byID[strings.ToLower(strings.TrimSpace(dep))]; d != nil {
+				visit(d)
+			}
+		}
+		state[ch] = 2
+		out = append(out, ch)
+	}
+	for _, ch := range changes {
+		visit(ch)
+	}
+	return out
+}
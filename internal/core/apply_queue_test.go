@@ -0,0 +1,134 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestApplyPending_QueuesDivergedAndContinues(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := &model.Spec{Domain: "auth", Content: "# Auth\n\n### Requirement: Login\n\nUsers log in.\n"}
+	if err := app.SpecManager.WriteSpec(spec); err != nil {
+		t.Fatal(err)
+	}
+	billing := &model.Spec{Domain: "billing", Content: "# Billing\n\n### Requirement: Invoice\n\nInvoices are sent.\n"}
+	if err := app.SpecManager.WriteSpec(billing); err != nil {
+		t.Fatal(err)
+	}
+
+	added := func(id string) []model.DeltaOperation {
+		return []model.DeltaOperation{{Type: "ADDED", Requirement: model.Requirement{ID: id, Title: id, Content: "### Requirement: " + id + "\n\nNew.\n"}}}
+	}
+	for _, ch := range []*model.Change{
+		{ID: "CH-001", Status: "draft", SpecDeltas: []model.SpecDelta{{Domain: "auth", BaseFingerprint: spec.Fingerprint, Operations: added("Logout")}}},
+		{ID: "CH-002", Status: "draft", SpecDeltas: []model.SpecDelta{{Domain: "billing", BaseFingerprint: "stale", Operations: added("Refund")}}},
+		{ID: "CH-003", Status: "split", SpecDeltas: []model.SpecDelta{{Domain: "auth", Operations: added("Ignored")}}},
+		{ID: "CH-004", Status: "draft", SpecDeltas: []model.SpecDelta{{Domain: "auth", Operations: added("Reset")}}},
+	} {
+		if err := app.ChangeManager.Save(ch); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := app.ApplyPending(nil)
+	if err != nil {
+		t.Fatalf("ApplyPending: %v", err)
+	}
+	var applied []string
+	for _, ch := range result.Applied {
+		applied = append(applied, ch.ID)
+	}
+	if !reflect.DeepEqual(applied, []string{"CH-001", "CH-004"}) {
+		t.Fatalf("applied = %v", applied)
+	}
+	if len(result.Queued) != 1 || result.Queued[0].ChangeID != "CH-002" || !reflect.DeepEqual(result.Queued[0].Domains, []string{"billing"}) {
+		t.Fatalf("queued = %+v", result.Queued)
+	}
+
+	// A second batch retries the queued change and counts the attempt.
+	if _, err := app.ApplyPending(nil); err != nil {
+		t.Fatal(err)
+	}
+	queue, err := app.LoadApplyQueue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queue.Changes) != 1 || queue.Changes[0].Attempts != 2 {
+		t.Fatalf("queue = %+v", queue.Changes)
+	}
+
+	// Once rebased it applies and leaves the queue.
+	ch, err := app.ChangeManager.ReadChange("CH-002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := app.SpecManager.ReadSpec("billing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch.SpecDeltas[0].BaseFingerprint = current.Fingerprint
+	if err := app.ChangeManager.Save(ch); err != nil {
+		t.Fatal(err)
+	}
+	if result, err = app.ApplyPending(nil); err != nil || len(result.Applied) != 1 {
+		t.Fatalf("retry = %+v, %v", result, err)
+	}
+	if queue, _ = app.LoadApplyQueue(); len(queue.Changes) != 0 {
+		t.Fatalf("queue not drained: %+v", queue.Changes)
+	}
+}
+
+func TestApplyPending_HoldsDependentsOfQueuedAndSkipped(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := &model.Spec{Domain: "auth", Content: "# Auth\n\n### Requirement: Login\n\nUsers log in.\n"}
+	if err := app.SpecManager.WriteSpec(spec); err != nil {
+		t.Fatal(err)
+	}
+	added := func(id string) []model.DeltaOperation {
+		return []model.DeltaOperation{{Type: "ADDED", Requirement: model.Requirement{ID: id, Title: id, Content: "### Requirement: " + id + "\n\nNew.\n"}}}
+	}
+	for _, ch := range []*model.Change{
+		{ID: "CH-001", Status: "draft", SpecDeltas: []model.SpecDelta{{Domain: "auth", BaseFingerprint: "stale", Operations: added("Logout")}}},
+		{ID: "CH-002", Status: "draft", DependsOn: []string{"CH-001"}, SpecDeltas: []model.SpecDelta{{Domain: "auth", Operations: added("Reset")}}},
+		{ID: "CH-003", Status: "draft", DependsOn: []string{"ch-002"}, SpecDeltas: []model.SpecDelta{{Domain: "auth", Operations: added("Lockout")}}},
+		{ID: "CH-004", Status: "draft", SpecDeltas: []model.SpecDelta{{Domain: "auth", Operations: added("Audit")}}},
+		{ID: "CH-005", Status: "draft", DependsOn: []string{"CH-004"}, SpecDeltas: []model.SpecDelta{{Domain: "auth", Operations: added("Export")}}},
+	} {
+		if err := app.ChangeManager.Save(ch); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	check := func(ch *model.Change) error {
+		if ch.ID == "CH-004" {
+			return errors.New("missing approval")
+		}
+		return nil
+	}
+	result, err := app.ApplyPending(check)
+	if err != nil {
+		t.Fatalf("ApplyPending: %v", err)
+	}
+	if len(result.Applied) != 0 || len(result.Queued) != 1 || result.Queued[0].ChangeID != "CH-001" {
+		t.Fatalf("applied %d, queued %+v", len(result.Applied), result.Queued)
+	}
+	want := map[string]string{
+		"CH-002": "depends on CH-001, which was queued for resolution",
+		"CH-003": "depends on ch-002, which was skipped",
+		"CH-004": "missing approval",
+		"CH-005": "depends on CH-004, which was skipped",
+	}
+	if !reflect.DeepEqual(result.Skipped, want) {
+		t.Fatalf("skipped = %v", result.Skipped)
+	}
+}
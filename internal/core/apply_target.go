@@ -0,0 +1,87 @@
+package core
+
+import (
+	"strings"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// ChangeStatusPartiallyApplied marks a change some of whose operations have
+// been merged by a targeted apply.
+const ChangeStatusPartiallyApplied = "partially-applied"
+
+// ApplyTarget selects part of a change for ApplyChangeTargets. An operation is
+// selected when its domain is in Domains and it matches one of Ops; an empty
+// field does not restrict. Strategy says how to merge a delta whose spec has
+// diverged: MergeStrategyReject (the default) or MergeStrategyMarkers.
+type ApplyTarget struct {
+	Domains []string
+	// Ops name requirements as "<requirement-id>" or "<domain>/<requirement-id>";
+	// every operation on a named requirement, scenario operations included, is
+	// selected.
+	Ops      []string
+	Strategy string
+}
+
+// Selects reports whether the target includes op of the delta for domain.
+func (t ApplyTarget) Selects(domain string, op model.DeltaOperation) bool {
+	if len(t.Domains) > 0 && !containsFold(t.Domains, domain) {
+		return false
+	}
+	if len(t.Ops) == 0 {
+		return true
+	}
+	for _, sel := range t.Ops {
+		d, id := "", sel
+		if i := strings.LastIndex(sel, "/"); i >= 0 {
+			d, id = sel[:i], sel[i+1:]
+		}
+		if (d == "" || strings.EqualFold(d, domain)) && strings.EqualFold(id, op.Requirement.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(strings.TrimSpace(v), s) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasPendingOperations(d model.SpecDelta) bool {
+	for _, op := range d.Operations {
+		if op.AppliedAt == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// deltaApplied reports whether a partial apply has merged all of d's
+// operations.
+func deltaApplied(d model.SpecDelta) bool {
+	return len(d.Operations) > 0 && !hasPendingOperations(d)
+}
+
+// RemainingOperations returns the change's deltas reduced to the operations
+// not applied yet, omitting deltas with none left.
+func RemainingOperations(change *model.Change) []model.SpecDelta {
+	var remaining []model.SpecDelta
+	for _, d := range change.SpecDeltas {
+		rest := d
+		rest.Operations = nil
+		for _, op := range d.Operations {
+			if op.AppliedAt == nil {
+				rest.Operations = append(rest.Operations, op)
+			}
+		}
+		if len(rest.Operations) > 0 {
+			remaining = append(remaining, rest)
+		}
+	}
+	return remaining
+}
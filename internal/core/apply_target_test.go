@@ -0,0 +1,73 @@
+package core
+
+import (
+	"testing"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestApplyChangeTargets_PartialThenRest(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fps []string
+	for _, domain := range []string{"auth", "billing"} {
+		spec := &model.Spec{Domain: domain, Content: "# " + domain + "\n"}
+		if err := app.SpecManager.WriteSpec(spec); err != nil {
+			t.Fatal(err)
+		}
+		fps = append(fps, spec.Fingerprint)
+	}
+	added := func(id string) model.DeltaOperation {
+		return model.DeltaOperation{Type: "ADDED", Requirement: model.Requirement{ID: id, Title: id, Content: "### Requirement: " + id + "\n\nNew.\n"}}
+	}
+	ch := &model.Change{ID: "CH-001", Status: "draft", SpecDeltas: []model.SpecDelta{
+		{Domain: "auth", BaseFingerprint: fps[0], Operations: []model.DeltaOperation{added("login"), added("logout")}},
+		{Domain: "billing", BaseFingerprint: fps[1], Operations: []model.DeltaOperation{added("invoice")}},
+	}}
+	if err := app.ChangeManager.Save(ch); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.ChangeManager.ApplyChangeTargets(ch, ApplyTarget{Domains: []string{"auth"}, Ops: []string{"login"}}); err != nil {
+		t.Fatalf("partial apply: %v", err)
+	}
+	if ch.Status != ChangeStatusPartiallyApplied {
+		t.Fatalf("status = %q", ch.Status)
+	}
+	remaining := RemainingOperations(ch)
+	if len(remaining) != 2 || remaining[0].Operations[0].Requirement.ID != "logout" || remaining[1].Domain != "billing" {
+		t.Fatalf("remaining = %+v", remaining)
+	}
+	auth, _ := app.SpecManager.ReadSpec("auth")
+	if len(auth.Requirements) != 1 || ch.SpecDeltas[0].BaseFingerprint != auth.Fingerprint {
+		t.Fatalf("auth after partial apply = %+v, base %s", auth.Requirements, ch.SpecDeltas[0].BaseFingerprint)
+	}
+	if stale, _ := FindDivergedDeltas(app.SpecManager, ch); len(stale) != 0 {
+		t.Fatalf("partially applied change reported stale: %+v", stale)
+	}
+
+	err = app.ChangeManager.ApplyChangeTargets(ch, ApplyTarget{Ops: []string{"auth/login"}})
+	if _, ok := err.(*custom_errors.ErrConflict); !ok {
+		t.Fatalf("re-applying an applied op: expected ErrConflict, got %v", err)
+	}
+
+	reloaded, err := app.ChangeManager.ReadChange("CH-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.ChangeManager.ApplyChange(reloaded); err != nil {
+		t.Fatalf("finishing apply: %v", err)
+	}
+	if reloaded.Status != "applied" || len(RemainingOperations(reloaded)) != 0 {
+		t.Fatalf("after finishing: status %q, remaining %+v", reloaded.Status, RemainingOperations(reloaded))
+	}
+	auth, _ = app.SpecManager.ReadSpec("auth")
+	billing, _ := app.SpecManager.ReadSpec("billing")
+	if len(auth.Requirements) != 2 || len(billing.Requirements) != 1 {
+		t.Fatalf("auth %d, billing %d requirements", len(auth.Requirements), len(billing.Requirements))
+	}
+}
@@ -0,0 +1,377 @@
+package core
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+// ArchiveConfig is the "archive" section of config.yaml: the retention
+// policy of 'change compact-archive'. An archived change is kept as is while
+// it is one of the KeepRecent most recently archived, or was archived less
+// than KeepDays days ago; older ones are compacted.
+//
+//	archive:
+//	  keep_recent: 50
+//	  keep_days: 90
+type ArchiveConfig struct {
+	KeepRecent int `yaml:"keep_recent,omitempty"`
+	KeepDays   int `yaml:"keep_days,omitempty"`
+}
+
+// Validate checks the limits.
+func (c ArchiveConfig) Validate() error {
+	var errs custom_errors.ValidationErrors
+	if c.KeepRecent < 0 {
+		errs.Add("archive.keep_recent", "cannot be negative")
+	}
+	if c.KeepDays < 0 {
+		errs.Add("archive.keep_days", "cannot be negative")
+	}
+	return errs.Err()
+}
+
+// CompactedArchiveDir is the directory under the archive root holding the
+// compacted changes: a tarball of their files and a manifest of the changes.
+const CompactedArchiveDir = ".compacted"
+
+const (
+	compactedBundleFile   = "changes.tar.gz"
+	compactedManifestFile = "manifest.json"
+)
+
+// CompactedChange is one change in the compacted archive's manifest.
+type CompactedChange struct {
+	Change model.Change `json:"change"`
+	// ArchivedAt is when the change was archived, or for changes archived
+	// before that was recorded, when its change file was last written.
+	ArchivedAt  time.Time `json:"archived_at"`
+	CompactedAt time.Time `json:"compacted_at"`
+	// Files are the change's files in the tarball, relative to the archive
+	// root with forward slashes.
+	Files []string `json:"files"`
+}
+
+// compactedManifest is the manifest file.
+type compactedManifest struct {
+	Changes []CompactedChange `json:"changes"`
+}
+
+// CompactedBundlePath returns the tarball of compacted changes under the
+// archive root of changesDir.
+func (l Layout) CompactedBundlePath(changesDir string) string {
+	return filepath.Join(l.ArchivePath(changesDir), CompactedArchiveDir, compactedBundleFile)
+}
+
+// compactedManifestPath returns the manifest of compacted changes.
+func (l Layout) compactedManifestPath(changesDir string) string {
+	return filepath.Join(l.ArchivePath(changesDir), CompactedArchiveDir, compactedManifestFile)
+}
+
+// readCompactedChanges returns the manifest of the changes compacted under
+// changesDir, in ID order; none when nothing was compacted yet.
+func readCompactedChanges(store storage.Storage, layout Layout, changesDir string) ([]CompactedChange, error) {
+	path := layout.compactedManifestPath(changesDir)
+	b, err := store.ReadFile(path)
+	if err != nil {
+		if _, ok := err.(*custom_errors.ErrNotFound); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var m compactedManifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse '%s': %w", path, err)
+	}
+	return m.Changes, nil
+}
+
+// CompactedChanges returns the manifest of the compacted archive, in ID
+// order.
+func (a *App) CompactedChanges() ([]CompactedChange, error) {
+	return readCompactedChanges(a.Options.Storage, a.Options.Layout, a.Options.ChangesDir)
+}
+
+// CompactOptions tunes CompactArchive.
+type CompactOptions struct {
+	// DryRun reports what would be compacted without writing.
+	DryRun bool
+	// Now is the reference time; zero means time.Now().
+	Now time.Time
+}
+
+// archivedEntry is a change in the archive and what belongs to it.
+type archivedEntry struct {
+	change     *model.Change
+	archivedAt time.Time
+	path       string // the change's own directory, or its file in flat layouts
+}
+
+// CompactArchive moves the archived changes outside policy into the
+// compacted archive: their files into the tarball (regular files only;
+// symbolic links are dropped) and their change records into the manifest,
+// which keeps them listed by 'change compact-archive --list', stats and
+// ID allocation. It returns the changes compacted, oldest first.
+func (a *App) CompactArchive(policy ArchiveConfig, opts CompactOptions) ([]CompactedChange, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	if policy.KeepRecent == 0 && policy.KeepDays == 0 {
+		return nil, custom_errors.NewErrValidation("archive", "no retention policy: set keep_recent or keep_days")
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	o := a.Options
+	store := o.Storage
+
+	entries, err := a.archivedEntries()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].archivedAt.After(entries[j].archivedAt) })
+	var expired []archivedEntry
+	for i, e := range entries {
+		recent := i < policy.KeepRecent
+		young := policy.KeepDays > 0 && now.Sub(e.archivedAt) < time.Duration(policy.KeepDays)*24*time.Hour
+		if !recent && !young {
+			expired = append(expired, e)
+		}
+	}
+	// Oldest first, as they would have been compacted one run at a time.
+	for i, j := 0, len(expired)-1; i < j; i, j = i+1, j-1 {
+		expired[i], expired[j] = expired[j], expired[i]
+	}
+
+	archive := o.Layout.ArchivePath(o.ChangesDir)
+	var out []CompactedChange
+	modes := map[string]os.FileMode{}
+	for _, e := range expired {
+		c := CompactedChange{Change: *e.change, ArchivedAt: e.archivedAt, CompactedAt: now}
+		err := store.WalkDir(e.path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || !d.Type().IsRegular() {
+				return err
+			}
+			rel, err := filepath.Rel(archive, p)
+			if err != nil {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			c.Files = append(c.Files, filepath.ToSlash(rel))
+			modes[filepath.ToSlash(rel)] = info.Mode().Perm()
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	if opts.DryRun || len(out) == 0 {
+		return out, nil
+	}
+
+	unlock, err := store.Lock(filepath.Join(archive, CompactedArchiveDir), 0)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	bundlePath := o.Layout.CompactedBundlePath(o.ChangesDir)
+	files, err := readTarGz(store, bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range out {
+		for _, name := range c.Files {
+			p := filepath.Join(archive, filepath.FromSlash(name))
+			data, err := store.ReadFile(p)
+			if err != nil {
+				return nil, err
+			}
+			files[name] = tarFile{data: data, mode: modes[name], modTime: c.ArchivedAt}
+		}
+	}
+	bundle, err := writeTarGz(files)
+	if err != nil {
+		return nil, err
+	}
+	if err := store.WriteFile(bundlePath, bundle, 0o644); err != nil {
+		return nil, err
+	}
+
+	manifest, err := a.CompactedChanges()
+	if err != nil {
+		return nil, err
+	}
+	byID := map[string]int{}
+	for i, c := range manifest {
+		byID[c.Change.ID] = i
+	}
+	for _, c := range out {
+		if i, ok := byID[c.Change.ID]; ok {
+			manifest[i] = c
+			continue
+		}
+		byID[c.Change.ID] = len(manifest)
+		manifest = append(manifest, c)
+	}
+	sort.SliceStable(manifest, func(i, j int) bool { return compareNatural(manifest[i].Change.ID, manifest[j].Change.ID) < 0 })
+	data, err := json.MarshalIndent(compactedManifest{Changes: manifest}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := store.WriteFile(o.Layout.compactedManifestPath(o.ChangesDir), append(data, '\n'), 0o644); err != nil {
+		return nil, err
+	}
+
+	for _, e := range expired {
+		if err := store.RemoveAll(e.path); err != nil {
+			return out, err
+		}
+	}
+	return out, nil
+}
+
+// archivedEntries returns the changes in the archive, not yet compacted,
+// with when they were archived.
+func (a *App) archivedEntries() ([]archivedEntry, error) {
+	o := a.Options
+	archive := o.Layout.ArchivePath(o.ChangesDir)
+	ids, err := findTemplateMatches(o.Storage, archive, o.Layout.ChangeFile, changePlaceholder, CompactedArchiveDir)
+	if err != nil {
+		if _, ok := err.(*custom_errors.ErrNotFound); ok || os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	dirTmpl := ownDirTemplate(o.Layout.ChangeFile, changePlaceholder)
+	var out []archivedEntry
+	for _, id := range ids {
+		file := o.Layout.ChangePath(archive, id)
+		b, err := o.Storage.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		var ch model.Change
+		if err := json.Unmarshal(b, &ch); err != nil {
+			continue
+		}
+		if ch.ID == "" {
+			ch.ID = id
+		}
+		e := archivedEntry{change: &ch, path: file}
+		if dirTmpl != "" {
+			e.path = filepath.Join(archive, expandTemplate(dirTmpl, changePlaceholder, id))
+		}
+		if ch.ArchivedAt != nil {
+			e.archivedAt = *ch.ArchivedAt
+			out = append(out, e)
+			continue
+		}
+		// Archived before changes recorded ArchivedAt: fall back to the
+		// change file's modification time.
+		err = o.Storage.WalkDir(file, func(_ string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			e.archivedAt = info.ModTime()
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+// tarFile is a regular file in a tarball.
+type tarFile struct {
+	data    []byte
+	mode    os.FileMode
+	modTime time.Time
+}
+
+// readTarGz returns the regular files of the gzipped tarball at path by
+// name; none when it does not exist.
+func readTarGz(store storage.Storage, path string) (map[string]tarFile, error) {
+	files := map[string]tarFile{}
+	b, err := store.ReadFile(path)
+	if err != nil {
+		if _, ok := err.(*custom_errors.ErrNotFound); ok {
+			return files, nil
+		}
+		return nil, err
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s': %w", path, err)
+	}
+	tr := tar.NewReader(zr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read '%s': %w", path, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read '%s': %w", path, err)
+		}
+		files[hdr.Name] = tarFile{data: data, mode: os.FileMode(hdr.Mode).Perm(), modTime: hdr.ModTime}
+	}
+}
+
+// writeTarGz returns a gzipped tarball of files, in name order.
+func writeTarGz(files map[string]tarFile) ([]byte, error) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	for _, name := range names {
+		f := files[name]
+		hdr := &tar.Header{Name: name, Mode: int64(f.mode), Size: int64(len(f.data)), ModTime: f.modTime, Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,141 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestApp_CompactArchive(t *testing.T) {
+	root := createTempDir(t)
+	changesDir := filepath.Join(root, "changes")
+	app, err := NewApp(AppOptions{SpecsDir: filepath.Join(root, "specs"), GoalsDir: filepath.Join(root, "goals"), ChangesDir: changesDir, CharterDir: root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := filepath.Join(changesDir, ".archive")
+	now := time.Now()
+	for i, id := range []string{"CH-001", "CH-002", "CH-003"} {
+		path := writeChangeJSON(t, archive, id, map[string]any{"id": id, "title": "Change " + id, "status": "archived", "author": "alice"})
+		archivedAt := now.Add(-time.Duration(100-i*40) * 24 * time.Hour) // 100, 60 and 20 days ago
+		if err := os.Chtimes(path, archivedAt, archivedAt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(archive, "CH-001", "notes.md"), []byte("notes\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.CompactArchive(ArchiveConfig{}, CompactOptions{}); err == nil {
+		t.Fatalf("expected compaction without a retention policy to fail")
+	}
+	policy := ArchiveConfig{KeepRecent: 1, KeepDays: 90}
+	dry, err := app.CompactArchive(policy, CompactOptions{DryRun: true, Now: now})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if len(dry) != 1 || dry[0].Change.ID != "CH-001" {
+		t.Fatalf("dry run compacts %+v, want CH-001", dry)
+	}
+	if _, err := os.Stat(filepath.Join(archive, "CH-001")); err != nil {
+		t.Fatalf("dry run removed the change: %v", err)
+	}
+
+	got, err := app.CompactArchive(ArchiveConfig{KeepRecent: 1}, CompactOptions{Now: now})
+	if err != nil {
+		t.Fatalf("CompactArchive: %v", err)
+	}
+	if len(got) != 2 || got[0].Change.ID != "CH-001" || got[1].Change.ID != "CH-002" {
+		t.Fatalf("compacted %+v, want CH-001 and CH-002", got)
+	}
+	for _, id := range []string{"CH-001", "CH-002"} {
+		if _, err := os.Stat(filepath.Join(archive, id)); !os.IsNotExist(err) {
+			t.Fatalf("expected %s removed from the archive, stat err: %v", id, err)
+		}
+	}
+
+	files, err := readTarGz(app.Options.Storage, app.Options.Layout.CompactedBundlePath(changesDir))
+	if err != nil {
+		t.Fatalf("read bundle: %v", err)
+	}
+	if f, ok := files["CH-001/notes.md"]; !ok || string(f.data) != "notes\n" || f.mode != 0o600 {
+		t.Fatalf("bundle is missing CH-001's notes: %+v", files)
+	}
+	if _, ok := files["CH-002/change.json"]; !ok || len(files) != 3 {
+		t.Fatalf("bundle files = %v", files)
+	}
+
+	manifest, err := app.CompactedChanges()
+	if err != nil {
+		t.Fatalf("CompactedChanges: %v", err)
+	}
+	if len(manifest) != 2 || manifest[0].Change.Title != "Change CH-001" || len(manifest[0].Files) != 2 {
+		t.Fatalf("manifest = %+v", manifest)
+	}
+	archived, err := app.archivedChanges()
+	if err != nil || len(archived) != 3 {
+		t.Fatalf("archivedChanges = %d, %v; want the kept and the compacted ones", len(archived), err)
+	}
+	ch, err := app.ChangeManager.CreateChange("Next", "", nil)
+	if err != nil {
+		t.Fatalf("CreateChange: %v", err)
+	}
+	if ch.ID != "CH-004" {
+		t.Fatalf("new change ID = %s, want CH-004", ch.ID)
+	}
+	clone, err := app.ChangeManager.CloneChange("CH-001", "CH-005")
+	if err != nil || clone.ClonedFrom != "CH-001" {
+		t.Fatalf("CloneChange of a compacted change = %+v, %v", clone, err)
+	}
+
+	// Compacting again adds to the bundle and manifest.
+	more, err := app.CompactArchive(ArchiveConfig{KeepDays: 1}, CompactOptions{Now: now})
+	if err != nil || len(more) != 1 || more[0].Change.ID != "CH-003" {
+		t.Fatalf("second compaction = %+v, %v", more, err)
+	}
+	if manifest, _ = app.CompactedChanges(); len(manifest) != 3 {
+		t.Fatalf("manifest after the second compaction = %+v", manifest)
+	}
+	if files, _ = readTarGz(app.Options.Storage, app.Options.Layout.CompactedBundlePath(changesDir)); len(files) != 4 {
+		t.Fatalf("bundle after the second compaction = %v", files)
+	}
+}
+
+func TestApp_CompactArchive_UsesRecordedArchiveTime(t *testing.T) {
+	root := createTempDir(t)
+	changesDir := filepath.Join(root, "changes")
+	app, err := NewApp(AppOptions{SpecsDir: filepath.Join(root, "specs"), GoalsDir: filepath.Join(root, "goals"), ChangesDir: changesDir, CharterDir: root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := filepath.Join(changesDir, ".archive")
+	now := time.Now()
+
+	// Archived just now, but its file was since touched with an old mtime.
+	fresh, err := app.ChangeManager.CreateChange("Fresh", "", nil)
+	if err != nil {
+		t.Fatalf("CreateChange: %v", err)
+	}
+	if err := app.ChangeManager.ArchiveChange(fresh); err != nil {
+		t.Fatalf("ArchiveChange: %v", err)
+	}
+	if fresh.ArchivedAt == nil {
+		t.Fatalf("expected ArchiveChange to record ArchivedAt")
+	}
+	old := now.Add(-100 * 24 * time.Hour)
+	if err := os.Chtimes(app.Options.Layout.ChangePath(archive, fresh.ID), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	// Archived long ago, but its file was written just now.
+	writeChangeJSON(t, archive, "CH-900", map[string]any{"id": "CH-900", "title": "Stale", "status": "archived", "archived_at": old.Format(time.RFC3339)})
+
+	got, err := app.CompactArchive(ArchiveConfig{KeepDays: 90}, CompactOptions{DryRun: true, Now: now})
+	if err != nil {
+		t.Fatalf("CompactArchive: %v", err)
+	}
+	if len(got) != 1 || got[0].Change.ID != "CH-900" || !got[0].ArchivedAt.Equal(old.Truncate(time.Second)) {
+		t.Fatalf("compacted %+v, want only CH-900 archived at %v", got, old)
+	}
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"os"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+)
+
+// ArtifactProblem is something wrong with one workspace artifact file. Kind
+// is "spec", "change", "plan" or "discussion"; ID is the domain, change ID or
+// goal.
+type ArtifactProblem struct {
+	Kind    string
+	ID      string
+	Path    string
+	Message string
+}
+
+// ArtifactCheckOptions configures CheckArtifacts. Include, when set, limits
+// the check to artifact files it accepts (paths as the layout builds them);
+// SpecSections are the level-2 sections every spec must have.
+type ArtifactCheckOptions struct {
+	Include      func(path string) bool
+	SpecSections []string
+}
+
+// CheckArtifacts reads every spec, change, plan and discussion file of the
+// workspace and reports the ones that are malformed: files that do not parse,
+// specs missing a required section or holding conflict markers, and pending
+// changes whose deltas do not validate against the current specs. Unlike the
+// managers' List methods, it does not skip files it cannot read.
+func (a *App) CheckArtifacts(opts ArtifactCheckOptions) ([]ArtifactProblem, error) {
+	o := a.Options
+	include := func(path string) bool { return opts.Include == nil || opts.Include(path) }
+	var problems []ArtifactProblem
+	report := func(kind, id, path, msg string) {
+		problems = append(problems, ArtifactProblem{Kind: kind, ID: id, Path: path, Message: msg})
+	}
+
+	domains, err := findTemplateMatches(o.Storage, o.SpecsDir, o.Layout.SpecFile, domainPlaceholder)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, domain := range domains {
+		path := o.Layout.SpecPath(o.SpecsDir, domain)
+		if !include(path) {
+			continue
+		}
+		spec, err := a.SpecManager.ReadSpec(domain)
+		if err != nil {
+			report("spec", domain, path, err.Error())
+			continue
+		}
+		if missing := MissingSpecSections(spec, opts.SpecSections); len(missing) > 0 {
+			report("spec", domain, path, "missing section(s): "+strings.Join(missing, ", "))
+		}
+		if HasConflictMarkers(spec.Content) {
+			report("spec", domain, path, "unresolved conflict markers")
+		}
+	}
+
+	ids, err := findTemplateMatches(o.Storage, o.ChangesDir, o.Layout.ChangeFile, changePlaceholder, o.Layout.ArchiveDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, id := range ids {
+		path := o.Layout.ChangePath(o.ChangesDir, id)
+		if !include(path) {
+			continue
+		}
+		ch, err := a.ChangeManager.ReadChange(id)
+		if err != nil {
+			report("change", id, path, err.Error())
+			continue
+		}
+		if !IsPendingChange(ch) {
+			continue
+		}
+		if err := ValidateChangeDeltas(a.SpecManager, ch); err != nil {
+			verr, ok := err.(*DeltaValidationError)
+			if !ok {
+				return nil, err
+			}
+			for _, issue := range verr.Issues {
+				report("change", id, path, issue.String())
+			}
+		}
+	}
+
+	goals, err := ListGoalIDs(o)
+	if err != nil {
+		return nil, err
+	}
+	for _, goal := range goals {
+		if path := o.Layout.PlanPath(o.GoalsDir, goal); include(path) {
+			if _, err := a.PlanManager.Load(goal); err != nil {
+				if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+					report("plan", goal, path, err.Error())
+				}
+			}
+		}
+		if path := o.Layout.DiscussionPath(o.GoalsDir, goal); include(path) {
+			if _, err := a.DiscussionManager.Load(goal); err != nil {
+				report("discussion", goal, path, err.Error())
+			}
+		}
+	}
+	return problems, nil
+}
@@ -0,0 +1,54 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestCheckArtifacts_ReportsMalformedFiles(t *testing.T) {
+	store := storage.NewInMem()
+	app, err := NewApp(AppOptions{Storage: store})
+	if err != nil {
+		t.Fatalf("NewApp: %v", err)
+	}
+	o := app.Options
+	if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "auth", Content: "# Auth\n\n## Purpose\n\n### Requirement: Login\n\nUsers log in.\n"}); err != nil {
+		t.Fatalf("WriteSpec: %v", err)
+	}
+	if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "billing", Content: "# Billing\n\n## Purpose\n\nPay.\n"}); err != nil {
+		t.Fatalf("WriteSpec: %v", err)
+	}
+	if err := store.WriteFile(o.Layout.ChangePath(o.ChangesDir, "CH-001"), []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.ChangeManager.Save(&model.Change{ID: "CH-002", Status: "draft", SpecDeltas: []model.SpecDelta{{
+		Domain:     "auth",
+		Operations: []model.DeltaOperation{{Type: "MODIFIED", Requirement: model.Requirement{ID: "logout", Content: "### Requirement: Logout\n"}}},
+	}}}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := store.WriteFile(o.Layout.PlanPath(o.GoalsDir, "001-auth"), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	problems, err := app.CheckArtifacts(ArtifactCheckOptions{SpecSections: []string{"Purpose"}})
+	if err != nil {
+		t.Fatalf("CheckArtifacts: %v", err)
+	}
+	var got []string
+	for _, p := range problems {
+		got = append(got, p.Kind+" "+p.ID)
+	}
+	if strings.Join(got, ", ") != "change CH-001, change CH-002, plan 001-auth" {
+		t.Fatalf("unexpected problems: %+v", problems)
+	}
+
+	only := o.Layout.SpecPath(o.SpecsDir, "auth")
+	problems, err = app.CheckArtifacts(ArtifactCheckOptions{SpecSections: []string{"Purpose", "Scope"}, Include: func(path string) bool { return path == only }})
+	if err != nil || len(problems) != 1 || problems[0].ID != "auth" || !strings.Contains(problems[0].Message, "Scope") {
+		t.Fatalf("expected only the included spec checked, got %+v (err %v)", problems, err)
+	}
+}
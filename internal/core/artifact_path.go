@@ -0,0 +1,61 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+)
+
+// Artifact kinds accepted by ArtifactPath.
+const (
+	ArtifactSpec    = "spec"
+	ArtifactPlan    = "plan"
+	ArtifactChange  = "change"
+	ArtifactCharter = "charter"
+)
+
+// ArtifactKinds lists the kinds ArtifactPath resolves, in help-text order.
+var ArtifactKinds = []string{ArtifactSpec, ArtifactPlan, ArtifactChange, ArtifactCharter}
+
+// ArtifactPath returns the file backing an artifact under the resolved
+// options: a spec by domain, a plan by goal ID, a change by change ID, or the
+// charter (which takes no ID). It returns ErrNotFound if the file does not
+// exist, so callers never open an editor on a misspelled ID.
+func ArtifactPath(o AppOptions, kind, id string) (string, error) {
+	o = o.WithDefaults()
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	id = strings.TrimSpace(id)
+	if kind != ArtifactCharter && id == "" {
+		return "", fmt.Errorf("%s requires an ID", kind)
+	}
+
+	var path string
+	switch kind {
+	case ArtifactSpec:
+		path = o.Layout.SpecPath(o.SpecsDir, id)
+	case ArtifactPlan:
+		path = o.Layout.PlanPath(o.GoalsDir, id)
+	case ArtifactChange:
+		path = o.Layout.ChangePath(o.ChangesDir, id)
+	case ArtifactCharter:
+		if id != "" {
+			return "", fmt.Errorf("charter takes no ID")
+		}
+		path = o.Layout.CharterPath(o.CharterDir)
+	default:
+		return "", fmt.Errorf("unknown artifact %q (expected one of: %s)", kind, strings.Join(ArtifactKinds, ", "))
+	}
+
+	exists, err := o.Storage.Exists(path)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		if kind == ArtifactCharter {
+			id = path
+		}
+		return "", custom_errors.NewErrNotFound(kind, id)
+	}
+	return path, nil
+}
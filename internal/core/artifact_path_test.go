@@ -0,0 +1,44 @@
+package core
+
+import (
+	"path/filepath"
+	"testing"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestArtifactPath(t *testing.T) {
+	opts := AppOptions{Storage: storage.NewInMem()}
+	app, err := NewApp(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "auth", Content: "# Auth\n"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.PlanManager.Save(&model.Plan{GoalID: "001-demo"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ArtifactPath(opts, "spec", "auth")
+	if err != nil || got != filepath.Join(".teamwerx", "specs", "auth", "spec.md") {
+		t.Fatalf("spec path = %q, %v", got, err)
+	}
+	if _, err := ArtifactPath(opts, "PLAN", "001-demo"); err != nil {
+		t.Fatalf("plan path: %v", err)
+	}
+
+	if _, err := ArtifactPath(opts, "change", "CH-404"); err == nil {
+		t.Fatal("expected missing change to fail")
+	} else if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+		t.Fatalf("expected ErrNotFound, got %T: %v", err, err)
+	}
+	if _, err := ArtifactPath(opts, "spec", ""); err == nil {
+		t.Fatal("expected spec without ID to fail")
+	}
+	if _, err := ArtifactPath(opts, "goal", "x"); err == nil {
+		t.Fatal("expected unknown kind to fail")
+	}
+}
@@ -0,0 +1,293 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// Batch operations.
+const (
+	BatchTaskAdd      = "task.add"      // goal, title[, parent]
+	BatchTaskComplete = "task.complete" // goal, task[, force]
+	BatchTaskWaive    = "task.waive"    // goal, task, reason
+	BatchTaskBlock    = "task.block"    // goal, task, reason[, author]
+	BatchTaskUnblock  = "task.unblock"  // goal, task[, author]
+	BatchDiscussAdd   = "discuss.add"   // goal, content[, type, author, task]
+)
+
+// BatchOp is one line of a batch file, e.g.
+//
+//	{"op":"task.add","goal":"001-auth","title":"Write tests"}
+//	{"op":"discuss.add","goal":"001-auth","type":"decision","content":"Use JWT"}
+type BatchOp struct {
+	Line    int    `json:"-"` // 1-based line in the batch file
+	Op      string `json:"op"`
+	Goal    string `json:"goal"`
+	Title   string `json:"title,omitempty"`
+	Parent  string `json:"parent,omitempty"`
+	Task    string `json:"task,omitempty"`
+	Force   bool   `json:"force,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Content string `json:"content,omitempty"`
+	Author  string `json:"author,omitempty"`
+}
+
+// ParseBatch reads JSON lines of BatchOps, skipping blank lines and lines
+// starting with "#". Each op is checked for its required fields.
+func ParseBatch(r io.Reader) ([]BatchOp, error) {
+	var ops []BatchOp
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		text := bytes.TrimSpace(sc.Bytes())
+		if len(text) == 0 || text[0] == '#' {
+			continue
+		}
+		var op BatchOp
+		dec := json.NewDecoder(bytes.NewReader(text))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&op); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		op.Line = line
+		if err := op.check(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		ops = append(ops, op)
+	}
+	return ops, sc.Err()
+}
+
+func (op BatchOp) check() error {
+	missing := func(field string) error {
+		return custom_errors.NewErrValidation(field, fmt.Sprintf("%s requires a value", op.Op))
+	}
+	if strings.TrimSpace(op.Goal) == "" {
+		return missing("goal")
+	}
+	switch op.Op {
+	case BatchTaskAdd:
+		if strings.TrimSpace(op.Title) == "" {
+			return missing("title")
+		}
+	case BatchTaskComplete, BatchTaskUnblock:
+		if op.Task == "" {
+			return missing("task")
+		}
+	case BatchTaskWaive, BatchTaskBlock:
+		if op.Task == "" {
+			return missing("task")
+		}
+		if strings.TrimSpace(op.Reason) == "" {
+			return missing("reason")
+		}
+	case BatchDiscussAdd:
+		if strings.TrimSpace(op.Content) == "" {
+			return missing("content")
+		}
+	default:
+		return custom_errors.NewErrValidation("op", fmt.Sprintf("unknown op %q", op.Op))
+	}
+	return nil
+}
+
+// BatchOptions configures RunBatch.
+type BatchOptions struct {
+	// Skip holds the Lines of ops applied by an earlier, interrupted run;
+	// they are neither validated nor applied again.
+	Skip map[int]bool
+	// Rate caps the ops written per second (0 means unlimited). Files are
+	// written whole, so the pause falls between files.
+	Rate float64
+	// DryRun validates every op against the workspace without writing.
+	DryRun bool
+	// Applied, when set, is called after each file is written with the ops
+	// it contained, e.g. to checkpoint progress for a later Skip.
+	Applied func(ops []BatchOp) error
+}
+
+// BatchResult is one op after validation, with the task or entry it created
+// or changed.
+type BatchResult struct {
+	Op     BatchOp `json:"op"`
+	File   string  `json:"file"`
+	Target string  `json:"target"` // task or discussion entry ID
+}
+
+// batchGroup is the ops writing one file: a goal's plan or discussion log.
+type batchGroup struct {
+	goal    string
+	plan    bool
+	ops     []BatchOp
+	results []BatchResult
+	apply   func() error
+}
+
+// RunBatch applies ops grouped by the file they change. Every op is first
+// applied in memory (a goal's plan is loaded once for all its task ops), so
+// a bad op fails the whole batch before anything is written. Each file is
+// then written once under its lock, in the order files are first mentioned.
+func (a *App) RunBatch(ops []BatchOp, opts BatchOptions) ([]BatchResult, error) {
+	var groups []*batchGroup
+	byKey := map[string]*batchGroup{}
+	for _, op := range ops {
+		if opts.Skip[op.Line] {
+			continue
+		}
+		if err := op.check(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", op.Line, err)
+		}
+		plan := op.Op != BatchDiscussAdd
+		key := fmt.Sprintf("%v/%s", plan, op.Goal)
+		g := byKey[key]
+		if g == nil {
+			g = &batchGroup{goal: op.Goal, plan: plan}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		g.ops = append(g.ops, op)
+	}
+
+	for _, g := range groups {
+		var err error
+		if g.plan {
+			err = a.prepareBatchPlan(g)
+		} else {
+			err = a.prepareBatchDiscussion(g)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var results []BatchResult
+	if opts.DryRun {
+		for _, g := range groups {
+			results = append(results, g.results...)
+		}
+		return results, nil
+	}
+	start, written := time.Now(), 0
+	for _, g := range groups {
+		if opts.Rate > 0 && written > 0 {
+			if wait := time.Duration(float64(written)/opts.Rate*float64(time.Second)) - time.Since(start); wait > 0 {
+				time.Sleep(wait)
+			}
+		}
+		if err := g.apply(); err != nil {
+			return results, fmt.Errorf("failed to write %s: %w", g.results[0].File, err)
+		}
+		written += len(g.ops)
+		results = append(results, g.results...)
+		if opts.Applied != nil {
+			if err := opts.Applied(g.ops); err != nil {
+				return results, err
+			}
+		}
+	}
+	return results, nil
+}
+
+// prepareBatchPlan applies g's task ops to the goal's plan in memory, and
+// sets g.apply to reload and re-apply them under the plan's lock, then save.
+func (a *App) prepareBatchPlan(g *batchGroup) error {
+	path := a.Options.Layout.PlanPath(a.Options.GoalsDir, g.goal)
+	run := func() ([]BatchResult, *model.Plan, error) {
+		plan, err := a.PlanManager.Load(g.goal)
+		if err != nil {
+			return nil, nil, err
+		}
+		var results []BatchResult
+		for _, op := range g.ops {
+			r := BatchResult{Op: op, File: path, Target: op.Task}
+			switch op.Op {
+			case BatchTaskAdd:
+				var t *model.Task
+				if op.Parent != "" {
+					t, err = a.PlanManager.AddSubtask(plan, op.Parent, op.Title)
+				} else {
+					t, err = a.PlanManager.AddTask(plan, op.Title)
+				}
+				if t != nil {
+					r.Target = t.ID
+				}
+			case BatchTaskComplete:
+				err = a.PlanManager.CompleteTask(plan, op.Task, op.Force)
+			case BatchTaskWaive:
+				err = a.PlanManager.WaiveTask(plan, op.Task, op.Reason)
+			case BatchTaskBlock:
+				err = a.PlanManager.BlockTask(plan, op.Task, op.Reason, op.Author)
+			case BatchTaskUnblock:
+				err = a.PlanManager.UnblockTask(plan, op.Task, op.Author)
+			}
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %d (%s): %w", op.Line, op.Op, err)
+			}
+			results = append(results, r)
+		}
+		return results, plan, nil
+	}
+
+	results, _, err := run()
+	if err != nil {
+		return err
+	}
+	g.results = results
+	g.apply = func() error {
+		unlock, err := a.Options.Storage.Lock(path, 0)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+		// Reload so writes since validation are kept rather than overwritten.
+		results, plan, err := run()
+		if err != nil {
+			return err
+		}
+		g.results = results
+		return a.PlanManager.Save(plan)
+	}
+	return nil
+}
+
+// prepareBatchDiscussion checks g's entries against the goal and the
+// discussion type registry, and sets g.apply to append them in one write.
+func (a *App) prepareBatchDiscussion(g *batchGroup) error {
+	if _, err := a.PlanManager.Load(g.goal); err != nil {
+		return fmt.Errorf("line %d (%s): %w", g.ops[0].Line, g.ops[0].Op, err)
+	}
+	path := a.Options.Layout.DiscussionPath(a.Options.GoalsDir, g.goal)
+	for _, op := range g.ops {
+		typ := strings.TrimSpace(op.Type)
+		if typ == "" {
+			typ = DiscussionTypeDiscussion
+		}
+		if err := a.DiscussionTypes.Validate(typ); err != nil {
+			return fmt.Errorf("line %d (%s): %w", op.Line, op.Op, err)
+		}
+		g.results = append(g.results, BatchResult{Op: op, File: path})
+	}
+	g.apply = func() error {
+		entries := make([]*model.DiscussionEntry, len(g.ops))
+		for i, op := range g.ops {
+			entries[i] = &model.DiscussionEntry{Type: op.Type, Content: op.Content, Author: op.Author, TaskID: op.Task}
+		}
+		if err := a.DiscussionManager.AddEntries(g.goal, entries); err != nil {
+			return err
+		}
+		for i, e := range entries {
+			g.results[i].Target = e.ID
+		}
+		return nil
+	}
+	return nil
+}
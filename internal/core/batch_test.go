@@ -0,0 +1,94 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestParseBatch(t *testing.T) {
+	ops, err := ParseBatch(strings.NewReader("# setup\n{\"op\":\"task.add\",\"goal\":\"001-demo\",\"title\":\"A\"}\n\n{\"op\":\"discuss.add\",\"goal\":\"001-demo\",\"content\":\"hi\"}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) != 2 || ops[0].Line != 2 || ops[1].Line != 4 {
+		t.Fatalf("ops = %+v", ops)
+	}
+	for _, bad := range []string{
+		`{"op":"task.add","goal":"001-demo"}`,
+		`{"op":"task.move","goal":"001-demo"}`,
+		`{"op":"task.add","goal":"001-demo","title":"A","colour":"red"}`,
+	} {
+		if _, err := ParseBatch(strings.NewReader(bad)); err == nil || !strings.Contains(err.Error(), "line 1") {
+			t.Errorf("ParseBatch(%s) error = %v", bad, err)
+		}
+	}
+}
+
+func TestRunBatch(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.PlanManager.Save(&model.Plan{GoalID: "001-demo"}); err != nil {
+		t.Fatal(err)
+	}
+	ops := []BatchOp{
+		{Line: 1, Op: BatchTaskAdd, Goal: "001-demo", Title: "Write tests"},
+		{Line: 2, Op: BatchDiscussAdd, Goal: "001-demo", Type: DiscussionTypeDecision, Content: "Use JWT"},
+		{Line: 3, Op: BatchTaskAdd, Goal: "001-demo", Title: "Ship"},
+		{Line: 4, Op: BatchTaskComplete, Goal: "001-demo", Task: "T01"},
+		{Line: 5, Op: BatchDiscussAdd, Goal: "001-demo", Content: "Done"},
+	}
+
+	// A bad op anywhere fails the batch before any file is written.
+	bad := append(append([]BatchOp{}, ops...), BatchOp{Line: 6, Op: BatchTaskComplete, Goal: "001-demo", Task: "T09"})
+	if _, err := app.RunBatch(bad, BatchOptions{}); err == nil || !strings.Contains(err.Error(), "line 6") {
+		t.Fatalf("expected line 6 to fail, got %v", err)
+	}
+	if plan, _ := app.PlanManager.Load("001-demo"); len(plan.Tasks) != 0 {
+		t.Fatalf("failed batch wrote tasks: %+v", plan.Tasks)
+	}
+
+	var applied [][]int
+	results, err := app.RunBatch(ops, BatchOptions{Applied: func(ops []BatchOp) error {
+		var lines []int
+		for _, op := range ops {
+			lines = append(lines, op.Line)
+		}
+		applied = append(applied, lines)
+		return nil
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Results are grouped by file: the plan's ops, then the discussion's.
+	if len(results) != 5 || results[0].Target != "T01" || results[1].Target != "T02" || results[3].Target != "D01" || results[4].Target != "D02" {
+		t.Fatalf("results = %+v", results)
+	}
+	if len(applied) != 2 || len(applied[0]) != 3 || len(applied[1]) != 2 {
+		t.Fatalf("applied = %v", applied)
+	}
+	plan, err := app.PlanManager.Load("001-demo")
+	if err != nil || len(plan.Tasks) != 2 || plan.Tasks[0].Status != TaskStatusCompleted {
+		t.Fatalf("plan = %+v, %v", plan, err)
+	}
+	entries, err := app.DiscussionManager.Load("001-demo")
+	if err != nil || len(entries) != 2 || entries[0].Type != DiscussionTypeDecision {
+		t.Fatalf("entries = %+v, %v", entries, err)
+	}
+
+	// Skipped lines are not applied again on resume.
+	skip := map[int]bool{1: true, 2: true, 3: true, 4: true}
+	if _, err := app.RunBatch(ops, BatchOptions{Skip: skip}); err != nil {
+		t.Fatal(err)
+	}
+	if plan, _ := app.PlanManager.Load("001-demo"); len(plan.Tasks) != 2 {
+		t.Fatalf("resume re-added tasks: %+v", plan.Tasks)
+	}
+	if entries, _ := app.DiscussionManager.Load("001-demo"); len(entries) != 3 {
+		t.Fatalf("entries after resume = %d", len(entries))
+	}
+}
@@ -0,0 +1,178 @@
+package core
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// SyntheticWorkspace sizes a generated workspace for benchmarks. Requirements
+// are spread evenly over Domains; each change modifies one requirement.
+type SyntheticWorkspace struct {
+	Domains      int
+	Requirements int
+	Changes      int
+}
+
+// DefaultSyntheticWorkspace is the size `teamwerx bench` uses by default.
+var DefaultSyntheticWorkspace = SyntheticWorkspace{Domains: 10, Requirements: 1000, Changes: 500}
+
+// SyntheticDomain returns the name of the i-th generated domain.
+func SyntheticDomain(i int) string {
+	return fmt.Sprintf("domain-%03d", i)
+}
+
+// SyntheticSpecContent returns a spec with n requirements, each with a
+// scenario, an acceptance checklist and a rationale note, so every parser
+// path is exercised.
+func SyntheticSpecContent(domain string, n int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "---\nowner: \"@bench\"\nstatus: draft\n---\n\n# %s\n\n## Overview\n\nGenerated for benchmarks.\n\n", domain)
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "### Requirement: Requirement %d\n\nThe system shall handle case %d within budget.\n\n", i, i)
+		b.WriteString("- [x] handles the happy path\n- [ ] handles the failure path\n\n")
+		fmt.Fprintf(&b, "> Rationale: keeps case %d predictable.\n\n", i)
+		fmt.Fprintf(&b, "#### Scenario: Case %d\n\n- GIVEN input %d\n- THEN output %d\n\n", i, i, i)
+	}
+	return b.String()
+}
+
+// GenerateSyntheticWorkspace writes specs and pending changes of the given
+// size through app's managers.
+func GenerateSyntheticWorkspace(app *App, size SyntheticWorkspace) error {
+	if size.Domains < 1 {
+		size.Domains = 1
+	}
+	perDomain := (size.Requirements + size.Domains - 1) / size.Domains
+	fingerprints := make([]string, size.Domains)
+	for d := 0; d < size.Domains; d++ {
+		n := perDomain
+		if rest := size.Requirements - d*perDomain; rest < n {
+			n = rest
+		}
+		if n < 0 {
+			n = 0
+		}
+		spec := &model.Spec{Domain: SyntheticDomain(d), Content: SyntheticSpecContent(SyntheticDomain(d), n)}
+		if err := app.SpecManager.WriteSpec(spec); err != nil {
+			return err
+		}
+		fingerprints[d] = spec.Fingerprint
+	}
+	for c := 0; c < size.Changes; c++ {
+		d := c % size.Domains
+		req := (c / size.Domains) % perDomain
+		title := fmt.Sprintf("Requirement %d", req)
+		ch := &model.Change{
+			ID:        fmt.Sprintf("CH-%04d", c+1),
+			Title:     fmt.Sprintf("Tighten %s/%s", SyntheticDomain(d), title),
+			Status:    "draft",
+			CreatedAt: time.Now(),
+			SpecDeltas: []model.SpecDelta{{
+				Domain:          SyntheticDomain(d),
+				BaseFingerprint: fingerprints[d],
+				Operations: []model.DeltaOperation{{
+					Type:        "MODIFIED",
+					Requirement: model.Requirement{ID: fmt.Sprintf("requirement-%d", req), Title: title, Content: fmt.Sprintf("### Requirement: %s\n\nThe system shall handle case %d within a tighter budget.\n", title, req)},
+				}},
+			}},
+		}
+		if err := app.ChangeManager.Save(ch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BenchResult is the measurement of one benchmarked operation.
+type BenchResult struct {
+	Name        string        `json:"name"`
+	Iterations  int           `json:"iterations"`
+	PerOp       time.Duration `json:"ns_per_op"`
+	AllocsPerOp uint64        `json:"allocs_per_op"`
+	BytesPerOp  uint64        `json:"bytes_per_op"`
+}
+
+// BenchCase is a named operation to measure. Run must be repeatable.
+type BenchCase struct {
+	Name string
+	Run  func() error
+}
+
+// Measure runs fn repeatedly until minTime has passed (at least once) and
+// reports the average cost per run.
+func Measure(name string, minTime time.Duration, fn func() error) (BenchResult, error) {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	n := 0
+	for n == 0 || time.Since(start) < minTime {
+		if err := fn(); err != nil {
+			return BenchResult{Name: name}, fmt.Errorf("%s: %w", name, err)
+		}
+		n++
+	}
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+	return BenchResult{
+		Name:        name,
+		Iterations:  n,
+		PerOp:       elapsed / time.Duration(n),
+		AllocsPerOp: (after.Mallocs - before.Mallocs) / uint64(n),
+		BytesPerOp:  (after.TotalAlloc - before.TotalAlloc) / uint64(n),
+	}, nil
+}
+
+// CoreBenchCases returns the parse, merge and list operations measured by
+// `teamwerx bench` against a workspace created by GenerateSyntheticWorkspace.
+// The merge case re-applies one change, so with idempotent merging it
+// measures parse, diff and fingerprint work without growing the spec.
+func CoreBenchCases(app *App, size SyntheticWorkspace) ([]BenchCase, error) {
+	domain := SyntheticDomain(0)
+	spec, err := app.SpecManager.ReadSpec(domain)
+	if err != nil {
+		return nil, err
+	}
+	content := []byte(spec.Content)
+	parser := NewSpecParser()
+
+	var merge *model.Change
+	if size.Changes > 0 {
+		if merge, err = app.ChangeManager.ReadChange("CH-0001"); err != nil {
+			return nil, err
+		}
+		// Apply once so later runs take the idempotent path.
+		delta := merge.SpecDeltas[0]
+		delta.BaseFingerprint = ""
+		if err := app.SpecMerger.Merge(&delta); err != nil {
+			return nil, err
+		}
+	}
+
+	cases := []BenchCase{
+		{Name: "parse", Run: func() error { _, err := parser.Parse(content); return err }},
+		{Name: "fingerprint", Run: func() error { SpecFingerprint(spec); return nil }},
+		{Name: "list-specs", Run: func() error { _, err := app.SpecManager.ListSpecs(); return err }},
+		{Name: "list-changes", Run: func() error { _, err := app.ChangeManager.ListChanges(); return err }},
+		{Name: "staleness", Run: func() error {
+			changes, err := app.ChangeManager.ListChanges()
+			if err != nil {
+				return err
+			}
+			_, err = CheckStaleness(app.SpecManager, changes)
+			return err
+		}},
+	}
+	if merge != nil {
+		cases = append(cases, BenchCase{Name: "merge", Run: func() error {
+			delta := merge.SpecDeltas[0]
+			delta.BaseFingerprint = ""
+			return app.SpecMerger.Merge(&delta)
+		}})
+	}
+	return cases, nil
+}
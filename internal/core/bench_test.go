@@ -0,0 +1,83 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func newSyntheticApp(tb testing.TB, size SyntheticWorkspace) *App {
+	tb.Helper()
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if err := GenerateSyntheticWorkspace(app, size); err != nil {
+		tb.Fatal(err)
+	}
+	return app
+}
+
+func TestGenerateSyntheticWorkspace(t *testing.T) {
+	size := SyntheticWorkspace{Domains: 3, Requirements: 10, Changes: 5}
+	app := newSyntheticApp(t, size)
+	specs, err := app.SpecManager.ListSpecs()
+	if err != nil || len(specs) != 3 {
+		t.Fatalf("specs = %d, %v", len(specs), err)
+	}
+	total := 0
+	for _, s := range specs {
+		total += len(s.Requirements)
+	}
+	if total != 10 {
+		t.Fatalf("requirements = %d", total)
+	}
+	changes, err := app.ChangeManager.ListChanges()
+	if err != nil || len(changes) != 5 {
+		t.Fatalf("changes = %d, %v", len(changes), err)
+	}
+
+	cases, err := CoreBenchCases(app, size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range cases {
+		if _, err := Measure(c.Name, 0, c.Run); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func benchmarkCase(b *testing.B, name string) {
+	app := newSyntheticApp(b, DefaultSyntheticWorkspace)
+	cases, err := CoreBenchCases(app, DefaultSyntheticWorkspace)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, c := range cases {
+		if c.Name != name {
+			continue
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := c.Run(); err != nil {
+				b.Fatal(err)
+			}
+		}
+		return
+	}
+	b.Fatalf("no bench case %q", name)
+}
+
+func BenchmarkSpecParser_Parse(b *testing.B) { benchmarkCase(b, "parse") }
+
+func BenchmarkSpecFingerprint(b *testing.B) { benchmarkCase(b, "fingerprint") }
+
+func BenchmarkSpecMerger_Merge(b *testing.B) { benchmarkCase(b, "merge") }
+
+func BenchmarkListSpecs(b *testing.B) { benchmarkCase(b, "list-specs") }
+
+func BenchmarkListChanges(b *testing.B) { benchmarkCase(b, "list-changes") }
+
+func BenchmarkCheckStaleness(b *testing.B) { benchmarkCase(b, "staleness") }
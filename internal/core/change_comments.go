@@ -0,0 +1,93 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// ChangeComments returns the review conversation on a change in the order it
+// was written. Comments use the discussion log format and live next to the
+// change (see Layout.ChangeCommentsPath), so archiving a change in its own
+// directory keeps them; the archived copy is read when the live one is gone.
+// A change without comments yields an empty list.
+func (a *App) ChangeComments(changeID string) ([]model.DiscussionEntry, error) {
+	if strings.TrimSpace(changeID) == "" {
+		return nil, custom_errors.NewErrValidation("changeID", "cannot be empty")
+	}
+	o := a.Options
+	for _, path := range []string{
+		o.Layout.ChangeCommentsPath(o.ChangesDir, changeID),
+		o.Layout.ChangeCommentsPath(o.Layout.ArchivePath(o.ChangesDir), changeID),
+	} {
+		data, err := o.Storage.ReadFile(path)
+		if err != nil {
+			if _, ok := err.(*custom_errors.ErrNotFound); ok {
+				continue
+			}
+			return nil, err
+		}
+		entries, err := parseYAMLEntries(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing change comments '%s': %w", path, err)
+		}
+		return entries, nil
+	}
+	return []model.DiscussionEntry{}, nil
+}
+
+// AddChangeComment appends comment to an open change's thread, assigning the
+// next "CNN" ID and, when unset, the comment type and the current time.
+func (a *App) AddChangeComment(changeID string, comment *model.DiscussionEntry) error {
+	if comment == nil {
+		return custom_errors.NewErrConflict("comment cannot be nil")
+	}
+	if strings.TrimSpace(comment.Content) == "" {
+		return custom_errors.NewErrValidation("content", "cannot be empty")
+	}
+	if _, err := a.ChangeManager.ReadChange(changeID); err != nil {
+		return err
+	}
+	if comment.Type == "" {
+		comment.Type = DiscussionTypeComment
+	}
+	if comment.Timestamp.IsZero() {
+		comment.Timestamp = time.Now()
+	}
+
+	path := a.Options.Layout.ChangeCommentsPath(a.Options.ChangesDir, changeID)
+	unlock, err := a.Options.Storage.Lock(path, 0)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	existing, err := a.Options.Storage.ReadFile(path)
+	if err != nil {
+		if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+			return err
+		}
+		existing = nil
+	}
+	current, _ := parseYAMLEntries(existing)
+	comment.ID = nextEntryID("C", current)
+	block, err := marshalEntryYAML(*comment)
+	if err != nil {
+		return err
+	}
+
+	var out bytes.Buffer
+	out.Write(existing)
+	if len(bytes.TrimSpace(existing)) > 0 {
+		if !bytes.HasSuffix(existing, []byte("\n")) {
+			out.WriteString("\n")
+		}
+		out.WriteString("\n")
+	}
+	out.Write(block)
+	return a.Options.Storage.WriteFile(path, out.Bytes(), 0o644)
+}
@@ -0,0 +1,69 @@
+package core
+
+import (
+	"path/filepath"
+	"testing"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestChangeComments_ThreadLivesWithTheChange(t *testing.T) {
+	store := storage.NewInMem()
+	app, err := NewApp(AppOptions{Storage: store})
+	if err != nil {
+		t.Fatalf("NewApp: %v", err)
+	}
+	ch := &model.Change{ID: "CH-001", Title: "Refresh tokens", Status: "draft"}
+	if err := app.ChangeManager.Save(ch); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if comments, err := app.ChangeComments("CH-001"); err != nil || len(comments) != 0 {
+		t.Fatalf("expected no comments yet, got %+v (err %v)", comments, err)
+	}
+	if err := app.AddChangeComment("CH-001", &model.DiscussionEntry{Author: "ana", Content: "Please tighten wording"}); err != nil {
+		t.Fatalf("AddChangeComment: %v", err)
+	}
+	if err := app.AddChangeComment("CH-001", &model.DiscussionEntry{Author: "bo", Content: "Done.\nSee the new scenario."}); err != nil {
+		t.Fatalf("AddChangeComment: %v", err)
+	}
+
+	path := filepath.Join(".teamwerx", "changes", "CH-001", "comments.md")
+	if ok, _ := store.Exists(path); !ok {
+		t.Fatalf("expected comments in %s", path)
+	}
+	comments, err := app.ChangeComments("CH-001")
+	if err != nil || len(comments) != 2 {
+		t.Fatalf("expected 2 comments, got %+v (err %v)", comments, err)
+	}
+	if c := comments[0]; c.ID != "C01" || c.Author != "ana" || c.Type != DiscussionTypeComment || c.Timestamp.IsZero() {
+		t.Fatalf("unexpected first comment: %+v", c)
+	}
+	if c := comments[1]; c.ID != "C02" || c.Content != "Done.\nSee the new scenario." {
+		t.Fatalf("unexpected second comment: %+v", c)
+	}
+
+	if err := app.ChangeManager.ArchiveChange(ch); err != nil {
+		t.Fatalf("ArchiveChange: %v", err)
+	}
+	if comments, err := app.ChangeComments("CH-001"); err != nil || len(comments) != 2 {
+		t.Fatalf("expected comments to follow the change into the archive, got %+v (err %v)", comments, err)
+	}
+
+	err = app.AddChangeComment("CH-404", &model.DiscussionEntry{Content: "hello"})
+	if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+		t.Fatalf("expected ErrNotFound for an unknown change, got %v", err)
+	}
+	if err := app.AddChangeComment("CH-001", &model.DiscussionEntry{Content: " "}); err == nil {
+		t.Fatalf("expected an empty comment to be rejected")
+	}
+}
+
+func TestLayout_ChangeCommentsPathFlatLayout(t *testing.T) {
+	l := Layout{ChangeFile: "{change}.json"}.WithDefaults()
+	if got, want := l.ChangeCommentsPath("changes", "CH-001"), filepath.Join("changes", "CH-001.comments.md"); got != want {
+		t.Fatalf("ChangeCommentsPath = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,209 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// DiffOp says whether a diff segment is unchanged, inserted or deleted.
+type DiffOp int
+
+const (
+	DiffEqual DiffOp = iota
+	DiffInsert
+	DiffDelete
+)
+
+// DiffSegment is a run of text sharing one DiffOp.
+type DiffSegment struct {
+	Op   DiffOp
+	Text string
+}
+
+// OperationDiff is the rendered effect of one delta operation on the current spec.
+type OperationDiff struct {
+	Domain        string
+	Type          string
+	RequirementID string
+	ScenarioID    string // set for scenario operations
+	// Note explains operations that do not do what their type suggests, e.g. a
+	// MODIFIED requirement that is missing from the spec and will be added.
+	Note     string
+	Segments []DiffSegment
+}
+
+// maxWordDiffCells bounds the LCS table; larger inputs are shown as a full
+// delete followed by a full insert.
+const maxWordDiffCells = 4_000_000
+
+// wordTokenPattern splits text into words and the whitespace between them.
+var wordTokenPattern = regexp.MustCompile(`\s+|\S+`)
+
+// WordDiff returns a word-level diff turning old into new. Whitespace runs are
+// tokens too, so concatenating the Equal and Delete segments yields old and
+// concatenating the Equal and Insert segments yields new.
+func WordDiff(old, new string) []DiffSegment {
+	a := wordTokenPattern.FindAllString(old, -1)
+	b := wordTokenPattern.FindAllString(new, -1)
+	if len(a)*len(b) > maxWordDiffCells {
+		var segs []DiffSegment
+		segs = appendSegment(segs, DiffDelete, old)
+		return appendSegment(segs, DiffInsert, new)
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var segs []DiffSegment
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			segs = appendSegment(segs, DiffEqual, a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			segs = appendSegment(segs, DiffDelete, a[i])
+			i++
+		default:
+			segs = appendSegment(segs, DiffInsert, b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		segs = appendSegment(segs, DiffDelete, a[i])
+	}
+	for ; j < len(b); j++ {
+		segs = appendSegment(segs, DiffInsert, b[j])
+	}
+	return segs
+}
+
+// appendSegment appends text, merging it into the last segment when the ops match.
+func appendSegment(segs []DiffSegment, op DiffOp, text string) []DiffSegment {
+	if text == "" {
+		return segs
+	}
+	if n := len(segs); n > 0 && segs[n-1].Op == op {
+		segs[n-1].Text += text
+		return segs
+	}
+	return append(segs, DiffSegment{Op: op, Text: text})
+}
+
+// DiffChange describes every operation in ch against the current spec text:
+// a word diff for MODIFIED, MODIFY_SCENARIO, APPEND and PATCH, the new block
+// for ADDED and ADD_SCENARIO, and the block being deleted for REMOVED and
+// REMOVE_SCENARIO.
+// Each operation is compared with the spec as it is now, not with the result
+// of earlier operations in the same change.
+func DiffChange(specs SpecManager, ch *model.Change) ([]OperationDiff, error) {
+	var diffs []OperationDiff
+	parser := specParser(specs)
+	for _, delta := range ch.SpecDeltas {
+		spec, err := specs.ReadSpec(delta.Domain)
+		if err != nil {
+			if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+				return nil, err
+			}
+			spec = &model.Spec{Domain: delta.Domain}
+		}
+		for _, op := range delta.Operations {
+			diffs = append(diffs, diffOperation(parser, spec, delta.Domain, op))
+		}
+	}
+	return diffs, nil
+}
+
+func diffOperation(parser *SpecParser, spec *model.Spec, domain string, op model.DeltaOperation) OperationDiff {
+	d := OperationDiff{Domain: domain, Type: op.Type, RequirementID: op.Requirement.ID}
+	var current *model.Requirement
+	for i := range spec.Requirements {
+		if spec.Requirements[i].ID == op.Requirement.ID {
+			current = &spec.Requirements[i]
+			break
+		}
+	}
+
+	switch op.Type {
+	case "ADDED":
+		d.Segments = appendSegment(nil, DiffInsert, buildRequirementText(op.Requirement, parser.Format()))
+	case "REMOVED":
+		if current == nil {
+			d.Note = "requirement is not in the spec; nothing to remove"
+			break
+		}
+		d.Segments = appendSegment(nil, DiffDelete, spec.Content[current.Start:current.End])
+	case "MODIFIED":
+		proposed := buildRequirementText(op.Requirement, parser.Format())
+		if current == nil {
+			d.Note = "requirement is not in the spec; it will be added"
+			d.Segments = appendSegment(nil, DiffInsert, proposed)
+			break
+		}
+		d.Segments = WordDiff(spec.Content[current.Start:current.End], proposed)
+	case "ADD_SCENARIO", "MODIFY_SCENARIO", "REMOVE_SCENARIO":
+		if op.Scenario == nil {
+			d.Note = "operation has no scenario"
+			break
+		}
+		d.ScenarioID = scenarioID(op.Scenario)
+		if current == nil {
+			d.Note = "requirement is not in the spec; the operation will fail"
+			break
+		}
+		var existing *model.Scenario
+		for i := range current.Scenarios {
+			if current.Scenarios[i].ID == d.ScenarioID {
+				existing = &current.Scenarios[i]
+				break
+			}
+		}
+		proposed := buildScenarioText(*op.Scenario)
+		switch {
+		case op.Type == "REMOVE_SCENARIO" && existing == nil:
+			d.Note = "scenario is not in the requirement; nothing to remove"
+		case op.Type == "REMOVE_SCENARIO":
+			d.Segments = appendSegment(nil, DiffDelete, spec.Content[existing.Start:existing.End])
+		case op.Type == "MODIFY_SCENARIO" && existing != nil:
+			d.Segments = WordDiff(spec.Content[existing.Start:existing.End], proposed)
+		default:
+			if op.Type == "MODIFY_SCENARIO" {
+				d.Note = "scenario is not in the requirement; it will be added"
+			}
+			d.Segments = appendSegment(nil, DiffInsert, proposed)
+		}
+	case "APPEND", "PATCH":
+		if current == nil {
+			d.Note = "requirement is not in the spec; the operation will fail"
+			break
+		}
+		text := spec.Content[current.Start:current.End]
+		edited, err := editRequirementText(parser, text, op)
+		if err != nil {
+			d.Note = err.Error()
+			break
+		}
+		d.Segments = WordDiff(text, edited)
+	default:
+		d.Note = fmt.Sprintf("unknown operation type: %s", op.Type)
+	}
+	return d
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+func TestWordDiff_ReconstructsBothSides(t *testing.T) {
+	old := "Users SHALL log in with a password.\n"
+	new := "Users SHALL log in with a password or SSO token.\n"
+	segs := WordDiff(old, new)
+
+	var gotOld, gotNew, inserted strings.Builder
+	for _, s := range segs {
+		if s.Op != DiffInsert {
+			gotOld.WriteString(s.Text)
+		}
+		if s.Op != DiffDelete {
+			gotNew.WriteString(s.Text)
+		}
+		if s.Op == DiffInsert {
+			inserted.WriteString(s.Text)
+		}
+	}
+	if gotOld.String() != old || gotNew.String() != new {
+		t.Fatalf("segments do not reconstruct inputs: %+v", segs)
+	}
+	if !strings.Contains(inserted.String(), "SSO token") || strings.Contains(inserted.String(), "Users") {
+		t.Fatalf("unexpected insertions %q", inserted.String())
+	}
+}
+
+func TestDiffChange_DescribesEachOperation(t *testing.T) {
+	baseDir := createTempDir(t)
+	writeSpecFile(t, baseDir, "auth", "# Auth\n\n### Requirement: Login\n\nUsers log in.\n\n### Requirement: Logout\n\nBye.\n")
+	specs := NewSpecManager(baseDir)
+
+	ch := &model.Change{ID: "CH-001", SpecDeltas: []model.SpecDelta{{
+		Domain: "auth",
+		Operations: []model.DeltaOperation{
+			{Type: "MODIFIED", Requirement: model.Requirement{ID: "login", Title: "Login", Content: "### Requirement: Login\n\nUsers log in with SSO.\n"}},
+			{Type: "ADDED", Requirement: model.Requirement{ID: "reset", Title: "Reset", Content: "### Requirement: Reset\n\nUsers reset passwords.\n"}},
+			{Type: "REMOVED", Requirement: model.Requirement{ID: "logout"}},
+			{Type: "REMOVED", Requirement: model.Requirement{ID: "missing"}},
+		},
+	}}}
+
+	diffs, err := DiffChange(specs, ch)
+	if err != nil {
+		t.Fatalf("DiffChange: %v", err)
+	}
+	if len(diffs) != 4 {
+		t.Fatalf("expected 4 diffs, got %d", len(diffs))
+	}
+
+	modified := diffs[0].Segments
+	var ins, del string
+	for _, s := range modified {
+		switch s.Op {
+		case DiffInsert:
+			ins += s.Text
+		case DiffDelete:
+			del += s.Text
+		}
+	}
+	if !strings.Contains(ins, "with SSO.") || del != "in." {
+		t.Fatalf("unexpected MODIFIED diff: %+v", modified)
+	}
+	if len(diffs[1].Segments) != 1 || diffs[1].Segments[0].Op != DiffInsert || !strings.Contains(diffs[1].Segments[0].Text, "Users reset passwords.") {
+		t.Fatalf("unexpected ADDED diff: %+v", diffs[1].Segments)
+	}
+	if len(diffs[2].Segments) != 1 || diffs[2].Segments[0].Op != DiffDelete || !strings.HasPrefix(diffs[2].Segments[0].Text, "### Requirement: Logout") {
+		t.Fatalf("unexpected REMOVED diff: %+v", diffs[2].Segments)
+	}
+	if diffs[3].Note == "" || len(diffs[3].Segments) != 0 {
+		t.Fatalf("expected a note for removing a missing requirement: %+v", diffs[3])
+	}
+}
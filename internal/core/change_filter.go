@@ -0,0 +1,93 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// ChangeFilter selects and orders changes for ListChangesFiltered. Zero
+// fields match everything; string fields compare case-insensitively.
+type ChangeFilter struct {
+	Status string
+	GoalID string
+	Domain string    // the change has a spec delta for this domain
+	Since  time.Time // created at or after this time
+	// Milestone selects the changes assigned to this milestone.
+	Milestone string
+	// CreatedBy selects the changes whose author is this person or agent.
+	CreatedBy string
+	Sort      string // one of ChangeSortKeys ("id" by default)
+	Order     string // SortAsc (default) or SortDesc
+}
+
+// Matches reports whether ch passes every set criterion of f.
+func (f ChangeFilter) Matches(ch *model.Change) bool {
+	if f.Status != "" && !strings.EqualFold(ch.Status, f.Status) {
+		return false
+	}
+	if f.GoalID != "" && !strings.EqualFold(ch.GoalID, f.GoalID) {
+		return false
+	}
+	if f.Milestone != "" && !strings.EqualFold(ch.Milestone, f.Milestone) {
+		return false
+	}
+	if f.CreatedBy != "" && !strings.EqualFold(ch.Author, f.CreatedBy) {
+		return false
+	}
+	if !f.Since.IsZero() && ch.CreatedAt.Before(f.Since) {
+		return false
+	}
+	if f.Domain != "" {
+		for _, d := range ch.SpecDeltas {
+			if strings.EqualFold(d.Domain, f.Domain) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// ListChangesFiltered returns the changes matching filter, sorted by
+// filter.Sort and filter.Order.
+func (m *changeManager) ListChangesFiltered(filter ChangeFilter) ([]*model.Change, error) {
+	all, err := m.ListChanges()
+	if err != nil {
+		return nil, err
+	}
+	changes := []*model.Change{}
+	for _, ch := range all {
+		if filter.Matches(ch) {
+			changes = append(changes, ch)
+		}
+	}
+	if err := SortChanges(changes, filter.Sort, filter.Order); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// ParseSince parses a --since value relative to now: a date (2006-01-02), an
+// RFC 3339 timestamp, or an age such as "36h" or "7d".
+func ParseSince(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if days := strings.TrimSuffix(s, "d"); days != s {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q (expected YYYY-MM-DD, an RFC 3339 time, or an age like 36h or 7d)", s)
+}
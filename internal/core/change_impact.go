@@ -0,0 +1,194 @@
+package core
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/utils"
+)
+
+// specReferencePattern matches a cross-reference to another requirement in
+// spec text, written as [[<domain>/<requirement-id>]].
+var specReferencePattern = regexp.MustCompile(`\[\[\s*([^\]/\s]+)\s*/\s*([^\]\s]+)\s*\]\]`)
+
+// SpecReference is a [[domain/requirement]] link found in a requirement.
+type SpecReference struct {
+	Domain        string `json:"domain"`
+	RequirementID string `json:"requirement_id"`
+}
+
+// String renders the reference as "<domain>/<requirement-id>", the same form
+// tasks use to link requirements.
+func (r SpecReference) String() string { return r.Domain + "/" + r.RequirementID }
+
+// ParseSpecReferences returns the distinct [[domain/requirement]] references
+// in text, in order of appearance. IDs are lower-cased.
+func ParseSpecReferences(text string) []SpecReference {
+	var refs []SpecReference
+	seen := map[SpecReference]bool{}
+	for _, m := range specReferencePattern.FindAllStringSubmatch(text, -1) {
+		ref := SpecReference{Domain: strings.ToLower(m[1]), RequirementID: strings.ToLower(m[2])}
+		if !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// ImpactedRequirement is a requirement a change edits directly.
+type ImpactedRequirement struct {
+	Domain        string   `json:"domain"`
+	RequirementID string   `json:"requirement_id"`
+	Operations    []string `json:"operations"`          // e.g. MODIFIED, ADD_SCENARIO
+	Scenarios     []string `json:"scenarios,omitempty"` // scenario IDs touched by scenario operations
+}
+
+// ImpactedReference is a requirement outside the change that references an
+// affected requirement. Depth 1 references a changed requirement directly;
+// depth n references a requirement at depth n-1.
+type ImpactedReference struct {
+	Domain        string `json:"domain"`
+	RequirementID string `json:"requirement_id"`
+	References    string `json:"references"` // the affected "<domain>/<requirement-id>" it links to
+	Depth         int    `json:"depth"`
+}
+
+// ImpactedTask is a plan task linked to an affected requirement.
+type ImpactedTask struct {
+	GoalID      string `json:"goal_id"`
+	TaskID      string `json:"task_id"`
+	Title       string `json:"title"`
+	Status      string `json:"status"`
+	Requirement string `json:"requirement"` // the affected "<domain>/<requirement-id>" it links
+}
+
+// ChangeImpact is the blast radius of a change.
+type ChangeImpact struct {
+	ChangeID     string                `json:"change_id"`
+	Requirements []ImpactedRequirement `json:"requirements"`
+	References   []ImpactedReference   `json:"references,omitempty"`
+	Tasks        []ImpactedTask        `json:"tasks,omitempty"`
+	// Domains lists the cross-referenced domains the change does not edit.
+	Domains []string `json:"domains,omitempty"`
+}
+
+// AnalyzeChangeImpact reports what ch affects: the requirements it edits, the
+// requirements in any spec that reference them (following [[domain/req]]
+// links transitively), and the plan tasks of every goal linked to any of
+// those requirements.
+func AnalyzeChangeImpact(app *App, ch *model.Change) (*ChangeImpact, error) {
+	impact := &ChangeImpact{ChangeID: ch.ID}
+	specs, err := app.SpecManager.ListSpecs()
+	if err != nil {
+		return nil, err
+	}
+	refs := newRequirementRefs(specs)
+	edited := map[string]bool{}
+	byKey := map[string]int{}
+	for _, delta := range ch.SpecDeltas {
+		domain := strings.ToLower(delta.Domain)
+		edited[domain] = true
+		for _, op := range delta.Operations {
+			key := refs.key(SpecReference{Domain: domain, RequirementID: strings.ToLower(op.Requirement.ID)}.String())
+			i, ok := byKey[key]
+			if !ok {
+				i = len(impact.Requirements)
+				byKey[key] = i
+				impact.Requirements = append(impact.Requirements, ImpactedRequirement{Domain: delta.Domain, RequirementID: op.Requirement.ID})
+			}
+			r := &impact.Requirements[i]
+			if !containsString(r.Operations, op.Type) {
+				r.Operations = append(r.Operations, op.Type)
+			}
+			if op.Scenario != nil && !containsString(r.Scenarios, op.Scenario.ID) {
+				r.Scenarios = append(r.Scenarios, op.Scenario.ID)
+			}
+		}
+	}
+
+	// Index every requirement by the affected requirements it references.
+	referrers := map[string][]SpecReference{}
+	for _, spec := range specs {
+		for _, req := range spec.Requirements {
+			from := SpecReference{Domain: strings.ToLower(spec.Domain), RequirementID: strings.ToLower(req.ID)}
+			for _, ref := range ParseSpecReferences(req.Content) {
+				if key := refs.key(ref.String()); key != from.String() {
+					referrers[key] = append(referrers[key], from)
+				}
+			}
+		}
+	}
+
+	// Breadth-first over inbound references, so each requirement is reported
+	// once at its shortest distance from the change.
+	affected := map[string]bool{}
+	frontier := make([]string, 0, len(byKey))
+	for key := range byKey {
+		affected[key] = true
+		frontier = append(frontier, key)
+	}
+	sort.Strings(frontier)
+	for depth := 1; len(frontier) > 0; depth++ {
+		var next []string
+		for _, target := range frontier {
+			for _, from := range referrers[target] {
+				key := from.String()
+				if affected[key] {
+					continue
+				}
+				affected[key] = true
+				next = append(next, key)
+				impact.References = append(impact.References, ImpactedReference{
+					Domain: from.Domain, RequirementID: from.RequirementID, References: target, Depth: depth,
+				})
+			}
+		}
+		sort.Strings(next)
+		frontier = next
+	}
+	domains := map[string]bool{}
+	for _, ref := range impact.References {
+		if !edited[ref.Domain] {
+			domains[ref.Domain] = true
+		}
+	}
+	for d := range domains {
+		impact.Domains = append(impact.Domains, d)
+	}
+	sort.Strings(impact.Domains)
+
+	goalIDs, err := ListGoalIDs(app.Options)
+	if err != nil {
+		return nil, err
+	}
+	for _, goalID := range goalIDs {
+		plan, err := app.PlanManager.Load(goalID)
+		if err != nil {
+			if _, ok := err.(*custom_errors.ErrNotFound); ok {
+				continue
+			}
+			return nil, err
+		}
+		for _, t := range plan.Tasks {
+			for _, link := range t.Requirements {
+				if key := refs.key(link); affected[key] {
+					impact.Tasks = append(impact.Tasks, ImpactedTask{
+						GoalID: goalID, TaskID: t.ID, Title: t.Title, Status: t.Status, Requirement: key,
+					})
+					break
+				}
+			}
+		}
+	}
+	sort.SliceStable(impact.Tasks, func(i, j int) bool {
+		if impact.Tasks[i].GoalID != impact.Tasks[j].GoalID {
+			return utils.NaturalLess(impact.Tasks[i].GoalID, impact.Tasks[j].GoalID)
+		}
+		return utils.NaturalLess(impact.Tasks[i].TaskID, impact.Tasks[j].TaskID)
+	})
+	return impact, nil
+}
@@ -0,0 +1,70 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestParseSpecReferences(t *testing.T) {
+	got := ParseSpecReferences("See [[Auth/Login]] and [[ billing / invoice-totals ]], again [[auth/login]]; not [[nope]].")
+	want := []SpecReference{{"auth", "login"}, {"billing", "invoice-totals"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseSpecReferences = %+v, want %+v", got, want)
+	}
+}
+
+func TestAnalyzeChangeImpact_FollowsReferencesAndTasks(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, spec := range []*model.Spec{
+		{Domain: "auth", Content: "# Auth\n\n### Requirement: Login\n\nUsers log in.\n\n### Requirement: Logout\n\nUsers log out.\n"},
+		{Domain: "billing", Content: "# Billing\n\n### Requirement: Checkout\n\nRequires a session, see [[auth/login]].\n"},
+		{Domain: "reports", Content: "# Reports\n\n### Requirement: Revenue\n\nBuilt from [[billing/checkout]].\n\n### Requirement: Usage\n\nUnrelated.\n"},
+	} {
+		if err := app.SpecManager.WriteSpec(spec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	plan := &model.Plan{GoalID: "001-pay", Tasks: []model.Task{
+		{ID: "T01", Title: "Wire checkout", Status: "pending", Requirements: []string{"billing/checkout"}},
+		{ID: "T02", Title: "Usage chart", Status: "pending", Requirements: []string{"reports/usage"}},
+	}}
+	if err := app.PlanManager.Save(plan); err != nil {
+		t.Fatal(err)
+	}
+
+	ch := &model.Change{ID: "CH-001", SpecDeltas: []model.SpecDelta{{
+		Domain: "auth",
+		Operations: []model.DeltaOperation{
+			{Type: "MODIFIED", Requirement: model.Requirement{ID: "login"}},
+			{Type: "ADD_SCENARIO", Requirement: model.Requirement{ID: "login"}, Scenario: &model.Scenario{ID: "lockout"}},
+		},
+	}}}
+	impact, err := AnalyzeChangeImpact(app, ch)
+	if err != nil {
+		t.Fatalf("AnalyzeChangeImpact: %v", err)
+	}
+
+	wantReqs := []ImpactedRequirement{{Domain: "auth", RequirementID: "login", Operations: []string{"MODIFIED", "ADD_SCENARIO"}, Scenarios: []string{"lockout"}}}
+	if !reflect.DeepEqual(impact.Requirements, wantReqs) {
+		t.Fatalf("requirements = %+v", impact.Requirements)
+	}
+	wantRefs := []ImpactedReference{
+		{Domain: "billing", RequirementID: "checkout", References: "auth/login", Depth: 1},
+		{Domain: "reports", RequirementID: "revenue", References: "billing/checkout", Depth: 2},
+	}
+	if !reflect.DeepEqual(impact.References, wantRefs) {
+		t.Fatalf("references = %+v", impact.References)
+	}
+	if len(impact.Tasks) != 1 || impact.Tasks[0].TaskID != "T01" || impact.Tasks[0].Requirement != "billing/checkout" {
+		t.Fatalf("tasks = %+v", impact.Tasks)
+	}
+	if !reflect.DeepEqual(impact.Domains, []string{"billing", "reports"}) {
+		t.Fatalf("domains = %v", impact.Domains)
+	}
+}
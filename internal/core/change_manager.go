@@ -5,40 +5,61 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"time"
 
 	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
 	"github.com/teamwerx/teamwerx/internal/model"
-	fileutil "github.com/teamwerx/teamwerx/internal/utils/file"
+	"github.com/teamwerx/teamwerx/internal/storage"
+	"github.com/teamwerx/teamwerx/internal/utils"
+	"github.com/teamwerx/teamwerx/internal/version"
 )
 
 // changeManager is a file-backed implementation of ChangeManager.
-// It persists each change under: <baseDir>/<changeID>/change.json
-// Archives are moved to:          <baseDir>/.archive/<changeID>/
+// With the default layout it persists each change under: <baseDir>/<changeID>/change.json
+// Archives are moved to:                                   <baseDir>/.archive/<changeID>/
 type changeManager struct {
 	baseDir     string
+	layout      Layout
 	specManager SpecManager
 	specMerger  SpecMerger
+	store       storage.Storage
+	// owners loads the ownership rules checked on apply; nil means none.
+	owners func() (*Owners, error)
 }
 
-// NewChangeManager constructs a new file-backed ChangeManager.
+// NewChangeManager constructs a new file-backed ChangeManager using the default layout.
 // - baseDir should point to the directory where change folders live (e.g., ".teamwerx/changes").
 // - specManager provides read/write access to specs.
 // - specMerger applies SpecDelta operations to specs.
 func NewChangeManager(baseDir string, specManager SpecManager, specMerger SpecMerger) ChangeManager {
+	return NewChangeManagerWithLayout(baseDir, DefaultLayout(), specManager, specMerger)
+}
+
+// NewChangeManagerWithLayout constructs a ChangeManager storing changes at
+// layout.ChangeFile and archiving them under layout.ArchiveDir.
+func NewChangeManagerWithLayout(baseDir string, layout Layout, specManager SpecManager, specMerger SpecMerger) ChangeManager {
+	return newChangeManager(baseDir, layout, specManager, specMerger, storage.OS(), nil)
+}
+
+func newChangeManager(baseDir string, layout Layout, specManager SpecManager, specMerger SpecMerger, store storage.Storage, owners func() (*Owners, error)) ChangeManager {
 	return &changeManager{
 		baseDir:     baseDir,
+		layout:      layout.WithDefaults(),
 		specManager: specManager,
 		specMerger:  specMerger,
+		store:       store,
+		owners:      owners,
 	}
 }
 
 func (m *changeManager) ReadChange(changeID string) (*model.Change, error) {
 	if changeID == "" {
-		return nil, custom_errors.NewErrConflict("changeID cannot be empty")
+		return nil, custom_errors.NewErrValidation("changeID", "cannot be empty")
 	}
 	path := m.changeFile(changeID)
-	b, err := fileutil.ReadFile(path)
+	b, err := m.store.ReadFile(path)
 	if err != nil {
 		// Translate file not found to resource not found with "change"
 		if _, ok := err.(*custom_errors.ErrNotFound); ok {
@@ -54,11 +75,12 @@ func (m *changeManager) ReadChange(changeID string) (*model.Change, error) {
 	if ch.ID == "" {
 		ch.ID = changeID
 	}
+	ch.LoadedFingerprint = utils.GenerateFingerprint(string(b))
 	return &ch, nil
 }
 
 func (m *changeManager) ListChanges() ([]*model.Change, error) {
-	entries, err := os.ReadDir(m.baseDir)
+	ids, err := findTemplateMatches(m.store, m.baseDir, m.layout.ChangeFile, changePlaceholder, m.layout.ArchiveDir)
 	if err != nil {
 		// If base directory doesn't exist, treat as empty list rather than error.
 		if os.IsNotExist(err) {
@@ -68,13 +90,9 @@ func (m *changeManager) ListChanges() ([]*model.Change, error) {
 	}
 
 	var changes []*model.Change
-	for _, e := range entries {
-		// Skip files and archive folder
-		if !e.IsDir() || e.Name() == ".archive" {
-			continue
-		}
-		path := filepath.Join(m.baseDir, e.Name(), "change.json")
-		b, rerr := os.ReadFile(path)
+	for _, id := range ids {
+		path := m.changeFile(id)
+		b, rerr := m.store.ReadFile(path)
 		if rerr != nil {
 			// Ignore unreadable change entries
 			continue
@@ -86,10 +104,15 @@ func (m *changeManager) ListChanges() ([]*model.Change, error) {
 		}
 		// Ensure ID is set
 		if ch.ID == "" {
-			ch.ID = e.Name()
+			ch.ID = id
 		}
+		ch.LoadedFingerprint = utils.GenerateFingerprint(string(b))
 		changes = append(changes, &ch)
 	}
+	// Natural ID order so CH-10 follows CH-9 regardless of directory order.
+	if err := SortChanges(changes, "", SortAsc); err != nil {
+		return nil, err
+	}
 	return changes, nil
 }
 
@@ -98,30 +121,106 @@ func (m *changeManager) ApplyChange(change *model.Change) error {
 		return custom_errors.NewErrConflict("change cannot be nil")
 	}
 	if change.ID == "" {
-		return custom_errors.NewErrConflict("change.ID cannot be empty")
+		return custom_errors.NewErrValidation("change.ID", "cannot be empty")
+	}
+
+	return m.ApplyChangeTargets(change, ApplyTarget{})
+}
+
+// checkApprovals enforces owner sign-off against the current ownership rules,
+// so domains added to the change after it was submitted are not let through.
+func (m *changeManager) checkApprovals(change *model.Change) error {
+	owners := &Owners{}
+	if m.owners != nil {
+		o, err := m.owners()
+		if err != nil {
+			return fmt.Errorf("failed to load owners: %w", err)
+		}
+		owners = o
+	}
+	return CheckApprovals(owners, change)
+}
+
+// ApplyChangeTargets merges the change's pending operations selected by
+// target; an empty target selects all of them. Merged operations are stamped
+// with AppliedAt. While operations remain the change is "partially-applied",
+// and each delta with pending operations gets its base fingerprint moved to
+// the spec it just produced, so a later apply finishes the rest without being
+// reported as diverged. It returns ErrConflict when nothing pending matches.
+// With the markers strategy a diverged delta is merged with conflict markers
+// (see MergeWithMarkers) and its conflicts are added to change.Conflicts.
+func (m *changeManager) ApplyChangeTargets(change *model.Change, target ApplyTarget) error {
+	if change == nil {
+		return custom_errors.NewErrConflict("change cannot be nil")
+	}
+	if change.ID == "" {
+		return custom_errors.NewErrValidation("change.ID", "cannot be empty")
+	}
+	if err := m.checkApprovals(change); err != nil {
+		return err
 	}
 
-	// Apply each SpecDelta using the SpecMerger
+	now := time.Now()
+	merged := 0
+	var mergeErr error
 	for i := range change.SpecDeltas {
 		d := &change.SpecDeltas[i]
-		if err := m.specMerger.Merge(d); err != nil {
-			return err
+		var selected []int
+		for j, op := range d.Operations {
+			if op.AppliedAt == nil && target.Selects(d.Domain, op) {
+				selected = append(selected, j)
+			}
+		}
+		// A delta without operations still has its base fingerprint checked.
+		empty := len(d.Operations) == 0 && len(target.Ops) == 0 && target.Selects(d.Domain, model.DeltaOperation{})
+		if len(selected) == 0 && !empty {
+			continue
+		}
+		partial := &model.SpecDelta{Domain: d.Domain, BaseFingerprint: d.BaseFingerprint}
+		for _, j := range selected {
+			partial.Operations = append(partial.Operations, d.Operations[j])
+		}
+		if strings.EqualFold(target.Strategy, MergeStrategyMarkers) {
+			var conflicts []model.MergeConflict
+			if conflicts, mergeErr = m.specMerger.MergeWithMarkers(partial, change.ID); mergeErr != nil {
+				break
+			}
+			change.Conflicts = append(change.Conflicts, conflicts...)
+		} else if mergeErr = m.specMerger.Merge(partial); mergeErr != nil {
+			break
+		}
+		for _, j := range selected {
+			d.Operations[j].AppliedAt = &now
+		}
+		merged += len(selected)
+		if empty {
+			merged++
+		}
+		d.AppliedFingerprint = partial.AppliedFingerprint
+		if hasPendingOperations(*d) && d.BaseFingerprint != "" {
+			d.BaseFingerprint = partial.AppliedFingerprint
 		}
 	}
+	if merged == 0 {
+		if mergeErr != nil {
+			return mergeErr
+		}
+		return custom_errors.NewErrConflict(fmt.Sprintf("change %s has no pending operations matching the selection", change.ID))
+	}
 
-	// Mark as applied and persist
-	if change.Status == "" {
-		change.Status = "applied"
-	} else {
-		change.Status = "applied"
+	// Record progress even when a later delta failed, so the merged
+	// operations are not applied twice.
+	change.Status = "applied"
+	if len(RemainingOperations(change)) > 0 {
+		change.Status = ChangeStatusPartiallyApplied
 	}
 	if change.CreatedAt.IsZero() {
-		change.CreatedAt = time.Now()
+		change.CreatedAt = now
 	}
 	if err := m.saveChange(change); err != nil {
 		return err
 	}
-	return nil
+	return mergeErr
 }
 
 func (m *changeManager) ArchiveChange(change *model.Change) error {
@@ -129,32 +228,407 @@ func (m *changeManager) ArchiveChange(change *model.Change) error {
 		return custom_errors.NewErrConflict("change cannot be nil")
 	}
 	if change.ID == "" {
-		return custom_errors.NewErrConflict("change.ID cannot be empty")
+		return custom_errors.NewErrValidation("change.ID", "cannot be empty")
 	}
 
-	srcDir := m.changeDir(change.ID)
-	dstDir := filepath.Join(m.baseDir, ".archive", change.ID)
+	// The archive mirrors the live layout: move the change's own directory when it
+	// has one (preserves any artifacts), otherwise just the change file.
+	archive := m.layout.ArchivePath(m.baseDir)
+	src, dst := m.changeFile(change.ID), m.layout.ChangePath(archive, change.ID)
+	if dirTmpl := ownDirTemplate(m.layout.ChangeFile, changePlaceholder); dirTmpl != "" {
+		src = filepath.Join(m.baseDir, expandTemplate(dirTmpl, changePlaceholder, change.ID))
+		dst = filepath.Join(archive, expandTemplate(dirTmpl, changePlaceholder, change.ID))
+	}
 
 	// Ensure archive parent exists
-	if err := fileutil.MkdirAll(filepath.Dir(dstDir), 0o755); err != nil {
+	if err := m.store.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+
+	// Stamp the archive time before moving, so retention does not depend on
+	// file modification times that copies and later edits can change.
+	now := time.Now()
+	change.ArchivedAt = &now
+	if err := m.saveChange(change); err != nil {
 		return err
 	}
 
-	// Attempt to move entire directory (preserves any artifacts)
-	if err := os.Rename(srcDir, dstDir); err != nil {
-		// Best-effort fallback: write archived change JSON and remove original JSON
-		// (Note: we won't recursively copy arbitrary artifacts in this fallback)
+	if err := m.store.Rename(src, dst); err != nil {
+		// The rename can fail, e.g. across devices: copy the change's
+		// directory with every artifact in it, or write just the change,
+		// then remove the original.
+		if dst != m.layout.ChangePath(archive, change.ID) {
+			if err := m.store.CopyDir(src, dst); err != nil {
+				return err
+			}
+		}
 		change.Status = "archived"
-		if err := m.saveChangeToPath(change, filepath.Join(dstDir, "change.json")); err != nil {
+		if err := m.saveChangeToPath(change, m.layout.ChangePath(archive, change.ID)); err != nil {
 			return err
 		}
-		_ = os.RemoveAll(srcDir) // cleanup original dir; ignore errors
+		_ = m.store.RemoveAll(src) // cleanup original; ignore errors
 		return nil
 	}
 
 	return nil
 }
 
+// SplitChange breaks a multi-domain change into one change per domain.
+// Each new change is named "<id>-<domain>", copies the title, goal and
+// dependencies of the original, and is persisted immediately. Other changes that
+// depended on the original are rewired to depend on all of the new changes, and
+// the original is archived with status "split".
+func (m *changeManager) SplitChange(change *model.Change) ([]*model.Change, error) {
+	if change == nil {
+		return nil, custom_errors.NewErrConflict("change cannot be nil")
+	}
+	if change.ID == "" {
+		return nil, custom_errors.NewErrValidation("change.ID", "cannot be empty")
+	}
+
+	// Group deltas by domain, preserving first-seen order.
+	var domains []string
+	byDomain := map[string][]model.SpecDelta{}
+	for _, d := range change.SpecDeltas {
+		if _, ok := byDomain[d.Domain]; !ok {
+			domains = append(domains, d.Domain)
+		}
+		byDomain[d.Domain] = append(byDomain[d.Domain], d)
+	}
+	if len(domains) < 2 {
+		return nil, custom_errors.NewErrConflict(fmt.Sprintf("change %s touches %d domain(s); nothing to split", change.ID, len(domains)))
+	}
+
+	var parts []*model.Change
+	for _, domain := range domains {
+		id := fmt.Sprintf("%s-%s", change.ID, utils.ToKebabCase(domain))
+		if ok, _ := m.store.Exists(m.changeFile(id)); ok {
+			return nil, custom_errors.NewErrConflict(fmt.Sprintf("change %s already exists", id))
+		}
+		parts = append(parts, &model.Change{
+			ID:         id,
+			Title:      fmt.Sprintf("%s (%s)", change.Title, domain),
+			Status:     change.Status,
+			GoalID:     change.GoalID,
+			CreatedAt:  time.Now(),
+			SpecDeltas: byDomain[domain],
+			DependsOn:  append([]string(nil), change.DependsOn...),
+		})
+	}
+
+	for _, p := range parts {
+		if err := m.saveChange(p); err != nil {
+			return nil, err
+		}
+	}
+
+	partIDs := make([]string, len(parts))
+	for i, p := range parts {
+		partIDs[i] = p.ID
+	}
+	if err := m.rewireDependencies(map[string][]string{change.ID: partIDs}); err != nil {
+		return nil, err
+	}
+
+	change.Status = "split"
+	if err := m.saveChange(change); err != nil {
+		return nil, err
+	}
+	if err := m.ArchiveChange(change); err != nil {
+		return nil, err
+	}
+	return parts, nil
+}
+
+// MergeChanges combines the deltas of several changes into a new change intoID.
+// Deltas for the same domain are folded together; identical operations are
+// de-duplicated, while conflicting operations on the same requirement (or
+// differing base fingerprints for a domain) return ErrConflict. On success the
+// merged change is saved, dependents are rewired to it, and the sources are
+// archived with status "merged".
+func (m *changeManager) MergeChanges(changeIDs []string, intoID, title string) (*model.Change, error) {
+	if len(changeIDs) < 2 {
+		return nil, custom_errors.NewErrValidation("changeIDs", "at least two changes are required to merge")
+	}
+	if strings.TrimSpace(intoID) == "" {
+		return nil, custom_errors.NewErrValidation("intoID", "cannot be empty")
+	}
+	if ok, _ := m.store.Exists(m.changeFile(intoID)); ok {
+		return nil, custom_errors.NewErrConflict(fmt.Sprintf("change %s already exists", intoID))
+	}
+
+	var sources []*model.Change
+	for _, id := range changeIDs {
+		ch, err := m.ReadChange(id)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, ch)
+	}
+
+	deltas, err := mergeSpecDeltas(sources)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := map[string]bool{}
+	for _, id := range changeIDs {
+		merged[id] = true
+	}
+	var deps []string
+	seenDep := map[string]bool{}
+	for _, src := range sources {
+		for _, dep := range src.DependsOn {
+			if !merged[dep] && !seenDep[dep] {
+				seenDep[dep] = true
+				deps = append(deps, dep)
+			}
+		}
+	}
+
+	if strings.TrimSpace(title) == "" {
+		title = "Merge of " + strings.Join(changeIDs, ", ")
+	}
+	out := &model.Change{
+		ID:         intoID,
+		Title:      title,
+		Status:     "draft",
+		GoalID:     sources[0].GoalID,
+		CreatedAt:  time.Now(),
+		SpecDeltas: deltas,
+		DependsOn:  deps,
+	}
+	if err := m.saveChange(out); err != nil {
+		return nil, err
+	}
+
+	rewire := map[string][]string{}
+	for _, id := range changeIDs {
+		rewire[id] = []string{intoID}
+	}
+	if err := m.rewireDependencies(rewire); err != nil {
+		return nil, err
+	}
+
+	for _, src := range sources {
+		src.Status = "merged"
+		if err := m.saveChange(src); err != nil {
+			return nil, err
+		}
+		if err := m.ArchiveChange(src); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// CloneChange saves a new draft change newID with the title, description,
+// goal, dependencies and spec deltas of sourceID, which may be pending or
+// archived (e.g. a rejected change getting a second attempt). Operations are
+// marked unapplied, approvals are dropped, each delta's BaseFingerprint and
+// operation bases are refreshed from its spec's current version, and
+// ClonedFrom records the source.
+func (m *changeManager) CloneChange(sourceID, newID string) (*model.Change, error) {
+	if strings.TrimSpace(newID) == "" {
+		return nil, custom_errors.NewErrValidation("newID", "cannot be empty")
+	}
+	if ok, _ := m.store.Exists(m.changeFile(newID)); ok {
+		return nil, custom_errors.NewErrConflict(fmt.Sprintf("change %s already exists", newID))
+	}
+	src, err := m.ReadChange(sourceID)
+	if _, ok := err.(*custom_errors.ErrNotFound); ok {
+		src, err = m.readArchivedChange(sourceID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Round-trip through JSON for a deep copy of the deltas.
+	data, err := json.Marshal(src.SpecDeltas)
+	if err != nil {
+		return nil, err
+	}
+	var deltas []model.SpecDelta
+	if err := json.Unmarshal(data, &deltas); err != nil {
+		return nil, err
+	}
+	for i := range deltas {
+		d := &deltas[i]
+		for j := range d.Operations {
+			d.Operations[j].AppliedAt = nil
+			d.Operations[j].Base = ""
+		}
+		d.BaseFingerprint = ""
+		if m.specManager == nil {
+			continue
+		}
+		spec, err := m.specManager.ReadSpec(d.Domain)
+		if err != nil {
+			if _, ok := err.(*custom_errors.ErrNotFound); ok {
+				continue
+			}
+			return nil, err
+		}
+		d.BaseFingerprint = spec.Fingerprint
+		SnapshotDeltaBases(spec, d)
+	}
+
+	clone := &model.Change{
+		ID:          newID,
+		Title:       src.Title,
+		Description: src.Description,
+		Status:      "draft",
+		GoalID:      src.GoalID,
+		CreatedAt:   time.Now(),
+		SpecDeltas:  deltas,
+		DependsOn:   append([]string(nil), src.DependsOn...),
+		ClonedFrom:  src.ID,
+	}
+	if err := m.saveChange(clone); err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
+
+// readArchivedChange reads changeID from the archive, or from the manifest
+// of the compacted archive.
+func (m *changeManager) readArchivedChange(changeID string) (*model.Change, error) {
+	b, err := m.store.ReadFile(m.layout.ChangePath(m.layout.ArchivePath(m.baseDir), changeID))
+	if err != nil {
+		if _, ok := err.(*custom_errors.ErrNotFound); ok {
+			compacted, err := readCompactedChanges(m.store, m.layout, m.baseDir)
+			if err != nil {
+				return nil, err
+			}
+			for _, c := range compacted {
+				if c.Change.ID == changeID {
+					ch := c.Change
+					return &ch, nil
+				}
+			}
+			return nil, custom_errors.NewErrNotFound("change", changeID)
+		}
+		return nil, err
+	}
+	var ch model.Change
+	if err := json.Unmarshal(b, &ch); err != nil {
+		return nil, fmt.Errorf("failed to parse archived change '%s': %w", changeID, err)
+	}
+	if ch.ID == "" {
+		ch.ID = changeID
+	}
+	return &ch, nil
+}
+
+// mergeSpecDeltas folds the deltas of several changes into one delta per domain.
+func mergeSpecDeltas(changes []*model.Change) ([]model.SpecDelta, error) {
+	var out []model.SpecDelta
+	index := map[string]int{}
+	for _, ch := range changes {
+		for _, d := range ch.SpecDeltas {
+			i, ok := index[d.Domain]
+			if !ok {
+				index[d.Domain] = len(out)
+				out = append(out, model.SpecDelta{Domain: d.Domain, BaseFingerprint: d.BaseFingerprint})
+				i = len(out) - 1
+			}
+			target := &out[i]
+			if d.BaseFingerprint != "" {
+				if target.BaseFingerprint == "" {
+					target.BaseFingerprint = d.BaseFingerprint
+				} else if target.BaseFingerprint != d.BaseFingerprint {
+					return nil, custom_errors.NewErrConflict(fmt.Sprintf("domain %s has differing base fingerprints (%s vs %s)", d.Domain, target.BaseFingerprint, d.BaseFingerprint))
+				}
+			}
+			for _, op := range d.Operations {
+				dup, err := findDuplicateOperation(target.Operations, op)
+				if err != nil {
+					return nil, custom_errors.NewErrConflict(fmt.Sprintf("domain %s: %s (from change %s)", d.Domain, err.Error(), ch.ID))
+				}
+				if !dup {
+					target.Operations = append(target.Operations, op)
+				}
+			}
+		}
+	}
+	return out, nil
+}
+
+// findDuplicateOperation reports whether op already exists in ops. It returns an
+// error when an operation for the same requirement (or the same scenario of a
+// requirement) exists but differs. APPEND and PATCH edits stack, so they only
+// ever match an identical operation.
+func findDuplicateOperation(ops []model.DeltaOperation, op model.DeltaOperation) (bool, error) {
+	target := operationTarget(op)
+	for _, existing := range ops {
+		if operationTarget(existing) != target {
+			continue
+		}
+		if anchoredOperation(op) || anchoredOperation(existing) {
+			if existing.Type == op.Type && existing.Text == op.Text && existing.Find == op.Find && existing.Replace == op.Replace {
+				return true, nil
+			}
+			continue
+		}
+		if existing.Type == op.Type &&
+			existing.Requirement.Title == op.Requirement.Title &&
+			existing.Requirement.Content == op.Requirement.Content &&
+			reflect.DeepEqual(existing.Scenario, op.Scenario) {
+			return true, nil
+		}
+		return false, fmt.Errorf("conflicting operations for %s (%s vs %s)", target, existing.Type, op.Type)
+	}
+	return false, nil
+}
+
+// operationTarget names what an operation edits: "requirement <id>" or
+// "scenario <requirement-id>/<scenario-id>".
+func operationTarget(op model.DeltaOperation) string {
+	if op.Scenario != nil {
+		return "scenario " + op.Requirement.ID + "/" + scenarioID(op.Scenario)
+	}
+	return "requirement " + op.Requirement.ID
+}
+
+// rewireDependencies replaces references to the keys of replace in every
+// remaining change's DependsOn with the mapped IDs, saving changes that were updated.
+func (m *changeManager) rewireDependencies(replace map[string][]string) error {
+	changes, err := m.ListChanges()
+	if err != nil {
+		return err
+	}
+	for _, ch := range changes {
+		if _, self := replace[ch.ID]; self || len(ch.DependsOn) == 0 {
+			continue
+		}
+		var deps []string
+		seen := map[string]bool{}
+		updated := false
+		for _, dep := range ch.DependsOn {
+			targets, ok := replace[dep]
+			if !ok {
+				targets = []string{dep}
+			} else {
+				updated = true
+			}
+			for _, t := range targets {
+				if t != ch.ID && !seen[t] {
+					seen[t] = true
+					deps = append(deps, t)
+				}
+			}
+		}
+		if !updated {
+			continue
+		}
+		ch.DependsOn = deps
+		if err := m.saveChange(ch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // saveChange writes the change JSON to its canonical path under baseDir/<id>/change.json.
 func (m *changeManager) saveChange(change *model.Change) error {
 	return m.saveChangeToPath(change, m.changeFile(change.ID))
@@ -165,16 +639,35 @@ func (m *changeManager) saveChangeToPath(change *model.Change, path string) erro
 		return custom_errors.NewErrConflict("change cannot be nil")
 	}
 	if change.ID == "" {
-		return custom_errors.NewErrConflict("change.ID cannot be empty")
+		return custom_errors.NewErrValidation("change.ID", "cannot be empty")
 	}
-	// Ensure directory exists
-	if err := fileutil.EnsureParentDir(path, 0o755); err != nil {
+	// Hold the lock from the fingerprint check through the write, so another
+	// process cannot save in between and have its edits overwritten.
+	unlock, err := m.store.Lock(path, 0)
+	if err != nil {
 		return err
 	}
+	defer unlock()
+	// Refuse to clobber edits made by another process since the change was read.
+	if change.LoadedFingerprint != "" {
+		current, err := m.store.ReadFile(path)
+		if _, missing := err.(*custom_errors.ErrNotFound); err != nil && !missing {
+			return err
+		}
+		if err == nil {
+			if fp := utils.GenerateFingerprint(string(current)); fp != change.LoadedFingerprint {
+				return custom_errors.NewErrDiverged(change.ID, change.LoadedFingerprint, fp, "change file was modified since it was read")
+			}
+		}
+	}
 	// If CreatedAt is zero, set it
 	if change.CreatedAt.IsZero() {
 		change.CreatedAt = time.Now()
 	}
+	// Record provenance the first time this binary writes the change
+	if change.CreatedWith == "" {
+		change.CreatedWith = version.CreatedWith()
+	}
 
 	// Marshal with indentation for readability
 	data, err := json.MarshalIndent(change, "", "  ")
@@ -184,17 +677,100 @@ func (m *changeManager) saveChangeToPath(change *model.Change, path string) erro
 	// Ensure newline at EOF
 	data = append(data, '\n')
 
-	return fileutil.WriteFile(path, data, 0o644)
+	if err := m.store.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+	change.LoadedFingerprint = utils.GenerateFingerprint(string(data))
+	return nil
 }
 
+// Save writes the change. Changes obtained from ReadChange or ListChanges carry
+// the fingerprint of the file they were read from; if change.json has been
+// modified since, Save returns ErrDiverged instead of overwriting it.
 func (m *changeManager) Save(change *model.Change) error {
 	return m.saveChange(change)
 }
 
-func (m *changeManager) changeDir(changeID string) string {
-	return filepath.Join(m.baseDir, changeID)
+// CreateChange saves a new draft change under the next free "CH-NNN" ID,
+// numbered after every live and archived change. The ID is allocated and the
+// change written under a lock, so concurrent callers never get the same ID.
+// Deltas without a base fingerprint are pinned to their spec's current one,
+// and operations editing a requirement record its current text as their Base.
+func (m *changeManager) CreateChange(title, goalID string, deltas []model.SpecDelta) (*model.Change, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, custom_errors.NewErrValidation("title", "cannot be empty")
+	}
+	for i := range deltas {
+		d := &deltas[i]
+		if d.BaseFingerprint != "" || m.specManager == nil {
+			continue
+		}
+		spec, err := m.specManager.ReadSpec(d.Domain)
+		if err != nil {
+			if _, ok := err.(*custom_errors.ErrNotFound); ok {
+				continue
+			}
+			return nil, err
+		}
+		d.BaseFingerprint = spec.Fingerprint
+	}
+	if err := snapshotChangeBases(m.specManager, deltas); err != nil {
+		return nil, err
+	}
+
+	unlock, err := m.store.Lock(filepath.Join(m.baseDir, ".create"), 0)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var existing []*model.Change
+	for _, dir := range []string{m.baseDir, m.layout.ArchivePath(m.baseDir)} {
+		ids, err := findTemplateMatches(m.store, dir, m.layout.ChangeFile, changePlaceholder, m.layout.ArchiveDir)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		for _, id := range ids {
+			existing = append(existing, &model.Change{ID: id})
+		}
+	}
+	compacted, err := readCompactedChanges(m.store, m.layout, m.baseDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range compacted {
+		existing = append(existing, &model.Change{ID: c.Change.ID})
+	}
+
+	change := &model.Change{
+		ID:         NextChangeID(existing),
+		Title:      title,
+		Status:     "draft",
+		GoalID:     strings.TrimSpace(goalID),
+		CreatedAt:  time.Now(),
+		SpecDeltas: deltas,
+	}
+	if err := m.saveChange(change); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// Reload replaces change with the current contents of its change.json,
+// discarding unsaved edits and refreshing the loaded fingerprint.
+func (m *changeManager) Reload(change *model.Change) error {
+	if change == nil {
+		return custom_errors.NewErrConflict("change cannot be nil")
+	}
+	fresh, err := m.ReadChange(change.ID)
+	if err != nil {
+		return err
+	}
+	*change = *fresh
+	return nil
 }
 
 func (m *changeManager) changeFile(changeID string) string {
-	return filepath.Join(m.changeDir(changeID), "change.json")
+	return m.layout.ChangePath(m.baseDir, changeID)
 }
@@ -3,26 +3,131 @@ package core
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"net"
 	"os"
 	"path/filepath"
+	"sync"
+	"syscall"
 	"testing"
 	"time"
 
 	ce "github.com/teamwerx/teamwerx/internal/errors"
 	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+	"github.com/teamwerx/teamwerx/internal/version"
 )
 
+func TestChangeManager_Save_DetectsConcurrentEdit(t *testing.T) {
+	baseDir := createTempDir(t)
+	cm := NewChangeManager(baseDir, nil, nil)
+	writeChangeJSON(t, baseDir, "CH-011", map[string]any{"id": "CH-011", "title": "Original", "status": "draft"})
+
+	mine, err := cm.ReadChange("CH-011")
+	if err != nil {
+		t.Fatalf("ReadChange failed: %v", err)
+	}
+	if mine.LoadedFingerprint == "" {
+		t.Fatalf("expected loaded fingerprint to be set")
+	}
+
+	// A reviewer edits the file after we read it.
+	theirs, err := cm.ReadChange("CH-011")
+	if err != nil {
+		t.Fatalf("ReadChange failed: %v", err)
+	}
+	theirs.Status = "submitted"
+	if err := cm.Save(theirs); err != nil {
+		t.Fatalf("Save (theirs) failed: %v", err)
+	}
+
+	mine.Title = "Retitled"
+	err = cm.Save(mine)
+	var div *ce.ErrDiverged
+	if !errors.As(err, &div) {
+		t.Fatalf("expected ErrDiverged, got %v", err)
+	}
+	if div.Domain != "CH-011" {
+		t.Fatalf("unexpected diverged resource: %q", div.Domain)
+	}
+
+	// Reload picks up their edit; reapplying ours then saves cleanly.
+	if err := cm.Reload(mine); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if mine.Status != "submitted" || mine.Title != "Original" {
+		t.Fatalf("expected reloaded contents, got %+v", mine)
+	}
+	mine.Title = "Retitled"
+	if err := cm.Save(mine); err != nil {
+		t.Fatalf("Save after reload failed: %v", err)
+	}
+	// Consecutive saves from the same copy keep working.
+	if err := cm.Save(mine); err != nil {
+		t.Fatalf("second Save failed: %v", err)
+	}
+	got, _ := cm.ReadChange("CH-011")
+	if got.Title != "Retitled" || got.Status != "submitted" {
+		t.Fatalf("unexpected persisted change: %+v", got)
+	}
+}
+
 // fakeSpecMerger is a test double for SpecMerger that records merge calls.
 type fakeSpecMerger struct {
 	merges []*model.SpecDelta
 	err    error
 }
 
+func TestChangeManager_Save_ConcurrentSavesDoNotOverwrite(t *testing.T) {
+	baseDir := createTempDir(t)
+	cm := NewChangeManager(baseDir, nil, nil)
+	writeChangeJSON(t, baseDir, "CH-012", map[string]any{"id": "CH-012", "title": "Original", "status": "draft"})
+
+	// Every writer read the same version, so exactly one save may land.
+	const writers = 8
+	var changes []*model.Change
+	for i := 0; i < writers; i++ {
+		ch, err := cm.ReadChange("CH-012")
+		if err != nil {
+			t.Fatalf("ReadChange failed: %v", err)
+		}
+		ch.Title = fmt.Sprintf("Writer %d", i)
+		changes = append(changes, ch)
+	}
+	errs := make([]error, writers)
+	var wg sync.WaitGroup
+	for i, ch := range changes {
+		wg.Add(1)
+		go func(i int, ch *model.Change) {
+			defer wg.Done()
+			errs[i] = cm.Save(ch)
+		}(i, ch)
+	}
+	wg.Wait()
+
+	saved := 0
+	for _, err := range errs {
+		var div *ce.ErrDiverged
+		switch {
+		case err == nil:
+			saved++
+		case !errors.As(err, &div):
+			t.Fatalf("expected ErrDiverged, got %v", err)
+		}
+	}
+	if saved != 1 {
+		t.Fatalf("expected exactly one save to succeed, got %d", saved)
+	}
+}
 func (f *fakeSpecMerger) Merge(delta *model.SpecDelta) error {
 	f.merges = append(f.merges, delta)
 	return f.err
 }
 
+func (f *fakeSpecMerger) MergeWithMarkers(delta *model.SpecDelta, label string) ([]model.MergeConflict, error) {
+	return nil, f.Merge(delta)
+}
+
 // writeChangeJSON writes raw JSON into <baseDir>/<changeID>/change.json creating directories.
 func writeChangeJSON(t *testing.T, baseDir, changeID string, raw map[string]any) string {
 	t.Helper()
@@ -110,6 +215,63 @@ func TestChangeManager_ListChanges_FiltersInvalid(t *testing.T) {
 	}
 }
 
+func TestChangeManager_ListChangesFiltered(t *testing.T) {
+	baseDir := createTempDir(t)
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	authDelta := []map[string]any{{"domain": "auth", "operations": []any{}}}
+	writeChangeJSON(t, baseDir, "CH-001", map[string]any{"title": "Zeta", "status": "draft", "goal_id": "001-auth", "created_at": now.AddDate(0, 0, -30), "spec_deltas": authDelta})
+	writeChangeJSON(t, baseDir, "CH-002", map[string]any{"title": "Alpha", "status": "draft", "goal_id": "001-auth", "created_at": now.AddDate(0, 0, -2)})
+	writeChangeJSON(t, baseDir, "CH-003", map[string]any{"title": "Mid", "status": "applied", "goal_id": "002-billing", "created_at": now.AddDate(0, 0, -1), "spec_deltas": authDelta, "author": "review-bot", "provenance": map[string]any{"tool": "review-bot", "model": "m-1"}})
+	cm := NewChangeManager(baseDir, nil, nil)
+
+	since, err := ParseSince("7d", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name   string
+		filter ChangeFilter
+		want   []string
+	}{
+		{"status", ChangeFilter{Status: "DRAFT"}, []string{"CH-001", "CH-002"}},
+		{"goal", ChangeFilter{GoalID: "002-billing"}, []string{"CH-003"}},
+		{"domain", ChangeFilter{Domain: "auth"}, []string{"CH-001", "CH-003"}},
+		{"since", ChangeFilter{Since: since}, []string{"CH-002", "CH-003"}},
+		{"created by", ChangeFilter{CreatedBy: "Review-Bot"}, []string{"CH-003"}},
+		{"combined and sorted", ChangeFilter{Status: "draft", Sort: "title"}, []string{"CH-002", "CH-001"}},
+		{"created desc", ChangeFilter{Sort: "created", Order: SortDesc}, []string{"CH-003", "CH-002", "CH-001"}},
+		{"no match", ChangeFilter{Status: "archived"}, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			changes, err := cm.ListChangesFiltered(tc.filter)
+			if err != nil {
+				t.Fatalf("ListChangesFiltered failed: %v", err)
+			}
+			assertChangeOrder(t, changes, tc.want...)
+		})
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	cases := map[string]time.Time{
+		"2025-03-01":           time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		"2025-03-01T08:00:00Z": time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC),
+		"3d":                   now.AddDate(0, 0, -3),
+		"36h":                  now.Add(-36 * time.Hour),
+	}
+	for in, want := range cases {
+		got, err := ParseSince(in, now)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("ParseSince(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseSince("last week", now); err == nil {
+		t.Errorf("expected an error for an unparseable value")
+	}
+}
+
 func TestChangeManager_ApplyChange_Success_WritesAndMerges(t *testing.T) {
 	baseDir := createTempDir(t)
 
@@ -236,6 +398,159 @@ func TestChangeManager_ArchiveChange_Success_MovesDirectory(t *testing.T) {
 	}
 }
 
+// crossDeviceStorage fails every rename as a move across file systems does.
+type crossDeviceStorage struct {
+	storage.Storage
+}
+
+func (s crossDeviceStorage) Rename(src, dst string) error {
+	return &os.LinkError{Op: "rename", Old: src, New: dst, Err: syscall.EXDEV}
+}
+
+func TestChangeManager_ArchiveChange_CrossDevice_CopiesArtifacts(t *testing.T) {
+	baseDir := createTempDir(t)
+	cm := newChangeManager(baseDir, DefaultLayout(), nil, nil, crossDeviceStorage{storage.OS()}, nil)
+	writeChangeJSON(t, baseDir, "CH-xdev", map[string]any{"id": "CH-xdev", "title": "Across devices", "status": "applied"})
+
+	dir := filepath.Join(baseDir, "CH-xdev")
+	if err := os.MkdirAll(filepath.Join(dir, "notes"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes", "review.md"), []byte("LGTM\n"), 0o600); err != nil {
+		t.Fatalf("write review: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "migrate.sh"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatalf("write script: %v", err)
+	}
+	if err := os.Symlink(filepath.Join("notes", "review.md"), filepath.Join(dir, "review")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	ch, err := cm.ReadChange("CH-xdev")
+	if err != nil {
+		t.Fatalf("ReadChange: %v", err)
+	}
+	if err := cm.ArchiveChange(ch); err != nil {
+		t.Fatalf("ArchiveChange: %v", err)
+	}
+
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("expected original dir removed, stat err: %v", err)
+	}
+	archived := filepath.Join(baseDir, ".archive", "CH-xdev")
+	if data, err := os.ReadFile(filepath.Join(archived, "notes", "review.md")); err != nil || string(data) != "LGTM\n" {
+		t.Fatalf("nested artifact = %q, %v", data, err)
+	}
+	for name, want := range map[string]os.FileMode{"notes/review.md": 0o600, "migrate.sh": 0o755} {
+		info, err := os.Stat(filepath.Join(archived, name))
+		if err != nil {
+			t.Fatalf("stat %s: %v", name, err)
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Fatalf("%s mode = %v, want %v", name, got, want)
+		}
+	}
+	if target, err := os.Readlink(filepath.Join(archived, "review")); err != nil || target != filepath.Join("notes", "review.md") {
+		t.Fatalf("symlink target = %q, %v", target, err)
+	}
+	data, err := os.ReadFile(filepath.Join(archived, "change.json"))
+	if err != nil {
+		t.Fatalf("read archived change: %v", err)
+	}
+	var saved model.Change
+	if err := json.Unmarshal(data, &saved); err != nil || saved.Status != "archived" {
+		t.Fatalf("archived change status = %q, %v", saved.Status, err)
+	}
+}
+
+func TestChangeManager_ArchiveChange_CrossDevice_FailedCopyCanBeRetried(t *testing.T) {
+	baseDir := createTempDir(t)
+	cm := newChangeManager(baseDir, DefaultLayout(), nil, nil, crossDeviceStorage{storage.OS()}, nil)
+	writeChangeJSON(t, baseDir, "CH-xdev", map[string]any{"id": "CH-xdev", "title": "Across devices", "status": "applied"})
+
+	// A socket cannot be copied, so the copy fails after change.json is in place.
+	sock := filepath.Join(baseDir, "CH-xdev", "zz.sock")
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	ch, err := cm.ReadChange("CH-xdev")
+	if err != nil {
+		t.Fatalf("ReadChange: %v", err)
+	}
+	if err := cm.ArchiveChange(ch); err == nil {
+		t.Fatal("expected copying a socket to fail")
+	}
+	archived := filepath.Join(baseDir, ".archive", "CH-xdev")
+	if _, err := os.Stat(archived); !os.IsNotExist(err) {
+		t.Fatalf("expected no partial archive, stat err: %v", err)
+	}
+
+	_ = ln.Close()
+	_ = os.Remove(sock)
+	if err := cm.ArchiveChange(ch); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(archived, "change.json")); err != nil {
+		t.Fatalf("expected the retry to archive the change: %v", err)
+	}
+}
+
+func TestChangeManager_CreateChange_AllocatesUniqueIDs(t *testing.T) {
+	baseDir := createTempDir(t)
+	specsDir := createTempDir(t)
+	writeSpecFile(t, specsDir, "auth", "# Auth\n\n### Requirement: Login\n\nUsers log in.\n")
+	specs := NewSpecManager(specsDir)
+	cm := NewChangeManager(baseDir, specs, nil)
+
+	archived := &model.Change{ID: "CH-004", Title: "Old"}
+	if err := cm.Save(archived); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := cm.ArchiveChange(archived); err != nil {
+		t.Fatalf("ArchiveChange: %v", err)
+	}
+
+	ch, err := cm.CreateChange(" Session timeout ", "001-auth", []model.SpecDelta{{Domain: "auth"}})
+	if err != nil {
+		t.Fatalf("CreateChange: %v", err)
+	}
+	spec, _ := specs.ReadSpec("auth")
+	if ch.ID != "CH-005" || ch.Title != "Session timeout" || ch.Status != "draft" || ch.CreatedAt.IsZero() || ch.SpecDeltas[0].BaseFingerprint != spec.Fingerprint {
+		t.Fatalf("unexpected change: %+v", ch)
+	}
+	if _, err := cm.ReadChange("CH-005"); err != nil {
+		t.Fatalf("expected the change persisted: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	ids := make([]string, 5)
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ch, err := cm.CreateChange("Concurrent", "", nil)
+			if err != nil {
+				t.Errorf("CreateChange: %v", err)
+				return
+			}
+			ids[i] = ch.ID
+		}(i)
+	}
+	wg.Wait()
+	seen := map[string]bool{}
+	for _, id := range ids {
+		if seen[id] {
+			t.Fatalf("ID %s allocated twice: %v", id, ids)
+		}
+		seen[id] = true
+	}
+
+	if _, err := cm.CreateChange("  ", "", nil); err == nil {
+		t.Fatalf("expected an empty title to be rejected")
+	}
+}
+
 func TestChangeManager_InputValidationErrors(t *testing.T) {
 	baseDir := createTempDir(t)
 	cm := NewChangeManager(baseDir, nil, nil)
@@ -244,9 +559,9 @@ func TestChangeManager_InputValidationErrors(t *testing.T) {
 	if _, err := cm.ReadChange(""); err == nil {
 		t.Fatal("expected error for empty change ID")
 	} else {
-		var conf *ce.ErrConflict
-		if !errors.As(err, &conf) {
-			t.Fatalf("expected ErrConflict, got %T: %v", err, err)
+		var verr *ce.ErrValidation
+		if !errors.As(err, &verr) {
+			t.Fatalf("expected ErrValidation, got %T: %v", err, err)
 		}
 	}
 
@@ -264,9 +579,9 @@ func TestChangeManager_InputValidationErrors(t *testing.T) {
 	if err := cm.ApplyChange(&model.Change{}); err == nil {
 		t.Fatal("expected error for empty change ID")
 	} else {
-		var conf *ce.ErrConflict
-		if !errors.As(err, &conf) {
-			t.Fatalf("expected ErrConflict, got %T: %v", err, err)
+		var verr *ce.ErrValidation
+		if !errors.As(err, &verr) {
+			t.Fatalf("expected ErrValidation, got %T: %v", err, err)
 		}
 	}
 
@@ -283,10 +598,198 @@ func TestChangeManager_InputValidationErrors(t *testing.T) {
 	// ArchiveChange empty ID
 	if err := cm.ArchiveChange(&model.Change{}); err == nil {
 		t.Fatal("expected error for empty change ID")
+	} else {
+		var verr *ce.ErrValidation
+		if !errors.As(err, &verr) {
+			t.Fatalf("expected ErrValidation, got %T: %v", err, err)
+		}
+	}
+}
+
+func TestChangeManager_SplitChange_ByDomain(t *testing.T) {
+	baseDir := createTempDir(t)
+	cm := NewChangeManager(baseDir, nil, nil)
+
+	ch := &model.Change{
+		ID:        "CH-001",
+		Title:     "Big change",
+		Status:    "draft",
+		DependsOn: []string{"CH-000"},
+		SpecDeltas: []model.SpecDelta{
+			{Domain: "auth", Operations: []model.DeltaOperation{{Type: "ADDED", Requirement: model.Requirement{ID: "login"}}}},
+			{Domain: "billing", Operations: []model.DeltaOperation{{Type: "ADDED", Requirement: model.Requirement{ID: "invoice"}}}},
+		},
+	}
+	if err := cm.Save(ch); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	dependent := &model.Change{ID: "CH-002", Title: "After big change", DependsOn: []string{"CH-001"}}
+	if err := cm.Save(dependent); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	parts, err := cm.SplitChange(ch)
+	if err != nil {
+		t.Fatalf("SplitChange failed: %v", err)
+	}
+	if len(parts) != 2 || parts[0].ID != "CH-001-auth" || parts[1].ID != "CH-001-billing" {
+		t.Fatalf("unexpected parts: %+v", parts)
+	}
+	if len(parts[0].DependsOn) != 1 || parts[0].DependsOn[0] != "CH-000" {
+		t.Fatalf("expected dependencies to be copied, got %v", parts[0].DependsOn)
+	}
+
+	if _, err := os.Stat(filepath.Join(baseDir, ".archive", "CH-001", "change.json")); err != nil {
+		t.Fatalf("expected original to be archived: %v", err)
+	}
+	got, err := cm.ReadChange("CH-002")
+	if err != nil {
+		t.Fatalf("ReadChange failed: %v", err)
+	}
+	if len(got.DependsOn) != 2 || got.DependsOn[0] != "CH-001-auth" || got.DependsOn[1] != "CH-001-billing" {
+		t.Fatalf("expected dependent to be rewired, got %v", got.DependsOn)
+	}
+}
+
+func TestChangeManager_MergeChanges_DedupesAndDetectsConflicts(t *testing.T) {
+	baseDir := createTempDir(t)
+	cm := NewChangeManager(baseDir, nil, nil)
+
+	login := model.DeltaOperation{Type: "ADDED", Requirement: model.Requirement{ID: "login", Title: "Login"}}
+	for _, ch := range []*model.Change{
+		{ID: "CH-002", SpecDeltas: []model.SpecDelta{{Domain: "auth", Operations: []model.DeltaOperation{login}}}},
+		{ID: "CH-003", SpecDeltas: []model.SpecDelta{
+			{Domain: "auth", Operations: []model.DeltaOperation{login, {Type: "ADDED", Requirement: model.Requirement{ID: "logout"}}}},
+			{Domain: "billing", Operations: []model.DeltaOperation{{Type: "REMOVED", Requirement: model.Requirement{ID: "invoice"}}}},
+		}},
+		{ID: "CH-004", SpecDeltas: []model.SpecDelta{{Domain: "auth", Operations: []model.DeltaOperation{{Type: "REMOVED", Requirement: model.Requirement{ID: "login"}}}}}},
+	} {
+		if err := cm.Save(ch); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	// Conflicting operations on the same requirement are rejected.
+	if _, err := cm.MergeChanges([]string{"CH-002", "CH-004"}, "CH-bad", ""); err == nil {
+		t.Fatal("expected conflict merging CH-002 and CH-004")
 	} else {
 		var conf *ce.ErrConflict
 		if !errors.As(err, &conf) {
 			t.Fatalf("expected ErrConflict, got %T: %v", err, err)
 		}
 	}
+
+	merged, err := cm.MergeChanges([]string{"CH-002", "CH-003"}, "CH-010", "")
+	if err != nil {
+		t.Fatalf("MergeChanges failed: %v", err)
+	}
+	if len(merged.SpecDeltas) != 2 {
+		t.Fatalf("expected 2 domains, got %+v", merged.SpecDeltas)
+	}
+	if ops := merged.SpecDeltas[0].Operations; len(ops) != 2 {
+		t.Fatalf("expected duplicate login op to be removed, got %+v", ops)
+	}
+	if _, err := cm.ReadChange("CH-002"); err == nil {
+		t.Fatal("expected source change to be archived")
+	}
+}
+
+func TestChangeManager_CloneChange_FromArchive(t *testing.T) {
+	specsDir := createTempDir(t)
+	writeSpecFile(t, specsDir, "auth", "# Auth\n\n### Requirement: Login\n\nUsers log in.\n")
+	specs := NewSpecManager(specsDir)
+	baseDir := createTempDir(t)
+	cm := NewChangeManager(baseDir, specs, nil)
+
+	applied := time.Now()
+	src := &model.Change{
+		ID:        "CH-001",
+		Title:     "Passkeys",
+		Status:    "rejected",
+		DependsOn: []string{"CH-000"},
+		Approvals: []model.Approval{{Approver: "alice", Domain: "auth"}},
+		SpecDeltas: []model.SpecDelta{{
+			Domain:          "auth",
+			BaseFingerprint: "stale",
+			Operations:      []model.DeltaOperation{{Type: "APPEND", Requirement: model.Requirement{ID: "login"}, Text: "- Passkeys.", AppliedAt: &applied}},
+		}},
+	}
+	if err := cm.Save(src); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := cm.ArchiveChange(src); err != nil {
+		t.Fatalf("ArchiveChange failed: %v", err)
+	}
+
+	clone, err := cm.CloneChange("CH-001", "CH-014")
+	if err != nil {
+		t.Fatalf("CloneChange failed: %v", err)
+	}
+	spec, err := specs.ReadSpec("auth")
+	if err != nil {
+		t.Fatalf("ReadSpec failed: %v", err)
+	}
+	if clone.Status != "draft" || clone.ClonedFrom != "CH-001" || len(clone.Approvals) != 0 {
+		t.Fatalf("unexpected clone: %+v", clone)
+	}
+	if d := clone.SpecDeltas[0]; d.BaseFingerprint != spec.Fingerprint || d.Operations[0].AppliedAt != nil {
+		t.Fatalf("expected refreshed, unapplied delta, got %+v", d)
+	}
+	if src.SpecDeltas[0].Operations[0].AppliedAt == nil {
+		t.Fatal("expected the source deltas to be left alone")
+	}
+	if _, err := cm.ReadChange("CH-014"); err != nil {
+		t.Fatalf("expected clone to be saved: %v", err)
+	}
+
+	var conf *ce.ErrConflict
+	if _, err := cm.CloneChange("CH-001", "CH-014"); !errors.As(err, &conf) {
+		t.Fatalf("expected ErrConflict for an existing ID, got %v", err)
+	}
+	var nf *ce.ErrNotFound
+	if _, err := cm.CloneChange("CH-099", "CH-015"); !errors.As(err, &nf) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestMergeSpecDeltas_StacksAppendAndPatch(t *testing.T) {
+	first := model.DeltaOperation{Type: "APPEND", Requirement: model.Requirement{ID: "login"}, Text: "- Rate limited."}
+	second := model.DeltaOperation{Type: "APPEND", Requirement: model.Requirement{ID: "login"}, Text: "- Audited."}
+	patch := model.DeltaOperation{Type: "PATCH", Requirement: model.Requirement{ID: "login"}, Find: "password", Replace: "passkey"}
+	deltas, err := mergeSpecDeltas([]*model.Change{
+		{ID: "CH-001", SpecDeltas: []model.SpecDelta{{Domain: "auth", Operations: []model.DeltaOperation{first, patch}}}},
+		{ID: "CH-002", SpecDeltas: []model.SpecDelta{{Domain: "auth", Operations: []model.DeltaOperation{first, second}}}},
+	})
+	if err != nil {
+		t.Fatalf("mergeSpecDeltas failed: %v", err)
+	}
+	if ops := deltas[0].Operations; len(ops) != 3 || ops[2].Text != "- Audited." {
+		t.Fatalf("expected distinct APPENDs to stack and the repeat to be dropped, got %+v", ops)
+	}
+}
+
+func TestChangeManager_Save_RecordsCreatedWith(t *testing.T) {
+	baseDir := createTempDir(t)
+	cm := NewChangeManager(baseDir, nil, nil)
+
+	ch := &model.Change{ID: "CH-010", Title: "Provenance"}
+	if err := cm.Save(ch); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if ch.CreatedWith != version.CreatedWith() {
+		t.Fatalf("expected created_with %q, got %q", version.CreatedWith(), ch.CreatedWith)
+	}
+
+	// An existing provenance is never overwritten.
+	ch.CreatedWith = "teamwerx/v0.1.0"
+	if err := cm.Save(ch); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := cm.ReadChange("CH-010")
+	if err != nil {
+		t.Fatalf("ReadChange failed: %v", err)
+	}
+	if loaded.CreatedWith != "teamwerx/v0.1.0" {
+		t.Fatalf("expected created_with to be preserved, got %q", loaded.CreatedWith)
+	}
 }
@@ -0,0 +1,184 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/utils"
+)
+
+// Built-in change templates.
+const (
+	ChangeTemplateAddRequirement       = "add-requirement"
+	ChangeTemplateTightenRequirement   = "tighten-requirement"
+	ChangeTemplateDeprecateRequirement = "deprecate-requirement"
+	ChangeTemplateSplitRequirement     = "split-requirement"
+)
+
+// ChangeTemplates lists the templates accepted by NewChangeFromTemplate.
+var ChangeTemplates = []string{
+	ChangeTemplateAddRequirement,
+	ChangeTemplateTightenRequirement,
+	ChangeTemplateDeprecateRequirement,
+	ChangeTemplateSplitRequirement,
+}
+
+// ChangeTemplateOptions parameterizes NewChangeFromTemplate.
+type ChangeTemplateOptions struct {
+	ID     string // change ID; required
+	GoalID string
+	Domain string // spec domain the delta targets; required
+	// Title is the new requirement's title for add-requirement, and an optional
+	// change title override for the other templates.
+	Title string
+	// RequirementID names the existing requirement for tighten, deprecate and split.
+	RequirementID string
+	// SplitInto holds the titles of the requirements a split produces (at least two).
+	SplitInto []string
+}
+
+// NewChangeFromTemplate builds a draft change whose delta is pre-populated for a
+// common operation:
+//   - add-requirement: ADDED requirement with a placeholder SHALL statement.
+//   - tighten-requirement: MODIFIED requirement seeded with its current text, ready to edit.
+//   - deprecate-requirement: MODIFIED requirement with a deprecation notice under the heading.
+//   - split-requirement: REMOVED requirement plus one ADDED requirement per SplitInto
+//     title, each seeded with the original body.
+//
+// Templates that edit an existing requirement pin the delta to the spec's
+// current fingerprint. The change is not saved.
+func NewChangeFromTemplate(specs SpecManager, template string, opts ChangeTemplateOptions) (*model.Change, error) {
+	opts.Domain = strings.TrimSpace(opts.Domain)
+	opts.Title = strings.TrimSpace(opts.Title)
+	if opts.ID == "" {
+		return nil, custom_errors.NewErrValidation("changeID", "cannot be empty")
+	}
+	if opts.Domain == "" {
+		return nil, custom_errors.NewErrValidation("domain", "cannot be empty")
+	}
+
+	ch := &model.Change{
+		ID:        opts.ID,
+		Status:    "draft",
+		GoalID:    opts.GoalID,
+		CreatedAt: time.Now(),
+	}
+	delta := model.SpecDelta{Domain: opts.Domain}
+	format := specParser(specs).Format()
+
+	if template == ChangeTemplateAddRequirement {
+		if opts.Title == "" {
+			return nil, custom_errors.NewErrValidation("title", "add-requirement needs a requirement title")
+		}
+		delta.Operations = []model.DeltaOperation{{
+			Type:        "ADDED",
+			Requirement: newTemplateRequirement(format, opts.Title, "The system SHALL ...\n"),
+		}}
+		ch.Title = fmt.Sprintf("Add %s requirement: %s", opts.Domain, opts.Title)
+		ch.SpecDeltas = []model.SpecDelta{delta}
+		return ch, nil
+	}
+
+	spec, err := specs.ReadSpec(opts.Domain)
+	if err != nil {
+		return nil, err
+	}
+	var target *model.Requirement
+	for i := range spec.Requirements {
+		if spec.Requirements[i].ID == opts.RequirementID {
+			target = &spec.Requirements[i]
+			break
+		}
+	}
+	if target == nil {
+		return nil, custom_errors.NewErrNotFound("requirement", opts.RequirementID)
+	}
+	delta.BaseFingerprint = spec.Fingerprint
+	current := spec.Content[target.Start:target.End]
+
+	switch template {
+	case ChangeTemplateTightenRequirement:
+		delta.Operations = []model.DeltaOperation{{
+			Type:        "MODIFIED",
+			Requirement: model.Requirement{ID: target.ID, Title: target.Title, Content: current},
+		}}
+		ch.Title = fmt.Sprintf("Tighten %s requirement: %s", opts.Domain, target.Title)
+
+	case ChangeTemplateDeprecateRequirement:
+		notice := fmt.Sprintf("> **Deprecated** by change %s. Describe the replacement and removal timeline here.\n\n", opts.ID)
+		delta.Operations = []model.DeltaOperation{{
+			Type:        "MODIFIED",
+			Requirement: model.Requirement{ID: target.ID, Title: target.Title, Content: insertAfterHeading(current, notice)},
+		}}
+		ch.Title = fmt.Sprintf("Deprecate %s requirement: %s", opts.Domain, target.Title)
+
+	case ChangeTemplateSplitRequirement:
+		if len(opts.SplitInto) < 2 {
+			return nil, custom_errors.NewErrValidation("into", "split-requirement needs at least two titles to split into")
+		}
+		body := strings.TrimLeft(target.Content, "\n")
+		delta.Operations = []model.DeltaOperation{{
+			Type:        "REMOVED",
+			Requirement: model.Requirement{ID: target.ID, Title: target.Title},
+		}}
+		for _, title := range opts.SplitInto {
+			delta.Operations = append(delta.Operations, model.DeltaOperation{
+				Type:        "ADDED",
+				Requirement: newTemplateRequirement(format, strings.TrimSpace(title), body),
+			})
+		}
+		ch.Title = fmt.Sprintf("Split %s requirement: %s", opts.Domain, target.Title)
+
+	default:
+		return nil, custom_errors.NewErrValidation("template", fmt.Sprintf("unknown change template %q (expected %s)", template, strings.Join(ChangeTemplates, ", ")))
+	}
+
+	if opts.Title != "" {
+		ch.Title = opts.Title
+	}
+	SnapshotDeltaBases(spec, &delta)
+	ch.SpecDeltas = []model.SpecDelta{delta}
+	return ch, nil
+}
+
+// newTemplateRequirement builds a requirement whose Content is a full block,
+// headed as format says.
+func newTemplateRequirement(format SpecFormat, title, body string) model.Requirement {
+	return model.Requirement{
+		ID:      utils.ToKebabCase(title),
+		Title:   title,
+		Content: format.RequirementHeading(title) + "\n\n" + body,
+	}
+}
+
+// insertAfterHeading inserts text after a block's heading line and the blank line that follows it.
+func insertAfterHeading(block, text string) string {
+	idx := strings.Index(block, "\n")
+	if idx == -1 {
+		return block + "\n\n" + text
+	}
+	head, rest := block[:idx+1], block[idx+1:]
+	trimmed := strings.TrimLeft(rest, "\n")
+	return head + "\n" + text + trimmed
+}
+
+// changeIDPattern matches generated change IDs ("CH-001").
+var changeIDPattern = regexp.MustCompile(`^CH-(\d+)$`)
+
+// NextChangeID returns the next "CH-NNN" ID after the highest numbered change.
+func NextChangeID(changes []*model.Change) string {
+	max := 0
+	for _, ch := range changes {
+		if m := changeIDPattern.FindStringSubmatch(ch.ID); m != nil {
+			if n, err := strconv.Atoi(m[1]); err == nil && n > max {
+				max = n
+			}
+		}
+	}
+	return fmt.Sprintf("CH-%03d", max+1)
+}
@@ -0,0 +1,98 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+func TestNewChangeFromTemplate_BuildsValidDeltas(t *testing.T) {
+	baseDir := createTempDir(t)
+	writeSpecFile(t, baseDir, "auth", "# Auth\n\n### Requirement: Login\n\nUsers log in.\n\n### Requirement: Logout\n\nBye.\n")
+	specs := NewSpecManager(baseDir)
+
+	cases := []struct {
+		template string
+		opts     ChangeTemplateOptions
+		ops      []string
+	}{
+		{ChangeTemplateAddRequirement, ChangeTemplateOptions{Title: "Session timeout"}, []string{"ADDED session-timeout"}},
+		{ChangeTemplateTightenRequirement, ChangeTemplateOptions{RequirementID: "login"}, []string{"MODIFIED login"}},
+		{ChangeTemplateDeprecateRequirement, ChangeTemplateOptions{RequirementID: "logout"}, []string{"MODIFIED logout"}},
+		{ChangeTemplateSplitRequirement, ChangeTemplateOptions{RequirementID: "login", SplitInto: []string{"Password login", "SSO login"}}, []string{"REMOVED login", "ADDED password-login", "ADDED sso-login"}},
+	}
+	for _, tc := range cases {
+		tc.opts.ID, tc.opts.Domain = "CH-001", "auth"
+		ch, err := NewChangeFromTemplate(specs, tc.template, tc.opts)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.template, err)
+		}
+		if ch.Status != "draft" || len(ch.SpecDeltas) != 1 {
+			t.Fatalf("%s: unexpected change %+v", tc.template, ch)
+		}
+		var ops []string
+		for _, op := range ch.SpecDeltas[0].Operations {
+			ops = append(ops, op.Type+" "+op.Requirement.ID)
+		}
+		if strings.Join(ops, ",") != strings.Join(tc.ops, ",") {
+			t.Fatalf("%s: expected ops %v, got %v", tc.template, tc.ops, ops)
+		}
+		if err := ValidateChangeDeltas(specs, ch); err != nil {
+			t.Fatalf("%s: template produced invalid change: %v", tc.template, err)
+		}
+	}
+}
+
+func TestNewChangeFromTemplate_DeprecateKeepsIDAndAddsNotice(t *testing.T) {
+	baseDir := createTempDir(t)
+	writeSpecFile(t, baseDir, "auth", "# Auth\n\n### Requirement: Logout\n\nBye.\n")
+	specs := NewSpecManager(baseDir)
+
+	ch, err := NewChangeFromTemplate(specs, ChangeTemplateDeprecateRequirement, ChangeTemplateOptions{ID: "CH-002", Domain: "auth", RequirementID: "logout"})
+	if err != nil {
+		t.Fatalf("NewChangeFromTemplate failed: %v", err)
+	}
+	if err := NewSpecMerger(specs).Merge(&ch.SpecDeltas[0]); err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	spec, _ := specs.ReadSpec("auth")
+	if len(spec.Requirements) != 1 || spec.Requirements[0].ID != "logout" {
+		t.Fatalf("expected logout to keep its ID, got %+v", spec.Requirements)
+	}
+	if !strings.Contains(spec.Content, "> **Deprecated** by change CH-002.") || !strings.Contains(spec.Content, "Bye.") {
+		t.Fatalf("unexpected content:\n%s", spec.Content)
+	}
+}
+
+func TestNewChangeFromTemplate_Errors(t *testing.T) {
+	baseDir := createTempDir(t)
+	writeSpecFile(t, baseDir, "auth", "# Auth\n\n### Requirement: Login\n\nBody.\n")
+	specs := NewSpecManager(baseDir)
+
+	bad := []struct {
+		template string
+		opts     ChangeTemplateOptions
+	}{
+		{"rename-requirement", ChangeTemplateOptions{RequirementID: "login"}},
+		{ChangeTemplateAddRequirement, ChangeTemplateOptions{}},
+		{ChangeTemplateTightenRequirement, ChangeTemplateOptions{RequirementID: "ghost"}},
+		{ChangeTemplateSplitRequirement, ChangeTemplateOptions{RequirementID: "login", SplitInto: []string{"Only one"}}},
+	}
+	for _, tc := range bad {
+		tc.opts.ID, tc.opts.Domain = "CH-001", "auth"
+		if _, err := NewChangeFromTemplate(specs, tc.template, tc.opts); err == nil {
+			t.Errorf("%s: expected error", tc.template)
+		}
+	}
+}
+
+func TestNextChangeID(t *testing.T) {
+	if got := NextChangeID(nil); got != "CH-001" {
+		t.Fatalf("expected CH-001, got %s", got)
+	}
+	changes := []*model.Change{{ID: "CH-009"}, {ID: "CH-010-auth"}, {ID: "CH-002"}}
+	if got := NextChangeID(changes); got != "CH-010" {
+		t.Fatalf("expected CH-010, got %s", got)
+	}
+}
@@ -0,0 +1,251 @@
+package core
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// maxCharterDepth bounds a chain of charters extending one another.
+const maxCharterDepth = 8
+
+// fetchCharterURL downloads a charter an "extends:" URL points to. Tests
+// replace it.
+var fetchCharterURL = func(url string) ([]byte, error) {
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// charterCacheDir holds the last fetched copy of each "extends:" URL, under
+// the .local directory.
+const charterCacheDir = "charters"
+
+// charterCachePath is where the last fetched copy of url is kept.
+func (m *charterManager) charterCachePath(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(m.baseDir, LocalDirName, charterCacheDir, fmt.Sprintf("%x.md", sum[:8]))
+}
+
+// fetchCharter downloads the charter at url and caches it. When the download
+// fails and a cached copy exists, the copy is returned and onCachedCharter is
+// told, so an unreachable organization URL does not stop work offline.
+func (m *charterManager) fetchCharter(url string) ([]byte, error) {
+	cache := m.charterCachePath(url)
+	data, err := fetchCharterURL(url)
+	if err == nil {
+		// The cache is a convenience: failing to update it (e.g. read-only
+		// mode) does not fail the read.
+		if ensureLocalDir(m.store, m.baseDir) == nil {
+			_ = m.store.WriteFile(cache, data, 0o644)
+		}
+		return data, nil
+	}
+	cached, cerr := m.store.ReadFile(cache)
+	if cerr != nil {
+		return nil, err
+	}
+	if m.onCachedCharter != nil {
+		m.onCachedCharter(url, err)
+	}
+	return cached, nil
+}
+
+// isCharterURL reports whether an "extends:" reference is a URL rather than
+// a path.
+func isCharterURL(ref string) bool {
+	return strings.HasPrefix(ref, "https://") || strings.HasPrefix(ref, "http://")
+}
+
+// ReadEffective returns the charter with the charters it extends merged in:
+// an "extends:" reference names an organization charter by http(s) URL, or by
+// path relative to the charter that references it, which may extend another
+// in turn. A charter fetched from a URL may only extend other URLs. Fetched
+// charters are cached under .teamwerx/.local and the cached copy is used
+// when the URL cannot be reached. See MergeCharters for how fields combine.
+func (m *charterManager) ReadEffective() (*model.Charter, error) {
+	charter, err := m.Read()
+	if err != nil {
+		return nil, err
+	}
+	chain := []*model.Charter{charter}
+	seen := map[string]bool{m.charterPath(): true}
+	dir := filepath.Dir(m.charterPath())
+	remote := false
+	for ref := strings.TrimSpace(charter.Extends); ref != ""; ref = strings.TrimSpace(chain[len(chain)-1].Extends) {
+		if len(chain) > maxCharterDepth {
+			return nil, custom_errors.NewErrValidation("extends", fmt.Sprintf("more than %d charters extend one another", maxCharterDepth))
+		}
+		source := ref
+		var data []byte
+		if isCharterURL(ref) {
+			data, err = m.fetchCharter(ref)
+		} else {
+			// Local paths in a downloaded charter would be resolved on
+			// this machine, not next to the charter.
+			if remote {
+				return nil, custom_errors.NewErrValidation("extends", fmt.Sprintf("charter fetched from a URL extends %q; it may only extend URLs", ref))
+			}
+			if dir != "" && !filepath.IsAbs(source) {
+				source = filepath.Join(dir, source)
+			}
+			dir = filepath.Dir(source)
+			data, err = m.store.ReadFile(source)
+		}
+		if seen[source] {
+			return nil, custom_errors.NewErrConflict(fmt.Sprintf("charter %s extends itself", ref))
+		}
+		seen[source] = true
+		if err != nil {
+			return nil, fmt.Errorf("failed to read extended charter '%s': %w", ref, err)
+		}
+		parent, err := parseCharterFile(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse extended charter '%s': %w", ref, err)
+		}
+		remote = isCharterURL(ref)
+		chain = append(chain, parent)
+	}
+
+	effective := chain[len(chain)-1]
+	for i := len(chain) - 2; i >= 0; i-- {
+		effective = MergeCharters(effective, chain[i])
+	}
+	effective.Extends = charter.Extends
+	return effective, nil
+}
+
+// MergeCharters returns the charter project gets when it extends org.
+// Fields the project sets override the organization's: scalars and lists
+// whole, conventions and goal templates key by key. The Markdown content is
+// merged by "## " section: a project section replaces the organization's of
+// the same heading unless it is empty or a charter init placeholder, new ones
+// follow, and the project's text before its first section replaces the
+// organization's when it has any. The dates are the project's.
+func MergeCharters(org, project *model.Charter) *model.Charter {
+	out := *project
+	if out.Title == "" {
+		out.Title = org.Title
+	}
+	if out.Version == "" {
+		out.Version = org.Version
+	}
+	if out.Purpose == "" {
+		out.Purpose = org.Purpose
+	}
+	if len(out.TechStack) == 0 {
+		out.TechStack = org.TechStack
+	}
+	if len(out.SpecSections) == 0 {
+		out.SpecSections = org.SpecSections
+	}
+	if len(org.Conventions) > 0 {
+		out.Conventions = map[string]interface{}{}
+		for k, v := range org.Conventions {
+			out.Conventions[k] = v
+		}
+		for k, v := range project.Conventions {
+			out.Conventions[k] = v
+		}
+	}
+	if len(org.GoalTemplates) > 0 {
+		out.GoalTemplates = map[string]model.GoalTemplate{}
+		for k, v := range org.GoalTemplates {
+			out.GoalTemplates[k] = v
+		}
+		for k, v := range project.GoalTemplates {
+			out.GoalTemplates[k] = v
+		}
+	}
+	out.Content = mergeCharterContent(org.Content, project.Content)
+	return &out
+}
+
+// charterSection is the text before a charter's first "## " heading
+// (heading "") or one "## " section, heading line included.
+type charterSection struct {
+	heading string
+	text    string
+}
+
+// splitCharterSections splits Markdown at its "## " headings, skipping
+// fenced code.
+func splitCharterSections(content string) []charterSection {
+	sections := []charterSection{{}}
+	fence := ""
+	for _, line := range strings.SplitAfter(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+		case strings.HasPrefix(line, "## "):
+			sections = append(sections, charterSection{heading: strings.ToLower(strings.TrimSpace(line[3:]))})
+		}
+		sections[len(sections)-1].text += line
+	}
+	return sections
+}
+
+// mergeCharterContent merges the Markdown of a project charter into its
+// organization's by "## " section.
+func mergeCharterContent(org, project string) string {
+	orgSections, projectSections := splitCharterSections(org), splitCharterSections(project)
+	overrides := map[string]string{}
+	for _, s := range projectSections[1:] {
+		// A section charter init left as a placeholder does not hide the
+		// organization's.
+		body := s.text
+		if i := strings.Index(body, "\n"); i >= 0 {
+			body = strings.TrimSpace(body[i:])
+		} else {
+			body = ""
+		}
+		if body != "" && !isPlaceholder(body) {
+			overrides[s.heading] = s.text
+		}
+	}
+	var parts []string
+	add := func(text string) {
+		if text = strings.TrimSpace(text); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	if strings.TrimSpace(projectSections[0].text) != "" {
+		add(projectSections[0].text)
+	} else {
+		add(orgSections[0].text)
+	}
+	inOrg := map[string]bool{}
+	for _, s := range orgSections[1:] {
+		inOrg[s.heading] = true
+		if text, ok := overrides[s.heading]; ok {
+			add(text)
+			continue
+		}
+		add(s.text)
+	}
+	for _, s := range projectSections[1:] {
+		if !inOrg[s.heading] {
+			add(s.text)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
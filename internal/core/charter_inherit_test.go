@@ -0,0 +1,105 @@
+package core
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestCharterManager_ReadEffective(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	org := "---\nextends: https://example.com/base.md\ntitle: Org\nversion: 2.0.0\ntech_stack: [go]\nconventions:\n  commits: conventional\n  review: two approvals\n---\n# Org charter\n\n## Governance\n\nChanges need a review.\n\n## AI Agent Instructions\n\nRun go test.\n"
+	if err := app.Options.Storage.WriteFile("org/charter.md", []byte(org), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	defer func(orig func(string) ([]byte, error)) { fetchCharterURL = orig }(fetchCharterURL)
+	fetchCharterURL = func(url string) ([]byte, error) {
+		return []byte("---\ntitle: Base\npurpose: Build things.\nspec_sections: [Overview]\n---\n## Security\n\nNo secrets in git.\n"), nil
+	}
+
+	project := &model.Charter{
+		Extends:     "../org/charter.md",
+		Title:       "Demo",
+		Conventions: map[string]interface{}{"review": "one approval"},
+		Content:     "## Governance\n\n[Define decision-making processes]\n\n## AI Agent Instructions\n\nRun make check.\n\n## Glossary\n\nWidget: a thing.\n",
+	}
+	if err := app.CharterManager.Write(project); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := app.CharterManager.ReadEffective()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "Demo" || got.Version != "2.0.0" || got.Purpose != "Build things." || got.Extends != "../org/charter.md" {
+		t.Fatalf("scalars = %q %q %q %q", got.Title, got.Version, got.Purpose, got.Extends)
+	}
+	if len(got.TechStack) != 1 || len(got.SpecSections) != 1 {
+		t.Fatalf("lists = %v %v", got.TechStack, got.SpecSections)
+	}
+	if got.Conventions["commits"] != "conventional" || got.Conventions["review"] != "one approval" {
+		t.Fatalf("conventions = %v", got.Conventions)
+	}
+	want := "# Org charter\n\n## Security\n\nNo secrets in git.\n\n## Governance\n\nChanges need a review.\n\n## AI Agent Instructions\n\nRun make check.\n\n## Glossary\n\nWidget: a thing."
+	if got.Content != want {
+		t.Fatalf("content = %q", got.Content)
+	}
+	if own, err := app.CharterManager.Read(); err != nil || own.Version != "" || strings.Contains(own.Content, "Changes need a review.") {
+		t.Fatalf("Read merged the org charter: %+v, %v", own, err)
+	}
+
+	if err := app.Options.Storage.WriteFile("org/charter.md", []byte("---\nextends: ../.teamwerx/charter.md\ntitle: Org\n---\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.CharterManager.ReadEffective(); err == nil {
+		t.Fatal("expected a cycle to be rejected")
+	} else if _, ok := err.(*custom_errors.ErrConflict); !ok {
+		t.Fatalf("cycle error = %T %v", err, err)
+	}
+}
+
+func TestCharterManager_ReadEffectiveCachesURLs(t *testing.T) {
+	var cachedURL string
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem(), OnCachedCharter: func(url string, err error) { cachedURL = url }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.CharterManager.Write(&model.Charter{Extends: "https://example.com/org.md", Title: "Demo"}); err != nil {
+		t.Fatal(err)
+	}
+	defer func(orig func(string) ([]byte, error)) { fetchCharterURL = orig }(fetchCharterURL)
+	offline := func(url string) ([]byte, error) { return nil, errors.New("network is unreachable") }
+
+	fetchCharterURL = offline
+	if _, err := app.CharterManager.ReadEffective(); err == nil {
+		t.Fatal("expected an error with neither the URL nor a cached copy")
+	}
+
+	fetchCharterURL = func(url string) ([]byte, error) { return []byte("---\ntitle: Org\nversion: 1.0.0\n---\n"), nil }
+	if got, err := app.CharterManager.ReadEffective(); err != nil || got.Version != "1.0.0" {
+		t.Fatalf("online read = %+v, %v", got, err)
+	}
+	fetchCharterURL = offline
+	got, err := app.CharterManager.ReadEffective()
+	if err != nil || got.Version != "1.0.0" {
+		t.Fatalf("offline read = %+v, %v", got, err)
+	}
+	if cachedURL != "https://example.com/org.md" {
+		t.Fatalf("expected the fallback to be reported, got %q", cachedURL)
+	}
+
+	// A fetched charter cannot reach into local files.
+	fetchCharterURL = func(url string) ([]byte, error) { return []byte("---\nextends: ../secrets.md\ntitle: Org\n---\n"), nil }
+	if _, err := app.CharterManager.ReadEffective(); err == nil {
+		t.Fatal("expected a relative extends in a fetched charter to be rejected")
+	} else if _, ok := err.(*custom_errors.ErrValidation); !ok {
+		t.Fatalf("relative extends error = %T %v", err, err)
+	}
+}
@@ -3,13 +3,11 @@ package core
 import (
 	"bytes"
 	"fmt"
-	"os"
-	"path/filepath"
 	"time"
 
 	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
 	"github.com/teamwerx/teamwerx/internal/model"
-	fileutil "github.com/teamwerx/teamwerx/internal/utils/file"
+	"github.com/teamwerx/teamwerx/internal/storage"
 	"gopkg.in/yaml.v3"
 )
 
@@ -18,6 +16,7 @@ import (
 //
 // File format:
 // ---
+// extends: ../org/charter.md
 // title: Project Title
 // version: 1.0.0
 // created: 2025-01-15T10:00:00Z
@@ -32,6 +31,10 @@ import (
 //	commit_prefix: "[PROJECT]"
 //	branch_naming: feature/*, bugfix/*
 //
+// spec_sections:
+//   - Overview
+//   - Requirements
+//
 // ---
 //
 // # Charter Content
@@ -39,30 +42,43 @@ import (
 // Additional markdown content describing governance, standards, etc.
 type charterManager struct {
 	baseDir string
+	layout  Layout
+	store   storage.Storage
+	// onCachedCharter is told when an extended charter URL could not be
+	// fetched and its cached copy was used.
+	onCachedCharter func(url string, err error)
 }
 
 // NewCharterManager creates a new file-backed CharterManager.
 // The baseDir should point to the workspace directory (e.g., ".teamwerx").
 func NewCharterManager(baseDir string) CharterManager {
-	return &charterManager{baseDir: baseDir}
+	return NewCharterManagerWithLayout(baseDir, DefaultLayout())
+}
+
+// NewCharterManagerWithLayout creates a CharterManager storing the charter at layout.CharterFile.
+func NewCharterManagerWithLayout(baseDir string, layout Layout) CharterManager {
+	return newCharterManager(baseDir, layout, storage.OS(), nil)
+}
+
+func newCharterManager(baseDir string, layout Layout, store storage.Storage, onCachedCharter func(url string, err error)) CharterManager {
+	return &charterManager{baseDir: baseDir, layout: layout.WithDefaults(), store: store, onCachedCharter: onCachedCharter}
 }
 
 func (m *charterManager) charterPath() string {
-	return filepath.Join(m.baseDir, "charter.md")
+	return m.layout.CharterPath(m.baseDir)
 }
 
 // Exists checks if the charter file exists.
 func (m *charterManager) Exists() bool {
-	path := m.charterPath()
-	_, err := os.Stat(path)
-	return err == nil
+	ok, err := m.store.Exists(m.charterPath())
+	return err == nil && ok
 }
 
 // Read loads and parses the charter file.
 // Returns ErrNotFound if the charter file doesn't exist.
 func (m *charterManager) Read() (*model.Charter, error) {
 	path := m.charterPath()
-	data, err := fileutil.ReadFile(path)
+	data, err := m.store.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
@@ -109,7 +125,7 @@ func (m *charterManager) Write(charter *model.Charter) error {
 		}
 	}
 
-	if err := fileutil.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+	if err := m.store.WriteFile(path, buf.Bytes(), 0o644); err != nil {
 		return fmt.Errorf("failed to write charter file '%s': %w", path, err)
 	}
 
@@ -140,23 +156,29 @@ func parseCharterFile(data []byte) (*model.Charter, error) {
 func marshalCharterFrontmatter(charter *model.Charter) ([]byte, error) {
 	// Create intermediate struct for clean YAML output
 	type charterYAML struct {
-		Title       string                 `yaml:"title"`
-		Version     string                 `yaml:"version,omitempty"`
-		Created     time.Time              `yaml:"created"`
-		Updated     time.Time              `yaml:"updated"`
-		Purpose     string                 `yaml:"purpose,omitempty"`
-		TechStack   []string               `yaml:"tech_stack,omitempty"`
-		Conventions map[string]interface{} `yaml:"conventions,omitempty"`
+		Extends       string                        `yaml:"extends,omitempty"`
+		Title         string                        `yaml:"title"`
+		Version       string                        `yaml:"version,omitempty"`
+		Created       time.Time                     `yaml:"created"`
+		Updated       time.Time                     `yaml:"updated"`
+		Purpose       string                        `yaml:"purpose,omitempty"`
+		TechStack     []string                      `yaml:"tech_stack,omitempty"`
+		Conventions   map[string]interface{}        `yaml:"conventions,omitempty"`
+		SpecSections  []string                      `yaml:"spec_sections,omitempty"`
+		GoalTemplates map[string]model.GoalTemplate `yaml:"goal_templates,omitempty"`
 	}
 
 	payload := charterYAML{
-		Title:       charter.Title,
-		Version:     charter.Version,
-		Created:     charter.Created,
-		Updated:     charter.Updated,
-		Purpose:     charter.Purpose,
-		TechStack:   charter.TechStack,
-		Conventions: charter.Conventions,
+		Extends:       charter.Extends,
+		Title:         charter.Title,
+		Version:       charter.Version,
+		Created:       charter.Created,
+		Updated:       charter.Updated,
+		Purpose:       charter.Purpose,
+		TechStack:     charter.TechStack,
+		Conventions:   charter.Conventions,
+		SpecSections:  charter.SpecSections,
+		GoalTemplates: charter.GoalTemplates,
 	}
 
 	data, err := yaml.Marshal(&payload)
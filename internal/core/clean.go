@@ -0,0 +1,232 @@
+package core
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+// Kinds of garbage reported by FindGarbage.
+const (
+	GarbageTempFile          = "temp-file"
+	GarbageEmptyDir          = "empty-dir"
+	GarbageOrphanedArchive   = "orphaned-archive"
+	GarbageSupersededArchive = "superseded-archive"
+	GarbageExpiredTrash      = "expired-trash"
+)
+
+// DefaultArchiveRetention is how long split/merged archived changes are kept by default.
+const DefaultArchiveRetention = 30 * 24 * time.Hour
+
+// tempFileGracePeriod protects temp files that a concurrent write may still be using.
+const tempFileGracePeriod = 10 * time.Minute
+
+// tempFilePattern matches the leftovers of an interrupted SafeWriteAtomic
+// ("<name>.tmp-<random digits>").
+var tempFilePattern = regexp.MustCompile(`\.tmp-[0-9]+$`)
+
+// GarbageItem is a single path that `teamwerx clean` would remove.
+type GarbageItem struct {
+	Kind   string
+	Path   string
+	Reason string
+}
+
+// CleanOptions tunes FindGarbage.
+type CleanOptions struct {
+	// Retention is how long superseded (split/merged) archived changes are kept.
+	// Zero means DefaultArchiveRetention.
+	Retention time.Duration
+	// TrashRetention is how long trashed items are kept. Zero means
+	// DefaultTrashRetention.
+	TrashRetention time.Duration
+	// Now is the reference time; zero means time.Now().
+	Now time.Time
+}
+
+// FindGarbage scans the workspace directories and reports:
+//   - temp files left behind by interrupted atomic writes (older than a short grace period),
+//   - empty goal and spec directories,
+//   - archived changes without a readable change.json,
+//   - archived changes superseded by split/merge that are older than the retention window,
+//   - trashed items older than the trash retention window.
+//
+// Nothing is removed; pass the result to RemoveGarbage.
+//
+// opts should be fully resolved (e.g. App.Options) so the configured layout is honored.
+// Garbage is an on-disk concern, so the real file system is always scanned,
+// whatever opts.Storage is.
+func FindGarbage(opts AppOptions, co CleanOptions) ([]GarbageItem, error) {
+	o := opts.WithDefaults()
+	now := co.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	retention := co.Retention
+	if retention == 0 {
+		retention = DefaultArchiveRetention
+	}
+	trashRetention := co.TrashRetention
+	if trashRetention == 0 {
+		trashRetention = DefaultTrashRetention
+	}
+
+	var items []GarbageItem
+	seen := map[string]bool{}
+	add := func(item GarbageItem) {
+		if !seen[item.Path] {
+			seen[item.Path] = true
+			items = append(items, item)
+		}
+	}
+
+	for _, root := range []string{o.CharterDir, o.SpecsDir, o.GoalsDir, o.ChangesDir} {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if d.IsDir() || !tempFilePattern.MatchString(d.Name()) {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if now.Sub(info.ModTime()) >= tempFileGracePeriod {
+				add(GarbageItem{Kind: GarbageTempFile, Path: path, Reason: "left behind by an interrupted write"})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, base := range []string{o.GoalsDir, o.SpecsDir} {
+		entries, err := os.ReadDir(base)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, e := range entries {
+			path := filepath.Join(base, e.Name())
+			if !e.IsDir() {
+				continue
+			}
+			empty, err := isEmptyTree(path, seen)
+			if err != nil {
+				return nil, err
+			}
+			if empty {
+				add(GarbageItem{Kind: GarbageEmptyDir, Path: path, Reason: "contains no files"})
+			}
+		}
+	}
+
+	archiveDir := o.Layout.ArchivePath(o.ChangesDir)
+	dirTmpl := ownDirTemplate(o.Layout.ChangeFile, changePlaceholder)
+	// archivedPath is what gets removed for an archived change: its own
+	// directory when the layout has one, otherwise the change file.
+	archivedPath := func(id string) string {
+		if dirTmpl != "" {
+			return filepath.Join(archiveDir, expandTemplate(dirTmpl, changePlaceholder, id))
+		}
+		return o.Layout.ChangePath(archiveDir, id)
+	}
+
+	if dirTmpl == changePlaceholder {
+		entries, err := os.ReadDir(archiveDir)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		for _, e := range entries {
+			if !e.IsDir() || e.Name() == CompactedArchiveDir {
+				continue
+			}
+			b, err := os.ReadFile(o.Layout.ChangePath(archiveDir, e.Name()))
+			var ch model.Change
+			if err != nil || json.Unmarshal(b, &ch) != nil {
+				add(GarbageItem{Kind: GarbageOrphanedArchive, Path: archivedPath(e.Name()), Reason: "missing or unreadable change file"})
+			}
+		}
+	}
+
+	ids, err := findTemplateMatches(storage.OS(), archiveDir, o.Layout.ChangeFile, changePlaceholder)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, id := range ids {
+		changePath := o.Layout.ChangePath(archiveDir, id)
+		info, statErr := os.Stat(changePath)
+		b, readErr := os.ReadFile(changePath)
+		var ch model.Change
+		if statErr != nil || readErr != nil || json.Unmarshal(b, &ch) != nil {
+			continue
+		}
+		if (ch.Status == "split" || ch.Status == "merged") && now.Sub(info.ModTime()) > retention {
+			add(GarbageItem{Kind: GarbageSupersededArchive, Path: archivedPath(id), Reason: "superseded by " + ch.Status + " beyond retention window"})
+		}
+	}
+
+	trashEntries, err := os.ReadDir(trashDir(o))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, e := range trashEntries {
+		if !e.IsDir() {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(trashDir(o), e.Name(), TrashItemFileName))
+		var item TrashItem
+		if err != nil || json.Unmarshal(b, &item) != nil {
+			continue
+		}
+		if now.Sub(item.DeletedAt) > trashRetention {
+			add(GarbageItem{Kind: GarbageExpiredTrash, Path: filepath.Join(trashDir(o), e.Name()), Reason: "trashed " + item.Kind + " " + item.Name + " beyond retention window"})
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Path < items[j].Path })
+	return items, nil
+}
+
+// RemoveGarbage deletes every item reported by FindGarbage through o's
+// storage. Items that have already disappeared are ignored.
+func RemoveGarbage(o AppOptions, items []GarbageItem) error {
+	store := o.WithDefaults().Storage
+	for _, item := range items {
+		if err := store.RemoveAll(item.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isEmptyTree reports whether dir contains no files other than the ones already
+// marked for removal (e.g. stale temp files).
+func isEmptyTree(dir string, removed map[string]bool) (bool, error) {
+	empty := true
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && !removed[path] {
+			empty = false
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return empty, err
+}
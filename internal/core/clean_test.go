@@ -0,0 +1,91 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFindGarbage_ReportsAndRemoves(t *testing.T) {
+	root := createTempDir(t)
+	opts := AppOptions{
+		SpecsDir:   filepath.Join(root, "specs"),
+		GoalsDir:   filepath.Join(root, "goals"),
+		ChangesDir: filepath.Join(root, "changes"),
+		CharterDir: root,
+	}
+	now := time.Now()
+	old := now.Add(-60 * 24 * time.Hour)
+
+	// Stale temp file next to a real spec, and a fresh one that must be kept.
+	writeSpecFile(t, opts.SpecsDir, "auth", "# Auth\n")
+	staleTmp := filepath.Join(opts.SpecsDir, "auth", "spec.md.tmp-123456")
+	freshTmp := filepath.Join(opts.SpecsDir, "auth", "spec.md.tmp-654321")
+	for _, p := range []string{staleTmp, freshTmp} {
+		if err := os.WriteFile(p, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chtimes(staleTmp, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	// Empty goal directory and a goal with content.
+	emptyGoal := filepath.Join(opts.GoalsDir, "002-empty", "nested")
+	if err := os.MkdirAll(emptyGoal, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeSpecFile(t, opts.GoalsDir, "001-real", "content")
+
+	// Archive: orphaned, superseded (old), superseded (recent), applied (old).
+	archive := filepath.Join(opts.ChangesDir, ".archive")
+	if err := os.MkdirAll(filepath.Join(archive, "CH-ORPHAN"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for id, status := range map[string]string{"CH-OLD": "split", "CH-NEW": "merged", "CH-DONE": "applied"} {
+		path := writeChangeJSON(t, archive, id, map[string]any{"id": id, "status": status})
+		if id != "CH-NEW" {
+			if err := os.Chtimes(path, old, old); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	items, err := FindGarbage(opts, CleanOptions{Now: now})
+	if err != nil {
+		t.Fatalf("FindGarbage failed: %v", err)
+	}
+	got := map[string]string{}
+	for _, item := range items {
+		got[item.Path] = item.Kind
+	}
+	want := map[string]string{
+		staleTmp: GarbageTempFile,
+		filepath.Join(opts.GoalsDir, "002-empty"): GarbageEmptyDir,
+		filepath.Join(archive, "CH-ORPHAN"):       GarbageOrphanedArchive,
+		filepath.Join(archive, "CH-OLD"):          GarbageSupersededArchive,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d items, got %+v", len(want), items)
+	}
+	for path, kind := range want {
+		if got[path] != kind {
+			t.Errorf("expected %s to be %s, got %q", path, kind, got[path])
+		}
+	}
+
+	if err := RemoveGarbage(opts, items); err != nil {
+		t.Fatalf("RemoveGarbage failed: %v", err)
+	}
+	for path := range want {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed", path)
+		}
+	}
+	for _, keep := range []string{freshTmp, filepath.Join(archive, "CH-NEW"), filepath.Join(archive, "CH-DONE")} {
+		if _, err := os.Stat(keep); err != nil {
+			t.Errorf("expected %s to be kept: %v", keep, err)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/storage"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the workspace configuration stored in <CharterDir>/config.yaml.
+//
+// Example:
+//
+//	layout:
+//	  spec_file: "{domain}/SPEC.md"
+//	  plan_file: "{goal}/PLAN.yaml"
+//	analytics:
+//	  enabled: true
+//	secrets:
+//	  mode: redact
+//	discussion:
+//	  types:
+//	    - name: risk
+//	theme:
+//	  name: monochrome
+//	read_only: true
+type Config struct {
+	Layout     Layout           `yaml:"layout,omitempty"`
+	Analytics  AnalyticsConfig  `yaml:"analytics,omitempty"`
+	Secrets    SecretsConfig    `yaml:"secrets,omitempty"`
+	Discussion DiscussionConfig `yaml:"discussion,omitempty"`
+	Theme      ThemeConfig      `yaml:"theme,omitempty"`
+	// Contributors marks which authors are agents in contributor stats.
+	Contributors ContributorsConfig `yaml:"contributors,omitempty"`
+	// Lint configures the requirement lint rules.
+	Lint LintConfig `yaml:"lint,omitempty"`
+	// Archive is the retention policy of 'change compact-archive'.
+	Archive ArchiveConfig `yaml:"archive,omitempty"`
+	// SpecFormat sets the heading level and prefix of requirements.
+	SpecFormat SpecFormat `yaml:"spec_format,omitempty"`
+	// ReadOnly locks the workspace: every App opened on it is read-only.
+	ReadOnly bool `yaml:"read_only,omitempty"`
+}
+
+// LoadConfig reads the workspace configuration at path. A missing file yields
+// an empty Config; invalid YAML or an invalid layout is an error.
+func LoadConfig(path string) (*Config, error) {
+	return loadConfig(storage.OS(), path)
+}
+
+func loadConfig(store storage.Storage, path string) (*Config, error) {
+	cfg := &Config{}
+	data, err := store.ReadFile(path)
+	if err != nil {
+		if _, ok := err.(*custom_errors.ErrNotFound); ok {
+			return cfg, nil
+		}
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config '%s': %w", path, err)
+	}
+	// Report every section's problems at once rather than one per run.
+	var errs custom_errors.ValidationErrors
+	for _, err := range []error{cfg.Layout.WithDefaults().Validate(), cfg.Secrets.Validate(), cfg.Discussion.Validate(), cfg.Theme.Validate(), cfg.Lint.Validate(), cfg.Archive.Validate(), cfg.SpecFormat.Validate()} {
+		errs = append(errs, custom_errors.Validation(err)...)
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid config '%s': %w", path, errs)
+	}
+	return cfg, nil
+}
+
+// ConfigPath returns the config file inside the workspace (charter) directory.
+func ConfigPath(charterDir string) string {
+	return filepath.Join(charterDir, ConfigFileName)
+}
@@ -0,0 +1,205 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// Merge strategies for a change whose spec has diverged from its base
+// fingerprint.
+const (
+	// MergeStrategyReject refuses the merge with ErrDiverged.
+	MergeStrategyReject = "reject"
+	// MergeStrategyMarkers writes both versions of each conflicted
+	// requirement between git-style conflict markers.
+	MergeStrategyMarkers = "markers"
+)
+
+// MergeStrategies lists the accepted merge strategies.
+var MergeStrategies = []string{MergeStrategyReject, MergeStrategyMarkers}
+
+// Conflict marker lines. The separator is preceded by a blank line so
+// markdown does not read it as a setext heading underline.
+const (
+	conflictMarkerOurs   = "<<<<<<< current"
+	conflictMarkerBase   = "||||||| base"
+	conflictMarkerSep    = "======="
+	conflictMarkerTheirs = ">>>>>>> "
+)
+
+// MergeWithMarkers is Merge with the markers strategy. When the spec has
+// diverged from the delta's base fingerprint, it does not return
+// ErrDiverged: each requirement the delta would give different text is
+// written as
+//
+//	<<<<<<< current
+//	<the spec's requirement>
+//	=======
+//	<the requirement as the delta leaves it>
+//	>>>>>>> <label>
+//
+// and returned as a conflict. A removed requirement leaves the incoming side
+// empty. When the operations recorded the requirement's Base, it is the
+// common ancestor: a requirement the spec has not changed since takes the
+// incoming text without a conflict, and a conflict shows the base between
+// "||||||| base" and the separator. Requirements missing from the spec,
+// already matching, or only edited by APPEND and PATCH are merged as usual. A spec that has not
+// diverged merges exactly as Merge does.
+func (m *specMerger) MergeWithMarkers(delta *model.SpecDelta, label string) ([]model.MergeConflict, error) {
+	err := m.Merge(delta)
+	if _, ok := err.(*custom_errors.ErrDiverged); !ok {
+		return nil, err
+	}
+
+	spec, err := m.specManager.ReadSpec(delta.Domain)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := m.parser.Parse([]byte(spec.Content))
+	if err != nil {
+		return nil, err
+	}
+	ours := parsed.Blocks
+	orig := delta
+	delta = resolveDeltaAliases(spec, delta)
+
+	// Apply every operation to get the incoming side. ADDED and ADD_SCENARIO
+	// of something the spec already has become replacements, so the incoming
+	// text is kept instead of failing as a duplicate.
+	theirs := ours
+	var touched []string
+	anchoredOnly := map[string]bool{}
+	placement := map[string]model.DeltaOperation{}
+	base := map[string]string{}
+	for _, op := range delta.Operations {
+		if op.AppliedAt != nil {
+			continue
+		}
+		switch op.Type {
+		case "ADDED":
+			op.Type = "MODIFIED"
+		case "ADD_SCENARIO":
+			op.Type = "MODIFY_SCENARIO"
+		}
+		if theirs, err = m.applyOperations(theirs, &model.SpecDelta{Domain: delta.Domain, Operations: []model.DeltaOperation{op}}); err != nil {
+			return nil, err
+		}
+		id := op.Requirement.ID
+		if _, seen := anchoredOnly[id]; !seen {
+			touched = append(touched, id)
+			anchoredOnly[id] = true
+			placement[id] = op
+		}
+		anchoredOnly[id] = anchoredOnly[id] && anchoredOperation(op)
+		if base[id] == "" {
+			base[id] = op.Base
+		}
+	}
+
+	now := time.Now()
+	merged := append([]model.SpecBlock{}, ours...)
+	var conflicts []model.MergeConflict
+	for _, id := range touched {
+		oi, ti := findRequirementBlock(merged, id), findRequirementBlock(theirs, id)
+		switch {
+		case oi == -1 && ti != -1:
+			op := placement[id]
+			op.Requirement = model.Requirement{ID: id, Content: theirs[ti].Content}
+			merged = placeRequirementBlock(merged, op, m.parser.Format())
+		case oi == -1:
+		case ti != -1 && (anchoredOnly[id] || sameBlockText(merged[oi].Content, theirs[ti].Content)):
+			merged[oi].Content = theirs[ti].Content
+		case base[id] != "" && sameBlockText(merged[oi].Content, base[id]):
+			// Unchanged since the change was written: only it edits the
+			// requirement.
+			if ti == -1 {
+				merged = append(merged[:oi], merged[oi+1:]...)
+			} else {
+				merged[oi].Content = theirs[ti].Content
+			}
+		default:
+			incoming := ""
+			if ti != -1 {
+				incoming = theirs[ti].Content
+			}
+			merged[oi].Content = conflictMarkerText(merged[oi].Content, base[id], incoming, label)
+			conflicts = append(conflicts, model.MergeConflict{Domain: delta.Domain, RequirementID: id, RecordedAt: now})
+		}
+	}
+
+	spec.Content = joinSpecBlocks(merged)
+	if err := m.specManager.WriteSpec(spec); err != nil {
+		return nil, err
+	}
+	orig.AppliedFingerprint = spec.Fingerprint
+	return conflicts, nil
+}
+
+// conflictMarkerText wraps the current and incoming text of a requirement in
+// conflict markers, with the base between them when it is known.
+func conflictMarkerText(current, base, incoming, label string) string {
+	var buf strings.Builder
+	buf.WriteString(conflictMarkerOurs + "\n")
+	buf.WriteString(strings.TrimRight(current, "\n") + "\n\n")
+	if strings.TrimSpace(base) != "" {
+		buf.WriteString(conflictMarkerBase + "\n")
+		buf.WriteString(strings.TrimRight(base, "\n") + "\n\n")
+	}
+	buf.WriteString(conflictMarkerSep + "\n")
+	if strings.TrimSpace(incoming) != "" {
+		buf.WriteString(strings.TrimRight(incoming, "\n") + "\n\n")
+	}
+	fmt.Fprintf(&buf, "%s%s\n\n", conflictMarkerTheirs, label)
+	return buf.String()
+}
+
+// HasConflictMarkers reports whether content still holds a conflict marker
+// line.
+func HasConflictMarkers(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		if line == conflictMarkerOurs || strings.HasPrefix(line, conflictMarkerTheirs) {
+			return true
+		}
+	}
+	return false
+}
+
+// UnresolvedConflicts returns the change's recorded conflicts whose spec still
+// holds the markers the change wrote.
+func UnresolvedConflicts(specs SpecManager, change *model.Change) ([]model.MergeConflict, error) {
+	marked := map[string]bool{}
+	var open []model.MergeConflict
+	for _, c := range change.Conflicts {
+		has, ok := marked[c.Domain]
+		if !ok {
+			spec, err := specs.ReadSpec(c.Domain)
+			if err != nil {
+				if _, notFound := err.(*custom_errors.ErrNotFound); !notFound {
+					return nil, err
+				}
+			} else {
+				for _, line := range strings.Split(spec.Content, "\n") {
+					if line == conflictMarkerTheirs+change.ID {
+						has = true
+						break
+					}
+				}
+			}
+			marked[c.Domain] = has
+		}
+		if has {
+			open = append(open, c)
+		}
+	}
+	return open, nil
+}
+
+// ValidMergeStrategy reports whether s is an accepted merge strategy; empty
+// means the default, reject.
+func ValidMergeStrategy(s string) bool {
+	return s == "" || containsFold(MergeStrategies, s)
+}
@@ -0,0 +1,79 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestApplyChangeTargets_MarkersStrategy(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatalf("NewApp: %v", err)
+	}
+	base := "# Auth\n\n### Requirement: Login\n\nUsers log in with a password.\n\n### Requirement: Logout\n\nUsers can log out.\n"
+	if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "auth", Content: base}); err != nil {
+		t.Fatalf("WriteSpec: %v", err)
+	}
+	spec, _ := app.SpecManager.ReadSpec("auth")
+	baseFP := spec.Fingerprint
+
+	// Someone else edits the login requirement after the change was drafted.
+	spec.Content = strings.Replace(spec.Content, "with a password", "with a passkey", 1)
+	if err := app.SpecManager.WriteSpec(spec); err != nil {
+		t.Fatalf("WriteSpec: %v", err)
+	}
+
+	ch := &model.Change{ID: "CH-007", Title: "Login via SSO", SpecDeltas: []model.SpecDelta{{
+		Domain:          "auth",
+		BaseFingerprint: baseFP,
+		Operations: []model.DeltaOperation{
+			{Type: "MODIFIED", Requirement: model.Requirement{ID: "login", Content: "### Requirement: Login\n\nUsers log in with SSO.\n"}},
+			{Type: "ADDED", Requirement: model.Requirement{ID: "sessions", Content: "### Requirement: Sessions\n\nSessions expire after a day.\n"}},
+		},
+	}}}
+	if err := app.ChangeManager.Save(ch); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if err := app.ChangeManager.ApplyChangeTargets(ch, ApplyTarget{}); err == nil {
+		t.Fatalf("expected the default strategy to reject the diverged spec")
+	}
+	// Strategies are matched case-insensitively, as ValidMergeStrategy accepts them.
+	if err := app.ChangeManager.ApplyChangeTargets(ch, ApplyTarget{Strategy: "Markers"}); err != nil {
+		t.Fatalf("ApplyChangeTargets with markers: %v", err)
+	}
+	if len(ch.Conflicts) != 1 || ch.Conflicts[0].Domain != "auth" || ch.Conflicts[0].RequirementID != "login" {
+		t.Fatalf("expected one recorded conflict on auth/login, got %+v", ch.Conflicts)
+	}
+
+	spec, _ = app.SpecManager.ReadSpec("auth")
+	want := "<<<<<<< current\n### Requirement: Login\n\nUsers log in with a passkey.\n\n=======\n### Requirement: Login\n\nUsers log in with SSO.\n\n>>>>>>> CH-007\n"
+	if !strings.Contains(spec.Content, want) {
+		t.Fatalf("expected both versions between markers, got:\n%s", spec.Content)
+	}
+	if !strings.Contains(spec.Content, "Sessions expire after a day.") || !strings.Contains(spec.Content, "Users can log out.") {
+		t.Fatalf("expected unconflicted requirements merged as usual, got:\n%s", spec.Content)
+	}
+	if !HasConflictMarkers(spec.Content) {
+		t.Fatalf("expected HasConflictMarkers to see the markers")
+	}
+
+	saved, err := app.ChangeManager.ReadChange("CH-007")
+	if err != nil || len(saved.Conflicts) != 1 {
+		t.Fatalf("expected the conflict persisted in the change, got %+v (err %v)", saved, err)
+	}
+	if open, err := UnresolvedConflicts(app.SpecManager, saved); err != nil || len(open) != 1 {
+		t.Fatalf("expected one unresolved conflict, got %v (err %v)", open, err)
+	}
+
+	spec.Content = strings.Replace(spec.Content, want, "### Requirement: Login\n\nUsers log in with SSO or a passkey.\n\n", 1)
+	if err := app.SpecManager.WriteSpec(spec); err != nil {
+		t.Fatalf("WriteSpec: %v", err)
+	}
+	if open, err := UnresolvedConflicts(app.SpecManager, saved); err != nil || len(open) != 0 {
+		t.Fatalf("expected no unresolved conflicts after editing, got %v (err %v)", open, err)
+	}
+}
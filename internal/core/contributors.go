@@ -0,0 +1,207 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// ContributorsConfig is the "contributors" section of config.yaml. Agents
+// names the authors that are AI agents or bots rather than people, so
+// contributor stats can split activity between the two.
+//
+//	contributors:
+//	  agents: [claude, ci-bot]
+type ContributorsConfig struct {
+	Agents []string `yaml:"agents,omitempty"`
+}
+
+// IsAgent reports whether author is one of the configured agents. A leading
+// "@" and case are ignored.
+func (c ContributorsConfig) IsAgent(author string) bool {
+	key := contributorKey(author)
+	for _, a := range c.Agents {
+		if contributorKey(a) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// ContributorActivity counts what one author did within a stats window.
+type ContributorActivity struct {
+	Author            string `json:"author"`
+	Agent             bool   `json:"agent"`
+	TasksCompleted    int    `json:"tasks_completed"`
+	DiscussionEntries int    `json:"discussion_entries"`
+	ChangesAuthored   int    `json:"changes_authored"`
+	ChangesApproved   int    `json:"changes_approved"`
+}
+
+// Total is the author's activity count across every kind.
+func (c ContributorActivity) Total() int {
+	return c.TasksCompleted + c.DiscussionEntries + c.ChangesAuthored + c.ChangesApproved
+}
+
+// ContributorStats is the per-author activity breakdown of a workspace, with
+// the totals for people and for agents.
+type ContributorStats struct {
+	Since        time.Time             `json:"since,omitempty"`
+	Contributors []ContributorActivity `json:"contributors"`
+	Humans       ContributorActivity   `json:"humans"`
+	Agents       ContributorActivity   `json:"agents"`
+}
+
+// ContributorStats counts, per author, the tasks completed, discussion
+// entries written, changes authored (archived ones included) and change
+// approvals given since the given time (everything when zero). A task counts
+// for whoever completed it, or its assignee when that was not recorded; tasks
+// completed before completion times were recorded are counted only when since
+// is zero. Activity without an author is reported under "unknown".
+// Contributors are ordered by total activity, busiest first.
+func (a *App) ContributorStats(since time.Time, cfg ContributorsConfig) (*ContributorStats, error) {
+	byKey := map[string]*ContributorActivity{}
+	count := func(author string, at time.Time) *ContributorActivity {
+		if !since.IsZero() && at.Before(since) {
+			return nil
+		}
+		author = strings.TrimPrefix(strings.TrimSpace(author), "@")
+		if author == "" {
+			author = "unknown"
+		}
+		key := contributorKey(author)
+		c := byKey[key]
+		if c == nil {
+			c = &ContributorActivity{Author: author, Agent: cfg.IsAgent(author)}
+			byKey[key] = c
+		}
+		return c
+	}
+
+	goals, err := ListGoalIDs(a.Options)
+	if err != nil {
+		return nil, err
+	}
+	for _, goal := range goals {
+		plan, err := a.PlanManager.Load(goal)
+		if err != nil {
+			if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+				return nil, err
+			}
+		} else {
+			for _, t := range plan.Tasks {
+				if t.Status != TaskStatusCompleted {
+					continue
+				}
+				var at time.Time
+				if t.CompletedAt != nil {
+					at = *t.CompletedAt
+				}
+				by := t.CompletedBy
+				if by == "" {
+					by = t.Assignee
+				}
+				if c := count(by, at); c != nil {
+					c.TasksCompleted++
+				}
+			}
+		}
+		err = a.DiscussionManager.Each(goal, func(e model.DiscussionEntry) error {
+			if c := count(e.Author, e.Timestamp); c != nil {
+				c.DiscussionEntries++
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	changes, err := a.ChangeManager.ListChanges()
+	if err != nil {
+		return nil, err
+	}
+	archived, err := a.archivedChanges()
+	if err != nil {
+		return nil, err
+	}
+	for _, ch := range append(changes, archived...) {
+		if c := count(ch.Author, ch.CreatedAt); c != nil {
+			c.ChangesAuthored++
+		}
+		for _, ap := range ch.Approvals {
+			if c := count(ap.Approver, ap.ApprovedAt); c != nil {
+				c.ChangesApproved++
+			}
+		}
+	}
+
+	stats := &ContributorStats{Since: since, Contributors: []ContributorActivity{}}
+	stats.Humans.Author, stats.Agents.Author, stats.Agents.Agent = "humans", "agents", true
+	for _, c := range byKey {
+		stats.Contributors = append(stats.Contributors, *c)
+		total := &stats.Humans
+		if c.Agent {
+			total = &stats.Agents
+		}
+		total.TasksCompleted += c.TasksCompleted
+		total.DiscussionEntries += c.DiscussionEntries
+		total.ChangesAuthored += c.ChangesAuthored
+		total.ChangesApproved += c.ChangesApproved
+	}
+	sort.Slice(stats.Contributors, func(i, j int) bool {
+		ci, cj := stats.Contributors[i], stats.Contributors[j]
+		if ci.Total() != cj.Total() {
+			return ci.Total() > cj.Total()
+		}
+		return compareNatural(ci.Author, cj.Author) < 0
+	})
+	return stats, nil
+}
+
+// archivedChanges reads every change in the archive, skipping unreadable ones
+// as ListChanges does.
+func (a *App) archivedChanges() ([]*model.Change, error) {
+	o := a.Options
+	archive := o.Layout.ArchivePath(o.ChangesDir)
+	ids, err := findTemplateMatches(o.Storage, archive, o.Layout.ChangeFile, changePlaceholder)
+	if err != nil {
+		if _, ok := err.(*custom_errors.ErrNotFound); ok || os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var out []*model.Change
+	for _, id := range ids {
+		b, err := o.Storage.ReadFile(o.Layout.ChangePath(archive, id))
+		if err != nil {
+			continue
+		}
+		var ch model.Change
+		if err := json.Unmarshal(b, &ch); err != nil {
+			continue
+		}
+		if ch.ID == "" {
+			ch.ID = id
+		}
+		out = append(out, &ch)
+	}
+	compacted, err := a.CompactedChanges()
+	if err != nil {
+		return nil, err
+	}
+	for i := range compacted {
+		out = append(out, &compacted[i].Change)
+	}
+	return out, nil
+}
+
+// contributorKey is the form authors are matched by.
+func contributorKey(author string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(author), "@"))
+}
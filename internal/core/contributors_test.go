@@ -0,0 +1,92 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestContributorStats_CountsPerAuthorAndSplitsAgents(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatalf("NewApp: %v", err)
+	}
+	now := time.Now()
+	old := now.AddDate(0, -2, 0)
+
+	plan := &model.Plan{GoalID: "001-auth"}
+	for _, title := range []string{"Login", "Logout", "Refresh"} {
+		if _, err := app.PlanManager.AddTask(plan, title); err != nil {
+			t.Fatalf("AddTask: %v", err)
+		}
+	}
+	if err := app.PlanManager.CompleteTask(plan, "T01", false); err != nil {
+		t.Fatalf("CompleteTask: %v", err)
+	}
+	if plan.Tasks[0].CompletedAt == nil {
+		t.Fatalf("expected CompleteTask to record the completion time")
+	}
+	plan.Tasks[0].CompletedBy = "claude"
+	plan.Tasks[1].Status, plan.Tasks[1].Assignee = TaskStatusCompleted, "@ana"
+	if err := app.PlanManager.Save(plan); err != nil {
+		t.Fatalf("Save plan: %v", err)
+	}
+
+	entries := []*model.DiscussionEntry{
+		{Type: DiscussionTypeDiscussion, Author: "ana", Content: "Scope?", Timestamp: now},
+		{Type: DiscussionTypeDiscussion, Author: "Claude", Content: "Proposal", Timestamp: now},
+		{Type: DiscussionTypeDiscussion, Author: "ana", Content: "Kickoff", Timestamp: old},
+	}
+	if err := app.DiscussionManager.AddEntries("001-auth", entries); err != nil {
+		t.Fatalf("AddEntries: %v", err)
+	}
+
+	ch := &model.Change{ID: "CH-001", Title: "Tokens", Status: "approved", Author: "claude", CreatedAt: now,
+		Approvals: []model.Approval{{Domain: "auth", Approver: "@ana", ApprovedAt: now}}}
+	if err := app.ChangeManager.Save(ch); err != nil {
+		t.Fatalf("Save change: %v", err)
+	}
+	if err := app.ChangeManager.ArchiveChange(ch); err != nil {
+		t.Fatalf("ArchiveChange: %v", err)
+	}
+	if err := app.ChangeManager.Save(&model.Change{ID: "CH-002", Title: "Old", Status: "draft", Author: "ana", CreatedAt: old}); err != nil {
+		t.Fatalf("Save change: %v", err)
+	}
+
+	cfg := ContributorsConfig{Agents: []string{"claude"}}
+	stats, err := app.ContributorStats(time.Time{}, cfg)
+	if err != nil {
+		t.Fatalf("ContributorStats: %v", err)
+	}
+	want := map[string]ContributorActivity{
+		"ana":    {Author: "ana", TasksCompleted: 1, DiscussionEntries: 2, ChangesAuthored: 1, ChangesApproved: 1},
+		"claude": {Author: "claude", Agent: true, TasksCompleted: 1, DiscussionEntries: 1, ChangesAuthored: 1},
+	}
+	if len(stats.Contributors) != len(want) {
+		t.Fatalf("expected %d contributors, got %+v", len(want), stats.Contributors)
+	}
+	for _, c := range stats.Contributors {
+		if w, ok := want[c.Author]; !ok || c != w {
+			t.Errorf("contributor %s: got %+v, want %+v", c.Author, c, w)
+		}
+	}
+	if stats.Contributors[0].Author != "ana" {
+		t.Errorf("expected the busiest contributor first, got %s", stats.Contributors[0].Author)
+	}
+	if stats.Humans.Total() != 5 || stats.Agents.Total() != 3 {
+		t.Errorf("unexpected totals: humans %+v, agents %+v", stats.Humans, stats.Agents)
+	}
+
+	// A window leaves out older activity and tasks without a completion time.
+	stats, err = app.ContributorStats(now.AddDate(0, 0, -30), cfg)
+	if err != nil {
+		t.Fatalf("ContributorStats: %v", err)
+	}
+	for _, c := range stats.Contributors {
+		if c.Author == "ana" && (c.TasksCompleted != 0 || c.DiscussionEntries != 1 || c.ChangesAuthored != 0 || c.ChangesApproved != 1) {
+			t.Errorf("unexpected windowed activity for ana: %+v", c)
+		}
+	}
+}
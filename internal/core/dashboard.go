@@ -0,0 +1,131 @@
+package core
+
+import (
+	"time"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// Dashboard is a read-only snapshot of a workspace for stakeholders: specs
+// and their requirements, goal progress, changes and the apply queue.
+type Dashboard struct {
+	GeneratedAt time.Time         `json:"generated_at"`
+	Specs       []DashboardSpec   `json:"specs"`
+	Goals       []DashboardGoal   `json:"goals"`
+	Changes     []DashboardChange `json:"changes"`
+	Queue       []QueuedChange    `json:"queue"`
+}
+
+// DashboardSpec summarizes one spec domain.
+type DashboardSpec struct {
+	Domain       string                 `json:"domain"`
+	Fingerprint  string                 `json:"fingerprint"`
+	Requirements []DashboardRequirement `json:"requirements"`
+}
+
+// DashboardRequirement summarizes one requirement.
+type DashboardRequirement struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Scenarios int    `json:"scenarios"`
+}
+
+// DashboardGoal is a goal's plan progress. HasPlan is false for goals whose
+// plan has not been written yet.
+type DashboardGoal struct {
+	GoalID   string             `json:"goal_id"`
+	Status   string             `json:"status"`
+	HasPlan  bool               `json:"has_plan"`
+	Progress model.PlanProgress `json:"progress"`
+}
+
+// DashboardChange summarizes one change. Stale lists the domains whose base
+// fingerprints no longer match the current specs.
+type DashboardChange struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Status    string    `json:"status"`
+	GoalID    string    `json:"goal_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Domains   []string  `json:"domains"`
+	Stale     []string  `json:"stale,omitempty"`
+}
+
+// BuildDashboard collects the dashboard snapshot for app. Lists are never nil
+// so the JSON encoding always has arrays.
+func BuildDashboard(app *App) (*Dashboard, error) {
+	d := &Dashboard{
+		GeneratedAt: time.Now().UTC(),
+		Specs:       []DashboardSpec{},
+		Goals:       []DashboardGoal{},
+		Changes:     []DashboardChange{},
+	}
+
+	specs, err := app.SpecManager.ListSpecs()
+	if err != nil {
+		return nil, err
+	}
+	for _, spec := range specs {
+		ds := DashboardSpec{Domain: spec.Domain, Fingerprint: spec.Fingerprint, Requirements: []DashboardRequirement{}}
+		for _, r := range spec.Requirements {
+			ds.Requirements = append(ds.Requirements, DashboardRequirement{ID: r.ID, Title: r.Title, Scenarios: len(r.Scenarios)})
+		}
+		d.Specs = append(d.Specs, ds)
+	}
+
+	goalIDs, err := ListGoalIDs(app.Options)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range goalIDs {
+		g := DashboardGoal{GoalID: id, Status: "active"}
+		plan, err := app.PlanManager.Load(id)
+		if err != nil {
+			if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+				return nil, err
+			}
+		} else {
+			g.HasPlan = true
+			g.Progress = plan.Progress
+			if plan.Status != "" {
+				g.Status = plan.Status
+			}
+		}
+		d.Goals = append(d.Goals, g)
+	}
+
+	changes, err := app.ChangeManager.ListChanges()
+	if err != nil {
+		return nil, err
+	}
+	staleness, err := CheckStaleness(app.SpecManager, changes)
+	if err != nil {
+		return nil, err
+	}
+	stale := map[string][]string{}
+	for _, s := range staleness {
+		for _, delta := range s.Stale {
+			stale[s.ChangeID] = append(stale[s.ChangeID], delta.Domain)
+		}
+	}
+	for _, ch := range changes {
+		dc := DashboardChange{
+			ID: ch.ID, Title: ch.Title, Status: ch.Status, GoalID: ch.GoalID, CreatedAt: ch.CreatedAt,
+			Domains: []string{}, Stale: stale[ch.ID],
+		}
+		for _, delta := range ch.SpecDeltas {
+			if !containsString(dc.Domains, delta.Domain) {
+				dc.Domains = append(dc.Domains, delta.Domain)
+			}
+		}
+		d.Changes = append(d.Changes, dc)
+	}
+
+	queue, err := app.LoadApplyQueue()
+	if err != nil {
+		return nil, err
+	}
+	d.Queue = queue.Changes
+	return d, nil
+}
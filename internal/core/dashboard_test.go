@@ -0,0 +1,47 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestBuildDashboard(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "auth", Content: "# Auth\n\n### Requirement: Login\n\nUsers log in.\n\n#### Scenario: Success\n\nOK.\n"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.PlanManager.Save(&model.Plan{GoalID: "001-demo", Tasks: []model.Task{
+		{ID: "T01", Title: "a", Status: "completed"},
+		{ID: "T02", Title: "b", Status: "pending"},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.ChangeManager.Save(&model.Change{ID: "CH-001", Title: "Tweak", Status: "draft", SpecDeltas: []model.SpecDelta{
+		{Domain: "auth", BaseFingerprint: "stale"},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := BuildDashboard(app)
+	if err != nil {
+		t.Fatalf("BuildDashboard: %v", err)
+	}
+	if len(d.Specs) != 1 || !reflect.DeepEqual(d.Specs[0].Requirements, []DashboardRequirement{{ID: "login", Title: "Login", Scenarios: 1}}) {
+		t.Fatalf("specs = %+v", d.Specs)
+	}
+	if len(d.Goals) != 1 || !d.Goals[0].HasPlan || d.Goals[0].Status != "active" || d.Goals[0].Progress.Completed != 1 || d.Goals[0].Progress.Total != 2 {
+		t.Fatalf("goals = %+v", d.Goals)
+	}
+	if len(d.Changes) != 1 || !reflect.DeepEqual(d.Changes[0].Domains, []string{"auth"}) || !reflect.DeepEqual(d.Changes[0].Stale, []string{"auth"}) {
+		t.Fatalf("changes = %+v", d.Changes)
+	}
+	if d.Queue == nil || len(d.Queue) != 0 {
+		t.Fatalf("queue = %#v", d.Queue)
+	}
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// Decision is a "decision" discussion entry and the goal it was recorded on.
+type Decision struct {
+	GoalID string                `json:"goal_id"`
+	Entry  model.DiscussionEntry `json:"entry"`
+}
+
+// Decisions collects the decision entries of the given goals (every goal
+// when none are given), oldest first.
+func (a *App) Decisions(goalIDs ...string) ([]Decision, error) {
+	if len(goalIDs) == 0 {
+		ids, err := ListGoalIDs(a.Options)
+		if err != nil {
+			return nil, err
+		}
+		goalIDs = ids
+	}
+	var out []Decision
+	for _, id := range goalIDs {
+		entries, err := a.DiscussionManager.Load(id)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.Type == DiscussionTypeDecision {
+				out = append(out, Decision{GoalID: id, Entry: e})
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if !out[i].Entry.Timestamp.Equal(out[j].Entry.Timestamp) {
+			return out[i].Entry.Timestamp.Before(out[j].Entry.Timestamp)
+		}
+		if out[i].GoalID != out[j].GoalID {
+			return compareNatural(out[i].GoalID, out[j].GoalID) < 0
+		}
+		return compareNatural(out[i].Entry.ID, out[j].Entry.ID) < 0
+	})
+	return out, nil
+}
+
+// FormatDecisionLog renders decisions as a markdown decision log: one
+// section per decision, titled by its first line.
+func FormatDecisionLog(decisions []Decision) string {
+	var b strings.Builder
+	b.WriteString("# Decision Log\n")
+	if len(decisions) == 0 {
+		b.WriteString("\nNo decisions recorded yet.\n")
+		return b.String()
+	}
+	for _, d := range decisions {
+		lines := strings.SplitN(strings.TrimSpace(d.Entry.Content), "\n", 2)
+		fmt.Fprintf(&b, "\n## %s\n\n", lines[0])
+		meta := []string{"Goal " + d.GoalID, d.Entry.ID}
+		if !d.Entry.Timestamp.IsZero() {
+			meta = append(meta, d.Entry.Timestamp.Format("2006-01-02"))
+		}
+		if d.Entry.Author != "" {
+			meta = append(meta, "@"+d.Entry.Author)
+		}
+		fmt.Fprintf(&b, "_%s_\n", strings.Join(meta, " · "))
+		if len(lines) > 1 {
+			if rest := strings.TrimSpace(lines[1]); rest != "" {
+				fmt.Fprintf(&b, "\n%s\n", rest)
+			}
+		}
+	}
+	return b.String()
+}
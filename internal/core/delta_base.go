@@ -0,0 +1,108 @@
+package core
+
+import (
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// baseOperation reports whether op edits or removes an existing requirement,
+// so its Base is worth recording.
+func baseOperation(op model.DeltaOperation) bool {
+	switch op.Type {
+	case "MODIFIED", "REMOVED", "MODIFY_SCENARIO", "REMOVE_SCENARIO":
+		return true
+	}
+	return false
+}
+
+// SnapshotDeltaBases records in each pending operation of d without a Base
+// the text spec gives the requirement it edits or removes. Nothing is
+// recorded when d was written against another version of the spec, whose
+// text is not at hand.
+func SnapshotDeltaBases(spec *model.Spec, d *model.SpecDelta) {
+	if spec == nil || (d.BaseFingerprint != "" && d.BaseFingerprint != spec.Fingerprint) {
+		return
+	}
+	for i := range d.Operations {
+		op := &d.Operations[i]
+		if op.Base != "" || op.AppliedAt != nil || !baseOperation(*op) {
+			continue
+		}
+		id, _ := ResolveRequirementAlias(spec, op.Requirement.ID)
+		for _, r := range spec.Requirements {
+			if r.ID == id {
+				op.Base = spec.Content[r.Start:r.End]
+				break
+			}
+		}
+	}
+}
+
+// snapshotChangeBases records the Base of every operation of deltas from
+// the current specs. Domains without a spec are skipped.
+func snapshotChangeBases(specs SpecManager, deltas []model.SpecDelta) error {
+	if specs == nil {
+		return nil
+	}
+	for i := range deltas {
+		spec, err := specs.ReadSpec(deltas[i].Domain)
+		if err != nil {
+			if _, ok := err.(*custom_errors.ErrNotFound); ok {
+				continue
+			}
+			return err
+		}
+		SnapshotDeltaBases(spec, &deltas[i])
+	}
+	return nil
+}
+
+// BaseDrift is a requirement a pending operation was written against whose
+// text has changed since: Base is what the author saw, Current what the spec
+// holds now ("" once the requirement is gone).
+type BaseDrift struct {
+	Domain        string
+	RequirementID string
+	Base          string
+	Current       string
+}
+
+// DriftedBases compares the recorded Base of each pending operation of
+// change with the current spec and returns one entry per requirement that
+// has changed since.
+func DriftedBases(specs SpecManager, change *model.Change) ([]BaseDrift, error) {
+	var out []BaseDrift
+	for _, d := range change.SpecDeltas {
+		var spec *model.Spec
+		seen := map[string]bool{}
+		for _, op := range d.Operations {
+			if op.Base == "" || op.AppliedAt != nil || seen[op.Requirement.ID] {
+				continue
+			}
+			seen[op.Requirement.ID] = true
+			if spec == nil {
+				var err error
+				if spec, err = specs.ReadSpec(d.Domain); err != nil {
+					if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+						return nil, err
+					}
+					spec = &model.Spec{Domain: d.Domain}
+				}
+			}
+			id, _ := ResolveRequirementAlias(spec, op.Requirement.ID)
+			current := ""
+			for _, r := range spec.Requirements {
+				if r.ID == id {
+					current = spec.Content[r.Start:r.End]
+					break
+				}
+			}
+			if !sameBlockText(current, op.Base) {
+				out = append(out, BaseDrift{Domain: d.Domain, RequirementID: op.Requirement.ID, Base: strings.TrimSpace(op.Base), Current: strings.TrimSpace(current)})
+			}
+		}
+	}
+	return out, nil
+}
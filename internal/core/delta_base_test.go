@@ -0,0 +1,63 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestCreateChange_SnapshotsBasesForThreeWayMerge(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatalf("NewApp: %v", err)
+	}
+	base := "# Auth\n\n### Requirement: Login\n\nUsers log in with a password.\n\n### Requirement: Logout\n\nUsers can log out.\n\n### Requirement: Audit\n\nLogins are audited.\n"
+	if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "auth", Content: base}); err != nil {
+		t.Fatalf("WriteSpec: %v", err)
+	}
+
+	ch, err := app.ChangeManager.CreateChange("SSO", "", []model.SpecDelta{{
+		Domain: "auth",
+		Operations: []model.DeltaOperation{
+			{Type: "MODIFIED", Requirement: model.Requirement{ID: "login", Content: "### Requirement: Login\n\nUsers log in with SSO.\n"}},
+			{Type: "MODIFIED", Requirement: model.Requirement{ID: "logout", Content: "### Requirement: Logout\n\nUsers can log out everywhere.\n"}},
+			{Type: "REMOVED", Requirement: model.Requirement{ID: "audit"}},
+			{Type: "ADDED", Requirement: model.Requirement{ID: "sessions", Content: "### Requirement: Sessions\n\nSessions expire.\n"}},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("CreateChange: %v", err)
+	}
+	ops := ch.SpecDeltas[0].Operations
+	if !strings.Contains(ops[0].Base, "with a password") || !strings.Contains(ops[2].Base, "Logins are audited.") || ops[3].Base != "" {
+		t.Fatalf("bases = %q, %q, %q", ops[0].Base, ops[2].Base, ops[3].Base)
+	}
+
+	// Only the logout requirement changes after the change was written.
+	spec, _ := app.SpecManager.ReadSpec("auth")
+	spec.Content = strings.Replace(spec.Content, "Users can log out.", "Users can sign out.", 1)
+	if err := app.SpecManager.WriteSpec(spec); err != nil {
+		t.Fatalf("WriteSpec: %v", err)
+	}
+	drifted, err := DriftedBases(app.SpecManager, ch)
+	if err != nil || len(drifted) != 1 || drifted[0].RequirementID != "logout" || drifted[0].Current != "### Requirement: Logout\n\nUsers can sign out." {
+		t.Fatalf("drifted = %+v (err %v)", drifted, err)
+	}
+
+	if err := app.ChangeManager.ApplyChangeTargets(ch, ApplyTarget{Strategy: MergeStrategyMarkers}); err != nil {
+		t.Fatalf("ApplyChangeTargets: %v", err)
+	}
+	if len(ch.Conflicts) != 1 || ch.Conflicts[0].RequirementID != "logout" {
+		t.Fatalf("conflicts = %+v", ch.Conflicts)
+	}
+	spec, _ = app.SpecManager.ReadSpec("auth")
+	want := "<<<<<<< current\n### Requirement: Logout\n\nUsers can sign out.\n\n||||||| base\n### Requirement: Logout\n\nUsers can log out.\n\n=======\n### Requirement: Logout\n\nUsers can log out everywhere.\n\n>>>>>>> " + ch.ID + "\n"
+	if !strings.Contains(spec.Content, want) {
+		t.Fatalf("expected the base between the markers, got:\n%s", spec.Content)
+	}
+	if !strings.Contains(spec.Content, "Users log in with SSO.") || strings.Contains(spec.Content, "Logins are audited.") || !strings.Contains(spec.Content, "Sessions expire.") {
+		t.Fatalf("expected unchanged requirements merged without conflict, got:\n%s", spec.Content)
+	}
+}
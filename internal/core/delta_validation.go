@@ -0,0 +1,176 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// DeltaIssue is a single field-level problem found in a change's spec deltas.
+// Field is a JSON path into change.json, e.g. "spec_deltas[0].operations[2].requirement.id".
+type DeltaIssue struct {
+	Field   string
+	Message string
+}
+
+func (i DeltaIssue) String() string {
+	return fmt.Sprintf("%s: %s", i.Field, i.Message)
+}
+
+// DeltaValidationError collects every DeltaIssue found in a change.
+type DeltaValidationError struct {
+	ChangeID string
+	Issues   []DeltaIssue
+}
+
+func (e *DeltaValidationError) Error() string {
+	parts := make([]string, 0, len(e.Issues))
+	for _, issue := range e.Issues {
+		parts = append(parts, issue.String())
+	}
+	return fmt.Sprintf("change %s has %d invalid delta operation(s): %s", e.ChangeID, len(e.Issues), strings.Join(parts, "; "))
+}
+
+// Unwrap exposes the issues as custom_errors.ValidationErrors.
+func (e *DeltaValidationError) Unwrap() error {
+	errs := make(custom_errors.ValidationErrors, len(e.Issues))
+	for i, issue := range e.Issues {
+		errs[i] = &custom_errors.ErrValidation{Field: issue.Field, Reason: issue.Message}
+	}
+	return errs
+}
+
+// ValidateChangeDeltas checks a change's operations against the current specs
+// before it is applied:
+//   - MODIFIED and REMOVED must reference a requirement ID present in the spec.
+//   - ADDED must not collide with an existing requirement ID.
+//   - Scenario operations must reference an existing requirement and carry a
+//     scenario; MODIFY_SCENARIO and REMOVE_SCENARIO must reference a scenario
+//     of that requirement, and ADD_SCENARIO must not collide with one.
+//   - APPEND and PATCH must reference an existing requirement and carry their
+//     text or find text.
+//
+// Operations are evaluated in order, so a delta may ADD a requirement and then
+// MODIFY it. Returns a *DeltaValidationError listing every issue, or nil.
+func ValidateChangeDeltas(specs SpecManager, change *model.Change) error {
+	if change == nil {
+		return custom_errors.NewErrConflict("change cannot be nil")
+	}
+
+	var issues []DeltaIssue
+	for i, d := range change.SpecDeltas {
+		deltaPath := fmt.Sprintf("spec_deltas[%d]", i)
+		if strings.TrimSpace(d.Domain) == "" {
+			issues = append(issues, DeltaIssue{Field: deltaPath + ".domain", Message: "domain is required"})
+			continue
+		}
+
+		present, spec, err := requirementScenarios(specs, d.Domain)
+		if err != nil {
+			return err
+		}
+		for j, op := range d.Operations {
+			opPath := fmt.Sprintf("%s.operations[%d]", deltaPath, j)
+			id := op.Requirement.ID
+			if id == "" {
+				issues = append(issues, DeltaIssue{Field: opPath + ".requirement.id", Message: "requirement id is required"})
+				continue
+			}
+			if op.Type != "ADDED" {
+				// A renamed requirement is still found by its old ID.
+				id, _ = ResolveRequirementAlias(spec, id)
+			}
+			scenarios, exists := present[id]
+			switch op.Type {
+			case "ADDED":
+				if exists {
+					issues = append(issues, DeltaIssue{Field: opPath + ".requirement.id", Message: fmt.Sprintf("requirement %q already exists in spec %s", id, d.Domain)})
+				}
+				present[id] = map[string]bool{}
+			case "MODIFIED":
+				if !exists {
+					issues = append(issues, DeltaIssue{Field: opPath + ".requirement.id", Message: fmt.Sprintf("requirement %q not found in spec %s", id, d.Domain)})
+				}
+			case "REMOVED":
+				if !exists {
+					issues = append(issues, DeltaIssue{Field: opPath + ".requirement.id", Message: fmt.Sprintf("requirement %q not found in spec %s", id, d.Domain)})
+				}
+				delete(present, id)
+			case "ADD_SCENARIO", "MODIFY_SCENARIO", "REMOVE_SCENARIO":
+				if !exists {
+					issues = append(issues, DeltaIssue{Field: opPath + ".requirement.id", Message: fmt.Sprintf("requirement %q not found in spec %s", id, d.Domain)})
+					continue
+				}
+				if op.Scenario == nil {
+					issues = append(issues, DeltaIssue{Field: opPath + ".scenario", Message: "scenario is required"})
+					continue
+				}
+				sid := scenarioID(op.Scenario)
+				if sid == "" {
+					issues = append(issues, DeltaIssue{Field: opPath + ".scenario.id", Message: "scenario id or title is required"})
+					continue
+				}
+				switch op.Type {
+				case "ADD_SCENARIO":
+					if scenarios[sid] {
+						issues = append(issues, DeltaIssue{Field: opPath + ".scenario.id", Message: fmt.Sprintf("scenario %q already exists in requirement %s", sid, id)})
+					}
+					scenarios[sid] = true
+				case "MODIFY_SCENARIO":
+					if !scenarios[sid] {
+						issues = append(issues, DeltaIssue{Field: opPath + ".scenario.id", Message: fmt.Sprintf("scenario %q not found in requirement %s", sid, id)})
+					}
+				case "REMOVE_SCENARIO":
+					if !scenarios[sid] {
+						issues = append(issues, DeltaIssue{Field: opPath + ".scenario.id", Message: fmt.Sprintf("scenario %q not found in requirement %s", sid, id)})
+					}
+					delete(scenarios, sid)
+				}
+			case "APPEND", "PATCH":
+				if !exists {
+					issues = append(issues, DeltaIssue{Field: opPath + ".requirement.id", Message: fmt.Sprintf("requirement %q not found in spec %s", id, d.Domain)})
+				}
+				if op.Type == "APPEND" && strings.TrimSpace(op.Text) == "" {
+					issues = append(issues, DeltaIssue{Field: opPath + ".text", Message: "text is required"})
+				}
+				if op.Type == "PATCH" && op.Find == "" {
+					issues = append(issues, DeltaIssue{Field: opPath + ".find", Message: "find is required"})
+				}
+				if op.Type == "PATCH" && op.Find != "" && op.Find == op.Replace {
+					issues = append(issues, DeltaIssue{Field: opPath + ".replace", Message: "replace must differ from find"})
+				}
+			default:
+				issues = append(issues, DeltaIssue{Field: opPath + ".type", Message: fmt.Sprintf("unknown operation type %q (expected ADDED, MODIFIED, REMOVED, ADD_SCENARIO, MODIFY_SCENARIO, REMOVE_SCENARIO, APPEND or PATCH)", op.Type)})
+			}
+		}
+	}
+
+	if len(issues) > 0 {
+		return &DeltaValidationError{ChangeID: change.ID, Issues: issues}
+	}
+	return nil
+}
+
+// requirementScenarios maps each requirement ID in a domain's current spec to
+// the set of its scenario IDs, and returns the spec. A missing spec yields an
+// empty map and a nil spec.
+func requirementScenarios(specs SpecManager, domain string) (map[string]map[string]bool, *model.Spec, error) {
+	ids := map[string]map[string]bool{}
+	spec, err := specs.ReadSpec(domain)
+	if err != nil {
+		if _, ok := err.(*custom_errors.ErrNotFound); ok {
+			return ids, nil, nil
+		}
+		return nil, nil, err
+	}
+	for _, r := range spec.Requirements {
+		scenarios := map[string]bool{}
+		for _, sc := range r.Scenarios {
+			scenarios[sc.ID] = true
+		}
+		ids[r.ID] = scenarios
+	}
+	return ids, spec, nil
+}
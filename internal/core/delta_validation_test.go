@@ -0,0 +1,115 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+func TestValidateChangeDeltas_ValidSequence(t *testing.T) {
+	baseDir := createTempDir(t)
+	writeSpecFile(t, baseDir, "auth", "# Auth\n\n### Requirement: Login\n\nBody.\n")
+	specs := NewSpecManager(baseDir)
+
+	ch := &model.Change{ID: "CH-1", SpecDeltas: []model.SpecDelta{{
+		Domain: "auth",
+		Operations: []model.DeltaOperation{
+			{Type: "MODIFIED", Requirement: model.Requirement{ID: "login"}},
+			{Type: "ADDED", Requirement: model.Requirement{ID: "logout"}},
+			{Type: "MODIFIED", Requirement: model.Requirement{ID: "logout"}},
+			{Type: "REMOVED", Requirement: model.Requirement{ID: "login"}},
+		},
+	}, {
+		Domain:     "billing",
+		Operations: []model.DeltaOperation{{Type: "ADDED", Requirement: model.Requirement{ID: "invoice"}}},
+	}}}
+
+	if err := ValidateChangeDeltas(specs, ch); err != nil {
+		t.Fatalf("expected valid change, got %v", err)
+	}
+}
+
+func TestValidateChangeDeltas_FieldLevelIssues(t *testing.T) {
+	baseDir := createTempDir(t)
+	writeSpecFile(t, baseDir, "auth", "# Auth\n\n### Requirement: Login\n\nBody.\n")
+	specs := NewSpecManager(baseDir)
+
+	ch := &model.Change{ID: "CH-2", SpecDeltas: []model.SpecDelta{{
+		Domain: "auth",
+		Operations: []model.DeltaOperation{
+			{Type: "ADDED", Requirement: model.Requirement{ID: "login"}},
+			{Type: "MODIFIED", Requirement: model.Requirement{ID: "ghost"}},
+			{Type: "RENAMED", Requirement: model.Requirement{ID: "login"}},
+		},
+	}, {
+		Domain:     "billing",
+		Operations: []model.DeltaOperation{{Type: "REMOVED", Requirement: model.Requirement{ID: "invoice"}}},
+	}}}
+
+	err := ValidateChangeDeltas(specs, ch)
+	verr, ok := err.(*DeltaValidationError)
+	if !ok {
+		t.Fatalf("expected *DeltaValidationError, got %T (%v)", err, err)
+	}
+	want := []string{
+		"spec_deltas[0].operations[0].requirement.id",
+		"spec_deltas[0].operations[1].requirement.id",
+		"spec_deltas[0].operations[2].type",
+		"spec_deltas[1].operations[0].requirement.id",
+	}
+	if len(verr.Issues) != len(want) {
+		t.Fatalf("expected %d issues, got %+v", len(want), verr.Issues)
+	}
+	for i, field := range want {
+		if verr.Issues[i].Field != field {
+			t.Errorf("issue %d: expected field %s, got %s", i, field, verr.Issues[i].Field)
+		}
+	}
+}
+
+func TestValidateChangeDeltas_ScenarioOperations(t *testing.T) {
+	baseDir := createTempDir(t)
+	writeSpecFile(t, baseDir, "auth", "# Auth\n\n### Requirement: Login\n\nBody.\n\n#### Scenario: Valid password\n\n- THEN ok\n")
+	specs := NewSpecManager(baseDir)
+	login := model.Requirement{ID: "login"}
+
+	valid := &model.Change{ID: "CH-3", SpecDeltas: []model.SpecDelta{{
+		Domain: "auth",
+		Operations: []model.DeltaOperation{
+			{Type: "MODIFY_SCENARIO", Requirement: login, Scenario: &model.Scenario{ID: "valid-password"}},
+			{Type: "ADD_SCENARIO", Requirement: login, Scenario: &model.Scenario{Title: "Locked account"}},
+			{Type: "REMOVE_SCENARIO", Requirement: login, Scenario: &model.Scenario{ID: "locked-account"}},
+		},
+	}}}
+	if err := ValidateChangeDeltas(specs, valid); err != nil {
+		t.Fatalf("expected valid change, got %v", err)
+	}
+
+	invalid := &model.Change{ID: "CH-4", SpecDeltas: []model.SpecDelta{{
+		Domain: "auth",
+		Operations: []model.DeltaOperation{
+			{Type: "ADD_SCENARIO", Requirement: login, Scenario: &model.Scenario{Title: "Valid password"}},
+			{Type: "MODIFY_SCENARIO", Requirement: login, Scenario: &model.Scenario{ID: "ghost"}},
+			{Type: "REMOVE_SCENARIO", Requirement: model.Requirement{ID: "logout"}, Scenario: &model.Scenario{ID: "x"}},
+			{Type: "ADD_SCENARIO", Requirement: login},
+		},
+	}}}
+	verr, ok := ValidateChangeDeltas(specs, invalid).(*DeltaValidationError)
+	if !ok {
+		t.Fatalf("expected *DeltaValidationError")
+	}
+	want := []string{
+		"spec_deltas[0].operations[0].scenario.id",
+		"spec_deltas[0].operations[1].scenario.id",
+		"spec_deltas[0].operations[2].requirement.id",
+		"spec_deltas[0].operations[3].scenario",
+	}
+	if len(verr.Issues) != len(want) {
+		t.Fatalf("expected %d issues, got %v", len(want), verr.Issues)
+	}
+	for i, f := range want {
+		if verr.Issues[i].Field != f {
+			t.Errorf("issue %d: expected field %s, got %s", i, f, verr.Issues[i].Field)
+		}
+	}
+}
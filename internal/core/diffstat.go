@@ -0,0 +1,278 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// Diffstat summarizes how a workspace changed between two snapshots, e.g.
+// two git revisions: requirements per domain, tasks and applied changes.
+type Diffstat struct {
+	From    string           `json:"from"`
+	To      string           `json:"to"`
+	Domains []DomainDiffstat `json:"domains"`
+	// TasksAdded and TasksCompleted are new tasks and tasks completed (or
+	// waived) since From.
+	TasksAdded     []DiffstatTask `json:"tasks_added"`
+	TasksCompleted []DiffstatTask `json:"tasks_completed"`
+	// ChangesApplied are changes applied (or archived after applying) since From.
+	ChangesApplied []DiffstatChange `json:"changes_applied"`
+}
+
+// DomainDiffstat lists the requirement IDs added, modified and removed in
+// one domain.
+type DomainDiffstat struct {
+	Domain   string   `json:"domain"`
+	Added    []string `json:"added,omitempty"`
+	Modified []string `json:"modified,omitempty"`
+	Removed  []string `json:"removed,omitempty"`
+}
+
+// DiffstatTask is a task of a goal.
+type DiffstatTask struct {
+	GoalID string `json:"goal_id"`
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+}
+
+// DiffstatChange is a change applied between the snapshots.
+type DiffstatChange struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// Empty reports whether nothing changed.
+func (d *Diffstat) Empty() bool {
+	return len(d.Domains) == 0 && len(d.TasksAdded) == 0 && len(d.TasksCompleted) == 0 && len(d.ChangesApplied) == 0
+}
+
+// ComputeDiffstat compares the workspace of before with that of after.
+// Requirements count as modified when their fingerprint differs, so note
+// edits do not show up.
+func ComputeDiffstat(before, after *App) (*Diffstat, error) {
+	d := &Diffstat{TasksAdded: []DiffstatTask{}, TasksCompleted: []DiffstatTask{}, ChangesApplied: []DiffstatChange{}, Domains: []DomainDiffstat{}}
+
+	oldSpecs, err := specRequirementFingerprints(before)
+	if err != nil {
+		return nil, err
+	}
+	newSpecs, err := specRequirementFingerprints(after)
+	if err != nil {
+		return nil, err
+	}
+	var domains []string
+	for domain := range oldSpecs {
+		domains = append(domains, domain)
+	}
+	for domain := range newSpecs {
+		domains = append(domains, domain)
+	}
+	for _, domain := range sortedUnion(domains) {
+		old, cur := oldSpecs[domain], newSpecs[domain]
+		dd := DomainDiffstat{Domain: domain}
+		var ids []string
+		for id := range old {
+			ids = append(ids, id)
+		}
+		for id := range cur {
+			ids = append(ids, id)
+		}
+		for _, id := range sortedUnion(ids) {
+			was, inOld := old[id]
+			is, inNew := cur[id]
+			switch {
+			case !inOld:
+				dd.Added = append(dd.Added, id)
+			case !inNew:
+				dd.Removed = append(dd.Removed, id)
+			case was != is:
+				dd.Modified = append(dd.Modified, id)
+			}
+		}
+		if len(dd.Added)+len(dd.Modified)+len(dd.Removed) > 0 {
+			d.Domains = append(d.Domains, dd)
+		}
+	}
+
+	oldTasks, err := goalTasks(before)
+	if err != nil {
+		return nil, err
+	}
+	newTasks, err := goalTasks(after)
+	if err != nil {
+		return nil, err
+	}
+	var goals []string
+	for goal := range newTasks {
+		goals = append(goals, goal)
+	}
+	for _, goal := range sortedUnion(goals) {
+		var ids []string
+		for id := range newTasks[goal] {
+			ids = append(ids, id)
+		}
+		for _, id := range sortedUnion(ids) {
+			t := newTasks[goal][id]
+			was, existed := oldTasks[goal][id]
+			ref := DiffstatTask{GoalID: goal, ID: id, Title: t.Title}
+			if !existed {
+				d.TasksAdded = append(d.TasksAdded, ref)
+			}
+			if isTaskClosed(t) && (!existed || !isTaskClosed(was)) {
+				d.TasksCompleted = append(d.TasksCompleted, ref)
+			}
+		}
+	}
+
+	oldChanges, err := changesByID(before)
+	if err != nil {
+		return nil, err
+	}
+	newChanges, err := changesByID(after)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for id := range newChanges {
+		ids = append(ids, id)
+	}
+	for _, id := range sortedUnion(ids) {
+		ch := newChanges[id]
+		if was, ok := oldChanges[id]; isChangeApplied(ch) && (!ok || !isChangeApplied(was)) {
+			d.ChangesApplied = append(d.ChangesApplied, DiffstatChange{ID: id, Title: ch.Title})
+		}
+	}
+	return d, nil
+}
+
+// FormatDiffstatMarkdown renders d as a markdown comment for a pull request.
+func FormatDiffstatMarkdown(d *Diffstat) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### teamwerx diffstat `%s`...`%s`\n\n", d.From, d.To)
+	if d.Empty() {
+		b.WriteString("No spec, plan or change updates.\n")
+		return b.String()
+	}
+	if len(d.Domains) > 0 {
+		b.WriteString("| Domain | Added | Modified | Removed |\n|---|---|---|---|\n")
+		for _, dd := range d.Domains {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", dd.Domain, diffstatCell(dd.Added), diffstatCell(dd.Modified), diffstatCell(dd.Removed))
+		}
+		b.WriteString("\n")
+	}
+	tasks := func(title string, list []DiffstatTask) {
+		if len(list) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "**%s (%d)**\n", title, len(list))
+		for _, t := range list {
+			fmt.Fprintf(&b, "- %s %s: %s\n", t.GoalID, t.ID, t.Title)
+		}
+		b.WriteString("\n")
+	}
+	tasks("Tasks added", d.TasksAdded)
+	tasks("Tasks completed", d.TasksCompleted)
+	if len(d.ChangesApplied) > 0 {
+		fmt.Fprintf(&b, "**Changes applied (%d)**\n", len(d.ChangesApplied))
+		for _, ch := range d.ChangesApplied {
+			fmt.Fprintf(&b, "- %s: %s\n", ch.ID, ch.Title)
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// diffstatCell renders a count and the IDs, e.g. "2 (`login`, `logout`)".
+func diffstatCell(ids []string) string {
+	if len(ids) == 0 {
+		return "–"
+	}
+	quoted := make([]string, len(ids))
+	for i, id := range ids {
+		quoted[i] = "`" + id + "`"
+	}
+	return fmt.Sprintf("%d (%s)", len(ids), strings.Join(quoted, ", "))
+}
+
+// specRequirementFingerprints maps each domain to its requirement
+// fingerprints by ID.
+func specRequirementFingerprints(a *App) (map[string]map[string]string, error) {
+	specs, err := a.SpecManager.ListSpecs()
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]map[string]string{}
+	for _, spec := range specs {
+		reqs := map[string]string{}
+		for _, r := range spec.Requirements {
+			reqs[r.ID] = r.Fingerprint
+		}
+		out[spec.Domain] = reqs
+	}
+	return out, nil
+}
+
+// goalTasks maps each goal to its tasks by ID.
+func goalTasks(a *App) (map[string]map[string]model.Task, error) {
+	ids, err := ListGoalIDs(a.Options)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]map[string]model.Task{}
+	for _, id := range ids {
+		plan, err := a.PlanManager.Load(id)
+		if err != nil {
+			continue
+		}
+		tasks := map[string]model.Task{}
+		for _, t := range plan.Tasks {
+			tasks[t.ID] = t
+		}
+		out[id] = tasks
+	}
+	return out, nil
+}
+
+// changesByID returns the live and archived changes of a by ID.
+func changesByID(a *App) (map[string]*model.Change, error) {
+	live, err := a.ChangeManager.ListChanges()
+	if err != nil {
+		return nil, err
+	}
+	archived, err := a.archivedChanges()
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]*model.Change{}
+	for _, ch := range append(live, archived...) {
+		out[ch.ID] = ch
+	}
+	return out, nil
+}
+
+func isTaskClosed(t model.Task) bool {
+	return t.Status == TaskStatusCompleted || t.Status == TaskStatusWaived
+}
+
+// isChangeApplied reports whether ch's deltas are in the specs: it was
+// applied, and possibly archived since.
+func isChangeApplied(ch *model.Change) bool {
+	return ch.Status == "applied" || ch.Status == "archived"
+}
+
+// sortedUnion returns ids without duplicates, in natural order.
+func sortedUnion(ids []string) []string {
+	seen := map[string]bool{}
+	out := []string{}
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return compareNatural(out[i], out[j]) < 0 })
+	return out
+}
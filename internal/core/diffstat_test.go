@@ -0,0 +1,62 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestComputeDiffstat(t *testing.T) {
+	snapshot := func(spec string, tasks []model.Task, changes ...*model.Change) *App {
+		t.Helper()
+		app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "auth", Content: spec}); err != nil {
+			t.Fatal(err)
+		}
+		if err := app.PlanManager.Save(&model.Plan{GoalID: "001-auth", Tasks: tasks}); err != nil {
+			t.Fatal(err)
+		}
+		for _, ch := range changes {
+			if err := app.ChangeManager.Save(ch); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return app
+	}
+	before := snapshot("# Auth\n\n### Requirement: Login\n\nUsers log in.\n\n### Requirement: Reset\n\nUsers reset.\n",
+		[]model.Task{{ID: "T01", Title: "Research", Status: TaskStatusPending}},
+		&model.Change{ID: "CH-001", Title: "Logout", Status: "approved"})
+	after := snapshot("# Auth\n\n### Requirement: Login\n\nUsers must log in.\n\n> Rationale: a note only.\n\n### Requirement: Logout\n\nUsers log out.\n",
+		[]model.Task{{ID: "T01", Title: "Research", Status: TaskStatusCompleted}, {ID: "T02", Title: "Implement", Status: TaskStatusPending}},
+		&model.Change{ID: "CH-001", Title: "Logout", Status: "applied"}, &model.Change{ID: "CH-002", Title: "Draft", Status: "draft"})
+
+	d, err := ComputeDiffstat(before, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Domains) != 1 || strings.Join(d.Domains[0].Added, ",") != "logout" || strings.Join(d.Domains[0].Modified, ",") != "login" || strings.Join(d.Domains[0].Removed, ",") != "reset" {
+		t.Fatalf("domains = %+v", d.Domains)
+	}
+	if len(d.TasksAdded) != 1 || d.TasksAdded[0].ID != "T02" || len(d.TasksCompleted) != 1 || d.TasksCompleted[0].ID != "T01" {
+		t.Fatalf("tasks added %+v, completed %+v", d.TasksAdded, d.TasksCompleted)
+	}
+	if len(d.ChangesApplied) != 1 || d.ChangesApplied[0].ID != "CH-001" {
+		t.Fatalf("changes applied = %+v", d.ChangesApplied)
+	}
+
+	d.From, d.To = "main", "HEAD"
+	md := FormatDiffstatMarkdown(d)
+	for _, want := range []string{"`main`...`HEAD`", "| auth | 1 (`logout`) | 1 (`login`) | 1 (`reset`) |", "- 001-auth T02: Implement", "- CH-001: Logout"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown lacks %q:\n%s", want, md)
+		}
+	}
+	if d, _ := ComputeDiffstat(after, after); !d.Empty() {
+		t.Fatalf("expected no differences, got %+v", d)
+	}
+}
@@ -0,0 +1,192 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// Transcript formats accepted by ParseTranscript.
+const (
+	TranscriptSlack    = "slack"
+	TranscriptJSONL    = "jsonl"
+	TranscriptMarkdown = "markdown"
+)
+
+// TranscriptFormats lists every supported transcript format.
+var TranscriptFormats = []string{TranscriptSlack, TranscriptJSONL, TranscriptMarkdown}
+
+// ParseTranscript converts an exported conversation into discussion entries
+// (type "discussion", no IDs) in conversation order:
+//
+//   - slack:    a channel export, i.e. a JSON array of messages with "text", "ts"
+//     and "user_profile.real_name" / "user_name" / "user".
+//   - jsonl:    one JSON object per line with "content" (or "text"), "author"
+//     (or "role"/"user") and an optional "timestamp" (RFC 3339 or Unix seconds).
+//   - markdown: one "## <author>" heading per message, optionally followed by
+//     " (<RFC 3339 timestamp>)"; the message is the text up to the next such heading.
+func ParseTranscript(format string, data []byte) ([]model.DiscussionEntry, error) {
+	switch format {
+	case TranscriptSlack:
+		return parseSlackTranscript(data)
+	case TranscriptJSONL:
+		return parseJSONLTranscript(data)
+	case TranscriptMarkdown:
+		return parseMarkdownTranscript(data), nil
+	}
+	return nil, fmt.Errorf("unsupported transcript format %q (expected one of: %s)", format, strings.Join(TranscriptFormats, ", "))
+}
+
+type slackMessage struct {
+	Type        string `json:"type"`
+	Subtype     string `json:"subtype"`
+	User        string `json:"user"`
+	UserName    string `json:"user_name"`
+	Text        string `json:"text"`
+	Ts          string `json:"ts"`
+	UserProfile struct {
+		RealName string `json:"real_name"`
+	} `json:"user_profile"`
+}
+
+func parseSlackTranscript(data []byte) ([]model.DiscussionEntry, error) {
+	var msgs []slackMessage
+	if err := json.Unmarshal(data, &msgs); err != nil {
+		return nil, fmt.Errorf("invalid slack export: %w", err)
+	}
+	var entries []model.DiscussionEntry
+	for _, m := range msgs {
+		// Join/leave notices and other system messages carry a subtype.
+		if (m.Type != "" && m.Type != "message") || m.Subtype != "" || strings.TrimSpace(m.Text) == "" {
+			continue
+		}
+		author := firstNonEmpty(m.UserProfile.RealName, m.UserName, m.User)
+		ts, err := parseTranscriptTime(m.Ts)
+		if err != nil {
+			return nil, fmt.Errorf("invalid slack timestamp %q: %w", m.Ts, err)
+		}
+		entries = append(entries, newImportedEntry(author, m.Text, ts))
+	}
+	return entries, nil
+}
+
+func parseJSONLTranscript(data []byte) ([]model.DiscussionEntry, error) {
+	var entries []model.DiscussionEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		var rec map[string]interface{}
+		if err := json.Unmarshal(raw, &rec); err != nil {
+			return nil, fmt.Errorf("line %d: invalid JSON: %w", line, err)
+		}
+		content := firstNonEmpty(stringField(rec, "content"), stringField(rec, "text"), stringField(rec, "message"))
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+		author := firstNonEmpty(stringField(rec, "author"), stringField(rec, "role"), stringField(rec, "user"))
+		ts, err := parseTranscriptTime(firstNonEmpty(stringField(rec, "timestamp"), stringField(rec, "time"), stringField(rec, "ts")))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid timestamp: %w", line, err)
+		}
+		entries = append(entries, newImportedEntry(author, content, ts))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+var markdownSpeaker = regexp.MustCompile(`^##\s+(.+?)(?:\s+\(([^)]+)\))?\s*$`)
+
+func parseMarkdownTranscript(data []byte) []model.DiscussionEntry {
+	var entries []model.DiscussionEntry
+	var current *model.DiscussionEntry
+	var body []string
+	flush := func() {
+		if current == nil {
+			return
+		}
+		if content := strings.TrimSpace(strings.Join(body, "\n")); content != "" {
+			current.Content = content
+			entries = append(entries, *current)
+		}
+		current, body = nil, nil
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if m := markdownSpeaker.FindStringSubmatch(line); m != nil {
+			ts, err := parseTranscriptTime(m[2])
+			author := m[1]
+			if err != nil {
+				// Parenthesized text that is not a timestamp is part of the name.
+				author, ts = strings.TrimPrefix(strings.TrimSpace(line), "## "), time.Time{}
+			}
+			flush()
+			e := newImportedEntry(strings.TrimSpace(author), "", ts)
+			current = &e
+			continue
+		}
+		if current != nil {
+			body = append(body, line)
+		}
+	}
+	flush()
+	return entries
+}
+
+func newImportedEntry(author, content string, ts time.Time) model.DiscussionEntry {
+	return model.DiscussionEntry{
+		Type:      DiscussionTypeDiscussion,
+		Author:    strings.TrimSpace(author),
+		Content:   strings.TrimSpace(content),
+		Timestamp: ts,
+	}
+}
+
+// parseTranscriptTime accepts RFC 3339 or (fractional) Unix seconds. An empty
+// string yields the zero time.
+func parseTranscriptTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	secs, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected RFC 3339 or Unix seconds")
+	}
+	whole := int64(secs)
+	return time.Unix(whole, int64((secs-float64(whole))*1e9)).UTC(), nil
+}
+
+func stringField(rec map[string]interface{}, key string) string {
+	switch v := rec[key].(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,71 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTranscript_Slack(t *testing.T) {
+	data := []byte(`[
+  {"type": "message", "user": "U1", "user_profile": {"real_name": "Alice"}, "text": "Use JWT?", "ts": "1700000000.000100"},
+  {"type": "message", "subtype": "channel_join", "user": "U2", "text": "joined", "ts": "1700000001.000000"},
+  {"type": "message", "user": "U2", "text": "Yes, with rotation", "ts": "1700000060.000000"}
+]`)
+	entries, err := ParseTranscript(TranscriptSlack, data)
+	if err != nil {
+		t.Fatalf("ParseTranscript failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	if entries[0].Author != "Alice" || entries[1].Author != "U2" {
+		t.Fatalf("unexpected authors: %q, %q", entries[0].Author, entries[1].Author)
+	}
+	if !entries[1].Timestamp.Equal(time.Unix(1700000060, 0)) {
+		t.Fatalf("unexpected timestamp: %v", entries[1].Timestamp)
+	}
+}
+
+func TestParseTranscript_JSONL(t *testing.T) {
+	data := []byte(`{"role": "user", "content": "How should we cache?", "timestamp": "2024-03-01T10:00:00Z"}
+
+{"author": "assistant", "text": "Use an LRU."}
+`)
+	entries, err := ParseTranscript(TranscriptJSONL, data)
+	if err != nil {
+		t.Fatalf("ParseTranscript failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Author != "user" || entries[1].Content != "Use an LRU." {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+	if entries[0].Timestamp.IsZero() || !entries[1].Timestamp.IsZero() {
+		t.Fatalf("unexpected timestamps: %+v", entries)
+	}
+
+	if _, err := ParseTranscript(TranscriptJSONL, []byte("{not json}\n")); err == nil {
+		t.Fatal("expected error for invalid line")
+	}
+}
+
+func TestParseTranscript_Markdown(t *testing.T) {
+	data := []byte("# Chat export\n\n## Alice (2024-03-01T10:00:00Z)\n\nFirst point.\n\nSecond paragraph.\n\n## Bob (team lead)\nAgreed.\n")
+	entries, err := ParseTranscript(TranscriptMarkdown, data)
+	if err != nil {
+		t.Fatalf("ParseTranscript failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	if entries[0].Author != "Alice" || entries[0].Content != "First point.\n\nSecond paragraph." || entries[0].Timestamp.IsZero() {
+		t.Fatalf("unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].Author != "Bob (team lead)" || entries[1].Content != "Agreed." {
+		t.Fatalf("unexpected second entry: %+v", entries[1])
+	}
+}
+
+func TestParseTranscript_UnknownFormat(t *testing.T) {
+	if _, err := ParseTranscript("irc", nil); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -11,7 +13,7 @@ import (
 
 	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
 	"github.com/teamwerx/teamwerx/internal/model"
-	fileutil "github.com/teamwerx/teamwerx/internal/utils/file"
+	"github.com/teamwerx/teamwerx/internal/storage"
 	"gopkg.in/yaml.v3"
 )
 
@@ -29,69 +31,197 @@ import (
 //
 // ---
 //
-// File path convention (baseDir is typically ".teamwerx/goals"):
+// Default file path convention (baseDir is typically ".teamwerx/goals"):
 //
 //	<baseDir>/<goalID>/discuss.md
+//	<baseDir>/<goalID>/attachments/<file>
+//
+// Attachment paths are recorded relative to the directory of the discussion file.
+//
+// Once discuss.md would grow past rotateSize it is renamed to the next numbered
+// part (discuss-0001.md, discuss-0002.md, ...) and a fresh discuss.md is
+// started. Reads walk the parts in order and then discuss.md, one file at a
+// time, so a long-lived goal never needs its whole history in memory.
 type discussionManager struct {
-	baseDir string
+	baseDir    string
+	layout     Layout
+	store      storage.Storage
+	secrets    *SecretScanner
+	types      *DiscussionTypeRegistry
+	rotateSize int64 // zero never rotates
 }
 
 // NewDiscussionManager constructs a DiscussionManager persisting to baseDir/<goalID>/discuss.md.
 func NewDiscussionManager(baseDir string) DiscussionManager {
-	return &discussionManager{baseDir: baseDir}
+	return NewDiscussionManagerWithLayout(baseDir, DefaultLayout())
+}
+
+// NewDiscussionManagerWithLayout constructs a DiscussionManager using layout.DiscussionFile
+// and layout.AttachmentsDir.
+func NewDiscussionManagerWithLayout(baseDir string, layout Layout) DiscussionManager {
+	return newDiscussionManager(baseDir, layout, storage.OS(), nil, nil, DefaultDiscussionRotateSize)
+}
+
+func newDiscussionManager(baseDir string, layout Layout, store storage.Storage, secrets *SecretScanner, types *DiscussionTypeRegistry, rotateSize int64) DiscussionManager {
+	return &discussionManager{baseDir: baseDir, layout: layout.WithDefaults(), store: store, secrets: secrets, types: types, rotateSize: rotateSize}
 }
 
 func (m *discussionManager) discussionPath(goalID string) string {
-	return filepath.Join(m.baseDir, goalID, "discuss.md")
+	return m.layout.DiscussionPath(m.baseDir, goalID)
+}
+
+func (m *discussionManager) attachmentsDir(goalID string) string {
+	return m.layout.AttachmentsPath(m.baseDir, goalID)
 }
 
-// Load reads and parses all discussion entries for the given goal.
-// If the file does not exist, it returns an empty slice and no error.
+// Load reads and parses all discussion entries for the given goal, across
+// every rotated part. If there is no discussion yet, it returns an empty slice
+// and no error. Use Each to visit entries without holding them all.
 func (m *discussionManager) Load(goalID string) ([]model.DiscussionEntry, error) {
-	if strings.TrimSpace(goalID) == "" {
-		return nil, custom_errors.NewErrConflict("goalID cannot be empty")
+	entries := []model.DiscussionEntry{}
+	err := m.Each(goalID, func(e model.DiscussionEntry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return entries, nil
+}
 
-	path := m.discussionPath(goalID)
-	data, err := fileutil.ReadFile(path)
+// Each calls fn for every discussion entry of the goal, oldest part first,
+// streaming one file at a time. An error from fn stops the walk and is
+// returned as is.
+func (m *discussionManager) Each(goalID string, fn func(model.DiscussionEntry) error) error {
+	files, err := m.Files(goalID)
 	if err != nil {
-		// If the file doesn't exist, return empty list (not an error)
-		if _, ok := err.(*custom_errors.ErrNotFound); ok {
-			return []model.DiscussionEntry{}, nil
+		return err
+	}
+	for _, path := range files {
+		if err := m.eachInFile(path, fn); err != nil {
+			return err
 		}
-		return nil, err
 	}
+	return nil
+}
 
-	entries, err := parseYAMLEntries(data)
+// Files returns the goal's discussion files in reading order: the rotated
+// parts (discuss-0001.md, ...) followed by discuss.md. Only existing files
+// are listed.
+func (m *discussionManager) Files(goalID string) ([]string, error) {
+	if strings.TrimSpace(goalID) == "" {
+		return nil, custom_errors.NewErrValidation("goalID", "cannot be empty")
+	}
+	path := m.discussionPath(goalID)
+	files, err := m.parts(path)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := m.store.Exists(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed parsing discussion file '%s': %w", path, err)
+		return nil, err
 	}
+	if ok {
+		files = append(files, path)
+	}
+	return files, nil
+}
 
-	return entries, nil
+// parts returns the rotated parts of the discussion log at path, in order.
+func (m *discussionManager) parts(path string) ([]string, error) {
+	var out []string
+	for n := 1; ; n++ {
+		part := discussionPartPath(path, n)
+		ok, err := m.store.Exists(part)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return out, nil
+		}
+		out = append(out, part)
+	}
+}
+
+func (m *discussionManager) eachInFile(path string, fn func(model.DiscussionEntry) error) error {
+	r, err := m.store.Open(path)
+	if err != nil {
+		if _, ok := err.(*custom_errors.ErrNotFound); ok {
+			return nil
+		}
+		return err
+	}
+	defer r.Close()
+	var fnErr error
+	err = scanYAMLEntries(r, func(e model.DiscussionEntry) error {
+		fnErr = fn(e)
+		return fnErr
+	})
+	if err != nil && fnErr == nil {
+		return fmt.Errorf("failed parsing discussion file '%s': %w", path, err)
+	}
+	return err
 }
 
 // AddEntry appends a new discussion entry for the given goal.
 // If entry.ID is empty, it assigns the next sequential ID using the form "DNN".
-// If entry.Timestamp is zero, it sets it to time.Now().
-// This method appends a YAML block to the bottom of the file atomically.
+// If entry.Timestamp is zero, it sets it to time.Now(). An empty entry.Type
+// means "discussion"; a type missing from the registry is an ErrConflict.
+// This method appends a YAML block to the bottom of the file atomically, holding
+// a "<file>.lock" lock so concurrent writers are serialized.
 func (m *discussionManager) AddEntry(goalID string, entry *model.DiscussionEntry) error {
-	if strings.TrimSpace(goalID) == "" {
-		return custom_errors.NewErrConflict("goalID cannot be empty")
-	}
 	if entry == nil {
 		return custom_errors.NewErrConflict("entry cannot be nil")
 	}
-	if strings.TrimSpace(entry.Type) == "" {
-		entry.Type = "discussion"
+	return m.AddEntries(goalID, []*model.DiscussionEntry{entry})
+}
+
+// AddEntries appends entries in order like AddEntry, but validates them all
+// first and reads and writes the file once under a single lock. Nothing is
+// written when any entry is rejected. When the entries would take a non-empty
+// discuss.md past the rotation size, it is first moved to the next numbered part.
+func (m *discussionManager) AddEntries(goalID string, entries []*model.DiscussionEntry) error {
+	if strings.TrimSpace(goalID) == "" {
+		return custom_errors.NewErrValidation("goalID", "cannot be empty")
 	}
-	if entry.Timestamp.IsZero() {
-		entry.Timestamp = time.Now()
+	for _, entry := range entries {
+		if entry == nil {
+			return custom_errors.NewErrConflict("entry cannot be nil")
+		}
+		entry.Type = strings.TrimSpace(entry.Type)
+		if entry.Type == "" {
+			entry.Type = DiscussionTypeDiscussion
+		}
+		if err := m.types.Validate(entry.Type); err != nil {
+			return err
+		}
+		if entry.Timestamp.IsZero() {
+			entry.Timestamp = time.Now()
+		}
+		if m.secrets != nil {
+			content, err := m.secrets.Check("discussion "+goalID, entry.Content)
+			if err != nil {
+				return err
+			}
+			entry.Content = content
+		}
+	}
+	if len(entries) == 0 {
+		return nil
 	}
 
 	path := m.discussionPath(goalID)
 
+	// Hold the lock across read-modify-write so concurrent writers (e.g. two
+	// agents) neither drop each other's entries nor assign the same ID.
+	unlock, err := m.store.Lock(path, 0)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// Read current file (if any) to compute next ID and append content.
-	existing, err := fileutil.ReadFile(path)
+	existing, err := m.store.ReadFile(path)
 	if err != nil {
 		// If not found, treat as empty file
 		if _, ok := err.(*custom_errors.ErrNotFound); ok {
@@ -101,54 +231,237 @@ func (m *discussionManager) AddEntry(goalID string, entry *model.DiscussionEntry
 		}
 	}
 
-	// Assign ID if missing
-	if strings.TrimSpace(entry.ID) == "" {
-		current, _ := parseYAMLEntries(existing) // ignore parse errors here; if malformed we still try to append
-		entry.ID = nextDiscussionID(current)
-	}
-
-	// Build YAML block for the entry
-	block, err := marshalEntryYAML(*entry)
+	current, _ := parseYAMLEntries(existing) // ignore parse errors here; if malformed we still try to append
+	parts, err := m.parts(path)
 	if err != nil {
 		return err
 	}
+	if len(current) == 0 && len(parts) > 0 {
+		// Just rotated: IDs continue from the newest part.
+		data, err := m.store.ReadFile(parts[len(parts)-1])
+		if err != nil {
+			return err
+		}
+		current, _ = parseYAMLEntries(data)
+	}
 
-	var out bytes.Buffer
-	out.Write(existing)
-	trimmed := bytes.TrimSpace(existing)
-	if len(trimmed) > 0 && !bytes.HasSuffix(existing, []byte("\n")) {
-		out.WriteString("\n")
+	blocks := make([][]byte, len(entries))
+	size := 0
+	for i, entry := range entries {
+		// Assign ID if missing
+		if strings.TrimSpace(entry.ID) == "" {
+			entry.ID = nextDiscussionID(current)
+		}
+		current = append(current, *entry)
+
+		// Build YAML block for the entry
+		block, err := marshalEntryYAML(*entry)
+		if err != nil {
+			return err
+		}
+		blocks[i] = block
+		size += len(block) + 1
 	}
-	// Ensure a single blank line separation between blocks for readability
-	if len(trimmed) > 0 {
-		out.WriteString("\n")
+
+	if m.rotateSize > 0 && len(bytes.TrimSpace(existing)) > 0 && int64(len(existing)+size) > m.rotateSize {
+		if err := m.store.Rename(path, discussionPartPath(path, len(parts)+1)); err != nil {
+			return err
+		}
+		existing = nil
 	}
-	out.Write(block)
-	// Ensure trailing newline at EOF
-	if !bytes.HasSuffix(block, []byte("\n")) {
-		out.WriteString("\n")
+
+	var out bytes.Buffer
+	out.Write(existing)
+	for _, block := range blocks {
+		written := out.Bytes()
+		trimmed := bytes.TrimSpace(written)
+		if len(trimmed) > 0 && !bytes.HasSuffix(written, []byte("\n")) {
+			out.WriteString("\n")
+		}
+		// Ensure a single blank line separation between blocks for readability
+		if len(trimmed) > 0 {
+			out.WriteString("\n")
+		}
+		out.Write(block)
+		// Ensure trailing newline at EOF
+		if !bytes.HasSuffix(block, []byte("\n")) {
+			out.WriteString("\n")
+		}
 	}
 
-	if err := fileutil.WriteFile(path, out.Bytes(), 0o644); err != nil {
+	if err := m.store.WriteFile(path, out.Bytes(), 0o644); err != nil {
 		return err
 	}
 	return nil
 }
 
+// RemoveEntry deletes the entry entryID (case-insensitive) from the goal's
+// discussion log, whichever part holds it, and returns it. Only the entry's
+// YAML block is cut; any other text in the file is kept as is. Attachments
+// stay on disk.
+func (m *discussionManager) RemoveEntry(goalID, entryID string) (*model.DiscussionEntry, error) {
+	if strings.TrimSpace(goalID) == "" {
+		return nil, custom_errors.NewErrValidation("goalID", "cannot be empty")
+	}
+
+	unlock, err := m.store.Lock(m.discussionPath(goalID), 0)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	files, err := m.Files(goalID)
+	if err != nil {
+		return nil, err
+	}
+	// Newest first: recent entries are the ones usually removed.
+	for i := len(files) - 1; i >= 0; i-- {
+		path := files[i]
+		data, err := m.store.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range entryBlocks(data) {
+			entries, _ := parseYAMLEntries(data[b.start:b.end])
+			if len(entries) != 1 || !strings.EqualFold(entries[0].ID, strings.TrimSpace(entryID)) {
+				continue
+			}
+			end := b.end
+			for end < len(data) && (data[end] == '\n' || data[end] == '\r') {
+				end++
+			}
+			out := append(append([]byte{}, data[:b.start]...), data[end:]...)
+			if err := m.store.WriteFile(path, out, 0o644); err != nil {
+				return nil, err
+			}
+			return &entries[0], nil
+		}
+	}
+	return nil, custom_errors.NewErrNotFound("discussion entry", entryID)
+}
+
+// byteSpan is a half-open byte range.
+type byteSpan struct{ start, end int }
+
+// entryBlocks returns the byte range of every "---" delimited block in a
+// discussion file, including both delimiter lines.
+func entryBlocks(data []byte) []byteSpan {
+	var blocks []byteSpan
+	start := -1
+	for pos := 0; pos < len(data); {
+		next := bytes.IndexByte(data[pos:], '\n')
+		if next < 0 {
+			next = len(data)
+		} else {
+			next += pos + 1
+		}
+		if strings.TrimSpace(string(data[pos:next])) == "---" {
+			if start < 0 {
+				start = pos
+			} else {
+				blocks = append(blocks, byteSpan{start, next})
+				start = -1
+			}
+		}
+		pos = next
+	}
+	return blocks
+}
+
+// StoreAttachment copies the file at srcPath into the goal's attachments directory
+// and returns its metadata. If a file with the same name already exists, a numeric
+// suffix is added (e.g., "log-2.txt") so earlier attachments are never overwritten.
+func (m *discussionManager) StoreAttachment(goalID, srcPath string) (*model.Attachment, error) {
+	if strings.TrimSpace(goalID) == "" {
+		return nil, custom_errors.NewErrValidation("goalID", "cannot be empty")
+	}
+	if strings.TrimSpace(srcPath) == "" {
+		return nil, custom_errors.NewErrValidation("path", "cannot be empty")
+	}
+
+	info, err := os.Stat(srcPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, custom_errors.NewErrNotFound("file", srcPath)
+		}
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, custom_errors.NewErrConflict(fmt.Sprintf("attachment is not a regular file: %s", srcPath))
+	}
+
+	name := filepath.Base(srcPath)
+	dir := m.attachmentsDir(goalID)
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	target := name
+	for i := 2; ; i++ {
+		ok, err := m.store.Exists(filepath.Join(dir, target))
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		target = fmt.Sprintf("%s-%d%s", stem, i, ext)
+	}
+
+	// The source is always a real file; only the copy goes through storage.
+	data, err := os.ReadFile(srcPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment '%s': %w", srcPath, err)
+	}
+	if err := m.store.WriteFile(filepath.Join(dir, target), data, info.Mode().Perm()); err != nil {
+		return nil, fmt.Errorf("failed to store attachment '%s': %w", srcPath, err)
+	}
+
+	rel, err := filepath.Rel(filepath.Dir(m.discussionPath(goalID)), filepath.Join(dir, target))
+	if err != nil {
+		return nil, err
+	}
+	return &model.Attachment{
+		Name: name,
+		Path: filepath.ToSlash(rel),
+		Size: info.Size(),
+	}, nil
+}
+
+// AttachmentPath returns the on-disk location of an attachment recorded for the goal.
+func (m *discussionManager) AttachmentPath(goalID string, att model.Attachment) string {
+	return filepath.Join(filepath.Dir(m.discussionPath(goalID)), filepath.FromSlash(att.Path))
+}
+
+// entryYAML is the on-disk YAML shape of a discussion entry.
+type entryYAML struct {
+	ID          string           `yaml:"id"`
+	Type        string           `yaml:"type"`
+	Author      string           `yaml:"author,omitempty"`
+	Timestamp   time.Time        `yaml:"timestamp"`
+	Content     string           `yaml:"content"`
+	TaskID      string           `yaml:"task_id,omitempty"`
+	Refs        []string         `yaml:"refs,omitempty,flow"`
+	Attachments []attachmentYAML `yaml:"attachments,omitempty"`
+}
+
+type attachmentYAML struct {
+	Name string `yaml:"name"`
+	Path string `yaml:"path"`
+	Size int64  `yaml:"size"`
+}
+
 // marshalEntryYAML serializes a DiscussionEntry into a YAML front-matter style block delimited by "---".
 func marshalEntryYAML(e model.DiscussionEntry) ([]byte, error) {
-	// Use an intermediate struct for clean YAML keys
-	type entryYAML struct {
-		ID        string    `yaml:"id"`
-		Type      string    `yaml:"type"`
-		Timestamp time.Time `yaml:"timestamp"`
-		Content   string    `yaml:"content"`
-	}
 	payload := entryYAML{
 		ID:        e.ID,
 		Type:      e.Type,
+		Author:    e.Author,
 		Timestamp: e.Timestamp,
 		Content:   e.Content,
+		TaskID:    e.TaskID,
+		Refs:      e.Refs,
+	}
+	for _, a := range e.Attachments {
+		payload.Attachments = append(payload.Attachments, attachmentYAML(a))
 	}
 
 	data, err := yaml.Marshal(&payload)
@@ -172,8 +485,20 @@ func marshalEntryYAML(e model.DiscussionEntry) ([]byte, error) {
 // and decodes each block into a DiscussionEntry.
 func parseYAMLEntries(data []byte) ([]model.DiscussionEntry, error) {
 	var entries []model.DiscussionEntry
+	err := scanYAMLEntries(bytes.NewReader(data), func(e model.DiscussionEntry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
 
-	sc := bufio.NewScanner(bytes.NewReader(data))
+// scanYAMLEntries is the streaming form of parseYAMLEntries: it calls fn for
+// each entry as soon as its block is read and stops at the first error fn returns.
+func scanYAMLEntries(r io.Reader, fn func(model.DiscussionEntry) error) error {
+	sc := bufio.NewScanner(r)
 	sc.Buffer(make([]byte, 0, 64*1024), 10*1024*1024) // allow large entries
 
 	const delim = "---"
@@ -193,22 +518,25 @@ func parseYAMLEntries(data []byte) ([]model.DiscussionEntry, error) {
 				// Closing the current block: parse it
 				b := block.Bytes()
 				// decode YAML
-				type entryYAML struct {
-					ID        string    `yaml:"id"`
-					Type      string    `yaml:"type"`
-					Timestamp time.Time `yaml:"timestamp"`
-					Content   string    `yaml:"content"`
-				}
 				var y entryYAML
 				if err := yaml.Unmarshal(b, &y); err == nil {
 					// Only accept entries with at least an ID or Content
 					if strings.TrimSpace(y.ID) != "" || strings.TrimSpace(y.Content) != "" {
-						entries = append(entries, model.DiscussionEntry{
+						entry := model.DiscussionEntry{
 							ID:        strings.TrimSpace(y.ID),
 							Type:      strings.TrimSpace(y.Type),
+							Author:    strings.TrimSpace(y.Author),
 							Content:   y.Content, // keep exact content, including newlines
 							Timestamp: y.Timestamp,
-						})
+							TaskID:    strings.TrimSpace(y.TaskID),
+							Refs:      y.Refs,
+						}
+						for _, a := range y.Attachments {
+							entry.Attachments = append(entry.Attachments, model.Attachment(a))
+						}
+						if err := fn(entry); err != nil {
+							return err
+						}
 					}
 				}
 				// Done with this block
@@ -224,18 +552,30 @@ func parseYAMLEntries(data []byte) ([]model.DiscussionEntry, error) {
 		}
 	}
 
-	if err := sc.Err(); err != nil {
-		return nil, err
-	}
+	return sc.Err()
+}
 
-	return entries, nil
+// TaskComments returns the entries attached to the given task (case-insensitive), in file order.
+func TaskComments(entries []model.DiscussionEntry, taskID string) []model.DiscussionEntry {
+	var out []model.DiscussionEntry
+	for _, e := range entries {
+		if e.TaskID != "" && strings.EqualFold(e.TaskID, taskID) {
+			out = append(out, e)
+		}
+	}
+	return out
 }
 
 // nextDiscussionID returns the next sequential ID in the form "DNN", using the
 // maximum existing numeric suffix + 1. If no entries exist, returns "D01".
 // If higher numbers exist (e.g., D120), it will not truncate; width grows as needed.
 func nextDiscussionID(entries []model.DiscussionEntry) string {
-	re := regexp.MustCompile(`^D(\d+)$`)
+	return nextEntryID("D", entries)
+}
+
+// nextEntryID is nextDiscussionID for IDs with the given prefix.
+func nextEntryID(prefix string, entries []model.DiscussionEntry) string {
+	re := regexp.MustCompile(`^` + regexp.QuoteMeta(prefix) + `(\d+)$`)
 	max := 0
 	for _, e := range entries {
 		m := re.FindStringSubmatch(strings.TrimSpace(e.ID))
@@ -250,7 +590,7 @@ func nextDiscussionID(entries []model.DiscussionEntry) string {
 	}
 	n := max + 1
 	if n < 100 {
-		return fmt.Sprintf("D%02d", n)
+		return fmt.Sprintf("%s%02d", prefix, n)
 	}
-	return fmt.Sprintf("D%d", n)
+	return fmt.Sprintf("%s%d", prefix, n)
 }
@@ -1,13 +1,17 @@
 package core
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
 )
 
 // Helper to write a discuss.md file for a goal.
@@ -201,3 +205,255 @@ func TestDiscussionManager_AddEntry_NextIDTwoDigits(t *testing.T) {
 		t.Fatalf("expected 10th entry ID D10, got %s", entries[9].ID)
 	}
 }
+
+func TestDiscussionManager_StoreAttachment_RoundTrip(t *testing.T) {
+	baseDir := createTempDir(t)
+	srcDir := createTempDir(t)
+	goalID := "001-attach"
+
+	src := filepath.Join(srcDir, "log.txt")
+	if err := os.WriteFile(src, []byte("boom\n"), 0o644); err != nil {
+		t.Fatalf("write source failed: %v", err)
+	}
+
+	m := NewDiscussionManager(baseDir)
+	first, err := m.StoreAttachment(goalID, src)
+	if err != nil {
+		t.Fatalf("StoreAttachment failed: %v", err)
+	}
+	if first.Path != "attachments/log.txt" || first.Size != 5 {
+		t.Fatalf("unexpected attachment metadata: %+v", first)
+	}
+
+	// Same name again must not overwrite the first copy.
+	second, err := m.StoreAttachment(goalID, src)
+	if err != nil {
+		t.Fatalf("StoreAttachment (second) failed: %v", err)
+	}
+	if second.Path != "attachments/log-2.txt" {
+		t.Fatalf("expected de-duplicated path, got %q", second.Path)
+	}
+
+	if err := m.AddEntry(goalID, &model.DiscussionEntry{
+		Content:     "See logs",
+		Attachments: []model.Attachment{*first, *second},
+	}); err != nil {
+		t.Fatalf("AddEntry failed: %v", err)
+	}
+
+	entries, err := m.Load(goalID)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(entries) != 1 || len(entries[0].Attachments) != 2 {
+		t.Fatalf("expected 1 entry with 2 attachments, got %+v", entries)
+	}
+	got := entries[0].Attachments[1]
+	if got.Name != "log.txt" || got.Path != second.Path {
+		t.Fatalf("attachment metadata not preserved: %+v", got)
+	}
+	if !fileExists(m.AttachmentPath(goalID, got)) {
+		t.Fatalf("expected attachment file at %s", m.AttachmentPath(goalID, got))
+	}
+}
+
+func TestDiscussionManager_StoreAttachment_MissingSource(t *testing.T) {
+	baseDir := createTempDir(t)
+	m := NewDiscussionManager(baseDir)
+
+	_, err := m.StoreAttachment("001-attach", filepath.Join(baseDir, "nope.png"))
+	if err == nil {
+		t.Fatal("expected error for missing source, got nil")
+	}
+}
+
+func TestDiscussionManager_TaskComments(t *testing.T) {
+	baseDir := createTempDir(t)
+	goalID := "001-comments"
+	m := NewDiscussionManager(baseDir)
+
+	for _, e := range []model.DiscussionEntry{
+		{Content: "Goal-level note"},
+		{Type: "comment", TaskID: "T02", Content: "blocked on API key"},
+		{Type: "comment", TaskID: "T03", Content: "other task"},
+		{Type: "comment", TaskID: "T02", Content: "unblocked"},
+	} {
+		e := e
+		if err := m.AddEntry(goalID, &e); err != nil {
+			t.Fatalf("AddEntry failed: %v", err)
+		}
+	}
+
+	entries, err := m.Load(goalID)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	comments := TaskComments(entries, "t02")
+	if len(comments) != 2 {
+		t.Fatalf("expected 2 comments for T02, got %d", len(comments))
+	}
+	if comments[0].ID != "D02" || strings.TrimSpace(comments[1].Content) != "unblocked" {
+		t.Fatalf("unexpected comments: %+v", comments)
+	}
+}
+
+func TestDiscussionManager_AddEntry_PreservesAuthorAndTimestamp(t *testing.T) {
+	baseDir := createTempDir(t)
+	dm := NewDiscussionManager(baseDir)
+	ts := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	if err := dm.AddEntry("001-import", &model.DiscussionEntry{Type: "discussion", Author: "Alice", Content: "Imported", Timestamp: ts}); err != nil {
+		t.Fatalf("AddEntry failed: %v", err)
+	}
+	entries, err := dm.Load("001-import")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Author != "Alice" || !entries[0].Timestamp.Equal(ts) {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestDiscussionManager_AddEntry_ConcurrentWritersKeepEveryEntry(t *testing.T) {
+	baseDir := createTempDir(t)
+	const writers = 25
+
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Separate managers model separate processes sharing the workspace.
+			dm := NewDiscussionManager(baseDir)
+			errs <- dm.AddEntry("001-race", &model.DiscussionEntry{Content: fmt.Sprintf("writer %d", i)})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("AddEntry failed: %v", err)
+		}
+	}
+
+	entries, err := NewDiscussionManager(baseDir).Load("001-race")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(entries) != writers {
+		t.Fatalf("expected %d entries, got %d", writers, len(entries))
+	}
+	ids := map[string]bool{}
+	contents := map[string]bool{}
+	for _, e := range entries {
+		if ids[e.ID] {
+			t.Fatalf("duplicate entry ID %s", e.ID)
+		}
+		ids[e.ID] = true
+		contents[e.Content] = true
+	}
+	if len(contents) != writers {
+		t.Fatalf("expected %d distinct entries, got %d", writers, len(contents))
+	}
+	if _, err := os.Stat(filepath.Join(baseDir, "001-race", "discuss.md.lock")); !os.IsNotExist(err) {
+		t.Fatalf("expected lock file to be released, stat err: %v", err)
+	}
+}
+
+func TestDiscussionManager_AddEntry_BreaksStaleLock(t *testing.T) {
+	baseDir := createTempDir(t)
+	lockPath := filepath.Join(baseDir, "001-stale", "discuss.md.lock")
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0o755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	if err := os.WriteFile(lockPath, []byte("12345\n"), 0o644); err != nil {
+		t.Fatalf("write lock failed: %v", err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(lockPath, old, old); err != nil {
+		t.Fatalf("chtimes failed: %v", err)
+	}
+
+	dm := NewDiscussionManager(baseDir)
+	if err := dm.AddEntry("001-stale", &model.DiscussionEntry{Content: "after crash"}); err != nil {
+		t.Fatalf("AddEntry failed: %v", err)
+	}
+}
+
+func TestDiscussionManager_RotatesAndReadsAllParts(t *testing.T) {
+	store := storage.NewInMem()
+	app, err := NewApp(AppOptions{Storage: store, DiscussionRotateSize: 300})
+	if err != nil {
+		t.Fatalf("NewApp: %v", err)
+	}
+	m := app.DiscussionManager
+	for i := 1; i <= 6; i++ {
+		if err := m.AddEntry("001-long", &model.DiscussionEntry{Content: fmt.Sprintf("entry number %d with some padding text", i)}); err != nil {
+			t.Fatalf("AddEntry %d: %v", i, err)
+		}
+	}
+
+	files, err := m.Files("001-long")
+	if err != nil {
+		t.Fatalf("Files: %v", err)
+	}
+	if len(files) < 3 || filepath.Base(files[0]) != "discuss-0001.md" || filepath.Base(files[len(files)-1]) != "discuss.md" {
+		t.Fatalf("expected rotated parts followed by discuss.md, got %v", files)
+	}
+	for _, f := range files {
+		data, _ := store.ReadFile(f)
+		if len(data) > 300 {
+			t.Fatalf("%s grew past the rotation size: %d bytes", f, len(data))
+		}
+	}
+
+	entries, err := m.Load("001-long")
+	if err != nil || len(entries) != 6 {
+		t.Fatalf("expected all 6 entries across parts, got %d (err %v)", len(entries), err)
+	}
+	for i, e := range entries {
+		if want := fmt.Sprintf("D%02d", i+1); e.ID != want {
+			t.Fatalf("entry %d: expected ID %s, got %s", i, want, e.ID)
+		}
+	}
+
+	var seen []string
+	stop := fmt.Errorf("stop")
+	err = m.Each("001-long", func(e model.DiscussionEntry) error {
+		seen = append(seen, e.ID)
+		if len(seen) == 2 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || strings.Join(seen, ",") != "D01,D02" {
+		t.Fatalf("expected Each to stop after D02 with fn's error, got %v (err %v)", seen, err)
+	}
+
+	if _, err := m.RemoveEntry("001-long", "d01"); err != nil {
+		t.Fatalf("RemoveEntry from a rotated part: %v", err)
+	}
+	if entries, _ := m.Load("001-long"); len(entries) != 5 || entries[0].ID != "D02" {
+		t.Fatalf("expected D01 removed from its part, got %+v", entries)
+	}
+
+	matches, err := app.Grep(GrepOptions{Pattern: regexp.MustCompile(`number 2\b`), Types: []string{GrepTypeDiscuss}})
+	if err != nil || len(matches) != 1 || matches[0].EntryID != "D02" || filepath.Base(matches[0].Path) != "discuss-0001.md" {
+		t.Fatalf("expected grep to find D02 in the first part, got %+v (err %v)", matches, err)
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	for in, want := range map[string]int64{"512": 512, "256KB": 256 << 10, "1mb": 1 << 20, "2 GB": 2 << 30, "off": 0} {
+		if got, err := ParseByteSize(in); err != nil || got != want {
+			t.Fatalf("ParseByteSize(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	if _, err := ParseByteSize("lots"); err == nil {
+		t.Fatalf("expected an invalid size to be rejected")
+	}
+	if err := (DiscussionConfig{RotateSize: "-1MB"}).Validate(); err == nil {
+		t.Fatalf("expected rotate_size to be validated")
+	}
+}
@@ -0,0 +1,57 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DefaultDiscussionRotateSize is the size past which a discussion log is
+// rotated when neither the options nor config.yaml set one.
+const DefaultDiscussionRotateSize = 1 << 20
+
+// ParseByteSize parses a size such as "512", "256KB", "1MB" or "2 GB".
+// Units are powers of 1024 and case-insensitive; "off" and "0" return 0.
+func ParseByteSize(s string) (int64, error) {
+	v := strings.ToUpper(strings.TrimSpace(s))
+	if v == "OFF" {
+		return 0, nil
+	}
+	mult := int64(1)
+	for _, u := range []struct {
+		suffix string
+		mult   int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(v, u.suffix) {
+			v, mult = strings.TrimSpace(strings.TrimSuffix(v, u.suffix)), u.mult
+			break
+		}
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q (want e.g. 512KB, 1MB or off)", s)
+	}
+	return n * mult, nil
+}
+
+// RotateBytes returns the configured rotation size in bytes: 0 when
+// rotate_size is unset (use the default) and -1 when it turns rotation off.
+// Validate reports malformed sizes.
+func (c DiscussionConfig) RotateBytes() int64 {
+	if strings.TrimSpace(c.RotateSize) == "" {
+		return 0
+	}
+	n, err := ParseByteSize(c.RotateSize)
+	if err != nil || n == 0 {
+		return -1
+	}
+	return n
+}
+
+// discussionPartPath returns the n-th rotated part of the discussion log at
+// path, e.g. "discuss-0001.md" for "discuss.md".
+func discussionPartPath(path string, n int) string {
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s-%04d%s", strings.TrimSuffix(path, ext), n, ext)
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// DiscussionTypeSummary marks an entry that condenses the entries before it.
+const DiscussionTypeSummary = "summary"
+
+// EntriesSinceSummary returns the entries recorded after the most recent
+// summary entry (all entries when there is no summary yet).
+func EntriesSinceSummary(entries []model.DiscussionEntry) []model.DiscussionEntry {
+	start := 0
+	for i, e := range entries {
+		if e.Type == DiscussionTypeSummary {
+			start = i + 1
+		}
+	}
+	return entries[start:]
+}
+
+// DiscussionContext returns the condensed history used when building agent
+// prompts: every summary entry, followed by the raw entries recorded since the
+// last summary. Raw entries already covered by a summary are dropped.
+func DiscussionContext(entries []model.DiscussionEntry) []model.DiscussionEntry {
+	var out []model.DiscussionEntry
+	for _, e := range entries {
+		if e.Type == DiscussionTypeSummary {
+			out = append(out, e)
+		}
+	}
+	return append(out, EntriesSinceSummary(entries)...)
+}
+
+// SummarizeDiscussion builds a template-based summary of entries: the covered
+// range, then the first line of each entry grouped by type in first-seen order.
+func SummarizeDiscussion(entries []model.DiscussionEntry) string {
+	if len(entries) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Summary of %s..%s (%d entries)\n", entries[0].ID, entries[len(entries)-1].ID, len(entries))
+
+	var order []string
+	byType := map[string][]model.DiscussionEntry{}
+	for _, e := range entries {
+		t := strings.TrimSpace(e.Type)
+		if t == "" {
+			t = DiscussionTypeDiscussion
+		}
+		if _, ok := byType[t]; !ok {
+			order = append(order, t)
+		}
+		byType[t] = append(byType[t], e)
+	}
+	for _, t := range order {
+		fmt.Fprintf(&b, "\n%s:\n", strings.ToUpper(t[:1])+t[1:])
+		for _, e := range byType[t] {
+			line := strings.SplitN(strings.TrimSpace(e.Content), "\n", 2)[0]
+			fmt.Fprintf(&b, "- %s: %s\n", e.ID, line)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// FormatDiscussionTranscript renders entries as plain text, one block per entry,
+// e.g. for piping into a summarizer plugin.
+func FormatDiscussionTranscript(entries []model.DiscussionEntry) string {
+	var b strings.Builder
+	for i, e := range entries {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "## %s [%s]", e.ID, e.Type)
+		if e.Author != "" {
+			fmt.Fprintf(&b, " @%s", e.Author)
+		}
+		if !e.Timestamp.IsZero() {
+			fmt.Fprintf(&b, " %s", e.Timestamp.Format(time.RFC3339))
+		}
+		fmt.Fprintf(&b, "\n\n%s\n", strings.TrimSpace(e.Content))
+	}
+	return b.String()
+}
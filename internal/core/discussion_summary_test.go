@@ -0,0 +1,51 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+func TestDiscussionContext_PrefersSummaries(t *testing.T) {
+	entries := []model.DiscussionEntry{
+		{ID: "D01", Type: "discussion", Content: "Start"},
+		{ID: "D02", Type: "reflection", Content: "Thoughts"},
+		{ID: "D03", Type: DiscussionTypeSummary, Content: "Summary of D01..D02"},
+		{ID: "D04", Type: "discussion", Content: "Follow-up"},
+	}
+
+	pending := EntriesSinceSummary(entries)
+	if len(pending) != 1 || pending[0].ID != "D04" {
+		t.Fatalf("expected only D04 since last summary, got %+v", pending)
+	}
+
+	ctx := DiscussionContext(entries)
+	var ids []string
+	for _, e := range ctx {
+		ids = append(ids, e.ID)
+	}
+	if got := strings.Join(ids, ","); got != "D03,D04" {
+		t.Fatalf("expected context D03,D04, got %s", got)
+	}
+}
+
+func TestSummarizeDiscussion_Template(t *testing.T) {
+	summary := SummarizeDiscussion([]model.DiscussionEntry{
+		{ID: "D01", Type: "discussion", Content: "Use JWT\nmore detail"},
+		{ID: "D02", Type: "reflection", Content: "Worked well"},
+		{ID: "D03", Type: "discussion", Content: "Rotate keys"},
+	})
+	for _, want := range []string{
+		"Summary of D01..D03 (3 entries)",
+		"Discussion:\n- D01: Use JWT\n- D03: Rotate keys",
+		"Reflection:\n- D02: Worked well",
+	} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("expected summary to contain %q, got:\n%s", want, summary)
+		}
+	}
+	if strings.Contains(summary, "more detail") {
+		t.Errorf("expected only first lines, got:\n%s", summary)
+	}
+}
@@ -0,0 +1,160 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+)
+
+// Built-in discussion entry types.
+const (
+	DiscussionTypeDiscussion      = "discussion"
+	DiscussionTypeReflection      = "reflection"
+	DiscussionTypeIssueCorrection = "issue-correction"
+	DiscussionTypeDecision        = "decision"
+	DiscussionTypeComment         = "comment"
+)
+
+// DiscussionColors are the color names an entry type may use.
+var DiscussionColors = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// DiscussionType describes one kind of discussion entry. Icon and Color are
+// used when listing entries.
+type DiscussionType struct {
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	Icon        string `yaml:"icon,omitempty" json:"icon,omitempty"`
+	Color       string `yaml:"color,omitempty" json:"color,omitempty"`
+}
+
+// DiscussionConfig is the "discussion" section of config.yaml. Configured
+// types are added to the built-in ones; a type with a built-in name replaces
+// its icon, color or description.
+//
+//	discussion:
+//	  types:
+//	    - name: risk
+//	      description: A risk to track
+//	      icon: "⚠"
+//	      color: red
+//	  rotate_size: 1MB
+//
+// RotateSize is how large discuss.md may grow before it is rotated into a
+// numbered part (discuss-0001.md, ...); "off" never rotates.
+type DiscussionConfig struct {
+	Types      []DiscussionType `yaml:"types,omitempty"`
+	RotateSize string           `yaml:"rotate_size,omitempty"`
+}
+
+// Validate checks every configured type has a name and a known color.
+func (c DiscussionConfig) Validate() error {
+	var errs custom_errors.ValidationErrors
+	seen := map[string]bool{}
+	for i, t := range c.Types {
+		field := fmt.Sprintf("discussion.types[%d]", i)
+		name := strings.TrimSpace(t.Name)
+		if name == "" || strings.ContainsAny(name, " \t\n") {
+			errs.Add(field+".name", fmt.Sprintf("%q must be a single word", t.Name))
+		} else if seen[name] {
+			errs.Add(field+".name", fmt.Sprintf("type %q is defined twice", name))
+		}
+		seen[name] = true
+		if t.Color != "" && !containsString(DiscussionColors, t.Color) {
+			errs.Add(field+".color", fmt.Sprintf("unknown color %q (want one of %s)", t.Color, strings.Join(DiscussionColors, ", ")))
+		}
+	}
+	if c.RotateSize != "" {
+		if _, err := ParseByteSize(c.RotateSize); err != nil {
+			errs.Add("discussion.rotate_size", err.Error())
+		}
+	}
+	return errs.Err()
+}
+
+var builtinDiscussionTypes = []DiscussionType{
+	{Name: DiscussionTypeDiscussion, Description: "General discussion", Icon: "•", Color: "white"},
+	{Name: DiscussionTypeReflection, Description: "Looking back on what happened", Icon: "↺", Color: "cyan"},
+	{Name: DiscussionTypeIssueCorrection, Description: "A mistake and how it was corrected", Icon: "!", Color: "red"},
+	{Name: DiscussionTypeDecision, Description: "A decision, collected by 'teamwerx decisions'", Icon: "✔", Color: "green"},
+	{Name: DiscussionTypeComment, Description: "A comment on a task", Icon: "›", Color: "yellow"},
+	{Name: DiscussionTypeSummary, Description: "A summary of the entries before it", Icon: "Σ", Color: "magenta"},
+}
+
+// DiscussionTypeRegistry is the set of entry types a workspace accepts. The
+// nil registry holds just the built-in types.
+type DiscussionTypeRegistry struct {
+	types []DiscussionType
+}
+
+// NewDiscussionTypeRegistry returns the built-in types merged with cfg.
+func NewDiscussionTypeRegistry(cfg DiscussionConfig) (*DiscussionTypeRegistry, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	r := &DiscussionTypeRegistry{types: append([]DiscussionType{}, builtinDiscussionTypes...)}
+	for _, t := range cfg.Types {
+		t.Name = strings.TrimSpace(t.Name)
+		if i := r.index(t.Name); i >= 0 {
+			base := r.types[i]
+			if t.Description == "" {
+				t.Description = base.Description
+			}
+			if t.Icon == "" {
+				t.Icon = base.Icon
+			}
+			if t.Color == "" {
+				t.Color = base.Color
+			}
+			r.types[i] = t
+			continue
+		}
+		r.types = append(r.types, t)
+	}
+	return r, nil
+}
+
+func (r *DiscussionTypeRegistry) list() []DiscussionType {
+	if r == nil {
+		return builtinDiscussionTypes
+	}
+	return r.types
+}
+
+func (r *DiscussionTypeRegistry) index(name string) int {
+	for i, t := range r.list() {
+		if t.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Types returns every registered type, built-in types first.
+func (r *DiscussionTypeRegistry) Types() []DiscussionType {
+	return append([]DiscussionType{}, r.list()...)
+}
+
+// Lookup returns the type called name. Entries written before a type was
+// removed from the config still list, so unknown names get a plain type.
+func (r *DiscussionTypeRegistry) Lookup(name string) (DiscussionType, bool) {
+	if i := r.index(name); i >= 0 {
+		return r.list()[i], true
+	}
+	return DiscussionType{Name: name, Icon: "?"}, false
+}
+
+// Validate returns an ErrValidation for the "type" field, naming the
+// accepted types, when name is not registered.
+func (r *DiscussionTypeRegistry) Validate(name string) error {
+	if _, ok := r.Lookup(name); ok {
+		return nil
+	}
+	names := make([]string, 0, len(r.list()))
+	for _, t := range r.list() {
+		names = append(names, t.Name)
+	}
+	sort.Strings(names)
+	return custom_errors.NewErrValidation("type", fmt.Sprintf("unknown discussion entry type %q (want one of %s)", name, strings.Join(names, ", ")))
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestDiscussionTypeRegistry(t *testing.T) {
+	r, err := NewDiscussionTypeRegistry(DiscussionConfig{Types: []DiscussionType{
+		{Name: "risk", Icon: "⚠", Color: "red"},
+		{Name: "decision", Color: "blue"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if risk, ok := r.Lookup("risk"); !ok || risk.Icon != "⚠" {
+		t.Fatalf("risk = %+v, %v", risk, ok)
+	}
+	if d, _ := r.Lookup("decision"); d.Color != "blue" || d.Icon != "✔" || d.Description == "" {
+		t.Fatalf("restyled decision = %+v", d)
+	}
+	if got := len(r.Types()); got != len(builtinDiscussionTypes)+1 {
+		t.Fatalf("types = %d", got)
+	}
+	if err := r.Validate("bogus"); err == nil || !strings.Contains(err.Error(), "risk") {
+		t.Fatalf("Validate(bogus) = %v", err)
+	}
+	if err := (*DiscussionTypeRegistry)(nil).Validate("reflection"); err != nil {
+		t.Fatalf("nil registry rejected a built-in type: %v", err)
+	}
+
+	for _, bad := range []DiscussionConfig{
+		{Types: []DiscussionType{{Name: "two words"}}},
+		{Types: []DiscussionType{{Name: "risk"}, {Name: "risk"}}},
+		{Types: []DiscussionType{{Name: "risk", Color: "mauve"}}},
+	} {
+		if _, err := NewDiscussionTypeRegistry(bad); err == nil {
+			t.Fatalf("expected %+v to fail", bad)
+		}
+	}
+}
+
+func TestDiscussionManager_ValidatesType(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem(), DiscussionTypes: []DiscussionType{{Name: "risk"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.DiscussionManager.AddEntry("001-demo", &model.DiscussionEntry{Type: "risk", Content: "Rate limits"}); err != nil {
+		t.Fatalf("configured type rejected: %v", err)
+	}
+	err = app.DiscussionManager.AddEntry("001-demo", &model.DiscussionEntry{Type: "bogus", Content: "x"})
+	if verr, ok := err.(*custom_errors.ErrValidation); !ok || verr.Field != "type" {
+		t.Fatalf("expected ErrValidation for unknown type, got %v", err)
+	}
+	entries, _ := app.DiscussionManager.Load("001-demo")
+	if len(entries) != 1 {
+		t.Fatalf("entries = %+v", entries)
+	}
+}
+
+func TestDecisions(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	add := func(goal, kind, content string, at time.Duration) {
+		t.Helper()
+		if err := app.DiscussionManager.AddEntry(goal, &model.DiscussionEntry{Type: kind, Content: content, Timestamp: base.Add(at), Author: "alice"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	add("001-auth", "decision", "Use JWT\nSessions are stateless.", 2*time.Hour)
+	add("001-auth", "discussion", "Which token format?", 0)
+	add("002-billing", "decision", "Bill monthly", time.Hour)
+
+	decisions, err := app.Decisions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decisions) != 2 || decisions[0].GoalID != "002-billing" || decisions[1].Entry.ID != "D01" {
+		t.Fatalf("decisions = %+v", decisions)
+	}
+	if only, _ := app.Decisions("001-auth"); len(only) != 1 || only[0].GoalID != "001-auth" {
+		t.Fatalf("filtered decisions = %+v", only)
+	}
+
+	log := FormatDecisionLog(decisions)
+	for _, want := range []string{"# Decision Log", "## Bill monthly", "## Use JWT", "_Goal 001-auth · D01 · 2026-03-01 · @alice_", "Sessions are stateless."} {
+		if !strings.Contains(log, want) {
+			t.Fatalf("log missing %q:\n%s", want, log)
+		}
+	}
+	if !strings.Contains(FormatDecisionLog(nil), "No decisions") {
+		t.Fatal("empty log should say so")
+	}
+}
@@ -0,0 +1,470 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"path"
+	"sort"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// DocsSiteOptions configure BuildDocsSite.
+type DocsSiteOptions struct {
+	// Title heads every page; empty uses the charter's title.
+	Title string
+	// OmitNotes drops requirement notes from the pages, as spec export does.
+	OmitNotes bool
+}
+
+// DocsSite is a static documentation site: page paths, "/"-separated and
+// relative to the site root, mapped to their content.
+type DocsSite struct {
+	Pages map[string][]byte
+}
+
+// Paths returns the paths of the site's pages, sorted.
+func (s *DocsSite) Paths() []string {
+	paths := make([]string, 0, len(s.Pages))
+	for p := range s.Pages {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// docsSearchEntry is one page, or section of a page, in the search index.
+type docsSearchEntry struct {
+	Title string `json:"title"`
+	Kind  string `json:"kind"`
+	URL   string `json:"url"`
+	Text  string `json:"text"`
+}
+
+// docsSite collects the pages and search entries of a site being built.
+type docsSite struct {
+	title    string
+	exporter *SpecExporter
+	pages    map[string][]byte
+	search   []docsSearchEntry
+}
+
+// docsTaskRef is a task linked to a requirement.
+type docsTaskRef struct {
+	GoalID string
+	Task   model.Task
+}
+
+// BuildDocsSite renders the workspace as a static site that works from the
+// file system or any web server:
+//
+//	index.html                         purpose, specs, goals and recent changes
+//	charter.html                       the effective charter
+//	specs/<domain>.html                a spec, requirement headings anchored
+//	specs/<domain>/<requirement>.html  a requirement with its scenarios, tasks and changes
+//	goals.html                         each goal's progress and tasks
+//	changes.html                       live and archived changes, newest first
+//	search.html, search-index.js       search over every page, in the browser
+//
+// Every page links the others through a navigation bar and search box.
+func (a *App) BuildDocsSite(opts DocsSiteOptions) (*DocsSite, error) {
+	var charter *model.Charter
+	if a.CharterManager.Exists() {
+		c, err := a.CharterManager.ReadEffective()
+		if err != nil {
+			return nil, err
+		}
+		charter = c
+	}
+	specs, err := a.SpecManager.ListSpecs()
+	if err != nil {
+		return nil, err
+	}
+	goalIDs, err := ListGoalIDs(a.Options)
+	if err != nil {
+		return nil, err
+	}
+	plans := map[string]*model.Plan{}
+	for _, id := range goalIDs {
+		plan, err := a.PlanManager.Load(id)
+		if err != nil {
+			if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+				return nil, err
+			}
+			continue
+		}
+		plans[id] = plan
+	}
+	changes, err := changesByID(a)
+	if err != nil {
+		return nil, err
+	}
+	history := make([]*model.Change, 0, len(changes))
+	for _, ch := range changes {
+		history = append(history, ch)
+	}
+	sort.Slice(history, func(i, j int) bool {
+		if !history[i].CreatedAt.Equal(history[j].CreatedAt) {
+			return history[i].CreatedAt.After(history[j].CreatedAt)
+		}
+		return compareNatural(history[i].ID, history[j].ID) > 0
+	})
+
+	title := strings.TrimSpace(opts.Title)
+	if title == "" && charter != nil {
+		title = strings.TrimSpace(charter.Title)
+	}
+	if title == "" {
+		title = "Project documentation"
+	}
+	exporter := NewSpecExporter()
+	exporter.Format = a.Options.SpecFormat
+	s := &docsSite{title: title, exporter: exporter, pages: map[string][]byte{}}
+
+	// Requirement pages exist for current requirements only; task and
+	// change links to others stay plain text.
+	known := map[string]bool{}
+	for _, spec := range specs {
+		for _, r := range spec.Requirements {
+			known[spec.Domain+"/"+r.ID] = true
+		}
+	}
+	tasks := map[string][]docsTaskRef{}
+	for _, id := range goalIDs {
+		if plan := plans[id]; plan != nil {
+			for _, t := range plan.Tasks {
+				for _, ref := range t.Requirements {
+					tasks[ref] = append(tasks[ref], docsTaskRef{GoalID: id, Task: t})
+				}
+			}
+		}
+	}
+	touched := map[string][]*model.Change{}
+	for i := len(history) - 1; i >= 0; i-- {
+		ch := history[i]
+		for _, d := range ch.SpecDeltas {
+			for _, op := range d.Operations {
+				key := d.Domain + "/" + op.Requirement.ID
+				if n := len(touched[key]); n == 0 || touched[key][n-1] != ch {
+					touched[key] = append(touched[key], ch)
+				}
+			}
+		}
+	}
+
+	if err := s.addCharter(charter); err != nil {
+		return nil, err
+	}
+	for _, spec := range specs {
+		if err := s.addSpec(a, spec, opts.OmitNotes, tasks, touched); err != nil {
+			return nil, err
+		}
+	}
+	s.addGoals(goalIDs, plans, known)
+	if err := s.addChanges(history, known); err != nil {
+		return nil, err
+	}
+	s.addIndex(charter, specs, goalIDs, plans, history)
+	if err := s.addSearch(); err != nil {
+		return nil, err
+	}
+	return &DocsSite{Pages: s.pages}, nil
+}
+
+// addPage wraps body in the site's page layout and stores it at p.
+func (s *docsSite) addPage(p, title string, body []byte) {
+	root := strings.Repeat("../", strings.Count(p, "/"))
+	var buf bytes.Buffer
+	buf.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&buf, "<title>%s &middot; %s</title>\n", html.EscapeString(title), html.EscapeString(s.title))
+	buf.WriteString("<style>body{font-family:sans-serif;max-width:52rem;margin:0 auto;padding:0 1rem 2rem;line-height:1.5}" +
+		"nav{display:flex;flex-wrap:wrap;gap:1rem;align-items:center;border-bottom:1px solid #ddd;padding:1rem 0;margin-bottom:1rem}" +
+		"nav form{margin-left:auto}h3[id]{border-top:1px solid #ddd;padding-top:.5rem}" +
+		"table{border-collapse:collapse}td,th{border:1px solid #ddd;padding:.25rem .5rem;text-align:left}.muted{color:#666}</style>\n")
+	buf.WriteString("</head>\n<body>\n<nav>\n")
+	fmt.Fprintf(&buf, "<strong><a href=\"%sindex.html\">%s</a></strong>\n", root, html.EscapeString(s.title))
+	for _, link := range [][2]string{{"charter.html", "Charter"}, {"index.html#specs", "Specs"}, {"goals.html", "Goals"}, {"changes.html", "Changes"}} {
+		fmt.Fprintf(&buf, "<a href=\"%s%s\">%s</a>\n", root, link[0], link[1])
+	}
+	fmt.Fprintf(&buf, "<form action=\"%ssearch.html\"><input type=\"search\" name=\"q\" placeholder=\"Search\"></form>\n", root)
+	buf.WriteString("</nav>\n<main>\n")
+	buf.Write(body)
+	buf.WriteString("</main>\n</body>\n</html>\n")
+	s.pages[p] = buf.Bytes()
+}
+
+// index adds an entry for url to the search index.
+func (s *docsSite) index(kind, title, url, text string) {
+	s.search = append(s.search, docsSearchEntry{Title: title, Kind: kind, URL: url, Text: strings.Join(strings.Fields(text), " ")})
+}
+
+func (s *docsSite) addCharter(charter *model.Charter) error {
+	var body bytes.Buffer
+	if charter == nil {
+		body.WriteString("<h1>Charter</h1>\n<p class=\"muted\">This workspace has no charter yet.</p>\n")
+		s.addPage("charter.html", "Charter", body.Bytes())
+		return nil
+	}
+	if charter.Version != "" {
+		fmt.Fprintf(&body, "<p class=\"muted\">Version %s</p>\n", html.EscapeString(charter.Version))
+	}
+	content, err := s.exporter.renderMarkdown([]byte(charter.Content), "specs/")
+	if err != nil {
+		return fmt.Errorf("failed to render charter: %w", err)
+	}
+	body.Write(content)
+	if len(charter.TechStack) > 0 {
+		body.WriteString("<h2 id=\"tech-stack\">Tech stack</h2>\n<ul>\n")
+		for _, t := range charter.TechStack {
+			fmt.Fprintf(&body, "<li>%s</li>\n", html.EscapeString(t))
+		}
+		body.WriteString("</ul>\n")
+	}
+	s.addPage("charter.html", "Charter", body.Bytes())
+	s.index("charter", "Charter", "charter.html", charter.Purpose+" "+charter.Content)
+	return nil
+}
+
+func (s *docsSite) addSpec(a *App, spec *model.Spec, omitNotes bool, tasks map[string][]docsTaskRef, touched map[string][]*model.Change) error {
+	specPage := path.Join("specs", SpecPageName(spec.Domain))
+	content := spec
+	if omitNotes {
+		stripped, err := specParser(a.SpecManager).Parse([]byte(StripRequirementNotes(spec)))
+		if err != nil {
+			return err
+		}
+		content = stripped
+	}
+	src := strings.TrimPrefix(content.Content, content.FrontMatter)
+	rendered, err := s.exporter.renderMarkdown([]byte(src), "")
+	if err != nil {
+		return fmt.Errorf("failed to render spec %s: %w", spec.Domain, err)
+	}
+	var body bytes.Buffer
+	if len(spec.Requirements) > 0 {
+		body.WriteString("<aside>\n<strong>Requirements</strong>\n<ul>\n")
+		for _, r := range spec.Requirements {
+			fmt.Fprintf(&body, "<li><a href=\"%s\">%s</a></li>\n", html.EscapeString(path.Join(spec.Domain, r.ID+".html")), html.EscapeString(r.Title))
+		}
+		body.WriteString("</ul>\n</aside>\n")
+	}
+	body.Write(rendered)
+	s.addPage(specPage, spec.Domain, body.Bytes())
+	s.index("spec", spec.Domain, specPage, spec.Meta.Owner+" "+strings.Join(spec.Meta.Tags, " "))
+
+	for _, r := range content.Requirements {
+		key := spec.Domain + "/" + r.ID
+		reqPage := path.Join("specs", spec.Domain, r.ID+".html")
+		block := content.Content[r.Start:r.End]
+		rendered, err := s.exporter.renderMarkdown([]byte(block), "../")
+		if err != nil {
+			return fmt.Errorf("failed to render requirement %s: %w", key, err)
+		}
+		root := strings.Repeat("../", strings.Count(reqPage, "/"))
+		var body bytes.Buffer
+		fmt.Fprintf(&body, "<p class=\"muted\"><a href=\"../%s\">%s</a> / %s</p>\n", html.EscapeString(SpecPageName(spec.Domain)), html.EscapeString(spec.Domain), html.EscapeString(r.ID))
+		body.Write(rendered)
+		if refs := tasks[key]; len(refs) > 0 {
+			body.WriteString("<h2 id=\"tasks\">Tasks</h2>\n<ul>\n")
+			for _, ref := range refs {
+				fmt.Fprintf(&body, "<li><a href=\"%sgoals.html#%s\">%s %s</a>: %s <span class=\"muted\">(%s)</span></li>\n",
+					root, html.EscapeString(ref.GoalID), html.EscapeString(ref.GoalID), html.EscapeString(ref.Task.ID),
+					html.EscapeString(ref.Task.Title), html.EscapeString(docsTaskStatus(ref.Task)))
+			}
+			body.WriteString("</ul>\n")
+		}
+		if chs := touched[key]; len(chs) > 0 {
+			body.WriteString("<h2 id=\"changes\">Changes</h2>\n<ul>\n")
+			for _, ch := range chs {
+				fmt.Fprintf(&body, "<li><a href=\"%schanges.html#%s\">%s</a>: %s <span class=\"muted\">(%s)</span></li>\n",
+					root, html.EscapeString(ch.ID), html.EscapeString(ch.ID), html.EscapeString(ch.Title), html.EscapeString(ch.Status))
+			}
+			body.WriteString("</ul>\n")
+		}
+		s.addPage(reqPage, r.Title, body.Bytes())
+		s.index("requirement", key+": "+r.Title, reqPage, block)
+	}
+	return nil
+}
+
+func (s *docsSite) addGoals(goalIDs []string, plans map[string]*model.Plan, known map[string]bool) {
+	var body bytes.Buffer
+	body.WriteString("<h1>Goals</h1>\n")
+	if len(goalIDs) == 0 {
+		body.WriteString("<p class=\"muted\">No goals yet.</p>\n")
+	}
+	for _, id := range goalIDs {
+		fmt.Fprintf(&body, "<h2 id=\"%s\">%s</h2>\n", html.EscapeString(id), html.EscapeString(id))
+		plan := plans[id]
+		if plan == nil {
+			body.WriteString("<p class=\"muted\">No plan yet.</p>\n")
+			s.index("goal", id, "goals.html#"+id, "")
+			continue
+		}
+		status := plan.Status
+		if status == "" {
+			status = "active"
+		}
+		fmt.Fprintf(&body, "<p>%s &middot; %s</p>\n", html.EscapeString(status), html.EscapeString(FormatPlanProgress(plan.Progress)))
+		var text []string
+		if len(plan.Tasks) > 0 {
+			body.WriteString("<table>\n<tr><th>Task</th><th>Title</th><th>Status</th><th>Assignee</th><th>Requirements</th></tr>\n")
+			for _, t := range plan.Tasks {
+				var reqs []string
+				for _, ref := range t.Requirements {
+					reqs = append(reqs, docsRequirementLink(ref, known))
+				}
+				fmt.Fprintf(&body, "<tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+					html.EscapeString(t.ID), html.EscapeString(t.Title), html.EscapeString(docsTaskStatus(t)),
+					html.EscapeString(t.Assignee), strings.Join(reqs, ", "))
+				text = append(text, t.Title)
+			}
+			body.WriteString("</table>\n")
+		}
+		s.index("goal", id, "goals.html#"+id, strings.Join(text, " "))
+	}
+	s.addPage("goals.html", "Goals", body.Bytes())
+}
+
+func (s *docsSite) addChanges(history []*model.Change, known map[string]bool) error {
+	var body bytes.Buffer
+	body.WriteString("<h1>Changes</h1>\n")
+	if len(history) == 0 {
+		body.WriteString("<p class=\"muted\">No changes yet.</p>\n")
+	}
+	for _, ch := range history {
+		fmt.Fprintf(&body, "<h2 id=\"%s\">%s: %s</h2>\n", html.EscapeString(ch.ID), html.EscapeString(ch.ID), html.EscapeString(ch.Title))
+		meta := []string{html.EscapeString(ch.Status)}
+		if !ch.CreatedAt.IsZero() {
+			meta = append(meta, ch.CreatedAt.Format("2006-01-02"))
+		}
+		if ch.Author != "" {
+			meta = append(meta, "by "+html.EscapeString(ch.Author))
+		}
+		if ch.GoalID != "" {
+			meta = append(meta, fmt.Sprintf("goal <a href=\"goals.html#%s\">%s</a>", html.EscapeString(ch.GoalID), html.EscapeString(ch.GoalID)))
+		}
+		if ch.Milestone != "" {
+			meta = append(meta, "milestone "+html.EscapeString(ch.Milestone))
+		}
+		fmt.Fprintf(&body, "<p class=\"muted\">%s</p>\n", strings.Join(meta, " &middot; "))
+		if strings.TrimSpace(ch.Description) != "" {
+			desc, err := s.exporter.renderMarkdown([]byte(ch.Description), "specs/")
+			if err != nil {
+				return fmt.Errorf("failed to render change %s: %w", ch.ID, err)
+			}
+			body.Write(desc)
+		}
+		body.WriteString("<ul>\n")
+		for _, d := range ch.SpecDeltas {
+			for _, op := range d.Operations {
+				fmt.Fprintf(&body, "<li>%s %s</li>\n", html.EscapeString(op.Type), docsRequirementLink(d.Domain+"/"+op.Requirement.ID, known))
+			}
+		}
+		body.WriteString("</ul>\n")
+		s.index("change", ch.ID+": "+ch.Title, "changes.html#"+ch.ID, ch.Description)
+	}
+	s.addPage("changes.html", "Changes", body.Bytes())
+	return nil
+}
+
+// recentChanges is how many changes the index page lists.
+const recentChanges = 10
+
+func (s *docsSite) addIndex(charter *model.Charter, specs []*model.Spec, goalIDs []string, plans map[string]*model.Plan, history []*model.Change) {
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "<h1>%s</h1>\n", html.EscapeString(s.title))
+	if charter != nil && strings.TrimSpace(charter.Purpose) != "" {
+		fmt.Fprintf(&body, "<p>%s</p>\n", html.EscapeString(strings.TrimSpace(charter.Purpose)))
+	}
+
+	body.WriteString("<h2 id=\"specs\">Specs</h2>\n<ul>\n")
+	for _, spec := range specs {
+		fmt.Fprintf(&body, "<li><a href=\"specs/%s\">%s</a> <span class=\"muted\">(%d requirement(s))</span></li>\n",
+			html.EscapeString(SpecPageName(spec.Domain)), html.EscapeString(spec.Domain), len(spec.Requirements))
+	}
+	body.WriteString("</ul>\n")
+
+	body.WriteString("<h2 id=\"goals\">Goals</h2>\n<ul>\n")
+	for _, id := range goalIDs {
+		progress := "no plan"
+		if plan := plans[id]; plan != nil {
+			progress = FormatPlanProgress(plan.Progress)
+		}
+		fmt.Fprintf(&body, "<li><a href=\"goals.html#%s\">%s</a> <span class=\"muted\">(%s)</span></li>\n", html.EscapeString(id), html.EscapeString(id), html.EscapeString(progress))
+	}
+	body.WriteString("</ul>\n")
+
+	body.WriteString("<h2 id=\"recent-changes\">Recent changes</h2>\n<ul>\n")
+	for i, ch := range history {
+		if i == recentChanges {
+			break
+		}
+		fmt.Fprintf(&body, "<li><a href=\"changes.html#%s\">%s</a>: %s <span class=\"muted\">(%s)</span></li>\n",
+			html.EscapeString(ch.ID), html.EscapeString(ch.ID), html.EscapeString(ch.Title), html.EscapeString(ch.Status))
+	}
+	body.WriteString("</ul>\n")
+	s.addPage("index.html", s.title, body.Bytes())
+}
+
+// docsSearchScript filters the search index by the "q" parameter: an entry
+// matches when its title or text holds every word.
+const docsSearchScript = `<script src="search-index.js"></script>
+<script>
+(function () {
+  var q = new URLSearchParams(window.location.search).get("q") || "";
+  document.getElementById("q").value = q;
+  var words = q.toLowerCase().split(/\s+/).filter(Boolean);
+  var out = document.getElementById("results");
+  if (!words.length) { return; }
+  var hits = teamwerxSearch.filter(function (e) {
+    var text = (e.title + " " + e.text).toLowerCase();
+    return words.every(function (w) { return text.indexOf(w) >= 0; });
+  });
+  document.getElementById("count").textContent = hits.length + " result(s)";
+  hits.forEach(function (e) {
+    var li = document.createElement("li");
+    var a = document.createElement("a");
+    a.href = e.url;
+    a.textContent = e.title;
+    li.appendChild(a);
+    li.appendChild(document.createTextNode(" (" + e.kind + ")"));
+    out.appendChild(li);
+  });
+})();
+</script>
+`
+
+// addSearch writes the search page and its index. The index is a script,
+// not JSON, so the page also works when opened from the file system.
+func (s *docsSite) addSearch() error {
+	data, err := json.Marshal(s.search)
+	if err != nil {
+		return err
+	}
+	s.pages["search-index.js"] = []byte("var teamwerxSearch = " + string(data) + ";\n")
+	body := "<h1>Search</h1>\n<form action=\"search.html\"><input id=\"q\" type=\"search\" name=\"q\" autofocus> <button>Search</button></form>\n" +
+		"<p id=\"count\" class=\"muted\"></p>\n<ul id=\"results\"></ul>\n" + docsSearchScript
+	s.addPage("search.html", "Search", []byte(body))
+	return nil
+}
+
+// docsRequirementLink links ref ("<domain>/<requirement-id>") to its page
+// from a page at the site root; unknown requirements are plain text.
+func docsRequirementLink(ref string, known map[string]bool) string {
+	if !known[ref] {
+		return html.EscapeString(ref)
+	}
+	return fmt.Sprintf("<a href=\"specs/%s.html\">%s</a>", html.EscapeString(ref), html.EscapeString(ref))
+}
+
+func docsTaskStatus(t model.Task) string {
+	if strings.TrimSpace(t.Status) == "" {
+		return "pending"
+	}
+	return t.Status
+}
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestBuildDocsSite(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "auth", Content: "# Auth\n\n### Requirement: Login\n\nUsers log in.\n\n> Rationale: internal.\n\n#### Scenario: Success\n\nOK.\n\n### Requirement: Logout\n\nSee [billing](../billing/spec.md#invoice).\n"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.PlanManager.Save(&model.Plan{GoalID: "001-demo", Tasks: []model.Task{
+		{ID: "T01", Title: "Build <login>", Status: "completed", Requirements: []string{"auth/login"}},
+		{ID: "T02", Title: "Gone", Requirements: []string{"auth/removed"}},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.ChangeManager.Save(&model.Change{ID: "CH-001", Title: "Tweak login", Status: "draft", GoalID: "001-demo", SpecDeltas: []model.SpecDelta{
+		{Domain: "auth", Operations: []model.DeltaOperation{{Type: "MODIFIED", Requirement: model.Requirement{ID: "login"}}}},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	site, err := app.BuildDocsSite(DocsSiteOptions{Title: "Demo", OmitNotes: true})
+	if err != nil {
+		t.Fatalf("BuildDocsSite: %v", err)
+	}
+	want := []string{"changes.html", "charter.html", "goals.html", "index.html", "search-index.js", "search.html",
+		"specs/auth.html", "specs/auth/login.html", "specs/auth/logout.html"}
+	if !reflect.DeepEqual(site.Paths(), want) {
+		t.Fatalf("paths = %v", site.Paths())
+	}
+
+	login := string(site.Pages["specs/auth/login.html"])
+	for _, s := range []string{`href="../../index.html"`, `action="../../search.html"`, `id="scenario-success"`, `href="../../goals.html#001-demo"`, `href="../../changes.html#CH-001"`, "Build &lt;login&gt;"} {
+		if !strings.Contains(login, s) {
+			t.Errorf("login page lacks %q:\n%s", s, login)
+		}
+	}
+	if strings.Contains(login, "Rationale") {
+		t.Errorf("expected notes to be omitted:\n%s", login)
+	}
+	if logout := string(site.Pages["specs/auth/logout.html"]); !strings.Contains(logout, `href="../billing.html#invoice"`) {
+		t.Errorf("expected the spec link to point at the billing page:\n%s", logout)
+	}
+
+	goals := string(site.Pages["goals.html"])
+	if !strings.Contains(goals, `<a href="specs/auth/login.html">auth/login</a>`) || strings.Contains(goals, `href="specs/auth/removed.html"`) {
+		t.Errorf("expected links to current requirements only:\n%s", goals)
+	}
+	if index := string(site.Pages["index.html"]); !strings.Contains(index, "<title>Demo &middot; Demo</title>") || !strings.Contains(index, `href="changes.html#CH-001"`) {
+		t.Errorf("unexpected index page:\n%s", index)
+	}
+	search := string(site.Pages["search-index.js"])
+	if !strings.HasPrefix(search, "var teamwerxSearch = [") || !strings.Contains(search, `"url":"specs/auth/login.html"`) {
+		t.Errorf("unexpected search index:\n%s", search)
+	}
+}
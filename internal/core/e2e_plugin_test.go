@@ -0,0 +1,54 @@
+package core
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// TestE2E_CLI_PluginDispatch verifies that unknown commands are dispatched to
+// teamwerx-<name> executables on PATH with workspace context in the environment,
+// and that `plugin list` discovers them.
+func TestE2E_CLI_PluginDispatch(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script plugins are not supported on windows")
+	}
+	t.Parallel()
+
+	repoRoot := findRepoRoot(t)
+	binPath := buildCLI(t, repoRoot)
+
+	pluginDir := t.TempDir()
+	script := "#!/bin/sh\necho \"hello $1 specs=$TEAMWERX_SPECS_DIR\"\necho \"$TEAMWERX_CONTEXT\"\n"
+	if err := os.WriteFile(filepath.Join(pluginDir, "teamwerx-hello"), []byte(script), 0o755); err != nil {
+		t.Fatalf("write plugin failed: %v", err)
+	}
+
+	work := t.TempDir()
+	env := append(os.Environ(), "TEAMWERX_CI=1", "PATH="+pluginDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cmd := exec.Command(binPath, "hello", "world")
+	cmd.Dir = work
+	cmd.Env = env
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("plugin dispatch failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(string(out), "hello world specs=") || !strings.Contains(string(out), `"goals_dir"`) {
+		t.Fatalf("unexpected plugin output:\n%s", out)
+	}
+
+	cmd = exec.Command(binPath, "plugin", "list")
+	cmd.Dir = work
+	cmd.Env = env
+	out, err = cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("plugin list failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(string(out), "- hello") {
+		t.Fatalf("expected hello plugin in listing:\n%s", out)
+	}
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestE2E_Porcelain checks the records documented in docs/porcelain.md for a
+// few commands: one record per line, the documented number of fields, and
+// tabs, newlines and backslashes inside fields escaped.
+func TestE2E_Porcelain(t *testing.T) {
+	t.Parallel()
+
+	repoRoot := findRepoRoot(t)
+	binPath := buildCLI(t, repoRoot)
+
+	tmp := t.TempDir()
+	writeFile(t, filepath.Join(tmp, ".teamwerx", "specs", "auth", "spec.md"), []byte(`# Auth Spec
+
+### Requirement: User Authentication
+
+Users must be able to authenticate with credentials.
+
+### Requirement: User Logout
+
+Users must be able to logout.
+`))
+
+	// parse splits output into records, failing on any line that is not one.
+	parse := func(out string, known ...string) [][]string {
+		t.Helper()
+		var records [][]string
+		for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+			fields := strings.Split(line, "\t")
+			ok := false
+			for _, k := range known {
+				ok = ok || fields[0] == k
+			}
+			if !ok {
+				t.Fatalf("unexpected line %q in porcelain output:\n%s", line, out)
+			}
+			records = append(records, fields)
+		}
+		return records
+	}
+
+	// spec list: a spec record followed by its requirements.
+	out := runCLIWithDir(t, binPath, tmp, []string{"--porcelain", "spec", "list"})
+	records := parse(out, "spec", "requirement")
+	if len(records) != 3 {
+		t.Fatalf("expected 1 spec and 2 requirement records, got:\n%s", out)
+	}
+	if r := records[0]; r[0] != "spec" || len(r) != 4 || r[1] != "auth" || r[3] != "2" {
+		t.Fatalf("unexpected spec record %q", r)
+	}
+	if r := records[1]; r[0] != "requirement" || len(r) != 7 || r[1] != "auth" || r[2] != "user-authentication" || r[4] != "User Authentication" {
+		t.Fatalf("unexpected requirement record %q", r)
+	}
+
+	// plan add and plan list: the task title keeps its tab, newline and
+	// backslash, escaped so the record stays on one line.
+	title := "Set up\tCI\nthen deploy \\ release"
+	escaped := `Set up\tCI\nthen deploy \\ release`
+	for _, args := range [][]string{
+		{"--porcelain", "plan", "add", "--goal", "001-demo", title},
+		{"--porcelain", "plan", "list", "--goal", "001-demo"},
+	} {
+		out = runCLIWithDir(t, binPath, tmp, args)
+		records = parse(out, "task", "goal")
+		var task []string
+		for _, r := range records {
+			if r[0] == "task" {
+				task = r
+			}
+		}
+		if len(task) != 9 || task[1] != "001-demo" || task[2] != "T01" || task[3] != "pending" || task[7] != escaped {
+			t.Fatalf("%v: unexpected task record %q in:\n%s", args, task, out)
+		}
+	}
+
+	// change create and change list: the change record has the same fields
+	// from every command.
+	for _, args := range [][]string{
+		{"--porcelain", "change", "create", "--template", "add-requirement", "--domain", "auth", "--title", "Audit\tlog", "--description", "Needed for compliance."},
+		{"--porcelain", "change", "list"},
+	} {
+		out = runCLIWithDir(t, binPath, tmp, args)
+		records = parse(out, "change", "issue")
+		var change []string
+		for _, r := range records {
+			if r[0] == "change" {
+				change = r
+			}
+		}
+		if len(change) != 8 || change[1] != "CH-001" || change[2] != "draft" || change[5] != "1" || !strings.HasSuffix(change[6], `Audit\tlog`) {
+			t.Fatalf("%v: unexpected change record %q in:\n%s", args, change, out)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestE2E_ScriptedAnswers drives prompts through the TEAMWERX_RESOLVE_STRATEGY
+// and TEAMWERX_DEFAULT_TYPE environment variables.
+func TestE2E_ScriptedAnswers(t *testing.T) {
+	t.Parallel()
+
+	repoRoot := findRepoRoot(t)
+	binPath := buildCLI(t, repoRoot)
+
+	ws := t.TempDir()
+	specPath := filepath.Join(ws, ".teamwerx", "specs", "auth", "spec.md")
+	mkdirAll(t, filepath.Dir(specPath))
+	spec := []byte("# Auth Spec\n\n### Requirement: Login\n\nAllow users to log in.\n")
+	writeFile(t, specPath, spec)
+
+	chDir := filepath.Join(ws, ".teamwerx", "changes", "CH-001")
+	mkdirAll(t, chDir)
+	writeFile(t, filepath.Join(chDir, "change.json"), []byte(`{
+  "id": "CH-001",
+  "title": "Update Login",
+  "status": "draft",
+  "created_at": "2024-01-01T00:00:00Z",
+  "spec_deltas": [{
+    "domain": "auth",
+    "base_fingerprint": "deadbeefcafebabe",
+    "operations": [{"type": "MODIFIED", "requirement": {"id": "login", "title": "Login", "content": "### Requirement: Login\n\nAllow users to log in with 2FA.\n\n"}}]
+  }]
+}
+`))
+
+	resolve := []string{"change", "resolve", "--id", "CH-001"}
+	out, err := runCLIAllowFailWithDir(t, binPath, ws, resolve, "TEAMWERX_RESOLVE_STRATEGY=merge")
+	if err == nil || !strings.Contains(out, "want one of refresh, skip, abort") {
+		t.Fatalf("expected an unknown strategy to fail, err=%v:\n%s", err, out)
+	}
+	out, err = runCLIAllowFailWithDir(t, binPath, ws, resolve, "TEAMWERX_RESOLVE_STRATEGY=abort")
+	if err == nil || !strings.Contains(out, "aborted by TEAMWERX_RESOLVE_STRATEGY") {
+		t.Fatalf("expected abort to fail, err=%v:\n%s", err, out)
+	}
+	out, err = runCLIAllowFailWithDir(t, binPath, ws, resolve, "TEAMWERX_RESOLVE_STRATEGY=skip")
+	if err != nil || !strings.Contains(out, "All deltas skipped") {
+		t.Fatalf("expected skip to apply nothing, err=%v:\n%s", err, out)
+	}
+	if got, _ := os.ReadFile(specPath); string(got) != string(spec) {
+		t.Fatalf("spec changed without a refresh:\n%s", got)
+	}
+
+	runCLIWithDir(t, binPath, ws, []string{"plan", "add", "--goal", "001-demo", "Set up CI"})
+	out, err = runCLIAllowFailWithDir(t, binPath, ws, []string{"discuss", "add", "--goal", "001-demo", "Went well"}, "TEAMWERX_DEFAULT_TYPE=Reflection")
+	if err != nil {
+		t.Fatalf("discuss add failed: %v\n%s", err, out)
+	}
+	out = runCLIWithDir(t, binPath, ws, []string{"discuss", "list", "--goal", "001-demo"})
+	if !strings.Contains(out, "reflection") {
+		t.Fatalf("expected a reflection entry:\n%s", out)
+	}
+}
@@ -38,6 +38,9 @@ func runCLI(t *testing.T, binPath string, args []string) string {
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, binPath, args...)
+	// Run outside the source tree so workspace files the CLI writes relative
+	// to the working directory (manifest, local state) land in a temp dir.
+	cmd.Dir = t.TempDir()
 	cmd.Env = append(os.Environ(), "TEAMWERX_CI=1")
 	out, err := cmd.CombinedOutput()
 	if ctx.Err() == context.DeadlineExceeded {
@@ -71,14 +74,15 @@ func runCLIWithDir(t *testing.T, binPath, dir string, args []string) string {
 
 // runCLIAllowFailWithDir executes the CLI and returns output and error without failing the test.
 // Useful for flows where failure is expected and handled by the test.
-func runCLIAllowFailWithDir(t *testing.T, binPath, dir string, args []string) (string, error) {
+// env adds KEY=value variables to the environment.
+func runCLIAllowFailWithDir(t *testing.T, binPath, dir string, args []string, env ...string) (string, error) {
 	t.Helper()
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, binPath, args...)
 	cmd.Dir = dir
-	cmd.Env = append(os.Environ(), "TEAMWERX_CI=1")
+	cmd.Env = append(append(os.Environ(), "TEAMWERX_CI=1"), env...)
 	out, err := cmd.CombinedOutput()
 	if ctx.Err() == context.DeadlineExceeded {
 		t.Fatalf("command timed out: %s %v\n%s", binPath, args, string(out))
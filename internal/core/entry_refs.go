@@ -0,0 +1,144 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// ResolveEntryRefs checks that each ref names a task in goalID's plan, a
+// pending or archived change, or a "<domain>/<requirement-id>" requirement,
+// and returns the refs spelled as the artifacts spell their IDs, without
+// duplicates. Every unknown ref is reported in one ValidationErrors.
+func (a *App) ResolveEntryRefs(goalID string, refs []string) ([]string, error) {
+	var plan *model.Plan
+	if p, err := a.PlanManager.Load(goalID); err == nil {
+		plan = p
+	} else if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+		return nil, err
+	}
+
+	var out []string
+	var errs custom_errors.ValidationErrors
+	for i, ref := range refs {
+		ref = strings.TrimSpace(ref)
+		if ref == "" {
+			continue
+		}
+		id, err := a.resolveEntryRef(plan, ref)
+		if err != nil {
+			if v := custom_errors.Validation(err); v != nil {
+				errs.Add(fmt.Sprintf("refs[%d]", i), v[0].Reason)
+				continue
+			}
+			return nil, err
+		}
+		if !containsFold(out, id) {
+			out = append(out, id)
+		}
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// resolveEntryRef returns the canonical spelling of ref. plan is the entry's
+// goal plan, or nil when the goal has none.
+func (a *App) resolveEntryRef(plan *model.Plan, ref string) (string, error) {
+	if domain, reqID, ok := strings.Cut(ref, "/"); ok {
+		spec, err := a.findSpecFold(strings.TrimSpace(domain))
+		if err != nil {
+			return "", err
+		}
+		if spec == nil {
+			return "", custom_errors.NewErrValidation("", fmt.Sprintf("%s: no spec domain %q", ref, domain))
+		}
+		// A renamed requirement's old ID resolves to its current one.
+		id, _ := ResolveRequirementAlias(spec, reqID)
+		for _, r := range spec.Requirements {
+			if r.ID == id {
+				return spec.Domain + "/" + r.ID, nil
+			}
+		}
+		return "", custom_errors.NewErrValidation("", fmt.Sprintf("%s: no requirement %q in %s", ref, reqID, spec.Domain))
+	}
+	if plan != nil {
+		if t := findTask(plan.Tasks, ref); t != nil {
+			return t.ID, nil
+		}
+	}
+	changes, err := a.ChangeManager.ListChanges()
+	if err != nil {
+		return "", err
+	}
+	for _, ch := range changes {
+		if strings.EqualFold(ch.ID, ref) {
+			return ch.ID, nil
+		}
+	}
+	o := a.Options
+	if ok, err := o.Storage.Exists(o.Layout.ChangePath(o.Layout.ArchivePath(o.ChangesDir), ref)); err != nil {
+		return "", err
+	} else if ok {
+		return ref, nil
+	}
+	return "", custom_errors.NewErrValidation("", fmt.Sprintf("%s: not a task of this goal, a change, or a <domain>/<requirement> ref", ref))
+}
+
+// findSpecFold reads the spec of domain, matched without regard to case, or
+// returns nil when there is none.
+func (a *App) findSpecFold(domain string) (*model.Spec, error) {
+	spec, err := a.SpecManager.ReadSpec(domain)
+	if err == nil {
+		return spec, nil
+	}
+	if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+		return nil, err
+	}
+	specs, err := a.SpecManager.ListSpecs()
+	if err != nil {
+		return nil, err
+	}
+	for i := range specs {
+		if strings.EqualFold(specs[i].Domain, domain) {
+			return specs[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// EntryBacklink is a discussion entry that refs an artifact.
+type EntryBacklink struct {
+	Goal  string                `json:"goal"`
+	Entry model.DiscussionEntry `json:"entry"`
+}
+
+// EntriesReferencing returns the discussion entries whose Refs include ref,
+// compared without regard to case, in goal and then log order. Task IDs
+// only mean something within a goal, so pass goalID to search one goal's
+// log; an empty goalID searches every goal.
+func (a *App) EntriesReferencing(goalID, ref string) ([]EntryBacklink, error) {
+	goals := []string{goalID}
+	if goalID == "" {
+		var err error
+		if goals, err = ListGoalIDs(a.Options); err != nil {
+			return nil, err
+		}
+	}
+	var out []EntryBacklink
+	for _, g := range goals {
+		entries, err := a.DiscussionManager.Load(g)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if containsFold(e.Refs, ref) {
+				out = append(out, EntryBacklink{Goal: g, Entry: e})
+			}
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,63 @@
+package core
+
+import (
+	"testing"
+
+	ce "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestResolveEntryRefs_AndBacklinks(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatalf("NewApp: %v", err)
+	}
+	if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "auth", Content: "# Auth\n\n### Requirement: Login\n\nUsers log in.\n"}); err != nil {
+		t.Fatalf("WriteSpec: %v", err)
+	}
+	if err := app.PlanManager.Save(&model.Plan{GoalID: "001-auth", Tasks: []model.Task{{ID: "T03", Title: "Build", Status: "pending"}}}); err != nil {
+		t.Fatalf("Save plan: %v", err)
+	}
+	archived := &model.Change{ID: "CH-001", Title: "Old"}
+	if err := app.ChangeManager.Save(archived); err != nil {
+		t.Fatalf("Save change: %v", err)
+	}
+	if err := app.ChangeManager.ArchiveChange(archived); err != nil {
+		t.Fatalf("ArchiveChange: %v", err)
+	}
+	if err := app.ChangeManager.Save(&model.Change{ID: "CH-002", Title: "New"}); err != nil {
+		t.Fatalf("Save change: %v", err)
+	}
+
+	refs, err := app.ResolveEntryRefs("001-auth", []string{"t03", "CH-001", "ch-002", "AUTH/login", "T03", " "})
+	if err != nil {
+		t.Fatalf("ResolveEntryRefs: %v", err)
+	}
+	if len(refs) != 4 || refs[0] != "T03" || refs[1] != "CH-001" || refs[2] != "CH-002" || refs[3] != "auth/login" {
+		t.Fatalf("expected canonical, deduplicated refs, got %v", refs)
+	}
+
+	_, err = app.ResolveEntryRefs("001-auth", []string{"T09", "auth/logout", "T03"})
+	if problems := ce.Validation(err); len(problems) != 2 || problems[0].Field != "refs[0]" || problems[1].Field != "refs[1]" {
+		t.Fatalf("expected both unknown refs reported, got %v", err)
+	}
+
+	entry := &model.DiscussionEntry{Type: "decision", Content: "Use passkeys", Refs: refs}
+	if err := app.DiscussionManager.AddEntry("001-auth", entry); err != nil {
+		t.Fatalf("AddEntry: %v", err)
+	}
+	if err := app.DiscussionManager.AddEntry("002-billing", &model.DiscussionEntry{Type: "discussion", Content: "Unrelated"}); err != nil {
+		t.Fatalf("AddEntry: %v", err)
+	}
+	linked, err := app.EntriesReferencing("", "auth/LOGIN")
+	if err != nil {
+		t.Fatalf("EntriesReferencing: %v", err)
+	}
+	if len(linked) != 1 || linked[0].Goal != "001-auth" || len(linked[0].Entry.Refs) != 4 {
+		t.Fatalf("expected the entry with its refs read back, got %+v", linked)
+	}
+	if linked, _ := app.EntriesReferencing("002-billing", "T03"); len(linked) != 0 {
+		t.Fatalf("expected task refs to stay within their goal, got %+v", linked)
+	}
+}
@@ -0,0 +1,382 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Hook managers InstallGitHooks can install into.
+const (
+	HookManagerGit      = "git"
+	HookManagerHusky    = "husky"
+	HookManagerLefthook = "lefthook"
+)
+
+// HookManagers lists the accepted hook managers.
+var HookManagers = []string{HookManagerGit, HookManagerHusky, HookManagerLefthook}
+
+// GitHookNames are the hooks teamwerx installs.
+var GitHookNames = []string{"pre-commit", "pre-push"}
+
+// Actions reported for each hook by InstallGitHooks and UninstallGitHooks.
+const (
+	HookInstalled = "installed"
+	HookUnchanged = "unchanged"
+	HookRemoved   = "removed"
+	HookRestored  = "restored"
+	HookAbsent    = "absent"
+)
+
+// hookMarker tags the lines and scripts teamwerx owns, so they can be
+// updated and removed without touching anything else.
+const hookMarker = "# teamwerx-hook"
+
+// hookBackupSuffix is appended to a git hook teamwerx replaced with --force.
+const hookBackupSuffix = ".pre-teamwerx"
+
+// lefthookConfigs are the lefthook config file names, in lookup order.
+var lefthookConfigs = []string{"lefthook.yml", ".lefthook.yml", "lefthook.yaml", ".lefthook.yaml"}
+
+// HookInstallOptions configures InstallGitHooks and UninstallGitHooks.
+// HooksDir is git's hooks directory (see git.HooksDir), used by the git
+// manager; husky and lefthook are configured under RepoRoot. Force lets the
+// git manager replace a hook teamwerx did not write: the old hook is kept as
+// "<hook>.pre-teamwerx", run first, and restored on uninstall.
+type HookInstallOptions struct {
+	RepoRoot string
+	HooksDir string
+	Manager  string
+	Hooks    []string
+	Force    bool
+}
+
+// HookChange is what happened to one hook.
+type HookChange struct {
+	Hook   string
+	Path   string
+	Action string
+}
+
+// DetectHookManager returns the hook manager the repository uses: lefthook
+// when it has a lefthook config, husky when it has a .husky directory, and
+// plain git hooks otherwise.
+func DetectHookManager(repoRoot string) string {
+	if lefthookConfig(repoRoot) != "" {
+		return HookManagerLefthook
+	}
+	if info, err := os.Stat(filepath.Join(repoRoot, ".husky")); err == nil && info.IsDir() {
+		return HookManagerHusky
+	}
+	return HookManagerGit
+}
+
+// GitHookCommand is the command each installed hook runs.
+func GitHookCommand(hook string) string {
+	return "teamwerx hooks run " + hook
+}
+
+// InstallGitHooks makes each hook run GitHookCommand, through the chosen
+// manager. Installing again updates teamwerx's hooks in place.
+func InstallGitHooks(opts HookInstallOptions) ([]HookChange, error) {
+	hooks, err := checkHookOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Manager == HookManagerLefthook {
+		return updateLefthook(opts.RepoRoot, hooks, true)
+	}
+	var out []HookChange
+	for _, hook := range hooks {
+		var c HookChange
+		if opts.Manager == HookManagerHusky {
+			c, err = installHuskyHook(filepath.Join(opts.RepoRoot, ".husky", hook), hook)
+		} else {
+			c, err = installGitHook(filepath.Join(opts.HooksDir, hook), hook, opts.Force)
+		}
+		if err != nil {
+			return out, err
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// UninstallGitHooks removes what InstallGitHooks added and restores any git
+// hook it replaced. Hooks teamwerx did not write are left alone.
+func UninstallGitHooks(opts HookInstallOptions) ([]HookChange, error) {
+	hooks, err := checkHookOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Manager == HookManagerLefthook {
+		return updateLefthook(opts.RepoRoot, hooks, false)
+	}
+	var out []HookChange
+	for _, hook := range hooks {
+		var c HookChange
+		if opts.Manager == HookManagerHusky {
+			c, err = uninstallHuskyHook(filepath.Join(opts.RepoRoot, ".husky", hook), hook)
+		} else {
+			c, err = uninstallGitHook(filepath.Join(opts.HooksDir, hook), hook)
+		}
+		if err != nil {
+			return out, err
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+func checkHookOptions(opts HookInstallOptions) ([]string, error) {
+	if !containsString(HookManagers, opts.Manager) {
+		return nil, custom_errors.NewErrValidation("manager", fmt.Sprintf("unknown hook manager %q (want %s)", opts.Manager, strings.Join(HookManagers, ", ")))
+	}
+	hooks := opts.Hooks
+	if len(hooks) == 0 {
+		hooks = GitHookNames
+	}
+	for _, h := range hooks {
+		if !containsString(GitHookNames, h) {
+			return nil, custom_errors.NewErrValidation("hook", fmt.Sprintf("unsupported hook %q (want %s)", h, strings.Join(GitHookNames, ", ")))
+		}
+	}
+	return hooks, nil
+}
+
+// gitHookScript is the script installed as a plain git hook. A hook replaced
+// with --force runs first, with the hook's arguments and input.
+func gitHookScript(hook string) string {
+	return fmt.Sprintf(`#!/bin/sh
+%s: installed by 'teamwerx hooks install'; remove with 'teamwerx hooks install --uninstall'.
+prev="$(dirname "$0")/%s%s"
+if [ -x "$prev" ]; then
+	"$prev" "$@" || exit $?
+fi
+exec %s
+`, hookMarker, hook, hookBackupSuffix, GitHookCommand(hook))
+}
+
+func installGitHook(path, hook string, force bool) (HookChange, error) {
+	c := HookChange{Hook: hook, Path: path, Action: HookInstalled}
+	script := gitHookScript(hook)
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil && string(data) == script:
+		c.Action = HookUnchanged
+		return c, nil
+	case err == nil && !bytes.Contains(data, []byte(hookMarker)):
+		if !force {
+			return c, custom_errors.NewErrConflict(fmt.Sprintf("%s already exists; add '%s' to it, or use --force to keep it as %s%s and run it first", path, GitHookCommand(hook), hook, hookBackupSuffix))
+		}
+		if err := os.Rename(path, path+hookBackupSuffix); err != nil {
+			return c, err
+		}
+	case err != nil && !os.IsNotExist(err):
+		return c, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return c, err
+	}
+	return c, os.WriteFile(path, []byte(script), 0o755)
+}
+
+func uninstallGitHook(path, hook string) (HookChange, error) {
+	c := HookChange{Hook: hook, Path: path, Action: HookAbsent}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) || (err == nil && !bytes.Contains(data, []byte(hookMarker))) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	if err := os.Remove(path); err != nil {
+		return c, err
+	}
+	c.Action = HookRemoved
+	if _, err := os.Stat(path + hookBackupSuffix); err == nil {
+		if err := os.Rename(path+hookBackupSuffix, path); err != nil {
+			return c, err
+		}
+		c.Action = HookRestored
+	}
+	return c, nil
+}
+
+// huskyBlock is the marked block teamwerx adds to a husky hook file.
+func huskyBlock(hook string) string {
+	return hookMarker + "\n" + GitHookCommand(hook) + "\n"
+}
+
+func installHuskyHook(path, hook string) (HookChange, error) {
+	c := HookChange{Hook: hook, Path: path, Action: HookInstalled}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return c, err
+	}
+	text := string(data)
+	if strings.Contains(text, huskyBlock(hook)) {
+		c.Action = HookUnchanged
+		return c, nil
+	}
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return c, err
+	}
+	return c, os.WriteFile(path, []byte(text+huskyBlock(hook)), 0o755)
+}
+
+func uninstallHuskyHook(path, hook string) (HookChange, error) {
+	c := HookChange{Hook: hook, Path: path, Action: HookAbsent}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	text := string(data)
+	if !strings.Contains(text, huskyBlock(hook)) {
+		return c, nil
+	}
+	c.Action = HookRemoved
+	text = strings.Replace(text, huskyBlock(hook), "", 1)
+	if strings.TrimSpace(text) == "" {
+		return c, os.Remove(path)
+	}
+	return c, os.WriteFile(path, []byte(text), 0o755)
+}
+
+func lefthookConfig(repoRoot string) string {
+	for _, name := range lefthookConfigs {
+		if _, err := os.Stat(filepath.Join(repoRoot, name)); err == nil {
+			return filepath.Join(repoRoot, name)
+		}
+	}
+	return ""
+}
+
+// updateLefthook adds (install) or removes a "teamwerx" command under each
+// hook of the lefthook config, creating lefthook.yml when there is none.
+// The file is edited as a YAML node tree so comments and the order of other
+// keys survive.
+func updateLefthook(repoRoot string, hooks []string, install bool) ([]HookChange, error) {
+	path := lefthookConfig(repoRoot)
+	if path == "" {
+		path = filepath.Join(repoRoot, lefthookConfigs[0])
+	}
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, custom_errors.NewErrValidation(path, "expected a mapping at the top level")
+	}
+
+	var out []HookChange
+	changed := false
+	for _, hook := range hooks {
+		c := HookChange{Hook: hook, Path: path}
+		run := GitHookCommand(hook)
+		if install {
+			commands := yamlMapping(yamlMapping(root, hook), "commands")
+			cmd := yamlMapping(commands, "teamwerx")
+			if v := yamlValue(cmd, "run"); v != nil && v.Value == run {
+				c.Action = HookUnchanged
+			} else {
+				yamlSet(cmd, "run", run)
+				c.Action = HookInstalled
+				changed = true
+			}
+		} else {
+			c.Action = HookAbsent
+			hookNode := yamlValue(root, hook)
+			commands := yamlValue(hookNode, "commands")
+			if yamlDelete(commands, "teamwerx") {
+				c.Action = HookRemoved
+				changed = true
+				if len(commands.Content) == 0 {
+					yamlDelete(hookNode, "commands")
+				}
+				if len(hookNode.Content) == 0 {
+					yamlDelete(root, hook)
+				}
+			}
+		}
+		out = append(out, c)
+	}
+	if !changed {
+		return out, nil
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	return out, os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// yamlValue returns the value of key in mapping m, or nil.
+func yamlValue(m *yaml.Node, key string) *yaml.Node {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// yamlMapping returns the mapping under key in m, adding an empty one when
+// the key is missing or not a mapping.
+func yamlMapping(m *yaml.Node, key string) *yaml.Node {
+	if v := yamlValue(m, key); v != nil && v.Kind == yaml.MappingNode {
+		return v
+	}
+	v := &yaml.Node{Kind: yaml.MappingNode}
+	yamlDelete(m, key)
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, v)
+	return v
+}
+
+func yamlSet(m *yaml.Node, key, value string) {
+	if v := yamlValue(m, key); v != nil {
+		*v = yaml.Node{Kind: yaml.ScalarNode, Value: value}
+		return
+	}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, &yaml.Node{Kind: yaml.ScalarNode, Value: value})
+}
+
+// yamlDelete removes key from mapping m and reports whether it was there.
+func yamlDelete(m *yaml.Node, key string) bool {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return false
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGitHooks_GitManagerKeepsExistingHook(t *testing.T) {
+	root := createTempDir(t)
+	hooksDir := filepath.Join(root, ".git", "hooks")
+	if err := os.MkdirAll(hooksDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	own := "#!/bin/sh\necho lint\n"
+	if err := os.WriteFile(filepath.Join(hooksDir, "pre-commit"), []byte(own), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	opts := HookInstallOptions{RepoRoot: root, HooksDir: hooksDir, Manager: DetectHookManager(root)}
+	if opts.Manager != HookManagerGit {
+		t.Fatalf("expected plain git hooks to be detected, got %s", opts.Manager)
+	}
+
+	if _, err := InstallGitHooks(opts); err == nil {
+		t.Fatalf("expected an existing hook to block the install without --force")
+	}
+	opts.Force = true
+	changes, err := InstallGitHooks(opts)
+	if err != nil || len(changes) != 2 || changes[0].Action != HookInstalled {
+		t.Fatalf("InstallGitHooks = %+v, %v", changes, err)
+	}
+	if data := readFile(t, filepath.Join(hooksDir, "pre-commit")); !strings.Contains(data, "exec teamwerx hooks run pre-commit") {
+		t.Fatalf("unexpected hook script:\n%s", data)
+	}
+	if got := readFile(t, filepath.Join(hooksDir, "pre-commit"+hookBackupSuffix)); got != own {
+		t.Fatalf("expected the old hook kept aside, got %q", got)
+	}
+	if changes, _ := InstallGitHooks(opts); changes[0].Action != HookUnchanged {
+		t.Fatalf("expected reinstalling to leave the hook unchanged, got %+v", changes)
+	}
+
+	changes, err = UninstallGitHooks(opts)
+	if err != nil || changes[0].Action != HookRestored || changes[1].Action != HookRemoved {
+		t.Fatalf("UninstallGitHooks = %+v, %v", changes, err)
+	}
+	if got := readFile(t, filepath.Join(hooksDir, "pre-commit")); got != own {
+		t.Fatalf("expected the old hook restored, got %q", got)
+	}
+}
+
+func TestGitHooks_HuskyAndLefthook(t *testing.T) {
+	root := createTempDir(t)
+	husky := filepath.Join(root, ".husky", "pre-commit")
+	if err := os.MkdirAll(filepath.Dir(husky), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(husky, []byte("npm test"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	opts := HookInstallOptions{RepoRoot: root, Manager: DetectHookManager(root), Hooks: []string{"pre-commit"}}
+	if opts.Manager != HookManagerHusky {
+		t.Fatalf("expected husky to be detected, got %s", opts.Manager)
+	}
+	if _, err := InstallGitHooks(opts); err != nil {
+		t.Fatalf("InstallGitHooks: %v", err)
+	}
+	if got := readFile(t, husky); got != "npm test\n# teamwerx-hook\nteamwerx hooks run pre-commit\n" {
+		t.Fatalf("unexpected husky hook %q", got)
+	}
+	if _, err := UninstallGitHooks(opts); err != nil {
+		t.Fatalf("UninstallGitHooks: %v", err)
+	}
+	if got := readFile(t, husky); got != "npm test\n" {
+		t.Fatalf("expected only the teamwerx block removed, got %q", got)
+	}
+
+	config := filepath.Join(root, "lefthook.yml")
+	orig := "# shared hooks\npre-commit:\n  commands:\n    lint:\n      run: make lint\n"
+	if err := os.WriteFile(config, []byte(orig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	opts = HookInstallOptions{RepoRoot: root, Manager: DetectHookManager(root)}
+	if opts.Manager != HookManagerLefthook {
+		t.Fatalf("expected lefthook to be detected, got %s", opts.Manager)
+	}
+	if _, err := InstallGitHooks(opts); err != nil {
+		t.Fatalf("InstallGitHooks: %v", err)
+	}
+	want := orig + "    teamwerx:\n      run: teamwerx hooks run pre-commit\npre-push:\n  commands:\n    teamwerx:\n      run: teamwerx hooks run pre-push\n"
+	if got := readFile(t, config); got != want {
+		t.Fatalf("unexpected lefthook config:\n%s", got)
+	}
+	if _, err := UninstallGitHooks(opts); err != nil {
+		t.Fatalf("UninstallGitHooks: %v", err)
+	}
+	if got := readFile(t, config); got != orig {
+		t.Fatalf("expected the original config back, got:\n%s", got)
+	}
+}
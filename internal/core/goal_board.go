@@ -0,0 +1,126 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// BoardColumns are the statuses of the board's columns, left to right.
+var BoardColumns = []string{TaskStatusPending, TaskStatusInProgress, TaskStatusReview, TaskStatusCompleted}
+
+// BoardCard is one task on the board.
+type BoardCard struct {
+	Goal     string `json:"goal"`
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Status   string `json:"status"` // the task's own status, e.g. "blocked" in the pending column
+	Assignee string `json:"assignee,omitempty"`
+}
+
+// BoardColumn holds the cards of one status with a count per assignee
+// ("" counts unassigned cards).
+type BoardColumn struct {
+	Status    string         `json:"status"`
+	Count     int            `json:"count"`
+	Assignees map[string]int `json:"assignees"`
+	Cards     []BoardCard    `json:"cards"`
+}
+
+// Board is the kanban view printed by `goal list --board`.
+type Board struct {
+	Goals   []string      `json:"goals"`
+	Columns []BoardColumn `json:"columns"`
+}
+
+// BoardOptions selects the goals on the board.
+type BoardOptions struct {
+	// Goals limits the board to these goals; empty means every goal.
+	Goals []string
+	// All keeps completed goals, which are left off by default.
+	All bool
+}
+
+// boardColumn maps a task status onto a board column: blocked tasks wait in
+// pending, waived ones are done, and unknown statuses count as pending.
+func boardColumn(status string) string {
+	switch status {
+	case TaskStatusInProgress, TaskStatusReview, TaskStatusCompleted:
+		return status
+	case TaskStatusWaived:
+		return TaskStatusCompleted
+	default:
+		return TaskStatusPending
+	}
+}
+
+// BuildBoard groups the tasks of every goal into BoardColumns, in goal then
+// plan order. Goals without a plan have no tasks and are skipped.
+func (a *App) BuildBoard(opts BoardOptions) (*Board, error) {
+	ids := opts.Goals
+	if len(ids) == 0 {
+		var err error
+		if ids, err = ListGoalIDs(a.Options); err != nil {
+			return nil, err
+		}
+	}
+
+	board := &Board{Goals: []string{}}
+	index := map[string]int{}
+	for i, status := range BoardColumns {
+		board.Columns = append(board.Columns, BoardColumn{Status: status, Assignees: map[string]int{}, Cards: []BoardCard{}})
+		index[status] = i
+	}
+	for _, id := range ids {
+		plan, err := a.PlanManager.Load(id)
+		if err != nil {
+			if _, ok := err.(*custom_errors.ErrNotFound); ok && len(opts.Goals) == 0 {
+				continue
+			}
+			return nil, fmt.Errorf("failed to load plan for %s: %w", id, err)
+		}
+		if plan.Status == PlanStatusCompleted && !opts.All {
+			continue
+		}
+		board.Goals = append(board.Goals, id)
+		for _, t := range plan.Tasks {
+			status := strings.TrimSpace(t.Status)
+			if status == "" {
+				status = TaskStatusPending
+			}
+			col := &board.Columns[index[boardColumn(status)]]
+			col.Cards = append(col.Cards, BoardCard{Goal: id, ID: t.ID, Title: t.Title, Status: status, Assignee: t.Assignee})
+			col.Count++
+			col.Assignees[t.Assignee]++
+		}
+	}
+	return board, nil
+}
+
+// AssigneeNames returns the column's assignees, busiest first, with unassigned
+// cards ("") last.
+func (c BoardColumn) AssigneeNames() []string {
+	names := make([]string, 0, len(c.Assignees))
+	for name := range c.Assignees {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := names[i], names[j]
+		if (a == "") != (b == "") {
+			return b == ""
+		}
+		if c.Assignees[a] != c.Assignees[b] {
+			return c.Assignees[a] > c.Assignees[b]
+		}
+		return compareNatural(a, b) < 0
+	})
+	return names
+}
+
+// Task returns the card as a model task, e.g. for porcelain output.
+func (c BoardCard) Task() model.Task {
+	return model.Task{ID: c.ID, Title: c.Title, Status: c.Status, Assignee: c.Assignee}
+}
@@ -0,0 +1,68 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestBuildBoard(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, plan := range []*model.Plan{
+		{GoalID: "001-auth", Tasks: []model.Task{
+			{ID: "T01", Title: "Research", Status: "completed", Assignee: "@alice"},
+			{ID: "T02", Title: "Implement", Status: "in-progress", Assignee: "@bob"},
+			{ID: "T03", Title: "Review", Status: "review", Assignee: "@alice"},
+			{ID: "T04", Title: "Docs", Status: "blocked"},
+			{ID: "T05", Title: "Legacy", Status: "waived"},
+		}},
+		{GoalID: "002-billing", Tasks: []model.Task{{ID: "T01", Title: "Invoices", Assignee: "@bob"}}},
+		{GoalID: "003-done", Status: PlanStatusCompleted, Tasks: []model.Task{{ID: "T01", Title: "Old", Status: "completed"}}},
+	} {
+		if err := app.PlanManager.Save(plan); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	board, err := app.BuildBoard(BoardOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(board.Goals, []string{"001-auth", "002-billing"}) {
+		t.Fatalf("goals = %v", board.Goals)
+	}
+	got := map[string][]string{}
+	for _, col := range board.Columns {
+		for _, c := range col.Cards {
+			got[col.Status] = append(got[col.Status], c.Goal+"/"+c.ID)
+		}
+		if col.Count != len(col.Cards) {
+			t.Errorf("%s count = %d, cards = %d", col.Status, col.Count, len(col.Cards))
+		}
+	}
+	want := map[string][]string{
+		"pending":     {"001-auth/T04", "002-billing/T01"},
+		"in-progress": {"001-auth/T02"},
+		"review":      {"001-auth/T03"},
+		"completed":   {"001-auth/T01", "001-auth/T05"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("board = %v", got)
+	}
+	if names := board.Columns[0].AssigneeNames(); !reflect.DeepEqual(names, []string{"@bob", ""}) {
+		t.Errorf("pending assignees = %q", names)
+	}
+
+	board, err = app.BuildBoard(BoardOptions{All: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(board.Goals) != 3 || board.Columns[3].Count != 3 {
+		t.Fatalf("board with completed goals = %+v", board)
+	}
+}
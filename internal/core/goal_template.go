@@ -0,0 +1,193 @@
+package core
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/utils"
+)
+
+// Built-in goal templates.
+const (
+	GoalTemplateFeature = "feature"
+	GoalTemplateBugfix  = "bugfix"
+	GoalTemplateSpike   = "spike"
+)
+
+// GoalTemplates lists the built-in goal templates.
+var GoalTemplates = []string{GoalTemplateFeature, GoalTemplateBugfix, GoalTemplateSpike}
+
+// GoalTemplate is the initial plan and discussion prompts for a new goal.
+type GoalTemplate struct {
+	Name      string
+	Tasks     []string
+	Questions []string
+}
+
+var builtinGoalTemplates = map[string]GoalTemplate{
+	GoalTemplateFeature: {
+		Name:  GoalTemplateFeature,
+		Tasks: []string{"Research the approach", "Write spec delta", "Implement", "Add tests", "Update docs"},
+		Questions: []string{
+			"What problem does this solve, and for whom?",
+			"Which spec domains and requirements does it touch?",
+			"What is explicitly out of scope?",
+			"How will we know it works?",
+		},
+	},
+	GoalTemplateBugfix: {
+		Name:  GoalTemplateBugfix,
+		Tasks: []string{"Reproduce the bug", "Write a failing test", "Fix the root cause", "Update the spec if behavior changed"},
+		Questions: []string{
+			"What is the expected behavior, and which requirement states it?",
+			"What are the exact steps to reproduce?",
+			"What is the root cause, not just the symptom?",
+			"Could the same bug exist elsewhere?",
+		},
+	},
+	GoalTemplateSpike: {
+		Name:  GoalTemplateSpike,
+		Tasks: []string{"Define the question and timebox", "Explore options", "Summarize findings and recommendation"},
+		Questions: []string{
+			"What question must this spike answer?",
+			"What is the timebox?",
+			"What options are on the table?",
+			"What would change our recommendation?",
+		},
+	},
+}
+
+// ResolveGoalTemplate returns the named template. A charter may override a
+// template's tasks and questions, or define new templates, in its frontmatter:
+//
+//	goal_templates:
+//	  feature:
+//	    tasks: [...]
+//	    questions: [...]
+//
+// Fields the charter leaves empty keep their built-in values.
+func ResolveGoalTemplate(name string, charter *model.Charter) (GoalTemplate, error) {
+	tmpl, ok := builtinGoalTemplates[name]
+	var override model.GoalTemplate
+	if charter != nil {
+		override, ok = charter.GoalTemplates[name]
+		if !ok {
+			_, ok = builtinGoalTemplates[name]
+		}
+	}
+	if !ok {
+		return GoalTemplate{}, custom_errors.NewErrNotFound("goal template", name)
+	}
+	tmpl.Name = name
+	if len(override.Tasks) > 0 {
+		tmpl.Tasks = override.Tasks
+	}
+	if len(override.Questions) > 0 {
+		tmpl.Questions = override.Questions
+	}
+	return tmpl, nil
+}
+
+// CreateGoal scaffolds goalID from tmpl: a plan with one task per template task
+// and an opening discussion entry listing the template's guiding questions.
+// It refuses to touch a goal that already has a plan or discussion.
+func CreateGoal(plans PlanManager, discussions DiscussionManager, goalID, title string, tmpl GoalTemplate) (*model.Plan, error) {
+	if strings.TrimSpace(goalID) == "" {
+		return nil, custom_errors.NewErrValidation("goalID", "cannot be empty")
+	}
+	if plan, err := plans.Load(goalID); err == nil && len(plan.Tasks) > 0 {
+		return nil, custom_errors.NewErrConflict(fmt.Sprintf("goal %s already has a plan", goalID))
+	}
+	if entries, err := discussions.Load(goalID); err == nil && len(entries) > 0 {
+		return nil, custom_errors.NewErrConflict(fmt.Sprintf("goal %s already has a discussion", goalID))
+	}
+
+	plan := &model.Plan{GoalID: goalID, Tasks: []model.Task{}}
+	for _, t := range tmpl.Tasks {
+		if _, err := plans.AddTask(plan, t); err != nil {
+			return nil, err
+		}
+	}
+	if err := plans.Save(plan); err != nil {
+		return nil, err
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "Goal created from the %s template", tmpl.Name)
+	if title = strings.TrimSpace(title); title != "" {
+		fmt.Fprintf(&buf, ": %s", title)
+	}
+	buf.WriteString("\n\nGuiding questions:\n")
+	for _, q := range tmpl.Questions {
+		fmt.Fprintf(&buf, "- %s\n", q)
+	}
+	if err := discussions.AddEntry(goalID, &model.DiscussionEntry{Type: DiscussionTypeDiscussion, Content: buf.String()}); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// goalNumberPattern matches the numeric prefix of goal IDs ("001-user-auth").
+var goalNumberPattern = regexp.MustCompile(`^(\d+)-`)
+
+// NextGoalID returns "<NNN>-<kebab title>", numbered after the highest existing goal.
+func NextGoalID(existing []string, title string) string {
+	max := 0
+	for _, id := range existing {
+		if m := goalNumberPattern.FindStringSubmatch(id); m != nil {
+			if n, err := strconv.Atoi(m[1]); err == nil && n > max {
+				max = n
+			}
+		}
+	}
+	return fmt.Sprintf("%03d-%s", max+1, utils.ToKebabCase(title))
+}
+
+// ListGoalIDs returns every goal in the workspace: goals with a plan or a
+// discussion file and, when the layout gives each goal its own directory,
+// every goal directory (even an empty one). IDs are sorted naturally.
+func ListGoalIDs(opts AppOptions) ([]string, error) {
+	o := opts.WithDefaults()
+	seen := map[string]bool{}
+	for _, tmpl := range []string{o.Layout.PlanFile, o.Layout.DiscussionFile} {
+		ids, err := findTemplateMatches(o.Storage, o.GoalsDir, tmpl, goalPlaceholder)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		for _, id := range ids {
+			seen[id] = true
+		}
+	}
+	if ownDirTemplate(o.Layout.PlanFile, goalPlaceholder) == goalPlaceholder {
+		err := o.Storage.WalkDir(o.GoalsDir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if p == o.GoalsDir || !d.IsDir() {
+				return nil
+			}
+			if !strings.HasPrefix(d.Name(), ".") {
+				seen[d.Name()] = true
+			}
+			return filepath.SkipDir
+		})
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	ids := make([]string, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return utils.NaturalLess(ids[i], ids[j]) })
+	return ids, nil
+}
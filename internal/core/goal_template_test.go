@@ -0,0 +1,91 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+func TestCreateGoal_SeedsPlanAndDiscussion(t *testing.T) {
+	goalsDir := createTempDir(t)
+	plans := NewPlanManager(goalsDir)
+	discussions := NewDiscussionManager(goalsDir)
+
+	tmpl, err := ResolveGoalTemplate(GoalTemplateBugfix, nil)
+	if err != nil {
+		t.Fatalf("ResolveGoalTemplate: %v", err)
+	}
+	if _, err := CreateGoal(plans, discussions, "002-fix-login", "Fix login", tmpl); err != nil {
+		t.Fatalf("CreateGoal: %v", err)
+	}
+
+	plan, err := plans.Load("002-fix-login")
+	if err != nil {
+		t.Fatalf("Load plan: %v", err)
+	}
+	if len(plan.Tasks) != len(tmpl.Tasks) || plan.Tasks[0].ID != "T01" || plan.Tasks[0].Title != "Reproduce the bug" {
+		t.Fatalf("unexpected tasks: %+v", plan.Tasks)
+	}
+	entries, err := discussions.Load("002-fix-login")
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one discussion entry, got %v (err %v)", entries, err)
+	}
+	if !strings.Contains(entries[0].Content, "Fix login") || !strings.Contains(entries[0].Content, "- What are the exact steps to reproduce?") {
+		t.Fatalf("discussion entry missing title or questions:\n%s", entries[0].Content)
+	}
+
+	if _, err := CreateGoal(plans, discussions, "002-fix-login", "Again", tmpl); err == nil {
+		t.Fatalf("expected CreateGoal to refuse an existing goal")
+	}
+}
+
+func TestResolveGoalTemplate_CharterOverrides(t *testing.T) {
+	charter := &model.Charter{GoalTemplates: map[string]model.GoalTemplate{
+		GoalTemplateSpike: {Questions: []string{"What is the hypothesis?"}},
+		"chore":           {Tasks: []string{"Do it"}},
+	}}
+
+	spike, err := ResolveGoalTemplate(GoalTemplateSpike, charter)
+	if err != nil {
+		t.Fatalf("spike: %v", err)
+	}
+	if len(spike.Questions) != 1 || spike.Questions[0] != "What is the hypothesis?" {
+		t.Fatalf("questions not overridden: %v", spike.Questions)
+	}
+	if len(spike.Tasks) != len(builtinGoalTemplates[GoalTemplateSpike].Tasks) {
+		t.Fatalf("tasks should keep built-in values: %v", spike.Tasks)
+	}
+
+	chore, err := ResolveGoalTemplate("chore", charter)
+	if err != nil || chore.Name != "chore" || len(chore.Tasks) != 1 {
+		t.Fatalf("charter-defined template: %+v (err %v)", chore, err)
+	}
+	if _, err := ResolveGoalTemplate("nope", charter); err == nil {
+		t.Fatalf("expected unknown template to fail")
+	}
+}
+
+func TestNextGoalID_NumbersAfterExistingGoals(t *testing.T) {
+	goalsDir := createTempDir(t)
+	if err := os.MkdirAll(filepath.Join(goalsDir, "003-empty"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	plans := NewPlanManager(goalsDir)
+	if err := plans.Save(&model.Plan{GoalID: "010-auth", Tasks: []model.Task{}}); err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := ListGoalIDs(AppOptions{GoalsDir: goalsDir})
+	if err != nil {
+		t.Fatalf("ListGoalIDs: %v", err)
+	}
+	if strings.Join(ids, ",") != "003-empty,010-auth" {
+		t.Fatalf("unexpected goals: %v", ids)
+	}
+	if got := NextGoalID(ids, "Payment Retries"); got != "011-payment-retries" {
+		t.Fatalf("NextGoalID = %q", got)
+	}
+}
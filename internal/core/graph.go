@@ -0,0 +1,263 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+)
+
+// Graph node kinds.
+const (
+	GraphGoal        = "goal"
+	GraphChange      = "change"
+	GraphDomain      = "domain"
+	GraphRequirement = "requirement"
+	GraphTask        = "task"
+)
+
+// Graph edge kinds.
+const (
+	EdgeProposes  = "proposes"   // goal -> change
+	EdgeModifies  = "modifies"   // change -> domain
+	EdgeContains  = "contains"   // domain -> requirement
+	EdgeDependsOn = "depends-on" // change -> change it must follow
+	EdgeHasTask   = "has-task"   // goal -> top-level task
+	EdgeSubtask   = "subtask"    // task -> subtask
+	EdgeLinks     = "links"      // task -> requirement
+)
+
+// GraphNode is one goal, change, domain, requirement or task. IDs are
+// "<kind>:<id>", with requirements as "requirement:<domain>/<id>" and tasks
+// as "task:<goal>/<id>".
+type GraphNode struct {
+	ID    string `json:"id"`
+	Kind  string `json:"kind"`
+	Label string `json:"label"`
+}
+
+// GraphEdge connects two node IDs.
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Kind string `json:"kind"`
+}
+
+// WorkspaceGraph is how a workspace's goals and changes map to its specs.
+type WorkspaceGraph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphOptions selects what BuildGraph includes. Goal limits the graph to
+// one goal, its changes and the domains they modify. Requirements adds each
+// shown domain's requirements; Tasks adds goal plans, subtasks and task
+// requirement links.
+type GraphOptions struct {
+	Goal         string
+	Requirements bool
+	Tasks        bool
+}
+
+// BuildGraph collects goals, pending changes and spec domains into a graph.
+// Nodes are grouped by kind and ordered naturally by ID, requirements in
+// spec order.
+func (a *App) BuildGraph(opts GraphOptions) (*WorkspaceGraph, error) {
+	g := &WorkspaceGraph{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	seen := map[string]bool{}
+	add := func(kind, id, label string) string {
+		key := kind + ":" + id
+		if !seen[key] {
+			seen[key] = true
+			g.Nodes = append(g.Nodes, GraphNode{ID: key, Kind: kind, Label: label})
+		}
+		return key
+	}
+	edges := map[GraphEdge]bool{}
+	link := func(from, to, kind string) {
+		e := GraphEdge{From: from, To: to, Kind: kind}
+		if !edges[e] {
+			edges[e] = true
+			g.Edges = append(g.Edges, e)
+		}
+	}
+
+	goals, err := ListGoalIDs(a.Options)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Goal != "" {
+		if !containsString(goals, opts.Goal) {
+			return nil, custom_errors.NewErrNotFound("goal", opts.Goal)
+		}
+		goals = []string{opts.Goal}
+	}
+	for _, id := range goals {
+		add(GraphGoal, id, id)
+	}
+
+	changes, err := a.ChangeManager.ListChanges()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(changes, func(i, j int) bool { return compareNatural(changes[i].ID, changes[j].ID) < 0 })
+	shown := map[string]bool{}
+	for _, ch := range changes {
+		if opts.Goal == "" || ch.GoalID == opts.Goal {
+			shown[ch.ID] = true
+		}
+	}
+	domains := map[string]bool{}
+	for _, ch := range changes {
+		if !shown[ch.ID] {
+			continue
+		}
+		node := add(GraphChange, ch.ID, fmt.Sprintf("%s: %s (%s)", ch.ID, ch.Title, ch.Status))
+		if ch.GoalID != "" {
+			link(add(GraphGoal, ch.GoalID, ch.GoalID), node, EdgeProposes)
+		}
+		for _, d := range ch.SpecDeltas {
+			domains[d.Domain] = true
+			link(node, add(GraphDomain, d.Domain, d.Domain), EdgeModifies)
+		}
+		for _, dep := range ch.DependsOn {
+			if shown[dep] {
+				link(node, GraphChange+":"+dep, EdgeDependsOn)
+			}
+		}
+	}
+
+	var refs requirementRefs
+	if opts.Goal == "" || opts.Requirements {
+		specs, err := a.SpecManager.ListSpecs()
+		if err != nil {
+			return nil, err
+		}
+		refs = newRequirementRefs(specs)
+		sort.Slice(specs, func(i, j int) bool { return compareNatural(specs[i].Domain, specs[j].Domain) < 0 })
+		for _, spec := range specs {
+			if opts.Goal != "" && !domains[spec.Domain] {
+				continue
+			}
+			node := add(GraphDomain, spec.Domain, spec.Domain)
+			if !opts.Requirements {
+				continue
+			}
+			for _, r := range spec.Requirements {
+				link(node, add(GraphRequirement, spec.Domain+"/"+r.ID, r.Title), EdgeContains)
+			}
+		}
+	}
+
+	if opts.Tasks {
+		for _, id := range goals {
+			plan, err := a.PlanManager.Load(id)
+			if err != nil {
+				if _, ok := err.(*custom_errors.ErrNotFound); ok {
+					continue
+				}
+				return nil, err
+			}
+			for _, t := range plan.Tasks {
+				node := add(GraphTask, id+"/"+t.ID, fmt.Sprintf("%s: %s (%s)", t.ID, t.Title, t.Status))
+				if t.ParentID != "" {
+					link(GraphTask+":"+id+"/"+t.ParentID, node, EdgeSubtask)
+				} else {
+					link(GraphGoal+":"+id, node, EdgeHasTask)
+				}
+				for _, req := range t.Requirements {
+					req = refs.resolve(req)
+					if seen[GraphRequirement+":"+req] {
+						link(node, GraphRequirement+":"+req, EdgeLinks)
+					} else if domain := strings.SplitN(req, "/", 2)[0]; seen[GraphDomain+":"+domain] {
+						link(node, GraphDomain+":"+domain, EdgeLinks)
+					}
+				}
+			}
+		}
+	}
+
+	kinds := map[string]int{GraphGoal: 0, GraphTask: 1, GraphChange: 2, GraphDomain: 3, GraphRequirement: 4}
+	sort.SliceStable(g.Nodes, func(i, j int) bool { return kinds[g.Nodes[i].Kind] < kinds[g.Nodes[j].Kind] })
+	return g, nil
+}
+
+// RenderMermaid renders g as a Mermaid flowchart for embedding in Markdown.
+func RenderMermaid(g *WorkspaceGraph) string {
+	ids := map[string]string{}
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i+1)
+		label := strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(n.Label)
+		open, close := mermaidShape(n.Kind)
+		fmt.Fprintf(&b, "  %s%s\"%s\"%s:::%s\n", ids[n.ID], open, label, close, n.Kind)
+	}
+	for _, e := range g.Edges {
+		arrow := "-->"
+		switch e.Kind {
+		case EdgeDependsOn:
+			arrow = "-. depends on .->"
+		case EdgeLinks:
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&b, "  %s %s %s\n", ids[e.From], arrow, ids[e.To])
+	}
+	b.WriteString("  classDef goal fill:#dbeafe,stroke:#1d4ed8\n")
+	b.WriteString("  classDef task fill:#f3f4f6,stroke:#6b7280\n")
+	b.WriteString("  classDef change fill:#fef3c7,stroke:#b45309\n")
+	b.WriteString("  classDef domain fill:#dcfce7,stroke:#15803d\n")
+	b.WriteString("  classDef requirement fill:#ffffff,stroke:#15803d\n")
+	return b.String()
+}
+
+func mermaidShape(kind string) (string, string) {
+	switch kind {
+	case GraphGoal:
+		return "([", "])"
+	case GraphDomain:
+		return "[(", ")]"
+	case GraphRequirement:
+		return "(", ")"
+	}
+	return "[", "]"
+}
+
+// RenderDOT renders g as a Graphviz digraph, e.g. for `dot -Tsvg`.
+func RenderDOT(g *WorkspaceGraph) string {
+	var b strings.Builder
+	b.WriteString("digraph teamwerx {\n  rankdir=LR;\n  node [fontname=\"Helvetica\" style=filled];\n")
+	for _, n := range g.Nodes {
+		shape, fill := "box", "#ffffff"
+		switch n.Kind {
+		case GraphGoal:
+			shape, fill = "ellipse", "#dbeafe"
+		case GraphTask:
+			fill = "#f3f4f6"
+		case GraphChange:
+			fill = "#fef3c7"
+		case GraphDomain:
+			shape, fill = "cylinder", "#dcfce7"
+		case GraphRequirement:
+			shape = "note"
+		}
+		fmt.Fprintf(&b, "  %s [label=%s shape=%s fillcolor=%q];\n", dotQuote(n.ID), dotQuote(n.Label), shape, fill)
+	}
+	for _, e := range g.Edges {
+		attrs := ""
+		switch e.Kind {
+		case EdgeDependsOn:
+			attrs = ` [style=dashed label="depends on"]`
+		case EdgeLinks:
+			attrs = " [style=dotted]"
+		}
+		fmt.Fprintf(&b, "  %s -> %s%s;\n", dotQuote(e.From), dotQuote(e.To), attrs)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
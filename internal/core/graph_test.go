@@ -0,0 +1,94 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestBuildGraph(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "auth", Content: "# Auth\n\n### Requirement: Login\n\nUsers log in.\n"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "billing", Content: "# Billing\n\n### Requirement: Invoice\n\nInvoices.\n"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.PlanManager.Save(&model.Plan{GoalID: "001-auth", Tasks: []model.Task{
+		{ID: "T01", Title: "Login", Status: "pending", Requirements: []string{"auth/login"}},
+		{ID: "T02", Title: "Tests", Status: "pending", ParentID: "T01"},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.PlanManager.Save(&model.Plan{GoalID: "002-billing"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, ch := range []*model.Change{
+		{ID: "CH-001", Title: "Login", Status: "draft", GoalID: "001-auth", CreatedAt: time.Now(), SpecDeltas: []model.SpecDelta{{Domain: "auth"}}},
+		{ID: "CH-002", Title: "Logout", Status: "draft", GoalID: "001-auth", CreatedAt: time.Now(), SpecDeltas: []model.SpecDelta{{Domain: "auth"}}, DependsOn: []string{"CH-001"}},
+		{ID: "CH-003", Title: "Invoices", Status: "draft", GoalID: "002-billing", CreatedAt: time.Now(), SpecDeltas: []model.SpecDelta{{Domain: "billing"}}},
+	} {
+		if err := app.ChangeManager.Save(ch); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	g, err := app.BuildGraph(GraphOptions{Goal: "001-auth", Requirements: true, Tasks: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, n := range g.Nodes {
+		ids = append(ids, n.ID)
+	}
+	want := "goal:001-auth task:001-auth/T01 task:001-auth/T02 change:CH-001 change:CH-002 domain:auth requirement:auth/login"
+	if got := strings.Join(ids, " "); got != want {
+		t.Fatalf("nodes = %s", got)
+	}
+	edges := map[GraphEdge]bool{}
+	for _, e := range g.Edges {
+		edges[e] = true
+	}
+	for _, e := range []GraphEdge{
+		{From: "goal:001-auth", To: "change:CH-001", Kind: EdgeProposes},
+		{From: "change:CH-001", To: "domain:auth", Kind: EdgeModifies},
+		{From: "change:CH-002", To: "change:CH-001", Kind: EdgeDependsOn},
+		{From: "domain:auth", To: "requirement:auth/login", Kind: EdgeContains},
+		{From: "task:001-auth/T01", To: "task:001-auth/T02", Kind: EdgeSubtask},
+		{From: "task:001-auth/T01", To: "requirement:auth/login", Kind: EdgeLinks},
+	} {
+		if !edges[e] {
+			t.Errorf("missing edge %+v", e)
+		}
+	}
+	if len(g.Edges) != 9 {
+		t.Errorf("edges = %+v", g.Edges)
+	}
+
+	if _, err := app.BuildGraph(GraphOptions{Goal: "009-missing"}); err == nil {
+		t.Fatal("expected unknown goal to fail")
+	}
+
+	// The whole workspace, without requirements or tasks.
+	g, err = app.BuildGraph(GraphOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Nodes) != 7 {
+		t.Fatalf("nodes = %+v", g.Nodes)
+	}
+	mermaid := RenderMermaid(g)
+	if !strings.HasPrefix(mermaid, "flowchart LR\n") || !strings.Contains(mermaid, `n1(["001-auth"]):::goal`) || !strings.Contains(mermaid, "n4 -. depends on .-> n3") {
+		t.Fatalf("mermaid = %s", mermaid)
+	}
+	dot := RenderDOT(g)
+	if !strings.Contains(dot, `"change:CH-002" -> "change:CH-001" [style=dashed label="depends on"];`) || !strings.HasSuffix(dot, "}\n") {
+		t.Fatalf("dot = %s", dot)
+	}
+}
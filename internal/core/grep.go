@@ -0,0 +1,343 @@
+package core
+
+import (
+	"regexp"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// Artifact types searched by Grep.
+const (
+	GrepTypeSpec    = "spec"
+	GrepTypePlan    = "plan"
+	GrepTypeDiscuss = "discuss"
+	GrepTypeChange  = "change"
+)
+
+// GrepTypes lists the artifact types Grep searches, in search order.
+var GrepTypes = []string{GrepTypeSpec, GrepTypePlan, GrepTypeDiscuss, GrepTypeChange}
+
+// GrepMatch is one match of a Grep pattern. Path, Line and Column locate it
+// in the artifact file as stored (lines and columns are 1-based, columns
+// count bytes); the other fields say which part of the artifact it is in and
+// are empty when the line is outside any requirement, task or entry.
+type GrepMatch struct {
+	Type   string `json:"type"`
+	Path   string `json:"path"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	Text   string `json:"text"`
+	Match  string `json:"match"`
+
+	Domain           string `json:"domain,omitempty"`
+	RequirementID    string `json:"requirement_id,omitempty"`
+	RequirementTitle string `json:"requirement_title,omitempty"`
+	Goal             string `json:"goal,omitempty"`
+	TaskID           string `json:"task_id,omitempty"`
+	TaskTitle        string `json:"task_title,omitempty"`
+	EntryID          string `json:"entry_id,omitempty"`
+	EntryType        string `json:"entry_type,omitempty"`
+	Author           string `json:"author,omitempty"`
+	ChangeID         string `json:"change_id,omitempty"`
+}
+
+// GrepOptions configures Grep. An empty Types searches every type.
+type GrepOptions struct {
+	Pattern *regexp.Regexp
+	Types   []string
+}
+
+// Grep searches the workspace's spec, plan, discussion and change files line
+// by line for opts.Pattern and returns every match with the requirement,
+// task, discussion entry or change delta it falls in. Matches are ordered by
+// type, then artifact, then position.
+func (a *App) Grep(opts GrepOptions) ([]GrepMatch, error) {
+	for _, t := range opts.Types {
+		if !containsFold(GrepTypes, t) {
+			return nil, custom_errors.NewErrValidation("type", "unknown artifact type "+t+" (expected "+strings.Join(GrepTypes, ", ")+")")
+		}
+	}
+	searched := func(t string) bool { return len(opts.Types) == 0 || containsFold(opts.Types, t) }
+
+	var out []GrepMatch
+	if searched(GrepTypeSpec) {
+		matches, err := a.grepSpecs(opts.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, matches...)
+	}
+	if searched(GrepTypePlan) || searched(GrepTypeDiscuss) {
+		goals, err := ListGoalIDs(a.Options)
+		if err != nil {
+			return nil, err
+		}
+		if searched(GrepTypePlan) {
+			for _, g := range goals {
+				matches, err := a.grepPlan(opts.Pattern, g)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, matches...)
+			}
+		}
+		if searched(GrepTypeDiscuss) {
+			for _, g := range goals {
+				matches, err := a.grepDiscussion(opts.Pattern, g)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, matches...)
+			}
+		}
+	}
+	if searched(GrepTypeChange) {
+		matches, err := a.grepChanges(opts.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, matches...)
+	}
+	return out, nil
+}
+
+// grepFile matches re against each line of the file at path and calls
+// annotate to fill in the context of each match. A missing file has no
+// matches.
+func (a *App) grepFile(re *regexp.Regexp, kind, path string, annotate func(m *GrepMatch)) ([]GrepMatch, error) {
+	data, err := a.Options.Storage.ReadFile(path)
+	if err != nil {
+		if _, ok := err.(*custom_errors.ErrNotFound); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var out []GrepMatch
+	for i, line := range strings.Split(string(data), "\n") {
+		for _, loc := range re.FindAllStringIndex(line, -1) {
+			if loc[0] == loc[1] && len(line) > 0 {
+				continue
+			}
+			m := GrepMatch{
+				Type:   kind,
+				Path:   path,
+				Line:   i + 1,
+				Column: loc[0] + 1,
+				Text:   strings.TrimRight(line, "\r"),
+				Match:  line[loc[0]:loc[1]],
+			}
+			annotate(&m)
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+func (a *App) grepSpecs(re *regexp.Regexp) ([]GrepMatch, error) {
+	specs, err := a.SpecManager.ListSpecs()
+	if err != nil {
+		return nil, err
+	}
+	parser := specParser(a.SpecManager)
+	var out []GrepMatch
+	for _, spec := range specs {
+		path := a.Options.Layout.SpecPath(a.Options.SpecsDir, spec.Domain)
+		data, err := a.Options.Storage.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		// Blocks cover the file byte-for-byte, so each starts where the
+		// previous one ends.
+		parsed, err := parser.Parse(data)
+		if err != nil {
+			return nil, err
+		}
+		var starts []int
+		line := 1
+		for _, b := range parsed.Blocks {
+			starts = append(starts, line)
+			line += strings.Count(b.Content, "\n")
+		}
+		domain := spec.Domain
+		matches, err := a.grepFile(re, GrepTypeSpec, path, func(m *GrepMatch) {
+			m.Domain = domain
+			for i := len(starts) - 1; i >= 0; i-- {
+				if starts[i] <= m.Line {
+					if b := parsed.Blocks[i]; b.Kind == model.SpecBlockRequirement {
+						m.RequirementID, m.RequirementTitle = b.RequirementID, b.Title
+					}
+					break
+				}
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, matches...)
+	}
+	return out, nil
+}
+
+func (a *App) grepPlan(re *regexp.Regexp, goalID string) ([]GrepMatch, error) {
+	plan, err := a.PlanManager.Load(goalID)
+	if err != nil {
+		if _, ok := err.(*custom_errors.ErrNotFound); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+	path := a.Options.Layout.PlanPath(a.Options.GoalsDir, goalID)
+	ids := make([]string, len(plan.Tasks))
+	for i, t := range plan.Tasks {
+		ids[i] = t.ID
+	}
+	anchors, err := a.anchorLines(path, ids, idLinePattern)
+	if err != nil {
+		return nil, err
+	}
+	return a.grepFile(re, GrepTypePlan, path, func(m *GrepMatch) {
+		m.Goal = goalID
+		if i := anchorFor(anchors, m.Line); i >= 0 {
+			m.TaskID, m.TaskTitle = plan.Tasks[i].ID, plan.Tasks[i].Title
+		}
+	})
+}
+
+// grepDiscussion searches each of the goal's discussion files, rotated parts
+// included, one at a time.
+func (a *App) grepDiscussion(re *regexp.Regexp, goalID string) ([]GrepMatch, error) {
+	files, err := a.DiscussionManager.Files(goalID)
+	if err != nil {
+		return nil, err
+	}
+	var out []GrepMatch
+	for _, path := range files {
+		data, err := a.Options.Storage.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		entries, err := parseYAMLEntries(data)
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		ids := make([]string, len(entries))
+		for i, e := range entries {
+			ids[i] = e.ID
+		}
+		anchors, err := a.anchorLines(path, ids, idLinePattern)
+		if err != nil {
+			return nil, err
+		}
+		matches, err := a.grepFile(re, GrepTypeDiscuss, path, func(m *GrepMatch) {
+			m.Goal = goalID
+			if i := anchorFor(anchors, m.Line); i >= 0 {
+				e := entries[i]
+				m.EntryID, m.EntryType, m.Author, m.TaskID = e.ID, e.Type, e.Author, e.TaskID
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, matches...)
+	}
+	return out, nil
+}
+
+func (a *App) grepChanges(re *regexp.Regexp) ([]GrepMatch, error) {
+	changes, err := a.ChangeManager.ListChanges()
+	if err != nil {
+		return nil, err
+	}
+	var out []GrepMatch
+	for _, ch := range changes {
+		path := a.Options.Layout.ChangePath(a.Options.ChangesDir, ch.ID)
+		// Each delta is anchored at its "domain" line and each operation at
+		// its "type" line, the first field of both.
+		var domains, reqs []string
+		var keys []string
+		for _, d := range ch.SpecDeltas {
+			keys = append(keys, "domain\x00"+d.Domain)
+			domains = append(domains, d.Domain)
+			reqs = append(reqs, "")
+			for _, op := range d.Operations {
+				keys = append(keys, "type\x00"+op.Type)
+				domains = append(domains, d.Domain)
+				reqs = append(reqs, op.Requirement.ID)
+			}
+		}
+		anchors, err := a.anchorLines(path, keys, changeKeyPattern)
+		if err != nil {
+			return nil, err
+		}
+		id := ch.ID
+		matches, err := a.grepFile(re, GrepTypeChange, path, func(m *GrepMatch) {
+			m.ChangeID = id
+			if i := anchorFor(anchors, m.Line); i >= 0 {
+				m.Domain, m.RequirementID = domains[i], reqs[i]
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, matches...)
+	}
+	return out, nil
+}
+
+// idLinePattern matches the line that opens the task or entry id in JSON
+// ("id": "T01"), YAML (id: D01, - id: T01) or a markdown checklist
+// (- [ ] `T01` ...).
+func idLinePattern(id string) *regexp.Regexp {
+	q := regexp.QuoteMeta(id)
+	return regexp.MustCompile(`^\s*(?:-\s+)?"?id"?\s*:\s*"?` + q + `"?,?\s*$|^\s*[-*]\s+\[[ xX]\]\s+` + "`" + q + "`")
+}
+
+// changeKeyPattern matches a "domain" or "type" line of a change file for a
+// key built as "<field>\x00<value>".
+func changeKeyPattern(key string) *regexp.Regexp {
+	field, value, _ := strings.Cut(key, "\x00")
+	return regexp.MustCompile(`^\s*"` + field + `":\s*"` + regexp.QuoteMeta(value) + `",?\s*$`)
+}
+
+// anchorLines finds, for each key in order, the first line after the
+// previous key's line that pattern(key) matches. Keys that are not found
+// get line 0 and are never attributed.
+func (a *App) anchorLines(path string, keys []string, pattern func(string) *regexp.Regexp) ([]int, error) {
+	data, err := a.Options.Storage.ReadFile(path)
+	if err != nil {
+		if _, ok := err.(*custom_errors.ErrNotFound); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+	lines := strings.Split(string(data), "\n")
+	anchors := make([]int, len(keys))
+	next := 0
+	for i, key := range keys {
+		re := pattern(key)
+		for j := next; j < len(lines); j++ {
+			if re.MatchString(lines[j]) {
+				anchors[i] = j + 1
+				next = j + 1
+				break
+			}
+		}
+	}
+	return anchors, nil
+}
+
+// anchorFor returns the index of the last anchor at or before line, or -1.
+func anchorFor(anchors []int, line int) int {
+	best := -1
+	for i, at := range anchors {
+		if at > 0 && at <= line {
+			best = i
+		}
+	}
+	return best
+}
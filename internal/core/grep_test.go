@@ -0,0 +1,70 @@
+package core
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestGrep_ReportsArtifactContext(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatalf("NewApp: %v", err)
+	}
+	spec := "# Auth\n\nIntro mentions tokens.\n\n### Requirement: Login\n\nUsers log in with a token.\n"
+	if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "auth", Content: spec}); err != nil {
+		t.Fatalf("WriteSpec: %v", err)
+	}
+	if err := app.PlanManager.Save(&model.Plan{GoalID: "001-auth", Tasks: []model.Task{
+		{ID: "T01", Title: "Design", Status: "pending"},
+		{ID: "T02", Title: "Issue token", Status: "pending"},
+	}}); err != nil {
+		t.Fatalf("Save plan: %v", err)
+	}
+	if err := app.DiscussionManager.AddEntry("001-auth", &model.DiscussionEntry{Type: "discussion", Content: "Kickoff"}); err != nil {
+		t.Fatalf("AddEntry: %v", err)
+	}
+	if err := app.DiscussionManager.AddEntry("001-auth", &model.DiscussionEntry{Type: "decision", Author: "@ana", Content: "Short-lived\ntokens only"}); err != nil {
+		t.Fatalf("AddEntry: %v", err)
+	}
+	if err := app.ChangeManager.Save(&model.Change{ID: "CH-001", Title: "Refresh", SpecDeltas: []model.SpecDelta{{
+		Domain:     "auth",
+		Operations: []model.DeltaOperation{{Type: "MODIFIED", Requirement: model.Requirement{ID: "login", Content: "### Requirement: Login\n\nUsers refresh a token.\n"}}},
+	}}}); err != nil {
+		t.Fatalf("Save change: %v", err)
+	}
+
+	matches, err := app.Grep(GrepOptions{Pattern: regexp.MustCompile(`tokens?\b`)})
+	if err != nil {
+		t.Fatalf("Grep: %v", err)
+	}
+	byType := map[string][]GrepMatch{}
+	for _, m := range matches {
+		byType[m.Type] = append(byType[m.Type], m)
+	}
+
+	specs := byType[GrepTypeSpec]
+	if len(specs) != 2 || specs[0].RequirementID != "" || specs[0].Line != 3 ||
+		specs[1].RequirementID != "login" || specs[1].RequirementTitle != "Login" || specs[1].Line != 7 || specs[1].Column != 21 {
+		t.Fatalf("unexpected spec matches: %+v", specs)
+	}
+	if plans := byType[GrepTypePlan]; len(plans) != 1 || plans[0].TaskID != "T02" || plans[0].Goal != "001-auth" {
+		t.Fatalf("unexpected plan matches: %+v", plans)
+	}
+	if d := byType[GrepTypeDiscuss]; len(d) != 1 || d[0].EntryID != "D02" || d[0].EntryType != "decision" || d[0].Author != "@ana" {
+		t.Fatalf("unexpected discussion matches: %+v", d)
+	}
+	if c := byType[GrepTypeChange]; len(c) != 1 || c[0].ChangeID != "CH-001" || c[0].Domain != "auth" || c[0].RequirementID != "login" {
+		t.Fatalf("unexpected change matches: %+v", c)
+	}
+
+	only, err := app.Grep(GrepOptions{Pattern: regexp.MustCompile(`token`), Types: []string{GrepTypePlan}})
+	if err != nil || len(only) != 1 || only[0].Type != GrepTypePlan {
+		t.Fatalf("expected --type to restrict the search, got %+v (err %v)", only, err)
+	}
+	if _, err := app.Grep(GrepOptions{Pattern: regexp.MustCompile(`x`), Types: []string{"charter"}}); err == nil {
+		t.Fatalf("expected an unknown type to be rejected")
+	}
+}
@@ -5,6 +5,7 @@ import "github.com/teamwerx/teamwerx/internal/model"
 // SpecManager defines the interface for managing project specifications.
 type SpecManager interface {
 	ReadSpec(domain string) (*model.Spec, error)
+	ReadRequirement(domain, reqID string) (*model.Requirement, error)
 	WriteSpec(spec *model.Spec) error
 	ListSpecs() ([]*model.Spec, error)
 }
@@ -14,31 +15,58 @@ type PlanManager interface {
 	Load(goalID string) (*model.Plan, error)
 	Save(plan *model.Plan) error
 	AddTask(plan *model.Plan, taskTitle string) (*model.Task, error)
+	AddSubtask(plan *model.Plan, parentID, taskTitle string) (*model.Task, error)
+	CompleteTask(plan *model.Plan, taskID string, force bool) error
+	WaiveTask(plan *model.Plan, taskID, reason string) error
+	BlockTask(plan *model.Plan, taskID, reason, by string) error
+	UnblockTask(plan *model.Plan, taskID, by string) error
 }
 
 // ChangeManager defines the interface for managing change proposals.
 type ChangeManager interface {
 	ReadChange(changeID string) (*model.Change, error)
 	ListChanges() ([]*model.Change, error)
+	ListChangesFiltered(filter ChangeFilter) ([]*model.Change, error)
 	Save(change *model.Change) error
+	CreateChange(title, goalID string, deltas []model.SpecDelta) (*model.Change, error)
+	Reload(change *model.Change) error
 	ApplyChange(change *model.Change) error
+	ApplyChangeTargets(change *model.Change, target ApplyTarget) error
 	ArchiveChange(change *model.Change) error
+	SplitChange(change *model.Change) ([]*model.Change, error)
+	MergeChanges(changeIDs []string, intoID, title string) (*model.Change, error)
+	CloneChange(sourceID, newID string) (*model.Change, error)
 }
 
 // SpecMerger defines the interface for merging spec deltas.
 type SpecMerger interface {
 	Merge(delta *model.SpecDelta) error
+	MergeWithMarkers(delta *model.SpecDelta, label string) ([]model.MergeConflict, error)
 }
 
 // DiscussionManager defines the interface for managing discussion logs.
 type DiscussionManager interface {
 	Load(goalID string) ([]model.DiscussionEntry, error)
+	Each(goalID string, fn func(model.DiscussionEntry) error) error
+	Files(goalID string) ([]string, error)
 	AddEntry(goalID string, entry *model.DiscussionEntry) error
+	AddEntries(goalID string, entries []*model.DiscussionEntry) error
+	RemoveEntry(goalID, entryID string) (*model.DiscussionEntry, error)
+	StoreAttachment(goalID, srcPath string) (*model.Attachment, error)
+	AttachmentPath(goalID string, att model.Attachment) string
+}
+
+// ReviewManager defines the interface for managing spec review comments.
+type ReviewManager interface {
+	Load(domain string) ([]model.ReviewComment, error)
+	AddComment(domain string, comment *model.ReviewComment) error
+	SetStatus(domain, commentID, status string) (*model.ReviewComment, error)
 }
 
 // CharterManager defines the interface for managing the project charter.
 type CharterManager interface {
 	Read() (*model.Charter, error)
+	ReadEffective() (*model.Charter, error)
 	Write(charter *model.Charter) error
 	Exists() bool
 }
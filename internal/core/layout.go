@@ -0,0 +1,219 @@
+package core
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+// Layout describes where each artifact lives relative to its base directory.
+// Templates use forward slashes and a single placeholder naming the artifact:
+//
+//	spec_file:       "{domain}/spec.md"     relative to SpecsDir
+//	review_file:     "{domain}/review.yaml" relative to SpecsDir
+//	plan_file:       "{goal}/plan.json"     relative to GoalsDir (.yaml/.yml stores YAML, .md a checklist)
+//	discussion_file: "{goal}/discuss.md"    relative to GoalsDir
+//	attachments_dir: "{goal}/attachments"   relative to GoalsDir
+//	change_file:     "{change}/change.json" relative to ChangesDir
+//	archive_dir:     ".archive"             relative to ChangesDir
+//	charter_file:    "charter.md"           relative to CharterDir
+//
+// {goal} is the goal ID (e.g. "001-user-auth"). Empty fields take the defaults above.
+type Layout struct {
+	SpecFile       string `yaml:"spec_file,omitempty" json:"spec_file"`
+	ReviewFile     string `yaml:"review_file,omitempty" json:"review_file"`
+	PlanFile       string `yaml:"plan_file,omitempty" json:"plan_file"`
+	DiscussionFile string `yaml:"discussion_file,omitempty" json:"discussion_file"`
+	AttachmentsDir string `yaml:"attachments_dir,omitempty" json:"attachments_dir"`
+	ChangeFile     string `yaml:"change_file,omitempty" json:"change_file"`
+	ArchiveDir     string `yaml:"archive_dir,omitempty" json:"archive_dir"`
+	CharterFile    string `yaml:"charter_file,omitempty" json:"charter_file"`
+}
+
+// Layout placeholders.
+const (
+	domainPlaceholder = "{domain}"
+	goalPlaceholder   = "{goal}"
+	changePlaceholder = "{change}"
+)
+
+// DefaultLayout returns the built-in file layout.
+func DefaultLayout() Layout {
+	return Layout{}.WithDefaults()
+}
+
+// WithDefaults returns a copy of the layout with empty fields set to their defaults.
+func (l Layout) WithDefaults() Layout {
+	if l.SpecFile == "" {
+		l.SpecFile = domainPlaceholder + "/spec.md"
+	}
+	if l.ReviewFile == "" {
+		l.ReviewFile = domainPlaceholder + "/review.yaml"
+	}
+	if l.PlanFile == "" {
+		l.PlanFile = goalPlaceholder + "/plan.json"
+	}
+	if l.DiscussionFile == "" {
+		l.DiscussionFile = goalPlaceholder + "/discuss.md"
+	}
+	if l.AttachmentsDir == "" {
+		l.AttachmentsDir = goalPlaceholder + "/attachments"
+	}
+	if l.ChangeFile == "" {
+		l.ChangeFile = changePlaceholder + "/change.json"
+	}
+	if l.ArchiveDir == "" {
+		l.ArchiveDir = ".archive"
+	}
+	if l.CharterFile == "" {
+		l.CharterFile = "charter.md"
+	}
+	return l
+}
+
+// Validate checks that every template names its placeholder exactly once and
+// stays inside its base directory. Problems are returned together as
+// custom_errors.ValidationErrors.
+func (l Layout) Validate() error {
+	checks := []struct {
+		field, tmpl, placeholder string
+	}{
+		{"spec_file", l.SpecFile, domainPlaceholder},
+		{"review_file", l.ReviewFile, domainPlaceholder},
+		{"plan_file", l.PlanFile, goalPlaceholder},
+		{"discussion_file", l.DiscussionFile, goalPlaceholder},
+		{"attachments_dir", l.AttachmentsDir, goalPlaceholder},
+		{"change_file", l.ChangeFile, changePlaceholder},
+		{"archive_dir", l.ArchiveDir, ""},
+		{"charter_file", l.CharterFile, ""},
+	}
+	var errs custom_errors.ValidationErrors
+	for _, c := range checks {
+		if c.placeholder != "" && strings.Count(c.tmpl, c.placeholder) != 1 {
+			errs.Add("layout."+c.field, fmt.Sprintf("%q must contain %s exactly once", c.tmpl, c.placeholder))
+		}
+		clean := path.Clean(c.tmpl)
+		if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+			errs.Add("layout."+c.field, fmt.Sprintf("%q must be relative to its base directory", c.tmpl))
+		}
+	}
+	return errs.Err()
+}
+
+// expandTemplate substitutes value for placeholder and returns an OS path.
+func expandTemplate(tmpl, placeholder, value string) string {
+	return filepath.FromSlash(strings.ReplaceAll(tmpl, placeholder, value))
+}
+
+// matchTemplate reports whether rel (a path relative to the template's base
+// directory) matches tmpl, returning the placeholder value.
+func matchTemplate(tmpl, placeholder, rel string) (string, bool) {
+	i := strings.Index(tmpl, placeholder)
+	if i < 0 {
+		return "", false
+	}
+	re := regexp.MustCompile("^" + regexp.QuoteMeta(tmpl[:i]) + "([^/]+)" + regexp.QuoteMeta(tmpl[i+len(placeholder):]) + "$")
+	m := re.FindStringSubmatch(filepath.ToSlash(rel))
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// ownDirTemplate returns the directory part of tmpl when that directory is
+// unique to one artifact (contains the placeholder), e.g. "{change}" for
+// "{change}/change.json". It returns "" for flat layouts such as "{change}.json".
+func ownDirTemplate(tmpl, placeholder string) string {
+	dir := path.Dir(tmpl)
+	if !strings.Contains(dir, placeholder) {
+		return ""
+	}
+	return dir
+}
+
+// SpecPath returns the spec file for domain under specsDir.
+func (l Layout) SpecPath(specsDir, domain string) string {
+	return filepath.Join(specsDir, expandTemplate(l.SpecFile, domainPlaceholder, domain))
+}
+
+// ReviewPath returns the review comment sidecar for domain under specsDir.
+func (l Layout) ReviewPath(specsDir, domain string) string {
+	return filepath.Join(specsDir, expandTemplate(l.ReviewFile, domainPlaceholder, domain))
+}
+
+// PlanPath returns the plan file for goalID under goalsDir.
+func (l Layout) PlanPath(goalsDir, goalID string) string {
+	return filepath.Join(goalsDir, expandTemplate(l.PlanFile, goalPlaceholder, goalID))
+}
+
+// DiscussionPath returns the discussion log for goalID under goalsDir.
+func (l Layout) DiscussionPath(goalsDir, goalID string) string {
+	return filepath.Join(goalsDir, expandTemplate(l.DiscussionFile, goalPlaceholder, goalID))
+}
+
+// AttachmentsPath returns the attachments directory for goalID under goalsDir.
+func (l Layout) AttachmentsPath(goalsDir, goalID string) string {
+	return filepath.Join(goalsDir, expandTemplate(l.AttachmentsDir, goalPlaceholder, goalID))
+}
+
+// ChangePath returns the change file for changeID under changesDir.
+func (l Layout) ChangePath(changesDir, changeID string) string {
+	return filepath.Join(changesDir, expandTemplate(l.ChangeFile, changePlaceholder, changeID))
+}
+
+// ChangeCommentsPath returns the review comment thread for changeID under
+// changesDir: comments.md in the change's own directory, or "<id>.comments.md"
+// next to the change file in flat layouts.
+func (l Layout) ChangeCommentsPath(changesDir, changeID string) string {
+	if dir := ownDirTemplate(l.ChangeFile, changePlaceholder); dir != "" {
+		return filepath.Join(changesDir, expandTemplate(dir, changePlaceholder, changeID), "comments.md")
+	}
+	file := l.ChangePath(changesDir, changeID)
+	return strings.TrimSuffix(file, filepath.Ext(file)) + ".comments.md"
+}
+
+// ArchivePath returns the archive root under changesDir.
+func (l Layout) ArchivePath(changesDir string) string {
+	return filepath.Join(changesDir, filepath.FromSlash(l.ArchiveDir))
+}
+
+// CharterPath returns the charter file under charterDir.
+func (l Layout) CharterPath(charterDir string) string {
+	return filepath.Join(charterDir, filepath.FromSlash(l.CharterFile))
+}
+
+// findTemplateMatches walks baseDir in store and returns the placeholder value of every
+// file matching tmpl, in walk order. Directories named in skip (relative to
+// baseDir, forward slashes) are not descended into.
+func findTemplateMatches(store storage.Storage, baseDir, tmpl, placeholder string, skip ...string) ([]string, error) {
+	var values []string
+	err := store.WalkDir(baseDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(baseDir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			for _, s := range skip {
+				if rel == path.Clean(s) {
+					return filepath.SkipDir
+				}
+			}
+			return nil
+		}
+		if v, ok := matchTemplate(tmpl, placeholder, rel); ok {
+			values = append(values, v)
+		}
+		return nil
+	})
+	return values, err
+}
@@ -0,0 +1,125 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+func newLayoutTestApp(t *testing.T, config string) (*App, string) {
+	t.Helper()
+	root := createTempDir(t)
+	if err := os.WriteFile(filepath.Join(root, ConfigFileName), []byte(config), 0o644); err != nil {
+		t.Fatalf("write config failed: %v", err)
+	}
+	app, err := NewApp(AppOptions{
+		SpecsDir:   filepath.Join(root, "specs"),
+		GoalsDir:   filepath.Join(root, "goals"),
+		ChangesDir: filepath.Join(root, "changes"),
+		CharterDir: root,
+	})
+	if err != nil {
+		t.Fatalf("NewApp failed: %v", err)
+	}
+	return app, root
+}
+
+func TestLayout_CustomFileNames(t *testing.T) {
+	app, root := newLayoutTestApp(t, `layout:
+  spec_file: "{domain}/SPEC.md"
+  plan_file: "{goal}/PLAN.yaml"
+  change_file: "{change}.json"
+  archive_dir: "done"
+`)
+
+	if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "auth", Content: "# Auth\n"}); err != nil {
+		t.Fatalf("WriteSpec failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "specs", "auth", "SPEC.md")); err != nil {
+		t.Fatalf("expected SPEC.md: %v", err)
+	}
+	specs, err := app.SpecManager.ListSpecs()
+	if err != nil || len(specs) != 1 || specs[0].Domain != "auth" {
+		t.Fatalf("ListSpecs: got %v, %v", specs, err)
+	}
+
+	plan := &model.Plan{GoalID: "001-demo"}
+	if _, err := app.PlanManager.AddTask(plan, "Write tests"); err != nil {
+		t.Fatalf("AddTask failed: %v", err)
+	}
+	if err := app.PlanManager.Save(plan); err != nil {
+		t.Fatalf("Save plan failed: %v", err)
+	}
+	raw := readFile(t, filepath.Join(root, "goals", "001-demo", "PLAN.yaml"))
+	if !strings.Contains(raw, "goal_id: 001-demo") {
+		t.Fatalf("expected YAML plan, got:\n%s", raw)
+	}
+	loaded, err := app.PlanManager.Load("001-demo")
+	if err != nil || len(loaded.Tasks) != 1 || loaded.Tasks[0].Title != "Write tests" {
+		t.Fatalf("Load plan: got %+v, %v", loaded, err)
+	}
+
+	ch := &model.Change{ID: "CH-1", Title: "Flat"}
+	if err := app.ChangeManager.Save(ch); err != nil {
+		t.Fatalf("Save change failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "changes", "CH-1.json")); err != nil {
+		t.Fatalf("expected flat change file: %v", err)
+	}
+	if err := app.ChangeManager.ArchiveChange(ch); err != nil {
+		t.Fatalf("ArchiveChange failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "changes", "done", "CH-1.json")); err != nil {
+		t.Fatalf("expected archived change file: %v", err)
+	}
+	changes, err := app.ChangeManager.ListChanges()
+	if err != nil || len(changes) != 0 {
+		t.Fatalf("expected no active changes after archive, got %v, %v", changes, err)
+	}
+}
+
+func TestLayout_ExplicitOptionsOverrideConfig(t *testing.T) {
+	root := createTempDir(t)
+	if err := os.WriteFile(filepath.Join(root, ConfigFileName), []byte("layout:\n  spec_file: \"{domain}/SPEC.md\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	o, err := AppOptions{CharterDir: root, Layout: Layout{SpecFile: "{domain}.md"}}.Resolve()
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if o.Layout.SpecFile != "{domain}.md" || o.Layout.PlanFile != "{goal}/plan.json" {
+		t.Fatalf("unexpected layout: %+v", o.Layout)
+	}
+}
+
+func TestLayout_InvalidTemplate(t *testing.T) {
+	root := createTempDir(t)
+	if err := os.WriteFile(filepath.Join(root, ConfigFileName), []byte("layout:\n  spec_file: \"spec.md\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewApp(AppOptions{CharterDir: root, SpecsDir: filepath.Join(root, "specs")}); err == nil {
+		t.Fatal("expected error for spec_file without {domain}")
+	}
+}
+
+func TestLoadConfig_ReportsEveryProblem(t *testing.T) {
+	root := createTempDir(t)
+	config := "layout:\n  spec_file: \"/specs/{domain}.md\"\n  plan_file: \"plan.json\"\nsecrets:\n  mode: loud\ndiscussion:\n  types:\n    - name: risk\n      color: mauve\n"
+	path := filepath.Join(root, ConfigFileName)
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := LoadConfig(path)
+	var fields []string
+	for _, f := range custom_errors.Validation(err) {
+		fields = append(fields, f.Field)
+	}
+	want := "layout.spec_file layout.plan_file secrets.mode discussion.types[0].color"
+	if got := strings.Join(fields, " "); got != want {
+		t.Fatalf("fields = %q (err %v)", got, err)
+	}
+}
@@ -0,0 +1,177 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+// LocalDirName is the per-user directory under the charter directory. It
+// holds a .gitignore ignoring everything in it, so its contents never reach
+// version control.
+const (
+	LocalDirName       = ".local"
+	LocalStateFileName = "state.json"
+)
+
+// Local state keys.
+const (
+	LocalGoal   = "goal"
+	LocalDomain = "domain"
+	LocalChange = "change"
+)
+
+// LocalStateKeys are the keys `teamwerx use` accepts.
+var LocalStateKeys = []string{LocalGoal, LocalDomain, LocalChange}
+
+// LocalState is the per-user context stored in .teamwerx/.local/state.json:
+// the goal, spec domain and change last used, which commands fall back to
+// when a required --goal, --domain or --id flag is omitted.
+type LocalState struct {
+	Goal      string    `json:"goal,omitempty"`
+	Domain    string    `json:"domain,omitempty"`
+	Change    string    `json:"change,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// Get returns the value remembered for key.
+func (s *LocalState) Get(key string) string {
+	switch key {
+	case LocalGoal:
+		return s.Goal
+	case LocalDomain:
+		return s.Domain
+	case LocalChange:
+		return s.Change
+	}
+	return ""
+}
+
+// Set remembers value for key and reports whether anything changed.
+func (s *LocalState) Set(key, value string) bool {
+	var field *string
+	switch key {
+	case LocalGoal:
+		field = &s.Goal
+	case LocalDomain:
+		field = &s.Domain
+	case LocalChange:
+		field = &s.Change
+	default:
+		return false
+	}
+	if *field == value {
+		return false
+	}
+	*field = value
+	return true
+}
+
+func localStatePath(o AppOptions) string {
+	return filepath.Join(o.CharterDir, LocalDirName, LocalStateFileName)
+}
+
+// LoadLocalState reads the per-user state. A missing or unreadable state file
+// yields an empty state: it only supplies defaults and is never worth failing
+// a command over.
+func (a *App) LoadLocalState() *LocalState {
+	st := &LocalState{}
+	data, err := a.Options.Storage.ReadFile(localStatePath(a.Options))
+	if err == nil {
+		_ = json.Unmarshal(data, st)
+	}
+	return st
+}
+
+// SaveLocalState writes the per-user state, creating .local and its
+// .gitignore on first use.
+func (a *App) SaveLocalState(st *LocalState) error {
+	if err := ensureLocalDir(a.Options.Storage, a.Options.CharterDir); err != nil {
+		return err
+	}
+	st.UpdatedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return a.Options.Storage.WriteFile(localStatePath(a.Options), append(data, '\n'), 0o644)
+}
+
+// ensureLocalDir creates the .local directory and its .gitignore.
+func ensureLocalDir(store storage.Storage, charterDir string) error {
+	dir := filepath.Join(charterDir, LocalDirName)
+	if err := store.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	ignore := filepath.Join(dir, ".gitignore")
+	if exists, err := store.Exists(ignore); err != nil {
+		return err
+	} else if !exists {
+		return store.WriteFile(ignore, []byte("# Per-user teamwerx state; never committed.\n*\n"), 0o644)
+	}
+	return nil
+}
+
+// UseContext checks that the goal, spec domain or change named by value
+// exists and remembers it as the default for key. An empty value forgets it.
+func (a *App) UseContext(key, value string) (*LocalState, error) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	value = strings.TrimSpace(value)
+	if !containsString(LocalStateKeys, key) {
+		return nil, custom_errors.NewErrValidation("key", fmt.Sprintf("unknown context %q (want one of %s)", key, strings.Join(LocalStateKeys, ", ")))
+	}
+	if value != "" {
+		if err := a.contextExists(key, value); err != nil {
+			return nil, err
+		}
+	}
+	st := a.LoadLocalState()
+	st.Set(key, value)
+	if err := a.SaveLocalState(st); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+func (a *App) contextExists(key, value string) error {
+	switch key {
+	case LocalGoal:
+		ids, err := ListGoalIDs(a.Options)
+		if err != nil {
+			return err
+		}
+		if !containsString(ids, value) {
+			return custom_errors.NewErrNotFound("goal", value)
+		}
+	case LocalDomain:
+		if _, err := a.SpecManager.ReadSpec(value); err != nil {
+			return err
+		}
+	case LocalChange:
+		if _, err := a.ChangeManager.ReadChange(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RememberContext records values that were used successfully, skipping the
+// write when nothing changed.
+func (a *App) RememberContext(values map[string]string) error {
+	st := a.LoadLocalState()
+	changed := false
+	for key, value := range values {
+		if value = strings.TrimSpace(value); value != "" && st.Set(key, value) {
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return a.SaveLocalState(st)
+}
@@ -0,0 +1,54 @@
+package core
+
+import (
+	"path/filepath"
+	"testing"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestLocalState_UseAndRemember(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st := app.LoadLocalState(); st.Goal != "" || st.Domain != "" || st.Change != "" {
+		t.Fatalf("initial state = %+v", st)
+	}
+	if err := app.PlanManager.Save(&model.Plan{GoalID: "001-demo"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.UseContext("goal", "002-missing"); err == nil {
+		t.Fatal("expected unknown goal to fail")
+	} else if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+		t.Fatalf("expected ErrNotFound, got %T", err)
+	}
+	if _, err := app.UseContext("colour", "red"); err == nil {
+		t.Fatal("expected unknown key to fail")
+	}
+	st, err := app.UseContext("goal", "001-demo")
+	if err != nil || st.Goal != "001-demo" {
+		t.Fatalf("UseContext = %+v, %v", st, err)
+	}
+	if st := app.LoadLocalState(); st.Goal != "001-demo" || st.UpdatedAt.IsZero() {
+		t.Fatalf("reloaded state = %+v", st)
+	}
+	ignore, err := app.Options.Storage.ReadFile(filepath.Join(app.Options.CharterDir, LocalDirName, ".gitignore"))
+	if err != nil || string(ignore) == "" {
+		t.Fatalf(".gitignore = %q, %v", ignore, err)
+	}
+
+	if err := app.RememberContext(map[string]string{LocalDomain: "auth", LocalChange: " CH-001 "}); err != nil {
+		t.Fatal(err)
+	}
+	if st := app.LoadLocalState(); st.Goal != "001-demo" || st.Domain != "auth" || st.Change != "CH-001" {
+		t.Fatalf("remembered state = %+v", st)
+	}
+
+	if st, err := app.UseContext("goal", ""); err != nil || st.Goal != "" || st.Domain != "auth" {
+		t.Fatalf("clearing goal = %+v, %v", st, err)
+	}
+}
@@ -0,0 +1,427 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/storage"
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFileName is the integrity manifest under the charter directory. It
+// records the hash of every spec, plan, discussion, change and charter file
+// as teamwerx last wrote it.
+const ManifestFileName = "manifest.json"
+
+// ManifestEntry is the recorded state of one artifact file.
+type ManifestEntry struct {
+	SHA256    string    `json:"sha256"`
+	Size      int       `json:"size"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Manifest maps artifact paths, relative to the charter directory and
+// slash-separated, to their recorded state. Artifacts outside the charter
+// directory (e.g. a --specs-dir elsewhere) are not recorded.
+type Manifest struct {
+	Files map[string]ManifestEntry `json:"files"`
+}
+
+// Kinds of drift reported by VerifyWorkspace.
+const (
+	DriftModified  = "modified"  // content differs from the last teamwerx write
+	DriftMissing   = "missing"   // recorded file no longer exists
+	DriftCorrupt   = "corrupt"   // JSON or YAML file no longer parses (e.g. a partial write)
+	DriftUntracked = "untracked" // artifact file teamwerx never wrote
+)
+
+// Drift is one file that changed outside teamwerx since the last operation.
+type Drift struct {
+	Path   string `json:"path"`
+	Kind   string `json:"kind"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// manifestTracker decides which paths belong in the manifest and maps them to
+// manifest keys.
+type manifestTracker struct {
+	charterDir string
+	dirs       []string // artifact directories, cleaned
+	files      []string // individual artifact files, cleaned
+}
+
+func newManifestTracker(o AppOptions) manifestTracker {
+	return manifestTracker{
+		charterDir: filepath.Clean(o.CharterDir),
+		dirs:       []string{filepath.Clean(o.SpecsDir), filepath.Clean(o.GoalsDir), filepath.Clean(o.ChangesDir)},
+		files:      []string{filepath.Clean(o.Layout.CharterPath(o.CharterDir))},
+	}
+}
+
+func (t manifestTracker) path() string {
+	return filepath.Join(t.charterDir, ManifestFileName)
+}
+
+// tracked reports whether path is an artifact inside the charter directory.
+// Lock files and the temp files of atomic writes are not.
+func (t manifestTracker) tracked(path string) bool {
+	path = filepath.Clean(path)
+	if strings.HasSuffix(path, ".lock") || tempFilePattern.MatchString(path) {
+		return false
+	}
+	if _, ok := t.key(path); !ok {
+		return false
+	}
+	for _, f := range t.files {
+		if path == f {
+			return true
+		}
+	}
+	for _, d := range t.dirs {
+		if within(path, d) && path != d {
+			return true
+		}
+	}
+	return false
+}
+
+// key maps path to its manifest key. It returns false for paths outside the
+// charter directory, which would otherwise be recorded as machine-specific
+// absolute or "../" paths.
+func (t manifestTracker) key(path string) (string, bool) {
+	path = filepath.Clean(path)
+	rel, err := filepath.Rel(t.charterDir, path)
+	if err != nil {
+		// One path is absolute and the other relative: compare both absolute.
+		dir, derr := filepath.Abs(t.charterDir)
+		abs, perr := filepath.Abs(path)
+		if derr != nil || perr != nil {
+			return "", false
+		}
+		if rel, err = filepath.Rel(dir, abs); err != nil {
+			return "", false
+		}
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// within reports whether path is dir or inside it.
+func within(path, dir string) bool {
+	return path == dir || dir == "." || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+func manifestEntry(data []byte, now time.Time) ManifestEntry {
+	sum := sha256.Sum256(data)
+	return ManifestEntry{SHA256: fmt.Sprintf("%x", sum), Size: len(data), UpdatedAt: now.UTC()}
+}
+
+func loadManifest(store storage.Storage, path string) (*Manifest, error) {
+	m := &Manifest{Files: map[string]ManifestEntry{}}
+	data, err := store.ReadFile(path)
+	if err != nil {
+		return m, err
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, &manifestParseError{path: path, err: err}
+	}
+	if m.Files == nil {
+		m.Files = map[string]ManifestEntry{}
+	}
+	return m, nil
+}
+
+// manifestParseError reports a manifest file that is not valid JSON.
+type manifestParseError struct {
+	path string
+	err  error
+}
+
+func (e *manifestParseError) Error() string {
+	return fmt.Sprintf("failed to parse manifest '%s': %v", e.path, e.err)
+}
+
+func (e *manifestParseError) Unwrap() error { return e.err }
+
+func saveManifest(store storage.Storage, path string, m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return store.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// manifestStorage wraps the managers' storage and records every artifact
+// written, moved or removed through it in the manifest.
+type manifestStorage struct {
+	storage.Storage
+	tracker manifestTracker
+}
+
+func (s *manifestStorage) WriteFile(path string, data []byte, perm os.FileMode) error {
+	if err := s.Storage.WriteFile(path, data, perm); err != nil {
+		return err
+	}
+	if !s.tracker.tracked(path) {
+		return nil
+	}
+	key, _ := s.tracker.key(path)
+	return s.update(func(m *Manifest) {
+		m.Files[key] = manifestEntry(data, time.Now())
+	})
+}
+
+func (s *manifestStorage) Rename(oldpath, newpath string) error {
+	if err := s.Storage.Rename(oldpath, newpath); err != nil {
+		return err
+	}
+	if !s.tracker.tracked(oldpath) && s.tracker.tracked(newpath) {
+		// Moved in from outside the artifact directories (e.g. restored
+		// from the trash): record what arrived.
+		return s.record(newpath)
+	}
+	from, ok := s.tracker.key(oldpath)
+	if !ok {
+		return nil
+	}
+	to, inside := s.tracker.key(newpath)
+	return s.update(func(m *Manifest) {
+		for k, e := range m.Files {
+			if k == from || strings.HasPrefix(k, from+"/") {
+				delete(m.Files, k)
+				moved := to + strings.TrimPrefix(k, from)
+				if inside && s.tracker.tracked(filepath.Join(s.tracker.charterDir, filepath.FromSlash(moved))) {
+					m.Files[moved] = e
+				}
+			}
+		}
+	})
+}
+
+func (s *manifestStorage) RemoveAll(path string) error {
+	if err := s.Storage.RemoveAll(path); err != nil {
+		return err
+	}
+	key, ok := s.tracker.key(path)
+	if !ok {
+		return nil
+	}
+	return s.update(func(m *Manifest) { forgetManifestKey(m, key) })
+}
+
+func (s *manifestStorage) CopyDir(src, dst string) error {
+	if err := s.Storage.CopyDir(src, dst); err != nil {
+		return err
+	}
+	if !s.tracker.tracked(dst) {
+		return nil
+	}
+	return s.record(dst)
+}
+
+// record adds every file at or under path to the manifest.
+func (s *manifestStorage) record(path string) error {
+	files := map[string][]byte{}
+	err := s.Storage.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || d.Type()&fs.ModeSymlink != 0 || !s.tracker.tracked(p) {
+			return err
+		}
+		data, err := s.Storage.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		key, _ := s.tracker.key(p)
+		files[key] = data
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	return s.update(func(m *Manifest) {
+		for key, data := range files {
+			m.Files[key] = manifestEntry(data, now)
+		}
+	})
+}
+
+// update applies fn to the manifest under its lock. A manifest that cannot be
+// parsed is rebuilt from scratch rather than failing the write that has
+// already happened; verify reports the affected files as untracked. Any other
+// read error is returned, leaving the manifest as it is.
+func (s *manifestStorage) update(fn func(*Manifest)) error {
+	path := s.tracker.path()
+	unlock, err := s.Storage.Lock(path, 0)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	m, err := loadManifest(s.Storage, path)
+	if err != nil {
+		if _, ok := err.(*manifestParseError); ok {
+			m = &Manifest{Files: map[string]ManifestEntry{}}
+		} else if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+			return err
+		}
+	}
+	fn(m)
+	return saveManifest(s.Storage, path, m)
+}
+
+func forgetManifestKey(m *Manifest, key string) {
+	for k := range m.Files {
+		if k == key || strings.HasPrefix(k, key+"/") {
+			delete(m.Files, k)
+		}
+	}
+}
+
+// artifactFiles lists every tracked file currently in the workspace.
+func artifactFiles(o AppOptions, t manifestTracker) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	read := func(path string) error {
+		data, err := o.Storage.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		key, _ := t.key(path)
+		files[key] = data
+		return nil
+	}
+	for _, dir := range t.dirs {
+		err := o.Storage.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if d.IsDir() || !t.tracked(path) {
+				return nil
+			}
+			return read(path)
+		})
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	for _, f := range t.files {
+		if !t.tracked(f) {
+			continue
+		}
+		if err := read(f); err != nil {
+			if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+				return nil, err
+			}
+		}
+	}
+	return files, nil
+}
+
+// VerifyWorkspace compares the workspace against the manifest and reports,
+// sorted by path, every artifact changed since the last teamwerx operation:
+// edited, deleted, no longer parseable, or never written by teamwerx. It
+// returns ErrNotFound when the workspace has no manifest yet.
+func VerifyWorkspace(o AppOptions) ([]Drift, error) {
+	t := newManifestTracker(o)
+	m, err := loadManifest(o.Storage, t.path())
+	if err != nil {
+		if _, ok := err.(*custom_errors.ErrNotFound); ok {
+			return nil, custom_errors.NewErrNotFound("manifest", t.path())
+		}
+		return nil, err
+	}
+	files, err := artifactFiles(o, t)
+	if err != nil {
+		return nil, err
+	}
+
+	drifts := []Drift{}
+	for key, entry := range m.Files {
+		data, ok := files[key]
+		if !ok {
+			drifts = append(drifts, Drift{Path: key, Kind: DriftMissing})
+			continue
+		}
+		if perr := parseError(key, data); perr != nil {
+			drifts = append(drifts, Drift{Path: key, Kind: DriftCorrupt, Detail: perr.Error()})
+			continue
+		}
+		if got := manifestEntry(data, time.Time{}); got.SHA256 != entry.SHA256 {
+			drifts = append(drifts, Drift{Path: key, Kind: DriftModified, Detail: fmt.Sprintf("%d -> %d bytes since %s", entry.Size, got.Size, entry.UpdatedAt.Format(time.RFC3339))})
+		}
+	}
+	for key, data := range files {
+		if _, ok := m.Files[key]; ok {
+			continue
+		}
+		d := Drift{Path: key, Kind: DriftUntracked}
+		if perr := parseError(key, data); perr != nil {
+			d.Kind, d.Detail = DriftCorrupt, perr.Error()
+		}
+		drifts = append(drifts, d)
+	}
+	sort.Slice(drifts, func(i, j int) bool { return drifts[i].Path < drifts[j].Path })
+	return drifts, nil
+}
+
+// parseError reports why a JSON or YAML artifact does not parse, or nil.
+func parseError(path string, data []byte) error {
+	var v interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return json.Unmarshal(data, &v)
+	case ".yaml", ".yml":
+		return yaml.Unmarshal(data, &v)
+	}
+	return nil
+}
+
+// AcceptWorkspace rewrites the manifest from the files currently in the
+// workspace, accepting any drift. It returns the number of files recorded.
+func AcceptWorkspace(o AppOptions) (int, error) {
+	t := newManifestTracker(o)
+	files, err := artifactFiles(o, t)
+	if err != nil {
+		return 0, err
+	}
+	m := &Manifest{Files: map[string]ManifestEntry{}}
+	now := time.Now()
+	for key, data := range files {
+		m.Files[key] = manifestEntry(data, now)
+	}
+	unlock, err := o.Storage.Lock(t.path(), 0)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+	return len(files), saveManifest(o.Storage, t.path(), m)
+}
+
+// ForgetManifestPaths drops paths, and anything under them, from the
+// manifest. Use it after removing artifacts without going through the
+// managers (e.g. RemoveGarbage).
+func ForgetManifestPaths(o AppOptions, paths []string) error {
+	t := newManifestTracker(o)
+	s := &manifestStorage{Storage: o.Storage, tracker: t}
+	if exists, err := o.Storage.Exists(t.path()); err != nil || !exists {
+		return err
+	}
+	return s.update(func(m *Manifest) {
+		for _, p := range paths {
+			if key, ok := t.key(p); ok {
+				forgetManifestKey(m, key)
+			}
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestVerifyWorkspace_ReportsDrift(t *testing.T) {
+	mem := storage.NewInMem()
+	app, err := NewApp(AppOptions{Storage: mem})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "auth", Content: "# Auth\n"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.PlanManager.Save(&model.Plan{GoalID: "001-demo"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"CH-001", "CH-002"} {
+		if err := app.ChangeManager.Save(&model.Change{ID: id, Title: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ch, err := app.ChangeManager.ReadChange("CH-002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.ChangeManager.ArchiveChange(ch); err != nil {
+		t.Fatal(err)
+	}
+
+	drifts, err := VerifyWorkspace(app.Options)
+	if err != nil {
+		t.Fatalf("VerifyWorkspace: %v", err)
+	}
+	if len(drifts) != 0 {
+		t.Fatalf("expected a clean workspace, got %+v", drifts)
+	}
+
+	// Out-of-band edits go straight to the underlying storage.
+	o := app.Options
+	specPath := o.Layout.SpecPath(o.SpecsDir, "auth")
+	if err := mem.WriteFile(specPath, []byte("# Auth\n\nEdited.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := mem.RemoveAll(o.Layout.PlanPath(o.GoalsDir, "001-demo")); err != nil {
+		t.Fatal(err)
+	}
+	if err := mem.WriteFile(o.Layout.ChangePath(o.ChangesDir, "CH-001"), []byte(`{"id": "CH-0`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := mem.WriteFile(o.Layout.SpecPath(o.SpecsDir, "billing"), []byte("# Billing\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	drifts, err = VerifyWorkspace(app.Options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, d := range drifts {
+		got[d.Path] = d.Kind
+	}
+	want := map[string]string{
+		"changes/CH-001/change.json": DriftCorrupt,
+		"goals/001-demo/plan.json":   DriftMissing,
+		"specs/auth/spec.md":         DriftModified,
+		"specs/billing/spec.md":      DriftUntracked,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("drift = %v, want %v", got, want)
+	}
+
+	// Accepting records the current state; broken files stay reported.
+	if _, err := AcceptWorkspace(app.Options); err != nil {
+		t.Fatal(err)
+	}
+	drifts, err = VerifyWorkspace(app.Options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drifts) != 1 || drifts[0].Path != "changes/CH-001/change.json" || drifts[0].Kind != DriftCorrupt {
+		t.Fatalf("after accept: %+v", drifts)
+	}
+}
+
+func TestManifest_IgnoresArtifactsOutsideWorkspace(t *testing.T) {
+	mem := storage.NewInMem()
+	app, err := NewApp(AppOptions{Storage: mem, SpecsDir: "/elsewhere/specs", GoalsDir: "../goals"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "auth", Content: "# Auth\n"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.PlanManager.Save(&model.Plan{GoalID: "001-demo"}); err != nil {
+		t.Fatal(err)
+	}
+	if exists, _ := mem.Exists(newManifestTracker(app.Options).path()); exists {
+		t.Fatal("expected no manifest for artifacts outside the workspace")
+	}
+
+	// A corrupt manifest is rebuilt by the next tracked write.
+	path := newManifestTracker(app.Options).path()
+	if err := mem.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.ChangeManager.Save(&model.Change{ID: "CH-001", Title: "One"}); err != nil {
+		t.Fatal(err)
+	}
+	m, err := loadManifest(mem, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for k := range m.Files {
+		keys = append(keys, k)
+	}
+	if !reflect.DeepEqual(keys, []string{"changes/CH-001/change.json"}) {
+		t.Fatalf("manifest keys = %v", keys)
+	}
+}
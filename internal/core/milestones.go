@@ -0,0 +1,107 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// MilestoneStatus reports how far the changes of a milestone have got.
+// Changes superseded by a split or merge are left out.
+type MilestoneStatus struct {
+	Name    string   `json:"name"`
+	Total   int      `json:"total"`
+	Applied int      `json:"applied"`
+	Pending []string `json:"pending"` // IDs of the changes still to apply
+}
+
+// Complete reports whether every change of the milestone has been applied.
+func (m MilestoneStatus) Complete() bool {
+	return m.Total > 0 && m.Applied == m.Total
+}
+
+// Percent is the share of the milestone's changes applied, 0-100.
+func (m MilestoneStatus) Percent() int {
+	if m.Total == 0 {
+		return 0
+	}
+	return m.Applied * 100 / m.Total
+}
+
+// Milestones reports every milestone that changes, live or archived, are
+// assigned to, in natural name order.
+func (a *App) Milestones() ([]MilestoneStatus, error) {
+	changes, err := a.ChangeManager.ListChanges()
+	if err != nil {
+		return nil, err
+	}
+	archived, err := a.archivedChanges()
+	if err != nil {
+		return nil, err
+	}
+	byKey := map[string]*MilestoneStatus{}
+	for _, ch := range append(changes, archived...) {
+		name := strings.TrimSpace(ch.Milestone)
+		if name == "" || ch.Status == "split" || ch.Status == "merged" {
+			continue
+		}
+		m := byKey[strings.ToLower(name)]
+		if m == nil {
+			m = &MilestoneStatus{Name: name, Pending: []string{}}
+			byKey[strings.ToLower(name)] = m
+		}
+		m.Total++
+		if IsPendingChange(ch) {
+			m.Pending = append(m.Pending, ch.ID)
+		} else {
+			m.Applied++
+		}
+	}
+	out := make([]MilestoneStatus, 0, len(byKey))
+	for _, m := range byKey {
+		sort.Slice(m.Pending, func(i, j int) bool { return compareNatural(m.Pending[i], m.Pending[j]) < 0 })
+		out = append(out, *m)
+	}
+	sort.Slice(out, func(i, j int) bool { return compareNatural(out[i].Name, out[j].Name) < 0 })
+	return out, nil
+}
+
+// Milestone reports one milestone; a name no change is assigned to is
+// ErrNotFound.
+func (a *App) Milestone(name string) (*MilestoneStatus, error) {
+	all, err := a.Milestones()
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range all {
+		if strings.EqualFold(m.Name, strings.TrimSpace(name)) {
+			return &m, nil
+		}
+	}
+	return nil, custom_errors.NewErrNotFound("milestone", name)
+}
+
+// AssignMilestone assigns a change to a milestone and saves it; an empty
+// milestone removes the change from its milestone. Applied changes keep the
+// milestone they were released in.
+func (a *App) AssignMilestone(changeID, milestone string) (*model.Change, error) {
+	milestone = strings.TrimSpace(milestone)
+	if strings.ContainsAny(milestone, "\t\n\r") {
+		return nil, custom_errors.NewErrValidation("milestone", "cannot contain tabs or line breaks")
+	}
+	ch, err := a.ChangeManager.ReadChange(changeID)
+	if err != nil {
+		return nil, err
+	}
+	if !IsPendingChange(ch) {
+		return nil, custom_errors.NewErrConflict(fmt.Sprintf("change %s is already %s", ch.ID, ch.Status))
+	}
+	ch.Milestone = milestone
+	if err := a.ChangeManager.Save(ch); err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
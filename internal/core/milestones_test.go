@@ -0,0 +1,85 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestMilestones_AssignApplyAndReportCompletion(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := &model.Spec{Domain: "auth", Content: "# Auth\n\n### Requirement: Login\n\nUsers log in.\n"}
+	if err := app.SpecManager.WriteSpec(spec); err != nil {
+		t.Fatal(err)
+	}
+	added := func(id string) []model.SpecDelta {
+		return []model.SpecDelta{{Domain: "auth", Operations: []model.DeltaOperation{{Type: "ADDED", Requirement: model.Requirement{ID: id, Title: id, Content: "### Requirement: " + id + "\n\nNew.\n"}}}}}
+	}
+	for _, ch := range []*model.Change{
+		{ID: "CH-001", Status: "draft", SpecDeltas: added("Logout"), DependsOn: []string{"CH-003"}},
+		{ID: "CH-002", Status: "draft", SpecDeltas: added("Reset")},
+		{ID: "CH-003", Status: "draft", SpecDeltas: added("Lockout")},
+	} {
+		if err := app.ChangeManager.Save(ch); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, id := range []string{"CH-001", "CH-003"} {
+		if _, err := app.AssignMilestone(id, " v1.2 "); err != nil {
+			t.Fatalf("AssignMilestone(%s): %v", id, err)
+		}
+	}
+	if _, err := app.AssignMilestone("CH-002", "v1.3"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.AssignMilestone("CH-002", ""); err != nil {
+		t.Fatalf("unassign: %v", err)
+	}
+
+	milestones, err := app.Milestones()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []MilestoneStatus{{Name: "v1.2", Total: 2, Pending: []string{"CH-001", "CH-003"}}}
+	if !reflect.DeepEqual(milestones, want) {
+		t.Fatalf("milestones = %+v, want %+v", milestones, want)
+	}
+	if _, err := app.Milestone("v1.3"); err == nil {
+		t.Fatal("expected an unassigned milestone to be not found")
+	} else if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+		t.Fatalf("expected ErrNotFound, got %T: %v", err, err)
+	}
+
+	// Only the milestone's changes are applied, dependencies first.
+	result, err := app.ApplyPendingMatching(ChangeFilter{Milestone: "V1.2"}, nil)
+	if err != nil {
+		t.Fatalf("ApplyPendingMatching: %v", err)
+	}
+	var applied []string
+	for _, ch := range result.Applied {
+		applied = append(applied, ch.ID)
+	}
+	if !reflect.DeepEqual(applied, []string{"CH-003", "CH-001"}) {
+		t.Fatalf("applied = %v", applied)
+	}
+	if ch, _ := app.ChangeManager.ReadChange("CH-002"); ch == nil || !IsPendingChange(ch) {
+		t.Fatalf("expected CH-002 to stay pending, got %+v", ch)
+	}
+
+	m, err := app.Milestone("v1.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.Complete() || m.Percent() != 100 || len(m.Pending) != 0 {
+		t.Fatalf("expected a complete milestone, got %+v", m)
+	}
+	if _, err := app.AssignMilestone("CH-001", "v2"); err == nil {
+		t.Fatal("expected an applied change to keep its milestone")
+	}
+}
@@ -0,0 +1,234 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+// OwnersFileName is the CODEOWNERS-style file mapping spec domains to owners,
+// stored in the workspace directory (next to charter.md).
+//
+// Format: one rule per line, a domain pattern followed by one or more owners.
+// Patterns use path.Match globbing; when several rules match, the last one wins.
+//
+//	# comments and blank lines are ignored
+//	*        @lead
+//	auth     @alice @bob
+//	billing* @carol
+const OwnersFileName = "OWNERS"
+
+type ownerRule struct {
+	pattern string
+	owners  []string
+}
+
+// Owners maps spec domains to the people who must approve changes to them.
+type Owners struct {
+	rules []ownerRule
+}
+
+// LoadOwners reads an owners file. A missing file yields an empty Owners so
+// that workspaces without ownership rules keep working unchanged.
+func LoadOwners(filePath string) (*Owners, error) {
+	return loadOwners(storage.OS(), filePath)
+}
+
+// loadOwners is LoadOwners reading through store.
+func loadOwners(store storage.Storage, filePath string) (*Owners, error) {
+	data, err := store.ReadFile(filePath)
+	if err != nil {
+		if _, ok := err.(*custom_errors.ErrNotFound); ok {
+			return &Owners{}, nil
+		}
+		return nil, err
+	}
+	return ParseOwners(data)
+}
+
+// ParseOwners parses owners file content.
+func ParseOwners(data []byte) (*Owners, error) {
+	o := &Owners{}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("owners line %d: expected '<domain-pattern> <owner>...'", line)
+		}
+		if _, err := path.Match(fields[0], ""); err != nil {
+			return nil, fmt.Errorf("owners line %d: invalid pattern %q: %w", line, fields[0], err)
+		}
+		rule := ownerRule{pattern: fields[0]}
+		for _, f := range fields[1:] {
+			rule.owners = append(rule.owners, normalizeOwner(f))
+		}
+		o.rules = append(o.rules, rule)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// For returns the owners of a domain, or nil when no rule matches.
+func (o *Owners) For(domain string) []string {
+	if o == nil {
+		return nil
+	}
+	var owners []string
+	for _, r := range o.rules {
+		if ok, _ := path.Match(r.pattern, domain); ok {
+			owners = r.owners
+		}
+	}
+	return owners
+}
+
+// RequiredApprovers returns the owners for every domain the change touches.
+// Domains without owners are omitted.
+func (o *Owners) RequiredApprovers(change *model.Change) map[string][]string {
+	out := map[string][]string{}
+	for _, d := range change.SpecDeltas {
+		if owners := o.For(d.Domain); len(owners) > 0 {
+			out[d.Domain] = owners
+		}
+	}
+	return out
+}
+
+// SubmitChange records the required approvers for each touched domain and marks
+// the change as submitted. Existing approvals are kept. It does not persist the change.
+func SubmitChange(owners *Owners, change *model.Change) error {
+	if change == nil {
+		return custom_errors.NewErrConflict("change cannot be nil")
+	}
+	change.RequiredApprovers = owners.RequiredApprovers(change)
+	change.Status = "submitted"
+	return nil
+}
+
+// ApproveChange records sign-off by approver for the given domain, or for every
+// domain the approver owns when domain is empty. It returns the approved domains
+// and ErrConflict if the approver does not own any matching domain.
+// It does not persist the change.
+func ApproveChange(change *model.Change, approver, domain string) ([]string, error) {
+	if change == nil {
+		return nil, custom_errors.NewErrConflict("change cannot be nil")
+	}
+	approver = normalizeOwner(approver)
+	if approver == "" {
+		return nil, custom_errors.NewErrValidation("approver", "cannot be empty")
+	}
+	if change.RequiredApprovers == nil {
+		return nil, custom_errors.NewErrConflict(fmt.Sprintf("change %s has not been submitted for approval", change.ID))
+	}
+
+	var approved []string
+	for _, d := range sortedKeys(change.RequiredApprovers) {
+		if domain != "" && d != domain {
+			continue
+		}
+		if !containsString(change.RequiredApprovers[d], approver) {
+			continue
+		}
+		if !hasApproval(change, d, approver) {
+			change.Approvals = append(change.Approvals, model.Approval{Domain: d, Approver: approver, ApprovedAt: time.Now()})
+		}
+		approved = append(approved, d)
+	}
+	if len(approved) == 0 {
+		return nil, custom_errors.NewErrConflict(fmt.Sprintf("%s is not an owner of any domain awaiting approval in change %s", approver, change.ID))
+	}
+	return approved, nil
+}
+
+// MissingApprovals returns the domains whose recorded required approvers have
+// not signed off yet, sorted by domain name.
+func MissingApprovals(change *model.Change) []string {
+	var missing []string
+	for _, d := range sortedKeys(change.RequiredApprovers) {
+		signed := false
+		for _, owner := range change.RequiredApprovers[d] {
+			if hasApproval(change, d, owner) {
+				signed = true
+				break
+			}
+		}
+		if !signed {
+			missing = append(missing, d)
+		}
+	}
+	return missing
+}
+
+// CheckApprovals verifies that a change may be applied under the given ownership
+// rules: owned domains require the change to have been submitted, every owned
+// domain the change touches now must have been recorded at submit time, and
+// every recorded domain must have owner sign-off.
+func CheckApprovals(owners *Owners, change *model.Change) error {
+	required := owners.RequiredApprovers(change)
+	if change.RequiredApprovers == nil {
+		if len(required) > 0 {
+			return custom_errors.NewErrConflict(fmt.Sprintf("change %s touches owned domains (%s); run 'change submit' first", change.ID, strings.Join(sortedKeys(required), ", ")))
+		}
+		return nil
+	}
+	var unsubmitted []string
+	for _, d := range sortedKeys(required) {
+		if _, ok := change.RequiredApprovers[d]; !ok {
+			unsubmitted = append(unsubmitted, d)
+		}
+	}
+	if len(unsubmitted) > 0 {
+		return custom_errors.NewErrConflict(fmt.Sprintf("change %s touches owned domains added since it was submitted (%s); run 'change submit' again", change.ID, strings.Join(unsubmitted, ", ")))
+	}
+	if missing := MissingApprovals(change); len(missing) > 0 {
+		return custom_errors.NewErrConflict(fmt.Sprintf("change %s is missing owner approval for: %s", change.ID, strings.Join(missing, ", ")))
+	}
+	return nil
+}
+
+func hasApproval(change *model.Change, domain, approver string) bool {
+	for _, a := range change.Approvals {
+		if a.Domain == domain && a.Approver == approver {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeOwner(s string) string {
+	return strings.TrimPrefix(strings.TrimSpace(s), "@")
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,135 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	ce "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestOwners_ParseAndMatch_LastRuleWins(t *testing.T) {
+	o, err := ParseOwners([]byte("# owners\n*  @lead\nauth @alice @bob\nbill* @carol\n"))
+	if err != nil {
+		t.Fatalf("ParseOwners failed: %v", err)
+	}
+	if got := o.For("auth"); !reflect.DeepEqual(got, []string{"alice", "bob"}) {
+		t.Fatalf("unexpected owners for auth: %v", got)
+	}
+	if got := o.For("billing"); !reflect.DeepEqual(got, []string{"carol"}) {
+		t.Fatalf("unexpected owners for billing: %v", got)
+	}
+	if got := o.For("search"); !reflect.DeepEqual(got, []string{"lead"}) {
+		t.Fatalf("unexpected owners for search: %v", got)
+	}
+
+	if _, err := ParseOwners([]byte("auth\n")); err == nil {
+		t.Fatal("expected error for rule without owners")
+	}
+}
+
+func TestOwners_SubmitApproveAndApplyGate(t *testing.T) {
+	o, _ := ParseOwners([]byte("auth @alice\nbilling @carol\n"))
+	ch := &model.Change{
+		ID: "CH-020",
+		SpecDeltas: []model.SpecDelta{
+			{Domain: "auth"}, {Domain: "billing"}, {Domain: "unowned"},
+		},
+	}
+
+	var conf *ce.ErrConflict
+	if err := CheckApprovals(o, ch); !errors.As(err, &conf) {
+		t.Fatalf("expected unsubmitted change to be blocked, got %v", err)
+	}
+
+	if err := SubmitChange(o, ch); err != nil {
+		t.Fatalf("SubmitChange failed: %v", err)
+	}
+	if len(ch.RequiredApprovers) != 2 || ch.Status != "submitted" {
+		t.Fatalf("unexpected submit result: %+v", ch)
+	}
+
+	if _, err := ApproveChange(ch, "@mallory", ""); !errors.As(err, &conf) {
+		t.Fatalf("expected non-owner approval to fail, got %v", err)
+	}
+	if _, err := ApproveChange(ch, "@alice", ""); err != nil {
+		t.Fatalf("ApproveChange failed: %v", err)
+	}
+	if got := MissingApprovals(ch); !reflect.DeepEqual(got, []string{"billing"}) {
+		t.Fatalf("expected billing to be missing, got %v", got)
+	}
+
+	// ApplyChange refuses while approvals are missing.
+	cm := NewChangeManager(createTempDir(t), nil, &fakeSpecMerger{})
+	if err := cm.ApplyChange(ch); !errors.As(err, &conf) {
+		t.Fatalf("expected ApplyChange to be blocked, got %v", err)
+	}
+
+	if _, err := ApproveChange(ch, "carol", "billing"); err != nil {
+		t.Fatalf("ApproveChange failed: %v", err)
+	}
+	if err := CheckApprovals(o, ch); err != nil {
+		t.Fatalf("expected approvals to be complete, got %v", err)
+	}
+	if err := cm.ApplyChange(ch); err != nil {
+		t.Fatalf("ApplyChange failed: %v", err)
+	}
+}
+
+func TestOwners_DeltaAddedAfterSubmit(t *testing.T) {
+	o, _ := ParseOwners([]byte("auth @alice\nbilling @carol\n"))
+	ch := &model.Change{ID: "CH-021", SpecDeltas: []model.SpecDelta{{Domain: "auth"}}}
+	if err := SubmitChange(o, ch); err != nil {
+		t.Fatalf("SubmitChange failed: %v", err)
+	}
+	if _, err := ApproveChange(ch, "alice", ""); err != nil {
+		t.Fatalf("ApproveChange failed: %v", err)
+	}
+
+	// An owned domain edited into change.json after submit needs sign-off too.
+	ch.SpecDeltas = append(ch.SpecDeltas, model.SpecDelta{Domain: "billing"})
+	var conf *ce.ErrConflict
+	if err := CheckApprovals(o, ch); !errors.As(err, &conf) {
+		t.Fatalf("expected the added domain to be blocked, got %v", err)
+	}
+	cm := newChangeManager(createTempDir(t), DefaultLayout(), nil, &fakeSpecMerger{}, storage.NewInMem(), func() (*Owners, error) { return o, nil })
+	if err := cm.ApplyChangeTargets(ch, ApplyTarget{Domains: []string{"auth"}}); !errors.As(err, &conf) {
+		t.Fatalf("expected ApplyChangeTargets to be blocked, got %v", err)
+	}
+
+	if err := SubmitChange(o, ch); err != nil {
+		t.Fatalf("SubmitChange failed: %v", err)
+	}
+	if got := MissingApprovals(ch); !reflect.DeepEqual(got, []string{"billing"}) {
+		t.Fatalf("expected billing to await approval after resubmit, got %v", got)
+	}
+	if _, err := ApproveChange(ch, "carol", "billing"); err != nil {
+		t.Fatalf("ApproveChange failed: %v", err)
+	}
+	if err := CheckApprovals(o, ch); err != nil {
+		t.Fatalf("expected approvals to be complete, got %v", err)
+	}
+}
+
+func TestApp_Owners_ReadsThroughStorage(t *testing.T) {
+	store := storage.NewInMem()
+	app, err := NewApp(AppOptions{Storage: store, CharterDir: "charter"})
+	if err != nil {
+		t.Fatalf("NewApp: %v", err)
+	}
+	if o, err := app.Owners(); err != nil || len(o.rules) != 0 {
+		t.Fatalf("expected empty owners without a file, got %+v (err %v)", o, err)
+	}
+	if err := store.WriteFile("charter/"+OwnersFileName, []byte("auth @alice\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	o, err := app.Owners()
+	if err != nil {
+		t.Fatalf("Owners: %v", err)
+	}
+	if got := o.For("auth"); !reflect.DeepEqual(got, []string{"alice"}) {
+		t.Fatalf("expected the in-memory OWNERS file to be used, got %v", got)
+	}
+}
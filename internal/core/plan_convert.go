@@ -0,0 +1,160 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+	"gopkg.in/yaml.v3"
+)
+
+// Plan storage formats, chosen by the extension of layout.plan_file.
+const (
+	PlanFormatJSON     = "json"
+	PlanFormatYAML     = "yaml"
+	PlanFormatMarkdown = "md"
+)
+
+// PlanFormats lists the plan storage formats.
+var PlanFormats = []string{PlanFormatJSON, PlanFormatYAML, PlanFormatMarkdown}
+
+// PlanFormat returns the format a plan file at path is stored in.
+func PlanFormat(path string) string {
+	switch {
+	case isMarkdownPath(path):
+		return PlanFormatMarkdown
+	case isYAMLPath(path):
+		return PlanFormatYAML
+	}
+	return PlanFormatJSON
+}
+
+// PlanConversion is one goal's plan rewritten by ConvertPlans.
+type PlanConversion struct {
+	Goal string `json:"goal"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ConvertPlans rewrites every goal's plan in format, removes the old plan
+// files and sets layout.plan_file in config.yaml to the new file name, which
+// it returns. Plans are copied as they are, so UpdatedAt is kept. Nothing
+// is removed until every new file has been written; if a write fails the
+// new files are removed again.
+func (a *App) ConvertPlans(format string) (string, []PlanConversion, error) {
+	if !containsString(PlanFormats, format) {
+		return "", nil, custom_errors.NewErrValidation("format", fmt.Sprintf("unknown plan format %q (want %s)", format, strings.Join(PlanFormats, ", ")))
+	}
+	o := a.Options
+	from := o.Layout.PlanFile
+	if PlanFormat(from) == format {
+		return "", nil, custom_errors.NewErrValidation("format", fmt.Sprintf("plans are already stored as %s (%s)", format, from))
+	}
+	target := o.Layout
+	target.PlanFile = strings.TrimSuffix(from, path.Ext(from)) + "." + format
+	if target.PlanFile == target.DiscussionFile {
+		return "", nil, custom_errors.NewErrConflict(fmt.Sprintf("plan file %s would replace the discussion file", target.PlanFile))
+	}
+
+	goalIDs, err := ListGoalIDs(o)
+	if err != nil {
+		return "", nil, err
+	}
+	var converted []PlanConversion
+	var plans []*model.Plan
+	for _, id := range goalIDs {
+		plan, err := a.PlanManager.Load(id)
+		if err != nil {
+			if _, ok := err.(*custom_errors.ErrNotFound); ok {
+				continue
+			}
+			return "", nil, err
+		}
+		c := PlanConversion{Goal: id, From: o.Layout.PlanPath(o.GoalsDir, id), To: target.PlanPath(o.GoalsDir, id)}
+		if exists, err := o.Storage.Exists(c.To); err != nil {
+			return "", nil, err
+		} else if exists {
+			return "", nil, custom_errors.NewErrConflict(fmt.Sprintf("%s already exists", c.To))
+		}
+		converted = append(converted, c)
+		plans = append(plans, plan)
+	}
+
+	for i, c := range converted {
+		data, err := encodePlan(c.To, plans[i])
+		if err == nil {
+			err = o.Storage.WriteFile(c.To, data, 0o644)
+		}
+		if err != nil {
+			for _, done := range converted[:i] {
+				_ = o.Storage.RemoveAll(done.To)
+			}
+			return "", nil, fmt.Errorf("failed to write %s: %w", c.To, err)
+		}
+	}
+	for _, c := range converted {
+		if err := o.Storage.RemoveAll(c.From); err != nil {
+			return "", converted, err
+		}
+	}
+	if err := setConfigValue(o.Storage, ConfigPath(o.CharterDir), []string{"layout", "plan_file"}, target.PlanFile); err != nil {
+		return "", converted, err
+	}
+
+	a.Options.Layout = target
+	a.PlanManager = newPlanManager(o.GoalsDir, target, o.Storage)
+	return target.PlanFile, converted, nil
+}
+
+// setConfigValue sets the config.yaml value at keys (e.g. layout,
+// plan_file), creating the file and any missing sections. Comments and the
+// order of existing keys are kept.
+func setConfigValue(store storage.Storage, path string, keys []string, value string) error {
+	var doc yaml.Node
+	data, err := store.ReadFile(path)
+	if err != nil {
+		if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+			return err
+		}
+	} else if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse config '%s': %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+
+	node := doc.Content[0]
+	for i, key := range keys {
+		if node.Kind != yaml.MappingNode {
+			return custom_errors.NewErrValidation(strings.Join(keys[:i], "."), "must be a mapping")
+		}
+		var next *yaml.Node
+		for j := 0; j+1 < len(node.Content); j += 2 {
+			if node.Content[j].Value == key {
+				next = node.Content[j+1]
+				break
+			}
+		}
+		if next == nil {
+			next = &yaml.Node{Kind: yaml.MappingNode}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, next)
+		}
+		node = next
+	}
+	*node = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value, Style: yaml.DoubleQuotedStyle, LineComment: node.LineComment}
+
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	return store.WriteFile(path, b.Bytes(), 0o644)
+}
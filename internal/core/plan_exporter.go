@@ -0,0 +1,170 @@
+package core
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// Plan export formats.
+const (
+	PlanExportJiraCSV    = "jira-csv"
+	PlanExportLinearJSON = "linear-json"
+)
+
+// PlanExportFormats lists the formats accepted by ExportPlan.
+var PlanExportFormats = []string{PlanExportJiraCSV, PlanExportLinearJSON}
+
+// jiraStatuses and linearStates map teamwerx task statuses onto the default
+// workflow states of each tracker. Unknown statuses pass through unchanged.
+var (
+	jiraStatuses = map[string]string{
+		"pending":     "To Do",
+		"in-progress": "In Progress",
+		"completed":   "Done",
+	}
+	linearStates = map[string]string{
+		"pending":     "Todo",
+		"in-progress": "In Progress",
+		"completed":   "Done",
+	}
+)
+
+// LinearIssue is one task in the linear-json export. Field names follow
+// Linear's issueCreate input so the file can be fed to its API or importer.
+type LinearIssue struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	State       string   `json:"stateName"`
+	Assignee    string   `json:"assignee,omitempty"`
+	ParentID    string   `json:"parentId,omitempty"`
+	Labels      []string `json:"labelNames"`
+}
+
+// ExportPlan renders a plan's tasks in a tracker import format:
+//   - jira-csv: Jira's CSV importer columns; subtasks use Issue Type "Sub-task"
+//     and reference their parent through "Issue Id"/"Parent Id".
+//   - linear-json: a JSON array of LinearIssue.
+//
+// Requirement links and custom fields are listed in each issue's description,
+// and task labels follow the goal's label. Tasks keep plan order.
+func ExportPlan(plan *model.Plan, format string) ([]byte, error) {
+	if plan == nil {
+		return nil, custom_errors.NewErrConflict("plan cannot be nil")
+	}
+	switch format {
+	case PlanExportJiraCSV:
+		return exportJiraCSV(plan)
+	case PlanExportLinearJSON:
+		return exportLinearJSON(plan)
+	default:
+		return nil, custom_errors.NewErrValidation("format", fmt.Sprintf("unknown plan export format %q (expected %s)", format, strings.Join(PlanExportFormats, ", ")))
+	}
+}
+
+func exportJiraCSV(plan *model.Plan) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	// Jira takes one label per "Labels" column, repeated as often as needed.
+	labelColumns := 1
+	for _, t := range plan.Tasks {
+		if n := len(taskLabels(plan.GoalID, t)); n > labelColumns {
+			labelColumns = n
+		}
+	}
+	header := []string{"Issue Id", "Parent Id", "Issue Type", "Summary", "Status", "Assignee", "Description"}
+	for i := 0; i < labelColumns; i++ {
+		header = append(header, "Labels")
+	}
+	rows := [][]string{header}
+	for _, t := range plan.Tasks {
+		issueType := "Task"
+		if t.ParentID != "" {
+			issueType = "Sub-task"
+		}
+		row := []string{
+			t.ID,
+			t.ParentID,
+			issueType,
+			t.Title,
+			mapStatus(jiraStatuses, t.Status),
+			strings.TrimPrefix(t.Assignee, "@"),
+			taskDescription(plan.GoalID, t),
+		}
+		labels := taskLabels(plan.GoalID, t)
+		row = append(row, labels...)
+		for i := len(labels); i < labelColumns; i++ {
+			row = append(row, "")
+		}
+		rows = append(rows, row)
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("failed to encode csv: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func exportLinearJSON(plan *model.Plan) ([]byte, error) {
+	issues := make([]LinearIssue, 0, len(plan.Tasks))
+	for _, t := range plan.Tasks {
+		issues = append(issues, LinearIssue{
+			ID:          t.ID,
+			Title:       t.Title,
+			Description: taskDescription(plan.GoalID, t),
+			State:       mapStatus(linearStates, t.Status),
+			Assignee:    strings.TrimPrefix(t.Assignee, "@"),
+			ParentID:    t.ParentID,
+			Labels:      taskLabels(plan.GoalID, t),
+		})
+	}
+	data, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode json: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// taskDescription is the issue body: the originating goal and task, followed by
+// one line per linked requirement and per custom field.
+func taskDescription(goalID string, t model.Task) string {
+	lines := []string{fmt.Sprintf("teamwerx goal %s, task %s", goalID, t.ID)}
+	if len(t.Requirements) > 0 {
+		lines = append(lines, "Requirements:")
+		for _, r := range t.Requirements {
+			lines = append(lines, "- "+r)
+		}
+	}
+	if len(t.Fields) > 0 {
+		lines = append(lines, "Fields:")
+		for _, f := range strings.Split(FormatTaskFields(t.Fields, "\n"), "\n") {
+			lines = append(lines, "- "+f)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// taskLabels are the issue's labels: the goal's, then the task's own with
+// spaces turned into dashes, as trackers split labels on whitespace.
+func taskLabels(goalID string, t model.Task) []string {
+	labels := []string{"teamwerx-" + goalID}
+	for _, l := range t.Labels {
+		labels = append(labels, strings.Join(strings.Fields(l), "-"))
+	}
+	return labels
+}
+
+func mapStatus(states map[string]string, status string) string {
+	if strings.TrimSpace(status) == "" {
+		status = "pending"
+	}
+	if s, ok := states[status]; ok {
+		return s
+	}
+	return status
+}
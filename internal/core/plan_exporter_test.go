@@ -0,0 +1,68 @@
+package core
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+func samplePlanForExport() *model.Plan {
+	return &model.Plan{GoalID: "001-auth", Tasks: []model.Task{
+		{ID: "T01", Title: "Login form, with \"remember me\"", Status: "completed", Assignee: "@alice", Requirements: []string{"auth/login"}},
+		{ID: "T02", Title: "Validate input", Status: "in-progress", ParentID: "T01"},
+		{ID: "T03", Title: "Docs", Status: ""},
+	}}
+}
+
+func TestExportPlan_JiraCSV(t *testing.T) {
+	data, err := ExportPlan(samplePlanForExport(), PlanExportJiraCSV)
+	if err != nil {
+		t.Fatalf("ExportPlan failed: %v", err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid csv: %v", err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("expected header + 3 rows, got %d", len(rows))
+	}
+	first := rows[1]
+	if first[0] != "T01" || first[2] != "Task" || first[3] != "Login form, with \"remember me\"" || first[4] != "Done" || first[5] != "alice" {
+		t.Fatalf("unexpected first row: %q", first)
+	}
+	if !strings.Contains(first[6], "- auth/login") {
+		t.Fatalf("expected requirement link in description, got %q", first[6])
+	}
+	if sub := rows[2]; sub[1] != "T01" || sub[2] != "Sub-task" || sub[4] != "In Progress" {
+		t.Fatalf("unexpected subtask row: %q", sub)
+	}
+	if rows[3][4] != "To Do" {
+		t.Fatalf("expected empty status to map to To Do, got %q", rows[3][4])
+	}
+}
+
+func TestExportPlan_LinearJSON(t *testing.T) {
+	data, err := ExportPlan(samplePlanForExport(), PlanExportLinearJSON)
+	if err != nil {
+		t.Fatalf("ExportPlan failed: %v", err)
+	}
+	var issues []LinearIssue
+	if err := json.Unmarshal(data, &issues); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if len(issues) != 3 {
+		t.Fatalf("expected 3 issues, got %d", len(issues))
+	}
+	if issues[0].State != "Done" || issues[0].Assignee != "alice" || issues[1].ParentID != "T01" || issues[2].State != "Todo" {
+		t.Fatalf("unexpected issues: %+v", issues)
+	}
+}
+
+func TestExportPlan_UnknownFormat(t *testing.T) {
+	if _, err := ExportPlan(samplePlanForExport(), "trello"); err == nil {
+		t.Fatalf("expected error for unknown format")
+	}
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// Task title templates used by ProposeTasks. {requirement} is the requirement
+// title and {scenario} the scenario title.
+const (
+	ImplementTaskTemplate = "Implement {requirement}"
+	TestTaskTemplate      = "Test {requirement}"
+	ScenarioTaskTemplate  = "Test {requirement}: {scenario}"
+)
+
+// TaskProposal is a plan task suggested from a spec requirement.
+type TaskProposal struct {
+	Title string
+	// Requirement is the linked "<domain>/<requirement-id>".
+	Requirement string
+	// Existing is set when the plan already has a task with this title.
+	Existing bool
+}
+
+// ProposeTasks suggests tasks for every requirement of spec, or only reqID
+// when set: an implementation task per requirement, plus a test task per
+// scenario (or a single test task for a requirement without scenarios). Each
+// proposal links its requirement. Proposals whose title matches an existing
+// task in plan (case-insensitively) are marked Existing, so regenerating
+// after the spec grows only proposes what is new.
+func ProposeTasks(spec *model.Spec, reqID string, plan *model.Plan) ([]TaskProposal, error) {
+	existing := map[string]bool{}
+	if plan != nil {
+		for _, t := range plan.Tasks {
+			existing[strings.ToLower(strings.TrimSpace(t.Title))] = true
+		}
+	}
+
+	var proposals []TaskProposal
+	found := false
+	for _, r := range spec.Requirements {
+		if reqID != "" && r.ID != reqID {
+			continue
+		}
+		found = true
+		link := spec.Domain + "/" + r.ID
+		add := func(tmpl, scenario string) {
+			title := strings.NewReplacer("{requirement}", r.Title, "{scenario}", scenario).Replace(tmpl)
+			proposals = append(proposals, TaskProposal{Title: title, Requirement: link, Existing: existing[strings.ToLower(title)]})
+		}
+		add(ImplementTaskTemplate, "")
+		if len(r.Scenarios) == 0 {
+			add(TestTaskTemplate, "")
+		}
+		for _, sc := range r.Scenarios {
+			add(ScenarioTaskTemplate, sc.Title)
+		}
+	}
+	if reqID != "" && !found {
+		return nil, custom_errors.NewErrNotFound("requirement", reqID)
+	}
+	return proposals, nil
+}
+
+// AddProposedTasks adds proposals to plan as top-level tasks linked to their
+// requirements, in order. The caller saves the plan.
+func AddProposedTasks(pm PlanManager, plan *model.Plan, proposals []TaskProposal) ([]model.Task, error) {
+	var added []model.Task
+	for _, p := range proposals {
+		task, err := pm.AddTask(plan, p.Title)
+		if err != nil {
+			return nil, err
+		}
+		task.Requirements = append(task.Requirements, p.Requirement)
+		added = append(added, *task)
+	}
+	return added, nil
+}
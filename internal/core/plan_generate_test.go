@@ -0,0 +1,56 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestProposeTasks(t *testing.T) {
+	spec, err := NewSpecParser().Parse([]byte("# Auth\n\n### Requirement: Login\n\nUsers log in.\n\n#### Scenario: Success\n\nOK.\n\n#### Scenario: Locked\n\nRefused.\n\n### Requirement: Logout\n\nUsers log out.\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec.Domain = "auth"
+	plan := &model.Plan{GoalID: "001-demo", Tasks: []model.Task{{ID: "T01", Title: "implement login"}}}
+
+	got, err := ProposeTasks(spec, "", plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []TaskProposal{
+		{Title: "Implement Login", Requirement: "auth/login", Existing: true},
+		{Title: "Test Login: Success", Requirement: "auth/login"},
+		{Title: "Test Login: Locked", Requirement: "auth/login"},
+		{Title: "Implement Logout", Requirement: "auth/logout"},
+		{Title: "Test Logout", Requirement: "auth/logout"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("proposals = %+v", got)
+	}
+
+	only, err := ProposeTasks(spec, "logout", nil)
+	if err != nil || len(only) != 2 {
+		t.Fatalf("logout proposals = %+v, %v", only, err)
+	}
+	if _, err := ProposeTasks(spec, "nope", nil); err == nil {
+		t.Fatal("expected unknown requirement to fail")
+	} else if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+		t.Fatalf("expected ErrNotFound, got %T", err)
+	}
+
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	added, err := AddProposedTasks(app.PlanManager, plan, want[1:3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(added) != 2 || added[0].ID != "T02" || !reflect.DeepEqual(plan.Tasks[2].Requirements, []string{"auth/login"}) {
+		t.Fatalf("added = %+v, plan = %+v", added, plan.Tasks)
+	}
+}
@@ -6,15 +6,19 @@ import (
 	"path/filepath"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
 	"github.com/teamwerx/teamwerx/internal/model"
-	fileutil "github.com/teamwerx/teamwerx/internal/utils/file"
+	"github.com/teamwerx/teamwerx/internal/storage"
+	"gopkg.in/yaml.v3"
 )
 
 // planManager implements PlanManager backed by file-based storage.
 // Plans are stored as JSON at: <baseDir>/<goalID>/plan.json
+// (or wherever layout.PlanFile points; a .yaml/.yml file stores the same document as YAML,
+// and a .md file stores it as a markdown checklist, see encodeMarkdownPlan).
 //
 // Example:
 //
@@ -23,34 +27,90 @@ import (
 //	file:    ".teamwerx/goals/001-my-goal/plan.json"
 type planManager struct {
 	baseDir string
+	layout  Layout
+	store   storage.Storage
 }
 
-// NewPlanManager creates a new file-backed PlanManager.
+// NewPlanManager creates a new file-backed PlanManager using the default layout.
 // The baseDir should point to the goals directory (e.g., ".teamwerx/goals").
 func NewPlanManager(baseDir string) PlanManager {
-	return &planManager{baseDir: baseDir}
+	return NewPlanManagerWithLayout(baseDir, DefaultLayout())
+}
+
+// NewPlanManagerWithLayout creates a PlanManager storing plans at layout.PlanFile.
+func NewPlanManagerWithLayout(baseDir string, layout Layout) PlanManager {
+	return newPlanManager(baseDir, layout, storage.OS())
+}
+
+func newPlanManager(baseDir string, layout Layout, store storage.Storage) PlanManager {
+	return &planManager{baseDir: baseDir, layout: layout.WithDefaults(), store: store}
 }
 
 func (m *planManager) planPath(goalID string) string {
-	return filepath.Join(m.baseDir, goalID, "plan.json")
+	return m.layout.PlanPath(m.baseDir, goalID)
+}
+
+// isYAMLPath reports whether a file should be encoded as YAML based on its extension.
+func isYAMLPath(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// decodePlan parses plan data in the format implied by path. YAML documents use
+// the same keys as the JSON form, so they are normalized through JSON.
+func decodePlan(path string, data []byte, plan *model.Plan) error {
+	if isMarkdownPath(path) {
+		return decodeMarkdownPlan(data, plan)
+	}
+	if isYAMLPath(path) {
+		var doc interface{}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return err
+		}
+		b, err := json.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		data = b
+	}
+	return json.Unmarshal(data, plan)
+}
+
+// encodePlan serializes a plan in the format implied by path.
+func encodePlan(path string, plan *model.Plan) ([]byte, error) {
+	if isMarkdownPath(path) {
+		return encodeMarkdownPlan(plan)
+	}
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil || !isYAMLPath(path) {
+		return append(data, '\n'), err
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(doc)
 }
 
 // Load reads and parses the plan for a given goalID.
 // Returns ErrNotFound if no plan file exists for the goal.
 func (m *planManager) Load(goalID string) (*model.Plan, error) {
 	if goalID == "" {
-		return nil, custom_errors.NewErrConflict("goalID cannot be empty")
+		return nil, custom_errors.NewErrValidation("goalID", "cannot be empty")
 	}
 
 	path := m.planPath(goalID)
-	b, err := fileutil.ReadFile(path)
+	b, err := m.store.ReadFile(path)
 	if err != nil {
 		// Pass through custom ErrNotFound as-is.
 		return nil, err
 	}
 
 	var plan model.Plan
-	if err := json.Unmarshal(b, &plan); err != nil {
+	if err := decodePlan(path, b, &plan); err != nil {
 		return nil, fmt.Errorf("failed to parse plan file '%s': %w", path, err)
 	}
 
@@ -58,6 +118,7 @@ func (m *planManager) Load(goalID string) (*model.Plan, error) {
 	if plan.GoalID == "" {
 		plan.GoalID = goalID
 	}
+	plan.Progress = ComputePlanProgress(&plan)
 
 	return &plan, nil
 }
@@ -69,18 +130,19 @@ func (m *planManager) Save(plan *model.Plan) error {
 		return custom_errors.NewErrConflict("plan cannot be nil")
 	}
 	if plan.GoalID == "" {
-		return custom_errors.NewErrConflict("plan.GoalID cannot be empty")
+		return custom_errors.NewErrValidation("plan.GoalID", "cannot be empty")
 	}
 
 	plan.UpdatedAt = time.Now()
+	plan.Progress = ComputePlanProgress(plan)
 	path := m.planPath(plan.GoalID)
 
-	data, err := json.MarshalIndent(plan, "", "  ")
+	data, err := encodePlan(path, plan)
 	if err != nil {
 		return fmt.Errorf("failed to encode plan: %w", err)
 	}
 
-	if err := fileutil.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+	if err := m.store.WriteFile(path, data, 0o644); err != nil {
 		return fmt.Errorf("failed to write plan file '%s': %w", path, err)
 	}
 
@@ -94,10 +156,10 @@ func (m *planManager) AddTask(plan *model.Plan, taskTitle string) (*model.Task,
 		return nil, custom_errors.NewErrConflict("plan cannot be nil")
 	}
 	if plan.GoalID == "" {
-		return nil, custom_errors.NewErrConflict("plan.GoalID cannot be empty")
+		return nil, custom_errors.NewErrValidation("plan.GoalID", "cannot be empty")
 	}
 	if taskTitle == "" {
-		return nil, custom_errors.NewErrConflict("task title cannot be empty")
+		return nil, custom_errors.NewErrValidation("title", "cannot be empty")
 	}
 
 	nextID := nextTaskID(plan.Tasks)
@@ -113,6 +175,179 @@ func (m *planManager) AddTask(plan *model.Plan, taskTitle string) (*model.Task,
 	return &plan.Tasks[len(plan.Tasks)-1], nil
 }
 
+// AddSubtask appends a new Task under the existing task parentID.
+// Subtasks share the plan's flat ID sequence (T01, T02, ...) and record their
+// parent via Task.ParentID. It does not persist changes; callers should invoke Save(plan).
+func (m *planManager) AddSubtask(plan *model.Plan, parentID, taskTitle string) (*model.Task, error) {
+	if plan == nil {
+		return nil, custom_errors.NewErrConflict("plan cannot be nil")
+	}
+	parent := findTask(plan.Tasks, parentID)
+	if parent == nil {
+		return nil, custom_errors.NewErrNotFound("task", parentID)
+	}
+	parentID = parent.ID
+
+	task, err := m.AddTask(plan, taskTitle)
+	if err != nil {
+		return nil, err
+	}
+	task.ParentID = parentID
+	return task, nil
+}
+
+// CompleteTask marks the task taskID as completed.
+// A parent task cannot be completed while any of its descendants are still open,
+// unless force is true. It does not persist changes; callers should invoke Save(plan).
+func (m *planManager) CompleteTask(plan *model.Plan, taskID string, force bool) error {
+	if plan == nil {
+		return custom_errors.NewErrConflict("plan cannot be nil")
+	}
+	task := findTask(plan.Tasks, taskID)
+	if task == nil {
+		return custom_errors.NewErrNotFound("task", taskID)
+	}
+
+	if !force {
+		var open []string
+		for _, child := range descendantTasks(plan.Tasks, task.ID) {
+			if child.Status != TaskStatusCompleted && child.Status != TaskStatusWaived {
+				open = append(open, child.ID)
+			}
+		}
+		if len(open) > 0 {
+			return custom_errors.NewErrConflict(fmt.Sprintf("task %s has incomplete subtasks: %s", task.ID, strings.Join(open, ", ")))
+		}
+	}
+
+	now := time.Now()
+	task.Status = TaskStatusCompleted
+	task.CompletedAt = &now
+	plan.UpdatedAt = now
+	return nil
+}
+
+// WaiveTask marks a task as deliberately skipped so it no longer blocks goal
+// completion. A reason is required and is recorded on the task.
+func (m *planManager) WaiveTask(plan *model.Plan, taskID, reason string) error {
+	if plan == nil {
+		return custom_errors.NewErrConflict("plan cannot be nil")
+	}
+	if strings.TrimSpace(reason) == "" {
+		return custom_errors.NewErrValidation("reason", "is required to waive a task")
+	}
+	task := findTask(plan.Tasks, taskID)
+	if task == nil {
+		return custom_errors.NewErrNotFound("task", taskID)
+	}
+	if task.Status == TaskStatusCompleted {
+		return custom_errors.NewErrConflict(fmt.Sprintf("task %s is already completed", task.ID))
+	}
+	task.Status = TaskStatusWaived
+	task.WaiverReason = strings.TrimSpace(reason)
+	plan.UpdatedAt = time.Now()
+	return nil
+}
+
+// BlockTask marks an open task as blocked for reason, recording who blocked
+// it and when. It does not persist changes; callers should invoke Save(plan).
+func (m *planManager) BlockTask(plan *model.Plan, taskID, reason, by string) error {
+	if plan == nil {
+		return custom_errors.NewErrConflict("plan cannot be nil")
+	}
+	if strings.TrimSpace(reason) == "" {
+		return custom_errors.NewErrValidation("reason", "is required to block a task")
+	}
+	task := findTask(plan.Tasks, taskID)
+	if task == nil {
+		return custom_errors.NewErrNotFound("task", taskID)
+	}
+	switch task.Status {
+	case TaskStatusCompleted, TaskStatusWaived, TaskStatusBlocked:
+		return custom_errors.NewErrConflict(fmt.Sprintf("task %s is already %s", task.ID, task.Status))
+	}
+	now := time.Now()
+	task.Blocks = append(task.Blocks, model.TaskBlock{
+		Reason:    strings.TrimSpace(reason),
+		BlockedAt: now,
+		BlockedBy: strings.TrimSpace(by),
+		Status:    task.Status,
+	})
+	task.Status = TaskStatusBlocked
+	plan.UpdatedAt = now
+	return nil
+}
+
+// UnblockTask closes the open block of a blocked task and restores the
+// status it had before. It does not persist changes; callers should invoke
+// Save(plan).
+func (m *planManager) UnblockTask(plan *model.Plan, taskID, by string) error {
+	if plan == nil {
+		return custom_errors.NewErrConflict("plan cannot be nil")
+	}
+	task := findTask(plan.Tasks, taskID)
+	if task == nil {
+		return custom_errors.NewErrNotFound("task", taskID)
+	}
+	if task.Status != TaskStatusBlocked {
+		return custom_errors.NewErrConflict(fmt.Sprintf("task %s is not blocked", task.ID))
+	}
+	now := time.Now()
+	status := TaskStatusPending
+	if b := OpenBlock(*task); b != nil {
+		b.UnblockedAt = &now
+		b.UnblockedBy = strings.TrimSpace(by)
+		if b.Status != "" {
+			status = b.Status
+		}
+	}
+	task.Status = status
+	plan.UpdatedAt = now
+	return nil
+}
+
+// OpenBlock returns the block of a blocked task, or nil when it is not
+// blocked or was marked blocked by hand without one.
+func OpenBlock(t model.Task) *model.TaskBlock {
+	if t.Status != TaskStatusBlocked || len(t.Blocks) == 0 {
+		return nil
+	}
+	if b := &t.Blocks[len(t.Blocks)-1]; b.UnblockedAt == nil {
+		return b
+	}
+	return nil
+}
+
+// findTask returns a pointer to the task with the given ID (case-insensitive), or nil.
+func findTask(tasks []model.Task, id string) *model.Task {
+	for i := range tasks {
+		if strings.EqualFold(tasks[i].ID, id) {
+			return &tasks[i]
+		}
+	}
+	return nil
+}
+
+// descendantTasks returns every task below parentID in the subtask hierarchy,
+// breadth-first. Cycles in hand-edited files are tolerated.
+func descendantTasks(tasks []model.Task, parentID string) []model.Task {
+	var out []model.Task
+	seen := map[string]bool{parentID: true}
+	queue := []string{parentID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, t := range tasks {
+			if t.ParentID == id && !seen[t.ID] {
+				seen[t.ID] = true
+				out = append(out, t)
+				queue = append(queue, t.ID)
+			}
+		}
+	}
+	return out
+}
+
 // nextTaskID generates a sequential ID for a new task based on existing tasks.
 // It prefers the format "TNN" (e.g., T01, T02, ...). If no tasks have such IDs,
 // it falls back to using the next index + 1.
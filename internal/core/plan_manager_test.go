@@ -139,9 +139,9 @@ func TestPlanManager_AddTask_EmptyTitle_Error(t *testing.T) {
 	if err == nil {
 		t.Fatalf("expected error for empty title, got nil")
 	}
-	var conf *ce.ErrConflict
-	if !errors.As(err, &conf) {
-		t.Fatalf("expected ErrConflict, got %T: %v", err, err)
+	var verr *ce.ErrValidation
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected ErrValidation, got %T: %v", err, err)
 	}
 }
 
@@ -154,9 +154,9 @@ func TestPlanManager_Save_EmptyGoalID_Error(t *testing.T) {
 	if err == nil {
 		t.Fatalf("expected error for empty GoalID, got nil")
 	}
-	var conf *ce.ErrConflict
-	if !errors.As(err, &conf) {
-		t.Fatalf("expected ErrConflict, got %T: %v", err, err)
+	var verr *ce.ErrValidation
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected ErrValidation, got %T: %v", err, err)
 	}
 }
 
@@ -218,3 +218,166 @@ func TestPlanManager_Save_Updates_UpdatedAt(t *testing.T) {
 		t.Fatalf("expected UpdatedAt to increase; first=%v second=%v", first, second)
 	}
 }
+
+func TestPlanManager_Subtasks_CompletionRollUp(t *testing.T) {
+	pm := NewPlanManager(createTempDir(t))
+	plan := &model.Plan{GoalID: "001-demo"}
+
+	parent, err := pm.AddTask(plan, "parent")
+	if err != nil {
+		t.Fatalf("AddTask failed: %v", err)
+	}
+	parentID := parent.ID
+	child, err := pm.AddSubtask(plan, "t01", "child")
+	if err != nil {
+		t.Fatalf("AddSubtask failed: %v", err)
+	}
+	if child.ID != "T02" || child.ParentID != parentID {
+		t.Fatalf("unexpected subtask: %+v", child)
+	}
+
+	if _, err := pm.AddSubtask(plan, "T99", "orphan"); err == nil {
+		t.Fatal("expected error for unknown parent")
+	}
+
+	// Parent cannot complete while the child is pending.
+	err = pm.CompleteTask(plan, parentID, false)
+	var cf *ce.ErrConflict
+	if !errors.As(err, &cf) {
+		t.Fatalf("expected ErrConflict, got %T: %v", err, err)
+	}
+
+	if err := pm.CompleteTask(plan, "T02", false); err != nil {
+		t.Fatalf("CompleteTask child failed: %v", err)
+	}
+	if err := pm.CompleteTask(plan, parentID, false); err != nil {
+		t.Fatalf("CompleteTask parent failed: %v", err)
+	}
+
+	// Force bypasses the roll-up check.
+	if _, err := pm.AddSubtask(plan, parentID, "late child"); err != nil {
+		t.Fatalf("AddSubtask failed: %v", err)
+	}
+	plan.Tasks[0].Status = "pending"
+	if err := pm.CompleteTask(plan, parentID, true); err != nil {
+		t.Fatalf("forced CompleteTask failed: %v", err)
+	}
+
+	if err := pm.Save(plan); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := pm.Load("001-demo")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.Tasks[2].ParentID != parentID {
+		t.Fatalf("expected ParentID to persist, got %+v", loaded.Tasks[2])
+	}
+}
+
+func TestPlanManager_ProgressAndGoalCompletionGate(t *testing.T) {
+	base := t.TempDir()
+	pm := NewPlanManager(base)
+
+	plan := &model.Plan{GoalID: "001-gate", Tasks: []model.Task{
+		{ID: "T01", Title: "a", Status: TaskStatusCompleted},
+		{ID: "T02", Title: "b", Status: TaskStatusBlocked},
+		{ID: "T03", Title: "c", Status: TaskStatusPending},
+	}}
+	if err := pm.Save(plan); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := pm.Load("001-gate")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if p := loaded.Progress; p.Total != 3 || p.Completed != 1 || p.Blocked != 1 || p.Pending != 1 {
+		t.Fatalf("unexpected progress: %+v", p)
+	}
+	if got := FormatPlanProgress(loaded.Progress); got != "1/3 complete, 1 blocked" {
+		t.Fatalf("FormatPlanProgress = %q", got)
+	}
+
+	var conflict *ce.ErrConflict
+	if err := CompleteGoal(loaded); !errors.As(err, &conflict) {
+		t.Fatalf("expected ErrConflict for open tasks, got %v", err)
+	}
+	if loaded.Status != "" {
+		t.Fatalf("plan must be unchanged after a refused completion")
+	}
+	if err := pm.WaiveTask(loaded, "T02", ""); err == nil {
+		t.Fatalf("expected waiving without a reason to fail")
+	}
+	for _, id := range []string{"T02", "T03"} {
+		if err := pm.WaiveTask(loaded, id, "out of scope"); err != nil {
+			t.Fatalf("WaiveTask %s: %v", id, err)
+		}
+	}
+	if err := CompleteGoal(loaded); err != nil {
+		t.Fatalf("CompleteGoal: %v", err)
+	}
+	if err := pm.Save(loaded); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	final, err := pm.Load("001-gate")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if final.Status != PlanStatusCompleted || final.CompletedAt == nil || final.Tasks[1].WaiverReason != "out of scope" {
+		t.Fatalf("completion or waiver not persisted: %+v", final)
+	}
+	if !final.Progress.Done() || final.Progress.Waived != 2 {
+		t.Fatalf("unexpected final progress: %+v", final.Progress)
+	}
+}
+
+func TestPlanManager_BlockAndUnblockTask(t *testing.T) {
+	base := t.TempDir()
+	pm := NewPlanManager(base)
+
+	plan := &model.Plan{GoalID: "001-block", Tasks: []model.Task{
+		{ID: "T01", Title: "a", Status: TaskStatusInProgress},
+		{ID: "T02", Title: "b", Status: TaskStatusCompleted},
+	}}
+	if err := pm.BlockTask(plan, "T01", " ", "alice"); err == nil {
+		t.Fatalf("expected blocking without a reason to fail")
+	}
+	var conflict *ce.ErrConflict
+	if err := pm.BlockTask(plan, "T02", "infra", "alice"); !errors.As(err, &conflict) {
+		t.Fatalf("expected ErrConflict blocking a completed task, got %v", err)
+	}
+	if err := pm.UnblockTask(plan, "T01", "alice"); !errors.As(err, &conflict) {
+		t.Fatalf("expected ErrConflict unblocking an unblocked task, got %v", err)
+	}
+	if err := pm.BlockTask(plan, "t01", "waiting on infra", "alice"); err != nil {
+		t.Fatalf("BlockTask: %v", err)
+	}
+	if err := pm.Save(plan); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := pm.Load("001-block")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	task := loaded.Tasks[0]
+	b := OpenBlock(task)
+	if task.Status != TaskStatusBlocked || b == nil || b.Reason != "waiting on infra" || b.BlockedBy != "alice" || b.BlockedAt.IsZero() {
+		t.Fatalf("block not persisted: %+v", task)
+	}
+	if loaded.Progress.Blocked != 1 {
+		t.Fatalf("progress = %+v", loaded.Progress)
+	}
+
+	if err := pm.UnblockTask(loaded, "T01", "bob"); err != nil {
+		t.Fatalf("UnblockTask: %v", err)
+	}
+	task = loaded.Tasks[0]
+	if task.Status != TaskStatusInProgress || OpenBlock(task) != nil {
+		t.Fatalf("expected the previous status restored, got %+v", task)
+	}
+	if len(task.Blocks) != 1 || task.Blocks[0].UnblockedAt == nil || task.Blocks[0].UnblockedBy != "bob" {
+		t.Fatalf("unblock not recorded: %+v", task.Blocks)
+	}
+}
@@ -0,0 +1,175 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// A markdown plan is a checklist with one task per item. Fields that the
+// checklist cannot show are kept as JSON in a trailing HTML comment, so
+// converting between plan formats is lossless:
+//
+//	<!-- teamwerx-plan: {"goal_id":"001-auth","updated_at":"2025-01-02T10:00:00Z"} -->
+//	# Plan: 001-auth
+//
+//	- [x] `T01` Research the approach
+//	- [ ] `T02` Write spec delta <!-- {"assignee":"@alice","status":"in-progress"} -->
+//	- [ ] `T03` Add tests <!-- {"parent_id":"T02"} -->
+//
+// Ticking a box completes its task and clearing it reopens the task; any
+// other status comes from the comment. An item without a `ID` gets the next
+// free task ID. Lines that are not checklist items are not kept.
+
+// planMarkdownMarker opens the comment holding the plan's own fields.
+const planMarkdownMarker = "teamwerx-plan:"
+
+var (
+	planMarkdownHeader = regexp.MustCompile(`^<!--\s*` + regexp.QuoteMeta(planMarkdownMarker) + `\s*(\{.*\})\s*-->\s*$`)
+	planMarkdownItem   = regexp.MustCompile(`^\s*[-*+]\s+\[([ xX])\]\s*(.*)$`)
+	planMarkdownID     = regexp.MustCompile("^`([^`\\s]+)`\\s*(.*)$")
+	planMarkdownMeta   = regexp.MustCompile(`^(.*?)\s*<!--\s*(\{.*\})\s*-->\s*$`)
+)
+
+// isMarkdownPath reports whether a plan file should be stored as markdown.
+func isMarkdownPath(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".md")
+}
+
+// taskChecked reports whether a task's checklist box is ticked.
+func taskChecked(status string) bool {
+	return status == TaskStatusCompleted || status == TaskStatusWaived
+}
+
+// jsonFields returns v's JSON object fields without the given keys.
+func jsonFields(v interface{}, drop ...string) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for _, k := range drop {
+		delete(fields, k)
+	}
+	return fields, nil
+}
+
+// encodeMarkdownPlan renders plan as a markdown checklist.
+func encodeMarkdownPlan(plan *model.Plan) ([]byte, error) {
+	header, err := jsonFields(plan, "tasks", "progress")
+	if err != nil {
+		return nil, err
+	}
+	meta, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "<!-- %s %s -->\n# Plan: %s\n", planMarkdownMarker, meta, plan.GoalID)
+	if len(plan.Tasks) > 0 {
+		b.WriteString("\n")
+	}
+
+	for _, t := range plan.Tasks {
+		fields, err := jsonFields(t, "id", "title", "status")
+		if err != nil {
+			return nil, err
+		}
+		box, implied := " ", TaskStatusPending
+		if taskChecked(t.Status) {
+			box, implied = "x", TaskStatusCompleted
+		}
+		if t.Status != implied {
+			fields["status"], _ = json.Marshal(t.Status)
+		}
+		// A title the item line cannot carry verbatim goes in the comment,
+		// with a one-line version shown.
+		title := strings.Join(strings.Fields(t.Title), " ")
+		if title != t.Title || strings.Contains(title, "<!--") || strings.HasPrefix(title, "`") {
+			fields["title"], _ = json.Marshal(t.Title)
+			title = strings.TrimLeft(strings.ReplaceAll(title, "<!--", "<!-"), "`")
+		}
+		fmt.Fprintf(&b, "- [%s] `%s` %s", box, t.ID, title)
+		if len(fields) > 0 {
+			meta, err := json.Marshal(fields)
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&b, " <!-- %s -->", meta)
+		}
+		b.WriteString("\n")
+	}
+	return b.Bytes(), nil
+}
+
+// decodeMarkdownPlan parses a markdown checklist written by
+// encodeMarkdownPlan or by hand.
+func decodeMarkdownPlan(data []byte, plan *model.Plan) error {
+	var unnumbered []int
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if m := planMarkdownHeader.FindStringSubmatch(line); m != nil {
+			if err := json.Unmarshal([]byte(m[1]), plan); err != nil {
+				return fmt.Errorf("line %d: %w", i+1, err)
+			}
+			continue
+		}
+		m := planMarkdownItem.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		checked, text := m[1] != " ", m[2]
+
+		var t model.Task
+		var meta struct {
+			Title  *string `json:"title"`
+			Status *string `json:"status"`
+		}
+		if mm := planMarkdownMeta.FindStringSubmatch(text); mm != nil {
+			if err := json.Unmarshal([]byte(mm[2]), &t); err != nil {
+				return fmt.Errorf("line %d: %w", i+1, err)
+			}
+			if err := json.Unmarshal([]byte(mm[2]), &meta); err != nil {
+				return fmt.Errorf("line %d: %w", i+1, err)
+			}
+			text = mm[1]
+		}
+		if im := planMarkdownID.FindStringSubmatch(text); im != nil {
+			t.ID, text = im[1], im[2]
+		}
+		t.Title = strings.TrimSpace(text)
+		if meta.Title != nil {
+			t.Title = *meta.Title
+		}
+
+		// The box wins over a recorded status it contradicts.
+		switch {
+		case meta.Status == nil && checked:
+			t.Status = TaskStatusCompleted
+		case meta.Status == nil:
+			t.Status = TaskStatusPending
+		case checked && !taskChecked(*meta.Status):
+			t.Status = TaskStatusCompleted
+		case !checked && taskChecked(*meta.Status):
+			t.Status = TaskStatusPending
+		default:
+			t.Status = *meta.Status
+		}
+		if t.ID == "" {
+			unnumbered = append(unnumbered, len(plan.Tasks))
+		}
+		plan.Tasks = append(plan.Tasks, t)
+	}
+	for _, i := range unnumbered {
+		plan.Tasks[i].ID = nextTaskID(plan.Tasks)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestMarkdownPlan_RoundTripsEveryField(t *testing.T) {
+	done := time.Date(2025, 3, 4, 5, 6, 7, 8, time.UTC)
+	plan := &model.Plan{
+		GoalID:      "001-auth",
+		UpdatedAt:   done,
+		Status:      PlanStatusCompleted,
+		CompletedAt: &done,
+		Tasks: []model.Task{
+			{ID: "T01", Title: "Research", Status: TaskStatusCompleted},
+			{ID: "T02", Title: "Build <!-- it -->", Status: TaskStatusInProgress, Assignee: "@alice", Requirements: []string{"auth/login"}},
+			{ID: "T03", Title: "Sub", Status: TaskStatusWaived, ParentID: "T02", WaiverReason: "covered by --> T02"},
+			{ID: "T04", Title: " spaced\ntitle", Status: ""},
+		},
+	}
+	data, err := encodePlan("plan.md", plan)
+	if err != nil {
+		t.Fatalf("encodePlan: %v", err)
+	}
+	if !strings.Contains(string(data), "- [x] `T01` Research\n") {
+		t.Fatalf("expected a plain checked item, got:\n%s", data)
+	}
+	var got model.Plan
+	if err := decodePlan("plan.md", data, &got); err != nil {
+		t.Fatalf("decodePlan: %v", err)
+	}
+	if !reflect.DeepEqual(&got, plan) {
+		t.Fatalf("round trip changed the plan:\n got %+v\nwant %+v\nfile:\n%s", got, *plan, data)
+	}
+}
+
+func TestMarkdownPlan_HandEdits(t *testing.T) {
+	md := "# Plan\n\nNotes are ignored.\n\n" +
+		"- [x] `T01` Research <!-- {\"status\":\"in-progress\"} -->\n" +
+		"- [ ] `T02` Build <!-- {\"status\":\"completed\"} -->\n" +
+		"* [ ] New task\n"
+	var plan model.Plan
+	if err := decodePlan("plan.md", []byte(md), &plan); err != nil {
+		t.Fatalf("decodePlan: %v", err)
+	}
+	want := []model.Task{
+		{ID: "T01", Title: "Research", Status: TaskStatusCompleted},
+		{ID: "T02", Title: "Build", Status: TaskStatusPending},
+		{ID: "T03", Title: "New task", Status: TaskStatusPending},
+	}
+	if !reflect.DeepEqual(plan.Tasks, want) {
+		t.Fatalf("unexpected tasks %+v", plan.Tasks)
+	}
+}
+
+func TestConvertPlans_RewritesFilesAndConfig(t *testing.T) {
+	store := storage.NewInMem()
+	if err := store.WriteFile(ConfigPath(".teamwerx"), []byte("# workspace settings\nsecrets:\n  mode: warn\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	app, err := NewAppWithStorage(store)
+	if err != nil {
+		t.Fatalf("NewAppWithStorage: %v", err)
+	}
+	plan := &model.Plan{GoalID: "001-auth", Tasks: []model.Task{{ID: "T01", Title: "Login", Status: TaskStatusPending, Assignee: "@bob"}}}
+	if err := app.PlanManager.Save(plan); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	planFile, converted, err := app.ConvertPlans(PlanFormatMarkdown)
+	if err != nil {
+		t.Fatalf("ConvertPlans: %v", err)
+	}
+	if planFile != "{goal}/plan.md" || len(converted) != 1 {
+		t.Fatalf("unexpected result %q %+v", planFile, converted)
+	}
+	if ok, _ := store.Exists(converted[0].From); ok {
+		t.Fatal("expected the JSON plan to be removed")
+	}
+	cfg, err := store.ReadFile(ConfigPath(".teamwerx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(cfg), "# workspace settings") || !strings.Contains(string(cfg), `plan_file: "{goal}/plan.md"`) {
+		t.Fatalf("unexpected config:\n%s", cfg)
+	}
+
+	// A fresh App reads the markdown plan through the updated config.
+	reopened, err := NewAppWithStorage(store)
+	if err != nil {
+		t.Fatalf("NewAppWithStorage: %v", err)
+	}
+	got, err := reopened.PlanManager.Load("001-auth")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !reflect.DeepEqual(got.Tasks, plan.Tasks) || !got.UpdatedAt.Equal(plan.UpdatedAt) {
+		t.Fatalf("conversion changed the plan: %+v", got)
+	}
+	if _, _, err := reopened.ConvertPlans(PlanFormatMarkdown); err == nil {
+		t.Fatal("expected an error converting to the current format")
+	}
+}
@@ -0,0 +1,92 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// Task statuses with special meaning for progress and goal completion.
+const (
+	TaskStatusPending    = "pending"
+	TaskStatusInProgress = "in-progress"
+	TaskStatusReview     = "review"
+	TaskStatusBlocked    = "blocked"
+	TaskStatusCompleted  = "completed"
+	TaskStatusWaived     = "waived"
+)
+
+// PlanStatusCompleted marks a plan whose goal has been completed.
+const PlanStatusCompleted = "completed"
+
+// ComputePlanProgress counts the plan's tasks by status. Subtasks count like
+// any other task.
+func ComputePlanProgress(plan *model.Plan) model.PlanProgress {
+	p := model.PlanProgress{Total: len(plan.Tasks)}
+	for _, t := range plan.Tasks {
+		switch t.Status {
+		case TaskStatusCompleted:
+			p.Completed++
+		case TaskStatusWaived:
+			p.Waived++
+		case TaskStatusInProgress:
+			p.InProgress++
+		case TaskStatusBlocked:
+			p.Blocked++
+		default:
+			p.Pending++
+		}
+	}
+	return p
+}
+
+// FormatPlanProgress renders progress as e.g. "3/5 complete, 1 blocked".
+// Waived tasks count toward completion and are called out.
+func FormatPlanProgress(p model.PlanProgress) string {
+	s := fmt.Sprintf("%d/%d complete", p.Completed+p.Waived, p.Total)
+	if p.Waived > 0 {
+		s += fmt.Sprintf(" (%d waived)", p.Waived)
+	}
+	if p.Blocked > 0 {
+		s += fmt.Sprintf(", %d blocked", p.Blocked)
+	}
+	return s
+}
+
+// OpenTasks returns the tasks that are neither completed nor waived, in plan order.
+func OpenTasks(plan *model.Plan) []model.Task {
+	var open []model.Task
+	for _, t := range plan.Tasks {
+		if t.Status != TaskStatusCompleted && t.Status != TaskStatusWaived {
+			open = append(open, t)
+		}
+	}
+	return open
+}
+
+// CompleteGoal marks the plan's goal completed. Every task must be completed
+// or waived (see PlanManager.WaiveTask); otherwise ErrConflict lists the open
+// tasks and the plan is left unchanged. The caller saves the plan.
+func CompleteGoal(plan *model.Plan) error {
+	if plan == nil {
+		return custom_errors.NewErrConflict("plan cannot be nil")
+	}
+	if plan.Status == PlanStatusCompleted {
+		return custom_errors.NewErrConflict(fmt.Sprintf("goal %s is already completed", plan.GoalID))
+	}
+	if open := OpenTasks(plan); len(open) > 0 {
+		ids := make([]string, len(open))
+		for i, t := range open {
+			ids[i] = t.ID
+		}
+		return custom_errors.NewErrConflict(fmt.Sprintf("goal %s has open tasks: %s (complete or waive them first)", plan.GoalID, strings.Join(ids, ", ")))
+	}
+	now := time.Now()
+	plan.Status = PlanStatusCompleted
+	plan.CompletedAt = &now
+	plan.Progress = ComputePlanProgress(plan)
+	return nil
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// Actions offered for each task of a plan triage session.
+const (
+	TriageComplete = "complete"
+	TriageSkip     = "skip"
+	TriageEdit     = "edit"
+	TriageAssign   = "assign"
+	TriageDefer    = "defer"
+)
+
+// TriageActions lists the triage actions in the order they are offered.
+var TriageActions = []string{TriageComplete, TriageSkip, TriageEdit, TriageAssign, TriageDefer}
+
+// TriageItem is an open task up for review, with notes on what may need
+// attention.
+type TriageItem struct {
+	Task  model.Task `json:"task"`
+	Notes []string   `json:"notes,omitempty"`
+}
+
+// TriagePlan returns the plan's open tasks in plan order, each noted when it
+// is blocked, unassigned, not linked to a requirement, or a parent whose
+// subtasks are all done and so can likely be completed.
+func TriagePlan(plan *model.Plan) []TriageItem {
+	var items []TriageItem
+	for _, t := range OpenTasks(plan) {
+		item := TriageItem{Task: t}
+		if t.Status == TaskStatusBlocked {
+			item.Notes = append(item.Notes, "blocked")
+		}
+		if t.Assignee == "" {
+			item.Notes = append(item.Notes, "unassigned")
+		}
+		if len(t.Requirements) == 0 {
+			item.Notes = append(item.Notes, "no linked requirements")
+		}
+		if children := descendantTasks(plan.Tasks, t.ID); len(children) > 0 {
+			done := true
+			for _, c := range children {
+				if c.Status != TaskStatusCompleted && c.Status != TaskStatusWaived {
+					done = false
+					break
+				}
+			}
+			if done {
+				item.Notes = append(item.Notes, fmt.Sprintf("all %d subtask(s) done", len(children)))
+			}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// DeferTask moves the task and its subtasks to the end of the plan, keeping
+// their relative order, so the next triage reviews them last. The caller
+// saves the plan.
+func DeferTask(plan *model.Plan, taskID string) error {
+	if plan == nil {
+		return custom_errors.NewErrConflict("plan cannot be nil")
+	}
+	task := findTask(plan.Tasks, taskID)
+	if task == nil {
+		return custom_errors.NewErrNotFound("task", taskID)
+	}
+	moved := map[string]bool{task.ID: true}
+	for _, c := range descendantTasks(plan.Tasks, task.ID) {
+		moved[c.ID] = true
+	}
+	var kept, tail []model.Task
+	for _, t := range plan.Tasks {
+		if moved[t.ID] {
+			tail = append(tail, t)
+		} else {
+			kept = append(kept, t)
+		}
+	}
+	plan.Tasks = append(kept, tail...)
+	plan.UpdatedAt = time.Now()
+	return nil
+}
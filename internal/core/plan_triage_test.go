@@ -0,0 +1,46 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+func TestTriagePlan_NotesAndDefer(t *testing.T) {
+	plan := &model.Plan{GoalID: "001-auth", Tasks: []model.Task{
+		{ID: "T01", Title: "Design", Status: TaskStatusCompleted},
+		{ID: "T02", Title: "Build", Status: TaskStatusPending, Assignee: "@ana", Requirements: []string{"auth/login"}},
+		{ID: "T03", Title: "Login form", Status: TaskStatusCompleted, ParentID: "T02"},
+		{ID: "T04", Title: "Docs", Status: TaskStatusBlocked},
+		{ID: "T05", Title: "Release", Status: TaskStatusPending, Assignee: "@bo", Requirements: []string{"auth/login"}},
+	}}
+
+	items := TriagePlan(plan)
+	if len(items) != 3 || items[0].Task.ID != "T02" || items[1].Task.ID != "T04" || items[2].Task.ID != "T05" {
+		t.Fatalf("expected the open tasks in plan order, got %+v", items)
+	}
+	if want := []string{"all 1 subtask(s) done"}; !reflect.DeepEqual(items[0].Notes, want) {
+		t.Fatalf("T02 notes = %v, want %v", items[0].Notes, want)
+	}
+	if want := []string{"blocked", "unassigned", "no linked requirements"}; !reflect.DeepEqual(items[1].Notes, want) {
+		t.Fatalf("T04 notes = %v, want %v", items[1].Notes, want)
+	}
+	if len(items[2].Notes) != 0 {
+		t.Fatalf("T05 should have no notes, got %v", items[2].Notes)
+	}
+
+	if err := DeferTask(plan, "t02"); err != nil {
+		t.Fatalf("DeferTask: %v", err)
+	}
+	var order []string
+	for _, task := range plan.Tasks {
+		order = append(order, task.ID)
+	}
+	if want := []string{"T01", "T04", "T05", "T02", "T03"}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("order after defer = %v, want %v", order, want)
+	}
+	if err := DeferTask(plan, "T99"); err == nil {
+		t.Fatalf("expected deferring an unknown task to fail")
+	}
+}
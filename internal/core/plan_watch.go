@@ -0,0 +1,94 @@
+package core
+
+import (
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/utils"
+)
+
+// GoalUpdate is what changed in a goal since the last GoalWatcher poll.
+type GoalUpdate struct {
+	// Plan is the reloaded plan when plan.json changed, and on the first poll.
+	Plan *model.Plan
+	// Entries are the discussion entries added since the last poll, in log
+	// order. Entries present on the first poll count as already seen.
+	Entries []model.DiscussionEntry
+}
+
+// GoalWatcher polls a goal's plan and discussion files through the App's
+// storage, so it works on any backend without file system notifications.
+// Files are compared by fingerprint, and only reloaded when they change.
+type GoalWatcher struct {
+	app    *App
+	goalID string
+
+	polled bool
+	planFP string
+	discFP string
+	seen   map[string]bool
+}
+
+// WatchGoal returns a watcher for goalID. Nothing is read until Poll.
+func (a *App) WatchGoal(goalID string) *GoalWatcher {
+	return &GoalWatcher{app: a, goalID: goalID, seen: map[string]bool{}}
+}
+
+// Poll reports what changed since the previous call, or nil when nothing
+// did. A goal without a plan is an ErrNotFound. After an error the next
+// poll retries the same files, so a watcher survives transient failures
+// such as a file caught mid-edit.
+func (w *GoalWatcher) Poll() (*GoalUpdate, error) {
+	o := w.app.Options
+	planFP, err := w.fingerprint(o.Layout.PlanPath(o.GoalsDir, w.goalID))
+	if err != nil {
+		return nil, err
+	}
+	if planFP == "" {
+		return nil, custom_errors.NewErrNotFound("goal", w.goalID)
+	}
+	discFP, err := w.fingerprint(o.Layout.DiscussionPath(o.GoalsDir, w.goalID))
+	if err != nil {
+		return nil, err
+	}
+
+	update := &GoalUpdate{}
+	if !w.polled || planFP != w.planFP {
+		if update.Plan, err = w.app.PlanManager.Load(w.goalID); err != nil {
+			return nil, err
+		}
+	}
+	if !w.polled || discFP != w.discFP {
+		entries, err := w.app.DiscussionManager.Load(w.goalID)
+		if err != nil {
+			if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+				return nil, err
+			}
+		}
+		for _, e := range entries {
+			if !w.seen[e.ID] {
+				w.seen[e.ID] = true
+				if w.polled {
+					update.Entries = append(update.Entries, e)
+				}
+			}
+		}
+	}
+
+	w.planFP, w.discFP, w.polled = planFP, discFP, true
+	if update.Plan == nil && len(update.Entries) == 0 {
+		return nil, nil
+	}
+	return update, nil
+}
+
+// fingerprint hashes the file at path, or returns "" when it is missing.
+func (w *GoalWatcher) fingerprint(path string) (string, error) {
+	data, err := w.app.Options.Storage.ReadFile(path)
+	if err != nil {
+		if _, ok := err.(*custom_errors.ErrNotFound); ok {
+			return "", nil
+		}
+		return "", err
+	}
+	return utils.GenerateFingerprint(string(data)), nil
+}
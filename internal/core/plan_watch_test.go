@@ -0,0 +1,63 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	ce "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestGoalWatcher_ReportsPlanChangesAndNewEntries(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatalf("NewApp: %v", err)
+	}
+	if _, err := app.WatchGoal("001-auth").Poll(); !errors.As(err, new(*ce.ErrNotFound)) {
+		t.Fatalf("expected ErrNotFound for a missing goal, got %v", err)
+	}
+
+	plan := &model.Plan{GoalID: "001-auth", Tasks: []model.Task{{ID: "T01", Title: "Login", Status: "pending"}}}
+	if err := app.PlanManager.Save(plan); err != nil {
+		t.Fatalf("Save plan: %v", err)
+	}
+	if err := app.DiscussionManager.AddEntry("001-auth", &model.DiscussionEntry{Type: "discussion", Content: "Kickoff"}); err != nil {
+		t.Fatalf("AddEntry: %v", err)
+	}
+
+	w := app.WatchGoal("001-auth")
+	first, err := w.Poll()
+	if err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if first == nil || first.Plan == nil || len(first.Entries) != 0 {
+		t.Fatalf("expected the plan and no new entries on the first poll, got %+v", first)
+	}
+	if u, err := w.Poll(); err != nil || u != nil {
+		t.Fatalf("expected no update when nothing changed, got %+v (err %v)", u, err)
+	}
+
+	if err := app.DiscussionManager.AddEntry("001-auth", &model.DiscussionEntry{Type: "discussion", Content: "Started T01"}); err != nil {
+		t.Fatalf("AddEntry: %v", err)
+	}
+	u, err := w.Poll()
+	if err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if u == nil || u.Plan != nil || len(u.Entries) != 1 || u.Entries[0].Content != "Started T01" {
+		t.Fatalf("expected only the new entry, got %+v", u)
+	}
+
+	plan.Tasks[0].Status = TaskStatusCompleted
+	if err := app.PlanManager.Save(plan); err != nil {
+		t.Fatalf("Save plan: %v", err)
+	}
+	u, err = w.Poll()
+	if err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if u == nil || u.Plan == nil || u.Plan.Tasks[0].Status != TaskStatusCompleted || len(u.Entries) != 0 {
+		t.Fatalf("expected the reloaded plan, got %+v", u)
+	}
+}
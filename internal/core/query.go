@@ -0,0 +1,429 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// Query subjects: what a Query returns.
+const (
+	QueryTasks        = "tasks"
+	QueryRequirements = "requirements"
+	QueryChanges      = "changes"
+)
+
+// QuerySubjects lists the subjects a Query can ask about.
+var QuerySubjects = []string{QueryTasks, QueryRequirements, QueryChanges}
+
+// Query is a read-only question about the workspace: the records of one
+// subject that pass every filter, e.g. the tasks with goal=001-auth and
+// status=open.
+type Query struct {
+	Subject string
+	Filters []QueryFilter
+}
+
+// QueryFilter keeps the records whose key matches one of Values.
+type QueryFilter struct {
+	Key    string
+	Values []string
+}
+
+// String renders q the way ParseQuery reads it.
+func (q Query) String() string {
+	parts := []string{q.Subject}
+	for _, f := range q.Filters {
+		parts = append(parts, f.Key+"="+strings.Join(f.Values, ","))
+	}
+	return strings.Join(parts, " ")
+}
+
+// ParseQuery reads a subject followed by key=value filters. A value may
+// list alternatives separated by commas.
+func ParseQuery(args []string) (Query, error) {
+	if len(args) == 0 {
+		return Query{}, custom_errors.NewErrValidation("query", fmt.Sprintf("missing subject (want %s)", strings.Join(QuerySubjects, ", ")))
+	}
+	q := Query{Subject: strings.ToLower(strings.TrimSpace(args[0]))}
+	keys := QueryKeys(q.Subject)
+	if keys == nil {
+		return Query{}, custom_errors.NewErrValidation("query", fmt.Sprintf("unknown subject %q (want %s)", args[0], strings.Join(QuerySubjects, ", ")))
+	}
+	var errs custom_errors.ValidationErrors
+	for _, arg := range args[1:] {
+		key, value, ok := strings.Cut(arg, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !ok || key == "" {
+			errs.Add("query", fmt.Sprintf("%q is not a key=value filter", arg))
+			continue
+		}
+		if !containsString(keys, key) {
+			errs.Add("query."+key, fmt.Sprintf("unknown filter for %s (want one of %s)", q.Subject, strings.Join(keys, ", ")))
+			continue
+		}
+		var values []string
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		if len(values) == 0 {
+			errs.Add("query."+key, "needs a value")
+			continue
+		}
+		q.Filters = append(q.Filters, QueryFilter{Key: key, Values: values})
+	}
+	if err := errs.Err(); err != nil {
+		return Query{}, err
+	}
+	return q, nil
+}
+
+// QueryKeys returns the filter keys of subject in sorted order, or nil for
+// an unknown subject.
+func QueryKeys(subject string) []string {
+	var keys []string
+	switch subject {
+	case QueryTasks:
+		for k := range taskPredicates {
+			keys = append(keys, k)
+		}
+	case QueryRequirements:
+		for k := range requirementPredicates {
+			keys = append(keys, k)
+		}
+	case QueryChanges:
+		for k := range changePredicates(time.Time{}) {
+			keys = append(keys, k)
+		}
+	default:
+		return nil
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// QueryAnswer holds the records a Query matched: Results is a
+// []TaskRecord, []RequirementRecord or []ChangeRecord by subject.
+type QueryAnswer struct {
+	Query   string      `json:"query"`
+	Subject string      `json:"subject"`
+	Count   int         `json:"count"`
+	Results interface{} `json:"results"`
+}
+
+// TaskRecord is a task of a goal.
+type TaskRecord struct {
+	GoalID string `json:"goal_id"`
+	model.Task
+}
+
+// RequirementRecord is a requirement with its domain's tags and the tasks
+// linking to it, as "<goal>/<task-id>".
+type RequirementRecord struct {
+	Domain    string   `json:"domain"`
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Priority  string   `json:"priority,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	Tasks     []string `json:"tasks"`
+	OpenTasks int      `json:"open_tasks"`
+}
+
+// ChangeRecord is a live change with the domains it touches and those still
+// awaiting owner approval.
+type ChangeRecord struct {
+	ID               string    `json:"id"`
+	Title            string    `json:"title"`
+	Status           string    `json:"status"`
+	GoalID           string    `json:"goal_id,omitempty"`
+	Author           string    `json:"author,omitempty"`
+	Milestone        string    `json:"milestone,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	Domains          []string  `json:"domains"`
+	AwaitingApproval []string  `json:"awaiting_approval,omitempty"`
+}
+
+// anyValue reports whether match holds for one of values.
+func anyValue(values []string, match func(v string) (bool, error)) (bool, error) {
+	for _, v := range values {
+		ok, err := match(v)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+// taskPredicates are the task filters. status also takes "open" (neither
+// completed nor waived) and "closed"; field takes "<key>=<value>".
+var taskPredicates = map[string]func(t TaskRecord, v string) (bool, error){
+	"goal": func(t TaskRecord, v string) (bool, error) { return strings.EqualFold(t.GoalID, v), nil },
+	"status": func(t TaskRecord, v string) (bool, error) {
+		switch strings.ToLower(v) {
+		case "open":
+			return !isTaskClosed(t.Task), nil
+		case "closed":
+			return isTaskClosed(t.Task), nil
+		}
+		return strings.EqualFold(t.Status, v), nil
+	},
+	"assignee": func(t TaskRecord, v string) (bool, error) {
+		return strings.EqualFold(strings.TrimPrefix(t.Assignee, "@"), strings.TrimPrefix(v, "@")), nil
+	},
+	"requirement": func(t TaskRecord, v string) (bool, error) {
+		for _, link := range t.Requirements {
+			if strings.EqualFold(link, v) {
+				return true, nil
+			}
+		}
+		return false, nil
+	},
+	"domain": func(t TaskRecord, v string) (bool, error) {
+		for _, link := range t.Requirements {
+			if domain, _, _ := strings.Cut(link, "/"); strings.EqualFold(domain, v) {
+				return true, nil
+			}
+		}
+		return false, nil
+	},
+	"label": func(t TaskRecord, v string) (bool, error) {
+		return TaskFilter{Labels: []string{v}}.Matches(t.Task), nil
+	},
+	"field": func(t TaskRecord, v string) (bool, error) {
+		fields, err := ParseTaskFields([]string{v})
+		if err != nil {
+			return false, err
+		}
+		return TaskFilter{Fields: fields}.Matches(t.Task), nil
+	},
+}
+
+// requirementPredicates are the requirement filters. tasks is "none" (no
+// linked task), "any", "open" (an open linked task) or "done" (only closed
+// ones); priority "none" matches requirements without one.
+var requirementPredicates = map[string]func(r RequirementRecord, v string) (bool, error){
+	"domain": func(r RequirementRecord, v string) (bool, error) { return strings.EqualFold(r.Domain, v), nil },
+	"tag": func(r RequirementRecord, v string) (bool, error) {
+		return containsFold(r.Tags, v), nil
+	},
+	"priority": func(r RequirementRecord, v string) (bool, error) {
+		v = strings.ToLower(v)
+		if err := ValidatePriority(v); err != nil {
+			return false, err
+		}
+		return MatchesPriority(model.Requirement{Priority: r.Priority}, v), nil
+	},
+	"tasks": func(r RequirementRecord, v string) (bool, error) {
+		switch strings.ToLower(v) {
+		case "none":
+			return len(r.Tasks) == 0, nil
+		case "any":
+			return len(r.Tasks) > 0, nil
+		case "open":
+			return r.OpenTasks > 0, nil
+		case "done":
+			return len(r.Tasks) > 0 && r.OpenTasks == 0, nil
+		}
+		return false, custom_errors.NewErrValidation("query.tasks", fmt.Sprintf("unknown value %q (want none, any, open or done)", v))
+	},
+}
+
+// changePredicates are the change filters, with ages measured from now.
+// status also takes "pending" (neither applied nor archived); awaiting is
+// "approval" (some domain lacks owner sign-off) or a domain awaiting it;
+// older and newer take an age such as "7d" or "36h", or a date.
+func changePredicates(now time.Time) map[string]func(c ChangeRecord, v string) (bool, error) {
+	age := func(v string) (time.Time, error) {
+		t, err := ParseSince(v, now)
+		if err != nil {
+			return t, custom_errors.NewErrValidation("query", fmt.Sprintf("invalid age %q (expected YYYY-MM-DD, an RFC 3339 time, or an age like 36h or 7d)", v))
+		}
+		return t, nil
+	}
+	return map[string]func(c ChangeRecord, v string) (bool, error){
+		"status": func(c ChangeRecord, v string) (bool, error) {
+			if strings.EqualFold(v, "pending") {
+				return IsPendingChange(&model.Change{Status: c.Status}), nil
+			}
+			return strings.EqualFold(c.Status, v), nil
+		},
+		"goal":      func(c ChangeRecord, v string) (bool, error) { return strings.EqualFold(c.GoalID, v), nil },
+		"author":    func(c ChangeRecord, v string) (bool, error) { return strings.EqualFold(c.Author, v), nil },
+		"milestone": func(c ChangeRecord, v string) (bool, error) { return strings.EqualFold(c.Milestone, v), nil },
+		"domain":    func(c ChangeRecord, v string) (bool, error) { return containsFold(c.Domains, v), nil },
+		"awaiting": func(c ChangeRecord, v string) (bool, error) {
+			if strings.EqualFold(v, "approval") {
+				return len(c.AwaitingApproval) > 0, nil
+			}
+			return containsFold(c.AwaitingApproval, v), nil
+		},
+		"older": func(c ChangeRecord, v string) (bool, error) {
+			t, err := age(v)
+			return err == nil && c.CreatedAt.Before(t), err
+		},
+		"newer": func(c ChangeRecord, v string) (bool, error) {
+			t, err := age(v)
+			return err == nil && !c.CreatedAt.Before(t), err
+		},
+	}
+}
+
+// Ask answers q from the workspace without modifying it. Records come in
+// natural ID order: tasks by goal, requirements by domain.
+func (a *App) Ask(q Query, now time.Time) (*QueryAnswer, error) {
+	ans := &QueryAnswer{Query: q.String(), Subject: q.Subject}
+	switch q.Subject {
+	case QueryTasks:
+		tasks, err := a.taskRecords()
+		if err != nil {
+			return nil, err
+		}
+		out := []TaskRecord{}
+		for _, t := range tasks {
+			ok, err := matchFilters(q.Filters, func(key, v string) (bool, error) { return taskPredicates[key](t, v) })
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				out = append(out, t)
+			}
+		}
+		ans.Count, ans.Results = len(out), out
+	case QueryRequirements:
+		reqs, err := a.requirementRecords()
+		if err != nil {
+			return nil, err
+		}
+		out := []RequirementRecord{}
+		for _, r := range reqs {
+			ok, err := matchFilters(q.Filters, func(key, v string) (bool, error) { return requirementPredicates[key](r, v) })
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				out = append(out, r)
+			}
+		}
+		ans.Count, ans.Results = len(out), out
+	case QueryChanges:
+		changes, err := a.changeRecords()
+		if err != nil {
+			return nil, err
+		}
+		predicates := changePredicates(now)
+		out := []ChangeRecord{}
+		for _, c := range changes {
+			ok, err := matchFilters(q.Filters, func(key, v string) (bool, error) { return predicates[key](c, v) })
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				out = append(out, c)
+			}
+		}
+		ans.Count, ans.Results = len(out), out
+	default:
+		return nil, custom_errors.NewErrValidation("query", fmt.Sprintf("unknown subject %q (want %s)", q.Subject, strings.Join(QuerySubjects, ", ")))
+	}
+	return ans, nil
+}
+
+// matchFilters reports whether every filter has a value match accepts.
+func matchFilters(filters []QueryFilter, match func(key, v string) (bool, error)) (bool, error) {
+	for _, f := range filters {
+		ok, err := anyValue(f.Values, func(v string) (bool, error) { return match(f.Key, v) })
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// taskRecords lists the tasks of every goal.
+func (a *App) taskRecords() ([]TaskRecord, error) {
+	ids, err := ListGoalIDs(a.Options)
+	if err != nil {
+		return nil, err
+	}
+	var out []TaskRecord
+	for _, id := range ids {
+		plan, err := a.PlanManager.Load(id)
+		if err != nil {
+			if _, ok := err.(*custom_errors.ErrNotFound); ok {
+				continue
+			}
+			return nil, err
+		}
+		for _, t := range plan.Tasks {
+			out = append(out, TaskRecord{GoalID: id, Task: t})
+		}
+	}
+	return out, nil
+}
+
+// requirementRecords lists the requirements of every spec with the tasks
+// linking to them, through renamed IDs.
+func (a *App) requirementRecords() ([]RequirementRecord, error) {
+	specs, err := a.SpecManager.ListSpecs()
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := a.taskRecords()
+	if err != nil {
+		return nil, err
+	}
+	refs := newRequirementRefs(specs)
+	linked, open := map[string][]string{}, map[string]int{}
+	for _, t := range tasks {
+		for _, link := range t.Requirements {
+			key := refs.key(link)
+			linked[key] = append(linked[key], t.GoalID+"/"+t.ID)
+			if !isTaskClosed(t.Task) {
+				open[key]++
+			}
+		}
+	}
+	var out []RequirementRecord
+	for _, spec := range specs {
+		for _, r := range spec.Requirements {
+			key := strings.ToLower(spec.Domain + "/" + r.ID)
+			rec := RequirementRecord{Domain: spec.Domain, ID: r.ID, Title: r.Title, Priority: r.Priority, Tags: spec.Meta.Tags, Tasks: linked[key], OpenTasks: open[key]}
+			if rec.Tasks == nil {
+				rec.Tasks = []string{}
+			}
+			out = append(out, rec)
+		}
+	}
+	return out, nil
+}
+
+// changeRecords lists the live changes in ID order.
+func (a *App) changeRecords() ([]ChangeRecord, error) {
+	changes, err := a.ChangeManager.ListChanges()
+	if err != nil {
+		return nil, err
+	}
+	if err := SortChanges(changes, "", ""); err != nil {
+		return nil, err
+	}
+	var out []ChangeRecord
+	for _, ch := range changes {
+		rec := ChangeRecord{ID: ch.ID, Title: ch.Title, Status: ch.Status, GoalID: ch.GoalID, Author: ch.Author, Milestone: ch.Milestone, CreatedAt: ch.CreatedAt, Domains: []string{}}
+		for _, d := range ch.SpecDeltas {
+			if !containsString(rec.Domains, d.Domain) {
+				rec.Domains = append(rec.Domains, d.Domain)
+			}
+		}
+		if IsPendingChange(ch) {
+			rec.AwaitingApproval = MissingApprovals(ch)
+		}
+		out = append(out, rec)
+	}
+	return out, nil
+}
@@ -0,0 +1,84 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestAsk(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatalf("NewApp: %v", err)
+	}
+	now := time.Now()
+	if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "auth", Content: "---\ntags: [security]\n---\n# Auth\n\n### Requirement: Login\n\nUsers log in.\n\n### Requirement: Logout\n\nUsers log out.\n"}); err != nil {
+		t.Fatalf("WriteSpec: %v", err)
+	}
+	if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "billing", Content: "# Billing\n\n### Requirement: Invoices\n\nInvoices are sent.\n"}); err != nil {
+		t.Fatalf("WriteSpec: %v", err)
+	}
+	plan := &model.Plan{GoalID: "001-auth"}
+	for _, title := range []string{"Login form", "Session store"} {
+		if _, err := app.PlanManager.AddTask(plan, title); err != nil {
+			t.Fatalf("AddTask: %v", err)
+		}
+	}
+	plan.Tasks[0].Requirements = []string{"auth/login"}
+	plan.Tasks[1].Status = TaskStatusCompleted
+	if err := app.PlanManager.Save(plan); err != nil {
+		t.Fatalf("Save plan: %v", err)
+	}
+	for _, ch := range []*model.Change{
+		{ID: "CH-001", Title: "Old", Status: "submitted", CreatedAt: now.AddDate(0, 0, -10), RequiredApprovers: map[string][]string{"auth": {"ana"}}, SpecDeltas: []model.SpecDelta{{Domain: "auth"}}},
+		{ID: "CH-002", Title: "New", Status: "submitted", CreatedAt: now, RequiredApprovers: map[string][]string{"auth": {"ana"}}},
+		{ID: "CH-003", Title: "Signed", Status: "submitted", CreatedAt: now.AddDate(0, 0, -10), RequiredApprovers: map[string][]string{"auth": {"ana"}}, Approvals: []model.Approval{{Domain: "auth", Approver: "ana"}}},
+	} {
+		if err := app.ChangeManager.Save(ch); err != nil {
+			t.Fatalf("Save change: %v", err)
+		}
+	}
+
+	ask := func(args ...string) *QueryAnswer {
+		t.Helper()
+		q, err := ParseQuery(args)
+		if err != nil {
+			t.Fatalf("ParseQuery(%v): %v", args, err)
+		}
+		ans, err := app.Ask(q, now)
+		if err != nil {
+			t.Fatalf("Ask(%v): %v", args, err)
+		}
+		return ans
+	}
+
+	if ans := ask("tasks", "goal=001-auth", "status=open"); ans.Count != 1 || ans.Results.([]TaskRecord)[0].ID != "T01" {
+		t.Fatalf("open tasks = %+v", ans)
+	}
+	if ans := ask("tasks", "status=open,completed"); ans.Count != 2 {
+		t.Fatalf("open or completed tasks = %+v", ans)
+	}
+	ans := ask("requirements", "tag=security", "tasks=none")
+	if reqs := ans.Results.([]RequirementRecord); ans.Count != 1 || reqs[0].ID != "logout" {
+		t.Fatalf("untasked security requirements = %+v", ans)
+	}
+	ans = ask("changes", "awaiting=approval", "older=7d")
+	if changes := ans.Results.([]ChangeRecord); ans.Count != 1 || changes[0].ID != "CH-001" || changes[0].Domains[0] != "auth" {
+		t.Fatalf("old changes awaiting approval = %+v", ans)
+	}
+	if ans := ask("changes", "status=applied"); ans.Count != 0 || len(ans.Results.([]ChangeRecord)) != 0 {
+		t.Fatalf("applied changes = %+v", ans)
+	}
+
+	for _, args := range [][]string{{"goals"}, {"tasks", "colour=red"}, {"tasks", "open"}, {"tasks", "goal="}} {
+		if _, err := ParseQuery(args); err == nil {
+			t.Fatalf("ParseQuery(%v) accepted", args)
+		}
+	}
+	q, _ := ParseQuery([]string{"changes", "older=soon"})
+	if _, err := app.Ask(q, now); err == nil {
+		t.Fatal("expected an invalid age to be rejected")
+	}
+}
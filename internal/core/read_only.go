@@ -0,0 +1,42 @@
+package core
+
+import (
+	"os"
+	"time"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+// readOnlyStorage refuses every write to the storage it wraps with an
+// ErrReadOnly, so an App opened with AppOptions.ReadOnly can read the
+// workspace but never alter it.
+type readOnlyStorage struct {
+	storage.Storage
+}
+
+func (readOnlyStorage) WriteFile(path string, data []byte, perm os.FileMode) error {
+	return custom_errors.NewErrReadOnly("write", path)
+}
+
+func (readOnlyStorage) MkdirAll(path string, perm os.FileMode) error {
+	return custom_errors.NewErrReadOnly("create", path)
+}
+
+func (readOnlyStorage) Rename(oldpath, newpath string) error {
+	return custom_errors.NewErrReadOnly("move", oldpath)
+}
+
+func (readOnlyStorage) RemoveAll(path string) error {
+	return custom_errors.NewErrReadOnly("remove", path)
+}
+
+func (readOnlyStorage) CopyDir(src, dst string) error {
+	return custom_errors.NewErrReadOnly("copy", src)
+}
+
+// Lock takes no lock: lock files are writes too, and with nothing being
+// written there is nothing to serialize.
+func (readOnlyStorage) Lock(path string, timeout time.Duration) (func() error, error) {
+	return func() error { return nil }, nil
+}
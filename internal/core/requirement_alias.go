@@ -0,0 +1,259 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// Renaming a requirement changes its ID, which references, task links and
+// pending changes may still use. The spec's front matter keeps the old IDs as
+// aliases of the new one:
+//
+//	---
+//	aliases:
+//	  login: password-login
+//	---
+//
+// and every lookup resolves them, reporting the old ID as deprecated.
+
+// DeprecatedRequirementID is a use of a renamed requirement's old ID.
+type DeprecatedRequirementID struct {
+	Domain string `json:"domain"`
+	Alias  string `json:"alias"`
+	ID     string `json:"id"`
+	// Where names the use, e.g. "spec_deltas[0].operations[1]"; empty when
+	// the caller knows it.
+	Where string `json:"where,omitempty"`
+}
+
+func (d DeprecatedRequirementID) String() string {
+	return fmt.Sprintf("%s/%s is deprecated, it was renamed to %s/%s", d.Domain, d.Alias, d.Domain, d.ID)
+}
+
+// ResolveRequirementAlias returns the current ID for id in spec: id itself
+// (spelled as the spec spells it) when the spec has such a requirement,
+// otherwise its target in the alias table, following requirements renamed
+// more than once. deprecated reports whether id was an alias. IDs are
+// compared without regard to case; an unknown id is returned unchanged.
+func ResolveRequirementAlias(spec *model.Spec, id string) (current string, deprecated bool) {
+	id = strings.TrimSpace(id)
+	if spec == nil {
+		return id, false
+	}
+	seen := map[string]bool{}
+	current = id
+	for {
+		for _, r := range spec.Requirements {
+			if strings.EqualFold(r.ID, current) {
+				return r.ID, deprecated
+			}
+		}
+		key := strings.ToLower(current)
+		if seen[key] {
+			return id, false
+		}
+		seen[key] = true
+		next := ""
+		for old, target := range spec.Meta.Aliases {
+			if strings.EqualFold(old, current) {
+				next = strings.TrimSpace(target)
+				break
+			}
+		}
+		if next == "" {
+			if deprecated {
+				return current, true
+			}
+			return id, false
+		}
+		current, deprecated = next, true
+	}
+}
+
+// resolveDeltaAliases returns delta with every operation naming its
+// requirement (and "after" placement hint) by current ID. A MODIFIED
+// requirement written under the old title keeps the current one, so merging
+// a change authored before the rename does not undo it. delta itself is not
+// modified.
+func resolveDeltaAliases(spec *model.Spec, delta *model.SpecDelta) *model.SpecDelta {
+	if spec == nil || len(spec.Meta.Aliases) == 0 {
+		return delta
+	}
+	out := *delta
+	out.Operations = append([]model.DeltaOperation{}, delta.Operations...)
+	for i := range out.Operations {
+		op := &out.Operations[i]
+		if op.After != "" {
+			op.After, _ = ResolveRequirementAlias(spec, op.After)
+		}
+		id, deprecated := ResolveRequirementAlias(spec, op.Requirement.ID)
+		if !deprecated {
+			continue
+		}
+		alias := op.Requirement.ID
+		op.Requirement.ID = id
+		if op.Type != "MODIFIED" {
+			continue
+		}
+		for _, r := range spec.Requirements {
+			if r.ID != id {
+				continue
+			}
+			if requirementHeadingID(op.Requirement.Content) == strings.ToLower(alias) {
+				op.Requirement.Content = retitleRequirement(op.Requirement.Content, r.Title)
+			}
+			if strings.EqualFold(utils.ToKebabCase(op.Requirement.Title), alias) {
+				op.Requirement.Title = r.Title
+			}
+		}
+	}
+	return &out
+}
+
+// requirementHeadingID returns the ID of the requirement heading ("###
+// Requirement: <title>", whatever the prefix) on the first line of content,
+// or "".
+func requirementHeadingID(content string) string {
+	line, _, _ := strings.Cut(strings.TrimLeft(content, "\n"), "\n")
+	if level, text := atxHeading(line); level > 0 {
+		if _, title, ok := strings.Cut(text, ":"); ok {
+			return strings.ToLower(utils.ToKebabCase(strings.TrimSpace(title)))
+		}
+	}
+	return ""
+}
+
+// retitleRequirement replaces the title of the requirement heading on the
+// first line of content, keeping its level and prefix.
+func retitleRequirement(content, title string) string {
+	lead := len(content) - len(strings.TrimLeft(content, "\n"))
+	line, rest, found := strings.Cut(content[lead:], "\n")
+	level, text := atxHeading(line)
+	prefix, _, ok := strings.Cut(text, ":")
+	if level == 0 || !ok {
+		return content
+	}
+	out := content[:lead] + strings.Repeat("#", level) + " " + prefix + ": " + title
+	if found {
+		out += "\n" + rest
+	}
+	return out
+}
+
+// DeprecatedChangeIDs lists the operations of ch that name a requirement, or
+// place one after a requirement, by a deprecated alias.
+func DeprecatedChangeIDs(specs SpecManager, ch *model.Change) ([]DeprecatedRequirementID, error) {
+	var out []DeprecatedRequirementID
+	for i, d := range ch.SpecDeltas {
+		spec, err := specs.ReadSpec(d.Domain)
+		if err != nil {
+			if _, ok := err.(*custom_errors.ErrNotFound); ok {
+				continue
+			}
+			return nil, err
+		}
+		for j, op := range d.Operations {
+			where := fmt.Sprintf("spec_deltas[%d].operations[%d]", i, j)
+			for _, ref := range []string{op.Requirement.ID, op.After} {
+				if ref == "" {
+					continue
+				}
+				if id, deprecated := ResolveRequirementAlias(spec, ref); deprecated {
+					out = append(out, DeprecatedRequirementID{Domain: spec.Domain, Alias: ref, ID: id, Where: where})
+				}
+			}
+		}
+	}
+	return out, nil
+}
+
+// SetRequirementAlias returns spec's content with oldID recorded in the front
+// matter as an alias of the requirement newID; an empty newID removes the
+// alias. oldID may not be the ID of a requirement the spec still has.
+func SetRequirementAlias(spec *model.Spec, oldID, newID string) (string, error) {
+	oldID, newID = strings.ToLower(strings.TrimSpace(oldID)), strings.TrimSpace(newID)
+	if oldID == "" {
+		return "", custom_errors.NewErrValidation("alias", "cannot be empty")
+	}
+	for _, r := range spec.Requirements {
+		if strings.EqualFold(r.ID, oldID) {
+			return "", custom_errors.NewErrConflict(fmt.Sprintf("%s is still a requirement of %s; rename it before aliasing its ID", oldID, spec.Domain))
+		}
+	}
+	aliases := map[string]string{}
+	for old, target := range spec.Meta.Aliases {
+		aliases[strings.ToLower(old)] = target
+	}
+	if newID == "" {
+		if _, ok := aliases[oldID]; !ok {
+			return "", custom_errors.NewErrNotFound("requirement alias", oldID)
+		}
+		delete(aliases, oldID)
+	} else {
+		id, _ := ResolveRequirementAlias(spec, newID)
+		found := false
+		for _, r := range spec.Requirements {
+			found = found || r.ID == id
+		}
+		if !found {
+			return "", custom_errors.NewErrNotFound("requirement", newID)
+		}
+		aliases[oldID] = id
+	}
+
+	if len(aliases) == 0 {
+		return setFrontMatterKey(spec, "aliases", nil)
+	}
+	olds := make([]string, 0, len(aliases))
+	for old := range aliases {
+		olds = append(olds, old)
+	}
+	sort.Strings(olds)
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, old := range olds {
+		node.Content = append(node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: old},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: aliases[old]})
+	}
+	return setFrontMatterKey(spec, "aliases", node)
+}
+
+// requirementRefs resolves "<domain>/<requirement-id>" references through
+// the alias tables of a set of specs.
+type requirementRefs map[string]*model.Spec // by lowercase domain
+
+func newRequirementRefs(specs []*model.Spec) requirementRefs {
+	refs := requirementRefs{}
+	for _, s := range specs {
+		refs[strings.ToLower(s.Domain)] = s
+	}
+	return refs
+}
+
+// resolve returns ref with a deprecated requirement ID replaced by the
+// current one, spelled as the spec spells it. Anything else is returned as is.
+func (r requirementRefs) resolve(ref string) string {
+	domain, id, ok := strings.Cut(strings.TrimSpace(ref), "/")
+	if !ok {
+		return ref
+	}
+	spec := r[strings.ToLower(domain)]
+	if spec == nil {
+		return ref
+	}
+	if current, deprecated := ResolveRequirementAlias(spec, id); deprecated {
+		return spec.Domain + "/" + current
+	}
+	return strings.TrimSpace(ref)
+}
+
+// key is resolve lowercased, for matching references.
+func (r requirementRefs) key(ref string) string {
+	return strings.ToLower(r.resolve(ref))
+}
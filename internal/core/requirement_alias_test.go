@@ -0,0 +1,90 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestRequirementAlias_ResolvesRenamedIDs(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatalf("NewApp: %v", err)
+	}
+	content := "# Auth\n\n### Requirement: Password Login\n\nUsers log in with a password.\n\n### Requirement: Logout\n\nUsers log out.\n"
+	if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "auth", Content: content}); err != nil {
+		t.Fatalf("WriteSpec: %v", err)
+	}
+	spec, err := app.SpecManager.ReadSpec("auth")
+	if err != nil {
+		t.Fatalf("ReadSpec: %v", err)
+	}
+	if _, err := SetRequirementAlias(spec, "logout", "password-login"); err == nil {
+		t.Fatalf("expected aliasing a current requirement ID to be refused")
+	}
+	if _, err := SetRequirementAlias(spec, "login", "missing"); err == nil {
+		t.Fatalf("expected an alias of a missing requirement to be refused")
+	}
+	updated, err := SetRequirementAlias(spec, "Login", "password-login")
+	if err != nil {
+		t.Fatalf("SetRequirementAlias: %v", err)
+	}
+	if !strings.HasPrefix(updated, "---\naliases:\n  login: password-login\n---\n") {
+		t.Fatalf("expected the alias in front matter, got:\n%s", updated)
+	}
+	if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "auth", Content: updated}); err != nil {
+		t.Fatalf("WriteSpec: %v", err)
+	}
+	spec, _ = app.SpecManager.ReadSpec("auth")
+
+	if id, deprecated := ResolveRequirementAlias(spec, "LOGIN"); id != "password-login" || !deprecated {
+		t.Fatalf("expected login to resolve to password-login, got %q %v", id, deprecated)
+	}
+	if id, deprecated := ResolveRequirementAlias(spec, "logout"); id != "logout" || deprecated {
+		t.Fatalf("expected a current ID to resolve to itself, got %q %v", id, deprecated)
+	}
+	chained := &model.Spec{Requirements: spec.Requirements, Meta: model.SpecMeta{Aliases: map[string]string{"signin": "login", "login": "password-login", "a": "b", "b": "a"}}}
+	if id, deprecated := ResolveRequirementAlias(chained, "signin"); id != "password-login" || !deprecated {
+		t.Fatalf("expected alias chains to be followed, got %q %v", id, deprecated)
+	}
+	if id, deprecated := ResolveRequirementAlias(chained, "a"); id != "a" || deprecated {
+		t.Fatalf("expected an alias cycle to resolve to nothing, got %q %v", id, deprecated)
+	}
+
+	// A change written before the rename still applies to the requirement.
+	ch := &model.Change{ID: "CH-001", Title: "Tighten login", Status: "pending", SpecDeltas: []model.SpecDelta{{
+		Domain: "auth",
+		Operations: []model.DeltaOperation{{Type: "MODIFIED", Requirement: model.Requirement{
+			ID: "login", Title: "Login", Content: "### Requirement: Login\n\nUsers log in with a password and a second factor.\n",
+		}}},
+	}}}
+	if err := ValidateChangeDeltas(app.SpecManager, ch); err != nil {
+		t.Fatalf("expected the old ID to validate, got %v", err)
+	}
+	uses, err := DeprecatedChangeIDs(app.SpecManager, ch)
+	if err != nil || len(uses) != 1 || uses[0].Alias != "login" || uses[0].ID != "password-login" || uses[0].Where != "spec_deltas[0].operations[0]" {
+		t.Fatalf("expected one deprecated use, got %+v (err %v)", uses, err)
+	}
+	if err := app.SpecMerger.Merge(&ch.SpecDeltas[0]); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	spec, _ = app.SpecManager.ReadSpec("auth")
+	if len(spec.Requirements) != 2 || spec.Requirements[0].ID != "password-login" || !strings.Contains(spec.Requirements[0].Content, "second factor") {
+		t.Fatalf("expected password-login to be modified in place, got %+v", spec.Requirements)
+	}
+	if ch.SpecDeltas[0].Operations[0].Requirement.ID != "login" {
+		t.Fatalf("expected the change itself to be left as written")
+	}
+
+	refs, err := app.ResolveEntryRefs("001-auth", []string{"auth/login"})
+	if err != nil || len(refs) != 1 || refs[0] != "auth/password-login" {
+		t.Fatalf("expected the entry ref to resolve to the new ID, got %v (err %v)", refs, err)
+	}
+
+	removed, err := SetRequirementAlias(spec, "login", "")
+	if err != nil || strings.HasPrefix(removed, "---") {
+		t.Fatalf("expected removing the last alias to drop the front matter, got %q (err %v)", removed, err)
+	}
+}
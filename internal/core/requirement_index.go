@@ -0,0 +1,262 @@
+package core
+
+import (
+	"crypto/sha256"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/utils"
+)
+
+// requirementRange is where one requirement block sits in a spec file.
+type requirementRange struct {
+	ID         string
+	Start, End int
+}
+
+// requirementIndex is the cached layout of a spec file: its front matter and
+// requirement offsets. sum hashes the file, so an edited file is rescanned.
+type requirementIndex struct {
+	sum    [sha256.Size]byte
+	meta   model.SpecMeta
+	ranges []requirementRange
+}
+
+// ReadRequirement returns one requirement of domain, resolving renamed IDs
+// through the spec's aliases. Only the requirement's own block is parsed:
+// its offsets come from the index cached by the last read or write of the
+// spec, or from a scan of the heading lines. Offsets in the result are into
+// the whole file, as with ReadSpec. Specs with "!include" lines are read in
+// full.
+func (m *specManager) ReadRequirement(domain, reqID string) (*model.Requirement, error) {
+	path := m.layout.SpecPath(m.baseDir, domain)
+	raw, err := m.store.ReadFile(path)
+	if err != nil {
+		if _, ok := err.(*custom_errors.ErrNotFound); ok {
+			return nil, custom_errors.NewErrNotFound("spec", domain)
+		}
+		return nil, err
+	}
+	if strings.Contains(string(raw), "!include") {
+		return m.readRequirementFull(domain, reqID)
+	}
+
+	idx, err := m.requirementIndex(path, raw)
+	if err != nil {
+		return nil, err
+	}
+	stub := &model.Spec{Meta: idx.meta}
+	for _, r := range idx.ranges {
+		stub.Requirements = append(stub.Requirements, model.Requirement{ID: r.ID})
+	}
+	id, _ := ResolveRequirementAlias(stub, reqID)
+	for _, rng := range idx.ranges {
+		if rng.ID != id {
+			continue
+		}
+		block := raw[rng.Start:rng.End]
+		parsed, err := m.parser.Parse(block)
+		if err != nil {
+			return nil, err
+		}
+		// A scan that disagrees with the parser (e.g. a setext heading
+		// inside the block) falls back to the full read.
+		if len(parsed.Blocks) != 1 || len(parsed.Requirements) != 1 || parsed.Requirements[0].ID != id {
+			return m.readRequirementFull(domain, reqID)
+		}
+		r := parsed.Requirements[0]
+		r.Fingerprint = RequirementFingerprint(parsed, r)
+		shiftRequirement(&r, rng.Start)
+		return &r, nil
+	}
+	return nil, custom_errors.NewErrNotFound("requirement", reqID)
+}
+
+// readRequirementFull reads the whole spec and returns reqID from it.
+func (m *specManager) readRequirementFull(domain, reqID string) (*model.Requirement, error) {
+	spec, err := m.ReadSpec(domain)
+	if err != nil {
+		return nil, err
+	}
+	id, _ := ResolveRequirementAlias(spec, reqID)
+	for i := range spec.Requirements {
+		if spec.Requirements[i].ID == id {
+			return &spec.Requirements[i], nil
+		}
+	}
+	return nil, custom_errors.NewErrNotFound("requirement", reqID)
+}
+
+// requirementIndex returns the index of the spec file at path, from the
+// cache when raw is unchanged since it was built.
+func (m *specManager) requirementIndex(path string, raw []byte) (*requirementIndex, error) {
+	sum := sha256.Sum256(raw)
+	m.mu.Lock()
+	idx, ok := m.index[path]
+	m.mu.Unlock()
+	if ok && idx.sum == sum {
+		return idx, nil
+	}
+	idx = &requirementIndex{sum: sum, ranges: scanRequirementRanges(raw, m.parser.Format())}
+	if n := frontMatterLen(raw); n > 0 {
+		meta, err := parseSpecMeta(string(raw[:n]))
+		if err != nil {
+			return nil, err
+		}
+		idx.meta = meta
+	}
+	m.storeIndex(path, idx)
+	return idx, nil
+}
+
+// indexSpec caches the requirement offsets of a spec just parsed from raw.
+// Specs with includes are not indexed: their offsets are into the expanded
+// content, not the file.
+func (m *specManager) indexSpec(path string, raw []byte, spec *model.Spec) {
+	if len(spec.Includes) > 0 {
+		return
+	}
+	idx := &requirementIndex{sum: sha256.Sum256(raw), meta: spec.Meta}
+	for _, r := range spec.Requirements {
+		idx.ranges = append(idx.ranges, requirementRange{ID: r.ID, Start: r.Start, End: r.End})
+	}
+	m.storeIndex(path, idx)
+}
+
+func (m *specManager) storeIndex(path string, idx *requirementIndex) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.index == nil {
+		m.index = map[string]*requirementIndex{}
+	}
+	m.index[path] = idx
+}
+
+// scanRequirementRanges finds the requirement blocks of a spec file from its
+// heading lines, skipping front matter and fenced code. A block runs from
+// its requirement heading (as format says, or with the slips Parse
+// tolerates) to the next heading of level 1-3, ATX or setext, that is not
+// below the requirement's own level.
+func scanRequirementRanges(raw []byte, format SpecFormat) []requirementRange {
+	format = format.WithDefaults()
+	loose := format.loosePrefix()
+	var out []requirementRange
+	open := -1
+	fence := ""
+	// para is the start of the paragraph the previous line belongs to, or
+	// -1: a setext underline turns it into a heading.
+	para := -1
+	pos := frontMatterLen(raw)
+	for _, line := range strings.SplitAfter(string(raw[pos:]), "\n") {
+		start := pos
+		pos += len(line)
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+			para = -1
+			continue
+		}
+		headingStart := start
+		level, title := atxHeading(line)
+		if level == 0 && para >= 0 && setextUnderline(line) {
+			level, headingStart = 2, para
+			if trimmed[0] == '=' {
+				level = 1
+			}
+			title = strings.Join(strings.Fields(string(raw[para:start])), " ")
+		}
+		if level == 0 {
+			if m := unspacedHeading.FindStringSubmatch(strings.TrimRight(line, "\r\n")); m != nil && len(m[1]) == format.RequirementLevel && loose.MatchString(strings.TrimSpace(m[2])) {
+				level, title = len(m[1]), strings.TrimSpace(m[2])
+			}
+		}
+		if level == 0 || level > 3 {
+			switch {
+			case trimmed == "" || level > 3 || listMarker.MatchString(line) || strings.HasPrefix(trimmed, ">"):
+				para = -1
+			case para < 0:
+				para = start
+			}
+			continue
+		}
+		para = -1
+		reqTitle, ok := format.requirementTitle(level, title)
+		if m := loose.FindStringSubmatch(title); !ok && m != nil && level == format.RequirementLevel {
+			reqTitle, ok = strings.TrimSpace(m[1]), true
+		}
+		if !ok && open >= 0 && level > format.RequirementLevel {
+			continue
+		}
+		if open >= 0 {
+			out[open].End = headingStart
+			open = -1
+		}
+		if ok {
+			out = append(out, requirementRange{
+				ID:    utils.ToKebabCase(reqTitle),
+				Start: headingStart,
+				End:   len(raw),
+			})
+			open = len(out) - 1
+		}
+	}
+	return out
+}
+
+// setextUnderline reports whether line is all "=" or all "-" (at least
+// one), as under a setext heading.
+func setextUnderline(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || len(line)-len(strings.TrimLeft(line, " ")) > 3 {
+		return false
+	}
+	return strings.Trim(trimmed, "=") == "" || strings.Trim(trimmed, "-") == ""
+}
+
+// atxHeading returns the level and text of an ATX heading line ("## Title"),
+// or 0 when line is not one.
+func atxHeading(line string) (int, string) {
+	line = strings.TrimRight(line, "\r\n")
+	indent := len(line) - len(strings.TrimLeft(line, " "))
+	if indent > 3 {
+		return 0, ""
+	}
+	line = line[indent:]
+	level := len(line) - len(strings.TrimLeft(line, "#"))
+	if level == 0 || level > 6 {
+		return 0, ""
+	}
+	rest := line[level:]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return 0, ""
+	}
+	title := strings.TrimSpace(rest)
+	if closing := strings.TrimRight(title, "#"); closing == "" || strings.HasSuffix(closing, " ") {
+		title = strings.TrimSpace(closing)
+	}
+	return level, title
+}
+
+// shiftRequirement moves every offset of r by delta.
+func shiftRequirement(r *model.Requirement, delta int) {
+	r.Start += delta
+	r.End += delta
+	for i := range r.Notes {
+		r.Notes[i].Start += delta
+		r.Notes[i].End += delta
+	}
+	for i := range r.AcceptanceCriteria {
+		r.AcceptanceCriteria[i].Mark += delta
+	}
+	for i := range r.Scenarios {
+		r.Scenarios[i].Start += delta
+		r.Scenarios[i].End += delta
+	}
+}
@@ -0,0 +1,54 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+)
+
+func TestSpecManager_ReadRequirement_MatchesReadSpec(t *testing.T) {
+	baseDir := createTempDir(t)
+	writeSpecFile(t, baseDir, "auth", "---\naliases:\n  signin: login\n---\n# Auth\n\n"+
+		"### Requirement: Login\n\nUsers log in.\nPriority: must\n\n- [x] Email works\n\n> Rationale: most users have email.\n\n#### Scenario: Valid password\n\nGiven a user.\n\n"+
+		"```md\n### Requirement: Not a heading\n```\n\n"+
+		"## Notes\n\nPlain section.\n\n"+
+		"### Requirement: Logout\n\nUsers log out.\n")
+
+	manager := NewSpecManager(baseDir)
+	spec, err := manager.ReadSpec("auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range spec.Requirements {
+		// A fresh manager has no cached offsets and scans the file.
+		for _, m := range []SpecManager{NewSpecManager(baseDir), manager} {
+			got, err := m.ReadRequirement("auth", want.ID)
+			if err != nil {
+				t.Fatalf("ReadRequirement(%s): %v", want.ID, err)
+			}
+			if !reflect.DeepEqual(*got, spec.Requirements[i]) {
+				t.Fatalf("ReadRequirement(%s) =\n%+v\nwant\n%+v", want.ID, *got, want)
+			}
+		}
+	}
+
+	if r, err := NewSpecManager(baseDir).ReadRequirement("auth", "SignIn"); err != nil || r.ID != "login" {
+		t.Fatalf("expected the alias to resolve to login, got %+v (err %v)", r, err)
+	}
+	if _, err := manager.ReadRequirement("auth", "not-a-heading"); err == nil {
+		t.Fatal("expected a heading inside fenced code not to be a requirement")
+	} else if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+		t.Fatalf("expected ErrNotFound, got %T: %v", err, err)
+	}
+
+	// An edited file is rescanned rather than read at stale offsets.
+	writeSpecFile(t, baseDir, "auth", "# Auth\n\n### Requirement: Logout\n\nUsers log out everywhere.\n")
+	r, err := manager.ReadRequirement("auth", "logout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Start != len("# Auth\n\n") || r.Content != "\n\nUsers log out everywhere.\n" {
+		t.Fatalf("stale requirement %+v", r)
+	}
+}
@@ -0,0 +1,276 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// Requirement lint rules. The structural rules always run; the style rules
+// run with LintOptions.Style.
+const (
+	LintRuleStatement   = "statement"    // the requirement states something
+	LintRuleDuplicateID = "duplicate-id" // no two requirements share an ID
+	LintRuleModal       = "modal-verb"   // the statement uses "shall" or "must"
+	LintRulePassive     = "passive-voice"
+	LintRuleLength      = "sentence-length"
+	LintRuleTodo        = "todo" // no TODO, FIXME, TBD or XXX markers
+	LintRuleGlossary    = "glossary"
+)
+
+// StructuralLintRules and StyleLintRules list the rules in the order they
+// are reported.
+var (
+	StructuralLintRules = []string{LintRuleStatement, LintRuleDuplicateID}
+	StyleLintRules      = []string{LintRuleModal, LintRulePassive, LintRuleLength, LintRuleTodo, LintRuleGlossary}
+)
+
+// DefaultMaxSentenceWords is the sentence-length limit when the config sets
+// none.
+const DefaultMaxSentenceWords = 30
+
+// LintConfig is the "lint" section of config.yaml. Every rule is on unless
+// turned off under rules; the glossary rule only runs when glossary lists
+// the defined terms.
+//
+//	lint:
+//	  rules:
+//	    passive-voice: false
+//	  max_sentence_words: 25
+//	  glossary: [session, access token]
+type LintConfig struct {
+	Rules            map[string]bool `yaml:"rules,omitempty"`
+	MaxSentenceWords int             `yaml:"max_sentence_words,omitempty"`
+	Glossary         []string        `yaml:"glossary,omitempty"`
+}
+
+// Validate checks the rule names and limits.
+func (c LintConfig) Validate() error {
+	var errs custom_errors.ValidationErrors
+	known := append(append([]string{}, StructuralLintRules...), StyleLintRules...)
+	names := make([]string, 0, len(c.Rules))
+	for name := range c.Rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !containsString(known, name) {
+			errs.Add("lint.rules."+name, fmt.Sprintf("unknown rule (want one of %s)", strings.Join(known, ", ")))
+		}
+	}
+	if c.MaxSentenceWords < 0 {
+		errs.Add("lint.max_sentence_words", "cannot be negative")
+	}
+	return errs.Err()
+}
+
+// Enabled reports whether rule is on.
+func (c LintConfig) Enabled(rule string) bool {
+	on, set := c.Rules[rule]
+	if rule == LintRuleGlossary && len(c.Glossary) == 0 {
+		return false
+	}
+	return !set || on
+}
+
+// LintOptions selects what LintRequirements checks.
+type LintOptions struct {
+	Config LintConfig
+	// Style adds the writing style rules to the structural ones.
+	Style bool
+	// RequirementID restricts the lint to one requirement.
+	RequirementID string
+}
+
+// LintFinding is one rule violation in a requirement.
+type LintFinding struct {
+	Domain        string `json:"domain"`
+	RequirementID string `json:"requirement_id"`
+	Rule          string `json:"rule"`
+	Message       string `json:"message"`
+	// Suggestion is a proposed fix: replacement text where one can be
+	// derived, advice otherwise.
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+var (
+	modalVerb   = regexp.MustCompile(`(?i)\b(shall|must)\b`)
+	weakModal   = regexp.MustCompile(`(?i)\b(should|will|may|can|could|might)\b`)
+	todoMarker  = regexp.MustCompile(`\b(TODO|FIXME|TBD|XXX)\b`)
+	passive     = regexp.MustCompile(`(?i)\b(is|are|was|were|be|been|being)\s+(?:\w+ly\s+)?(\w+ed|written|given|taken|shown|known|chosen|hidden|broken|seen|sent|built|made|done|kept|held|set|put|paid|sold|told|found)\b`)
+	boldTerm    = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	sentenceEnd = regexp.MustCompile(`[.!?]+(\s+|$)`)
+	listMarker  = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+`)
+)
+
+// LintRequirements checks the requirements of spec against the enabled
+// rules. Requirements included from a shared library are linted where they
+// are defined, not here.
+func LintRequirements(spec *model.Spec, opts LintOptions) ([]LintFinding, error) {
+	cfg := opts.Config
+	maxWords := cfg.MaxSentenceWords
+	if maxWords == 0 {
+		maxWords = DefaultMaxSentenceWords
+	}
+	glossary := map[string]string{}
+	for _, term := range cfg.Glossary {
+		glossary[glossaryKey(term)] = strings.TrimSpace(term)
+	}
+
+	reqID := ""
+	if opts.RequirementID != "" {
+		reqID, _ = ResolveRequirementAlias(spec, opts.RequirementID)
+		found := false
+		for _, r := range spec.Requirements {
+			found = found || r.ID == reqID
+		}
+		if !found {
+			return nil, custom_errors.NewErrNotFound("requirement", opts.RequirementID)
+		}
+	}
+
+	var out []LintFinding
+	seen := map[string]bool{}
+	for _, r := range spec.Requirements {
+		if r.Source != "" {
+			continue
+		}
+		duplicate := seen[strings.ToLower(r.ID)]
+		seen[strings.ToLower(r.ID)] = true
+		if reqID != "" && r.ID != reqID {
+			continue
+		}
+		add := func(rule, message, suggestion string) {
+			out = append(out, LintFinding{Domain: spec.Domain, RequirementID: r.ID, Rule: rule, Message: message, Suggestion: suggestion})
+		}
+
+		statement := requirementStatement(r)
+		if cfg.Enabled(LintRuleStatement) && statement == "" {
+			add(LintRuleStatement, "the requirement has no statement", "Add a sentence under the heading saying what the system must do")
+		}
+		if cfg.Enabled(LintRuleDuplicateID) && duplicate {
+			add(LintRuleDuplicateID, fmt.Sprintf("another requirement already has the ID %s", r.ID), "Retitle one of the requirements")
+		}
+		if !opts.Style || statement == "" {
+			continue
+		}
+
+		sentences := splitSentences(statement)
+		if cfg.Enabled(LintRuleModal) && !modalVerb.MatchString(statement) {
+			suggestion := `State the obligation with "shall" or "must"`
+			for _, s := range sentences {
+				if loc := weakModal.FindStringIndex(s); loc != nil {
+					suggestion = fmt.Sprintf("%q", s[:loc[0]]+"must"+s[loc[1]:])
+					break
+				}
+			}
+			add(LintRuleModal, `the statement does not use "shall" or "must"`, suggestion)
+		}
+		for _, s := range sentences {
+			if cfg.Enabled(LintRulePassive) {
+				if m := passive.FindString(s); m != "" {
+					add(LintRulePassive, fmt.Sprintf("possible passive voice: %q", m), "Name the actor, e.g. \"The system encrypts ...\"")
+				}
+			}
+			if n := len(strings.Fields(s)); cfg.Enabled(LintRuleLength) && n > maxWords {
+				add(LintRuleLength, fmt.Sprintf("sentence has %d words (max %d): %q", n, maxWords, abbreviate(s, 60)), "Split it into shorter sentences")
+			}
+		}
+		if cfg.Enabled(LintRuleTodo) {
+			for _, m := range uniqueStrings(todoMarker.FindAllString(r.Content, -1)) {
+				add(LintRuleTodo, fmt.Sprintf("unresolved %s marker", m), fmt.Sprintf("Resolve the %s or track it as a task", m))
+			}
+		}
+		if cfg.Enabled(LintRuleGlossary) {
+			for _, m := range boldTerm.FindAllStringSubmatch(statement, -1) {
+				term := strings.TrimSpace(m[1] + m[2])
+				if _, ok := glossary[glossaryKey(term)]; ok {
+					continue
+				}
+				suggestion := fmt.Sprintf("Use a defined term or add %q to lint.glossary", term)
+				if near := nearestGlossaryTerm(term, glossary); near != "" {
+					suggestion = fmt.Sprintf("%q", near)
+				}
+				add(LintRuleGlossary, fmt.Sprintf("%q is not a glossary term", term), suggestion)
+			}
+		}
+	}
+	return out, nil
+}
+
+// requirementStatement returns the prose of a requirement: the text between
+// its heading and first scenario, without notes, fenced code, checklists and
+// "Code:" or "Priority:" lines, joined into one line.
+func requirementStatement(r model.Requirement) string {
+	var parts []string
+	for i, line := range metadataLines(r.Content, r.Start, r.Notes) {
+		trimmed := strings.TrimSpace(line)
+		if i == 0 && strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "#") {
+			break
+		}
+		if trimmed == "" || checklistItem.MatchString(line) || codeAnnotation.MatchString(line) || priorityAnnotation.MatchString(line) {
+			continue
+		}
+		parts = append(parts, strings.TrimSpace(listMarker.ReplaceAllString(line, "")))
+	}
+	return strings.Join(parts, " ")
+}
+
+// splitSentences splits text after ".", "!" and "?".
+func splitSentences(text string) []string {
+	var out []string
+	for _, s := range sentenceEnd.Split(text, -1) {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// glossaryKey folds case and a plural "s" so "Sessions" matches "session".
+func glossaryKey(term string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.Join(strings.Fields(term), " ")), "s")
+}
+
+// nearestGlossaryTerm returns the glossary term that term contains or is
+// contained in, e.g. "session" for "user session", or "".
+func nearestGlossaryTerm(term string, glossary map[string]string) string {
+	key := glossaryKey(term)
+	keys := make([]string, 0, len(glossary))
+	for k := range glossary {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if strings.Contains(key, k) || strings.Contains(k, key) {
+			return glossary[k]
+		}
+	}
+	return ""
+}
+
+// abbreviate shortens s to n runes, marking the cut with an ellipsis.
+func abbreviate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return strings.TrimSpace(string(r[:n])) + "…"
+}
+
+func uniqueStrings(in []string) []string {
+	var out []string
+	for _, s := range in {
+		if !containsString(out, s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
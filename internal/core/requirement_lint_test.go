@@ -0,0 +1,92 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+const lintSpec = `# Auth
+
+## Requirements
+
+### Requirement: Login
+
+Passwords are hashed before storage. TODO pick algorithm.
+
+#### Scenario: Hashing
+- WHEN a user signs up
+- THEN the password is stored hashed
+
+### Requirement: Logout
+
+The system will end the **user sessions** when asked.
+
+- [ ] Session cookie cleared
+Code: internal/auth/**
+
+### Requirement: Empty
+
+#### Scenario: Nothing
+- WHEN nothing happens
+`
+
+func TestLintRequirements(t *testing.T) {
+	spec, err := NewSpecParser().Parse([]byte(lintSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec.Domain = "auth"
+	cfg := LintConfig{Glossary: []string{"session"}}
+
+	rules := func(findings []LintFinding) map[string][]string {
+		out := map[string][]string{}
+		for _, f := range findings {
+			out[f.RequirementID] = append(out[f.RequirementID], f.Rule)
+		}
+		return out
+	}
+
+	structural, err := LintRequirements(spec, LintOptions{Config: cfg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rules(structural); len(got) != 1 || len(got["empty"]) != 1 || got["empty"][0] != LintRuleStatement {
+		t.Fatalf("structural findings = %v", got)
+	}
+
+	findings, err := LintRequirements(spec, LintOptions{Config: cfg, Style: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := rules(findings)
+	if want := []string{LintRuleModal, LintRulePassive, LintRuleTodo}; !reflect.DeepEqual(got["login"], want) {
+		t.Errorf("login rules = %v, want %v", got["login"], want)
+	}
+	if want := []string{LintRuleModal, LintRuleGlossary}; !reflect.DeepEqual(got["logout"], want) {
+		t.Errorf("logout rules = %v, want %v", got["logout"], want)
+	}
+	for _, f := range findings {
+		if f.RequirementID == "logout" && f.Rule == LintRuleModal && f.Suggestion != `"The system must end the **user sessions** when asked"` {
+			t.Errorf("unexpected modal suggestion %s", f.Suggestion)
+		}
+		if f.Rule == LintRuleGlossary && f.Suggestion != `"session"` {
+			t.Errorf("unexpected glossary suggestion %s", f.Suggestion)
+		}
+	}
+
+	// Rules can be turned off, and the lint narrowed to one requirement.
+	cfg.Rules = map[string]bool{LintRulePassive: false, LintRuleTodo: false}
+	findings, err = LintRequirements(spec, LintOptions{Config: cfg, Style: true, RequirementID: "login"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rules(findings); len(got) != 1 || !reflect.DeepEqual(got["login"], []string{LintRuleModal}) {
+		t.Fatalf("findings with rules off = %v", got)
+	}
+	if _, err := LintRequirements(spec, LintOptions{RequirementID: "nope"}); err == nil {
+		t.Fatal("expected an unknown requirement to be an error")
+	}
+	if err := (LintConfig{Rules: map[string]bool{"shouting": true}}).Validate(); err == nil {
+		t.Fatal("expected an unknown rule to be invalid")
+	}
+}
@@ -0,0 +1,186 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// Requirement priorities, from a "Priority: <value>" line in the block.
+const (
+	PriorityMust   = "must"
+	PriorityShould = "should"
+	PriorityCould  = "could"
+)
+
+// Priorities lists the known priorities, highest first.
+var Priorities = []string{PriorityMust, PriorityShould, PriorityCould}
+
+// priorityAnnotation matches a "Priority: <value>" line, optionally as a
+// list item.
+var priorityAnnotation = regexp.MustCompile(`(?i)^\s*(?:[-*+]\s+)?Priority:\s*(\S+)\s*$`)
+
+// findPriority returns the lowercased value of the block's first
+// "Priority:" line, or "".
+func findPriority(block string, offset int, notes []model.RequirementNote) string {
+	for _, line := range metadataLines(block, offset, notes) {
+		if m := priorityAnnotation.FindStringSubmatch(line); m != nil {
+			return strings.ToLower(strings.Trim(m[1], "`*_"))
+		}
+	}
+	return ""
+}
+
+// ValidatePriority checks a priority given on the command line; "none"
+// selects requirements without one.
+func ValidatePriority(p string) error {
+	if p == "none" || containsString(Priorities, p) {
+		return nil
+	}
+	return custom_errors.NewErrValidation("priority", fmt.Sprintf("unknown priority %q (want %s or none)", p, strings.Join(Priorities, ", ")))
+}
+
+// MatchesPriority reports whether r has priority p, where "none" matches
+// requirements without a priority.
+func MatchesPriority(r model.Requirement, p string) bool {
+	if p == "none" {
+		return r.Priority == ""
+	}
+	return r.Priority == p
+}
+
+// PriorityCounts counts requirements of one priority by how far their
+// linked tasks have got: planned requirements are linked from at least one
+// task, and done ones only from completed or waived tasks.
+type PriorityCounts struct {
+	Priority string `json:"priority"` // "" for requirements without one
+	Total    int    `json:"total"`
+	Planned  int    `json:"planned"`
+	Done     int    `json:"done"`
+}
+
+// DomainPriorities is the priority roll-up of one spec domain.
+type DomainPriorities struct {
+	Domain     string           `json:"domain"`
+	Priorities []PriorityCounts `json:"priorities"`
+}
+
+// PriorityReport rolls requirement priorities up per domain and overall.
+type PriorityReport struct {
+	Domains []DomainPriorities `json:"domains"`
+	Total   []PriorityCounts   `json:"total"`
+	// Blocked are the blocked tasks of every goal, which hold up the
+	// requirements they link to.
+	Blocked []BlockedTask `json:"blocked"`
+}
+
+// BlockedTask is a blocked task of a goal, with its open block when it was
+// blocked with 'plan block'.
+type BlockedTask struct {
+	GoalID string           `json:"goal_id"`
+	Task   model.Task       `json:"task"`
+	Block  *model.TaskBlock `json:"block,omitempty"`
+}
+
+// priorityRank orders must, should, could, then unknown values, then none.
+func priorityRank(p string) int {
+	for i, known := range Priorities {
+		if p == known {
+			return i
+		}
+	}
+	if p == "" {
+		return len(Priorities) + 1
+	}
+	return len(Priorities)
+}
+
+// addPriority counts one requirement into rows, keeping rows in rank order.
+func addPriority(rows []PriorityCounts, p string, planned, done bool) []PriorityCounts {
+	i := sort.Search(len(rows), func(i int) bool {
+		ri, rp := priorityRank(rows[i].Priority), priorityRank(p)
+		return ri > rp || ri == rp && rows[i].Priority >= p
+	})
+	if i == len(rows) || rows[i].Priority != p {
+		rows = append(rows, PriorityCounts{})
+		copy(rows[i+1:], rows[i:])
+		rows[i] = PriorityCounts{Priority: p}
+	}
+	rows[i].Total++
+	if planned {
+		rows[i].Planned++
+	}
+	if done {
+		rows[i].Done++
+	}
+	return rows
+}
+
+// BuildPriorityReport counts every spec's requirements by priority, and by
+// the status of the plan tasks linked to them. priority, when set, limits
+// the report to that priority ("none" for requirements without one).
+func (a *App) BuildPriorityReport(priority string) (*PriorityReport, error) {
+	if priority != "" {
+		if err := ValidatePriority(priority); err != nil {
+			return nil, err
+		}
+	}
+	specs, err := a.SpecManager.ListSpecs()
+	if err != nil {
+		return nil, err
+	}
+	refs := newRequirementRefs(specs)
+
+	// open and closed count the tasks linking each "domain/id" requirement.
+	open, closed := map[string]int{}, map[string]int{}
+	blocked := []BlockedTask{}
+	goalIDs, err := ListGoalIDs(a.Options)
+	if err != nil {
+		return nil, err
+	}
+	for _, goalID := range goalIDs {
+		plan, err := a.PlanManager.Load(goalID)
+		if err != nil {
+			if _, ok := err.(*custom_errors.ErrNotFound); ok {
+				continue
+			}
+			return nil, err
+		}
+		for _, t := range plan.Tasks {
+			if t.Status == TaskStatusBlocked {
+				blocked = append(blocked, BlockedTask{GoalID: goalID, Task: t, Block: OpenBlock(t)})
+			}
+			for _, link := range t.Requirements {
+				key := refs.key(link)
+				if t.Status == TaskStatusCompleted || t.Status == TaskStatusWaived {
+					closed[key]++
+				} else {
+					open[key]++
+				}
+			}
+		}
+	}
+
+	report := &PriorityReport{Domains: []DomainPriorities{}, Total: []PriorityCounts{}, Blocked: blocked}
+	for _, spec := range specs {
+		d := DomainPriorities{Domain: spec.Domain, Priorities: []PriorityCounts{}}
+		for _, r := range spec.Requirements {
+			if priority != "" && !MatchesPriority(r, priority) {
+				continue
+			}
+			key := strings.ToLower(spec.Domain + "/" + r.ID)
+			planned := open[key]+closed[key] > 0
+			done := planned && open[key] == 0
+			d.Priorities = addPriority(d.Priorities, r.Priority, planned, done)
+			report.Total = addPriority(report.Total, r.Priority, planned, done)
+		}
+		if len(d.Priorities) > 0 {
+			report.Domains = append(report.Domains, d)
+		}
+	}
+	return report, nil
+}
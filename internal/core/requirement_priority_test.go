@@ -0,0 +1,59 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestParse_RequirementPriority(t *testing.T) {
+	spec, err := NewSpecParser().Parse([]byte("# Pay\n\n### Requirement: Charge\n\nPriority: MUST\n\n### Requirement: Refund\n\n- Priority: should\n\n### Requirement: Wallet\n\n```\nPriority: must\n```\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range spec.Requirements {
+		got = append(got, r.Priority)
+	}
+	if !reflect.DeepEqual(got, []string{"must", "should", ""}) {
+		t.Fatalf("priorities = %q", got)
+	}
+}
+
+func TestBuildPriorityReport(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "pay", Content: "# Pay\n\n### Requirement: Charge\n\nPriority: must\n\n### Requirement: Refund\n\nPriority: must\n\n### Requirement: Wallet\n\nLater.\n"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.PlanManager.Save(&model.Plan{GoalID: "001-pay", Tasks: []model.Task{
+		{ID: "T01", Title: "Charge", Status: "completed", Requirements: []string{"pay/charge"}},
+		{ID: "T02", Title: "Refund", Status: "pending", Requirements: []string{"pay/refund"}},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := app.BuildPriorityReport("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []PriorityCounts{{Priority: "must", Total: 2, Planned: 2, Done: 1}, {Priority: "", Total: 1}}
+	if len(report.Domains) != 1 || !reflect.DeepEqual(report.Domains[0].Priorities, want) || !reflect.DeepEqual(report.Total, want) {
+		t.Fatalf("report = %+v", report)
+	}
+
+	report, err = app.BuildPriorityReport("none")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(report.Total, []PriorityCounts{{Priority: "", Total: 1}}) {
+		t.Fatalf("none report = %+v", report.Total)
+	}
+	if _, err := app.BuildPriorityReport("urgent"); err == nil {
+		t.Fatal("expected an unknown priority to fail")
+	}
+}
@@ -0,0 +1,129 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// Retrospective is what a team reflects on when completing a goal. It is
+// stored as a "reflection" discussion entry with one section per field.
+type Retrospective struct {
+	Worked    []string `json:"worked,omitempty"`
+	DidntWork []string `json:"didnt_work,omitempty"`
+	FollowUps []string `json:"follow_ups,omitempty"`
+}
+
+// Retrospective section headings, in the order they are written.
+const (
+	retroWorked    = "What worked"
+	retroDidntWork = "What didn't work"
+	retroFollowUps = "Follow-ups"
+)
+
+// Empty reports whether nothing was recorded.
+func (r Retrospective) Empty() bool {
+	return len(cleanItems(r.Worked)) == 0 && len(cleanItems(r.DidntWork)) == 0 && len(cleanItems(r.FollowUps)) == 0
+}
+
+// FormatRetrospective renders r as the content of a reflection entry.
+// Sections without items are left out.
+func FormatRetrospective(goalID string, r Retrospective) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Retrospective for goal %s\n", goalID)
+	for _, s := range []struct {
+		title string
+		items []string
+	}{{retroWorked, r.Worked}, {retroDidntWork, r.DidntWork}, {retroFollowUps, r.FollowUps}} {
+		items := cleanItems(s.items)
+		if len(items) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", s.title)
+		for _, item := range items {
+			fmt.Fprintf(&b, "- %s\n", item)
+		}
+	}
+	return b.String()
+}
+
+// ParseRetrospective reads back the sections of a reflection entry written
+// by FormatRetrospective. It reports false when content has none of them.
+func ParseRetrospective(content string) (Retrospective, bool) {
+	var r Retrospective
+	var section *[]string
+	found := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "## ") {
+			section = nil
+			switch strings.TrimSpace(strings.TrimPrefix(trimmed, "## ")) {
+			case retroWorked:
+				section = &r.Worked
+			case retroDidntWork:
+				section = &r.DidntWork
+			case retroFollowUps:
+				section = &r.FollowUps
+			}
+			found = found || section != nil
+			continue
+		}
+		if section != nil && strings.HasPrefix(trimmed, "- ") {
+			*section = append(*section, strings.TrimSpace(trimmed[2:]))
+		}
+	}
+	return r, found
+}
+
+// RecordRetrospective adds r to goalID's discussion as a reflection entry.
+func (a *App) RecordRetrospective(goalID, author string, r Retrospective) (*model.DiscussionEntry, error) {
+	if r.Empty() {
+		return nil, custom_errors.NewErrValidation("retrospective", "cannot be empty")
+	}
+	entry := &model.DiscussionEntry{
+		Type:    DiscussionTypeReflection,
+		Author:  strings.TrimSpace(author),
+		Content: FormatRetrospective(goalID, r),
+	}
+	if err := a.DiscussionManager.AddEntry(goalID, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// SpawnFollowUpGoals creates one goal from tmpl per follow-up of r, numbered
+// after the existing goals, and notes in each where it came from.
+func (a *App) SpawnFollowUpGoals(goalID string, r Retrospective, tmpl GoalTemplate) ([]*model.Plan, error) {
+	existing, err := ListGoalIDs(a.Options)
+	if err != nil {
+		return nil, err
+	}
+	var out []*model.Plan
+	for _, title := range cleanItems(r.FollowUps) {
+		id := NextGoalID(existing, title)
+		plan, err := CreateGoal(a.PlanManager, a.DiscussionManager, id, title, tmpl)
+		if err != nil {
+			return out, err
+		}
+		note := &model.DiscussionEntry{Type: DiscussionTypeDiscussion, Content: fmt.Sprintf("Follow-up from the retrospective of goal %s.", goalID)}
+		if err := a.DiscussionManager.AddEntry(id, note); err != nil {
+			return out, err
+		}
+		existing = append(existing, id)
+		out = append(out, plan)
+	}
+	return out, nil
+}
+
+// cleanItems trims items and drops the empty ones.
+func cleanItems(items []string) []string {
+	var out []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
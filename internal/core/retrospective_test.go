@@ -0,0 +1,63 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestRetrospective_RecordAndSpawnFollowUps(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := ResolveGoalTemplate(GoalTemplateSpike, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CreateGoal(app.PlanManager, app.DiscussionManager, "001-user-auth", "User auth", tmpl); err != nil {
+		t.Fatal(err)
+	}
+
+	retro := Retrospective{
+		Worked:    []string{"Pairing on the spec delta"},
+		DidntWork: []string{" ", "Late reviews"},
+		FollowUps: []string{"Rate limiting", "Audit log"},
+	}
+	entry, err := app.RecordRetrospective("001-user-auth", "alice", retro)
+	if err != nil {
+		t.Fatalf("RecordRetrospective: %v", err)
+	}
+	if entry.Type != DiscussionTypeReflection || entry.Author != "alice" {
+		t.Fatalf("unexpected entry %+v", entry)
+	}
+	parsed, ok := ParseRetrospective(entry.Content)
+	want := Retrospective{Worked: retro.Worked, DidntWork: []string{"Late reviews"}, FollowUps: retro.FollowUps}
+	if !ok || !reflect.DeepEqual(parsed, want) {
+		t.Fatalf("parsed %+v (ok %v), want %+v", parsed, ok, want)
+	}
+	if _, err := app.RecordRetrospective("001-user-auth", "", Retrospective{Worked: []string{""}}); err == nil {
+		t.Fatal("expected an empty retrospective to be rejected")
+	}
+
+	plans, err := app.SpawnFollowUpGoals("001-user-auth", retro, tmpl)
+	if err != nil {
+		t.Fatalf("SpawnFollowUpGoals: %v", err)
+	}
+	var ids []string
+	for _, p := range plans {
+		ids = append(ids, p.GoalID)
+	}
+	if !reflect.DeepEqual(ids, []string{"002-rate-limiting", "003-audit-log"}) {
+		t.Fatalf("spawned %v", ids)
+	}
+	entries, err := app.DiscussionManager.Load("003-audit-log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || !strings.Contains(entries[1].Content, "001-user-auth") {
+		t.Fatalf("expected the follow-up to note its origin, got %+v", entries)
+	}
+}
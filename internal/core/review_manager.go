@@ -0,0 +1,174 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+	"gopkg.in/yaml.v3"
+)
+
+// reviewManager is a file-backed ReviewManager. Comments for a domain live in a
+// YAML sidecar next to the spec (<baseDir>/<domain>/review.yaml by default):
+//
+//	comments:
+//	  - id: R01
+//	    requirement_id: password-reset
+//	    author: alice
+//	    body: needs NFRs
+//	    status: open
+//	    created_at: 2025-01-15T10:00:00Z
+type reviewManager struct {
+	baseDir string
+	layout  Layout
+	store   storage.Storage
+}
+
+// reviewFile is the on-disk shape of a review sidecar.
+type reviewFile struct {
+	Comments []model.ReviewComment `yaml:"comments"`
+}
+
+var reviewIDPattern = regexp.MustCompile(`^R(\d+)$`)
+
+// NewReviewManager creates a ReviewManager storing sidecars under the specs directory.
+func NewReviewManager(baseDir string, layout Layout) ReviewManager {
+	return newReviewManager(baseDir, layout, storage.OS())
+}
+
+func newReviewManager(baseDir string, layout Layout, store storage.Storage) ReviewManager {
+	return &reviewManager{baseDir: baseDir, layout: layout.WithDefaults(), store: store}
+}
+
+// Load returns every review comment for domain in creation order.
+// A missing sidecar yields an empty list.
+func (m *reviewManager) Load(domain string) ([]model.ReviewComment, error) {
+	if strings.TrimSpace(domain) == "" {
+		return nil, custom_errors.NewErrValidation("domain", "cannot be empty")
+	}
+	path := m.layout.ReviewPath(m.baseDir, domain)
+	data, err := m.store.ReadFile(path)
+	if err != nil {
+		if _, ok := err.(*custom_errors.ErrNotFound); ok {
+			return []model.ReviewComment{}, nil
+		}
+		return nil, err
+	}
+	var f reviewFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse review file '%s': %w", path, err)
+	}
+	return f.Comments, nil
+}
+
+// AddComment appends an open comment, assigning the next "RNN" ID and a timestamp.
+func (m *reviewManager) AddComment(domain string, comment *model.ReviewComment) error {
+	if comment == nil {
+		return custom_errors.NewErrConflict("comment cannot be nil")
+	}
+	if strings.TrimSpace(comment.RequirementID) == "" {
+		return custom_errors.NewErrValidation("requirement_id", "cannot be empty")
+	}
+	if strings.TrimSpace(comment.Body) == "" {
+		return custom_errors.NewErrValidation("body", "cannot be empty")
+	}
+	comments, err := m.Load(domain)
+	if err != nil {
+		return err
+	}
+
+	next := 1
+	for _, c := range comments {
+		if match := reviewIDPattern.FindStringSubmatch(c.ID); match != nil {
+			if n, _ := strconv.Atoi(match[1]); n >= next {
+				next = n + 1
+			}
+		}
+	}
+	comment.ID = fmt.Sprintf("R%02d", next)
+	comment.Status = model.ReviewOpen
+	if comment.CreatedAt.IsZero() {
+		comment.CreatedAt = time.Now()
+	}
+	return m.save(domain, append(comments, *comment))
+}
+
+// SetStatus moves a comment to status (open, resolved or closed).
+func (m *reviewManager) SetStatus(domain, commentID, status string) (*model.ReviewComment, error) {
+	switch status {
+	case model.ReviewOpen, model.ReviewResolved, model.ReviewClosed:
+	default:
+		return nil, custom_errors.NewErrValidation("status", fmt.Sprintf("invalid review status %q", status))
+	}
+	comments, err := m.Load(domain)
+	if err != nil {
+		return nil, err
+	}
+	for i := range comments {
+		if comments[i].ID != commentID {
+			continue
+		}
+		now := time.Now()
+		comments[i].Status = status
+		comments[i].UpdatedAt = &now
+		if err := m.save(domain, comments); err != nil {
+			return nil, err
+		}
+		return &comments[i], nil
+	}
+	return nil, custom_errors.NewErrNotFound("review comment", commentID)
+}
+
+func (m *reviewManager) save(domain string, comments []model.ReviewComment) error {
+	data, err := yaml.Marshal(reviewFile{Comments: comments})
+	if err != nil {
+		return fmt.Errorf("failed to encode review comments: %w", err)
+	}
+	return m.store.WriteFile(m.layout.ReviewPath(m.baseDir, domain), data, 0o644)
+}
+
+// OpenReviewComments filters comments down to the open ones, optionally limited
+// to the given requirement IDs (all requirements when ids is empty).
+func OpenReviewComments(comments []model.ReviewComment, ids ...string) []model.ReviewComment {
+	var out []model.ReviewComment
+	for _, c := range comments {
+		if c.Status != model.ReviewOpen {
+			continue
+		}
+		if len(ids) > 0 && !containsString(ids, c.RequirementID) {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// ChangeReviewComments returns the open review comments on requirements touched
+// by change, keyed by domain. Domains without open comments are omitted.
+func ChangeReviewComments(reviews ReviewManager, change *model.Change) (map[string][]model.ReviewComment, error) {
+	if change == nil {
+		return nil, custom_errors.NewErrConflict("change cannot be nil")
+	}
+	touched := map[string][]string{}
+	for _, d := range change.SpecDeltas {
+		for _, op := range d.Operations {
+			touched[d.Domain] = append(touched[d.Domain], op.Requirement.ID)
+		}
+	}
+	out := map[string][]model.ReviewComment{}
+	for domain, ids := range touched {
+		comments, err := reviews.Load(domain)
+		if err != nil {
+			return nil, err
+		}
+		if open := OpenReviewComments(comments, ids...); len(open) > 0 {
+			out[domain] = open
+		}
+	}
+	return out, nil
+}
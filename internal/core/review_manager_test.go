@@ -0,0 +1,71 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	ce "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+func TestReviewManager_AddAndResolve(t *testing.T) {
+	baseDir := createTempDir(t)
+	rm := NewReviewManager(baseDir, DefaultLayout())
+
+	first := &model.ReviewComment{RequirementID: "login", Author: "alice", Body: "needs NFRs"}
+	if err := rm.AddComment("auth", first); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	second := &model.ReviewComment{RequirementID: "logout", Body: "clarify timeout"}
+	if err := rm.AddComment("auth", second); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	if first.ID != "R01" || second.ID != "R02" || first.Status != model.ReviewOpen {
+		t.Fatalf("unexpected ids/status: %+v %+v", first, second)
+	}
+
+	if _, err := rm.SetStatus("auth", "R01", model.ReviewResolved); err != nil {
+		t.Fatalf("SetStatus failed: %v", err)
+	}
+	comments, err := rm.Load("auth")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(comments) != 2 || comments[0].Status != model.ReviewResolved || comments[0].UpdatedAt == nil {
+		t.Fatalf("unexpected comments after resolve: %+v", comments)
+	}
+	if open := OpenReviewComments(comments); len(open) != 1 || open[0].ID != "R02" {
+		t.Fatalf("expected only R02 open, got %+v", open)
+	}
+
+	_, err = rm.SetStatus("auth", "R99", model.ReviewClosed)
+	var nf *ce.ErrNotFound
+	if !errors.As(err, &nf) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestChangeReviewComments_TouchedRequirementsOnly(t *testing.T) {
+	baseDir := createTempDir(t)
+	rm := NewReviewManager(baseDir, DefaultLayout())
+	for _, c := range []*model.ReviewComment{
+		{RequirementID: "login", Body: "touched"},
+		{RequirementID: "profile", Body: "untouched"},
+	} {
+		if err := rm.AddComment("auth", c); err != nil {
+			t.Fatalf("AddComment failed: %v", err)
+		}
+	}
+
+	ch := &model.Change{ID: "CH-1", SpecDeltas: []model.SpecDelta{{
+		Domain:     "auth",
+		Operations: []model.DeltaOperation{{Type: "MODIFIED", Requirement: model.Requirement{ID: "login"}}},
+	}}}
+	got, err := ChangeReviewComments(rm, ch)
+	if err != nil {
+		t.Fatalf("ChangeReviewComments failed: %v", err)
+	}
+	if len(got["auth"]) != 1 || got["auth"][0].Body != "touched" {
+		t.Fatalf("unexpected comments: %+v", got)
+	}
+}
@@ -0,0 +1,182 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/utils"
+)
+
+// Test skeleton formats.
+const (
+	SkeletonGo      = "go"
+	SkeletonGherkin = "gherkin"
+)
+
+// SkeletonFormats lists the accepted test skeleton formats.
+var SkeletonFormats = []string{SkeletonGo, SkeletonGherkin}
+
+// ScenarioSkeleton is a test file generated from one requirement's scenarios.
+// Path is relative to the output directory.
+type ScenarioSkeleton struct {
+	Path          string
+	RequirementID string
+	Scenarios     int
+	Content       []byte
+}
+
+// SkeletonOptions configures RenderScenarioSkeletons. Package is the Go
+// package clause; it defaults to "<domain>_test".
+type SkeletonOptions struct {
+	Format  string
+	Package string
+}
+
+// RenderScenarioSkeletons renders a test skeleton for each requirement of the
+// spec that has scenarios, or only for reqID when it is set: a Go file with
+// one skipped test function per scenario, or a gherkin feature with one
+// scenario per scenario. Every test and scenario carries a comment naming the
+// "<domain>/<requirement>#<scenario>" it was generated from, with the
+// scenario's steps. A reqID without scenarios is a validation error.
+func RenderScenarioSkeletons(spec *model.Spec, reqID string, opts SkeletonOptions) ([]ScenarioSkeleton, error) {
+	format := strings.ToLower(strings.TrimSpace(opts.Format))
+	if format == "" {
+		format = SkeletonGo
+	}
+	if !containsString(SkeletonFormats, format) {
+		return nil, custom_errors.NewErrValidation("format", fmt.Sprintf("unknown format %q (want %s)", opts.Format, strings.Join(SkeletonFormats, " or ")))
+	}
+	pkg := strings.TrimSpace(opts.Package)
+	if pkg == "" {
+		pkg = strings.ReplaceAll(utils.ToKebabCase(spec.Domain), "-", "_") + "_test"
+	}
+
+	var out []ScenarioSkeleton
+	found := false
+	for _, r := range spec.Requirements {
+		if reqID != "" && r.ID != reqID {
+			continue
+		}
+		found = true
+		if len(r.Scenarios) == 0 {
+			if reqID != "" {
+				return nil, custom_errors.NewErrValidation("requirement", fmt.Sprintf("%s/%s has no scenarios", spec.Domain, r.ID))
+			}
+			continue
+		}
+		base := strings.ReplaceAll(utils.ToKebabCase(spec.Domain)+"_"+r.ID, "-", "_")
+		sk := ScenarioSkeleton{RequirementID: r.ID, Scenarios: len(r.Scenarios)}
+		if format == SkeletonGherkin {
+			sk.Path = base + ".feature"
+			sk.Content = renderGherkinSkeleton(spec.Domain, r)
+		} else {
+			sk.Path = base + "_test.go"
+			sk.Content = renderGoSkeleton(spec.Domain, pkg, r)
+		}
+		out = append(out, sk)
+	}
+	if reqID != "" && !found {
+		return nil, custom_errors.NewErrNotFound("requirement", reqID)
+	}
+	return out, nil
+}
+
+func renderGoSkeleton(domain, pkg string, r model.Requirement) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Test skeletons for requirement %s/%s (%s), generated by\n", domain, r.ID, r.Title)
+	b.WriteString("// 'teamwerx spec req extract-scenarios'. Fill in each test and remove its\n")
+	b.WriteString("// t.Skip; the Scenario comments trace each test back to the spec.\n\n")
+	fmt.Fprintf(&b, "package %s\n\nimport \"testing\"\n", pkg)
+	seen := map[string]int{}
+	for _, sc := range r.Scenarios {
+		name := "Test" + goIdentifier(r.Title) + "_" + goIdentifier(sc.Title)
+		if seen[name]++; seen[name] > 1 {
+			name = fmt.Sprintf("%s%d", name, seen[name])
+		}
+		ref := scenarioRef(domain, r.ID, sc.ID)
+		fmt.Fprintf(&b, "\n// Scenario: %s (%s)\n", ref, sc.Title)
+		if steps := ScenarioSteps(sc); len(steps) > 0 {
+			b.WriteString("//\n")
+			for _, step := range steps {
+				fmt.Fprintf(&b, "//\t%s\n", step)
+			}
+		}
+		fmt.Fprintf(&b, "func %s(t *testing.T) {\n\tt.Skip(%q)\n}\n", name, "not implemented: "+ref)
+	}
+	return []byte(b.String())
+}
+
+func renderGherkinSkeleton(domain string, r model.Requirement) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Generated by 'teamwerx spec req extract-scenarios' from %s/%s.\n", domain, r.ID)
+	fmt.Fprintf(&b, "Feature: %s\n", r.Title)
+	for _, sc := range r.Scenarios {
+		fmt.Fprintf(&b, "\n  # %s\n  Scenario: %s\n", scenarioRef(domain, r.ID, sc.ID), sc.Title)
+		for _, step := range ScenarioSteps(sc) {
+			fmt.Fprintf(&b, "    %s\n", gherkinStep(step))
+		}
+	}
+	return []byte(b.String())
+}
+
+// scenarioRef names a scenario as "<domain>/<requirement>#<scenario>".
+func scenarioRef(domain, reqID, scenarioID string) string {
+	return domain + "/" + reqID + "#" + scenarioID
+}
+
+var (
+	scenarioListItem = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+(.*)$`)
+	stepEmphasis     = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+)
+
+// ScenarioSteps returns the steps of a scenario: the list items under its
+// heading, or its non-blank lines when it has no list, with bold markup
+// removed (e.g. "**GIVEN** a user" becomes "GIVEN a user").
+func ScenarioSteps(sc model.Scenario) []string {
+	lines := strings.Split(sc.Content, "\n")
+	if len(lines) > 0 && strings.HasPrefix(strings.TrimSpace(lines[0]), "#") {
+		lines = lines[1:]
+	}
+	var items, text []string
+	for _, line := range lines {
+		line = stepEmphasis.ReplaceAllString(strings.TrimRight(line, "\r"), "$1$2")
+		if m := scenarioListItem.FindStringSubmatch(line); m != nil {
+			items = append(items, strings.TrimSpace(m[1]))
+		} else if t := strings.TrimSpace(line); t != "" {
+			text = append(text, t)
+		}
+	}
+	if len(items) > 0 {
+		return items
+	}
+	return text
+}
+
+// gherkinStep turns a step into a gherkin step line: a leading GIVEN, WHEN,
+// THEN, AND or BUT becomes the keyword, anything else is a "*" step.
+func gherkinStep(step string) string {
+	word, rest, _ := strings.Cut(step, " ")
+	switch kw := strings.ToUpper(strings.TrimRight(word, ":,")); kw {
+	case "GIVEN", "WHEN", "THEN", "AND", "BUT":
+		return kw[:1] + strings.ToLower(kw[1:]) + " " + strings.TrimSpace(rest)
+	}
+	return "* " + step
+}
+
+// goIdentifier turns a title into an exported Go identifier, e.g.
+// "Locked account" -> "LockedAccount".
+func goIdentifier(title string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(utils.ToKebabCase(title), "-") {
+		if part != "" {
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	if b.Len() == 0 {
+		return "Scenario"
+	}
+	return b.String()
+}
@@ -0,0 +1,69 @@
+package core
+
+import (
+	"go/format"
+	"strings"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestRenderScenarioSkeletons(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatalf("NewApp: %v", err)
+	}
+	content := "# Auth\n\n### Requirement: Login\n\nUsers log in.\n\n" +
+		"#### Scenario: Locked account\n\n- **GIVEN** a locked user\n- **WHEN** they log in\n- THEN login is refused\n\n" +
+		"#### Scenario: Remembered device\n\nA known device skips the second factor.\n\n" +
+		"### Requirement: Logout\n\nUsers log out.\n"
+	if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "user-auth", Content: content}); err != nil {
+		t.Fatalf("WriteSpec: %v", err)
+	}
+	spec, err := app.SpecManager.ReadSpec("user-auth")
+	if err != nil {
+		t.Fatalf("ReadSpec: %v", err)
+	}
+
+	skeletons, err := RenderScenarioSkeletons(spec, "", SkeletonOptions{})
+	if err != nil {
+		t.Fatalf("RenderScenarioSkeletons: %v", err)
+	}
+	if len(skeletons) != 1 || skeletons[0].Path != "user_auth_login_test.go" || skeletons[0].Scenarios != 2 {
+		t.Fatalf("expected one Go file for login, got %+v", skeletons)
+	}
+	src := skeletons[0].Content
+	if formatted, err := format.Source(src); err != nil || string(formatted) != string(src) {
+		t.Fatalf("expected gofmt-clean Go (err %v):\n%s", err, src)
+	}
+	for _, want := range []string{
+		"package user_auth_test",
+		"// Scenario: user-auth/login#locked-account (Locked account)\n//\n//\tGIVEN a locked user\n//\tWHEN they log in\n//\tTHEN login is refused\nfunc TestLogin_LockedAccount(t *testing.T) {",
+		`t.Skip("not implemented: user-auth/login#remembered-device")`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Fatalf("expected %q in:\n%s", want, src)
+		}
+	}
+
+	features, err := RenderScenarioSkeletons(spec, "login", SkeletonOptions{Format: SkeletonGherkin})
+	if err != nil || len(features) != 1 || features[0].Path != "user_auth_login.feature" {
+		t.Fatalf("expected one feature file, got %+v (err %v)", features, err)
+	}
+	want := "Feature: Login\n\n  # user-auth/login#locked-account\n  Scenario: Locked account\n    Given a locked user\n    When they log in\n    Then login is refused\n\n" +
+		"  # user-auth/login#remembered-device\n  Scenario: Remembered device\n    * A known device skips the second factor.\n"
+	if !strings.HasSuffix(string(features[0].Content), want) {
+		t.Fatalf("unexpected feature:\n%s", features[0].Content)
+	}
+
+	if _, err := RenderScenarioSkeletons(spec, "logout", SkeletonOptions{}); err == nil {
+		t.Fatalf("expected a requirement without scenarios to be rejected")
+	}
+	if _, err := RenderScenarioSkeletons(spec, "missing", SkeletonOptions{}); err == nil {
+		t.Fatalf("expected an unknown requirement to be rejected")
+	}
+	if _, err := RenderScenarioSkeletons(spec, "", SkeletonOptions{Format: "rspec"}); err == nil {
+		t.Fatalf("expected an unknown format to be rejected")
+	}
+}
@@ -0,0 +1,255 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+)
+
+// Secret scanning modes.
+const (
+	SecretsModeOff    = "off"    // no scanning (default)
+	SecretsModeWarn   = "warn"   // write as-is and report findings
+	SecretsModeRedact = "redact" // replace findings with a placeholder and report them
+	SecretsModeBlock  = "block"  // refuse the write with *SecretsDetectedError
+)
+
+// SecretsModes lists the accepted secrets.mode values.
+var SecretsModes = []string{SecretsModeOff, SecretsModeWarn, SecretsModeRedact, SecretsModeBlock}
+
+// DefaultSecretEntropy is the minimum Shannon entropy, in bits per character,
+// for a long mixed-case alphanumeric token to be reported as a likely secret.
+const DefaultSecretEntropy = 4.0
+
+// minEntropyTokenLen is the shortest token the entropy check considers.
+const minEntropyTokenLen = 24
+
+// SecretsConfig is the "secrets" section of config.yaml. Discussion entries and
+// specs are scanned before they are written.
+//
+//	secrets:
+//	  mode: redact             # off (default), warn, redact or block
+//	  patterns:                # extra regexes, checked alongside the built-in rules
+//	    - "ACME-[0-9]{12}"
+//	  allow:                   # matches of these regexes are never reported
+//	    - "sk-test-[a-z0-9]+"
+//	  entropy: 4.5             # high-entropy threshold; negative disables the check
+type SecretsConfig struct {
+	Mode     string   `yaml:"mode,omitempty"`
+	Patterns []string `yaml:"patterns,omitempty"`
+	Allow    []string `yaml:"allow,omitempty"`
+	Entropy  float64  `yaml:"entropy,omitempty"`
+}
+
+// SecretFinding is one suspected secret in scanned text. The secret itself is
+// never stored; Preview keeps only its first and last few characters.
+type SecretFinding struct {
+	Rule    string `json:"rule"`
+	Line    int    `json:"line"`
+	Preview string `json:"preview"`
+	start   int
+	end     int
+}
+
+// SecretsDetectedError is returned by writes refused in block mode.
+type SecretsDetectedError struct {
+	Source   string // e.g. "spec auth", "discussion 001-auth"
+	Findings []SecretFinding
+}
+
+func (e *SecretsDetectedError) Error() string {
+	parts := make([]string, len(e.Findings))
+	for i, f := range e.Findings {
+		parts[i] = fmt.Sprintf("%s at line %d (%s)", f.Rule, f.Line, f.Preview)
+	}
+	return fmt.Sprintf("possible secrets in %s: %s", e.Source, strings.Join(parts, "; "))
+}
+
+// secretRule is a named pattern. When group is non-zero only that capture
+// group is the secret (e.g. the value after "api_key=").
+type secretRule struct {
+	name  string
+	re    *regexp.Regexp
+	group int
+}
+
+var builtinSecretRules = []secretRule{
+	{name: "aws-access-key", re: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{name: "github-token", re: regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+	{name: "slack-token", re: regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{name: "google-api-key", re: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{name: "api-key", re: regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{20,}`)},
+	{name: "private-key", re: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?(?:-----END [A-Z ]*PRIVATE KEY-----|$)`)},
+	{name: "credential-assignment", re: regexp.MustCompile(`(?i)\b(?:api[_-]?key|secret|token|passw(?:or)?d)\b["']?\s*[:=]\s*["']?([A-Za-z0-9_\-/+=.]{12,})`), group: 1},
+}
+
+var entropyTokenPattern = regexp.MustCompile(`[A-Za-z0-9+/_\-=]{24,}`)
+
+// SecretScanner finds likely secrets with regex rules and an entropy check,
+// and applies the configured mode to writes. A nil *SecretScanner is off.
+type SecretScanner struct {
+	mode       string
+	rules      []secretRule
+	allow      []*regexp.Regexp
+	minEntropy float64
+	report     func(source string, findings []SecretFinding)
+}
+
+// NewSecretScanner builds a scanner from cfg. report, if non-nil, is called
+// with the findings of every write in warn and redact mode. It returns nil
+// when scanning is off.
+func NewSecretScanner(cfg SecretsConfig, report func(source string, findings []SecretFinding)) (*SecretScanner, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Mode == "" || cfg.Mode == SecretsModeOff {
+		return nil, nil
+	}
+	s := &SecretScanner{
+		mode:       cfg.Mode,
+		rules:      append([]secretRule(nil), builtinSecretRules...),
+		minEntropy: cfg.Entropy,
+		report:     report,
+	}
+	if s.minEntropy == 0 {
+		s.minEntropy = DefaultSecretEntropy
+	}
+	for i, p := range cfg.Patterns {
+		s.rules = append(s.rules, secretRule{name: fmt.Sprintf("pattern-%d", i+1), re: regexp.MustCompile(p)})
+	}
+	for _, p := range cfg.Allow {
+		s.allow = append(s.allow, regexp.MustCompile(p))
+	}
+	return s, nil
+}
+
+// Validate checks the mode and that every pattern compiles.
+func (c SecretsConfig) Validate() error {
+	var errs custom_errors.ValidationErrors
+	if c.Mode != "" {
+		ok := false
+		for _, m := range SecretsModes {
+			ok = ok || c.Mode == m
+		}
+		if !ok {
+			errs.Add("secrets.mode", fmt.Sprintf("%q must be one of %s", c.Mode, strings.Join(SecretsModes, ", ")))
+		}
+	}
+	for _, group := range []struct {
+		field    string
+		patterns []string
+	}{{"secrets.patterns", c.Patterns}, {"secrets.allow", c.Allow}} {
+		for i, p := range group.patterns {
+			if _, err := regexp.Compile(p); err != nil {
+				errs.Add(fmt.Sprintf("%s[%d]", group.field, i), fmt.Sprintf("invalid pattern %q: %v", p, err))
+			}
+		}
+	}
+	return errs.Err()
+}
+
+// Scan returns the suspected secrets in text, in order of appearance.
+// Overlapping matches are reported once.
+func (s *SecretScanner) Scan(text string) []SecretFinding {
+	if s == nil {
+		return nil
+	}
+	var found []SecretFinding
+	add := func(rule string, start, end int) {
+		secret := text[start:end]
+		for _, a := range s.allow {
+			if a.MatchString(secret) {
+				return
+			}
+		}
+		found = append(found, SecretFinding{Rule: rule, start: start, end: end})
+	}
+	for _, r := range s.rules {
+		for _, m := range r.re.FindAllStringSubmatchIndex(text, -1) {
+			start, end := m[0], m[1]
+			if r.group > 0 && m[2*r.group] >= 0 {
+				start, end = m[2*r.group], m[2*r.group+1]
+			}
+			add(r.name, start, end)
+		}
+	}
+	if s.minEntropy > 0 {
+		for _, m := range entropyTokenPattern.FindAllStringIndex(text, -1) {
+			if tok := text[m[0]:m[1]]; looksRandom(tok, s.minEntropy) {
+				add("high-entropy-string", m[0], m[1])
+			}
+		}
+	}
+
+	sort.SliceStable(found, func(i, j int) bool { return found[i].start < found[j].start })
+	var out []SecretFinding
+	for _, f := range found {
+		if n := len(out); n > 0 && f.start < out[n-1].end {
+			continue
+		}
+		f.Line = strings.Count(text[:f.start], "\n") + 1
+		f.Preview = maskSecret(text[f.start:f.end])
+		out = append(out, f)
+	}
+	return out
+}
+
+// Check scans text about to be written to source and applies the mode: it
+// returns the text to write (redacted in redact mode) or *SecretsDetectedError
+// in block mode.
+func (s *SecretScanner) Check(source, text string) (string, error) {
+	findings := s.Scan(text)
+	if len(findings) == 0 {
+		return text, nil
+	}
+	if s.mode == SecretsModeBlock {
+		return "", &SecretsDetectedError{Source: source, Findings: findings}
+	}
+	if s.report != nil {
+		s.report(source, findings)
+	}
+	if s.mode != SecretsModeRedact {
+		return text, nil
+	}
+	var b strings.Builder
+	last := 0
+	for _, f := range findings {
+		b.WriteString(text[last:f.start])
+		fmt.Fprintf(&b, "[REDACTED:%s]", f.Rule)
+		last = f.end
+	}
+	b.WriteString(text[last:])
+	return b.String(), nil
+}
+
+// looksRandom reports whether tok mixes upper case, lower case and digits and
+// has at least minEntropy bits of Shannon entropy per character. Hex digests
+// and ordinary identifiers fail the first test.
+func looksRandom(tok string, minEntropy float64) bool {
+	if len(tok) < minEntropyTokenLen || !strings.ContainsAny(tok, "0123456789") ||
+		strings.ToLower(tok) == tok || strings.ToUpper(tok) == tok {
+		return false
+	}
+	counts := map[rune]int{}
+	for _, r := range tok {
+		counts[r]++
+	}
+	var h float64
+	for _, c := range counts {
+		p := float64(c) / float64(len(tok))
+		h -= p * math.Log2(p)
+	}
+	return h >= minEntropy
+}
+
+// maskSecret keeps the first and last four characters of long secrets.
+func maskSecret(secret string) string {
+	if len(secret) <= 12 {
+		return strings.Repeat("*", len(secret))
+	}
+	return secret[:4] + "…" + secret[len(secret)-4:]
+}
@@ -0,0 +1,167 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/utils"
+)
+
+// Sort orders accepted by the Sort* helpers.
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// Sort keys accepted by the listing commands. Every key falls back to the
+// natural order of IDs (then dates) so ties are always broken deterministically.
+var (
+	SpecSortKeys       = []string{"domain", "requirements"}
+	ChangeSortKeys     = []string{"id", "created", "status", "title"}
+	DiscussionSortKeys = []string{"id", "date", "type"}
+	TaskSortKeys       = []string{"id", "status", "title"}
+)
+
+// compareFunc is a three-way comparison of items i and j: negative if i sorts first, zero if equal.
+type compareFunc func(i, j int) int
+
+func compareNatural(a, b string) int {
+	switch {
+	case utils.NaturalLess(a, b):
+		return -1
+	case utils.NaturalLess(b, a):
+		return 1
+	}
+	return 0
+}
+
+func compareTime(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
+
+func compareInt(a, b int) int {
+	return a - b
+}
+
+// sortBy stably sorts n items by the first non-zero comparison in keys, reversing
+// the result for SortDesc.
+func sortBy(n int, swap func(i, j int), order string, keys ...compareFunc) error {
+	desc, err := isDescending(order)
+	if err != nil {
+		return err
+	}
+	sort.Stable(comparator{n: n, swap: swap, cmp: func(i, j int) bool {
+		for _, k := range keys {
+			if c := k(i, j); c != 0 {
+				if desc {
+					return c > 0
+				}
+				return c < 0
+			}
+		}
+		return false
+	}})
+	return nil
+}
+
+type comparator struct {
+	n    int
+	swap func(i, j int)
+	cmp  func(i, j int) bool
+}
+
+func (c comparator) Len() int           { return c.n }
+func (c comparator) Less(i, j int) bool { return c.cmp(i, j) }
+func (c comparator) Swap(i, j int)      { c.swap(i, j) }
+
+func isDescending(order string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(order)) {
+	case "", SortAsc:
+		return false, nil
+	case SortDesc:
+		return true, nil
+	}
+	return false, fmt.Errorf("invalid sort order %q (expected %s or %s)", order, SortAsc, SortDesc)
+}
+
+func unknownSortKey(key string, valid []string) error {
+	return fmt.Errorf("invalid sort key %q (expected one of: %s)", key, strings.Join(valid, ", "))
+}
+
+// SortSpecs orders specs by key ("domain" by default).
+func SortSpecs(specs []*model.Spec, key, order string) error {
+	byDomain := func(i, j int) int { return compareNatural(specs[i].Domain, specs[j].Domain) }
+	keys := []compareFunc{byDomain}
+	switch key {
+	case "", "domain":
+	case "requirements":
+		keys = []compareFunc{func(i, j int) int { return compareInt(len(specs[i].Requirements), len(specs[j].Requirements)) }, byDomain}
+	default:
+		return unknownSortKey(key, SpecSortKeys)
+	}
+	return sortBy(len(specs), func(i, j int) { specs[i], specs[j] = specs[j], specs[i] }, order, keys...)
+}
+
+// SortChanges orders changes by key ("id" by default).
+func SortChanges(changes []*model.Change, key, order string) error {
+	byID := func(i, j int) int { return compareNatural(changes[i].ID, changes[j].ID) }
+	byCreated := func(i, j int) int { return compareTime(changes[i].CreatedAt, changes[j].CreatedAt) }
+	var keys []compareFunc
+	switch key {
+	case "", "id":
+		keys = []compareFunc{byID, byCreated}
+	case "created":
+		keys = []compareFunc{byCreated, byID}
+	case "status":
+		keys = []compareFunc{func(i, j int) int { return strings.Compare(changes[i].Status, changes[j].Status) }, byID, byCreated}
+	case "title":
+		keys = []compareFunc{func(i, j int) int { return compareNatural(changes[i].Title, changes[j].Title) }, byID, byCreated}
+	default:
+		return unknownSortKey(key, ChangeSortKeys)
+	}
+	return sortBy(len(changes), func(i, j int) { changes[i], changes[j] = changes[j], changes[i] }, order, keys...)
+}
+
+// SortDiscussionEntries orders discussion entries by key ("id" by default).
+func SortDiscussionEntries(entries []model.DiscussionEntry, key, order string) error {
+	byID := func(i, j int) int { return compareNatural(entries[i].ID, entries[j].ID) }
+	byDate := func(i, j int) int { return compareTime(entries[i].Timestamp, entries[j].Timestamp) }
+	var keys []compareFunc
+	switch key {
+	case "", "id":
+		keys = []compareFunc{byID, byDate}
+	case "date":
+		keys = []compareFunc{byDate, byID}
+	case "type":
+		keys = []compareFunc{func(i, j int) int { return strings.Compare(entries[i].Type, entries[j].Type) }, byID, byDate}
+	default:
+		return unknownSortKey(key, DiscussionSortKeys)
+	}
+	return sortBy(len(entries), func(i, j int) { entries[i], entries[j] = entries[j], entries[i] }, order, keys...)
+}
+
+// SortTasks orders plan tasks by key ("id" by default).
+func SortTasks(tasks []model.Task, key, order string) error {
+	byID := func(i, j int) int { return compareNatural(tasks[i].ID, tasks[j].ID) }
+	var keys []compareFunc
+	switch key {
+	case "", "id":
+		keys = []compareFunc{byID}
+	case "status":
+		keys = []compareFunc{func(i, j int) int { return strings.Compare(tasks[i].Status, tasks[j].Status) }, byID}
+	case "title":
+		keys = []compareFunc{func(i, j int) int { return compareNatural(tasks[i].Title, tasks[j].Title) }, byID}
+	default:
+		return unknownSortKey(key, TaskSortKeys)
+	}
+	return sortBy(len(tasks), func(i, j int) { tasks[i], tasks[j] = tasks[j], tasks[i] }, order, keys...)
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+func TestSortChanges_NaturalIDsAndOrder(t *testing.T) {
+	changes := []*model.Change{{ID: "CH-10"}, {ID: "CH-9"}, {ID: "CH-009a"}, {ID: "CH-100"}}
+	if err := SortChanges(changes, "id", SortAsc); err != nil {
+		t.Fatalf("SortChanges failed: %v", err)
+	}
+	assertChangeOrder(t, changes, "CH-9", "CH-009a", "CH-10", "CH-100")
+
+	if err := SortChanges(changes, "id", SortDesc); err != nil {
+		t.Fatalf("SortChanges failed: %v", err)
+	}
+	assertChangeOrder(t, changes, "CH-100", "CH-10", "CH-009a", "CH-9")
+}
+
+func TestSortChanges_CreatedFallsBackToID(t *testing.T) {
+	now := time.Now()
+	changes := []*model.Change{
+		{ID: "CH-2", CreatedAt: now},
+		{ID: "CH-3", CreatedAt: now.Add(-time.Hour)},
+		{ID: "CH-1", CreatedAt: now},
+	}
+	if err := SortChanges(changes, "created", ""); err != nil {
+		t.Fatalf("SortChanges failed: %v", err)
+	}
+	assertChangeOrder(t, changes, "CH-3", "CH-1", "CH-2")
+}
+
+func TestSortTasks_NestedIDs(t *testing.T) {
+	tasks := []model.Task{{ID: "T02.10"}, {ID: "T10"}, {ID: "T02.2"}, {ID: "T02"}}
+	if err := SortTasks(tasks, "", ""); err != nil {
+		t.Fatalf("SortTasks failed: %v", err)
+	}
+	want := []string{"T02", "T02.2", "T02.10", "T10"}
+	for i, id := range want {
+		if tasks[i].ID != id {
+			t.Fatalf("position %d: expected %s, got %s", i, id, tasks[i].ID)
+		}
+	}
+}
+
+func TestSort_InvalidKeyAndOrder(t *testing.T) {
+	if err := SortSpecs(nil, "bogus", SortAsc); err == nil {
+		t.Fatal("expected error for unknown sort key")
+	}
+	if err := SortDiscussionEntries(nil, "id", "sideways"); err == nil {
+		t.Fatal("expected error for unknown sort order")
+	}
+}
+
+func TestListChanges_NaturalOrder(t *testing.T) {
+	baseDir := createTempDir(t)
+	for _, id := range []string{"CH-10", "CH-2", "CH-1"} {
+		writeChangeJSON(t, baseDir, id, map[string]any{"id": id, "title": id})
+	}
+	changes, err := NewChangeManager(baseDir, nil, nil).ListChanges()
+	if err != nil {
+		t.Fatalf("ListChanges failed: %v", err)
+	}
+	assertChangeOrder(t, changes, "CH-1", "CH-2", "CH-10")
+}
+
+func assertChangeOrder(t *testing.T, changes []*model.Change, want ...string) {
+	t.Helper()
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %d", len(want), len(changes))
+	}
+	for i, id := range want {
+		if changes[i].ID != id {
+			t.Fatalf("position %d: expected %s, got %s", i, id, changes[i].ID)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"strings"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// Requirement comparison results.
+const (
+	CompareLeftOnly  = "left-only"
+	CompareRightOnly = "right-only"
+	CompareDiffers   = "differs"
+	CompareSame      = "same"
+)
+
+// RequirementComparison is one requirement ID in either of two compared specs.
+type RequirementComparison struct {
+	ID         string        `json:"id"`
+	Status     string        `json:"status"`
+	LeftTitle  string        `json:"left_title,omitempty"`
+	RightTitle string        `json:"right_title,omitempty"`
+	Segments   []DiffSegment `json:"-"` // word diff from left to right (differs only)
+}
+
+// CompareSpecs matches the requirements of two specs by ID: those only in one
+// spec, and a word diff of the blocks for shared IDs whose text differs
+// (surrounding whitespace is ignored). Results follow left's order, then
+// right-only requirements in right's order.
+func CompareSpecs(left, right *model.Spec) []RequirementComparison {
+	rightByID := map[string]*model.Requirement{}
+	for i := range right.Requirements {
+		rightByID[right.Requirements[i].ID] = &right.Requirements[i]
+	}
+	seen := map[string]bool{}
+	var out []RequirementComparison
+	for _, l := range left.Requirements {
+		seen[l.ID] = true
+		r := rightByID[l.ID]
+		if r == nil {
+			out = append(out, RequirementComparison{ID: l.ID, Status: CompareLeftOnly, LeftTitle: l.Title})
+			continue
+		}
+		c := RequirementComparison{ID: l.ID, Status: CompareSame, LeftTitle: l.Title, RightTitle: r.Title}
+		lt := strings.TrimSpace(left.Content[l.Start:l.End]) + "\n"
+		rt := strings.TrimSpace(right.Content[r.Start:r.End]) + "\n"
+		if lt != rt {
+			c.Status = CompareDiffers
+			c.Segments = WordDiff(lt, rt)
+		}
+		out = append(out, c)
+	}
+	for _, r := range right.Requirements {
+		if !seen[r.ID] {
+			out = append(out, RequirementComparison{ID: r.ID, Status: CompareRightOnly, RightTitle: r.Title})
+		}
+	}
+	return out
+}
@@ -0,0 +1,38 @@
+package core
+
+import (
+	"testing"
+)
+
+func TestCompareSpecs(t *testing.T) {
+	left, _ := NewSpecParser().Parse([]byte("# Auth\n\n### Requirement: Login\n\nUsers log in.\n\n### Requirement: Logout\n\nUsers log out.\n\n### Requirement: Audit\n\nEvents are logged.\n"))
+	right, _ := NewSpecParser().Parse([]byte("# Login\n\n### Requirement: SSO\n\nSSO works.\n\n### Requirement: Login\n\nUsers log in quickly.\n\n### Requirement: Audit\n\nEvents are logged.\n"))
+
+	got := CompareSpecs(left, right)
+	want := []struct{ id, status string }{
+		{"login", CompareDiffers},
+		{"logout", CompareLeftOnly},
+		{"audit", CompareSame},
+		{"sso", CompareRightOnly},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("comparisons = %+v", got)
+	}
+	for i, w := range want {
+		if got[i].ID != w.id || got[i].Status != w.status {
+			t.Fatalf("comparison %d = %+v, want %s %s", i, got[i], w.id, w.status)
+		}
+	}
+	var inserted string
+	for _, seg := range got[0].Segments {
+		if seg.Op == DiffInsert {
+			inserted += seg.Text
+		}
+	}
+	if inserted != "in quickly." {
+		t.Fatalf("login diff inserts %q", inserted)
+	}
+	if got[2].Segments != nil {
+		t.Fatalf("identical requirement has a diff: %+v", got[2].Segments)
+	}
+}
@@ -0,0 +1,154 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"path"
+	"strings"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/utils"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// SpecExporter renders specs as standalone HTML documents for publishing.
+//
+// Rendering rules:
+//   - Requirement headings get an id equal to the requirement ID, so
+//     "<domain>.html#<requirement-id>" is a stable anchor.
+//   - Other headings get an id derived from their kebab-cased text.
+//   - Links to another domain's spec.md (e.g. "../billing/spec.md#invoice")
+//     are rewritten to the exported page ("billing.html#invoice").
+//   - With OmitNotes set, requirement notes (rationale and <details> sections)
+//     are left out of the page.
+type SpecExporter struct {
+	md goldmark.Markdown
+	// OmitNotes drops requirement notes from rendered pages.
+	OmitNotes bool
+	// Format is the requirement heading format; zero means the default.
+	Format SpecFormat
+}
+
+// NewSpecExporter creates a new SpecExporter.
+func NewSpecExporter() *SpecExporter {
+	return &SpecExporter{md: goldmark.New()}
+}
+
+// SpecPageName returns the exported file name for a domain.
+func SpecPageName(domain string) string {
+	return domain + ".html"
+}
+
+// RenderSpec renders a single spec as a full HTML page.
+func (e *SpecExporter) RenderSpec(spec *model.Spec) ([]byte, error) {
+	if spec == nil {
+		return nil, fmt.Errorf("spec is nil")
+	}
+	src := []byte(spec.Content)
+	if e.OmitNotes {
+		src = []byte(StripRequirementNotes(spec))
+	}
+	src = bytes.TrimPrefix(src, []byte(spec.FrontMatter))
+	body, err := e.renderMarkdown(src, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to render spec %s: %w", spec.Domain, err)
+	}
+
+	var buf bytes.Buffer
+	writePageHeader(&buf, spec.Domain)
+	buf.WriteString(`<nav><a href="index.html">&larr; All specs</a></nav>` + "\n")
+	buf.Write(body)
+	writePageFooter(&buf)
+	return buf.Bytes(), nil
+}
+
+// renderMarkdown renders src as an HTML fragment with the heading ids and
+// spec links of RenderSpec. Rewritten spec links are prefixed with pageDir,
+// the path from the rendered page to the spec pages.
+func (e *SpecExporter) renderMarkdown(src []byte, pageDir string) ([]byte, error) {
+	doc := e.md.Parser().Parse(text.NewReader(src))
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.Heading:
+			title := strings.TrimSpace(string(node.Text(src)))
+			id := utils.ToKebabCase(title)
+			if reqTitle, ok := e.Format.WithDefaults().requirementTitle(node.Level, title); ok {
+				id = utils.ToKebabCase(reqTitle)
+			}
+			if id != "" {
+				node.SetAttributeString("id", []byte(id))
+			}
+		case *ast.Link:
+			dest := string(node.Destination)
+			if rewritten := rewriteSpecLink(dest); rewritten != dest {
+				node.Destination = []byte(pageDir + rewritten)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	var body bytes.Buffer
+	if err := e.md.Renderer().Render(&body, src, doc); err != nil {
+		return nil, err
+	}
+	return body.Bytes(), nil
+}
+
+// RenderIndex renders an index page linking every spec and its requirements.
+func (e *SpecExporter) RenderIndex(specs []*model.Spec) []byte {
+	var buf bytes.Buffer
+	writePageHeader(&buf, "Specs")
+	buf.WriteString("<h1>Specs</h1>\n<ul>\n")
+	for _, spec := range specs {
+		page := html.EscapeString(SpecPageName(spec.Domain))
+		fmt.Fprintf(&buf, "<li><a href=\"%s\">%s</a> (%d requirement(s))", page, html.EscapeString(spec.Domain), len(spec.Requirements))
+		if len(spec.Requirements) > 0 {
+			buf.WriteString("\n<ul>\n")
+			for _, r := range spec.Requirements {
+				fmt.Fprintf(&buf, "<li><a href=\"%s#%s\">%s</a></li>\n", page, html.EscapeString(r.ID), html.EscapeString(r.Title))
+			}
+			buf.WriteString("</ul>\n")
+		}
+		buf.WriteString("</li>\n")
+	}
+	buf.WriteString("</ul>\n")
+	writePageFooter(&buf)
+	return buf.Bytes()
+}
+
+// rewriteSpecLink maps links that point at a domain's spec.md to its exported page.
+// Absolute URLs and other links are returned unchanged.
+func rewriteSpecLink(dest string) string {
+	if strings.Contains(dest, "://") || strings.HasPrefix(dest, "mailto:") {
+		return dest
+	}
+	target, fragment := dest, ""
+	if i := strings.Index(dest, "#"); i >= 0 {
+		target, fragment = dest[:i], dest[i:]
+	}
+	if !strings.EqualFold(path.Base(target), "spec.md") {
+		return dest
+	}
+	domain := path.Base(path.Dir(target))
+	if domain == "." || domain == "/" || domain == ".." {
+		return dest
+	}
+	return SpecPageName(domain) + fragment
+}
+
+func writePageHeader(buf *bytes.Buffer, title string) {
+	buf.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(buf, "<title>%s</title>\n", html.EscapeString(title))
+	buf.WriteString("<style>body{font-family:sans-serif;max-width:52rem;margin:2rem auto;padding:0 1rem;line-height:1.5}h3[id]{border-top:1px solid #ddd;padding-top:.5rem}</style>\n")
+	buf.WriteString("</head>\n<body>\n")
+}
+
+func writePageFooter(buf *bytes.Buffer) {
+	buf.WriteString("</body>\n</html>\n")
+}
@@ -0,0 +1,47 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+func TestSpecExporter_RenderSpecAnchorsAndLinks(t *testing.T) {
+	parser := NewSpecParser()
+	spec, err := parser.Parse([]byte("# Auth\n\n## Overview\n\nSee [invoices](../billing/spec.md#invoice) and [site](https://example.com/spec.md).\n\n### Requirement: Password Reset\n\nUsers can reset passwords.\n"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	spec.Domain = "auth"
+
+	out, err := NewSpecExporter().RenderSpec(spec)
+	if err != nil {
+		t.Fatalf("RenderSpec failed: %v", err)
+	}
+	html := string(out)
+	for _, want := range []string{
+		`<h3 id="password-reset">`,
+		`<h2 id="overview">`,
+		`href="billing.html#invoice"`,
+		`href="https://example.com/spec.md"`,
+		`href="index.html"`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, html)
+		}
+	}
+}
+
+func TestSpecExporter_RenderIndex(t *testing.T) {
+	specs := []*model.Spec{
+		{Domain: "auth", Requirements: []model.Requirement{{ID: "login", Title: "Login"}}},
+		{Domain: "billing"},
+	}
+	html := string(NewSpecExporter().RenderIndex(specs))
+	for _, want := range []string{`href="auth.html"`, `href="auth.html#login"`, `href="billing.html"`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected index to contain %q, got:\n%s", want, html)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+)
+
+// Default requirement heading: "### Requirement: <title>".
+const (
+	DefaultRequirementLevel  = 3
+	DefaultRequirementPrefix = "Requirement:"
+)
+
+// SpecFormat says how spec files mark a requirement: the level of its
+// heading and the prefix of the heading text. It is the "spec_format"
+// section of config.yaml; empty fields take the defaults.
+//
+//	spec_format:
+//	  requirement_level: 2
+//	  requirement_prefix: "REQ:"
+type SpecFormat struct {
+	RequirementLevel  int    `yaml:"requirement_level,omitempty" json:"requirement_level"`
+	RequirementPrefix string `yaml:"requirement_prefix,omitempty" json:"requirement_prefix"`
+}
+
+// DefaultSpecFormat returns the built-in requirement heading format.
+func DefaultSpecFormat() SpecFormat {
+	return SpecFormat{}.WithDefaults()
+}
+
+// WithDefaults returns a copy of the format with empty fields set to their
+// defaults.
+func (f SpecFormat) WithDefaults() SpecFormat {
+	if f.RequirementLevel == 0 {
+		f.RequirementLevel = DefaultRequirementLevel
+	}
+	if strings.TrimSpace(f.RequirementPrefix) == "" {
+		f.RequirementPrefix = DefaultRequirementPrefix
+	}
+	f.RequirementPrefix = strings.TrimSpace(f.RequirementPrefix)
+	return f
+}
+
+// Validate checks the heading level and prefix. Requirements are split at
+// headings of level 1-3, and the prefix ends with a colon so the title
+// after it can be told apart.
+func (f SpecFormat) Validate() error {
+	var errs custom_errors.ValidationErrors
+	if f.RequirementLevel < 0 || f.RequirementLevel > 3 {
+		errs.Add("spec_format.requirement_level", "must be 1, 2 or 3")
+	}
+	if p := strings.TrimSpace(f.RequirementPrefix); p != "" && (!strings.HasSuffix(p, ":") || strings.ContainsAny(p, "#\n")) {
+		errs.Add("spec_format.requirement_prefix", fmt.Sprintf("%q must end with a colon and hold no '#' or line break", p))
+	}
+	return errs.Err()
+}
+
+// RequirementHeading returns the heading line of a requirement titled title,
+// without a line break.
+func (f SpecFormat) RequirementHeading(title string) string {
+	f = f.WithDefaults()
+	return strings.Repeat("#", f.RequirementLevel) + " " + f.RequirementPrefix + " " + strings.TrimSpace(title)
+}
+
+// requirementTitle returns the title of a requirement heading of level with
+// text heading, and whether it is one.
+func (f SpecFormat) requirementTitle(level int, heading string) (string, bool) {
+	if level != f.RequirementLevel || !strings.HasPrefix(heading, f.RequirementPrefix) {
+		return "", false
+	}
+	return strings.TrimSpace(strings.TrimPrefix(heading, f.RequirementPrefix)), true
+}
+
+// loosePrefix matches heading text that starts with the prefix but for its
+// case and spaces before the colon, e.g. "requirement : Login".
+func (f SpecFormat) loosePrefix() *regexp.Regexp {
+	word := strings.TrimSpace(strings.TrimSuffix(f.RequirementPrefix, ":"))
+	return regexp.MustCompile(`(?i)^` + regexp.QuoteMeta(word) + `\s*:\s*(.*)$`)
+}
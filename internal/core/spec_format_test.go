@@ -0,0 +1,139 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestSpecParser_ToleratesHeadingSlips(t *testing.T) {
+	content := "# Auth\n\n###Requirement: Login\n\nUsers log in.\n\n### requirement : Logout\n\nUsers log out.\n\n## Requirement: Misplaced\n\nNot a requirement.\n"
+	spec, err := NewSpecParser().Parse([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, r := range spec.Requirements {
+		ids = append(ids, r.ID)
+	}
+	if strings.Join(ids, ",") != "login,logout" {
+		t.Fatalf("expected login and logout, got %v", ids)
+	}
+	if len(spec.Warnings) != 3 {
+		t.Fatalf("expected a warning per slip, got %q", spec.Warnings)
+	}
+	if !strings.HasPrefix(spec.Warnings[0], "line 3: ") {
+		t.Errorf("expected the first warning to name line 3, got %q", spec.Warnings[0])
+	}
+
+	clean, err := NewSpecParser().Parse([]byte("# Auth\n\n### Requirement: Login\n\nUsers log in.\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clean.Warnings) != 0 {
+		t.Errorf("expected no warnings for a well-formed spec, got %q", clean.Warnings)
+	}
+}
+
+func TestSpecParser_CustomFormat(t *testing.T) {
+	format := SpecFormat{RequirementLevel: 2, RequirementPrefix: "REQ:"}
+	content := "# Auth\n\n## REQ: Login\n\nUsers log in.\n\n### Scenario: Valid password\n\nGiven a user.\n\n## Notes\n\nPlain section.\n\n## REQ: Logout\n\nUsers log out.\n"
+	spec, err := NewSpecParserWithFormat(format).Parse([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spec.Requirements) != 2 || spec.Requirements[0].ID != "login" || spec.Requirements[1].ID != "logout" {
+		t.Fatalf("expected login and logout, got %+v", spec.Requirements)
+	}
+	if login := spec.Requirements[0]; !strings.Contains(content[login.Start:login.End], "Valid password") {
+		t.Errorf("expected the level 3 heading to stay inside login, got %q", content[login.Start:login.End])
+	}
+
+	ranges := scanRequirementRanges([]byte(content), format)
+	if len(ranges) != len(spec.Requirements) {
+		t.Fatalf("scan found %d requirements, parser %d", len(ranges), len(spec.Requirements))
+	}
+	for i, r := range spec.Requirements {
+		if ranges[i].ID != r.ID || ranges[i].Start != r.Start || ranges[i].End != r.End {
+			t.Errorf("scan %+v disagrees with parsed %s [%d,%d)", ranges[i], r.ID, r.Start, r.End)
+		}
+	}
+
+	if got := format.RequirementHeading("Audit Log"); got != "## REQ: Audit Log" {
+		t.Errorf("RequirementHeading = %q", got)
+	}
+}
+
+func TestSpecFormat_Validate(t *testing.T) {
+	for _, f := range []SpecFormat{{}, {RequirementLevel: 2, RequirementPrefix: "REQ:"}} {
+		if err := f.Validate(); err != nil {
+			t.Errorf("Validate(%+v): %v", f, err)
+		}
+	}
+	for _, f := range []SpecFormat{{RequirementLevel: 4}, {RequirementPrefix: "REQ"}, {RequirementPrefix: "# REQ:"}} {
+		if err := f.Validate(); err == nil {
+			t.Errorf("expected Validate(%+v) to fail", f)
+		}
+	}
+}
+
+func TestSpecManager_SetextHeadings(t *testing.T) {
+	baseDir := createTempDir(t)
+	writeSpecFile(t, baseDir, "auth", "Auth\n====\n\n### Requirement: Login\n\nUsers log in.\n\nNotes\n-----\n\nPlain section.\n\n### Requirement: Logout\n\nUsers log out.\n")
+
+	manager := NewSpecManager(baseDir)
+	spec, err := manager.ReadSpec("auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	login, err := NewSpecManager(baseDir).ReadRequirement("auth", "login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if login.End != spec.Requirements[0].End || strings.Contains(login.Content, "Plain section") {
+		t.Fatalf("expected login to end at the setext heading, got %q", login.Content)
+	}
+
+	delta := &model.SpecDelta{Domain: "auth", Operations: []model.DeltaOperation{{
+		Type:        "MODIFIED",
+		Requirement: model.Requirement{ID: "login", Content: "### Requirement: Login\n\nUsers log in with a passkey.\n"},
+	}}}
+	if err := NewSpecMerger(manager).Merge(delta); err != nil {
+		t.Fatal(err)
+	}
+	merged := readFile(t, manager.(*specManager).layout.SpecPath(baseDir, "auth"))
+	if !strings.Contains(merged, "passkey.\n\nNotes\n-----\n\nPlain section.") {
+		t.Fatalf("expected the setext section to survive the merge, got:\n%s", merged)
+	}
+}
+
+func TestApp_SpecFormatFromConfig(t *testing.T) {
+	store := storage.NewInMem()
+	if err := store.WriteFile(ConfigPath(".teamwerx"), []byte("spec_format:\n  requirement_level: 2\n  requirement_prefix: \"REQ:\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	app, err := NewAppWithStorage(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if app.Options.SpecFormat.RequirementLevel != 2 || app.Options.SpecFormat.RequirementPrefix != "REQ:" {
+		t.Fatalf("expected the configured format, got %+v", app.Options.SpecFormat)
+	}
+
+	delta := &model.SpecDelta{Domain: "auth", Operations: []model.DeltaOperation{{
+		Type:        "ADDED",
+		Requirement: model.Requirement{ID: "login", Title: "Login"},
+	}}}
+	if err := NewSpecMerger(app.SpecManager).Merge(delta); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := app.SpecManager.ReadSpec("auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spec.Requirements) != 1 || !strings.HasPrefix(spec.Content, "## REQ: Login") {
+		t.Fatalf("expected a \"## REQ:\" requirement, got %q", spec.Content)
+	}
+}
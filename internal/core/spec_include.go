@@ -0,0 +1,131 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// SharedSpecDir is the directory under the specs directory holding shared
+// requirement libraries: "_shared/<name>.md" is included as "shared/<name>".
+const SharedSpecDir = "_shared"
+
+// includeDirective matches an "!include shared/<name>" line. Names are
+// slash-separated path segments without "..".
+var includeDirective = regexp.MustCompile(`^!include\s+shared/([A-Za-z0-9][A-Za-z0-9._-]*(?:/[A-Za-z0-9][A-Za-z0-9._-]*)*)\s*$`)
+
+// SharedLibraryPath returns the file of the shared library name, e.g.
+// "<specsDir>/_shared/security-baseline.md" for "security-baseline".
+func SharedLibraryPath(specsDir, name string) string {
+	return filepath.Join(specsDir, SharedSpecDir, filepath.FromSlash(name)+".md")
+}
+
+// expandIncludes replaces each "!include shared/<name>" line of raw, outside
+// fenced code, with the requirement blocks of that library.
+func (m *specManager) expandIncludes(raw string) (string, []model.SpecInclude, error) {
+	if !strings.Contains(raw, "!include") {
+		return raw, nil, nil
+	}
+	var out strings.Builder
+	var includes []model.SpecInclude
+	fence := ""
+	for _, line := range strings.SplitAfter(raw, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+		default:
+			if match := includeDirective.FindStringSubmatch(strings.TrimRight(line, "\r\n")); match != nil {
+				text, err := m.sharedRequirements(match[1])
+				if err != nil {
+					return "", nil, err
+				}
+				start := out.Len()
+				out.WriteString(text)
+				includes = append(includes, model.SpecInclude{Ref: "shared/" + match[1], Directive: line, Text: text, Start: start, End: out.Len()})
+				continue
+			}
+		}
+		out.WriteString(line)
+	}
+	return out.String(), includes, nil
+}
+
+// sharedRequirements returns a library's text from its first requirement
+// heading on, so a library may open with its own title and description.
+func (m *specManager) sharedRequirements(name string) (string, error) {
+	ref := "shared/" + name
+	data, err := m.store.ReadFile(SharedLibraryPath(m.baseDir, name))
+	if err != nil {
+		if _, ok := err.(*custom_errors.ErrNotFound); ok {
+			return "", custom_errors.NewErrNotFound("shared requirement library", ref)
+		}
+		return "", err
+	}
+	lib, err := m.parser.Parse(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", ref, err)
+	}
+	if len(lib.Requirements) == 0 {
+		return "", custom_errors.NewErrValidation("include", fmt.Sprintf("%s has no requirements", ref))
+	}
+	text := lib.Content[lib.Requirements[0].Start:]
+	for _, line := range strings.Split(text, "\n") {
+		if includeDirective.MatchString(strings.TrimRight(line, "\r")) {
+			return "", custom_errors.NewErrValidation("include", fmt.Sprintf("%s includes another library; includes do not nest", ref))
+		}
+	}
+	return strings.TrimRight(text, "\n") + "\n", nil
+}
+
+// collapseIncludes turns expanded content back into the file text: each
+// include's expansion is replaced by its directive line. An expansion that
+// no longer appears verbatim was edited in the domain, which is refused so
+// the library stays the single source of its requirements.
+func (m *specManager) collapseIncludes(domain, content string, includes []model.SpecInclude) (string, error) {
+	var out strings.Builder
+	cursor := 0
+	for _, inc := range includes {
+		i := strings.Index(content[cursor:], inc.Text)
+		if i < 0 {
+			name := strings.TrimPrefix(inc.Ref, "shared/")
+			return "", custom_errors.NewErrConflict(fmt.Sprintf("spec %s changes requirements included from %s; edit %s instead", domain, inc.Ref, SharedLibraryPath(m.baseDir, name)))
+		}
+		out.WriteString(content[cursor : cursor+i])
+		out.WriteString(inc.Directive)
+		cursor += i + len(inc.Text)
+	}
+	out.WriteString(content[cursor:])
+	return out.String(), nil
+}
+
+// parseExpanded expands raw's includes and parses the result, marking each
+// included requirement with its library.
+func (m *specManager) parseExpanded(raw []byte) (*model.Spec, error) {
+	content, includes, err := m.expandIncludes(string(raw))
+	if err != nil {
+		return nil, err
+	}
+	spec, err := m.parser.Parse([]byte(content))
+	if err != nil {
+		return nil, err
+	}
+	spec.Includes = includes
+	for i := range spec.Requirements {
+		r := &spec.Requirements[i]
+		for _, inc := range includes {
+			if r.Start >= inc.Start && r.Start < inc.End {
+				r.Source = inc.Ref
+			}
+		}
+	}
+	return spec, nil
+}
@@ -0,0 +1,63 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+func TestSpecManager_Includes(t *testing.T) {
+	baseDir := createTempDir(t)
+	if err := os.MkdirAll(filepath.Join(baseDir, SharedSpecDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(SharedLibraryPath(baseDir, "security-baseline"), []byte("# Security baseline\n\nShared by every service.\n\n### Requirement: Audit log\n\nEvery change is logged.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	writeSpecFile(t, baseDir, "payments", "# Payments\n\n!include shared/security-baseline\n\n### Requirement: Refunds\n\nRefunds are supported.\n")
+
+	manager := NewSpecManager(baseDir)
+	spec, err := manager.ReadSpec("payments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spec.Requirements) != 2 || spec.Requirements[0].ID != "audit-log" || spec.Requirements[0].Source != "shared/security-baseline" || spec.Requirements[1].Source != "" {
+		t.Fatalf("requirements = %+v", spec.Requirements)
+	}
+	if strings.Contains(spec.Content, "!include") || strings.Contains(spec.Content, "Shared by every service") {
+		t.Fatalf("content not expanded:\n%s", spec.Content)
+	}
+
+	// Merging into the domain keeps the directive in the file.
+	merger := NewSpecMerger(manager)
+	if err := merger.Merge(&model.SpecDelta{Domain: "payments", Operations: []model.DeltaOperation{{
+		Type:        "ADDED",
+		Requirement: model.Requirement{ID: "chargebacks", Title: "Chargebacks", Content: "### Requirement: Chargebacks\n\nChargebacks are tracked.\n"},
+	}}}); err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	content := readFile(t, filepath.Join(baseDir, "payments", "spec.md"))
+	if !strings.Contains(content, "!include shared/security-baseline\n") || strings.Contains(content, "Every change is logged") || !strings.Contains(content, "Chargebacks") {
+		t.Fatalf("file after merge:\n%s", content)
+	}
+
+	// Included requirements are changed in the library, not the domain.
+	err = merger.Merge(&model.SpecDelta{Domain: "payments", Operations: []model.DeltaOperation{{Type: "REMOVED", Requirement: model.Requirement{ID: "audit-log"}}}})
+	if _, ok := err.(*custom_errors.ErrConflict); !ok {
+		t.Fatalf("expected ErrConflict removing an included requirement, got %v", err)
+	}
+
+	writeSpecFile(t, baseDir, "orders", "# Orders\n\n!include shared/missing\n")
+	if _, err := manager.ReadSpec("orders"); err == nil {
+		t.Fatal("expected a missing library to fail")
+	}
+	writeSpecFile(t, baseDir, "docs", "# Docs\n\n```\n!include shared/missing\n```\n")
+	if _, err := manager.ReadSpec("docs"); err != nil {
+		t.Fatalf("directive in a code fence should be ignored: %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package core
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/utils"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// tagPattern matches "#tag" tokens in requirement prose. Heading markers never
+// match because they are not part of the heading's text nodes.
+var tagPattern = regexp.MustCompile(`(?:^|[\s(])#([A-Za-z][\w-]*)`)
+
+// SpecInfo is the machine-readable view of a spec printed by `spec show --json`.
+// Offsets are byte offsets into the spec file, after any "!include" lines are
+// expanded; End is exclusive. Lines are 1-based and inclusive.
+type SpecInfo struct {
+	Domain       string            `json:"domain"`
+	Fingerprint  string            `json:"fingerprint"`
+	Size         int               `json:"size"`
+	Meta         model.SpecMeta    `json:"meta"`
+	Requirements []RequirementInfo `json:"requirements"`
+}
+
+// RequirementInfo locates one requirement block and summarizes its content.
+type RequirementInfo struct {
+	ID         string         `json:"id"`
+	Title      string         `json:"title"`
+	Start      int            `json:"start"`
+	End        int            `json:"end"`
+	StartLine  int            `json:"start_line"`
+	EndLine    int            `json:"end_line"`
+	Hash       string         `json:"hash"`                 // fingerprint of the block text
+	Tags       []string       `json:"tags,omitempty"`       // "#tag" tokens in the prose, sorted
+	References []string       `json:"references,omitempty"` // link destinations, in document order
+	Code       []string       `json:"code,omitempty"`       // "Code:" path globs
+	Source     string         `json:"source,omitempty"`     // "shared/<name>" for included requirements
+	Scenarios  []ScenarioInfo `json:"scenarios,omitempty"`
+	// AcceptanceCriteria are the block's "- [ ]" checklist items.
+	AcceptanceCriteria []CriterionInfo `json:"acceptance_criteria,omitempty"`
+}
+
+// CriterionInfo is one acceptance criterion and the line it is on.
+type CriterionInfo struct {
+	Index int    `json:"index"`
+	Text  string `json:"text"`
+	Done  bool   `json:"done"`
+	Line  int    `json:"line"`
+}
+
+// ScenarioInfo locates one "#### Scenario:" sub-block of a requirement.
+type ScenarioInfo struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Start     int    `json:"start"`
+	End       int    `json:"end"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	Hash      string `json:"hash"`
+}
+
+// DescribeSpec builds a SpecInfo from a parsed spec. The spec must come from
+// SpecParser (e.g. SpecManager.ReadSpec) so requirement offsets are populated.
+func DescribeSpec(spec *model.Spec) *SpecInfo {
+	src := []byte(spec.Content)
+	info := &SpecInfo{
+		Domain:       spec.Domain,
+		Fingerprint:  spec.Fingerprint,
+		Size:         len(src),
+		Meta:         spec.Meta,
+		Requirements: []RequirementInfo{},
+	}
+	md := goldmark.New()
+	for _, r := range spec.Requirements {
+		block := src[r.Start:r.End]
+		if r.Fingerprint == "" {
+			r.Fingerprint = RequirementFingerprint(spec, r)
+		}
+		ri := RequirementInfo{
+			ID:        r.ID,
+			Title:     r.Title,
+			Start:     r.Start,
+			End:       r.End,
+			StartLine: lineNumber(src, r.Start),
+			EndLine:   lastLineNumber(src, r.Start, r.End),
+			Hash:      r.Fingerprint,
+			Code:      r.Code,
+			Source:    r.Source,
+		}
+		ri.Tags, ri.References = extractTagsAndReferences(md, block)
+		for _, c := range r.AcceptanceCriteria {
+			ri.AcceptanceCriteria = append(ri.AcceptanceCriteria, CriterionInfo{Index: c.Index, Text: c.Text, Done: c.Done, Line: lineNumber(src, c.Mark)})
+		}
+		for _, sc := range r.Scenarios {
+			ri.Scenarios = append(ri.Scenarios, ScenarioInfo{
+				ID:        sc.ID,
+				Title:     sc.Title,
+				Start:     sc.Start,
+				End:       sc.End,
+				StartLine: lineNumber(src, sc.Start),
+				EndLine:   lastLineNumber(src, sc.Start, sc.End),
+				Hash:      utils.GenerateFingerprint(sc.Content),
+			})
+		}
+		info.Requirements = append(info.Requirements, ri)
+	}
+	return info
+}
+
+// extractTagsAndReferences walks a requirement block's markdown AST collecting
+// "#tag" tokens from prose and link/autolink destinations. Code spans and code
+// blocks are ignored.
+func extractTagsAndReferences(md goldmark.Markdown, block []byte) ([]string, []string) {
+	doc := md.Parser().Parse(text.NewReader(block))
+	tagSet := map[string]bool{}
+	var refs []string
+	seenRef := map[string]bool{}
+	addRef := func(dest string) {
+		if dest != "" && !seenRef[dest] {
+			seenRef[dest] = true
+			refs = append(refs, dest)
+		}
+	}
+
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.Heading:
+			return ast.WalkSkipChildren, nil
+		case *ast.CodeSpan, *ast.FencedCodeBlock, *ast.CodeBlock:
+			return ast.WalkSkipChildren, nil
+		case *ast.Link:
+			addRef(string(node.Destination))
+		case *ast.AutoLink:
+			addRef(string(node.URL(block)))
+		case *ast.Paragraph, *ast.TextBlock:
+			var buf bytes.Buffer
+			for i := 0; i < node.Lines().Len(); i++ {
+				seg := node.Lines().At(i)
+				buf.Write(seg.Value(block))
+				buf.WriteByte('\n')
+			}
+			for _, m := range tagPattern.FindAllSubmatch(stripCodeSpans(buf.Bytes()), -1) {
+				tagSet[string(m[1])] = true
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	var tags []string
+	for t := range tagSet {
+		tags = append(tags, t)
+	}
+	sort.Strings(tags)
+	return tags, refs
+}
+
+// codeSpanPattern matches inline code so tags inside backticks are ignored.
+var codeSpanPattern = regexp.MustCompile("`[^`]*`")
+
+func stripCodeSpans(b []byte) []byte {
+	return codeSpanPattern.ReplaceAll(b, nil)
+}
+
+// lineNumber returns the 1-based line containing offset.
+func lineNumber(src []byte, offset int) int {
+	return bytes.Count(src[:offset], []byte("\n")) + 1
+}
+
+// lastLineNumber returns the 1-based line of the last byte in [start, end).
+func lastLineNumber(src []byte, start, end int) int {
+	if end <= start {
+		return lineNumber(src, start)
+	}
+	return lineNumber(src, end-1)
+}
@@ -0,0 +1,45 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDescribeSpec_RangesLinesTagsAndReferences(t *testing.T) {
+	content := "# Auth\n\n### Requirement: Login\n\nUsers log in #security per [RFC](https://example.com/rfc) and `#notatag`.\n\n#### Scenario: Ok\n\n- THEN ok #happy\n\n### Requirement: Logout\n\nBye.\n"
+	spec, err := NewSpecParser().Parse([]byte(content))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	spec.Domain = "auth"
+
+	info := DescribeSpec(spec)
+	if info.Size != len(content) || len(info.Requirements) != 2 {
+		t.Fatalf("unexpected info: %+v", info)
+	}
+
+	login := info.Requirements[0]
+	if got := content[login.Start:login.End]; got[:len("### Requirement: Login")] != "### Requirement: Login" {
+		t.Fatalf("login range does not start at heading: %q", got)
+	}
+	if login.StartLine != 3 || login.EndLine != 10 {
+		t.Fatalf("unexpected login lines %d-%d", login.StartLine, login.EndLine)
+	}
+	if !reflect.DeepEqual(login.Tags, []string{"happy", "security"}) {
+		t.Fatalf("unexpected tags: %v", login.Tags)
+	}
+	if !reflect.DeepEqual(login.References, []string{"https://example.com/rfc"}) {
+		t.Fatalf("unexpected references: %v", login.References)
+	}
+	if len(login.Scenarios) != 1 || content[login.Scenarios[0].Start:login.Scenarios[0].End] != "#### Scenario: Ok\n\n- THEN ok #happy\n\n" {
+		t.Fatalf("unexpected scenario range: %+v", login.Scenarios)
+	}
+
+	logout := info.Requirements[1]
+	if logout.Start != login.End || logout.End != len(content) || logout.StartLine != 11 || logout.EndLine != 13 {
+		t.Fatalf("unexpected logout range: %+v", logout)
+	}
+	if logout.Hash == "" || logout.Hash == login.Hash {
+		t.Fatalf("expected distinct content hashes")
+	}
+}
@@ -2,43 +2,61 @@ package core
 
 import (
 	"fmt"
-	"io/ioutil"
-	"os"
-	"path/filepath"
+	"sync"
 
 	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
 	"github.com/teamwerx/teamwerx/internal/model"
-	"github.com/teamwerx/teamwerx/internal/utils"
+	"github.com/teamwerx/teamwerx/internal/storage"
 )
 
 // specManager implements the SpecManager interface.
 type specManager struct {
 	baseDir    string
+	layout     Layout
 	parser     *SpecParser
 	serializer *SpecSerializer
+	store      storage.Storage
+	secrets    *SecretScanner
+
+	mu    sync.Mutex
+	index map[string]*requirementIndex // by spec path; see ReadRequirement
 }
 
-// NewSpecManager creates a new SpecManager.
+// NewSpecManager creates a new SpecManager using the default layout.
 func NewSpecManager(baseDir string) SpecManager {
+	return NewSpecManagerWithLayout(baseDir, DefaultLayout())
+}
+
+// NewSpecManagerWithLayout creates a new SpecManager storing specs at layout.SpecFile.
+func NewSpecManagerWithLayout(baseDir string, layout Layout) SpecManager {
+	return newSpecManager(baseDir, layout, storage.OS(), nil, DefaultSpecFormat())
+}
+
+func newSpecManager(baseDir string, layout Layout, store storage.Storage, secrets *SecretScanner, format SpecFormat) SpecManager {
 	return &specManager{
 		baseDir:    baseDir,
-		parser:     NewSpecParser(),
+		layout:     layout.WithDefaults(),
+		parser:     NewSpecParserWithFormat(format),
 		serializer: NewSpecSerializer(),
+		store:      store,
+		secrets:    secrets,
 	}
 }
 
-// ReadSpec reads a spec file for a given domain.
+// ReadSpec reads a spec file for a given domain. "!include shared/<name>"
+// lines are expanded to the library's requirements (see SharedSpecDir), so
+// Content, offsets and the fingerprint all describe the expanded spec.
 func (m *specManager) ReadSpec(domain string) (*model.Spec, error) {
-	path := filepath.Join(m.baseDir, domain, "spec.md")
-	content, err := ioutil.ReadFile(path)
+	path := m.layout.SpecPath(m.baseDir, domain)
+	content, err := m.store.ReadFile(path)
 	if err != nil {
-		if os.IsNotExist(err) {
+		if _, ok := err.(*custom_errors.ErrNotFound); ok {
 			return nil, custom_errors.NewErrNotFound("spec", domain)
 		}
 		return nil, err
 	}
 
-	spec, err := m.parser.Parse(content)
+	spec, err := m.parseExpanded(content)
 	if err != nil {
 		return nil, err
 	}
@@ -47,8 +65,10 @@ func (m *specManager) ReadSpec(domain string) (*model.Spec, error) {
 
 	// Compute and set a fingerprint for the spec content for conflict detection.
 	// GenerateFingerprint trims surrounding whitespace before hashing so incidental
-	// formatting differences do not change the fingerprint.
-	spec.Fingerprint = utils.GenerateFingerprint(spec.Content)
+	// formatting differences do not change the fingerprint, and requirement notes
+	// are left out so editorial rationale never causes divergence.
+	setFingerprints(spec)
+	m.indexSpec(path, content, spec)
 
 	return spec, nil
 }
@@ -56,18 +76,25 @@ func (m *specManager) ReadSpec(domain string) (*model.Spec, error) {
 // WriteSpec writes a spec to a file.
 // If `spec.Content` is present (non-empty), prefer writing it directly as the file's content.
 // Otherwise fall back to the serializer to construct the content.
+// Expansions of spec.Includes are written back as their "!include" lines.
+// On success spec is updated in place to what a ReadSpec would now return,
+// fingerprints included, so callers need not read it back.
 func (m *specManager) WriteSpec(spec *model.Spec) error {
 	if spec == nil {
 		return fmt.Errorf("spec cannot be nil")
 	}
 
-	path := filepath.Join(m.baseDir, spec.Domain, "spec.md")
+	path := m.layout.SpecPath(m.baseDir, spec.Domain)
 
 	var content []byte
 	var err error
 
 	if spec.Content != "" {
-		content = []byte(spec.Content)
+		raw, err := m.collapseIncludes(spec.Domain, spec.Content, spec.Includes)
+		if err != nil {
+			return err
+		}
+		content = []byte(raw)
 	} else {
 		content, err = m.serializer.Serialize(spec)
 		if err != nil {
@@ -75,49 +102,57 @@ func (m *specManager) WriteSpec(spec *model.Spec) error {
 		}
 	}
 
-	// Ensure the target directory exists before writing the file.
-	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
-		return err
+	if m.secrets != nil {
+		checked, err := m.secrets.Check("spec "+spec.Domain, string(content))
+		if err != nil {
+			return err
+		}
+		content = []byte(checked)
 	}
 
-	if err := ioutil.WriteFile(path, content, 0644); err != nil {
+	// WriteFile creates the target directory if needed.
+	if err := m.store.WriteFile(path, content, 0o644); err != nil {
 		return err
 	}
 
-	// Re-parse to refresh AST and normalize content/requirements; update fingerprint.
-	if parsed, perr := m.parser.Parse(content); perr == nil && spec != nil {
-		parsed.Domain = spec.Domain
-		// Update the original spec in memory to reflect canonical state post-write.
-		spec.Content = parsed.Content
-		spec.Requirements = parsed.Requirements
-		spec.AST = parsed.AST
-	}
-	// Always refresh fingerprint based on final content.
-	if spec != nil {
-		spec.Fingerprint = utils.GenerateFingerprint(string(content))
+	// Re-parse to refresh AST and normalize content/requirements; update fingerprints.
+	parsed, perr := m.parseExpanded(content)
+	if perr != nil {
+		spec.Fingerprint = SpecFingerprint(&model.Spec{Content: string(content)})
+		return nil
 	}
-
+	spec.Content = parsed.Content
+	spec.FrontMatter = parsed.FrontMatter
+	spec.Meta = parsed.Meta
+	spec.Requirements = parsed.Requirements
+	spec.Includes = parsed.Includes
+	spec.Blocks = parsed.Blocks
+	spec.AST = parsed.AST
+	setFingerprints(spec)
+	m.indexSpec(path, content, spec)
 	return nil
 }
 
-// ListSpecs lists all available specs.
+// ListSpecs lists all available specs in natural domain order.
 func (m *specManager) ListSpecs() ([]*model.Spec, error) {
-	files, err := ioutil.ReadDir(m.baseDir)
+	domains, err := findTemplateMatches(m.store, m.baseDir, m.layout.SpecFile, domainPlaceholder)
 	if err != nil {
 		return nil, err
 	}
 
 	var specs []*model.Spec
-	for _, file := range files {
-		if file.IsDir() {
-			spec, err := m.ReadSpec(file.Name())
-			if err != nil {
-				// Ignore specs that can't be read
-				continue
-			}
-			specs = append(specs, spec)
+	for _, domain := range domains {
+		spec, err := m.ReadSpec(domain)
+		if err != nil {
+			// Ignore specs that can't be read
+			continue
 		}
+		specs = append(specs, spec)
 	}
 
+	// Directory order is byte-wise; return specs in natural domain order instead.
+	if err := SortSpecs(specs, "", SortAsc); err != nil {
+		return nil, err
+	}
 	return specs, nil
 }
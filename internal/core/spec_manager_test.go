@@ -9,6 +9,7 @@ import (
 
 	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
 	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
 )
 
 func TestSpecManager_ReadSpec_Success(t *testing.T) {
@@ -155,3 +156,42 @@ func TestSpecManager_ListSpecs_Empty(t *testing.T) {
 		t.Errorf("Expected 0 specs, got %d", len(specs))
 	}
 }
+
+func TestSpecManager_WriteSpec_RefreshesFingerprints(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := &model.Spec{Domain: "auth", Content: "# Auth\n\n### Requirement: Login\n\nUsers log in.\n"}
+	if err := app.SpecManager.WriteSpec(spec); err != nil {
+		t.Fatal(err)
+	}
+	if spec.Fingerprint == "" || len(spec.Requirements) != 1 || spec.Requirements[0].Fingerprint == "" {
+		t.Fatalf("expected WriteSpec to fingerprint the spec in place, got %+v", spec)
+	}
+	read, err := app.SpecManager.ReadSpec("auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Fingerprint != spec.Fingerprint || read.Requirements[0].Fingerprint != spec.Requirements[0].Fingerprint {
+		t.Fatalf("written fingerprints differ from a re-read: %q vs %q", spec.Fingerprint, read.Fingerprint)
+	}
+
+	ch := &model.Change{ID: "CH-001", Status: "draft", SpecDeltas: []model.SpecDelta{{
+		Domain:     "auth",
+		Operations: []model.DeltaOperation{{Type: "ADDED", Requirement: model.Requirement{ID: "logout", Title: "Logout", Content: "### Requirement: Logout\n\nUsers log out.\n"}}},
+	}}}
+	if err := app.ChangeManager.Save(ch); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.ChangeManager.ApplyChange(ch); err != nil {
+		t.Fatalf("ApplyChange: %v", err)
+	}
+	read, err = app.SpecManager.ReadSpec("auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ch.SpecDeltas[0].AppliedFingerprint; got == "" || got != read.Fingerprint {
+		t.Fatalf("applied fingerprint = %q, want %q", got, read.Fingerprint)
+	}
+}
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"strings"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// BranchDelta returns the requirement-level delta that turns current into
+// incoming, e.g. a spec read from another git branch: incoming's new
+// requirements are ADDED after the requirement preceding them there, and
+// requirements whose text differs are MODIFIED with incoming's block.
+// Requirements only in current are REMOVED when remove is set; without a
+// merge base they may just as well be new on this side, so they are kept
+// by default. The delta is based on current's fingerprint.
+func BranchDelta(current, incoming *model.Spec, remove bool) model.SpecDelta {
+	delta := model.SpecDelta{Domain: current.Domain, BaseFingerprint: current.Fingerprint}
+	for _, c := range CompareSpecs(current, incoming) {
+		r := findSpecRequirement(incoming, c.ID)
+		switch c.Status {
+		case CompareLeftOnly:
+			if remove {
+				l := findSpecRequirement(current, c.ID)
+				delta.Operations = append(delta.Operations, model.DeltaOperation{
+					Type:        "REMOVED",
+					Requirement: model.Requirement{ID: l.ID, Title: l.Title},
+				})
+			}
+		case CompareRightOnly, CompareDiffers:
+			op := model.DeltaOperation{
+				Type:        "MODIFIED",
+				Requirement: model.Requirement{ID: r.ID, Title: r.Title, Content: strings.TrimSpace(incoming.Content[r.Start:r.End]) + "\n\n"},
+			}
+			if c.Status == CompareRightOnly {
+				op.Type = "ADDED"
+				op.After = precedingRequirement(incoming, r.ID)
+			}
+			delta.Operations = append(delta.Operations, op)
+		}
+	}
+	return delta
+}
+
+// findSpecRequirement returns the requirement of spec with id, or nil.
+func findSpecRequirement(spec *model.Spec, id string) *model.Requirement {
+	for i := range spec.Requirements {
+		if spec.Requirements[i].ID == id {
+			return &spec.Requirements[i]
+		}
+	}
+	return nil
+}
+
+// precedingRequirement returns the ID of the requirement before id in spec,
+// or "" when id comes first.
+func precedingRequirement(spec *model.Spec, id string) string {
+	prev := ""
+	for _, r := range spec.Requirements {
+		if r.ID == id {
+			return prev
+		}
+		prev = r.ID
+	}
+	return ""
+}
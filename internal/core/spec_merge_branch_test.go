@@ -0,0 +1,56 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+func TestBranchDelta_MergesBranchRequirements(t *testing.T) {
+	baseDir := createTempDir(t)
+	specs := NewSpecManager(baseDir)
+	if err := specs.WriteSpec(&model.Spec{Domain: "auth", Content: "# Auth\n\n### Requirement: Login\n\nUsers log in.\n\n### Requirement: Audit\n\nEvents are logged.\n"}); err != nil {
+		t.Fatalf("WriteSpec: %v", err)
+	}
+	current, err := specs.ReadSpec("auth")
+	if err != nil {
+		t.Fatalf("ReadSpec: %v", err)
+	}
+	incoming, _ := NewSpecParser().Parse([]byte("# Auth\n\n### Requirement: Login\n\nUsers log in with 2FA.\n\n### Requirement: Logout\n\nUsers log out.\n"))
+
+	delta := BranchDelta(current, incoming, false)
+	if delta.BaseFingerprint != current.Fingerprint {
+		t.Fatalf("base fingerprint = %q, want %q", delta.BaseFingerprint, current.Fingerprint)
+	}
+	var ops []string
+	for _, op := range delta.Operations {
+		ops = append(ops, op.Type+" "+op.Requirement.ID+" after "+op.After)
+	}
+	want := []string{"MODIFIED login after ", "ADDED logout after login"}
+	if len(ops) != len(want) || ops[0] != want[0] || ops[1] != want[1] {
+		t.Fatalf("operations = %q, want %q", ops, want)
+	}
+
+	if err := NewSpecMerger(specs).Merge(&delta); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	merged, err := specs.ReadSpec("auth")
+	if err != nil {
+		t.Fatalf("ReadSpec: %v", err)
+	}
+	var ids []string
+	for _, r := range merged.Requirements {
+		ids = append(ids, r.ID)
+	}
+	if len(ids) != 3 || ids[0] != "login" || ids[1] != "logout" || ids[2] != "audit" {
+		t.Fatalf("merged requirements = %q", ids)
+	}
+	if got := BranchDelta(merged, incoming, false); len(got.Operations) != 0 {
+		t.Fatalf("merged spec still differs: %+v", got.Operations)
+	}
+
+	removal := BranchDelta(merged, incoming, true)
+	if len(removal.Operations) != 1 || removal.Operations[0].Type != "REMOVED" || removal.Operations[0].Requirement.ID != "audit" {
+		t.Fatalf("operations with remove = %+v", removal.Operations)
+	}
+}
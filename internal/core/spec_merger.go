@@ -1,40 +1,58 @@
 package core
 
 import (
-	"bytes"
 	"fmt"
 	"strings"
 
 	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
 	"github.com/teamwerx/teamwerx/internal/model"
 	"github.com/teamwerx/teamwerx/internal/utils"
-	"github.com/yuin/goldmark"
-	"github.com/yuin/goldmark/ast"
-	"github.com/yuin/goldmark/text"
 )
 
-// specMerger implements SpecMerger by using the Markdown AST to compute exact
-// byte ranges for requirement blocks and then applying text-range edits.
-// This gives us robust detection of requirement boundaries while keeping the
-// edit model (replace/delete/append) simple and deterministic.
+// specMerger implements SpecMerger on top of the ordered spec document model
+// produced by SpecParser. Operations add, replace or drop whole requirement
+// blocks, or "#### Scenario:" sub-blocks within one requirement; every other
+// block (preamble, sections) is carried through untouched, so content outside
+// the edited requirements is preserved byte-for-byte.
 type specMerger struct {
 	specManager SpecManager
-	md          goldmark.Markdown
+	parser      *SpecParser
 }
 
-// NewSpecMerger creates a new AST-assisted SpecMerger.
+// NewSpecMerger creates a new block-based SpecMerger.
 func NewSpecMerger(specManager SpecManager) SpecMerger {
 	return &specMerger{
 		specManager: specManager,
-		md:          goldmark.New(),
+		parser:      specParser(specManager),
 	}
 }
 
+// specParser returns the parser specs reads with, so requirement headings
+// are found the same way; NewSpecParser for other implementations.
+func specParser(specs SpecManager) *SpecParser {
+	if m, ok := specs.(*specManager); ok {
+		return m.parser
+	}
+	return NewSpecParser()
+}
+
 // Merge applies the given SpecDelta to the domain's spec. If the delta carries
 // a BaseFingerprint it will be compared against the current spec fingerprint
 // and an ErrDiverged will be returned when they differ (preventing accidental
 // overwrites). The caller may refresh fingerprints or choose a force path as
 // appropriate at a higher level.
+//
+// Merging is idempotent: an ADDED requirement (or ADD_SCENARIO scenario) that
+// already exists with the same text is skipped, a MODIFIED one that already
+// matches is a no-op, and a delta whose every operation is already reflected
+// in the spec succeeds without writing even if the spec has moved past its
+// base fingerprint, so re-applying an applied change changes nothing. Adding
+// a requirement or scenario whose ID exists with different text is an
+// ErrConflict rather than a duplicate.
+//
+// APPEND and PATCH edit text inside a requirement and are checked against
+// its current text instead, so a delta made only of them is merged even when
+// the spec has moved past its base fingerprint (see anchoredDelta).
 func (m *specMerger) Merge(delta *model.SpecDelta) error {
 	if delta == nil {
 		return fmt.Errorf("nil delta")
@@ -53,84 +71,263 @@ func (m *specMerger) Merge(delta *model.SpecDelta) error {
 		}
 	}
 
+	parsed, err := m.parser.Parse([]byte(spec.Content))
+	if err != nil {
+		return err
+	}
+	orig := delta
+	delta = resolveDeltaAliases(spec, delta)
+	blocks, opErr := m.applyOperations(parsed.Blocks, delta)
+	unchanged := opErr == nil && joinSpecBlocks(blocks) == spec.Content && spec.Fingerprint != ""
+
 	// Conflict detection via base fingerprint (if provided).
 	if delta.BaseFingerprint != "" {
-		// If current fingerprint differs from the base fingerprint, refuse to merge.
+		// If current fingerprint differs from the base fingerprint, refuse to merge
+		// unless the delta is already fully reflected in the spec.
 		// Note: spec.Fingerprint may be empty for empty/nonexistent specs.
-		if spec.Fingerprint != "" && spec.Fingerprint != delta.BaseFingerprint {
+		if spec.Fingerprint != "" && spec.Fingerprint != delta.BaseFingerprint && !(unchanged && len(delta.Operations) > 0) && !anchoredDelta(*delta) {
 			return custom_errors.NewErrDiverged(delta.Domain, delta.BaseFingerprint, spec.Fingerprint, "current spec fingerprint does not match delta base fingerprint")
 		}
 	}
+	if opErr != nil {
+		return opErr
+	}
+	if unchanged {
+		orig.AppliedFingerprint = spec.Fingerprint
+		return nil
+	}
 
-	// Use AST to compute byte ranges for requirement blocks.
-	content := spec.Content
-	src := []byte(content)
-	// Parse the AST for the current content once; when content changes due to edits,
-	// we will reparse as needed using updated src.
-	doc := m.md.Parser().Parse(text.NewReader(src))
+	// Persist the updated content; WriteSpec re-parses and refreshes the fingerprint.
+	spec.Content = joinSpecBlocks(blocks)
+	if err := m.specManager.WriteSpec(spec); err != nil {
+		return err
+	}
+	orig.AppliedFingerprint = spec.Fingerprint
+	return nil
+}
 
+// applyOperations applies the delta's operations, in order, to a copy of
+// blocks and returns the result.
+func (m *specMerger) applyOperations(blocks []model.SpecBlock, delta *model.SpecDelta) ([]model.SpecBlock, error) {
+	blocks = append([]model.SpecBlock{}, blocks...)
+	format := m.parser.Format()
 	for _, op := range delta.Operations {
 		switch op.Type {
 		case "ADDED":
-			// Append the requirement block to the end of the document.
-			block := buildRequirementText(op.Requirement)
-			// Ensure tidy separation.
-			if len(content) > 0 && !strings.HasSuffix(content, "\n") {
-				content += "\n"
+			// Place the requirement block as its hints ask, unless it is
+			// already there.
+			if idx := findRequirementBlock(blocks, op.Requirement.ID); idx != -1 {
+				if sameBlockText(blocks[idx].Content, buildRequirementText(op.Requirement, format)) {
+					continue
+				}
+				return nil, custom_errors.NewErrConflict(fmt.Sprintf("cannot add requirement %s to %s: it already exists with different content", op.Requirement.ID, delta.Domain))
 			}
-			content += block
+			blocks = placeRequirementBlock(blocks, op, format)
 
 		case "REMOVED":
-			// Find range via AST on current src.
-			start, end := findRequirementRangeAST(doc, src, op.Requirement.ID)
-			if start == -1 {
+			idx := findRequirementBlock(blocks, op.Requirement.ID)
+			if idx == -1 {
 				// Nothing to remove.
 				continue
 			}
-			content = content[:start] + content[end:]
-			// Re-parse to update doc/src for subsequent operations.
-			src = []byte(content)
-			doc = m.md.Parser().Parse(text.NewReader(src))
+			blocks = append(blocks[:idx], blocks[idx+1:]...)
 
 		case "MODIFIED":
 			// Replace the existing requirement block, or add if not present.
-			start, end := findRequirementRangeAST(doc, src, op.Requirement.ID)
-			block := buildRequirementText(op.Requirement)
-			if start == -1 {
-				// Add as new block.
-				if len(content) > 0 && !strings.HasSuffix(content, "\n") {
-					content += "\n"
-				}
-				content += block
-			} else {
-				content = content[:start] + block + content[end:]
+			idx := findRequirementBlock(blocks, op.Requirement.ID)
+			if idx == -1 {
+				blocks = placeRequirementBlock(blocks, op, format)
+				continue
+			}
+			if sameBlockText(blocks[idx].Content, buildRequirementText(op.Requirement, format)) {
+				continue
+			}
+			blocks[idx] = requirementBlock(op.Requirement, format)
+
+		case "ADD_SCENARIO", "MODIFY_SCENARIO", "REMOVE_SCENARIO":
+			if err := m.mergeScenario(blocks, op); err != nil {
+				return nil, err
 			}
-			// Re-parse after modification for subsequent operations.
-			src = []byte(content)
-			doc = m.md.Parser().Parse(text.NewReader(src))
+
+		case "APPEND", "PATCH":
+			idx := findRequirementBlock(blocks, op.Requirement.ID)
+			if idx == -1 {
+				return nil, custom_errors.NewErrNotFound("requirement", op.Requirement.ID)
+			}
+			content, err := editRequirementText(m.parser, blocks[idx].Content, op)
+			if err != nil {
+				return nil, err
+			}
+			blocks[idx].Content = content
 
 		default:
-			return custom_errors.NewErrConflict(fmt.Sprintf("unknown operation type: %s", op.Type))
+			return nil, custom_errors.NewErrValidation("type", fmt.Sprintf("unknown operation type %q", op.Type))
 		}
 	}
+	return blocks, nil
+}
 
-	// Persist the updated content.
-	spec.Content = content
-	// Recompute fingerprint (SpecManager.WriteSpec will persist content; fingerprinting
-	// is typically updated on Read; we keep WriteSpec responsibility minimal here).
-	if err := m.specManager.WriteSpec(spec); err != nil {
-		return err
+// anchoredDelta reports whether every pending operation of d is an APPEND
+// or PATCH, whose anchors are checked against the current requirement text.
+func anchoredDelta(d model.SpecDelta) bool {
+	pending := false
+	for _, op := range d.Operations {
+		if op.AppliedAt != nil {
+			continue
+		}
+		if !anchoredOperation(op) {
+			return false
+		}
+		pending = true
+	}
+	return pending
+}
+
+// anchoredOperation reports whether op is an APPEND or PATCH.
+func anchoredOperation(op model.DeltaOperation) bool {
+	return op.Type == "APPEND" || op.Type == "PATCH"
+}
+
+// editRequirementText applies an APPEND or PATCH to a requirement block.
+//
+// APPEND adds op.Text as a new paragraph at the end of the requirement's
+// body, ahead of its scenarios; text already ending the body, or standing as a
+// paragraph of its own, is not added twice.
+// PATCH replaces op.Find, which must occur exactly once, with op.Replace. A
+// PATCH whose Replace is already in place is a no-op; a missing or ambiguous
+// Find is an ErrConflict, as the requirement changed since the op was written.
+func editRequirementText(p *SpecParser, block string, op model.DeltaOperation) (string, error) {
+	id := op.Requirement.ID
+	if op.Type == "APPEND" {
+		text := strings.TrimSpace(op.Text)
+		if text == "" {
+			return "", custom_errors.NewErrValidation("text", fmt.Sprintf("APPEND on requirement %s has no text", id))
+		}
+		subs := p.splitRequirementBlock(block)
+		lead := subs[0].Content
+		if hasTrailingText(lead, text) {
+			return block, nil
+		}
+		body := strings.TrimRight(lead, "\n")
+		tail := lead[len(body):]
+		if tail == "" {
+			tail = "\n"
+		}
+		subs[0].Content = body + "\n\n" + text + tail
+		var buf strings.Builder
+		for _, sub := range subs {
+			buf.WriteString(sub.Content)
+		}
+		return buf.String(), nil
+	}
+
+	if op.Find == "" {
+		return "", custom_errors.NewErrValidation("find", fmt.Sprintf("PATCH on requirement %s has no find text", id))
+	}
+	n := strings.Count(block, op.Find)
+	if op.Replace != "" && strings.Contains(block, op.Replace) && n == strings.Count(op.Replace, op.Find) {
+		return block, nil
+	}
+	switch n {
+	case 1:
+		return strings.Replace(block, op.Find, op.Replace, 1), nil
+	case 0:
+		return "", custom_errors.NewErrConflict(fmt.Sprintf("cannot patch requirement %s: %q is no longer in its text", id, op.Find))
+	default:
+		return "", custom_errors.NewErrConflict(fmt.Sprintf("cannot patch requirement %s: %q occurs %d times; include more surrounding text", id, op.Find, n))
+	}
+}
+
+// hasTrailingText reports whether body already holds text as a whole
+// paragraph or as its final lines, the places an earlier APPEND of text
+// would have left it. Text merely contained in a longer line does not count.
+func hasTrailingText(body, text string) bool {
+	body = strings.TrimSpace(body)
+	if body == text || strings.HasSuffix(body, "\n"+text) {
+		return true
+	}
+	for _, para := range strings.Split(body, "\n\n") {
+		if strings.TrimSpace(para) == text {
+			return true
+		}
+	}
+	return false
+}
+
+// sameBlockText reports whether two requirement or scenario texts match,
+// ignoring surrounding blank lines.
+func sameBlockText(a, b string) bool {
+	return strings.TrimSpace(a) == strings.TrimSpace(b)
+}
+
+// mergeScenario applies a scenario operation to the requirement block named by
+// op.Requirement.ID, editing only that block. The requirement must exist.
+// Like requirements, MODIFY_SCENARIO adds a missing scenario and
+// REMOVE_SCENARIO of a missing scenario is a no-op.
+func (m *specMerger) mergeScenario(blocks []model.SpecBlock, op model.DeltaOperation) error {
+	if op.Scenario == nil {
+		return custom_errors.NewErrValidation("scenario", fmt.Sprintf("%s on requirement %s has no scenario", op.Type, op.Requirement.ID))
+	}
+	idx := findRequirementBlock(blocks, op.Requirement.ID)
+	if idx == -1 {
+		return custom_errors.NewErrNotFound("requirement", op.Requirement.ID)
+	}
+
+	id := scenarioID(op.Scenario)
+	subs := m.parser.splitRequirementBlock(blocks[idx].Content)
+	pos := -1
+	for i, sub := range subs {
+		if sub.ScenarioID == id {
+			pos = i
+			break
+		}
+	}
+
+	switch {
+	case op.Type == "REMOVE_SCENARIO":
+		if pos == -1 {
+			return nil
+		}
+		subs = append(subs[:pos], subs[pos+1:]...)
+	case pos != -1 && sameBlockText(subs[pos].Content, buildScenarioText(*op.Scenario)):
+		return nil
+	case op.Type == "ADD_SCENARIO" && pos != -1:
+		return custom_errors.NewErrConflict(fmt.Sprintf("cannot add scenario %s to requirement %s: it already exists with different content", id, op.Requirement.ID))
+	case op.Type == "MODIFY_SCENARIO" && pos != -1:
+		subs[pos].Content = buildScenarioText(*op.Scenario)
+	default:
+		// ADD_SCENARIO, or MODIFY_SCENARIO of a scenario that does not exist yet.
+		if n := len(subs); n > 0 && !strings.HasSuffix(subs[n-1].Content, "\n\n") {
+			if !strings.HasSuffix(subs[n-1].Content, "\n") {
+				subs[n-1].Content += "\n"
+			}
+			subs[n-1].Content += "\n"
+		}
+		subs = append(subs, requirementSubBlock{ScenarioID: id, Content: buildScenarioText(*op.Scenario)})
 	}
 
+	var buf strings.Builder
+	for _, sub := range subs {
+		buf.WriteString(sub.Content)
+	}
+	blocks[idx].Content = buf.String()
 	return nil
 }
 
-// buildRequirementText returns a normalized markdown block for a requirement.
-// If the Requirement.Content is non-empty, prefer it (ensuring trailing blank lines).
-// Otherwise construct a level-3 "Requirement:" heading block.
-func buildRequirementText(r model.Requirement) string {
-	if strings.TrimSpace(r.Content) != "" {
-		c := r.Content
+// scenarioID returns the scenario's ID, deriving it from the title when unset.
+func scenarioID(s *model.Scenario) string {
+	if s.ID != "" {
+		return s.ID
+	}
+	return utils.ToKebabCase(strings.TrimSpace(s.Title))
+}
+
+// buildScenarioText returns the markdown for a scenario sub-block. A non-empty
+// Content is used as-is (it must include the heading); otherwise a
+// "#### Scenario:" heading is built from the title with one bullet per step.
+func buildScenarioText(s model.Scenario) string {
+	if strings.TrimSpace(s.Content) != "" {
+		c := s.Content
 		if !strings.HasSuffix(c, "\n") {
 			c += "\n"
 		}
@@ -139,93 +336,115 @@ func buildRequirementText(r model.Requirement) string {
 		}
 		return c
 	}
-	title := strings.TrimSpace(r.Title)
+	title := strings.TrimSpace(s.Title)
 	if title == "" {
-		title = r.ID
+		title = s.ID
+	}
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "#### Scenario: %s\n\n", title)
+	for _, step := range s.Steps {
+		fmt.Fprintf(&buf, "- %s\n", step)
 	}
-	return fmt.Sprintf("### Requirement: %s\n\n\n", title)
+	if len(s.Steps) > 0 {
+		buf.WriteString("\n")
+	}
+	return buf.String()
 }
 
-// findRequirementRangeAST locates the byte start/end offsets for a requirement
-// block using the parsed AST. It returns (-1, -1) if not found.
-//
-// Approach:
-//   - Walk top-level children of the document looking for level-3 headings.
-//   - For a heading whose text begins with "Requirement:", extract the title,
-//     kebab-case it and compare with the provided id.
-//   - If matched, the start offset is the heading's first line Start.
-//   - The end offset is computed as the Start of the next sibling heading that
-//     has level <= 3 (i.e., next requirement or higher-level section), or EOF.
-func findRequirementRangeAST(doc ast.Node, src []byte, id string) (int, int) {
-	const prefix = "Requirement:"
-
-	// Iterate over top-level children; use doc.FirstChild()/NextSibling() to preserve original ordering.
-	for node := doc.FirstChild(); node != nil; node = node.NextSibling() {
-		// We're looking for headings at level 3.
-		h, ok := node.(*ast.Heading)
-		if !ok || h.Level != 3 {
-			continue
+// findRequirementBlock returns the index of the requirement block with the given ID, or -1.
+func findRequirementBlock(blocks []model.SpecBlock, id string) int {
+	for i, b := range blocks {
+		if b.Kind == model.SpecBlockRequirement && b.RequirementID == id {
+			return i
 		}
+	}
+	return -1
+}
 
-		// Extract heading text bytes.
-		headingText := bytes.TrimSpace(h.Text(src))
-		if !bytes.HasPrefix(headingText, []byte(prefix)) {
-			continue
-		}
+// requirementBlock builds a requirement block from a delta requirement,
+// headed as format says when it has no content.
+func requirementBlock(r model.Requirement, format SpecFormat) model.SpecBlock {
+	title := strings.TrimSpace(r.Title)
+	if title == "" {
+		title = r.ID
+	}
+	return model.SpecBlock{
+		Kind:          model.SpecBlockRequirement,
+		Level:         format.WithDefaults().RequirementLevel,
+		Title:         title,
+		RequirementID: r.ID,
+		Content:       buildRequirementText(r, format),
+	}
+}
 
-		// Extract title part after "Requirement:".
-		titleBytes := bytes.TrimSpace(bytes.TrimPrefix(headingText, []byte(prefix)))
-		title := string(titleBytes)
-		if utils.ToKebabCase(title) != id {
-			continue
-		}
+// appendRequirementBlock appends a requirement block, making sure the preceding
+// block ends with a newline so the new heading starts on its own line.
+func appendRequirementBlock(blocks []model.SpecBlock, r model.Requirement, format SpecFormat) []model.SpecBlock {
+	if n := len(blocks); n > 0 && blocks[n-1].Content != "" && !strings.HasSuffix(blocks[n-1].Content, "\n") {
+		blocks[n-1].Content += "\n"
+	}
+	return append(blocks, requirementBlock(r, format))
+}
 
-		// Found matching heading. Compute start offset from the first segment of heading.Lines()
-		// (Lines represents the source segments that make up the node). The segment start
-		// may point to the first character of the heading text (after the "### " marker).
-		// To ensure we include the full heading marker (e.g., "### "), search backwards
-		// to the beginning of the line and use that as the block start.
-		if h.Lines().Len() == 0 {
-			// Defensive: if no lines info, fall back to -1.
-			return -1, -1
-		}
-		firstSeg := h.Lines().At(0)
-		// Compute the start of the heading line by finding the last newline before firstSeg.Start.
-		lineStart := 0
-		if firstSeg.Start > 0 {
-			if idx := bytes.LastIndex(src[:firstSeg.Start], []byte("\n")); idx >= 0 {
-				lineStart = idx + 1
-			} else {
-				lineStart = 0
-			}
+// placeRequirementBlock inserts the requirement op adds where its placement
+// hints ask: right after requirement op.After, or else at the end of the
+// section headed op.Section (before the next heading of the same or a higher
+// level). The hints are soft; when neither names a block in the spec, the
+// requirement is appended to the end of the document.
+func placeRequirementBlock(blocks []model.SpecBlock, op model.DeltaOperation, format SpecFormat) []model.SpecBlock {
+	at := -1
+	if after := strings.TrimSpace(op.After); after != "" {
+		if idx := findRequirementBlock(blocks, after); idx != -1 {
+			at = idx + 1
 		}
-		start := lineStart
-
-		// Find the end offset: look for next sibling that is a heading with level <= 3 and take
-		// the beginning of that heading's line (so we remove the full trailing heading marker).
-		end := len(src) // default: EOF
-		for sib := h.NextSibling(); sib != nil; sib = sib.NextSibling() {
-			if nh, ok := sib.(*ast.Heading); ok {
-				// any heading of level 1..3 marks the end of the current requirement block
-				if nh.Lines().Len() > 0 && nh.Level <= 3 {
-					endSeg := nh.Lines().At(0)
-					// Compute the start of that heading's line the same way as above.
-					nextLineStart := 0
-					if endSeg.Start > 0 {
-						if idx := bytes.LastIndex(src[:endSeg.Start], []byte("\n")); idx >= 0 {
-							nextLineStart = idx + 1
-						} else {
-							nextLineStart = 0
-						}
-					}
-					end = nextLineStart
-					break
-				}
+	}
+	if section := strings.TrimSpace(strings.TrimLeft(op.Section, "# ")); at == -1 && section != "" {
+		for i, b := range blocks {
+			if b.Kind != model.SpecBlockSection || !strings.EqualFold(b.Title, section) {
+				continue
 			}
+			at = i + 1
+			for at < len(blocks) && blocks[at].Level > b.Level {
+				at++
+			}
+			break
 		}
+	}
+	if at == -1 || at == len(blocks) {
+		return appendRequirementBlock(blocks, op.Requirement, format)
+	}
 
-		return start, end
+	// The block before the insertion point keeps its text; give it a blank
+	// line so the new heading is set apart. The new block ends with one.
+	prev := &blocks[at-1]
+	if !strings.HasSuffix(prev.Content, "\n") {
+		prev.Content += "\n"
+	}
+	if !strings.HasSuffix(prev.Content, "\n\n") {
+		prev.Content += "\n"
 	}
+	out := append([]model.SpecBlock{}, blocks[:at]...)
+	out = append(out, requirementBlock(op.Requirement, format))
+	return append(out, blocks[at:]...)
+}
 
-	return -1, -1
+// buildRequirementText returns a normalized markdown block for a requirement.
+// If the Requirement.Content is non-empty, prefer it (ensuring trailing blank lines).
+// Otherwise construct a bare requirement heading as format says.
+func buildRequirementText(r model.Requirement, format SpecFormat) string {
+	if strings.TrimSpace(r.Content) != "" {
+		c := r.Content
+		if !strings.HasSuffix(c, "\n") {
+			c += "\n"
+		}
+		if !strings.HasSuffix(c, "\n\n") {
+			c += "\n"
+		}
+		return c
+	}
+	title := strings.TrimSpace(r.Title)
+	if title == "" {
+		title = r.ID
+	}
+	return format.RequirementHeading(title) + "\n\n\n"
 }
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
 	"github.com/teamwerx/teamwerx/internal/model"
 )
 
@@ -217,3 +218,221 @@ func TestSpecMerger_UnknownOperationReturnsError(t *testing.T) {
 		t.Fatalf("expected non-empty error for unknown operation")
 	}
 }
+
+func TestSpecMerger_PreservesNonRequirementSections(t *testing.T) {
+	baseDir := createTempDir(t)
+	domain := "preserve-domain"
+
+	orig := "# Domain Spec\n\n## Overview\n\nKeep  *this*   exactly.\n\n### Requirement: Login\n\nOld body.\n\n## Non-Goals\n\n* trailing section\n"
+	writeSpecFile(t, baseDir, domain, orig)
+
+	merger := NewSpecMerger(NewSpecManager(baseDir))
+	delta := &model.SpecDelta{
+		Domain: domain,
+		Operations: []model.DeltaOperation{
+			{Type: "MODIFIED", Requirement: model.Requirement{ID: "login", Title: "Login", Content: "### Requirement: Login\n\nNew body.\n\n"}},
+		},
+	}
+	if err := merger.Merge(delta); err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+
+	want := "# Domain Spec\n\n## Overview\n\nKeep  *this*   exactly.\n\n### Requirement: Login\n\nNew body.\n\n## Non-Goals\n\n* trailing section\n"
+	if got := readFile(t, filepath.Join(baseDir, domain, "spec.md")); got != want {
+		t.Fatalf("unexpected content:\nwant %q\ngot  %q", want, got)
+	}
+}
+
+func TestSpecMerger_ScenarioOperations(t *testing.T) {
+	baseDir := createTempDir(t)
+	domain := "auth"
+	original := "# Auth\n\n### Requirement: Login\n\nUsers log in.\n\n#### Scenario: Valid password\n\n- GIVEN a user\n- THEN they are logged in\n\n#### Scenario: Locked account\n\n- THEN login is refused\n\n### Requirement: Logout\n\nUsers log out.\n"
+	writeSpecFile(t, baseDir, domain, original)
+
+	specManager := NewSpecManager(baseDir)
+	merger := NewSpecMerger(specManager)
+	login := model.Requirement{ID: "login"}
+	delta := &model.SpecDelta{
+		Domain: domain,
+		Operations: []model.DeltaOperation{
+			{Type: "MODIFY_SCENARIO", Requirement: login, Scenario: &model.Scenario{Title: "Valid password", Steps: []string{"GIVEN a user with 2FA", "THEN a code is requested"}}},
+			{Type: "REMOVE_SCENARIO", Requirement: login, Scenario: &model.Scenario{ID: "locked-account"}},
+			{Type: "ADD_SCENARIO", Requirement: login, Scenario: &model.Scenario{Title: "Expired password", Steps: []string{"THEN a reset is required"}}},
+		},
+	}
+	if err := merger.Merge(delta); err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+
+	content := readFile(t, filepath.Join(baseDir, domain, "spec.md"))
+	want := "# Auth\n\n### Requirement: Login\n\nUsers log in.\n\n#### Scenario: Valid password\n\n- GIVEN a user with 2FA\n- THEN a code is requested\n\n#### Scenario: Expired password\n\n- THEN a reset is required\n\n### Requirement: Logout\n\nUsers log out.\n"
+	if content != want {
+		t.Fatalf("unexpected spec content:\n%q\nwant:\n%q", content, want)
+	}
+
+	spec, err := specManager.ReadSpec(domain)
+	if err != nil {
+		t.Fatalf("ReadSpec failed: %v", err)
+	}
+	var ids []string
+	for _, sc := range spec.Requirements[0].Scenarios {
+		ids = append(ids, sc.ID)
+	}
+	if strings.Join(ids, ",") != "valid-password,expired-password" {
+		t.Fatalf("unexpected scenarios: %v", ids)
+	}
+
+	// Scenario operations require the target requirement to exist.
+	missing := &model.SpecDelta{Domain: domain, Operations: []model.DeltaOperation{
+		{Type: "ADD_SCENARIO", Requirement: model.Requirement{ID: "ghost"}, Scenario: &model.Scenario{Title: "Any"}},
+	}}
+	if err := merger.Merge(missing); err == nil {
+		t.Fatalf("expected error for scenario on missing requirement")
+	}
+}
+
+func TestSpecMerger_ReapplyIsIdempotent(t *testing.T) {
+	baseDir := createTempDir(t)
+	domain := "idem"
+	writeSpecFile(t, baseDir, domain, "# Idem\n\n### Requirement: Login\n\nUsers log in.\n")
+
+	specManager := NewSpecManager(baseDir)
+	merger := NewSpecMerger(specManager)
+	base, err := specManager.ReadSpec(domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	delta := &model.SpecDelta{
+		Domain:          domain,
+		BaseFingerprint: base.Fingerprint,
+		Operations: []model.DeltaOperation{
+			{Type: "ADDED", Requirement: model.Requirement{ID: "logout", Title: "Logout", Content: "### Requirement: Logout\n\nUsers log out.\n"}},
+			{Type: "MODIFIED", Requirement: model.Requirement{ID: "login", Title: "Login", Content: "### Requirement: Login\n\nUsers log in with SSO.\n"}},
+			{Type: "ADD_SCENARIO", Requirement: model.Requirement{ID: "login"}, Scenario: &model.Scenario{Title: "Success", Steps: []string{"it works"}}},
+		},
+	}
+	if err := merger.Merge(delta); err != nil {
+		t.Fatalf("first merge: %v", err)
+	}
+	path := filepath.Join(baseDir, domain, "spec.md")
+	applied := readFile(t, path)
+
+	// Same delta again: the base fingerprint is stale, but everything is
+	// already in the spec, so nothing changes.
+	if err := merger.Merge(delta); err != nil {
+		t.Fatalf("re-merge: %v", err)
+	}
+	if got := readFile(t, path); got != applied {
+		t.Fatalf("re-merge changed the spec:\n%s", got)
+	}
+
+	// Without a base fingerprint each operation is skipped individually.
+	delta.BaseFingerprint = ""
+	if err := merger.Merge(delta); err != nil {
+		t.Fatalf("re-merge without base: %v", err)
+	}
+	if got := readFile(t, path); got != applied || strings.Count(got, "### Requirement: Logout") != 1 {
+		t.Fatalf("re-merge without base changed the spec:\n%s", got)
+	}
+
+	// Adding an existing ID with different text is a conflict, not a duplicate.
+	clash := &model.SpecDelta{Domain: domain, Operations: []model.DeltaOperation{
+		{Type: "ADDED", Requirement: model.Requirement{ID: "logout", Title: "Logout", Content: "### Requirement: Logout\n\nSomething else.\n"}},
+	}}
+	if err := merger.Merge(clash); err == nil {
+		t.Fatal("expected conflicting ADDED to fail")
+	}
+}
+
+func TestSpecMerger_AppendAndPatch(t *testing.T) {
+	baseDir := createTempDir(t)
+	domain := "auth"
+	writeSpecFile(t, baseDir, domain, "# Auth\n\n### Requirement: Login\n\nUsers log in with a password.\n\n#### Scenario: Success\n\n- GIVEN a user\n\n### Requirement: Logout\n\nUsers log out.\n")
+	specManager := NewSpecManager(baseDir)
+	merger := NewSpecMerger(specManager)
+	spec, err := specManager.ReadSpec(domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A concurrent edit to another requirement moves the fingerprint.
+	writeSpecFile(t, baseDir, domain, "# Auth\n\n### Requirement: Login\n\nUsers log in with a password.\n\n#### Scenario: Success\n\n- GIVEN a user\n\n### Requirement: Logout\n\nUsers log out from any page.\n")
+
+	delta := &model.SpecDelta{Domain: domain, BaseFingerprint: spec.Fingerprint, Operations: []model.DeltaOperation{
+		{Type: "PATCH", Requirement: model.Requirement{ID: "login"}, Find: "a password", Replace: "a password or passkey"},
+		{Type: "APPEND", Requirement: model.Requirement{ID: "login"}, Text: "- Failed attempts are rate limited."},
+	}}
+	if err := merger.Merge(delta); err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	want := "# Auth\n\n### Requirement: Login\n\nUsers log in with a password or passkey.\n\n- Failed attempts are rate limited.\n\n#### Scenario: Success\n\n- GIVEN a user\n\n### Requirement: Logout\n\nUsers log out from any page.\n"
+	path := filepath.Join(baseDir, domain, "spec.md")
+	if got := readFile(t, path); got != want {
+		t.Fatalf("spec after merge:\n%s", got)
+	}
+
+	// Re-applying changes nothing.
+	if err := merger.Merge(delta); err != nil {
+		t.Fatalf("re-apply failed: %v", err)
+	}
+	if got := readFile(t, path); got != want {
+		t.Fatalf("spec after re-apply:\n%s", got)
+	}
+
+	// Text that only occurs inside a longer line is still appended.
+	if err := merger.Merge(&model.SpecDelta{Domain: domain, Operations: []model.DeltaOperation{
+		{Type: "APPEND", Requirement: model.Requirement{ID: "logout"}, Text: "Users log out."},
+	}}); err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	want = strings.Replace(want, "from any page.\n", "from any page.\n\nUsers log out.\n", 1)
+	if got := readFile(t, path); got != want {
+		t.Fatalf("spec after appending a substring:\n%s", got)
+	}
+
+	// A PATCH whose context is gone or ambiguous is a conflict.
+	for _, find := range []string{"a username", "- "} {
+		err := merger.Merge(&model.SpecDelta{Domain: domain, Operations: []model.DeltaOperation{
+			{Type: "PATCH", Requirement: model.Requirement{ID: "login"}, Find: find, Replace: "x"},
+		}})
+		if _, ok := err.(*custom_errors.ErrConflict); !ok {
+			t.Errorf("find %q: expected ErrConflict, got %v", find, err)
+		}
+	}
+
+	// Other operations still require the base fingerprint.
+	err = merger.Merge(&model.SpecDelta{Domain: domain, BaseFingerprint: spec.Fingerprint, Operations: []model.DeltaOperation{
+		{Type: "APPEND", Requirement: model.Requirement{ID: "logout"}, Text: "Sessions end."},
+		{Type: "REMOVED", Requirement: model.Requirement{ID: "login"}},
+	}})
+	if _, ok := err.(*custom_errors.ErrDiverged); !ok {
+		t.Fatalf("expected ErrDiverged, got %v", err)
+	}
+}
+
+func TestSpecMerger_PlacementHints(t *testing.T) {
+	baseDir := createTempDir(t)
+	domain := "placement-domain"
+
+	orig := "# Auth\n\n## Sessions\n\n### Requirement: Login\n\nUsers log in.\n\n### Requirement: Logout\n\nUsers log out.\n\n## Tokens\n\n### Requirement: Issue\n\nTokens are issued.\n\n## Non-Goals\n\n* federation\n"
+	writeSpecFile(t, baseDir, domain, orig)
+
+	merger := NewSpecMerger(NewSpecManager(baseDir))
+	delta := &model.SpecDelta{
+		Domain: domain,
+		Operations: []model.DeltaOperation{
+			{Type: "ADDED", After: "login", Requirement: model.Requirement{ID: "remember-me", Content: "### Requirement: Remember Me\n\nSessions can persist."}},
+			{Type: "ADDED", Section: "tokens", Requirement: model.Requirement{ID: "revoke", Content: "### Requirement: Revoke\n\nTokens can be revoked.\n"}},
+			{Type: "ADDED", After: "missing", Section: "Missing", Requirement: model.Requirement{ID: "audit", Content: "### Requirement: Audit\n\nEvents are logged.\n"}},
+		},
+	}
+	if err := merger.Merge(delta); err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+
+	want := "# Auth\n\n## Sessions\n\n### Requirement: Login\n\nUsers log in.\n\n### Requirement: Remember Me\n\nSessions can persist.\n\n### Requirement: Logout\n\nUsers log out.\n\n## Tokens\n\n### Requirement: Issue\n\nTokens are issued.\n\n### Requirement: Revoke\n\nTokens can be revoked.\n\n## Non-Goals\n\n* federation\n### Requirement: Audit\n\nEvents are logged.\n\n"
+	if got := readFile(t, filepath.Join(baseDir, domain, "spec.md")); got != want {
+		t.Fatalf("unexpected content:\nwant %q\ngot  %q", want, got)
+	}
+}
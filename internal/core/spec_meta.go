@@ -0,0 +1,158 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+// SpecMetaKeys are the front matter keys `spec meta set` accepts.
+var SpecMetaKeys = []string{"owner", "status", "tags", "version"}
+
+// frontMatterLen returns the length of the YAML front matter block at the
+// start of content: a "---" line, the YAML, and a closing "---" (or "...")
+// line. It returns 0 when content has no front matter.
+func frontMatterLen(content []byte) int {
+	pos := 0
+	for i := 0; pos < len(content); i++ {
+		next := bytes.IndexByte(content[pos:], '\n')
+		if next < 0 {
+			next = len(content)
+		} else {
+			next += pos + 1
+		}
+		line := strings.TrimRight(string(content[pos:next]), "\r\n")
+		if i == 0 && line != "---" {
+			return 0
+		}
+		if i > 0 && (line == "---" || line == "...") {
+			return next
+		}
+		pos = next
+	}
+	return 0
+}
+
+// maskFrontMatter returns content with the front matter blanked out (every
+// byte but newlines replaced by a space), so markdown parsing neither mistakes
+// it for a thematic break and setext heading nor shifts any offsets.
+func maskFrontMatter(content []byte) []byte {
+	n := frontMatterLen(content)
+	if n == 0 {
+		return content
+	}
+	masked := append([]byte{}, content...)
+	for i := 0; i < n; i++ {
+		if masked[i] != '\n' {
+			masked[i] = ' '
+		}
+	}
+	return masked
+}
+
+// frontMatterYAML returns the YAML between the delimiters of a front matter
+// block.
+func frontMatterYAML(block string) string {
+	body := block[strings.IndexByte(block, '\n')+1:]
+	if i := strings.LastIndex(strings.TrimRight(body, "\r\n"), "\n"); i >= 0 {
+		return body[:i+1]
+	}
+	return ""
+}
+
+// parseSpecMeta decodes a front matter block.
+func parseSpecMeta(block string) (model.SpecMeta, error) {
+	var meta model.SpecMeta
+	if err := yaml.Unmarshal([]byte(frontMatterYAML(block)), &meta); err != nil {
+		return meta, fmt.Errorf("invalid spec front matter: %w", err)
+	}
+	return meta, nil
+}
+
+// SetSpecMeta returns spec's content with the front matter key set to value,
+// creating the front matter if needed; an empty value removes the key. tags
+// takes a comma-separated list. Other keys, comments and the rest of the
+// document are kept as they are.
+func SetSpecMeta(spec *model.Spec, key, value string) (string, error) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	if !containsString(SpecMetaKeys, key) {
+		return "", custom_errors.NewErrValidation("key", fmt.Sprintf("unknown spec metadata key %q (want one of %s)", key, strings.Join(SpecMetaKeys, ", ")))
+	}
+	value = strings.TrimSpace(value)
+
+	var node *yaml.Node
+	if value != "" {
+		node = &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+		if key == "tags" {
+			node = &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+			for _, t := range strings.Split(value, ",") {
+				if t = strings.TrimSpace(t); t != "" {
+					node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: t})
+				}
+			}
+		}
+	}
+	return setFrontMatterKey(spec, key, node)
+}
+
+// setFrontMatterKey returns spec's content with the front matter key set to
+// node, creating the front matter if needed; a nil node removes the key, and
+// the front matter with it once it is empty.
+func setFrontMatterKey(spec *model.Spec, key string, node *yaml.Node) (string, error) {
+	doc := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	if spec.FrontMatter != "" {
+		var parsed yaml.Node
+		if err := yaml.Unmarshal([]byte(frontMatterYAML(spec.FrontMatter)), &parsed); err != nil {
+			return "", fmt.Errorf("invalid spec front matter: %w", err)
+		}
+		if len(parsed.Content) > 0 {
+			doc = &parsed
+		}
+	}
+	mapping := doc.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return "", fmt.Errorf("invalid spec front matter: not a mapping")
+	}
+
+	replaced := false
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value != key {
+			continue
+		}
+		if node == nil {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+		} else {
+			mapping.Content[i+1] = node
+		}
+		replaced = true
+		break
+	}
+	if !replaced && node != nil {
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, node)
+	}
+
+	body := strings.TrimPrefix(spec.Content, spec.FrontMatter)
+	if len(mapping.Content) == 0 {
+		if spec.FrontMatter == "" {
+			return spec.Content, nil
+		}
+		return strings.TrimLeft(body, "\r\n"), nil
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	if spec.FrontMatter == "" && body != "" && !strings.HasPrefix(body, "\n") {
+		body = "\n" + body
+	}
+	return "---\n" + buf.String() + "---\n" + body, nil
+}
@@ -0,0 +1,108 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+const specWithFrontMatter = `---
+owner: "@alice"
+status: draft # until review
+tags: [auth, security]
+version: "1.2"
+---
+
+# Auth
+
+### Requirement: Login
+
+Users log in.
+`
+
+func TestSpecParser_FrontMatter(t *testing.T) {
+	spec, err := NewSpecParser().Parse([]byte(specWithFrontMatter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := model.SpecMeta{Owner: "@alice", Status: "draft", Tags: []string{"auth", "security"}, Version: "1.2"}
+	if !reflect.DeepEqual(spec.Meta, want) {
+		t.Fatalf("meta = %+v", spec.Meta)
+	}
+	if len(spec.Blocks) != 3 || spec.Blocks[0].Kind != model.SpecBlockPreamble || !strings.HasPrefix(spec.Blocks[0].Content, spec.FrontMatter) {
+		t.Fatalf("blocks = %+v", spec.Blocks)
+	}
+	if len(spec.Requirements) != 1 || spec.Content[spec.Requirements[0].Start:spec.Requirements[0].End] != "### Requirement: Login\n\nUsers log in.\n" {
+		t.Fatalf("requirements = %+v", spec.Requirements)
+	}
+
+	plain, _ := NewSpecParser().Parse([]byte(strings.TrimPrefix(specWithFrontMatter, spec.FrontMatter)))
+	if SpecFingerprint(spec) != SpecFingerprint(plain) {
+		t.Fatal("front matter should not change the fingerprint")
+	}
+
+	if _, err := NewSpecParser().Parse([]byte("---\ntags: [unclosed\n---\n# Auth\n")); err == nil {
+		t.Fatal("expected invalid front matter to fail")
+	}
+}
+
+func TestSetSpecMeta(t *testing.T) {
+	spec, _ := NewSpecParser().Parse([]byte(specWithFrontMatter))
+	content, err := SetSpecMeta(spec, "owner", "@bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated, err := NewSpecParser().Parse([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Meta.Owner != "@bob" || updated.Meta.Status != "draft" || !strings.Contains(updated.FrontMatter, "# until review") {
+		t.Fatalf("updated front matter = %q", updated.FrontMatter)
+	}
+	if !strings.HasSuffix(content, strings.TrimPrefix(specWithFrontMatter, spec.FrontMatter)) {
+		t.Fatalf("body changed: %q", content)
+	}
+
+	bare, _ := NewSpecParser().Parse([]byte("# Auth\n"))
+	content, err = SetSpecMeta(bare, "tags", "a, b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content != "---\ntags: [a, b]\n---\n\n# Auth\n" {
+		t.Fatalf("created front matter = %q", content)
+	}
+	tagged, _ := NewSpecParser().Parse([]byte(content))
+	if content, err = SetSpecMeta(tagged, "tags", ""); err != nil || content != "# Auth\n" {
+		t.Fatalf("removing the last key = %q, %v", content, err)
+	}
+
+	if _, err := SetSpecMeta(bare, "colour", "red"); err == nil {
+		t.Fatal("expected unknown key to fail")
+	}
+}
+
+func TestSpecMerger_PreservesFrontMatter(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "auth", Content: specWithFrontMatter}); err != nil {
+		t.Fatal(err)
+	}
+	spec, _ := app.SpecManager.ReadSpec("auth")
+	err = app.SpecMerger.Merge(&model.SpecDelta{
+		Domain:          "auth",
+		BaseFingerprint: spec.Fingerprint,
+		Operations:      []model.DeltaOperation{{Type: "ADDED", Requirement: model.Requirement{ID: "logout", Title: "Logout", Content: "### Requirement: Logout\n\nUsers log out.\n"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged, _ := app.SpecManager.ReadSpec("auth")
+	if merged.FrontMatter != spec.FrontMatter || len(merged.Requirements) != 2 {
+		t.Fatalf("merged = %q", merged.Content)
+	}
+}
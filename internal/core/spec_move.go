@@ -0,0 +1,75 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// MoveRequirement reorders a spec so requirement reqID sits immediately before
+// (or, with after set, immediately after) requirement anchorID, and writes the
+// spec back. Only block order changes: every block keeps its exact text and
+// the blank lines that follow it, except that the file keeps its original
+// ending (a block moved away from, or to, the end trades its trailing blank
+// lines with its neighbor). The spec's fingerprint changes, so pending changes
+// based on the old text become stale.
+func MoveRequirement(specs SpecManager, domain, reqID, anchorID string, after bool) (*model.Spec, error) {
+	if reqID == anchorID {
+		return nil, custom_errors.NewErrConflict("cannot move a requirement relative to itself")
+	}
+	spec, err := specs.ReadSpec(domain)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := specParser(specs).Parse([]byte(spec.Content))
+	if err != nil {
+		return nil, err
+	}
+	blocks := parsed.Blocks
+
+	from := findRequirementBlock(blocks, reqID)
+	if from == -1 {
+		return nil, custom_errors.NewErrNotFound("requirement", reqID)
+	}
+	if findRequirementBlock(blocks, anchorID) == -1 {
+		return nil, custom_errors.NewErrNotFound("requirement", anchorID)
+	}
+
+	// Take the block out. If it was last, it carried the end-of-file gap, which
+	// stays at the end of the document with the new last block.
+	moved := blocks[from]
+	blocks = append(blocks[:from:from], blocks[from+1:]...)
+	if from == len(blocks) && from > 0 {
+		swapTrailingGap(&blocks[from-1], &moved)
+	}
+
+	to := findRequirementBlock(blocks, anchorID)
+	if after {
+		to++
+	}
+	blocks = append(blocks[:to], append([]model.SpecBlock{moved}, blocks[to:]...)...)
+	if last := len(blocks) - 1; to == last && last > 0 {
+		swapTrailingGap(&blocks[last-1], &blocks[last])
+	}
+
+	spec.Content = joinSpecBlocks(blocks)
+	if err := specs.WriteSpec(spec); err != nil {
+		return nil, fmt.Errorf("failed to write spec '%s': %w", domain, err)
+	}
+	return spec, nil
+}
+
+// swapTrailingGap exchanges the trailing blank lines of two blocks.
+func swapTrailingGap(a, b *model.SpecBlock) {
+	aBody, aGap := splitTrailingGap(a.Content)
+	bBody, bGap := splitTrailingGap(b.Content)
+	a.Content, b.Content = aBody+bGap, bBody+aGap
+}
+
+// splitTrailingGap splits s into its text and the run of line breaks ending it.
+func splitTrailingGap(s string) (string, string) {
+	body := strings.TrimRight(s, "\r\n")
+	return body, s[len(body):]
+}
@@ -0,0 +1,60 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	ce "github.com/teamwerx/teamwerx/internal/errors"
+)
+
+func TestMoveRequirement_PreservesBlocksAndGaps(t *testing.T) {
+	const original = "# Auth\n\nIntro.\n\n### Requirement: A\n\nAlpha.\n\n### Requirement: B\n\nBeta.\n\n\n### Requirement: C\n\nGamma.\n"
+
+	cases := []struct {
+		name, req, anchor string
+		after             bool
+		want              string
+	}{
+		{
+			name: "last before first", req: "c", anchor: "a",
+			want: "# Auth\n\nIntro.\n\n### Requirement: C\n\nGamma.\n\n\n### Requirement: A\n\nAlpha.\n\n### Requirement: B\n\nBeta.\n",
+		},
+		{
+			name: "first after last", req: "a", anchor: "c", after: true,
+			want: "# Auth\n\nIntro.\n\n### Requirement: B\n\nBeta.\n\n\n### Requirement: C\n\nGamma.\n\n### Requirement: A\n\nAlpha.\n",
+		},
+		{
+			name: "middle before first", req: "b", anchor: "a",
+			want: "# Auth\n\nIntro.\n\n### Requirement: B\n\nBeta.\n\n\n### Requirement: A\n\nAlpha.\n\n### Requirement: C\n\nGamma.\n",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			baseDir := createTempDir(t)
+			writeSpecFile(t, baseDir, "auth", original)
+			specs := NewSpecManager(baseDir)
+
+			if _, err := MoveRequirement(specs, "auth", tc.req, tc.anchor, tc.after); err != nil {
+				t.Fatalf("MoveRequirement: %v", err)
+			}
+			spec, err := specs.ReadSpec("auth")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if spec.Content != tc.want {
+				t.Fatalf("unexpected content:\n%q\nwant:\n%q", spec.Content, tc.want)
+			}
+		})
+	}
+}
+
+func TestMoveRequirement_UnknownRequirement(t *testing.T) {
+	baseDir := createTempDir(t)
+	writeSpecFile(t, baseDir, "auth", "# Auth\n\n### Requirement: A\n\nAlpha.\n")
+	specs := NewSpecManager(baseDir)
+
+	var nf *ce.ErrNotFound
+	if _, err := MoveRequirement(specs, "auth", "a", "missing", false); !errors.As(err, &nf) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package core
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/utils"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// rationalePrefix starts a blockquote that is a requirement's rationale note,
+// e.g. "> Rationale: customers asked for this in #212".
+const rationalePrefix = "Rationale:"
+
+var (
+	quoteMarker   = regexp.MustCompile(`(?m)^ {0,3}> ?`)
+	detailsTag    = regexp.MustCompile(`(?i)^\s*<details\b`)
+	detailsMarkup = regexp.MustCompile(`(?is)</?details[^>]*>|<summary[^>]*>.*?</summary>`)
+)
+
+// findNotes returns the editorial notes in a requirement block. block is the
+// block's text and offset its position in the spec. A note is either a
+// blockquote whose text starts with "Rationale:" or a <details> HTML section
+// up to its closing </details>. Each note's range covers its lines plus the
+// blank lines after it, so removing it leaves the surrounding text as if the
+// note had never been written.
+func (p *SpecParser) findNotes(block string, offset int) []model.RequirementNote {
+	src := []byte(block)
+	doc := p.goldmark.Parser().Parse(text.NewReader(src))
+
+	var notes []model.RequirementNote
+	for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+		kind := ""
+		last := n
+		switch node := n.(type) {
+		case *ast.Blockquote:
+			if strings.HasPrefix(strings.TrimSpace(string(node.Text(src))), rationalePrefix) {
+				kind = model.NoteRationale
+			}
+		case *ast.HTMLBlock:
+			if detailsTag.Match(blockLines(node, src)) {
+				kind = model.NoteDetails
+				// The closing tag may be a later block when the section contains blank lines.
+				for ; last != nil; last = last.NextSibling() {
+					if h, ok := last.(*ast.HTMLBlock); ok && bytes.Contains(bytes.ToLower(blockLines(h, src)), []byte("</details>")) {
+						break
+					}
+					if last.NextSibling() == nil {
+						break
+					}
+				}
+			}
+		}
+		if kind == "" {
+			continue
+		}
+
+		start, _, ok := nodeSpan(n)
+		_, stop, ok2 := nodeSpan(last)
+		if !ok || !ok2 {
+			continue
+		}
+		start = lineStart(src, start)
+		end := lineEnd(src, stop)
+		raw := string(src[start:end])
+		for end < len(src) {
+			next := len(src)
+			if idx := bytes.IndexByte(src[end:], '\n'); idx >= 0 {
+				next = end + idx + 1
+			}
+			if strings.TrimSpace(string(src[end:next])) != "" {
+				break
+			}
+			end = next
+		}
+		notes = append(notes, model.RequirementNote{
+			Kind:  kind,
+			Text:  noteText(kind, raw),
+			Start: offset + start,
+			End:   offset + end,
+		})
+		n = last
+	}
+	return notes
+}
+
+// noteText strips the quote markers and "Rationale:" prefix, or the details
+// and summary tags, from a note's source.
+func noteText(kind, raw string) string {
+	if kind == model.NoteRationale {
+		s := strings.TrimSpace(quoteMarker.ReplaceAllString(raw, ""))
+		s = strings.TrimPrefix(strings.TrimPrefix(s, "**"+rationalePrefix+"**"), rationalePrefix)
+		return strings.TrimSpace(s)
+	}
+	return strings.TrimSpace(detailsMarkup.ReplaceAllString(raw, ""))
+}
+
+// blockLines returns the raw text of a block node's lines, including an HTML
+// block's closing line.
+func blockLines(n ast.Node, src []byte) []byte {
+	var buf bytes.Buffer
+	lines := n.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		seg := lines.At(i)
+		buf.Write(seg.Value(src))
+	}
+	if h, ok := n.(*ast.HTMLBlock); ok && h.HasClosure() {
+		buf.Write(h.ClosureLine.Value(src))
+	}
+	return buf.Bytes()
+}
+
+// nodeSpan returns the smallest and largest source offsets covered by the
+// lines of n and its descendants.
+func nodeSpan(n ast.Node) (start, stop int, ok bool) {
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering || c.Type() != ast.TypeBlock {
+			return ast.WalkContinue, nil
+		}
+		lines := c.Lines()
+		for i := 0; i < lines.Len(); i++ {
+			seg := lines.At(i)
+			if !ok || seg.Start < start {
+				start = seg.Start
+			}
+			if !ok || seg.Stop > stop {
+				stop = seg.Stop
+			}
+			ok = true
+		}
+		if h, isHTML := c.(*ast.HTMLBlock); isHTML && h.HasClosure() && h.ClosureLine.Stop > stop {
+			stop = h.ClosureLine.Stop
+		}
+		return ast.WalkContinue, nil
+	})
+	return start, stop, ok
+}
+
+// lineEnd returns the offset just past the line break ending the line that
+// contains offset (or len(src) on the last line).
+func lineEnd(src []byte, offset int) int {
+	if offset > 0 && offset <= len(src) && src[offset-1] == '\n' {
+		return offset
+	}
+	if idx := bytes.IndexByte(src[offset:], '\n'); idx >= 0 {
+		return offset + idx + 1
+	}
+	return len(src)
+}
+
+// StripRequirementNotes returns the spec's content with every requirement
+// note removed.
+func StripRequirementNotes(spec *model.Spec) string {
+	var notes []model.RequirementNote
+	for _, r := range spec.Requirements {
+		notes = append(notes, r.Notes...)
+	}
+	if len(notes) == 0 {
+		return spec.Content
+	}
+	sort.Slice(notes, func(i, j int) bool { return notes[i].Start < notes[j].Start })
+	var b strings.Builder
+	last := 0
+	for _, n := range notes {
+		if n.Start < last {
+			continue
+		}
+		b.WriteString(spec.Content[last:n.Start])
+		last = n.End
+	}
+	b.WriteString(spec.Content[last:])
+	return b.String()
+}
+
+// SpecFingerprint fingerprints a parsed spec's content without its front
+// matter and requirement notes, so editing metadata or rationale never makes
+// pending changes stale.
+func SpecFingerprint(spec *model.Spec) string {
+	return utils.GenerateFingerprint(strings.TrimPrefix(StripRequirementNotes(spec), spec.FrontMatter))
+}
+
+// RequirementFingerprint fingerprints one requirement block of a parsed spec
+// without its notes, the per-requirement counterpart of SpecFingerprint.
+func RequirementFingerprint(spec *model.Spec, r model.Requirement) string {
+	if r.Start < 0 || r.End > len(spec.Content) || r.Start > r.End {
+		return ""
+	}
+	return SpecFingerprint(&model.Spec{Content: spec.Content[r.Start:r.End], Requirements: []model.Requirement{rebaseNotes(r)}})
+}
+
+// setFingerprints sets the fingerprint of spec and of each of its
+// requirements from its current content.
+func setFingerprints(spec *model.Spec) {
+	spec.Fingerprint = SpecFingerprint(spec)
+	for i := range spec.Requirements {
+		spec.Requirements[i].Fingerprint = RequirementFingerprint(spec, spec.Requirements[i])
+	}
+}
+
+// rebaseNotes returns r with its note ranges relative to the requirement's
+// own block rather than the whole spec.
+func rebaseNotes(r model.Requirement) model.Requirement {
+	notes := make([]model.RequirementNote, len(r.Notes))
+	for i, n := range r.Notes {
+		n.Start -= r.Start
+		n.End -= r.Start
+		notes[i] = n
+	}
+	r.Notes = notes
+	return r
+}
@@ -0,0 +1,136 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+const notesSpecBase = `# Auth
+
+### Requirement: Login
+
+Users log in with a password.
+
+#### Scenario: Success
+
+Given valid credentials.
+
+### Requirement: Logout
+
+Users log out.
+`
+
+const notesSpecWithNotes = `# Auth
+
+### Requirement: Login
+
+Users log in with a password.
+
+> Rationale: SSO is out of scope until the
+> identity provider contract is signed.
+
+#### Scenario: Success
+
+Given valid credentials.
+
+### Requirement: Logout
+
+Users log out.
+
+<details>
+<summary>Notes</summary>
+
+Sessions are server-side.
+
+</details>
+`
+
+func TestSpecParser_ParsesRequirementNotes(t *testing.T) {
+	spec, err := NewSpecParser().Parse([]byte(notesSpecWithNotes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spec.Requirements) != 2 {
+		t.Fatalf("requirements = %d", len(spec.Requirements))
+	}
+	login, logout := spec.Requirements[0], spec.Requirements[1]
+	if len(login.Notes) != 1 || login.Notes[0].Kind != model.NoteRationale ||
+		login.Notes[0].Text != "SSO is out of scope until the\nidentity provider contract is signed." {
+		t.Fatalf("login notes = %+v", login.Notes)
+	}
+	if len(logout.Notes) != 1 || logout.Notes[0].Kind != model.NoteDetails || logout.Notes[0].Text != "Sessions are server-side." {
+		t.Fatalf("logout notes = %+v", logout.Notes)
+	}
+	if len(login.Scenarios) != 1 {
+		t.Fatalf("scenarios = %+v", login.Scenarios)
+	}
+
+	// A note ending the spec leaves the blank line before it behind.
+	if got := StripRequirementNotes(spec); strings.TrimSpace(got) != strings.TrimSpace(notesSpecBase) {
+		t.Fatalf("stripped content:\n%s", got)
+	}
+}
+
+func TestSpecFingerprint_IgnoresNotes(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := &model.Spec{Domain: "auth", Content: notesSpecBase}
+	if err := app.SpecManager.WriteSpec(base); err != nil {
+		t.Fatal(err)
+	}
+	noted := &model.Spec{Domain: "auth", Content: notesSpecWithNotes}
+	if err := app.SpecManager.WriteSpec(noted); err != nil {
+		t.Fatal(err)
+	}
+	if noted.Fingerprint != base.Fingerprint {
+		t.Fatalf("fingerprint changed by notes: %s != %s", noted.Fingerprint, base.Fingerprint)
+	}
+	read, err := app.SpecManager.ReadSpec("auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Fingerprint != base.Fingerprint {
+		t.Fatalf("read fingerprint %s != %s", read.Fingerprint, base.Fingerprint)
+	}
+
+	// Editing the requirement text itself still changes the fingerprint.
+	edited := &model.Spec{Domain: "auth", Content: strings.Replace(notesSpecWithNotes, "a password", "a passkey", 1)}
+	if err := app.SpecManager.WriteSpec(edited); err != nil {
+		t.Fatal(err)
+	}
+	if edited.Fingerprint == base.Fingerprint {
+		t.Fatal("expected requirement edits to change the fingerprint")
+	}
+}
+
+func TestSpecExporter_OmitNotes(t *testing.T) {
+	spec, err := NewSpecParser().Parse([]byte(notesSpecWithNotes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec.Domain = "auth"
+	e := NewSpecExporter()
+	page, err := e.RenderSpec(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(page), "SSO is out of scope") {
+		t.Fatal("expected notes in default export")
+	}
+	e.OmitNotes = true
+	page, err = e.RenderSpec(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(page), "SSO is out of scope") || strings.Contains(string(page), "Sessions are server-side") {
+		t.Fatalf("notes not omitted:\n%s", page)
+	}
+	if !strings.Contains(string(page), "Users log out.") {
+		t.Fatal("requirement text missing from export")
+	}
+}
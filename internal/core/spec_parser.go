@@ -2,6 +2,9 @@ package core
 
 import (
 	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/teamwerx/teamwerx/internal/model"
 	"github.com/teamwerx/teamwerx/internal/utils"
@@ -10,67 +13,278 @@ import (
 	"github.com/yuin/goldmark/text"
 )
 
+// scenarioPrefix marks a level-4 heading inside a requirement as a scenario.
+const scenarioPrefix = "Scenario:"
+
 // SpecParser is responsible for parsing spec files.
 type SpecParser struct {
 	goldmark goldmark.Markdown
+	format   SpecFormat
+	loose    *regexp.Regexp
 }
 
-// NewSpecParser creates a new SpecParser.
+// NewSpecParser creates a SpecParser for "### Requirement: <title>" headings.
 func NewSpecParser() *SpecParser {
+	return NewSpecParserWithFormat(DefaultSpecFormat())
+}
+
+// NewSpecParserWithFormat creates a SpecParser for the requirement headings
+// format describes.
+func NewSpecParserWithFormat(format SpecFormat) *SpecParser {
+	format = format.WithDefaults()
 	return &SpecParser{
 		goldmark: goldmark.New(),
+		format:   format,
+		loose:    format.loosePrefix(),
 	}
 }
 
+// Format returns the requirement heading format the parser reads.
+func (p *SpecParser) Format() SpecFormat {
+	return p.format
+}
+
+// unspacedHeading matches a line that would be an ATX heading but for the
+// space after its "#"s, e.g. "###Requirement: Login".
+var unspacedHeading = regexp.MustCompile(`^ {0,3}(#{1,6})([^#\s].*?)\s*$`)
+
 // Parse parses the content of a spec file and returns a Spec model.
+//
+// The document is split into ordered blocks at every top-level heading of level
+// 1-3, ATX or setext: an optional preamble, plain sections, and requirement
+// blocks ("### Requirement: <title>", or as the parser's SpecFormat says). A
+// requirement block keeps the headings below its own level.
+// Blocks cover the input byte-for-byte, so serializing them back yields the
+// original content unchanged. YAML front matter at the top of the file is
+// decoded into Spec.Meta and stays, verbatim, at the start of the preamble.
+//
+// Requirement headings with a slip in spacing or case are still read as
+// requirements, each with a warning in Spec.Warnings: a missing space after
+// the "#"s ("###Requirement: Login"), or a prefix in another case or with
+// spaces before its colon ("### requirement : Login"). So is a prefixed
+// heading of another level, which stays a plain section.
 func (p *SpecParser) Parse(content []byte) (*model.Spec, error) {
-	reader := text.NewReader(content)
+	reader := text.NewReader(maskFrontMatter(content))
 	node := p.goldmark.Parser().Parse(reader)
 
 	spec := &model.Spec{
 		Content: string(content),
 		AST:     node,
 	}
+	if n := frontMatterLen(content); n > 0 {
+		meta, err := parseSpecMeta(string(content[:n]))
+		if err != nil {
+			return nil, err
+		}
+		spec.Meta = meta
+		spec.FrontMatter = string(content[:n])
+	}
 
-	var requirements []model.Requirement
-	var currentReq *model.Requirement
-
-	_ = ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
-		if entering {
-			if h, ok := n.(*ast.Heading); ok && h.Level <= 3 {
-				// Finish previous requirement if we encounter a new heading
-				if currentReq != nil {
-					end := n.Lines().At(0).Start
-					currentReq.Content = string(content[currentReq.Start:end])
-					requirements = append(requirements, *currentReq)
-					currentReq = nil
+	type boundary struct {
+		start      int // start of the heading line
+		headingEnd int // end of the heading text
+		level      int
+		title      string
+		// reqTitle is set for a requirement heading.
+		reqTitle string
+		isReq    bool
+	}
+	warn := func(offset int, format string, a ...interface{}) {
+		line := bytes.Count(content[:offset], []byte("\n")) + 1
+		spec.Warnings = append(spec.Warnings, fmt.Sprintf("line %d: ", line)+fmt.Sprintf(format, a...))
+	}
+	var bounds []boundary
+	for n := node.FirstChild(); n != nil; n = n.NextSibling() {
+		switch h := n.(type) {
+		case *ast.Heading:
+			if h.Level > 3 || h.Lines().Len() == 0 {
+				continue
+			}
+			b := boundary{
+				start:      lineStart(content, h.Lines().At(0).Start),
+				headingEnd: h.Lines().At(h.Lines().Len() - 1).Stop,
+				level:      h.Level,
+				title:      string(bytes.TrimSpace(h.Text(content))),
+			}
+			b.reqTitle, b.isReq = p.format.requirementTitle(b.level, b.title)
+			if m := p.loose.FindStringSubmatch(b.title); !b.isReq && m != nil {
+				want := p.format.RequirementHeading(m[1])
+				if b.level == p.format.RequirementLevel {
+					b.reqTitle, b.isReq = strings.TrimSpace(m[1]), true
+					warn(b.start, "requirement heading %q should read %q", b.title, want)
+				} else {
+					warn(b.start, "%q is a level %d heading, not a requirement; write %q", b.title, b.level, want)
 				}
-
-				if h.Level == 3 && bytes.HasPrefix(h.Text(content), []byte("Requirement:")) {
-					title := bytes.TrimSpace(bytes.TrimPrefix(h.Text(content), []byte("Requirement:")))
-					currentReq = &model.Requirement{
-						ID:    utils.ToKebabCase(string(title)),
-						Title: string(title),
-						Start: n.Lines().At(n.Lines().Len() - 1).Stop,
-					}
+			}
+			bounds = append(bounds, b)
+		case *ast.Paragraph:
+			// "###Requirement:" is paragraph text to Markdown.
+			for i := 0; i < h.Lines().Len(); i++ {
+				start := lineStart(content, h.Lines().At(i).Start)
+				end := bytes.IndexByte(content[start:], '\n')
+				if end < 0 {
+					end = len(content) - start
+				}
+				line := string(content[start : start+end])
+				m := unspacedHeading.FindStringSubmatch(line)
+				if m == nil || len(m[1]) != p.format.RequirementLevel {
+					continue
+				}
+				title := strings.TrimSpace(m[2])
+				pm := p.loose.FindStringSubmatch(title)
+				if pm == nil {
+					continue
 				}
+				warn(start, "requirement heading %q needs a space after the '#'s: %q", strings.TrimSpace(line), p.format.RequirementHeading(pm[1]))
+				bounds = append(bounds, boundary{start: start, headingEnd: start + end, level: len(m[1]), title: title, reqTitle: strings.TrimSpace(pm[1]), isReq: true})
 			}
 		}
-		return ast.WalkContinue, nil
-	})
+	}
+
+	// Headings below the requirement level (with "## REQ:" requirements, a
+	// "### Rationale") belong to the requirement above them.
+	nested := bounds
+	bounds = nil
+	inReq := false
+	for _, b := range nested {
+		if inReq && !b.isReq && b.level > p.format.RequirementLevel {
+			continue
+		}
+		bounds = append(bounds, b)
+		inReq = b.isReq
+	}
 
-	// Add the last requirement if it exists
-	if currentReq != nil {
-		currentReq.Content = string(content[currentReq.Start:])
-		requirements = append(requirements, *currentReq)
+	if len(bounds) == 0 || bounds[0].start > 0 {
+		end := len(content)
+		if len(bounds) > 0 {
+			end = bounds[0].start
+		}
+		if end > 0 {
+			spec.Blocks = append(spec.Blocks, model.SpecBlock{
+				Kind:    model.SpecBlockPreamble,
+				Content: string(content[:end]),
+			})
+		}
 	}
 
-	spec.Requirements = requirements
+	for i, b := range bounds {
+		end := len(content)
+		if i+1 < len(bounds) {
+			end = bounds[i+1].start
+		}
+		block := model.SpecBlock{
+			Kind:    model.SpecBlockSection,
+			Level:   b.level,
+			Title:   b.title,
+			Content: string(content[b.start:end]),
+		}
+		if b.isReq {
+			reqTitle := b.reqTitle
+			block.Kind = model.SpecBlockRequirement
+			block.Title = reqTitle
+			block.RequirementID = utils.ToKebabCase(reqTitle)
 
-	// We need to remove the Start field from the model, it was temporary
-	for i := range spec.Requirements {
-		spec.Requirements[i].Start = 0
+			body := ""
+			if b.headingEnd < end {
+				body = string(content[b.headingEnd:end])
+			}
+			req := model.Requirement{
+				ID:      block.RequirementID,
+				Title:   reqTitle,
+				Content: body,
+				Start:   b.start,
+				End:     end,
+				Notes:   p.findNotes(string(content[b.start:end]), b.start),
+			}
+			req.AcceptanceCriteria = findAcceptanceCriteria(string(content[b.start:end]), b.start, req.Notes)
+			req.Code = findCodePaths(string(content[b.start:end]), b.start, req.Notes)
+			req.Priority = findPriority(string(content[b.start:end]), b.start, req.Notes)
+			offset := b.start
+			for _, sub := range p.splitRequirementBlock(block.Content) {
+				if sub.ScenarioID != "" {
+					req.Scenarios = append(req.Scenarios, model.Scenario{
+						ID:            sub.ScenarioID,
+						Title:         sub.Title,
+						RequirementID: req.ID,
+						Content:       sub.Content,
+						Start:         offset,
+						End:           offset + len(sub.Content),
+					})
+				}
+				offset += len(sub.Content)
+			}
+			spec.Requirements = append(spec.Requirements, req)
+		}
+		spec.Blocks = append(spec.Blocks, block)
 	}
 
 	return spec, nil
 }
+
+// requirementSubBlock is a region of a requirement block: the heading and body
+// before the first level-4 heading, or one level-4 heading and its body.
+type requirementSubBlock struct {
+	Title      string // heading text ("Scenario:" prefix stripped); empty for the lead-in
+	ScenarioID string // set when the heading is "#### Scenario: <title>"
+	Content    string // exact source text
+}
+
+// splitRequirementBlock splits a requirement block's text at its level-4
+// headings. Like Parse's blocks, the sub-blocks cover content byte-for-byte.
+func (p *SpecParser) splitRequirementBlock(content string) []requirementSubBlock {
+	src := []byte(content)
+	doc := p.goldmark.Parser().Parse(text.NewReader(src))
+
+	var starts []int
+	var titles []string
+	for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+		h, ok := n.(*ast.Heading)
+		if !ok || h.Level != 4 || h.Lines().Len() == 0 {
+			continue
+		}
+		starts = append(starts, lineStart(src, h.Lines().At(0).Start))
+		titles = append(titles, string(bytes.TrimSpace(h.Text(src))))
+	}
+
+	var subs []requirementSubBlock
+	if len(starts) == 0 || starts[0] > 0 {
+		end := len(src)
+		if len(starts) > 0 {
+			end = starts[0]
+		}
+		subs = append(subs, requirementSubBlock{Content: string(src[:end])})
+	}
+	for i, start := range starts {
+		end := len(src)
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		sub := requirementSubBlock{Title: titles[i], Content: string(src[start:end])}
+		if strings.HasPrefix(titles[i], scenarioPrefix) {
+			sub.Title = strings.TrimSpace(strings.TrimPrefix(titles[i], scenarioPrefix))
+			sub.ScenarioID = utils.ToKebabCase(sub.Title)
+		}
+		subs = append(subs, sub)
+	}
+	return subs
+}
+
+// lineStart returns the offset of the beginning of the line containing offset.
+func lineStart(src []byte, offset int) int {
+	if offset <= 0 {
+		return 0
+	}
+	if idx := bytes.LastIndexByte(src[:offset], '\n'); idx >= 0 {
+		return idx + 1
+	}
+	return 0
+}
+
+// joinSpecBlocks concatenates blocks back into document text.
+func joinSpecBlocks(blocks []model.SpecBlock) string {
+	var buf strings.Builder
+	for _, b := range blocks {
+		buf.WriteString(b.Content)
+	}
+	return buf.String()
+}
@@ -147,3 +147,37 @@ Another description.
 		t.Errorf("Expected Title 'Another Feature', got '%s'", req2.Title)
 	}
 }
+
+func TestSpecParser_Parse_BlocksRoundTrip(t *testing.T) {
+	content := "Intro line before any heading.\n\n# Domain Spec\n\n## Overview\n\nOverview *text*.\n\n### Requirement: Login\n\nBody.\n\n#### Detail\n\nNested.\n\n## Non-Goals\n\n- none\n"
+
+	spec, err := NewSpecParser().Parse([]byte(content))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	kinds := []string{}
+	for _, b := range spec.Blocks {
+		kinds = append(kinds, b.Kind)
+	}
+	want := []string{"preamble", "section", "section", "requirement", "section"}
+	if len(kinds) != len(want) {
+		t.Fatalf("expected kinds %v, got %v", want, kinds)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Fatalf("expected kinds %v, got %v", want, kinds)
+		}
+	}
+	if spec.Blocks[3].RequirementID != "login" || spec.Blocks[3].Title != "Login" {
+		t.Errorf("unexpected requirement block: %+v", spec.Blocks[3])
+	}
+
+	out, err := NewSpecSerializer().Serialize(spec)
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+	if string(out) != content {
+		t.Fatalf("round trip mismatch:\nwant %q\ngot  %q", content, string(out))
+	}
+}
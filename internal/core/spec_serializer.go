@@ -5,7 +5,6 @@ import (
 	"errors"
 
 	"github.com/teamwerx/teamwerx/internal/model"
-	"github.com/yuin/goldmark"
 )
 
 // SpecSerializer is responsible for serializing a Spec model to a file.
@@ -22,14 +21,10 @@ func (s *SpecSerializer) Serialize(spec *model.Spec) ([]byte, error) {
 		return nil, errors.New("spec is nil")
 	}
 
-	// Prefer rendering from AST when present, using existing Content as source bytes.
-	if spec.AST != nil {
-		var buf bytes.Buffer
-		md := goldmark.New()
-		if err := md.Renderer().Render(&buf, []byte(spec.Content), spec.AST); err != nil {
-			return nil, err
-		}
-		return buf.Bytes(), nil
+	// Prefer the ordered document model: concatenating the blocks reproduces the
+	// preamble, sections and requirement blocks byte-for-byte.
+	if len(spec.Blocks) > 0 {
+		return []byte(joinSpecBlocks(spec.Blocks)), nil
 	}
 
 	// Fallback: reconstruct from requirements in order, preserving each block and a blank line.
@@ -0,0 +1,61 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// DefaultSpecSections are used for scaffolding when the charter does not define
+// its own spec_sections list.
+var DefaultSpecSections = []string{"Overview", "Requirements"}
+
+// ScaffoldSpec builds the initial markdown for a new spec domain, with a title
+// heading followed by one level-2 heading per required section.
+func ScaffoldSpec(domain string, sections []string) string {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "# %s Spec\n", domain)
+	for _, s := range sections {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		fmt.Fprintf(&buf, "\n## %s\n\n", s)
+	}
+	return buf.String()
+}
+
+// MissingSpecSections returns the required sections that do not appear as
+// level-2 headings in the spec content. Comparison is case-insensitive and
+// ignores surrounding whitespace. The result preserves the order of required.
+func MissingSpecSections(spec *model.Spec, required []string) []string {
+	if spec == nil {
+		return required
+	}
+	src := maskFrontMatter([]byte(spec.Content))
+	doc := goldmark.New().Parser().Parse(text.NewReader(src))
+
+	present := map[string]bool{}
+	for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+		if h, ok := n.(*ast.Heading); ok && h.Level == 2 {
+			present[strings.ToLower(string(bytes.TrimSpace(h.Text(src))))] = true
+		}
+	}
+
+	var missing []string
+	for _, r := range required {
+		name := strings.TrimSpace(r)
+		if name == "" {
+			continue
+		}
+		if !present[strings.ToLower(name)] {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,31 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+func TestScaffoldSpec_SatisfiesRequiredSections(t *testing.T) {
+	sections := []string{"Overview", "Requirements", "Non-Goals"}
+	content := ScaffoldSpec("payments", sections)
+
+	spec, err := NewSpecParser().Parse([]byte(content))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if missing := MissingSpecSections(spec, sections); len(missing) != 0 {
+		t.Fatalf("scaffold should contain all sections, missing %v:\n%s", missing, content)
+	}
+}
+
+func TestMissingSpecSections_CaseInsensitiveAndOrdered(t *testing.T) {
+	spec := &model.Spec{Content: "# Auth\n\n## overview\n\nText.\n\n### Requirement: Login\n\nBody.\n"}
+
+	got := MissingSpecSections(spec, []string{"Non-Goals", "Overview", "Requirements"})
+	want := []string{"Non-Goals", "Requirements"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
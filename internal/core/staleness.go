@@ -0,0 +1,120 @@
+package core
+
+import (
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// StaleDelta describes a single SpecDelta whose BaseFingerprint no longer matches
+// the current spec for its domain.
+type StaleDelta struct {
+	Domain             string
+	BaseFingerprint    string
+	CurrentFingerprint string
+	// Requirements is the number of distinct requirements touched by the delta's
+	// operations, i.e. how many requirements need re-review before apply.
+	Requirements int
+}
+
+// ChangeStaleness groups the stale deltas of a single change.
+type ChangeStaleness struct {
+	ChangeID string
+	Title    string
+	Status   string
+	Stale    []StaleDelta
+}
+
+// IsPendingChange reports whether a change has not been applied or archived yet.
+func IsPendingChange(ch *model.Change) bool {
+	switch ch.Status {
+	case "applied", "archived":
+		return false
+	}
+	return true
+}
+
+// CheckStaleness cross-checks the BaseFingerprints of every pending change against
+// the current specs and returns one entry per change that has at least one stale delta.
+// The fingerprint semantics mirror SpecMerger.Merge: deltas without a BaseFingerprint
+// and domains with no (or empty) current spec are never considered stale.
+func CheckStaleness(specs SpecManager, changes []*model.Change) ([]ChangeStaleness, error) {
+	fingerprint := cachedFingerprints(specs)
+
+	var report []ChangeStaleness
+	for _, ch := range changes {
+		if ch == nil || !IsPendingChange(ch) {
+			continue
+		}
+		stale, err := staleDeltas(ch, fingerprint)
+		if err != nil {
+			return nil, err
+		}
+		if len(stale) > 0 {
+			report = append(report, ChangeStaleness{ChangeID: ch.ID, Title: ch.Title, Status: ch.Status, Stale: stale})
+		}
+	}
+	return report, nil
+}
+
+// FindDivergedDeltas performs a pre-flight check of a single change and returns
+// every delta that SpecMerger.Merge would reject with ErrDiverged, without
+// modifying any spec. This lets callers resolve all conflicts in one pass.
+func FindDivergedDeltas(specs SpecManager, change *model.Change) ([]StaleDelta, error) {
+	if change == nil {
+		return nil, custom_errors.NewErrConflict("change cannot be nil")
+	}
+	return staleDeltas(change, cachedFingerprints(specs))
+}
+
+// cachedFingerprints returns a lookup of current spec fingerprints that reads each
+// domain at most once. Missing specs yield an empty fingerprint.
+func cachedFingerprints(specs SpecManager) func(domain string) (string, error) {
+	current := map[string]string{}
+	return func(domain string) (string, error) {
+		if fp, ok := current[domain]; ok {
+			return fp, nil
+		}
+		spec, err := specs.ReadSpec(domain)
+		if err != nil {
+			if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+				return "", err
+			}
+			spec = &model.Spec{}
+		}
+		current[domain] = spec.Fingerprint
+		return spec.Fingerprint, nil
+	}
+}
+
+func staleDeltas(ch *model.Change, fingerprint func(string) (string, error)) ([]StaleDelta, error) {
+	var stale []StaleDelta
+	for _, d := range ch.SpecDeltas {
+		if d.BaseFingerprint == "" || deltaApplied(d) || anchoredDelta(d) {
+			continue
+		}
+		fp, err := fingerprint(d.Domain)
+		if err != nil {
+			return nil, err
+		}
+		if fp == "" || fp == d.BaseFingerprint {
+			continue
+		}
+		stale = append(stale, StaleDelta{
+			Domain:             d.Domain,
+			BaseFingerprint:    d.BaseFingerprint,
+			CurrentFingerprint: fp,
+			Requirements:       countTouchedRequirements(d),
+		})
+	}
+	return stale, nil
+}
+
+// countTouchedRequirements returns the number of distinct requirement IDs referenced
+// by a delta's operations.
+func countTouchedRequirements(d model.SpecDelta) int {
+	seen := map[string]bool{}
+	for _, op := range d.Operations {
+		seen[op.Requirement.ID] = true
+	}
+	return len(seen)
+}
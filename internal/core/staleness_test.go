@@ -0,0 +1,87 @@
+package core
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+func TestCheckStaleness_ReportsOnlyPendingStaleDeltas(t *testing.T) {
+	specsDir := createTempDir(t)
+	writeFile(t, filepath.Join(specsDir, "auth", "spec.md"), []byte("# Auth\n\n### Requirement: Login\n\nUsers log in.\n"))
+	writeFile(t, filepath.Join(specsDir, "billing", "spec.md"), []byte("# Billing\n\n### Requirement: Invoice\n\nSend invoices.\n"))
+
+	sm := NewSpecManager(specsDir)
+	billing, err := sm.ReadSpec("billing")
+	if err != nil {
+		t.Fatalf("ReadSpec failed: %v", err)
+	}
+
+	changes := []*model.Change{
+		{
+			ID:     "CH-001",
+			Title:  "Stale auth change",
+			Status: "draft",
+			SpecDeltas: []model.SpecDelta{
+				{
+					Domain:          "auth",
+					BaseFingerprint: "0000000000000000",
+					Operations: []model.DeltaOperation{
+						{Type: "MODIFIED", Requirement: model.Requirement{ID: "login"}},
+						{Type: "ADDED", Requirement: model.Requirement{ID: "logout"}},
+						{Type: "MODIFIED", Requirement: model.Requirement{ID: "login"}},
+					},
+				},
+				{Domain: "billing", BaseFingerprint: billing.Fingerprint},
+			},
+		},
+		{
+			ID:         "CH-002",
+			Status:     "applied",
+			SpecDeltas: []model.SpecDelta{{Domain: "auth", BaseFingerprint: "0000000000000000"}},
+		},
+		{
+			ID:         "CH-003",
+			SpecDeltas: []model.SpecDelta{{Domain: "auth"}, {Domain: "missing", BaseFingerprint: "abc"}},
+		},
+	}
+
+	report, err := CheckStaleness(sm, changes)
+	if err != nil {
+		t.Fatalf("CheckStaleness failed: %v", err)
+	}
+	if len(report) != 1 {
+		t.Fatalf("expected 1 stale change, got %d: %+v", len(report), report)
+	}
+	if report[0].ChangeID != "CH-001" || len(report[0].Stale) != 1 {
+		t.Fatalf("unexpected report: %+v", report[0])
+	}
+	d := report[0].Stale[0]
+	if d.Domain != "auth" || d.Requirements != 2 || d.CurrentFingerprint == "" {
+		t.Fatalf("unexpected stale delta: %+v", d)
+	}
+}
+
+func TestFindDivergedDeltas_CollectsAllDomains(t *testing.T) {
+	specsDir := createTempDir(t)
+	writeFile(t, filepath.Join(specsDir, "auth", "spec.md"), []byte("# Auth\n\n### Requirement: Login\n\nUsers log in.\n"))
+	writeFile(t, filepath.Join(specsDir, "billing", "spec.md"), []byte("# Billing\n\n### Requirement: Invoice\n\nSend invoices.\n"))
+
+	sm := NewSpecManager(specsDir)
+	ch := &model.Change{
+		ID: "CH-010",
+		SpecDeltas: []model.SpecDelta{
+			{Domain: "auth", BaseFingerprint: "stale-auth"},
+			{Domain: "billing", BaseFingerprint: "stale-billing"},
+		},
+	}
+
+	diverged, err := FindDivergedDeltas(sm, ch)
+	if err != nil {
+		t.Fatalf("FindDivergedDeltas failed: %v", err)
+	}
+	if len(diverged) != 2 || diverged[0].Domain != "auth" || diverged[1].Domain != "billing" {
+		t.Fatalf("expected both domains to diverge, got %+v", diverged)
+	}
+}
@@ -0,0 +1,151 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// taskFieldKey is the form of a task field name, e.g. "sprint" or
+// "story-points".
+var taskFieldKey = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// TaskEdit is a set of label and field edits for EditTask. Empty values in
+// SetFields remove the field.
+type TaskEdit struct {
+	AddLabels    []string
+	RemoveLabels []string
+	SetFields    map[string]string
+}
+
+// ParseTaskFields parses "key=value" pairs, as given to --field. A pair with
+// an empty value ("sprint=") is kept, to clear the field.
+func ParseTaskFields(pairs []string) (map[string]string, error) {
+	fields := map[string]string{}
+	var errs custom_errors.ValidationErrors
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		switch {
+		case !ok:
+			errs.Add("field", fmt.Sprintf("expected key=value, got %q", pair))
+		case !taskFieldKey.MatchString(key):
+			errs.Add("field", fmt.Sprintf("%q is not a field name (letters, digits, '-', '_' and '.')", key))
+		default:
+			fields[key] = strings.TrimSpace(value)
+		}
+	}
+	return fields, errs.Err()
+}
+
+// validateTaskLabel checks a label: one line, and no comma, which separates
+// labels in filters and porcelain output.
+func validateTaskLabel(label string) error {
+	if label == "" || strings.ContainsAny(label, ",\n") {
+		return custom_errors.NewErrValidation("label", fmt.Sprintf("%q must be non-empty and hold no comma or line break", label))
+	}
+	return nil
+}
+
+// EditTask applies edit to task taskID of plan: labels are added (once,
+// compared case-insensitively) and removed, and fields set or cleared.
+// Nothing changes when an edit is invalid.
+func EditTask(plan *model.Plan, taskID string, edit TaskEdit) (*model.Task, error) {
+	task := findTask(plan.Tasks, taskID)
+	if task == nil {
+		return nil, custom_errors.NewErrNotFound("task", taskID)
+	}
+	for _, l := range append(append([]string{}, edit.AddLabels...), edit.RemoveLabels...) {
+		if err := validateTaskLabel(strings.TrimSpace(l)); err != nil {
+			return nil, err
+		}
+	}
+	for k := range edit.SetFields {
+		if !taskFieldKey.MatchString(k) {
+			return nil, custom_errors.NewErrValidation("field", fmt.Sprintf("%q is not a field name", k))
+		}
+	}
+
+	for _, l := range edit.AddLabels {
+		if l = strings.TrimSpace(l); !containsFold(task.Labels, l) {
+			task.Labels = append(task.Labels, l)
+		}
+	}
+	remove := trimAll(edit.RemoveLabels)
+	labels := task.Labels[:0]
+	for _, l := range task.Labels {
+		if !containsFold(remove, l) {
+			labels = append(labels, l)
+		}
+	}
+	task.Labels = labels
+	if len(task.Labels) == 0 {
+		task.Labels = nil
+	}
+
+	for k, v := range edit.SetFields {
+		if v == "" {
+			delete(task.Fields, k)
+			continue
+		}
+		if task.Fields == nil {
+			task.Fields = map[string]string{}
+		}
+		task.Fields[k] = v
+	}
+	if len(task.Fields) == 0 {
+		task.Fields = nil
+	}
+	return task, nil
+}
+
+// TaskFilter selects tasks by labels and fields. A task matches when it has
+// every label (compared case-insensitively) and every field value (the
+// value compared case-insensitively); a field wanted with an empty value
+// must be unset.
+type TaskFilter struct {
+	Labels []string
+	Fields map[string]string
+}
+
+// Matches reports whether t passes every criterion of f.
+func (f TaskFilter) Matches(t model.Task) bool {
+	for _, l := range f.Labels {
+		if !containsFold(t.Labels, strings.TrimSpace(l)) {
+			return false
+		}
+	}
+	for k, v := range f.Fields {
+		if !strings.EqualFold(t.Fields[k], v) {
+			return false
+		}
+	}
+	return true
+}
+
+// FormatTaskFields renders fields as "key=value" pairs sorted by key,
+// joined by sep.
+func FormatTaskFields(fields map[string]string, sep string) string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + fields[k]
+	}
+	return strings.Join(pairs, sep)
+}
+
+func trimAll(values []string) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = strings.TrimSpace(v)
+	}
+	return out
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+func TestEditTask(t *testing.T) {
+	plan := &model.Plan{GoalID: "001-demo", Tasks: []model.Task{{ID: "T01", Title: "API"}, {ID: "T02", Title: "UI"}}}
+
+	fields, err := ParseTaskFields([]string{"sprint=12", "owner-team = payments"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	task, err := EditTask(plan, "T01", TaskEdit{AddLabels: []string{"backend", "Tech Debt", "BACKEND"}, SetFields: fields})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(task.Labels, []string{"backend", "Tech Debt"}) || !reflect.DeepEqual(task.Fields, map[string]string{"sprint": "12", "owner-team": "payments"}) {
+		t.Fatalf("unexpected task %+v", task)
+	}
+
+	filter := TaskFilter{Labels: []string{"tech debt"}, Fields: map[string]string{"sprint": "12"}}
+	if !filter.Matches(plan.Tasks[0]) || filter.Matches(plan.Tasks[1]) {
+		t.Fatal("expected the filter to match T01 only")
+	}
+	if !(TaskFilter{Fields: map[string]string{"sprint": ""}}).Matches(plan.Tasks[1]) {
+		t.Fatal("expected an empty field value to match tasks without the field")
+	}
+
+	task, err = EditTask(plan, "T01", TaskEdit{RemoveLabels: []string{"backend", "tech debt"}, SetFields: map[string]string{"sprint": "", "owner-team": ""}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Labels != nil || task.Fields != nil {
+		t.Fatalf("expected labels and fields to be cleared, got %+v", task)
+	}
+
+	if _, err := EditTask(plan, "T01", TaskEdit{AddLabels: []string{"a,b"}}); err == nil {
+		t.Fatal("expected a label with a comma to be rejected")
+	}
+	if _, err := ParseTaskFields([]string{"sprint"}); err == nil {
+		t.Fatal("expected a field without '=' to be rejected")
+	}
+	if _, err := EditTask(plan, "T09", TaskEdit{AddLabels: []string{"x"}}); err == nil {
+		t.Fatal("expected an unknown task to be reported")
+	}
+}
+
+func TestTaskLabels_Serialization(t *testing.T) {
+	plan := &model.Plan{GoalID: "001-demo", Tasks: []model.Task{{
+		ID: "T01", Title: "API", Status: TaskStatusPending,
+		Labels: []string{"backend", "Tech Debt"}, Fields: map[string]string{"sprint": "12"},
+	}}}
+	for _, path := range []string{"plan.json", "plan.yaml", "plan.md"} {
+		data, err := encodePlan(path, plan)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got model.Plan
+		if err := decodePlan(path, data, &got); err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		if len(got.Tasks) != 1 || !reflect.DeepEqual(got.Tasks[0].Labels, plan.Tasks[0].Labels) || !reflect.DeepEqual(got.Tasks[0].Fields, plan.Tasks[0].Fields) {
+			t.Errorf("%s lost labels or fields: %+v", path, got.Tasks)
+		}
+	}
+
+	csv, err := ExportPlan(plan, PlanExportJiraCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(csv), "Issue Id,Parent Id,Issue Type,Summary,Status,Assignee,Description,Labels,Labels,Labels\n") ||
+		!strings.Contains(string(csv), ",teamwerx-001-demo,backend,Tech-Debt\n") || !strings.Contains(string(csv), "- sprint=12") {
+		t.Errorf("unexpected jira export:\n%s", csv)
+	}
+}
@@ -0,0 +1,160 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+)
+
+// Semantic output roles a theme styles.
+const (
+	ThemeRoleHeader  = "header"  // headings and table headers
+	ThemeRoleID      = "id"      // the ID or name leading a list item
+	ThemeRoleStatus  = "status"  // task, change and check statuses
+	ThemeRoleSuccess = "success" // confirmations and additions
+	ThemeRoleWarn    = "warn"    // warnings and empty results
+	ThemeRoleError   = "error"   // failures and removals
+	ThemeRoleInfo    = "info"    // progress notes
+	ThemeRoleMuted   = "muted"   // secondary details
+)
+
+// ThemeRoles lists the roles a theme styles.
+var ThemeRoles = []string{ThemeRoleHeader, ThemeRoleID, ThemeRoleStatus, ThemeRoleSuccess, ThemeRoleWarn, ThemeRoleError, ThemeRoleInfo, ThemeRoleMuted}
+
+// Built-in theme names.
+const (
+	ThemeDefault      = "default"
+	ThemeHighContrast = "high-contrast"
+	ThemeMonochrome   = "monochrome"
+)
+
+// ThemeAttributes are the text attributes a style may combine with a color.
+var ThemeAttributes = []string{"bold", "faint", "italic", "underline", "reverse"}
+
+// A style is a space-separated list of at most one color (one of
+// DiscussionColors, optionally prefixed "hi-" for the bright variant) and any
+// ThemeAttributes, e.g. "hi-red bold". "plain" is a style with neither.
+const themePlain = "plain"
+
+var builtinThemes = map[string]map[string]string{
+	ThemeDefault: {
+		ThemeRoleHeader:  "cyan bold",
+		ThemeRoleID:      "white bold",
+		ThemeRoleStatus:  "yellow",
+		ThemeRoleSuccess: "green",
+		ThemeRoleWarn:    "yellow",
+		ThemeRoleError:   "red",
+		ThemeRoleInfo:    "cyan",
+		ThemeRoleMuted:   "faint",
+	},
+	// Bright colors in bold, and nothing faint.
+	ThemeHighContrast: {
+		ThemeRoleHeader:  "hi-white bold underline",
+		ThemeRoleID:      "hi-white bold",
+		ThemeRoleStatus:  "hi-yellow bold",
+		ThemeRoleSuccess: "hi-green bold",
+		ThemeRoleWarn:    "hi-yellow bold",
+		ThemeRoleError:   "hi-red bold",
+		ThemeRoleInfo:    "hi-cyan bold",
+		ThemeRoleMuted:   "white",
+	},
+	// No colors: roles differ by attribute only.
+	ThemeMonochrome: {
+		ThemeRoleHeader:  "bold underline",
+		ThemeRoleID:      "bold",
+		ThemeRoleStatus:  "underline",
+		ThemeRoleSuccess: "bold",
+		ThemeRoleWarn:    "bold",
+		ThemeRoleError:   "bold reverse",
+		ThemeRoleInfo:    themePlain,
+		ThemeRoleMuted:   "faint",
+	},
+}
+
+// BuiltinThemes returns the names of the built-in themes, sorted.
+func BuiltinThemes() []string {
+	names := make([]string, 0, len(builtinThemes))
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ThemeConfig is the theme section of the workspace config: a built-in theme
+// to start from and per-role style overrides.
+//
+//	theme:
+//	  name: high-contrast
+//	  styles:
+//	    header: magenta bold
+//	    muted: plain
+type ThemeConfig struct {
+	Name   string            `yaml:"name,omitempty"`
+	Styles map[string]string `yaml:"styles,omitempty"`
+}
+
+// Validate checks the theme name and every role and style.
+func (c ThemeConfig) Validate() error {
+	var errs custom_errors.ValidationErrors
+	if c.Name != "" {
+		if _, ok := builtinThemes[c.Name]; !ok {
+			errs.Add("theme.name", fmt.Sprintf("unknown theme %q (want one of %s)", c.Name, strings.Join(BuiltinThemes(), ", ")))
+		}
+	}
+	roles := make([]string, 0, len(c.Styles))
+	for role := range c.Styles {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	for _, role := range roles {
+		field := "theme.styles." + role
+		if !containsString(ThemeRoles, role) {
+			errs.Add(field, fmt.Sprintf("unknown role (want one of %s)", strings.Join(ThemeRoles, ", ")))
+			continue
+		}
+		if _, _, err := ParseThemeStyle(c.Styles[role]); err != nil {
+			errs.Add(field, err.Error())
+		}
+	}
+	return errs.Err()
+}
+
+// Resolve returns the style of every role: the named built-in theme (the
+// default theme when Name is empty) with Styles overriding single roles.
+func (c ThemeConfig) Resolve() map[string]string {
+	base, ok := builtinThemes[c.Name]
+	if !ok {
+		base = builtinThemes[ThemeDefault]
+	}
+	out := make(map[string]string, len(ThemeRoles))
+	for _, role := range ThemeRoles {
+		out[role] = base[role]
+		if s, ok := c.Styles[role]; ok {
+			out[role] = s
+		}
+	}
+	return out
+}
+
+// ParseThemeStyle splits a style into its color (empty for none; "hi-"
+// prefixed for a bright color) and attributes.
+func ParseThemeStyle(style string) (color string, attrs []string, err error) {
+	for _, word := range strings.Fields(strings.ToLower(style)) {
+		switch {
+		case word == themePlain:
+		case containsString(ThemeAttributes, word):
+			attrs = append(attrs, word)
+		case containsString(DiscussionColors, strings.TrimPrefix(word, "hi-")):
+			if color != "" {
+				return "", nil, fmt.Errorf("style %q has more than one color", style)
+			}
+			color = word
+		default:
+			return "", nil, fmt.Errorf("unknown style word %q in %q (want a color, hi-<color>, %s or %s)", word, style, strings.Join(ThemeAttributes, ", "), themePlain)
+		}
+	}
+	return color, attrs, nil
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestThemeConfig_ResolveAndValidate(t *testing.T) {
+	styles := ThemeConfig{Name: ThemeMonochrome, Styles: map[string]string{ThemeRoleHeader: "magenta bold"}}.Resolve()
+	if styles[ThemeRoleHeader] != "magenta bold" || styles[ThemeRoleError] != "bold reverse" {
+		t.Fatalf("expected monochrome with the header overridden, got %v", styles)
+	}
+	if got := (ThemeConfig{}).Resolve()[ThemeRoleSuccess]; got != "green" {
+		t.Fatalf("expected the default theme when unnamed, got success %q", got)
+	}
+	for _, name := range BuiltinThemes() {
+		if err := (ThemeConfig{Name: name, Styles: builtinThemes[name]}).Validate(); err != nil {
+			t.Fatalf("built-in theme %s is invalid: %v", name, err)
+		}
+	}
+
+	color, attrs, err := ParseThemeStyle("hi-Red bold underline")
+	if err != nil || color != "hi-red" || strings.Join(attrs, ",") != "bold,underline" {
+		t.Fatalf("ParseThemeStyle = %q %v %v", color, attrs, err)
+	}
+
+	err = ThemeConfig{Name: "neon", Styles: map[string]string{"banner": "red", ThemeRoleWarn: "red blue", ThemeRoleID: "sparkly"}}.Validate()
+	if err == nil {
+		t.Fatalf("expected an invalid theme to be rejected")
+	}
+	for _, want := range []string{"theme.name", "theme.styles.banner", "theme.styles.warn", "theme.styles.id"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %s in %v", want, err)
+		}
+	}
+
+	store := storage.NewInMem()
+	if err := store.WriteFile(ConfigPath(".teamwerx"), []byte("theme:\n  styles:\n    muted: glitter\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if _, err := loadConfig(store, ConfigPath(".teamwerx")); err == nil || !strings.Contains(err.Error(), "theme.styles.muted") {
+		t.Fatalf("expected loadConfig to reject the theme, got %v", err)
+	}
+}
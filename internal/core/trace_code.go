@@ -0,0 +1,246 @@
+package core
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// codeAnnotation matches a "Code: <glob>[, <glob>...]" line, optionally as a
+// list item.
+var codeAnnotation = regexp.MustCompile(`^\s*(?:[-*+]\s+)?Code:\s*(.+?)\s*$`)
+
+// metadataLines returns the lines of a requirement block that may hold
+// metadata such as "Code:" or "Priority:", i.e. those outside fenced code
+// and editorial notes, without their line endings.
+func metadataLines(block string, offset int, notes []model.RequirementNote) []string {
+	var lines []string
+	fence := ""
+	pos := 0
+	for _, line := range strings.SplitAfter(block, "\n") {
+		start := pos
+		pos += len(line)
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		if !inNote(notes, offset+start) {
+			lines = append(lines, strings.TrimRight(line, "\r\n"))
+		}
+	}
+	return lines
+}
+
+// findCodePaths returns the globs of a requirement block's "Code:" lines.
+func findCodePaths(block string, offset int, notes []model.RequirementNote) []string {
+	var globs []string
+	for _, line := range metadataLines(block, offset, notes) {
+		m := codeAnnotation.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		for _, g := range strings.Split(m[1], ",") {
+			if g = strings.Trim(strings.TrimSpace(g), "`"); g != "" {
+				globs = append(globs, g)
+			}
+		}
+	}
+	return globs
+}
+
+// MatchCodePath reports whether the slash-separated path matches pattern.
+// Patterns use path.Match syntax per segment, plus "**" for any number of
+// directories; a pattern naming a directory matches everything under it.
+func MatchCodePath(pattern, name string) bool {
+	pattern = strings.Trim(path.Clean(filepath.ToSlash(pattern)), "/")
+	name = strings.Trim(path.Clean(filepath.ToSlash(name)), "/")
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	// A directory pattern covers the files below it.
+	return true
+}
+
+// Code trace findings.
+const (
+	TraceMissing     = "missing"     // a Code: glob matches no file
+	TraceUnspecified = "unspecified" // code changed with no spec change for its requirement
+)
+
+// CodeTraceFinding is one problem reported by TraceCode.
+type CodeTraceFinding struct {
+	Kind          string   `json:"kind"`
+	Domain        string   `json:"domain"`
+	RequirementID string   `json:"requirement_id"`
+	Priority      string   `json:"priority,omitempty"`
+	Pattern       string   `json:"pattern"`
+	Files         []string `json:"files,omitempty"` // changed files matching Pattern (unspecified only)
+}
+
+// CodeTraceOptions configures TraceCode. Root is the directory Code: globs
+// are relative to (normally the workspace root). ChangedFiles are the
+// slash-separated, Root-relative paths changed in the range being checked,
+// e.g. from `git diff --name-only`; when nil, only missing globs are reported.
+type CodeTraceOptions struct {
+	Root         string
+	ChangedFiles []string
+}
+
+// CodeTraceReport is the result of TraceCode.
+type CodeTraceReport struct {
+	Requirements int `json:"requirements"` // requirements with Code: annotations
+	// ByPriority counts the traced requirements per priority ("" for none).
+	ByPriority map[string]int     `json:"by_priority"`
+	Findings   []CodeTraceFinding `json:"findings"`
+}
+
+// TraceCode checks every requirement's Code: globs against the files under
+// opts.Root, and flags requirements whose code is among opts.ChangedFiles
+// while neither their spec file changed nor a pending change touches them.
+func (a *App) TraceCode(opts CodeTraceOptions) (*CodeTraceReport, error) {
+	root, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	files, err := listCodeFiles(root)
+	if err != nil {
+		return nil, err
+	}
+	specs, err := a.SpecManager.ListSpecs()
+	if err != nil {
+		return nil, err
+	}
+	changes, err := a.ChangeManager.ListChanges()
+	if err != nil {
+		return nil, err
+	}
+	changed := map[string]bool{}
+	for _, f := range opts.ChangedFiles {
+		changed[path.Clean(filepath.ToSlash(f))] = true
+	}
+
+	report := &CodeTraceReport{ByPriority: map[string]int{}, Findings: []CodeTraceFinding{}}
+	for _, spec := range specs {
+		specChanged := changed[relSlash(root, a.Options.Layout.SpecPath(a.Options.SpecsDir, spec.Domain))]
+		for _, r := range spec.Requirements {
+			if len(r.Code) == 0 {
+				continue
+			}
+			report.Requirements++
+			report.ByPriority[r.Priority]++
+			covered := specChanged || pendingChangeTouches(changes, spec, r.ID)
+			for _, pattern := range r.Code {
+				if !anyMatch(pattern, files) {
+					report.Findings = append(report.Findings, CodeTraceFinding{Kind: TraceMissing, Domain: spec.Domain, RequirementID: r.ID, Priority: r.Priority, Pattern: pattern})
+				}
+				if covered {
+					continue
+				}
+				var hits []string
+				for _, f := range opts.ChangedFiles {
+					if MatchCodePath(pattern, f) {
+						hits = append(hits, path.Clean(filepath.ToSlash(f)))
+					}
+				}
+				if len(hits) > 0 {
+					sort.Strings(hits)
+					report.Findings = append(report.Findings, CodeTraceFinding{Kind: TraceUnspecified, Domain: spec.Domain, RequirementID: r.ID, Priority: r.Priority, Pattern: pattern, Files: hits})
+				}
+			}
+		}
+	}
+	return report, nil
+}
+
+// listCodeFiles lists the files under root as slash-separated relative
+// paths, skipping hidden directories such as .git and .teamwerx.
+func listCodeFiles(root string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && p == root {
+				return fs.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			if p != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		files = append(files, relSlash(root, p))
+		return nil
+	})
+	return files, err
+}
+
+func relSlash(root, p string) string {
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return filepath.ToSlash(p)
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return filepath.ToSlash(p)
+	}
+	return filepath.ToSlash(rel)
+}
+
+func anyMatch(pattern string, files []string) bool {
+	for _, f := range files {
+		if MatchCodePath(pattern, f) {
+			return true
+		}
+	}
+	return false
+}
+
+// pendingChangeTouches reports whether an unarchived change modifies the
+// requirement, or adds or removes it, by its current ID or an alias.
+func pendingChangeTouches(changes []*model.Change, spec *model.Spec, reqID string) bool {
+	for _, ch := range changes {
+		for _, d := range ch.SpecDeltas {
+			if d.Domain != spec.Domain {
+				continue
+			}
+			for _, op := range d.Operations {
+				if id, _ := ResolveRequirementAlias(spec, op.Requirement.ID); id == reqID {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
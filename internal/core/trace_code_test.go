@@ -0,0 +1,92 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+func TestMatchCodePath(t *testing.T) {
+	for _, tc := range []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"internal/auth/**", "internal/auth/login.go", true},
+		{"internal/auth/**", "internal/auth/jwt/token.go", true},
+		{"internal/auth", "internal/auth/login.go", true},
+		{"internal/**/*.go", "internal/auth/jwt/token.go", true},
+		{"**/login.go", "cmd/login.go", true},
+		{"cmd/*.go", "cmd/sub/login.go", false},
+		{"internal/auth/**", "internal/authz/x.go", false},
+		{"./cmd/login.go", "cmd/login.go", true},
+	} {
+		if got := MatchCodePath(tc.pattern, tc.name); got != tc.want {
+			t.Errorf("MatchCodePath(%q, %q) = %v, want %v", tc.pattern, tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestSpecParser_CodeAnnotations(t *testing.T) {
+	spec, err := NewSpecParser().Parse([]byte("# Auth\n\n### Requirement: Login\n\nUsers log in.\nCode: internal/auth/**, `cmd/login.go`\n- Code: web/login.tsx\n\n```\nCode: not/this.go\n```\n\n> Rationale: Code: nor/this.go\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"internal/auth/**", "cmd/login.go", "web/login.tsx"}
+	if got := spec.Requirements[0].Code; !reflect.DeepEqual(got, want) {
+		t.Fatalf("code = %q", got)
+	}
+}
+
+func TestTraceCode(t *testing.T) {
+	root := createTempDir(t)
+	app, err := NewApp(AppOptions{
+		SpecsDir:   filepath.Join(root, ".teamwerx", "specs"),
+		GoalsDir:   filepath.Join(root, ".teamwerx", "goals"),
+		ChangesDir: filepath.Join(root, ".teamwerx", "changes"),
+		CharterDir: filepath.Join(root, ".teamwerx"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "internal", "auth"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "internal", "auth", "login.go"), []byte("package auth\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	content := "# Auth\n\n### Requirement: Login\n\nCode: internal/auth/**\n\n### Requirement: Logout\n\nCode: internal/logout.go\n\n### Requirement: Audit\n\nCode: internal/auth/**\n"
+	if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "auth", Content: content}); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.ChangeManager.Save(&model.Change{ID: "CH-001", Title: "Audit", Status: "draft", CreatedAt: time.Now(), SpecDeltas: []model.SpecDelta{{
+		Domain:     "auth",
+		Operations: []model.DeltaOperation{{Type: "MODIFIED", Requirement: model.Requirement{ID: "audit", Title: "Audit", Content: "### Requirement: Audit\n\nCode: internal/auth/**\nAudited.\n"}}},
+	}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := app.TraceCode(CodeTraceOptions{Root: root, ChangedFiles: []string{"internal/auth/login.go", "README.md"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []CodeTraceFinding{
+		{Kind: TraceUnspecified, Domain: "auth", RequirementID: "login", Pattern: "internal/auth/**", Files: []string{"internal/auth/login.go"}},
+		{Kind: TraceMissing, Domain: "auth", RequirementID: "logout", Pattern: "internal/logout.go"},
+	}
+	if report.Requirements != 3 || !reflect.DeepEqual(report.Findings, want) {
+		t.Fatalf("report = %+v", report)
+	}
+
+	// A change to the spec file covers every requirement in it.
+	report, err = app.TraceCode(CodeTraceOptions{Root: root, ChangedFiles: []string{"internal/auth/login.go", ".teamwerx/specs/auth/spec.md"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Findings) != 1 || report.Findings[0].Kind != TraceMissing {
+		t.Fatalf("findings with spec change = %+v", report.Findings)
+	}
+}
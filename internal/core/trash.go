@@ -0,0 +1,336 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+)
+
+// TrashDirName is the directory, under the charter directory, that holds
+// deleted specs, discussion entries and tasks until they are restored or
+// purged. Each item gets its own subdirectory with a TrashItemFileName
+// describing it and, for deleted files, the files themselves.
+const (
+	TrashDirName      = ".trash"
+	TrashItemFileName = "item.json"
+)
+
+// DefaultTrashRetention is how long trashed items are kept before
+// `teamwerx clean` and `trash purge` remove them.
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+// Kinds of trashed items.
+const (
+	TrashSpec  = "spec"
+	TrashEntry = "entry"
+	TrashTask  = "task"
+)
+
+// TrashItem describes one deleted artifact.
+type TrashItem struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"`
+	Name      string    `json:"name"` // domain, or "<goal>/<entry or task ID>"
+	DeletedAt time.Time `json:"deleted_at"`
+	// Paths are the original locations of the files moved into the trash
+	// (spec kind); the Nth is stored as "<N>" in the item directory.
+	Paths []string               `json:"paths,omitempty"`
+	Entry *model.DiscussionEntry `json:"entry,omitempty"`
+	// Tasks are a removed task followed by its subtasks.
+	Tasks []model.Task `json:"tasks,omitempty"`
+}
+
+// GoalID returns the goal an entry or task item belonged to.
+func (t TrashItem) GoalID() string {
+	if i := strings.LastIndex(t.Name, "/"); i >= 0 && t.Kind != TrashSpec {
+		return t.Name[:i]
+	}
+	return ""
+}
+
+func trashDir(o AppOptions) string {
+	return filepath.Join(o.CharterDir, TrashDirName)
+}
+
+var trashUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// newTrashItem reserves a trash directory for an item and returns the item
+// with its ID set.
+func (a *App) newTrashItem(kind, name string) (*TrashItem, string, error) {
+	now := time.Now().UTC()
+	base := now.Format("20060102T150405") + "-" + kind + "-" + trashUnsafe.ReplaceAllString(name, "-")
+	id := base
+	for i := 2; ; i++ {
+		exists, err := a.Options.Storage.Exists(filepath.Join(trashDir(a.Options), id))
+		if err != nil {
+			return nil, "", err
+		}
+		if !exists {
+			break
+		}
+		id = base + "-" + strconv.Itoa(i)
+	}
+	dir := filepath.Join(trashDir(a.Options), id)
+	if err := a.Options.Storage.MkdirAll(dir, 0o755); err != nil {
+		return nil, "", err
+	}
+	return &TrashItem{ID: id, Kind: kind, Name: name, DeletedAt: now}, dir, nil
+}
+
+func (a *App) saveTrashItem(dir string, item *TrashItem) error {
+	data, err := json.MarshalIndent(item, "", "  ")
+	if err != nil {
+		return err
+	}
+	return a.Options.Storage.WriteFile(filepath.Join(dir, TrashItemFileName), append(data, '\n'), 0o644)
+}
+
+// TrashSpec moves a spec domain into the trash: its directory when the layout
+// gives each domain one, otherwise its spec and review files.
+func (a *App) TrashSpec(domain string) (*TrashItem, error) {
+	o := a.Options
+	specPath := o.Layout.SpecPath(o.SpecsDir, domain)
+	exists, err := o.Storage.Exists(specPath)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, custom_errors.NewErrNotFound("spec", domain)
+	}
+
+	var paths []string
+	if tmpl := ownDirTemplate(o.Layout.SpecFile, domainPlaceholder); tmpl != "" {
+		paths = append(paths, filepath.Join(o.SpecsDir, expandTemplate(tmpl, domainPlaceholder, domain)))
+	} else {
+		paths = append(paths, specPath)
+		review := o.Layout.ReviewPath(o.SpecsDir, domain)
+		if ok, err := o.Storage.Exists(review); err != nil {
+			return nil, err
+		} else if ok {
+			paths = append(paths, review)
+		}
+	}
+
+	item, dir, err := a.newTrashItem(TrashSpec, domain)
+	if err != nil {
+		return nil, err
+	}
+	item.Paths = paths
+	if err := a.saveTrashItem(dir, item); err != nil {
+		return nil, err
+	}
+	for i, p := range paths {
+		if err := o.Storage.Rename(p, filepath.Join(dir, strconv.Itoa(i))); err != nil {
+			return nil, fmt.Errorf("failed to move '%s' to the trash: %w", p, err)
+		}
+	}
+	return item, nil
+}
+
+// TrashDiscussionEntry removes a discussion entry and keeps a copy in the
+// trash.
+func (a *App) TrashDiscussionEntry(goalID, entryID string) (*TrashItem, error) {
+	entry, err := a.DiscussionManager.RemoveEntry(goalID, entryID)
+	if err != nil {
+		return nil, err
+	}
+	item, dir, err := a.newTrashItem(TrashEntry, goalID+"/"+entry.ID)
+	if err != nil {
+		return nil, err
+	}
+	item.Entry = entry
+	return item, a.saveTrashItem(dir, item)
+}
+
+// TrashTask removes a task and all of its subtasks from the goal's plan and
+// keeps them in the trash.
+func (a *App) TrashTask(goalID, taskID string) (*TrashItem, error) {
+	plan, err := a.PlanManager.Load(goalID)
+	if err != nil {
+		return nil, err
+	}
+	task := findTask(plan.Tasks, taskID)
+	if task == nil {
+		return nil, custom_errors.NewErrNotFound("task", taskID)
+	}
+	removed := append([]model.Task{*task}, descendantTasks(plan.Tasks, task.ID)...)
+	gone := map[string]bool{}
+	for _, t := range removed {
+		gone[t.ID] = true
+	}
+	kept := plan.Tasks[:0]
+	for _, t := range plan.Tasks {
+		if !gone[t.ID] {
+			kept = append(kept, t)
+		}
+	}
+
+	item, dir, err := a.newTrashItem(TrashTask, goalID+"/"+removed[0].ID)
+	if err != nil {
+		return nil, err
+	}
+	item.Tasks = removed
+	// Record the item before the plan changes so a failed save loses nothing.
+	if err := a.saveTrashItem(dir, item); err != nil {
+		return nil, err
+	}
+	plan.Tasks = kept
+	if err := a.PlanManager.Save(plan); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// ListTrash returns every trashed item, oldest first. Directories without a
+// readable item file are skipped.
+func (a *App) ListTrash() ([]TrashItem, error) {
+	items := []TrashItem{}
+	root := trashDir(a.Options)
+	err := a.Options.Storage.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			if path != root && filepath.Dir(path) != root {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() != TrashItemFileName || filepath.Dir(filepath.Dir(path)) != root {
+			return nil
+		}
+		data, err := a.Options.Storage.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var item TrashItem
+		if json.Unmarshal(data, &item) == nil && item.ID != "" {
+			items = append(items, item)
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].DeletedAt.Before(items[j].DeletedAt) })
+	return items, nil
+}
+
+func (a *App) findTrashItem(id string) (*TrashItem, error) {
+	items, err := a.ListTrash()
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		if items[i].ID == id {
+			return &items[i], nil
+		}
+	}
+	return nil, custom_errors.NewErrNotFound("trash item", id)
+}
+
+// RestoreTrash puts a trashed item back where it came from and removes it
+// from the trash. It returns ErrConflict when that would overwrite something:
+// a spec path that exists again, or an entry or task ID now in use. Restored
+// tasks whose parent is gone become top-level tasks.
+func (a *App) RestoreTrash(id string) (*TrashItem, error) {
+	item, err := a.findTrashItem(id)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(trashDir(a.Options), item.ID)
+	store := a.Options.Storage
+
+	switch item.Kind {
+	case TrashSpec:
+		for _, p := range item.Paths {
+			if exists, err := store.Exists(p); err != nil {
+				return nil, err
+			} else if exists {
+				return nil, custom_errors.NewErrConflict(fmt.Sprintf("cannot restore %s: '%s' already exists", item.ID, p))
+			}
+		}
+		for i, p := range item.Paths {
+			if err := store.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+				return nil, err
+			}
+			if err := store.Rename(filepath.Join(dir, strconv.Itoa(i)), p); err != nil {
+				return nil, fmt.Errorf("failed to restore '%s': %w", p, err)
+			}
+		}
+	case TrashEntry:
+		if item.Entry == nil {
+			return nil, fmt.Errorf("trash item %s has no entry", item.ID)
+		}
+		entries, err := a.DiscussionManager.Load(item.GoalID())
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if strings.EqualFold(e.ID, item.Entry.ID) {
+				return nil, custom_errors.NewErrConflict(fmt.Sprintf("cannot restore %s: entry %s already exists", item.ID, e.ID))
+			}
+		}
+		if err := a.DiscussionManager.AddEntry(item.GoalID(), item.Entry); err != nil {
+			return nil, err
+		}
+	case TrashTask:
+		plan, err := a.PlanManager.Load(item.GoalID())
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range item.Tasks {
+			if findTask(plan.Tasks, t.ID) != nil {
+				return nil, custom_errors.NewErrConflict(fmt.Sprintf("cannot restore %s: task %s already exists", item.ID, t.ID))
+			}
+		}
+		tasks := append([]model.Task{}, item.Tasks...)
+		if len(tasks) > 0 && tasks[0].ParentID != "" && findTask(plan.Tasks, tasks[0].ParentID) == nil {
+			tasks[0].ParentID = ""
+		}
+		plan.Tasks = append(plan.Tasks, tasks...)
+		if err := a.PlanManager.Save(plan); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("trash item %s has unknown kind %q", item.ID, item.Kind)
+	}
+
+	if err := store.RemoveAll(dir); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// PurgeTrash permanently removes trashed items deleted before cutoff and
+// returns them.
+func (a *App) PurgeTrash(cutoff time.Time) ([]TrashItem, error) {
+	items, err := a.ListTrash()
+	if err != nil {
+		return nil, err
+	}
+	var purged []TrashItem
+	for _, item := range items {
+		if !item.DeletedAt.Before(cutoff) {
+			continue
+		}
+		if err := a.Options.Storage.RemoveAll(filepath.Join(trashDir(a.Options), item.ID)); err != nil {
+			return purged, err
+		}
+		purged = append(purged, item)
+	}
+	return purged, nil
+}
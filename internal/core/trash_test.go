@@ -0,0 +1,174 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestTrash_SpecRoundTrip(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "auth", Content: "# Auth\n\n### Requirement: Login\n\nUsers log in.\n"}); err != nil {
+		t.Fatal(err)
+	}
+
+	item, err := app.TrashSpec("auth")
+	if err != nil {
+		t.Fatalf("TrashSpec: %v", err)
+	}
+	if _, err := app.SpecManager.ReadSpec("auth"); err == nil {
+		t.Fatal("spec still readable after trashing")
+	}
+	items, err := app.ListTrash()
+	if err != nil || len(items) != 1 || items[0].ID != item.ID || items[0].Kind != TrashSpec {
+		t.Fatalf("ListTrash = %+v, %v", items, err)
+	}
+	drifts, err := VerifyWorkspace(app.Options)
+	if err != nil || len(drifts) != 0 {
+		t.Fatalf("drift after trashing = %+v, %v", drifts, err)
+	}
+
+	if _, err := app.RestoreTrash(item.ID); err != nil {
+		t.Fatalf("RestoreTrash: %v", err)
+	}
+	spec, err := app.SpecManager.ReadSpec("auth")
+	if err != nil || len(spec.Requirements) != 1 {
+		t.Fatalf("restored spec = %+v, %v", spec, err)
+	}
+	if items, _ := app.ListTrash(); len(items) != 0 {
+		t.Fatalf("trash not emptied: %+v", items)
+	}
+	drifts, err = VerifyWorkspace(app.Options)
+	if err != nil || len(drifts) != 0 {
+		t.Fatalf("drift after restoring = %+v, %v", drifts, err)
+	}
+}
+
+func TestTrash_EntryAndTask(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []string{"first", "second", "third"} {
+		if err := app.DiscussionManager.AddEntry("001-demo", &model.DiscussionEntry{Content: c}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entryItem, err := app.TrashDiscussionEntry("001-demo", "d02")
+	if err != nil {
+		t.Fatalf("TrashDiscussionEntry: %v", err)
+	}
+	entries, _ := app.DiscussionManager.Load("001-demo")
+	if len(entries) != 2 || entries[0].ID != "D01" || entries[1].ID != "D03" {
+		t.Fatalf("entries after delete = %+v", entries)
+	}
+
+	plan := &model.Plan{GoalID: "001-demo"}
+	parent, _ := app.PlanManager.AddTask(plan, "Parent")
+	parentID := parent.ID
+	if _, err := app.PlanManager.AddSubtask(plan, parentID, "Child"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.PlanManager.AddTask(plan, "Other"); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.PlanManager.Save(plan); err != nil {
+		t.Fatal(err)
+	}
+	taskItem, err := app.TrashTask("001-demo", parentID)
+	if err != nil {
+		t.Fatalf("TrashTask: %v", err)
+	}
+	if len(taskItem.Tasks) != 2 {
+		t.Fatalf("trashed tasks = %+v", taskItem.Tasks)
+	}
+	plan, _ = app.PlanManager.Load("001-demo")
+	if len(plan.Tasks) != 1 || plan.Tasks[0].Title != "Other" {
+		t.Fatalf("plan after remove = %+v", plan.Tasks)
+	}
+
+	for _, id := range []string{entryItem.ID, taskItem.ID} {
+		if _, err := app.RestoreTrash(id); err != nil {
+			t.Fatalf("RestoreTrash(%s): %v", id, err)
+		}
+	}
+	entries, _ = app.DiscussionManager.Load("001-demo")
+	if len(entries) != 3 || entries[2].ID != "D02" || entries[2].Content != "second" {
+		t.Fatalf("entries after restore = %+v", entries)
+	}
+	plan, _ = app.PlanManager.Load("001-demo")
+	if len(plan.Tasks) != 3 || plan.Tasks[2].ParentID != parentID {
+		t.Fatalf("plan after restore = %+v", plan.Tasks)
+	}
+
+	if _, err := app.RestoreTrash("nope"); err == nil {
+		t.Fatal("expected unknown item to fail")
+	} else if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+		t.Fatalf("expected ErrNotFound, got %T", err)
+	}
+}
+
+func TestTrash_RestoreConflictAndPurge(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	write := func() {
+		if err := app.SpecManager.WriteSpec(&model.Spec{Domain: "auth", Content: "# Auth\n"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write()
+	item, err := app.TrashSpec("auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	write()
+	if _, err := app.RestoreTrash(item.ID); err == nil {
+		t.Fatal("expected restore over an existing spec to fail")
+	} else if _, ok := err.(*custom_errors.ErrConflict); !ok {
+		t.Fatalf("expected ErrConflict, got %T", err)
+	}
+
+	if purged, err := app.PurgeTrash(item.DeletedAt); err != nil || len(purged) != 0 {
+		t.Fatalf("purge before deletion = %+v, %v", purged, err)
+	}
+	purged, err := app.PurgeTrash(time.Now().Add(time.Second))
+	if err != nil || len(purged) != 1 {
+		t.Fatalf("purge = %+v, %v", purged, err)
+	}
+	if items, _ := app.ListTrash(); len(items) != 0 {
+		t.Fatalf("trash after purge = %+v", items)
+	}
+}
+
+func TestFindGarbage_ExpiredTrash(t *testing.T) {
+	root := createTempDir(t)
+	opts := AppOptions{CharterDir: root}
+	for id, age := range map[string]time.Duration{"old": 40 * 24 * time.Hour, "new": time.Hour} {
+		dir := filepath.Join(root, TrashDirName, id)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		data := `{"id":"` + id + `","kind":"spec","name":"auth","deleted_at":"` + time.Now().Add(-age).UTC().Format(time.RFC3339) + `"}`
+		if err := os.WriteFile(filepath.Join(dir, TrashItemFileName), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	items, err := FindGarbage(opts, CleanOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Kind != GarbageExpiredTrash || filepath.Base(items[0].Path) != "old" {
+		t.Fatalf("garbage = %+v", items)
+	}
+}
@@ -0,0 +1,32 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// WorkspaceDirName is the directory that marks the root of a teamwerx workspace.
+const WorkspaceDirName = ".teamwerx"
+
+// ConfigFileName is the workspace configuration file, relative to the workspace directory.
+const ConfigFileName = "config.yaml"
+
+// FindWorkspaceRoot walks up from start looking for a directory that contains
+// a .teamwerx folder and returns that directory. It returns "" when no
+// workspace is found before reaching the filesystem root.
+func FindWorkspaceRoot(start string) string {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return ""
+	}
+	for {
+		if info, err := os.Stat(filepath.Join(dir, WorkspaceDirName)); err == nil && info.IsDir() {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,350 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/utils/git"
+	"gopkg.in/yaml.v3"
+)
+
+// Built-in workspace templates.
+const (
+	WorkspaceTemplateWebService = "web-service"
+	WorkspaceTemplateLibrary    = "library"
+	WorkspaceTemplateMLProject  = "ml-project"
+)
+
+// WorkspaceTemplates lists the built-in workspace templates.
+var WorkspaceTemplates = []string{WorkspaceTemplateWebService, WorkspaceTemplateLibrary, WorkspaceTemplateMLProject}
+
+// WorkspaceTemplateFile is the manifest at the root of a template directory
+// or repository.
+const WorkspaceTemplateFile = "template.yaml"
+
+// WorkspaceTemplate pre-populates a new workspace with a charter, spec
+// domains and goals with skeleton plans.
+type WorkspaceTemplate struct {
+	Name        string
+	Description string
+	Charter     *model.Charter
+	Specs       []model.Spec
+	Goals       []WorkspaceGoal
+}
+
+// WorkspaceGoal is a goal a workspace template creates from a goal template.
+type WorkspaceGoal struct {
+	ID       string   `yaml:"id"`
+	Title    string   `yaml:"title"`
+	Template string   `yaml:"template"` // goal template; defaults to feature
+	Tasks    []string `yaml:"tasks"`    // replace the goal template's tasks when set
+}
+
+// workspaceTemplateYAML is the template.yaml manifest. The charter and specs
+// live beside it as charter.md and specs/<domain>.md.
+type workspaceTemplateYAML struct {
+	Name        string          `yaml:"name"`
+	Description string          `yaml:"description"`
+	Goals       []WorkspaceGoal `yaml:"goals"`
+}
+
+// templateCharter builds a charter with the usual sections around a purpose
+// and the given standards.
+func templateCharter(purpose string, techStack []string, standards ...string) *model.Charter {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "# Project Charter\n\n## Purpose\n\n%s\n\n## Governance\n\n[Define decision-making processes, approval workflows, etc.]\n\n## Standards\n\n", purpose)
+	for _, s := range standards {
+		fmt.Fprintf(&buf, "- %s\n", s)
+	}
+	buf.WriteString("\n## AI Agent Instructions\n\n[Specific instructions for AI coding assistants working on this project]\n")
+	return &model.Charter{
+		Title:        "Project Charter",
+		Version:      "1.0.0",
+		Purpose:      purpose,
+		TechStack:    techStack,
+		SpecSections: DefaultSpecSections,
+		Content:      buf.String(),
+	}
+}
+
+// templateSpec scaffolds domain with an overview and one requirement per
+// title, each a single SHALL statement.
+func templateSpec(domain, overview string, requirements ...[2]string) model.Spec {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "# %s Spec\n\n## Overview\n\n%s\n\n## Requirements\n", domain, overview)
+	for _, r := range requirements {
+		fmt.Fprintf(&buf, "\n### Requirement: %s\n\n%s\n", r[0], r[1])
+	}
+	return model.Spec{Domain: domain, Content: buf.String()}
+}
+
+var builtinWorkspaceTemplates = map[string]WorkspaceTemplate{
+	WorkspaceTemplateWebService: {
+		Name:        WorkspaceTemplateWebService,
+		Description: "HTTP service with an API, authentication and operations specs",
+		Charter: templateCharter("[Describe the service, its consumers and what it must never get wrong]",
+			[]string{"Add your language, framework and datastore here"},
+			"Every endpoint is covered by the api spec before it ships",
+			"Breaking API changes go through a change proposal",
+			"Services log structured JSON and expose health checks"),
+		Specs: []model.Spec{
+			templateSpec("api", "The public HTTP interface of the service.",
+				[2]string{"Versioned Endpoints", "The service SHALL expose every endpoint under a versioned path prefix."},
+				[2]string{"Error Responses", "The service SHALL return errors as JSON with a stable error code and message."}),
+			templateSpec("auth", "How clients authenticate and what they are allowed to do.",
+				[2]string{"Authenticated Requests", "The service SHALL reject requests without valid credentials with 401."}),
+			templateSpec("operations", "Running the service in production.",
+				[2]string{"Health Check", "The service SHALL answer GET /healthz with 200 when it can serve traffic."},
+				[2]string{"Structured Logging", "The service SHALL log one JSON object per request."}),
+		},
+		Goals: []WorkspaceGoal{
+			{ID: "001-service-skeleton", Title: "Service skeleton", Template: GoalTemplateFeature,
+				Tasks: []string{"Choose framework and layout", "Add health check endpoint", "Add structured logging", "Set up CI", "Document local development"}},
+		},
+	},
+	WorkspaceTemplateLibrary: {
+		Name:        WorkspaceTemplateLibrary,
+		Description: "Reusable library with a public API and release process",
+		Charter: templateCharter("[Describe what the library does and who depends on it]",
+			[]string{"Add your language and package manager here"},
+			"The public API follows semantic versioning",
+			"Every exported symbol is documented",
+			"Changes to the public API go through a change proposal"),
+		Specs: []model.Spec{
+			templateSpec("api", "The exported surface of the library.",
+				[2]string{"Documented Exports", "The library SHALL document every exported function and type."},
+				[2]string{"Stable Errors", "The library SHALL return typed errors callers can match on."}),
+			templateSpec("compatibility", "What users can rely on across releases.",
+				[2]string{"Semantic Versioning", "The library SHALL only remove or change exported behavior in a major release."}),
+		},
+		Goals: []WorkspaceGoal{
+			{ID: "001-first-release", Title: "First release", Template: GoalTemplateFeature,
+				Tasks: []string{"Define the public API", "Implement core functionality", "Add tests and examples", "Write README and changelog", "Publish v0.1.0"}},
+		},
+	},
+	WorkspaceTemplateMLProject: {
+		Name:        WorkspaceTemplateMLProject,
+		Description: "Machine learning project with data, training and evaluation specs",
+		Charter: templateCharter("[Describe the prediction task, its users and the metric that matters]",
+			[]string{"Add your language, ML framework and experiment tracker here"},
+			"Every experiment is reproducible from a commit and a config",
+			"Datasets are versioned and never modified in place",
+			"Models ship only after evaluation against the baseline"),
+		Specs: []model.Spec{
+			templateSpec("data", "Where training and evaluation data comes from and how it is prepared.",
+				[2]string{"Versioned Datasets", "The project SHALL record the version of every dataset used for training."},
+				[2]string{"Held-out Evaluation Set", "The project SHALL keep an evaluation set that is never used for training."}),
+			templateSpec("training", "How models are trained.",
+				[2]string{"Reproducible Runs", "Training SHALL record its config, seed and code version with every run."}),
+			templateSpec("evaluation", "How models are judged before release.",
+				[2]string{"Baseline Comparison", "Evaluation SHALL report every metric for the candidate and the current baseline."}),
+		},
+		Goals: []WorkspaceGoal{
+			{ID: "001-baseline-model", Title: "Baseline model", Template: GoalTemplateSpike,
+				Tasks: []string{"Define the task and success metric", "Assemble and version the dataset", "Train a baseline", "Evaluate and record results"}},
+		},
+	},
+}
+
+// ResolveWorkspaceTemplate returns the template named by ref: a built-in
+// template name, a local template directory, or a git URL, which is cloned
+// and read like a directory. A template directory holds template.yaml and
+// optionally charter.md and specs/<domain>.md.
+func ResolveWorkspaceTemplate(ctx context.Context, ref string) (*WorkspaceTemplate, error) {
+	ref = strings.TrimSpace(ref)
+	if tmpl, ok := builtinWorkspaceTemplates[ref]; ok {
+		return &tmpl, nil
+	}
+	if info, err := os.Stat(ref); err == nil && info.IsDir() {
+		return LoadWorkspaceTemplateDir(ref)
+	}
+	if !isGitURL(ref) {
+		return nil, custom_errors.NewErrNotFound("workspace template", ref)
+	}
+	tmp, err := os.MkdirTemp("", "teamwerx-template-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+	dir := filepath.Join(tmp, "template")
+	if err := git.Clone(ctx, ref, dir); err != nil {
+		return nil, err
+	}
+	return LoadWorkspaceTemplateDir(dir)
+}
+
+// isGitURL reports whether ref looks like something git can clone.
+func isGitURL(ref string) bool {
+	return strings.Contains(ref, "://") || strings.HasPrefix(ref, "git@") || strings.HasSuffix(ref, ".git")
+}
+
+// LoadWorkspaceTemplateDir reads a template directory.
+func LoadWorkspaceTemplateDir(dir string) (*WorkspaceTemplate, error) {
+	data, err := os.ReadFile(filepath.Join(dir, WorkspaceTemplateFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, custom_errors.NewErrNotFound("workspace template manifest", filepath.Join(dir, WorkspaceTemplateFile))
+		}
+		return nil, err
+	}
+	var manifest workspaceTemplateYAML
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, custom_errors.NewErrValidation(WorkspaceTemplateFile, err.Error())
+	}
+	tmpl := &WorkspaceTemplate{
+		Name:        strings.TrimSpace(manifest.Name),
+		Description: strings.TrimSpace(manifest.Description),
+		Goals:       manifest.Goals,
+	}
+	if tmpl.Name == "" {
+		tmpl.Name = filepath.Base(dir)
+	}
+	// Templates may come from any git URL: a goal ID must name a directory
+	// inside the goals directory, never a path out of it.
+	for i, g := range tmpl.Goals {
+		id := strings.TrimSpace(g.ID)
+		if strings.ContainsAny(id, `/\`) || strings.HasPrefix(id, ".") {
+			return nil, custom_errors.NewErrValidation(fmt.Sprintf("goals[%d].id", i), fmt.Sprintf("%q must be a plain name without path separators or a leading '.'", id))
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, "charter.md")); err == nil {
+		if tmpl.Charter, err = parseCharterFile(data); err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "specs", "*.md"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		tmpl.Specs = append(tmpl.Specs, model.Spec{
+			Domain:  strings.TrimSuffix(filepath.Base(f), ".md"),
+			Content: string(data),
+		})
+	}
+	return tmpl, nil
+}
+
+// WorkspaceTemplateResult lists what ApplyWorkspaceTemplate created and what
+// it left alone because the workspace already had it.
+type WorkspaceTemplateResult struct {
+	Charter        *model.Charter
+	Specs          []*model.Spec
+	Goals          []*model.Plan
+	SkippedCharter bool
+	SkippedSpecs   []string
+	SkippedGoals   []string
+}
+
+// ApplyWorkspaceTemplate writes tmpl's charter, specs and goals into the
+// workspace. It only ever adds: an existing charter, spec domain or goal is
+// kept and reported as skipped, so applying a template twice is harmless.
+func (a *App) ApplyWorkspaceTemplate(tmpl *WorkspaceTemplate) (*WorkspaceTemplateResult, error) {
+	res := &WorkspaceTemplateResult{}
+
+	if tmpl.Charter != nil {
+		if a.CharterManager.Exists() {
+			res.SkippedCharter = true
+		} else {
+			charter := *tmpl.Charter
+			if err := a.CharterManager.Write(&charter); err != nil {
+				return nil, fmt.Errorf("failed to write charter: %w", err)
+			}
+			res.Charter = &charter
+		}
+	}
+
+	for _, s := range tmpl.Specs {
+		if _, err := a.SpecManager.ReadSpec(s.Domain); err == nil {
+			res.SkippedSpecs = append(res.SkippedSpecs, s.Domain)
+			continue
+		} else if _, ok := err.(*custom_errors.ErrNotFound); !ok {
+			return nil, fmt.Errorf("failed to check spec %s: %w", s.Domain, err)
+		}
+		spec := &model.Spec{Domain: s.Domain, Content: reformatRequirementHeadings(s.Content, a.Options.SpecFormat)}
+		if err := a.SpecManager.WriteSpec(spec); err != nil {
+			return nil, fmt.Errorf("failed to write spec %s: %w", s.Domain, err)
+		}
+		res.Specs = append(res.Specs, spec)
+	}
+
+	var charter *model.Charter
+	if a.CharterManager.Exists() {
+		var err error
+		if charter, err = a.CharterManager.ReadEffective(); err != nil {
+			return nil, fmt.Errorf("failed to read charter: %w", err)
+		}
+	}
+	for _, g := range tmpl.Goals {
+		name := strings.TrimSpace(g.Template)
+		if name == "" {
+			name = GoalTemplateFeature
+		}
+		goalTmpl, err := ResolveGoalTemplate(name, charter)
+		if err != nil {
+			return nil, err
+		}
+		if len(g.Tasks) > 0 {
+			goalTmpl.Tasks = g.Tasks
+		}
+		id := strings.TrimSpace(g.ID)
+		if id == "" {
+			existing, err := ListGoalIDs(a.Options)
+			if err != nil {
+				return nil, err
+			}
+			id = NextGoalID(existing, g.Title)
+		}
+		plan, err := CreateGoal(a.PlanManager, a.DiscussionManager, id, g.Title, goalTmpl)
+		if err != nil {
+			if _, ok := err.(*custom_errors.ErrConflict); ok {
+				res.SkippedGoals = append(res.SkippedGoals, id)
+				continue
+			}
+			return nil, fmt.Errorf("failed to create goal %s: %w", id, err)
+		}
+		res.Goals = append(res.Goals, plan)
+	}
+	return res, nil
+}
+
+// reformatRequirementHeadings rewrites the default "### Requirement: <title>"
+// headings of content as format says, so template specs match the
+// workspace's spec_format.
+func reformatRequirementHeadings(content string, format SpecFormat) string {
+	format = format.WithDefaults()
+	if format == DefaultSpecFormat() {
+		return content
+	}
+	ranges := scanRequirementRanges([]byte(content), DefaultSpecFormat())
+	var b strings.Builder
+	pos := 0
+	for _, r := range ranges {
+		line, _, _ := strings.Cut(content[r.Start:], "\n")
+		level, text := atxHeading(line)
+		title, ok := DefaultSpecFormat().requirementTitle(level, text)
+		if !ok {
+			continue
+		}
+		b.WriteString(content[pos:r.Start])
+		b.WriteString(format.RequirementHeading(title))
+		pos = r.Start + len(line)
+	}
+	b.WriteString(content[pos:])
+	return b.String()
+}
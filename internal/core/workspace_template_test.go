@@ -0,0 +1,104 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestApplyWorkspaceTemplate_BuiltinOnlyAdds(t *testing.T) {
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatalf("NewApp: %v", err)
+	}
+	tmpl, err := ResolveWorkspaceTemplate(context.Background(), WorkspaceTemplateWebService)
+	if err != nil {
+		t.Fatalf("ResolveWorkspaceTemplate: %v", err)
+	}
+	res, err := app.ApplyWorkspaceTemplate(tmpl)
+	if err != nil {
+		t.Fatalf("ApplyWorkspaceTemplate: %v", err)
+	}
+	if res.Charter == nil || len(res.Specs) != 3 || len(res.Goals) != 1 {
+		t.Fatalf("expected charter, 3 specs and 1 goal, got %+v", res)
+	}
+	spec, err := app.SpecManager.ReadSpec("api")
+	if err != nil || len(spec.Requirements) != 2 {
+		t.Fatalf("expected api spec with 2 requirements, got %+v (err %v)", spec, err)
+	}
+	plan, err := app.PlanManager.Load("001-service-skeleton")
+	if err != nil || len(plan.Tasks) != 5 {
+		t.Fatalf("expected goal with 5 tasks, got %+v (err %v)", plan, err)
+	}
+
+	again, err := app.ApplyWorkspaceTemplate(tmpl)
+	if err != nil {
+		t.Fatalf("second ApplyWorkspaceTemplate: %v", err)
+	}
+	if again.Charter != nil || len(again.Specs) != 0 || len(again.Goals) != 0 ||
+		!again.SkippedCharter || len(again.SkippedSpecs) != 3 || len(again.SkippedGoals) != 1 {
+		t.Fatalf("expected everything kept on the second run, got %+v", again)
+	}
+}
+
+func TestResolveWorkspaceTemplate_Directory(t *testing.T) {
+	dir := createTempDir(t)
+	files := map[string]string{
+		WorkspaceTemplateFile: "name: acme\ndescription: Acme services\ngoals:\n  - title: Onboard team\n    template: spike\n",
+		"charter.md":          "---\ntitle: Acme Charter\npurpose: Ship it\n---\n# Acme\n",
+		"specs/billing.md":    "# Billing\n\n### Requirement: Invoices\n\nThe system SHALL send invoices.\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tmpl, err := ResolveWorkspaceTemplate(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("ResolveWorkspaceTemplate: %v", err)
+	}
+	if tmpl.Name != "acme" || tmpl.Charter == nil || tmpl.Charter.Title != "Acme Charter" || len(tmpl.Specs) != 1 || tmpl.Specs[0].Domain != "billing" {
+		t.Fatalf("unexpected template: %+v", tmpl)
+	}
+
+	app, err := NewApp(AppOptions{Storage: storage.NewInMem()})
+	if err != nil {
+		t.Fatalf("NewApp: %v", err)
+	}
+	res, err := app.ApplyWorkspaceTemplate(tmpl)
+	if err != nil {
+		t.Fatalf("ApplyWorkspaceTemplate: %v", err)
+	}
+	if len(res.Goals) != 1 || res.Goals[0].GoalID != "001-onboard-team" || len(res.Goals[0].Tasks) != 3 {
+		t.Fatalf("expected a numbered spike goal, got %+v", res.Goals)
+	}
+
+	if _, err := ResolveWorkspaceTemplate(context.Background(), "no-such-template"); err == nil {
+		t.Fatalf("expected an unknown template name to fail")
+	}
+}
+
+func TestLoadWorkspaceTemplateDir_RejectsEscapingGoalIDs(t *testing.T) {
+	for _, id := range []string{"../../../escaped", "goals/nested", `..\escaped`, ".hidden"} {
+		dir := createTempDir(t)
+		manifest := "name: evil\ngoals:\n  - id: \"" + strings.ReplaceAll(id, `\`, `\\`) + "\"\n    title: Escape\n"
+		if err := os.WriteFile(filepath.Join(dir, WorkspaceTemplateFile), []byte(manifest), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadWorkspaceTemplateDir(dir); err == nil {
+			t.Errorf("expected goal ID %q to be rejected", id)
+		} else if _, ok := err.(*custom_errors.ErrValidation); !ok {
+			t.Errorf("goal ID %q: expected ErrValidation, got %T %v", id, err, err)
+		}
+	}
+}
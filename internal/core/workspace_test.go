@@ -0,0 +1,23 @@
+package core
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestFindWorkspaceRoot_WalksUp(t *testing.T) {
+	root := createTempDir(t)
+	mkdirAll(t, filepath.Join(root, WorkspaceDirName))
+	nested := filepath.Join(root, "a", "b")
+	mkdirAll(t, nested)
+
+	got := FindWorkspaceRoot(nested)
+	want, _ := filepath.Abs(root)
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	if got := FindWorkspaceRoot(createTempDir(t)); got != "" {
+		t.Fatalf("expected no workspace, got %q", got)
+	}
+}
@@ -0,0 +1,229 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+// A long-running process such as the server can take the workspace writer
+// lease: a lock file in .teamwerx/.local that it refreshes with a heartbeat.
+// While the lease is live, every other process opening the workspace fails
+// fast on its first write instead of interleaving with the owner's. A lease
+// whose heartbeat stopped (the owner crashed) expires and is ignored.
+//
+// The lease freezes the workspace for everyone but its holder: it does not
+// route their writes to the holder. serve web --exclusive, the only holder
+// today, is read-only, so while it runs the workspace cannot be changed.
+
+const (
+	// WriterLeaseFileName is the lease file under the .local directory.
+	WriterLeaseFileName = "writer.lease"
+	// DefaultWriterLeaseTTL is how long a lease stays live without a heartbeat.
+	DefaultWriterLeaseTTL = 15 * time.Second
+)
+
+// WriterLease is the content of the lease file.
+type WriterLease struct {
+	Owner      string    `json:"owner"` // e.g. "serve web"
+	PID        int       `json:"pid"`
+	Host       string    `json:"host"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Live reports whether the lease was refreshed recently enough to hold.
+func (l WriterLease) Live(now time.Time) bool {
+	return now.Before(l.ExpiresAt)
+}
+
+// Mine reports whether the current process holds the lease.
+func (l WriterLease) Mine() bool {
+	host, _ := os.Hostname()
+	return l.PID == os.Getpid() && l.Host == host
+}
+
+func (l WriterLease) String() string {
+	return fmt.Sprintf("%s (pid %d on %s, since %s)", l.Owner, l.PID, l.Host, l.AcquiredAt.Local().Format("2006-01-02 15:04:05"))
+}
+
+func writerLeasePath(charterDir string) string {
+	return filepath.Join(charterDir, LocalDirName, WriterLeaseFileName)
+}
+
+// readWriterLease returns the lease recorded in the workspace, or nil when
+// there is none. A lease file that cannot be parsed is an error, not a
+// missing lease, so that writes never proceed on a guess.
+func readWriterLease(store storage.Storage, charterDir string) (*WriterLease, error) {
+	path := writerLeasePath(charterDir)
+	data, err := store.ReadFile(path)
+	if err != nil {
+		if _, ok := err.(*custom_errors.ErrNotFound); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var l WriterLease
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("failed to parse writer lease '%s' (remove it if no teamwerx server is running): %w", path, err)
+	}
+	return &l, nil
+}
+
+// WriterLease returns the live lease another process holds on the
+// workspace, or nil.
+func (a *App) WriterLease() (*WriterLease, error) {
+	l, err := readWriterLease(a.Options.Storage, a.Options.CharterDir)
+	if err != nil || l == nil || !l.Live(time.Now()) || l.Mine() {
+		return nil, err
+	}
+	return l, nil
+}
+
+// AcquireWriterLease makes the current process the workspace's only writer
+// until the returned release function is called, refreshing the lease every
+// third of ttl (DefaultWriterLeaseTTL when zero). It is an ErrConflict when
+// another process holds a live lease.
+//
+// A failed refresh is retried on the next heartbeat. When the lease expires
+// before a refresh succeeds, the heartbeat stops and the error is sent on
+// lost: the lease is gone and the caller must stop acting as the owner.
+func (a *App) AcquireWriterLease(owner string, ttl time.Duration) (release func() error, lost <-chan error, err error) {
+	if ttl <= 0 {
+		ttl = DefaultWriterLeaseTTL
+	}
+	if held, err := a.WriterLease(); err != nil {
+		return nil, nil, err
+	} else if held != nil {
+		return nil, nil, custom_errors.NewErrConflict(fmt.Sprintf("the workspace is already owned by %s", held))
+	}
+	store, charterDir := a.Options.Storage, a.Options.CharterDir
+	if err := ensureLocalDir(store, charterDir); err != nil {
+		return nil, nil, err
+	}
+	path := writerLeasePath(charterDir)
+	unlock, err := store.Lock(path, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+	// Re-check under the lock: another process may have just taken it.
+	held, err := readWriterLease(store, charterDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if held != nil && held.Live(time.Now()) && !held.Mine() {
+		return nil, nil, custom_errors.NewErrConflict(fmt.Sprintf("the workspace is already owned by %s", held))
+	}
+
+	host, _ := os.Hostname()
+	now := time.Now().UTC()
+	lease := WriterLease{Owner: owner, PID: os.Getpid(), Host: host, AcquiredAt: now, ExpiresAt: now.Add(ttl)}
+	write := func() error {
+		data, err := json.MarshalIndent(lease, "", "  ")
+		if err != nil {
+			return err
+		}
+		return store.WriteFile(path, append(data, '\n'), 0o644)
+	}
+	if err := write(); err != nil {
+		return nil, nil, err
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	lostc := make(chan error, 1)
+	go func() {
+		defer close(done)
+		tick := time.NewTicker(ttl / 3)
+		defer tick.Stop()
+		expires := lease.ExpiresAt
+		for {
+			select {
+			case <-stop:
+				return
+			case t := <-tick.C:
+				lease.ExpiresAt = t.UTC().Add(ttl)
+				if err := write(); err == nil {
+					expires = lease.ExpiresAt
+				} else if !t.Before(expires) {
+					lostc <- fmt.Errorf("lost the writer lease: %w", err)
+					return
+				}
+			}
+		}
+	}()
+	var once sync.Once
+	return func() error {
+		var err error
+		once.Do(func() {
+			close(stop)
+			<-done
+			err = store.RemoveAll(path)
+		})
+		return err
+	}, lostc, nil
+}
+
+// leaseGuardStorage fails every write with an ErrConflict while another
+// process holds a live writer lease on the workspace. Writes to the lease
+// file itself go through, so an expired lease can be taken over.
+type leaseGuardStorage struct {
+	storage.Storage
+	charterDir string
+}
+
+func (s leaseGuardStorage) check(op, path string) error {
+	if filepath.Clean(path) == filepath.Clean(writerLeasePath(s.charterDir)) {
+		return nil
+	}
+	l, err := readWriterLease(s.Storage, s.charterDir)
+	if err != nil {
+		return fmt.Errorf("cannot %s %s: %w", op, path, err)
+	}
+	if l == nil || !l.Live(time.Now()) || l.Mine() {
+		return nil
+	}
+	return custom_errors.NewErrConflict(fmt.Sprintf("cannot %s %s: the workspace is owned by %s; stop it and retry", op, path, l))
+}
+
+func (s leaseGuardStorage) WriteFile(path string, data []byte, perm os.FileMode) error {
+	if err := s.check("write", path); err != nil {
+		return err
+	}
+	return s.Storage.WriteFile(path, data, perm)
+}
+
+func (s leaseGuardStorage) MkdirAll(path string, perm os.FileMode) error {
+	if err := s.check("create", path); err != nil {
+		return err
+	}
+	return s.Storage.MkdirAll(path, perm)
+}
+
+func (s leaseGuardStorage) Rename(oldpath, newpath string) error {
+	if err := s.check("move", oldpath); err != nil {
+		return err
+	}
+	return s.Storage.Rename(oldpath, newpath)
+}
+
+func (s leaseGuardStorage) RemoveAll(path string) error {
+	if err := s.check("remove", path); err != nil {
+		return err
+	}
+	return s.Storage.RemoveAll(path)
+}
+
+func (s leaseGuardStorage) CopyDir(src, dst string) error {
+	if err := s.check("copy", src); err != nil {
+		return err
+	}
+	return s.Storage.CopyDir(src, dst)
+}
@@ -0,0 +1,112 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	custom_errors "github.com/teamwerx/teamwerx/internal/errors"
+	"github.com/teamwerx/teamwerx/internal/model"
+	"github.com/teamwerx/teamwerx/internal/storage"
+)
+
+func TestWriterLease_OtherProcessesFailFast(t *testing.T) {
+	store := storage.NewInMem()
+	app, err := NewApp(AppOptions{Storage: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := writerLeasePath(app.Options.CharterDir)
+	foreign := func(expires time.Time) {
+		data, _ := json.Marshal(WriterLease{Owner: "serve web", PID: os.Getpid() + 1, Host: "elsewhere", AcquiredAt: time.Now(), ExpiresAt: expires})
+		if err := store.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	spec := &model.Spec{Domain: "auth", Content: "# Auth\n"}
+
+	foreign(time.Now().Add(time.Minute))
+	err = app.SpecManager.WriteSpec(spec)
+	if _, ok := err.(*custom_errors.ErrConflict); !ok {
+		t.Fatalf("expected a write under another process's lease to conflict, got %v", err)
+	}
+	if held, err := app.WriterLease(); err != nil || held == nil || held.Owner != "serve web" {
+		t.Fatalf("expected the foreign lease, got %+v (err %v)", held, err)
+	}
+	if _, _, err := app.AcquireWriterLease("cli", 0); err == nil {
+		t.Fatal("expected acquiring a held lease to fail")
+	}
+
+	// An expired lease no longer blocks and can be taken over.
+	foreign(time.Now().Add(-time.Second))
+	if err := app.SpecManager.WriteSpec(spec); err != nil {
+		t.Fatalf("write after the lease expired: %v", err)
+	}
+	release, _, err := app.AcquireWriterLease("serve web", time.Minute)
+	if err != nil {
+		t.Fatalf("AcquireWriterLease: %v", err)
+	}
+	if held, _ := app.WriterLease(); held != nil {
+		t.Fatalf("expected our own lease not to be reported as foreign, got %+v", held)
+	}
+	if err := app.SpecManager.WriteSpec(spec); err != nil {
+		t.Fatalf("the lease holder must be able to write: %v", err)
+	}
+	if err := release(); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if ok, _ := store.Exists(path); ok {
+		t.Fatal("expected release to remove the lease file")
+	}
+
+	// An unreadable lease blocks writes rather than being ignored.
+	if err := store.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.SpecManager.WriteSpec(spec); err == nil {
+		t.Fatal("expected a write under a corrupt lease to fail")
+	}
+	if err := app.Options.Storage.MkdirAll("elsewhere", 0o755); err == nil {
+		t.Fatal("expected MkdirAll under a corrupt lease to fail")
+	}
+}
+
+// failingLeaseStorage fails writes to the lease file once broken is set.
+type failingLeaseStorage struct {
+	storage.Storage
+	broken *int32
+}
+
+func (s failingLeaseStorage) WriteFile(path string, data []byte, perm os.FileMode) error {
+	if atomic.LoadInt32(s.broken) == 1 && filepath.Base(path) == WriterLeaseFileName {
+		return errors.New("disk full")
+	}
+	return s.Storage.WriteFile(path, data, perm)
+}
+
+func TestWriterLease_ReportsLostHeartbeat(t *testing.T) {
+	var broken int32
+	app, err := NewApp(AppOptions{Storage: failingLeaseStorage{Storage: storage.NewInMem(), broken: &broken}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	release, lost, err := app.AcquireWriterLease("serve web", 30*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	atomic.StoreInt32(&broken, 1)
+	select {
+	case err := <-lost:
+		if err == nil || !strings.Contains(err.Error(), "disk full") {
+			t.Fatalf("unexpected lost error %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the failed heartbeat to be reported")
+	}
+}
@@ -1,6 +1,10 @@
 package errors
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 // ErrNotFound is returned when a resource is not found.
 type ErrNotFound struct {
@@ -31,6 +35,22 @@ func NewErrConflict(message string) error {
 	return &ErrConflict{Message: message}
 }
 
+// ErrReadOnly is returned when a write is attempted on a workspace opened in
+// read-only mode.
+type ErrReadOnly struct {
+	Op   string // the refused operation, e.g. "write" or "remove"
+	Path string
+}
+
+func (e *ErrReadOnly) Error() string {
+	return fmt.Sprintf("workspace is read-only: refusing to %s '%s'", e.Op, e.Path)
+}
+
+// NewErrReadOnly creates a new ErrReadOnly.
+func NewErrReadOnly(op, path string) error {
+	return &ErrReadOnly{Op: op, Path: path}
+}
+
 // ErrDiverged is returned when a spec (or other artifact) has diverged from the
 // expected base fingerprint. This is useful to detect concurrent edits and
 // prevent silent overwrites during merges.
@@ -57,3 +77,66 @@ func NewErrDiverged(domain, baseFP, currentFP, reason string) error {
 		Reason:             reason,
 	}
 }
+
+// ErrValidation is returned when user input, a config file or an artifact
+// fails validation. Field is the path to the offending value, e.g.
+// "layout.plan_file" or "spec_deltas[0].domain"; it is empty when the
+// problem is not tied to one field.
+type ErrValidation struct {
+	Field  string `json:"field,omitempty"`
+	Reason string `json:"reason"`
+}
+
+func (e *ErrValidation) Error() string {
+	if e.Field == "" {
+		return e.Reason
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Reason)
+}
+
+// NewErrValidation creates a new ErrValidation.
+func NewErrValidation(field, reason string) error {
+	return &ErrValidation{Field: field, Reason: reason}
+}
+
+// ValidationErrors collects every problem found in one input, so callers can
+// report them all at once instead of fixing one per run.
+type ValidationErrors []*ErrValidation
+
+func (v ValidationErrors) Error() string {
+	if len(v) == 1 {
+		return v[0].Error()
+	}
+	parts := make([]string, len(v))
+	for i, e := range v {
+		parts[i] = e.Error()
+	}
+	return fmt.Sprintf("%d validation errors: %s", len(v), strings.Join(parts, "; "))
+}
+
+// Add records a problem with field.
+func (v *ValidationErrors) Add(field, reason string) {
+	*v = append(*v, &ErrValidation{Field: field, Reason: reason})
+}
+
+// Err returns v as an error, or nil when nothing was recorded.
+func (v ValidationErrors) Err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return v
+}
+
+// Validation returns the validation problems carried by err or anything it
+// wraps, or nil when err is not a validation error.
+func Validation(err error) []*ErrValidation {
+	var many ValidationErrors
+	if errors.As(err, &many) {
+		return many
+	}
+	var one *ErrValidation
+	if errors.As(err, &one) {
+		return []*ErrValidation{one}
+	}
+	return nil
+}
@@ -16,10 +16,23 @@ type Project struct {
 
 // DiscussionEntry represents a single entry in a goal's discussion log.
 type DiscussionEntry struct {
-	ID        string    `json:"id"`
-	Type      string    `json:"type"` // e.g., "discussion", "reflection", "issue-correction"
-	Content   string    `json:"content"`
-	Timestamp time.Time `json:"timestamp"`
+	ID          string       `json:"id"`
+	Type        string       `json:"type"` // e.g., "discussion", "reflection", "issue-correction"
+	Author      string       `json:"author,omitempty"`
+	Content     string       `json:"content"`
+	Timestamp   time.Time    `json:"timestamp"`
+	Attachments []Attachment `json:"attachments,omitempty"`
+	TaskID      string       `json:"task_id,omitempty"` // set for comments on a specific plan task
+	// Refs link the entry to tasks of its goal (e.g. "T03"), changes
+	// ("CH-001") and requirements ("auth/login").
+	Refs []string `json:"refs,omitempty"`
+}
+
+// Attachment describes a file stored alongside a goal's discussion log.
+type Attachment struct {
+	Name string `json:"name"` // original file name
+	Path string `json:"path"` // path relative to the goal directory, e.g. "attachments/log.txt"
+	Size int64  `json:"size"`
 }
 
 // Goal represents a high-level objective.
@@ -35,49 +48,248 @@ type Plan struct {
 	GoalID    string    `json:"goal_id"`
 	Tasks     []Task    `json:"tasks"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// Status is "completed" once the goal has been completed; empty while active.
+	Status      string     `json:"status,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	// Progress is recomputed from Tasks on every load and save; it is persisted
+	// only so other tools can read it without counting tasks themselves.
+	Progress PlanProgress `json:"progress"`
+}
+
+// PlanProgress summarizes task statuses in a plan.
+type PlanProgress struct {
+	Total      int `json:"total"`
+	Completed  int `json:"completed"`
+	Waived     int `json:"waived,omitempty"`
+	InProgress int `json:"in_progress"`
+	Blocked    int `json:"blocked"`
+	Pending    int `json:"pending"` // any other status, including "pending"
+}
+
+// Done reports whether every task is completed or waived.
+func (p PlanProgress) Done() bool {
+	return p.Completed+p.Waived == p.Total
 }
 
 // Task represents a single work item in a plan.
 type Task struct {
-	ID     string `json:"id"`
-	Title  string `json:"title"`
-	Status string `json:"status"` // e.g., "pending", "in-progress", "completed"
+	ID           string   `json:"id"`
+	Title        string   `json:"title"`
+	Status       string   `json:"status"`                  // e.g., "pending", "in-progress", "review", "blocked", "completed", "waived"
+	ParentID     string   `json:"parent_id,omitempty"`     // ID of the parent task for subtasks
+	Assignee     string   `json:"assignee,omitempty"`      // e.g., "@alice"
+	Requirements []string `json:"requirements,omitempty"`  // linked requirements as "<domain>/<requirement-id>"
+	WaiverReason string   `json:"waiver_reason,omitempty"` // why a "waived" task was skipped
+	// CompletedAt and CompletedBy record when and by whom the task was completed.
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CompletedBy string     `json:"completed_by,omitempty"`
+	// Blocks records each time the task was blocked and unblocked, oldest
+	// first; the last one is open while the task is blocked.
+	Blocks []TaskBlock `json:"blocks,omitempty"`
+	// Labels are free-form tags, e.g. "backend"; Fields are team-defined
+	// key/value attributes, e.g. sprint=12. teamwerx only stores and
+	// filters them.
+	Labels []string          `json:"labels,omitempty"`
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// TaskBlock is one period a task was blocked.
+type TaskBlock struct {
+	Reason    string    `json:"reason"`
+	BlockedAt time.Time `json:"blocked_at"`
+	BlockedBy string    `json:"blocked_by,omitempty"`
+	// Status is the task's status before it was blocked, restored when it
+	// is unblocked.
+	Status      string     `json:"status,omitempty"`
+	UnblockedAt *time.Time `json:"unblocked_at,omitempty"`
+	UnblockedBy string     `json:"unblocked_by,omitempty"`
 }
 
 // Spec represents a project specification for a domain.
+//
+// Content holds the raw markdown, Blocks is the ordered document model derived
+// from it (concatenating every block's Content reproduces Content exactly), and
+// Requirements is a convenience view over the requirement blocks.
+//
+// Meta is the domain metadata from an optional YAML front matter block at the
+// top of the file; FrontMatter is that block's exact text, delimiters included
+// (it is also the start of the preamble block).
 type Spec struct {
 	Domain       string        `json:"domain"`
 	Content      string        `json:"content"`
 	Fingerprint  string        `json:"fingerprint"`
+	Meta         SpecMeta      `json:"meta"`
 	Requirements []Requirement `json:"requirements"`
-	AST          ast.Node      `json:"-"`
+	// Includes are the "!include shared/<name>" lines of the spec file, which
+	// SpecManager.ReadSpec expands in Content.
+	Includes []SpecInclude `json:"includes,omitempty"`
+	// Warnings are the problems the parser worked around, such as a
+	// requirement heading missing the space after its "#"s, by line.
+	Warnings    []string    `json:"warnings,omitempty"`
+	FrontMatter string      `json:"-"`
+	Blocks      []SpecBlock `json:"-"`
+	AST         ast.Node    `json:"-"`
+}
+
+// SpecInclude is one "!include shared/<name>" line of a spec file and the
+// shared requirement blocks it expanded to.
+type SpecInclude struct {
+	Ref       string `json:"ref"` // "shared/<name>"
+	Directive string `json:"-"`   // the line as written, newline included
+	Text      string `json:"-"`   // the expansion substituted for it in Spec.Content
+	Start     int    `json:"-"`   // byte offset of Text in Spec.Content
+	End       int    `json:"-"`   // byte offset just past Text
+}
+
+// SpecMeta is the front matter of a spec domain. Aliases maps the old IDs of
+// renamed requirements to their current IDs.
+type SpecMeta struct {
+	Owner   string            `yaml:"owner,omitempty" json:"owner,omitempty"`
+	Status  string            `yaml:"status,omitempty" json:"status,omitempty"`
+	Tags    []string          `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Version string            `yaml:"version,omitempty" json:"version,omitempty"`
+	Aliases map[string]string `yaml:"aliases,omitempty" json:"aliases,omitempty"`
+}
+
+// Spec block kinds.
+const (
+	SpecBlockPreamble    = "preamble"    // text before the first heading
+	SpecBlockSection     = "section"     // any level 1-3 heading that is not a requirement
+	SpecBlockRequirement = "requirement" // "### Requirement: <title>" heading and its body
+)
+
+// SpecBlock is one contiguous region of a spec document. A block starts at a
+// level 1-3 heading line (or the start of the file for the preamble) and runs
+// until the next such heading or EOF.
+type SpecBlock struct {
+	Kind          string `json:"kind"`
+	Level         int    `json:"level,omitempty"`          // heading level; 0 for the preamble
+	Title         string `json:"title,omitempty"`          // heading text ("Requirement:" prefix stripped)
+	RequirementID string `json:"requirement_id,omitempty"` // set for requirement blocks
+	Content       string `json:"content"`                  // exact source text, including the heading line
 }
 
 // Requirement represents a single requirement within a spec.
 type Requirement struct {
-	ID      string `json:"id"`
-	Title   string `json:"title"`
-	Content string `json:"content"`
-	Start   int    `json:"-"` // Temporary field for parsing
+	ID        string     `json:"id"`
+	Title     string     `json:"title"`
+	Content   string     `json:"content"`
+	Scenarios []Scenario `json:"scenarios,omitempty"` // "#### Scenario:" sub-blocks, in document order
+	// Notes are editorial notes inside the block ("> Rationale:" quotes and
+	// <details> sections). They are excluded from spec fingerprints.
+	Notes []RequirementNote `json:"notes,omitempty"`
+	// AcceptanceCriteria are the "- [ ]" / "- [x]" checklist items in the block.
+	AcceptanceCriteria []AcceptanceCriterion `json:"acceptance_criteria,omitempty"`
+	// Code are the path globs of "Code: <glob>" lines in the block: the
+	// source files that implement the requirement.
+	Code []string `json:"code,omitempty"`
+	// Priority is the value of the block's "Priority:" line, lowercased:
+	// "must", "should" or "could"; empty when unset.
+	Priority string `json:"priority,omitempty"`
+	// Source is the shared library the requirement was included from, e.g.
+	// "shared/security-baseline"; empty for the domain's own requirements.
+	Source string `json:"source,omitempty"`
+	// Fingerprint hashes the requirement block without its notes. It is set
+	// by SpecManager when the spec is read or written.
+	Fingerprint string `json:"fingerprint,omitempty"`
+	Start       int    `json:"-"` // byte offset of the heading line in Spec.Content
+	End         int    `json:"-"` // byte offset just past the block
+}
+
+// AcceptanceCriterion is one checklist item of a requirement.
+type AcceptanceCriterion struct {
+	Index int    `json:"index"` // 1-based position in the requirement's checklist
+	Text  string `json:"text"`
+	Done  bool   `json:"done"`
+	// Mark is the byte offset in Spec.Content of the character between the
+	// brackets (' ' or 'x').
+	Mark int `json:"-"`
+}
+
+// Requirement note kinds.
+const (
+	NoteRationale = "rationale" // a blockquote starting with "Rationale:"
+	NoteDetails   = "details"   // a collapsible <details> HTML section
+)
+
+// RequirementNote is an editorial note attached to a requirement. Start and
+// End cover the note's lines and the blank lines after it.
+type RequirementNote struct {
+	Kind  string `json:"kind"`
+	Text  string `json:"text"` // note text without quote markers, prefix or tags
+	Start int    `json:"-"`    // byte offset in Spec.Content
+	End   int    `json:"-"`    // byte offset just past the note
 }
 
 // Scenario represents a behavior-driven scenario associated with a requirement or spec.
+// In a spec it is a "#### Scenario: <title>" sub-block of a requirement; Content
+// is the block's exact text, including the heading line.
 type Scenario struct {
 	ID            string   `json:"id"`
 	Title         string   `json:"title"`
 	Steps         []string `json:"steps,omitempty"`
 	RequirementID string   `json:"requirement_id,omitempty"`
 	Content       string   `json:"content,omitempty"`
+	Start         int      `json:"-"` // byte offset of the heading line in Spec.Content
+	End           int      `json:"-"` // byte offset just past the block
 }
 
 // Change represents a change proposal.
 type Change struct {
-	ID         string      `json:"id"`
-	Title      string      `json:"title"`
-	Status     string      `json:"status"`
-	GoalID     string      `json:"goal_id"`
-	CreatedAt  time.Time   `json:"created_at"`
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	// Description says why the change is needed, as free-form Markdown.
+	Description string    `json:"description,omitempty"`
+	Status      string    `json:"status"`
+	GoalID      string    `json:"goal_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	// Author is the person or agent that created the change.
+	Author string `json:"author,omitempty"`
+	// CreatedWith records the teamwerx version that first wrote the change, e.g. "teamwerx/v1.2.3".
+	CreatedWith string `json:"created_with,omitempty"`
+	// Provenance records the tool that generated the change and its source.
+	Provenance *Provenance `json:"provenance,omitempty"`
 	SpecDeltas []SpecDelta `json:"spec_deltas"`
+	DependsOn  []string    `json:"depends_on,omitempty"` // IDs of changes that must be applied first
+	// ClonedFrom is the ID of the change this one was cloned from, if any.
+	ClonedFrom string `json:"cloned_from,omitempty"`
+	// Milestone groups changes meant to be applied together, e.g. "v1.2".
+	Milestone string `json:"milestone,omitempty"`
+	// RequiredApprovers maps each touched domain to its owners, recorded at submit time.
+	RequiredApprovers map[string][]string `json:"required_approvers,omitempty"`
+	Approvals         []Approval          `json:"approvals,omitempty"`
+	// Conflicts lists the requirements an apply with the markers strategy
+	// wrote with conflict markers for a human to resolve.
+	Conflicts []MergeConflict `json:"conflicts,omitempty"`
+	// ArchivedAt is when the change was moved to the archive.
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	// LoadedFingerprint is the fingerprint of change.json when it was read.
+	// Save refuses to overwrite the file if it has changed since; empty for new changes.
+	LoadedFingerprint string `json:"-"`
+}
+
+// Provenance describes how a generated change came about, so proposals from
+// several agents can be told apart.
+type Provenance struct {
+	Tool         string `json:"tool,omitempty"`         // the generating agent or tool
+	Model        string `json:"model,omitempty"`        // the model behind it, if any
+	Conversation string `json:"conversation,omitempty"` // ID or URL of the source conversation
+	Commit       string `json:"commit,omitempty"`       // the commit the change was generated from
+}
+
+// Approval records an owner's sign-off for one domain of a change.
+type Approval struct {
+	Domain     string    `json:"domain"`
+	Approver   string    `json:"approver"`
+	ApprovedAt time.Time `json:"approved_at"`
+}
+
+// MergeConflict records a requirement written with both its current and
+// incoming text between conflict markers.
+type MergeConflict struct {
+	Domain        string    `json:"domain"`
+	RequirementID string    `json:"requirement_id"`
+	RecordedAt    time.Time `json:"recorded_at"`
 }
 
 // SpecDelta represents the changes to a spec in a proposal.
@@ -85,16 +297,63 @@ type SpecDelta struct {
 	Domain          string           `json:"domain"`
 	BaseFingerprint string           `json:"base_fingerprint,omitempty"`
 	Operations      []DeltaOperation `json:"operations"`
+	// AppliedFingerprint is the spec's fingerprint right after the delta was
+	// last merged into it; empty until then.
+	AppliedFingerprint string `json:"applied_fingerprint,omitempty"`
 }
 
 // DeltaOperation represents a single operation in a spec delta.
+//
+// Scenario operations (ADD_SCENARIO, MODIFY_SCENARIO, REMOVE_SCENARIO) target
+// Scenario within the requirement identified by Requirement.ID.
 type DeltaOperation struct {
-	Type        string      `json:"type"` // ADDED, MODIFIED, REMOVED
+	Type        string      `json:"type"` // ADDED, MODIFIED, REMOVED, ADD_SCENARIO, MODIFY_SCENARIO, REMOVE_SCENARIO, APPEND, PATCH
 	Requirement Requirement `json:"requirement"`
+	Scenario    *Scenario   `json:"scenario,omitempty"`
+	// Text is the markdown APPEND adds to the end of the requirement's body.
+	Text string `json:"text,omitempty"`
+	// Find and Replace are a PATCH: Find must occur exactly once in the
+	// requirement and is replaced by Replace.
+	Find    string `json:"find,omitempty"`
+	Replace string `json:"replace,omitempty"`
+	// After and Section place a requirement the operation adds: right after
+	// requirement After, or else at the end of the section whose heading is
+	// Section. Without either, or when neither is found, it goes at the end.
+	After   string `json:"after,omitempty"`
+	Section string `json:"section,omitempty"`
+	// Base is the requirement's text when the change was created, recorded
+	// for MODIFIED, REMOVED, MODIFY_SCENARIO and REMOVE_SCENARIO so reviews
+	// show what the author saw and merges have a common ancestor.
+	Base string `json:"base,omitempty"`
+	// AppliedAt is set once a partial apply has merged the operation; later
+	// applies skip it.
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// Review comment statuses.
+const (
+	ReviewOpen     = "open"
+	ReviewResolved = "resolved" // addressed by a spec edit or change
+	ReviewClosed   = "closed"   // dismissed without a spec edit
+)
+
+// ReviewComment is an inline review note on a spec requirement, stored in a
+// sidecar file so reviews never edit the spec itself.
+type ReviewComment struct {
+	ID            string     `json:"id" yaml:"id"`
+	RequirementID string     `json:"requirement_id" yaml:"requirement_id"`
+	Author        string     `json:"author,omitempty" yaml:"author,omitempty"`
+	Body          string     `json:"body" yaml:"body"`
+	Status        string     `json:"status" yaml:"status"`
+	CreatedAt     time.Time  `json:"created_at" yaml:"created_at"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty" yaml:"updated_at,omitempty"`
 }
 
 // Charter represents the project's steering document - defines purpose, tech stack, and conventions.
 type Charter struct {
+	// Extends names an organization charter this one inherits from, by
+	// http(s) URL or by path relative to this charter.
+	Extends     string                 `yaml:"extends,omitempty"`
 	Title       string                 `yaml:"title"`
 	Version     string                 `yaml:"version"`
 	Created     time.Time              `yaml:"created"`
@@ -102,5 +361,15 @@ type Charter struct {
 	Purpose     string                 `yaml:"purpose"`
 	TechStack   []string               `yaml:"tech_stack,omitempty"`
 	Conventions map[string]interface{} `yaml:"conventions,omitempty"`
-	Content     string                 `yaml:"-"` // Markdown content after frontmatter
+	// SpecSections lists the level-2 sections every spec.md must contain (e.g., Overview, Requirements).
+	SpecSections []string `yaml:"spec_sections,omitempty"`
+	// GoalTemplates overrides or adds goal templates used by `goal create --template`.
+	GoalTemplates map[string]GoalTemplate `yaml:"goal_templates,omitempty"`
+	Content       string                  `yaml:"-"` // Markdown content after frontmatter
+}
+
+// GoalTemplate is a charter-defined set of initial tasks and guiding questions for new goals.
+type GoalTemplate struct {
+	Tasks     []string `yaml:"tasks,omitempty"`
+	Questions []string `yaml:"questions,omitempty"`
 }
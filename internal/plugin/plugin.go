@@ -0,0 +1,160 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	customerrors "github.com/teamwerx/teamwerx/internal/errors"
+)
+
+// Prefix is the executable name prefix used to discover plugins on PATH.
+// A plugin named "foo" is provided by an executable called "teamwerx-foo".
+const Prefix = "teamwerx-"
+
+// ContextEnv is the environment variable carrying the JSON-encoded Context.
+const ContextEnv = "TEAMWERX_CONTEXT"
+
+// Plugin describes an external command discovered on PATH.
+type Plugin struct {
+	Name string // name used on the command line (without prefix)
+	Path string // absolute path to the executable
+}
+
+// Context is the structured workspace information passed to plugins.
+// It is encoded as JSON into TEAMWERX_CONTEXT, and each directory is also
+// exposed as an individual TEAMWERX_* variable for shell-script plugins.
+type Context struct {
+	Workspace  string `json:"workspace"`
+	SpecsDir   string `json:"specs_dir"`
+	GoalsDir   string `json:"goals_dir"`
+	ChangesDir string `json:"changes_dir"`
+	CharterDir string `json:"charter_dir"`
+}
+
+// Discover scans every directory on PATH for plugin executables. When the same
+// plugin name appears in multiple directories, the first one on PATH wins (the
+// same precedence the shell uses). Results are sorted by name.
+func Discover() []Plugin {
+	seen := map[string]bool{}
+	var plugins []Plugin
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			// Unreadable PATH entries are common; skip them quietly.
+			continue
+		}
+		for _, e := range entries {
+			name, ok := pluginName(e.Name())
+			if !ok || seen[name] {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, Plugin{Name: name, Path: path})
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
+// Find looks up a single plugin by name using exec.LookPath.
+// Returns ErrNotFound if no matching executable is on PATH.
+func Find(name string) (*Plugin, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return nil, customerrors.NewErrNotFound("plugin", name)
+	}
+	path, err := exec.LookPath(Prefix + name)
+	if err != nil {
+		return nil, customerrors.NewErrNotFound("plugin", name)
+	}
+	return &Plugin{Name: name, Path: path}, nil
+}
+
+// Env returns the environment variables describing ctx, suitable for appending
+// to os.Environ() when launching a plugin.
+func (c Context) Env() ([]string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode plugin context: %w", err)
+	}
+	return []string{
+		"TEAMWERX_WORKSPACE=" + c.Workspace,
+		"TEAMWERX_SPECS_DIR=" + c.SpecsDir,
+		"TEAMWERX_GOALS_DIR=" + c.GoalsDir,
+		"TEAMWERX_CHANGES_DIR=" + c.ChangesDir,
+		"TEAMWERX_CHARTER_DIR=" + c.CharterDir,
+		ContextEnv + "=" + string(data),
+	}, nil
+}
+
+// Run executes the plugin with args, wiring stdio through and exporting ctx.
+// The plugin's exit error (if any) is returned unchanged so callers can
+// propagate its exit code.
+func (p Plugin) Run(ctx Context, args []string) error {
+	env, err := ctx.Env()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(p.Path, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), env...)
+	return cmd.Run()
+}
+
+// Output executes the plugin with args, feeding input on stdin and returning
+// its stdout. Stderr is passed through so plugins can report progress.
+func (p Plugin) Output(ctx Context, args []string, input []byte) ([]byte, error) {
+	env, err := ctx.Env()
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(p.Path, args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), env...)
+	return cmd.Output()
+}
+
+// pluginName extracts the plugin name from an executable file name.
+func pluginName(file string) (string, bool) {
+	if !strings.HasPrefix(file, Prefix) {
+		return "", false
+	}
+	name := strings.TrimPrefix(file, Prefix)
+	if runtime.GOOS == "windows" {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return name, name != ""
+}
+
+// isExecutable reports whether path is a regular file the current user can execute.
+// On Windows every regular file with an executable extension is accepted.
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".exe", ".bat", ".cmd":
+			return true
+		}
+		return false
+	}
+	return info.Mode().Perm()&0o111 != 0
+}
@@ -0,0 +1,319 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	customerrors "github.com/teamwerx/teamwerx/internal/errors"
+	fileutil "github.com/teamwerx/teamwerx/internal/utils/file"
+)
+
+// InMem is a Storage that keeps every file in memory. It is safe for
+// concurrent use and is meant for tests and for embedding teamwerx without a
+// workspace on disk. The zero value is not usable; call NewInMem.
+type InMem struct {
+	mu    sync.Mutex
+	files map[string]*memFile
+	dirs  map[string]time.Time // directory -> mod time
+	locks map[string]bool
+}
+
+type memFile struct {
+	data    []byte
+	mode    os.FileMode
+	modTime time.Time
+}
+
+// NewInMem returns an empty in-memory Storage.
+func NewInMem() *InMem {
+	return &InMem{
+		files: map[string]*memFile{},
+		dirs:  map[string]time.Time{},
+		locks: map[string]bool{},
+	}
+}
+
+func (s *InMem) ReadFile(path string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.files[clean(path)]
+	if !ok {
+		if _, isDir := s.dirs[clean(path)]; isDir {
+			return nil, &fs.PathError{Op: "read", Path: path, Err: fmt.Errorf("is a directory")}
+		}
+		return nil, customerrors.NewErrNotFound("file", path)
+	}
+	return append([]byte(nil), f.data...), nil
+}
+
+// Open returns a reader over a snapshot of the file's contents.
+func (s *InMem) Open(path string) (io.ReadCloser, error) {
+	data, err := s.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *InMem) WriteFile(path string, data []byte, perm os.FileMode) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := clean(path)
+	if _, isDir := s.dirs[p]; isDir {
+		return &fs.PathError{Op: "open", Path: path, Err: fmt.Errorf("is a directory")}
+	}
+	s.mkdirAll(filepath.Dir(p))
+	s.files[p] = &memFile{data: append([]byte(nil), data...), mode: perm, modTime: time.Now()}
+	return nil
+}
+
+func (s *InMem) Exists(path string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := clean(path)
+	_, isFile := s.files[p]
+	_, isDir := s.dirs[p]
+	return isFile || isDir, nil
+}
+
+func (s *InMem) MkdirAll(path string, perm os.FileMode) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := clean(path)
+	if _, isFile := s.files[p]; isFile {
+		return &fs.PathError{Op: "mkdir", Path: path, Err: fs.ErrExist}
+	}
+	s.mkdirAll(p)
+	return nil
+}
+
+// mkdirAll records p and its parents as directories. Callers hold s.mu.
+func (s *InMem) mkdirAll(p string) {
+	for {
+		if _, ok := s.dirs[p]; ok {
+			return
+		}
+		s.dirs[p] = time.Now()
+		parent := filepath.Dir(p)
+		if parent == p {
+			return
+		}
+		p = parent
+	}
+}
+
+func (s *InMem) Rename(oldpath, newpath string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	from, to := clean(oldpath), clean(newpath)
+	if f, ok := s.files[from]; ok {
+		delete(s.files, from)
+		s.files[to] = f
+		return nil
+	}
+	if _, ok := s.dirs[from]; !ok {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: fs.ErrNotExist}
+	}
+	if _, ok := s.dirs[filepath.Dir(to)]; !ok {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: fs.ErrNotExist}
+	}
+	for p, f := range s.files {
+		if rel, ok := under(from, p); ok {
+			delete(s.files, p)
+			s.files[filepath.Join(to, rel)] = f
+		}
+	}
+	for p, t := range s.dirs {
+		if rel, ok := under(from, p); ok {
+			delete(s.dirs, p)
+			s.dirs[filepath.Join(to, rel)] = t
+		}
+	}
+	delete(s.dirs, from)
+	s.dirs[to] = time.Now()
+	return nil
+}
+
+func (s *InMem) RemoveAll(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := clean(path)
+	delete(s.files, p)
+	delete(s.dirs, p)
+	for f := range s.files {
+		if _, ok := under(p, f); ok {
+			delete(s.files, f)
+		}
+	}
+	for d := range s.dirs {
+		if _, ok := under(p, d); ok {
+			delete(s.dirs, d)
+		}
+	}
+	return nil
+}
+
+func (s *InMem) CopyDir(src, dst string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	from, to := clean(src), clean(dst)
+	if _, ok := s.dirs[from]; !ok {
+		if _, isFile := s.files[from]; isFile {
+			return customerrors.NewErrConflict("source is not a directory")
+		}
+		return customerrors.NewErrNotFound("directory", src)
+	}
+	_, fileExists := s.files[to]
+	if _, dirExists := s.dirs[to]; dirExists || fileExists {
+		return customerrors.NewErrConflict("destination already exists: " + dst)
+	}
+	now := time.Now()
+	for p := range s.dirs {
+		if rel, ok := under(from, p); ok {
+			s.dirs[filepath.Join(to, rel)] = now
+		}
+	}
+	for p, f := range s.files {
+		if rel, ok := under(from, p); ok {
+			data := append([]byte(nil), f.data...)
+			s.files[filepath.Join(to, rel)] = &memFile{data: data, mode: f.mode, modTime: now}
+		}
+	}
+	s.mkdirAll(to)
+	return nil
+}
+
+// WalkDir visits root and everything under it in lexical order, like
+// filepath.WalkDir. The tree is snapshotted first, so fn may modify the storage.
+func (s *InMem) WalkDir(root string, fn fs.WalkDirFunc) error {
+	s.mu.Lock()
+	children := map[string][]memDirEntry{}
+	for p, f := range s.files {
+		children[filepath.Dir(p)] = append(children[filepath.Dir(p)], memDirEntry{name: filepath.Base(p), mode: f.mode, size: int64(len(f.data)), modTime: f.modTime})
+	}
+	for p, t := range s.dirs {
+		if parent := filepath.Dir(p); parent != p {
+			children[parent] = append(children[parent], memDirEntry{name: filepath.Base(p), mode: fs.ModeDir | 0o755, modTime: t})
+		}
+	}
+	r := clean(root)
+	var rootEntry *memDirEntry
+	if f, ok := s.files[r]; ok {
+		rootEntry = &memDirEntry{name: filepath.Base(r), mode: f.mode, size: int64(len(f.data)), modTime: f.modTime}
+	} else if t, ok := s.dirs[r]; ok {
+		rootEntry = &memDirEntry{name: filepath.Base(r), mode: fs.ModeDir | 0o755, modTime: t}
+	}
+	s.mu.Unlock()
+
+	if rootEntry == nil {
+		return fn(root, nil, &fs.PathError{Op: "lstat", Path: root, Err: fs.ErrNotExist})
+	}
+	for _, entries := range children {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+	}
+	err := walkMem(root, *rootEntry, children, fn)
+	if err == filepath.SkipDir {
+		return nil
+	}
+	return err
+}
+
+func walkMem(path string, d memDirEntry, children map[string][]memDirEntry, fn fs.WalkDirFunc) error {
+	if err := fn(path, d, nil); err != nil || !d.IsDir() {
+		if err == filepath.SkipDir && d.IsDir() {
+			err = nil
+		}
+		return err
+	}
+	for _, child := range children[clean(path)] {
+		if err := walkMem(filepath.Join(path, child.name), child, children, fn); err != nil {
+			if err == filepath.SkipDir {
+				break
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Lock takes an in-process lock on path. Locks are not stale-broken: an InMem
+// storage cannot outlive the process holding them.
+func (s *InMem) Lock(path string, timeout time.Duration) (func() error, error) {
+	if timeout <= 0 {
+		timeout = fileutil.DefaultLockTimeout
+	}
+	p := clean(path)
+	deadline := time.Now().Add(timeout)
+	for {
+		s.mu.Lock()
+		if !s.locks[p] {
+			s.locks[p] = true
+			s.mu.Unlock()
+			return func() error {
+				s.mu.Lock()
+				defer s.mu.Unlock()
+				delete(s.locks, p)
+				return nil
+			}, nil
+		}
+		s.mu.Unlock()
+		if time.Now().After(deadline) {
+			return nil, customerrors.NewErrConflict(fmt.Sprintf("timed out waiting for lock on %s", path))
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// Files returns the path of every stored file, sorted. It is mainly useful in tests.
+func (s *InMem) Files() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	paths := make([]string, 0, len(s.files))
+	for p := range s.files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func clean(path string) string { return filepath.Clean(path) }
+
+// under reports whether p lies strictly below dir and returns its relative path.
+func under(dir, p string) (string, bool) {
+	prefix := dir + string(filepath.Separator)
+	if dir == string(filepath.Separator) {
+		prefix = dir
+	}
+	if dir == "." {
+		return p, p != "."
+	}
+	if !strings.HasPrefix(p, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(p, prefix), true
+}
+
+// memDirEntry implements fs.DirEntry and fs.FileInfo for InMem entries.
+type memDirEntry struct {
+	name    string
+	mode    fs.FileMode
+	size    int64
+	modTime time.Time
+}
+
+func (e memDirEntry) Name() string               { return e.name }
+func (e memDirEntry) IsDir() bool                { return e.mode.IsDir() }
+func (e memDirEntry) Type() fs.FileMode          { return e.mode.Type() }
+func (e memDirEntry) Info() (fs.FileInfo, error) { return e, nil }
+func (e memDirEntry) Size() int64                { return e.size }
+func (e memDirEntry) Mode() fs.FileMode          { return e.mode }
+func (e memDirEntry) ModTime() time.Time         { return e.modTime }
+func (e memDirEntry) Sys() interface{}           { return nil }
@@ -0,0 +1,83 @@
+// Package storage abstracts the file access performed by the core managers so
+// a workspace can live on disk (OS) or entirely in memory (InMem).
+package storage
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	customerrors "github.com/teamwerx/teamwerx/internal/errors"
+	fileutil "github.com/teamwerx/teamwerx/internal/utils/file"
+)
+
+// Storage is the file system used by the core managers. Paths are ordinary
+// OS-style paths; backends decide what they refer to.
+type Storage interface {
+	// ReadFile returns the file's contents, or *errors.ErrNotFound when it does not exist.
+	ReadFile(path string) ([]byte, error)
+	// Open opens the file for streaming reads, or returns *errors.ErrNotFound
+	// when it does not exist. The caller closes it.
+	Open(path string) (io.ReadCloser, error)
+	// WriteFile replaces the file's contents, creating parent directories as needed.
+	WriteFile(path string, data []byte, perm os.FileMode) error
+	// Exists reports whether a file or directory exists at path.
+	Exists(path string) (bool, error)
+	// MkdirAll creates a directory and any missing parents.
+	MkdirAll(path string, perm os.FileMode) error
+	// Rename moves a file or directory tree. The destination's parent must exist.
+	Rename(oldpath, newpath string) error
+	// RemoveAll deletes path and anything under it. A missing path is not an error.
+	RemoveAll(path string) error
+	// CopyDir copies the directory tree at src to dst, which must not exist,
+	// keeping file modes and symbolic links.
+	CopyDir(src, dst string) error
+	// WalkDir walks the tree rooted at root like filepath.WalkDir.
+	WalkDir(root string, fn fs.WalkDirFunc) error
+	// Lock takes an exclusive lock on path, waiting up to timeout
+	// (fileutil.DefaultLockTimeout when zero). Call the returned function to release it.
+	Lock(path string, timeout time.Duration) (func() error, error)
+}
+
+// osStorage is the Storage backed by the real file system.
+type osStorage struct{}
+
+// OS returns the Storage backed by the real file system.
+func OS() Storage { return osStorage{} }
+
+func (osStorage) ReadFile(path string) ([]byte, error) { return fileutil.ReadFile(path) }
+
+func (osStorage) Open(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, customerrors.NewErrNotFound("file", path)
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+func (osStorage) WriteFile(path string, data []byte, perm os.FileMode) error {
+	return fileutil.WriteFile(path, data, perm)
+}
+
+func (osStorage) Exists(path string) (bool, error) { return fileutil.Exists(path) }
+
+func (osStorage) MkdirAll(path string, perm os.FileMode) error { return fileutil.MkdirAll(path, perm) }
+
+func (osStorage) Rename(oldpath, newpath string) error { return os.Rename(oldpath, newpath) }
+
+func (osStorage) RemoveAll(path string) error { return os.RemoveAll(path) }
+
+func (osStorage) CopyDir(src, dst string) error { return fileutil.CopyDir(src, dst) }
+
+func (osStorage) WalkDir(root string, fn fs.WalkDirFunc) error {
+	return filepath.WalkDir(root, fn)
+}
+
+func (osStorage) Lock(path string, timeout time.Duration) (func() error, error) {
+	return fileutil.Lock(path, timeout)
+}
@@ -144,6 +144,82 @@ func CopyFile(src, dst string) error {
 	return nil
 }
 
+// CopyDir copies the directory tree at src to dst, which must not exist.
+// Files keep their mode bits and directories their permissions; symbolic
+// links are recreated as links to the same target rather than followed.
+// Returns ErrNotFound if src does not exist, and ErrConflict if src is not a
+// directory, dst exists, or the tree holds a device, pipe or socket. When the
+// copy fails partway, what was copied is removed again.
+func CopyDir(src, dst string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return customerrors.NewErrNotFound("directory", src)
+		}
+		return err
+	}
+	if !info.IsDir() {
+		return customerrors.NewErrConflict("source is not a directory")
+	}
+	if _, err := os.Lstat(dst); err == nil {
+		return customerrors.NewErrConflict("destination already exists: " + dst)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	// Directories are created writable and get their own permissions once
+	// their contents are in place, deepest first.
+	type dirMode struct {
+		path string
+		mode os.FileMode
+	}
+	var dirs []dirMode
+	err = filepath.WalkDir(src, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch mode := info.Mode(); {
+		case mode.IsDir():
+			if err := os.MkdirAll(target, 0o700); err != nil {
+				return err
+			}
+			dirs = append(dirs, dirMode{target, mode.Perm()})
+			return nil
+		case mode&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case mode.IsRegular():
+			return CopyFile(path, target)
+		default:
+			return customerrors.NewErrConflict("cannot copy special file " + path)
+		}
+	})
+	for i := len(dirs) - 1; i >= 0 && err == nil; i-- {
+		err = os.Chmod(dirs[i].path, dirs[i].mode)
+	}
+	if err != nil {
+		// Leave no partial copy behind: dst must not exist for a retry.
+		for _, d := range dirs {
+			_ = os.Chmod(d.path, 0o700)
+		}
+		_ = os.RemoveAll(dst)
+		return err
+	}
+	return nil
+}
+
 // SafeWriteAtomic writes data to path using a best-effort atomic replace:
 // it writes to a temporary file in the destination directory, fsyncs it,
 // then renames it over the target and fsyncs the directory.
@@ -229,15 +305,17 @@ func MoveFile(src, dst string) error {
 		// would return EXDEV not ErrInvalid; handle that path below.
 		return err
 	} else {
-		// If cross-device (EXDEV), fall back to copy + remove for regular files.
+		// If cross-device (EXDEV), fall back to copy + remove.
 		// Detect file vs dir.
 		info, statErr := os.Stat(src)
 		if statErr != nil {
 			return statErr
 		}
 		if info.IsDir() {
-			// Use os.Rename error directly for directories, since recursive copy is out-of-scope.
-			return err
+			if copyErr := CopyDir(src, dst); copyErr != nil {
+				return copyErr
+			}
+			return os.RemoveAll(src)
 		}
 		if copyErr := CopyFile(src, dst); copyErr != nil {
 			return copyErr
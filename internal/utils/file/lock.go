@@ -0,0 +1,59 @@
+package file
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	customerrors "github.com/teamwerx/teamwerx/internal/errors"
+)
+
+// DefaultLockTimeout is how long Lock waits for a competing holder by default.
+const DefaultLockTimeout = 10 * time.Second
+
+// staleLockAge is how old a lock file must be before it is assumed to belong
+// to a crashed process and is broken. Locks only guard short read-modify-write
+// cycles, so a holder should never legitimately keep one this long.
+const staleLockAge = 30 * time.Second
+
+// lockRetryInterval is the pause between attempts to acquire a held lock.
+const lockRetryInterval = 5 * time.Millisecond
+
+// Lock acquires an exclusive advisory lock for path by creating "<path>.lock"
+// with O_EXCL, which works across processes on every platform. It retries
+// until timeout (DefaultLockTimeout when zero) and then returns ErrConflict.
+// Lock files older than staleLockAge are removed so a crashed writer cannot
+// block others forever.
+//
+// The returned function releases the lock; call it exactly once.
+func Lock(path string, timeout time.Duration) (func() error, error) {
+	if timeout <= 0 {
+		timeout = DefaultLockTimeout
+	}
+	if err := EnsureParentDir(path, 0o755); err != nil {
+		return nil, err
+	}
+
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(timeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			_, _ = fmt.Fprintf(f, "%d\n", os.Getpid())
+			_ = f.Close()
+			return func() error { return os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+			_ = os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, customerrors.NewErrConflict(fmt.Sprintf("timed out waiting for lock on %s", path))
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
@@ -75,6 +75,102 @@ func Diff(ctx context.Context, repoPath, refA, refB string, pathspec ...string)
 	return out, nil
 }
 
+// ChangedFiles returns the files that differ between ref and the working tree,
+// plus untracked files that are not ignored, as slash-separated paths
+// relative to repoPath. Paths outside repoPath are omitted.
+func ChangedFiles(ctx context.Context, repoPath, ref string) ([]string, error) {
+	if err := ensureDir(repoPath); err != nil {
+		return nil, err
+	}
+	diff, err := runGit(ctx, repoPath, "diff", "--name-only", "--relative", ref, "--")
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := runGit(ctx, repoPath, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	var files []string
+	for _, line := range strings.Split(diff+"\n"+untracked, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !seen[line] {
+			seen[line] = true
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// StagedFiles returns the files added, copied, modified or renamed in the
+// index, as slash-separated paths relative to repoPath. Paths outside
+// repoPath are omitted.
+func StagedFiles(ctx context.Context, repoPath string) ([]string, error) {
+	if err := ensureDir(repoPath); err != nil {
+		return nil, err
+	}
+	out, err := runGit(ctx, repoPath, "diff", "--cached", "--name-only", "--relative", "--diff-filter=ACMR", "--")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// HooksDir returns the absolute path of the repository's hooks directory,
+// honoring core.hooksPath and worktrees.
+func HooksDir(ctx context.Context, repoPath string) (string, error) {
+	if err := ensureDir(repoPath); err != nil {
+		return "", err
+	}
+	out, err := runGit(ctx, repoPath, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	dir := strings.TrimSpace(out)
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(repoPath, dir)
+	}
+	return filepath.Abs(dir)
+}
+
+// Show returns the content of file as of ref, e.g. Show(ctx, ".", "main",
+// ".teamwerx/specs/auth/spec.md"). file is relative to repoPath.
+func Show(ctx context.Context, repoPath, ref, file string) (string, error) {
+	if err := ensureDir(repoPath); err != nil {
+		return "", err
+	}
+	rel := filepath.ToSlash(file)
+	if !filepath.IsAbs(file) && !strings.HasPrefix(rel, "./") {
+		rel = "./" + rel
+	}
+	return runGit(ctx, repoPath, "show", ref+":"+rel)
+}
+
+// ListFiles returns the files under dir as of ref, as slash-separated paths
+// relative to repoPath, e.g. ListFiles(ctx, ".", "main", ".teamwerx/specs").
+// A dir missing at ref yields no files.
+func ListFiles(ctx context.Context, repoPath, ref, dir string) ([]string, error) {
+	if err := ensureDir(repoPath); err != nil {
+		return nil, err
+	}
+	out, err := runGit(ctx, repoPath, "ls-tree", "-r", "--name-only", ref, "--", filepath.ToSlash(dir))
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
 // Apply applies a patch to the repository at repoPath using `git apply`.
 // - If threeWay is true, passes --3way to attempt a three-way merge.
 // - If check is true, passes --check to validate without applying.
@@ -233,3 +329,13 @@ func RepoRoot(ctx context.Context, repoPath string) (string, error) {
 	}
 	return root, nil
 }
+
+// Clone makes a shallow clone of url into dest, which must not exist yet.
+func Clone(ctx context.Context, url, dest string) error {
+	parent := filepath.Dir(dest)
+	if err := ensureDir(parent); err != nil {
+		return err
+	}
+	_, err := runGit(ctx, parent, "clone", "--depth", "1", "--quiet", "--", url, dest)
+	return err
+}
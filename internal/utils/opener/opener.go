@@ -0,0 +1,75 @@
+package opener
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	customerrors "github.com/teamwerx/teamwerx/internal/errors"
+)
+
+// Command returns the platform-specific command used to open path with the
+// user's default application:
+// - macOS:   open <path>
+// - Windows: cmd /c start "" <path>
+// - others:  xdg-open <path>
+func Command(path string) *exec.Cmd {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", path)
+	case "windows":
+		return exec.Command("cmd", "/c", "start", "", path)
+	default:
+		return exec.Command("xdg-open", path)
+	}
+}
+
+// Open launches the default application for the file at path without waiting
+// for it to exit. Returns ErrNotFound if the path does not exist.
+func Open(path string) error {
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return customerrors.NewErrNotFound("file", path)
+		}
+		return err
+	}
+	cmd := Command(path)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// Reap the child in the background; the opener usually exits immediately.
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
+
+// Editor returns the user's preferred editor command from $VISUAL or
+// $EDITOR, split into program and arguments (e.g. "code --wait"). It returns
+// nil when neither is set.
+func Editor() []string {
+	for _, key := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(key)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return nil
+}
+
+// Edit opens the file at path in the user's editor and waits for it to exit.
+// Without $VISUAL or $EDITOR it falls back to Open. Returns ErrNotFound if
+// the path does not exist.
+func Edit(path string) error {
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return customerrors.NewErrNotFound("file", path)
+		}
+		return err
+	}
+	editor := Editor()
+	if editor == nil {
+		return Open(path)
+	}
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Run()
+}
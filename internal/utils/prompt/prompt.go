@@ -3,10 +3,12 @@ package prompt
 import (
 	"fmt"
 	"os"
+	"os/exec"
 	"strings"
 
 	"github.com/manifoldco/promptui"
 	"github.com/mattn/go-isatty"
+	"github.com/teamwerx/teamwerx/internal/utils/opener"
 )
 
 // Options controls behavior for prompt helpers.
@@ -41,11 +43,53 @@ func IsInteractive() bool {
 	return stdoutTTY && stdinTTY
 }
 
+// Environment variables holding scripted answers, so automation can drive
+// flows that would otherwise take the prompt default.
+const (
+	// EnvAssumeYes answers every Confirm with Yes when set to a true value
+	// (1, true, yes).
+	EnvAssumeYes = "TEAMWERX_ASSUME_YES"
+	// EnvResolveStrategy picks how conflicting domains are resolved:
+	// refresh, skip or abort.
+	EnvResolveStrategy = "TEAMWERX_RESOLVE_STRATEGY"
+	// EnvDefaultType picks the discussion entry type, e.g. reflection.
+	EnvDefaultType = "TEAMWERX_DEFAULT_TYPE"
+)
+
+// AssumeYes reports whether EnvAssumeYes is set to a true value.
+func AssumeYes() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(EnvAssumeYes))) {
+	case "1", "true", "yes", "y":
+		return true
+	}
+	return false
+}
+
+// Scripted returns the answer held by the environment variable env, matched
+// case-insensitively against choices. It reports false when env is unset,
+// and fails when the answer is not one of choices.
+func Scripted(env string, choices []string) (string, bool, error) {
+	answer := strings.TrimSpace(os.Getenv(env))
+	if answer == "" {
+		return "", false, nil
+	}
+	for _, c := range choices {
+		if strings.EqualFold(c, answer) {
+			return c, true, nil
+		}
+	}
+	return "", false, fmt.Errorf("%s=%s: want one of %s", env, answer, strings.Join(choices, ", "))
+}
+
 // Confirm prompts the user with a Yes/No choice.
+// - With TEAMWERX_ASSUME_YES set, returns true without prompting.
 // - In non-interactive mode, returns defaultYes without prompting.
 // - In interactive mode, presents a select prompt with Yes/No.
 // Returns true for Yes, false for No.
 func Confirm(label string, defaultYes bool) (bool, error) {
+	if AssumeYes() {
+		return true, nil
+	}
 	if !IsInteractive() {
 		return defaultYes, nil
 	}
@@ -98,6 +142,79 @@ func Select(label string, items []string, defaultIndex int) (int, string, error)
 	return idx, choice, nil
 }
 
+// SelectScripted is Select answered by the environment variable env when it
+// is set (see Scripted); otherwise it prompts as Select does.
+func SelectScripted(env, label string, items []string, defaultIndex int) (int, string, error) {
+	choice, ok, err := Scripted(env, items)
+	if err != nil {
+		return -1, "", err
+	}
+	if ok {
+		for i, it := range items {
+			if it == choice {
+				return i, choice, nil
+			}
+		}
+	}
+	return Select(label, items, defaultIndex)
+}
+
+// MultiSelect lets the user toggle any number of items on or off.
+//   - defaults marks which items start selected (missing entries count as unselected).
+//   - In non-interactive mode, returns the indices selected by defaults without prompting.
+//   - In interactive mode, shows a select prompt where choosing an item toggles it,
+//     and choosing "Done" confirms the current selection.
+//
+// Returns the selected indices in ascending order.
+func MultiSelect(label string, items []string, defaults []bool) ([]int, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no items to select from")
+	}
+
+	selected := make([]bool, len(items))
+	copy(selected, defaults)
+
+	if IsInteractive() {
+		const done = "Done"
+		cursor := 0
+		for {
+			rows := make([]string, 0, len(items)+1)
+			for i, it := range items {
+				mark := "[ ]"
+				if selected[i] {
+					mark = "[x]"
+				}
+				rows = append(rows, fmt.Sprintf("%s %s", mark, it))
+			}
+			rows = append(rows, done)
+
+			sel := promptui.Select{
+				Label:     label,
+				Items:     rows,
+				Size:      min(len(rows), 10),
+				CursorPos: cursor,
+			}
+			idx, _, err := sel.Run()
+			if err != nil {
+				return nil, fmt.Errorf("prompt multiselect failed: %w", err)
+			}
+			if idx == len(items) {
+				break
+			}
+			selected[idx] = !selected[idx]
+			cursor = idx
+		}
+	}
+
+	var out []int
+	for i, ok := range selected {
+		if ok {
+			out = append(out, i)
+		}
+	}
+	return out, nil
+}
+
 // Input prompts the user for free-form text input.
 // - In non-interactive mode, returns defaultValue.
 // - In interactive mode, shows an input prompt with the default pre-filled.
@@ -135,3 +252,58 @@ func min(a, b int) int {
 	}
 	return b
 }
+
+// editorScissors separates the text being edited from the instructions below
+// it; the line and everything after it are dropped from the result.
+const editorScissors = "# ------------------------ >8 ------------------------"
+
+// Editor collects long-form text by opening $VISUAL or $EDITOR (vi when
+// neither is set) on a temporary Markdown file holding initial.
+//   - In non-interactive mode, returns initial without launching anything.
+//   - In interactive mode, returns the saved text with surrounding whitespace
+//     trimmed; an empty result means the user cleared the file.
+//
+// The label is written below a scissors line, so Markdown headings ("# ...")
+// in the text itself are kept.
+func Editor(label, initial string) (string, error) {
+	if !IsInteractive() {
+		return initial, nil
+	}
+
+	f, err := os.CreateTemp("", "teamwerx-*.md")
+	if err != nil {
+		return "", fmt.Errorf("prompt editor failed: %w", err)
+	}
+	path := f.Name()
+	defer os.Remove(path)
+	body := strings.TrimRight(initial, "\n") + "\n\n" + editorScissors + "\n" +
+		"# " + label + "\n" +
+		"# Write above the line; it and everything below it are ignored.\n"
+	if _, err := f.WriteString(body); err != nil {
+		f.Close()
+		return "", fmt.Errorf("prompt editor failed: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("prompt editor failed: %w", err)
+	}
+
+	editor := opener.Editor()
+	if editor == nil {
+		editor = []string{"vi"}
+	}
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("prompt editor failed: %s: %w", editor[0], err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("prompt editor failed: %w", err)
+	}
+	text := string(data)
+	if i := strings.Index(text, editorScissors); i >= 0 {
+		text = text[:i]
+	}
+	return strings.TrimSpace(text), nil
+}
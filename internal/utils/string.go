@@ -60,3 +60,52 @@ func ToKebabCase(s string) string {
 
 	return s
 }
+
+// NaturalLess reports whether a sorts before b in natural order: runs of ASCII
+// digits compare numerically and everything else compares byte-wise, so the
+// result does not depend on the system locale.
+//
+// Examples:
+//
+//	"CH-9"  < "CH-10"
+//	"T02.1" < "T02.10"
+func NaturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		da, db := isDigit(a[0]), isDigit(b[0])
+		switch {
+		case da && db:
+			na, ra := splitDigits(a)
+			nb, rb := splitDigits(b)
+			// Compare numerically by ignoring leading zeros, then by length.
+			ta, tb := strings.TrimLeft(na, "0"), strings.TrimLeft(nb, "0")
+			if len(ta) != len(tb) {
+				return len(ta) < len(tb)
+			}
+			if ta != tb {
+				return ta < tb
+			}
+			if len(na) != len(nb) {
+				return len(na) < len(nb)
+			}
+			a, b = ra, rb
+		case a[0] != b[0]:
+			return a[0] < b[0]
+		default:
+			a, b = a[1:], b[1:]
+		}
+	}
+	return len(a) < len(b)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// splitDigits splits s into its leading run of digits and the remainder.
+func splitDigits(s string) (string, string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+)
+
+// RelativeTime describes t relative to now in its largest whole unit, e.g.
+// "just now", "5m ago", "2h ago", "3d ago", "in 4h". Spans of a year or more
+// use "y"; a month counts as 30 days.
+func RelativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+	if d < time.Minute {
+		return "just now"
+	}
+	var s string
+	switch day := 24 * time.Hour; {
+	case d < time.Hour:
+		s = fmt.Sprintf("%dm", int(d/time.Minute))
+	case d < day:
+		s = fmt.Sprintf("%dh", int(d/time.Hour))
+	case d < 30*day:
+		s = fmt.Sprintf("%dd", int(d/day))
+	case d < 365*day:
+		s = fmt.Sprintf("%dmo", int(d/(30*day)))
+	default:
+		s = fmt.Sprintf("%dy", int(d/(365*day)))
+	}
+	if future {
+		return "in " + s
+	}
+	return s + " ago"
+}
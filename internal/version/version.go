@@ -0,0 +1,51 @@
+// Package version holds build metadata injected at link time, e.g.:
+//
+//	go build -ldflags "-X github.com/teamwerx/teamwerx/internal/version.Version=v1.2.3 \
+//	  -X github.com/teamwerx/teamwerx/internal/version.Commit=$(git rev-parse --short HEAD) \
+//	  -X github.com/teamwerx/teamwerx/internal/version.Date=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+package version
+
+import (
+	"fmt"
+	"runtime"
+)
+
+var (
+	// Version is the semantic version of the build ("dev" for local builds).
+	Version = "dev"
+	// Commit is the git commit the binary was built from.
+	Commit = "unknown"
+	// Date is the build date in RFC 3339 format.
+	Date = "unknown"
+)
+
+// Info describes the running binary.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Date      string `json:"date"`
+	GoVersion string `json:"go_version"`
+	Platform  string `json:"platform"`
+}
+
+// Get returns the build metadata of the running binary.
+func Get() Info {
+	return Info{
+		Version:   Version,
+		Commit:    Commit,
+		Date:      Date,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+}
+
+// String returns a one-line description, e.g. "teamwerx v1.2.3 (abc1234, 2024-01-01T00:00:00Z, go1.21.0)".
+func (i Info) String() string {
+	return fmt.Sprintf("teamwerx %s (%s, %s, %s)", i.Version, i.Commit, i.Date, i.GoVersion)
+}
+
+// CreatedWith returns the provenance string recorded in artifacts written by
+// this binary, e.g. "teamwerx/v1.2.3".
+func CreatedWith() string {
+	return "teamwerx/" + Version
+}
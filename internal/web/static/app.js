@@ -0,0 +1,90 @@
+// Read-only dashboard: renders GET /api/dashboard and fetches requirement text
+// from GET /api/specs/<domain> on demand.
+(function () {
+  "use strict";
+
+  function el(tag, attrs, children) {
+    var node = document.createElement(tag);
+    Object.keys(attrs || {}).forEach(function (k) {
+      if (k === "text") node.textContent = attrs[k];
+      else if (k === "onclick") node.addEventListener("click", attrs[k]);
+      else node.setAttribute(k, attrs[k]);
+    });
+    (children || []).forEach(function (c) { if (c) node.appendChild(c); });
+    return node;
+  }
+
+  function text(s) { return document.createTextNode(s == null ? "" : String(s)); }
+
+  function tag(label) { return el("span", { "class": "tag " + label, text: label }); }
+
+  function table(headers, rows) {
+    if (rows.length === 0) return el("p", { "class": "empty", text: "None." });
+    var head = el("tr", {}, headers.map(function (h) { return el("th", { text: h }); }));
+    return el("table", {}, [head].concat(rows.map(function (cells) {
+      return el("tr", {}, cells.map(function (c) {
+        return el("td", {}, [typeof c === "object" ? c : text(c)]);
+      }));
+    })));
+  }
+
+  function fill(id, node) {
+    var target = document.getElementById(id);
+    target.innerHTML = "";
+    target.appendChild(node);
+  }
+
+  function day(ts) { return ts && ts.indexOf("0001-") !== 0 ? ts.slice(0, 10) : ""; }
+
+  function renderGoals(goals) {
+    fill("goals", table(["Goal", "Status", "Progress", ""], goals.map(function (g) {
+      var p = g.progress, done = p.completed + (p.waived || 0);
+      var pct = p.total ? Math.round(100 * done / p.total) : 0;
+      var bar = el("span", { "class": "bar" }, [el("span", { style: "width:" + pct + "%" })]);
+      return [g.goal_id, tag(g.status), bar, g.has_plan ? done + "/" + p.total + (p.blocked ? " (" + p.blocked + " blocked)" : "") : "no plan"];
+    })));
+  }
+
+  function renderChanges(changes, queue) {
+    fill("changes", table(["Change", "Status", "Domains", "Created", "Title"], changes.map(function (c) {
+      var status = el("span", {}, [tag(c.status || "draft")].concat(c.stale ? [text(" "), tag("stale")] : []));
+      return [c.id, status, c.domains.join(", "), day(c.created_at), c.title];
+    })));
+    fill("queue", table(["Change", "Diverged domains", "Attempts", "Queued"], queue.map(function (q) {
+      return [q.change_id, q.domains.join(", "), q.attempts, day(q.queued_at)];
+    })));
+  }
+
+  function showRequirement(domain, id) {
+    fetch("api/specs/" + encodeURIComponent(domain)).then(function (r) { return r.json(); }).then(function (spec) {
+      var pre = document.getElementById("requirement");
+      var req = (spec.requirements || []).filter(function (r) { return r.id === id; })[0];
+      pre.textContent = req ? "### Requirement: " + req.title + "\n\n" + req.content : "Requirement not found.";
+      pre.hidden = false;
+      pre.scrollIntoView({ behavior: "smooth" });
+    });
+  }
+
+  function renderSpecs(specs) {
+    if (specs.length === 0) { fill("specs", el("p", { "class": "empty", text: "No specs." })); return; }
+    fill("specs", el("div", {}, specs.map(function (s) {
+      var rows = s.requirements.map(function (r) {
+        return [el("a", { text: r.id, onclick: function () { showRequirement(s.domain, r.id); } }), r.title, r.scenarios];
+      });
+      return el("details", {}, [
+        el("summary", { text: s.domain + " (" + s.requirements.length + " requirements)" }),
+        table(["Requirement", "Title", "Scenarios"], rows)
+      ]);
+    })));
+  }
+
+  fetch("api/dashboard").then(function (r) { return r.json(); }).then(function (d) {
+    if (d.error) throw new Error(d.error);
+    document.getElementById("generated").textContent = "as of " + new Date(d.generated_at).toLocaleString();
+    renderGoals(d.goals);
+    renderChanges(d.changes, d.queue);
+    renderSpecs(d.specs);
+  }).catch(function (err) {
+    document.querySelector("main").textContent = "Failed to load dashboard: " + err.message;
+  });
+})();
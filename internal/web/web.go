@@ -0,0 +1,80 @@
+// Package web serves the read-only dashboard: a JSON API over a workspace and
+// the embedded single-page app that renders it.
+package web
+
+import (
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"strings"
+
+	"github.com/teamwerx/teamwerx/internal/core"
+	customerrors "github.com/teamwerx/teamwerx/internal/errors"
+)
+
+//go:embed static
+var assets embed.FS
+
+// Handler returns the dashboard's HTTP handler. Every route is read-only:
+//
+//	GET /                     the dashboard page and its assets
+//	GET /api/dashboard        core.Dashboard snapshot
+//	GET /api/specs/<domain>   the parsed spec, including requirement text
+//	GET /api/goals/<goal-id>  the goal's plan
+//	GET /api/changes/<id>     the change
+//
+// Other methods get 405 Method Not Allowed. Data is read from app on every
+// request, so edits made with the CLI show up on reload.
+func Handler(app *core.App) http.Handler {
+	static, _ := fs.Sub(assets, "static")
+	mux := http.NewServeMux()
+	mux.Handle("/", http.FileServer(http.FS(static)))
+	mux.HandleFunc("/api/dashboard", func(w http.ResponseWriter, r *http.Request) {
+		d, err := core.BuildDashboard(app)
+		writeJSON(w, d, err)
+	})
+	mux.HandleFunc("/api/specs/", func(w http.ResponseWriter, r *http.Request) {
+		spec, err := app.SpecManager.ReadSpec(strings.TrimPrefix(r.URL.Path, "/api/specs/"))
+		writeJSON(w, spec, err)
+	})
+	mux.HandleFunc("/api/goals/", func(w http.ResponseWriter, r *http.Request) {
+		plan, err := app.PlanManager.Load(strings.TrimPrefix(r.URL.Path, "/api/goals/"))
+		writeJSON(w, plan, err)
+	})
+	mux.HandleFunc("/api/changes/", func(w http.ResponseWriter, r *http.Request) {
+		ch, err := app.ChangeManager.ReadChange(strings.TrimPrefix(r.URL.Path, "/api/changes/"))
+		writeJSON(w, ch, err)
+	})
+	return readOnly(mux)
+}
+
+// readOnly rejects every method except GET and HEAD.
+func readOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "read-only dashboard", http.StatusMethodNotAllowed)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeJSON writes v as JSON, or err as {"error": ...} with 404 for
+// ErrNotFound and 500 otherwise.
+func writeJSON(w http.ResponseWriter, v interface{}, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err != nil {
+		status := http.StatusInternalServerError
+		if _, ok := err.(*customerrors.ErrNotFound); ok {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		v = map[string]string{"error": err.Error()}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}